@@ -1,8 +1,13 @@
 package api
 
 import (
+	stdcontext "context"
 	"time"
 
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/library/validate"
+	"github.com/aichy126/onedock/middleware"
+	"github.com/aichy126/onedock/models"
 	"github.com/aichy126/onedock/service"
 	"github.com/aichy126/onedock/utils"
 	"github.com/gin-gonic/gin"
@@ -20,6 +25,50 @@ func NewApi() *Api {
 	}
 }
 
+// CloseStreams 结束实时日志等长连接，注册为 http.Server 的 RegisterOnShutdown
+func (api *Api) CloseStreams() {
+	if api.ser != nil {
+		api.ser.CloseStreams()
+	}
+}
+
+// Shutdown 进程退出前停止后台任务并排空端口代理
+func (api *Api) Shutdown(ctx stdcontext.Context) error {
+	if api.ser == nil {
+		return nil
+	}
+	return api.ser.Shutdown(ctx)
+}
+
+// requestContext 创建服务层上下文，并带上操作者、令牌指纹与请求 ID
+// 上下文随 HTTP 请求取消：客户端断开或超过接口超时后，进行中的 Docker 操作随之中止
+func requestContext(c *gin.Context) context.IContext {
+	ctx := context.Ginform(c)
+	if withContext, ok := ctx.(interface {
+		WithContext(stdcontext.Context) context.IContext
+	}); ok {
+		ctx = withContext.WithContext(c.Request.Context())
+	}
+	ctx.Set(models.ContextKeyActor, middleware.Actor(c))
+	ctx.Set(models.ContextKeyTokenID, c.GetString(middleware.TokenIDKey))
+	ctx.Set(models.ContextKeyAdmin, c.GetBool(middleware.AdminKey))
+	ctx.Set(models.ContextKeyRequestID, c.GetString(middleware.RequestIDKey))
+	if scope, ok := c.Get(middleware.ScopeKey); ok {
+		ctx.Set(models.ContextKeyScope, scope)
+	}
+	return ctx
+}
+
+// rfailBind 请求体绑定失败返回：校验错误在 data 中列出每个字段，其他错误（如 JSON 格式错误）只返回说明
+func rfailBind(c *gin.Context, err error) {
+	validation := validate.Errors(err)
+	if validation == nil {
+		utils.Rfail(c, "invalid request body: "+err.Error())
+		return
+	}
+	utils.RfailCode(c, utils.CodeValidationFailed, validation.Error(), validation)
+}
+
 // @Summary 健康检查
 // @Description 用于检查 OneDock 服务的健康状态和连通性，返回服务状态信息
 // @Tags 系统监控
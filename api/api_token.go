@@ -0,0 +1,128 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAPIToken 创建访问令牌
+// @Summary 创建访问令牌
+// @Description 创建带角色和有效期的访问令牌，仅管理员令牌可用。服务端只保存令牌摘要，明文只在本次响应中返回，请妥善保存
+// @Tags 令牌管理
+// @Accept json
+// @Produce json
+// @Param request body models.APITokenRequest true "令牌名称、角色和有效期"
+// @Success 200 {object} object{code=int,data=models.APIToken,msg=string} "创建成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/tokens [post]
+func (api *Api) CreateAPIToken(c *gin.Context) {
+	var req models.APITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	token, err := api.ser.CreateAPIToken(ctx, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Name", req.Name), log.Any("Message", "创建访问令牌失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, token)
+}
+
+// ListAPITokens 列出访问令牌
+// @Summary 列出访问令牌
+// @Description 列出通过接口创建的访问令牌及其状态，不返回令牌明文；配置文件中的令牌不在列表中。仅管理员令牌可用
+// @Tags 令牌管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=object{Tokens=[]models.APIToken,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/tokens [get]
+func (api *Api) ListAPITokens(c *gin.Context) {
+	ctx := requestContext(c)
+	tokens, err := api.ser.ListAPITokens(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "获取访问令牌列表失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Tokens": tokens,
+		"Total":  len(tokens),
+	})
+}
+
+// RevokeAPIToken 吊销访问令牌
+// @Summary 吊销访问令牌
+// @Description 令牌立即失效且不可恢复。仅管理员令牌可用
+// @Tags 令牌管理
+// @Accept json
+// @Produce json
+// @Param id path int true "令牌 ID" example:"1"
+// @Success 200 {object} object{code=int,data=models.APIToken,msg=string} "吊销成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/tokens/{id}/revoke [post]
+func (api *Api) RevokeAPIToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Rfail(c, "invalid token id")
+		return
+	}
+	ctx := requestContext(c)
+	token, err := api.ser.RevokeAPIToken(ctx, id)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ID", id), log.Any("Message", "吊销访问令牌失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, token)
+}
+
+// ExpireAPIToken 设置访问令牌过期时间
+// @Summary 设置访问令牌过期时间
+// @Description 不传 expires_at 时令牌立即过期；传入将来的时间可在轮换时给旧令牌留出切换时间。仅管理员令牌可用
+// @Tags 令牌管理
+// @Accept json
+// @Produce json
+// @Param id path int true "令牌 ID" example:"1"
+// @Param request body models.APITokenExpireRequest false "过期时间"
+// @Success 200 {object} object{code=int,data=models.APIToken,msg=string} "设置成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/tokens/{id}/expire [post]
+func (api *Api) ExpireAPIToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Rfail(c, "invalid token id")
+		return
+	}
+	var req models.APITokenExpireRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("API", log.Any("Error", err), log.Any("Message", "请求参数错误"))
+			rfailBind(c, err)
+			return
+		}
+	}
+	ctx := requestContext(c)
+	token, err := api.ser.ExpireAPIToken(ctx, id, req.ExpiresAt)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ID", id), log.Any("Message", "设置访问令牌过期时间失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, token)
+}
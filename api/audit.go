@@ -0,0 +1,60 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ListAuditLogs 查询审计日志
+// @Summary 查询审计日志
+// @Description 查询修改类 API 调用（POST、PUT、PATCH、DELETE）的审计记录：操作者、时间、请求体（敏感字段已脱敏）和结果，按时间倒序返回。记录保留 audit.retention_days 天
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param service query string false "服务名称" example:"nginx-web"
+// @Param actor query string false "操作者" example:"token:abcd****"
+// @Param method query string false "HTTP 方法" example:"DELETE"
+// @Param request_id query string false "请求 ID（响应头 X-Request-ID）" example:"8d3e6f0a-4b1c-4e2d-9a7b-1c2d3e4f5a6b"
+// @Param failed query bool false "只返回失败的请求" example:"true"
+// @Param since query string false "开始时间（RFC3339）" example:"2024-01-15T00:00:00Z"
+// @Param until query string false "结束时间（RFC3339）" example:"2024-01-16T00:00:00Z"
+// @Param limit query int false "返回条数，默认 50，最多 500" default(50)
+// @Param offset query int false "跳过的条数" default(0)
+// @Success 200 {object} object{code=int,data=models.AuditList,msg=string} "查询成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/audit [get]
+func (api *Api) ListAuditLogs(c *gin.Context) {
+	query := &models.AuditQuery{
+		Service:   c.Query("service"),
+		Actor:     c.Query("actor"),
+		Method:    strings.ToUpper(c.Query("method")),
+		RequestID: c.Query("request_id"),
+		Failed:    c.Query("failed") == "true",
+		Limit:     utils.StringToInt(c.Query("limit")),
+		Offset:    utils.StringToInt(c.Query("offset")),
+	}
+	for param, dest := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				utils.Rfail(c, "invalid "+param+": must be an RFC3339 time")
+				return
+			}
+			*dest = t
+		}
+	}
+
+	ctx := requestContext(c)
+	result, err := api.ser.ListAuditLogs(ctx, query)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, result)
+}
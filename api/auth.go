@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/middleware"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCLogin 跳转到身份提供方登录
+// @Summary OIDC 登录
+// @Description 跳转到身份提供方（auth.oidc）登录，完成后回到 redirect 指定的本站路径。登录后浏览器携带会话 Cookie 访问接口和 Swagger UI，无需令牌
+// @Tags 系统监控
+// @Produce json
+// @Param redirect query string false "登录后跳转的本站路径" example:"/swagger/index.html"
+// @Success 302 "跳转到身份提供方"
+// @Failure 200 {object} object{code=int,msg=string,data=object} "未开启 OIDC 登录"
+// @Router /onedock/auth/login [get]
+func (api *Api) OIDCLogin(c *gin.Context) {
+	location, err := middleware.OIDCLoginURL(c, c.Query("redirect"))
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "OIDC 登录失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, location)
+}
+
+// OIDCCallback 身份提供方登录回调
+// @Summary OIDC 登录回调
+// @Description 身份提供方登录完成后的回调地址（auth.oidc.redirect_url），校验 ID Token 并按用户组映射角色，写入会话 Cookie 后跳转回登录前的页面
+// @Tags 系统监控
+// @Produce json
+// @Param code query string true "授权码"
+// @Param state query string true "登录请求的 state"
+// @Success 302 "登录成功，跳转回登录前的页面"
+// @Failure 200 {object} object{code=int,msg=string,data=object} "登录失败"
+// @Router /onedock/auth/callback [get]
+func (api *Api) OIDCCallback(c *gin.Context) {
+	user, role, redirect, err := middleware.OIDCCallback(c)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "OIDC 登录回调失败"))
+		utils.RfailCode(c, utils.CodeUnauthorized, "login failed: "+err.Error(), nil)
+		return
+	}
+	log.Info("API", log.Any("User", user), log.Any("Role", role), log.Any("ClientIP", c.ClientIP()), log.Any("Message", "OIDC 登录成功"))
+	c.Redirect(http.StatusFound, redirect)
+}
+
+// Logout 退出登录
+// @Summary 退出登录
+// @Description 清除 OIDC 登录的会话 Cookie，令牌和 JWT 不受影响
+// @Tags 系统监控
+// @Produce json
+// @Success 200 {object} object{code=int,data=object,msg=string} "已退出"
+// @Router /onedock/auth/logout [post]
+func (api *Api) Logout(c *gin.Context) {
+	middleware.ClearSession(c)
+	utils.Rsucc(c, gin.H{"message": "logged out"})
+}
+
+// WhoAmI 获取当前身份
+// @Summary 获取当前身份
+// @Description 返回当前请求的操作者标识和角色，可用于确认令牌、JWT 或 OIDC 登录是否生效
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=models.Identity,msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/auth/whoami [get]
+func (api *Api) WhoAmI(c *gin.Context) {
+	var scope *models.TokenScope
+	if value, ok := c.Get(middleware.ScopeKey); ok {
+		scope, _ = value.(*models.TokenScope)
+	}
+	utils.Rsucc(c, &models.Identity{
+		Actor:       middleware.Actor(c),
+		Role:        c.GetString(middleware.RoleKey),
+		AuthEnabled: utils.ConfGetbool("auth.enabled"),
+		Scope:       scope,
+	})
+}
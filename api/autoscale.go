@@ -0,0 +1,85 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SetAutoscalePolicy 设置自动扩缩容策略
+// @Summary 设置自动扩缩容策略
+// @Description 定期采集副本的 CPU、内存使用率及端口代理的请求速率、并发请求数，按 ceil(当前副本数 × 当前值 / 目标值) 在最小、最大副本数之间自动调整副本数，多个目标取最大值。扩容、缩容分别有冷却时间，已存在的策略会被覆盖
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param policy body models.AutoscalePolicy true "自动扩缩容策略"
+// @Success 200 {object} object{code=int,data=models.AutoscaleStatus,msg=string} "设置成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/autoscale [put]
+func (api *Api) SetAutoscalePolicy(c *gin.Context) {
+	name := c.Param("name")
+	var req models.AutoscalePolicy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	status, err := api.ser.SetAutoscalePolicy(ctx, name, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "设置自动扩缩容策略失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// GetAutoscaleStatus 获取自动扩缩容状态
+// @Summary 获取自动扩缩容状态
+// @Description 获取服务的自动扩缩容策略，以及最近一次评估的平均使用率、请求速率、期望副本数和结论
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.AutoscaleStatus,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/autoscale [get]
+func (api *Api) GetAutoscaleStatus(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	status, err := api.ser.GetAutoscaleStatus(ctx, name)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// DeleteAutoscalePolicy 删除自动扩缩容策略
+// @Summary 删除自动扩缩容策略
+// @Description 关闭服务的自动扩缩容，当前副本数保持不变
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/autoscale [delete]
+func (api *Api) DeleteAutoscalePolicy(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	if err := api.ser.DeleteAutoscalePolicy(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "删除自动扩缩容策略失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, nil)
+}
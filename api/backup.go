@@ -0,0 +1,58 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateBackup 下载全量备份
+// @Summary 下载全量备份
+// @Description 将所有服务的期望状态、部署历史、端口登记与预留、密钥（保持 secret.key 加密）、服务模板、定时与自动扩缩容、定时任务、服务锁、镜像更新监视及全局代理配置打包为 tar.gz 归档，用于灾难恢复和迁移主机
+// @Tags 服务管理
+// @Produce application/gzip
+// @Success 200 {file} file "tar.gz 备份归档"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "备份失败"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/system/backup [get]
+func (api *Api) CreateBackup(c *gin.Context) {
+	ctx := requestContext(c)
+	var buf bytes.Buffer
+	if _, err := api.ser.CreateBackup(ctx, &buf); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "创建备份失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=onedock-backup-%s.tar.gz", time.Now().Format("20060102-150405")))
+	c.Data(http.StatusOK, "application/gzip", buf.Bytes())
+}
+
+// RestoreBackup 恢复全量备份
+// @Summary 恢复全量备份
+// @Description 请求体为 GET /onedock/system/backup 下载的归档。仅能在没有受管服务的主机上执行：用备份替换本机的全部状态，再执行一轮调和，按期望状态创建副本并启动端口代理。备份中有密钥时本机 secret.key 必须与备份时一致
+// @Tags 服务管理
+// @Accept application/gzip
+// @Produce json
+// @Param dry_run query bool false "只校验归档并返回将恢复的内容和警告" example:"true"
+// @Success 200 {object} object{code=int,data=models.RestoreResult,msg=string} "恢复完成"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "归档无效、密钥不匹配或主机上已有服务"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/system/restore [post]
+func (api *Api) RestoreBackup(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+	ctx := requestContext(c)
+	result, err := api.ser.RestoreBackup(ctx, c.Request.Body, dryRun)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "恢复备份失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, result)
+}
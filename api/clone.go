@@ -0,0 +1,39 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CloneService 克隆服务
+// @Summary 克隆服务
+// @Description 复制现有服务的配置部署为新服务，可覆盖镜像标签、环境变量、公共端口和副本数，用于快速创建评审或预发副本。新服务不能已存在，不填公共端口时自动分配，部署受端口登记和配额限制
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "原服务名称" example:"nginx-web"
+// @Param request body models.CloneRequest true "新服务名称与覆盖的配置"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "克隆成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/clone [post]
+func (api *Api) CloneService(c *gin.Context) {
+	name := c.Param("name")
+	var req models.CloneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "请求参数错误"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	service, err := api.ser.CloneService(ctx, name, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("NewName", req.Name), log.Any("Message", "克隆服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SaveCronJob 创建或更新定时任务
+// @Summary 创建或更新定时任务
+// @Description 按 cron 表达式周期性运行一次性任务，同名定时任务已存在时覆盖配置。concurrency_policy 决定上一次运行未结束时的处理：allow 同时运行、forbid 跳过（默认）、replace 取消上一次
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param cronjob body models.CronJobRequest true "定时任务配置"
+// @Success 200 {object} object{code=int,data=models.CronJob,msg=string} "保存成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/cronjobs [post]
+func (api *Api) SaveCronJob(c *gin.Context) {
+	var req models.CronJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	cronJob, err := api.ser.SaveCronJob(ctx, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("CronJob", req.Name), log.Any("Message", "保存定时任务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, cronJob)
+}
+
+// ListCronJobs 列出定时任务
+// @Summary 列出定时任务
+// @Description 获取全部定时任务及其下次触发时间、运行中的任务
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=object{CronJobs=[]models.CronJob,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/cronjobs [get]
+func (api *Api) ListCronJobs(c *gin.Context) {
+	ctx := requestContext(c)
+	cronJobs, err := api.ser.ListCronJobs(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "获取定时任务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"CronJobs": cronJobs,
+		"Total":    len(cronJobs),
+	})
+}
+
+// GetCronJob 获取定时任务
+// @Summary 获取定时任务
+// @Description 获取定时任务配置及最近的运行记录（含退出码和日志）
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param cronjob path string true "定时任务名称" example:"nightly-report"
+// @Success 200 {object} object{code=int,data=models.CronJob,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/cronjobs/{cronjob} [get]
+func (api *Api) GetCronJob(c *gin.Context) {
+	name := c.Param("cronjob")
+	ctx := requestContext(c)
+	cronJob, err := api.ser.GetCronJob(ctx, name)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, cronJob)
+}
+
+// RunCronJob 立即触发定时任务
+// @Summary 立即触发定时任务
+// @Description 不等待调度时间立即运行一次，同样遵循并发策略；暂停的定时任务也可以手动触发
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param cronjob path string true "定时任务名称" example:"nightly-report"
+// @Success 200 {object} object{code=int,data=models.Job,msg=string} "触发成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/cronjobs/{cronjob}/run [post]
+func (api *Api) RunCronJob(c *gin.Context) {
+	name := c.Param("cronjob")
+	ctx := requestContext(c)
+	job, err := api.ser.RunCronJob(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("CronJob", name), log.Any("Message", "触发定时任务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, job)
+}
+
+// DeleteCronJob 删除定时任务
+// @Summary 删除定时任务
+// @Description 删除定时任务及其运行记录，运行中的任务会被取消
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param cronjob path string true "定时任务名称" example:"nightly-report"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/cronjobs/{cronjob} [delete]
+func (api *Api) DeleteCronJob(c *gin.Context) {
+	name := c.Param("cronjob")
+	ctx := requestContext(c)
+	if err := api.ser.DeleteCronJob(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("CronJob", name), log.Any("Message", "删除定时任务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "cron job deleted successfully"})
+}
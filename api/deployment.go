@@ -0,0 +1,56 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ListDeployments 列出异步部署
+// @Summary 列出异步部署
+// @Description 按提交时间倒序列出通过 async=true 提交的部署，可按服务过滤
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param service query string false "服务名称" example:"nginx-web"
+// @Param limit query int false "返回条数，默认 50" default(50)
+// @Success 200 {object} object{code=int,data=object{Deployments=[]models.Deployment,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/deployments [get]
+func (api *Api) ListDeployments(c *gin.Context) {
+	limit := utils.StringToInt(c.DefaultQuery("limit", "50"))
+	ctx := requestContext(c)
+	deployments, err := api.ser.ListDeployments(ctx, c.Query("service"), limit)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "获取部署列表失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Deployments": deployments,
+		"Total":       len(deployments),
+	})
+}
+
+// GetDeployment 获取异步部署进度
+// @Summary 获取异步部署进度
+// @Description 获取异步部署的状态（pending / running / succeeded / failed）、当前阶段（pulling / creating / updating）、副本进度和失败原因
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param id path string true "部署ID" example:"7c1e9a04b2d3"
+// @Success 200 {object} object{code=int,data=models.Deployment,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "部署不存在"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/deployments/{id} [get]
+func (api *Api) GetDeployment(c *gin.Context) {
+	ctx := requestContext(c)
+	deployment, err := api.ser.GetDeployment(ctx, c.Param("id"))
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, deployment)
+}
@@ -1,20 +1,33 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
 	"github.com/aichy126/igo/context"
 	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/middleware"
 	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/service"
 	"github.com/aichy126/onedock/utils"
 	"github.com/gin-gonic/gin"
 )
 
 // DeployOrUpdateService 部署或更新服务
 // @Summary 部署或更新服务
-// @Description 部署新的服务或更新现有服务配置，支持容器镜像、端口映射、环境变量、卷挂载等完整配置
+// @Description 部署新的服务或更新现有服务配置，支持容器镜像、端口映射、环境变量、卷挂载等完整配置。
+// @Description dry_run 为 true（请求体字段或查询参数）时只校验请求、检查镜像是否可拉取和端口是否可用，返回 models.DeployPlan，不创建或修改任何容器
+// @Description async 为 true 时立即返回 models.Deployment，部署或更新在后台执行，通过 GET /onedock/deployments/{id} 查询进度，避免拉取大镜像时请求超时
 // @Tags 服务管理
 // @Accept json
 // @Produce json
 // @Param service body models.ServiceRequest true "服务配置信息"
+// @Param dry_run query bool false "只预演，不部署" example:"true"
+// @Param async query bool false "异步部署，立即返回部署ID" example:"true"
+// @Param Idempotency-Key header string false "幂等键，以相同的键重试时返回第一次成功的结果" example:"5f0c2a9e-8d1b-4c7e-9a43-2b6f1e0d7c11"
 // @Success 200 {object} object{code=int,data=models.Service,msg=string} "部署成功"
 // @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
 // @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
@@ -25,39 +38,106 @@ func (api *Api) DeployOrUpdateService(c *gin.Context) {
 	var req models.ServiceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
-		utils.Rfail(c, "invalid request body: "+err.Error())
+		rfailBind(c, err)
 		return
 	}
 
-	// 验证必填参数
-	if req.Name == "" || req.Image == "" || req.Tag == "" || req.InternalPort <= 0 {
-		utils.Rfail(c, "missing required fields: name, image, tag, internal_port")
+	ctx := requestContext(c)
+	// 预演：返回校验结果和将要执行的操作
+	if req.DryRun || c.Query("dry_run") == "true" {
+		plan, err := api.ser.PlanDeploy(ctx, &req)
+		if err != nil {
+			log.Error("API", log.Any("Error", err), log.Any("ServiceName", req.Name), log.Any("Message", "部署预演失败"))
+			utils.RfailErr(c, err)
+			return
+		}
+		utils.Rsucc(c, plan)
+		return
+	}
+
+	// 异步部署：立即返回部署ID
+	if c.Query("async") == "true" {
+		deployment, err := api.ser.StartDeployment(ctx, &req)
+		if err != nil {
+			log.Error("API", log.Any("Error", err), log.Any("ServiceName", req.Name), log.Any("RequestID", c.GetString(middleware.RequestIDKey)), log.Any("Message", "提交异步部署失败"))
+			utils.RfailErr(c, err)
+			return
+		}
+		utils.Rsucc(c, deployment)
 		return
 	}
-	ctx := context.Ginform(c)
 	// 调用服务层
 	service, err := api.ser.DeployOrUpdateService(ctx, &req)
 	if err != nil {
-		log.Error("API", log.Any("Error", err), log.Any("ServiceName", req.Name), log.Any("Message", "部署服务失败"))
-		utils.Rfail(c, err.Error())
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", req.Name), log.Any("RequestID", c.GetString(middleware.RequestIDKey)), log.Any("Message", "部署服务失败"))
+		utils.RfailErr(c, err)
 		return
 	}
 	utils.Rsucc(c, service)
 }
 
+// DeployBatch 批量部署服务
+// @Summary 批量部署服务
+// @Description 按顺序部署或更新多个服务，返回每个服务的结果，单个服务失败不影响其他服务。批内服务名称和公共端口不能重复
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param services body []models.ServiceRequest true "服务配置列表"
+// @Param Idempotency-Key header string false "幂等键，以相同的键重试时返回第一次成功的结果" example:"5f0c2a9e-8d1b-4c7e-9a43-2b6f1e0d7c11"
+// @Success 200 {object} object{code=int,data=object{Results=[]models.BatchDeployResult,Total=int,Succeeded=int,Failed=int},msg=string} "批量部署完成"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/batch [post]
+func (api *Api) DeployBatch(c *gin.Context) {
+	var reqs []models.ServiceRequest
+	if err := c.ShouldBindJSON(&reqs); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	if len(reqs) == 0 {
+		utils.Rfail(c, "at least one service is required")
+		return
+	}
+	ctx := requestContext(c)
+
+	results := api.ser.DeployBatch(ctx, reqs)
+	succeeded := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		}
+	}
+	utils.Rsucc(c, gin.H{
+		"Results":   results,
+		"Total":     len(results),
+		"Succeeded": succeeded,
+		"Failed":    len(results) - succeeded,
+	})
+}
+
 // ListServices 列出所有服务
 // @Summary 列出所有服务
-// @Description 获取系统中所有部署的服务列表，包括服务基本信息、状态和副本数量
+// @Description 获取系统中所有部署的服务列表，包括服务基本信息、状态和副本数量，可按命名空间过滤；令牌限定了服务范围时只返回范围内的服务
 // @Tags 服务管理
 // @Accept json
 // @Produce json
+// @Param namespace query string false "只列出指定命名空间的服务" example:"staging"
 // @Success 200 {object} object{code=int,data=object{Services=[]models.Service,Total=int},msg=string} "获取成功"
 // @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
 // @Security BearerAuth || TokenAuth || QueryAuth
 // @Router /onedock [get]
 func (api *Api) ListServices(c *gin.Context) {
-	ctx := context.Ginform(c)
-	services := api.ser.ListServices(ctx)
+	ctx := requestContext(c)
+	var services []*models.Service
+	if namespace := c.Query("namespace"); namespace != "" {
+		services = api.ser.ListServicesInNamespace(ctx, namespace)
+	} else {
+		services = api.ser.ListServices(ctx)
+	}
+	services = service.ScopedServices(ctx, services)
+	api.ser.AttachLabels(services)
 
 	// 转换为值类型切片
 	serviceList := make([]models.Service, len(services))
@@ -70,6 +150,100 @@ func (api *Api) ListServices(c *gin.Context) {
 	})
 }
 
+// SearchServices 搜索服务
+// @Summary 搜索服务
+// @Description 按用户标签、镜像、状态、端口和命名空间筛选服务，条件之间为且的关系，结果按名称排序；令牌限定了服务范围时只返回范围内的服务。
+// @Description label 为标签选择器：逗号分隔的 key=value 或 key（只要求存在该标签），可重复传入；image 匹配镜像名称或仓库路径的最后一段，可带标签（nginx:alpine）；port 匹配公共端口或内部端口
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param label query string false "标签选择器" example:"team=payments"
+// @Param image query string false "镜像名称" example:"nginx"
+// @Param status query string false "服务状态" Enums(stopped, starting, running, stopping, failed, updating)
+// @Param port query int false "公共端口或内部端口" example:"9303"
+// @Param namespace query string false "命名空间" example:"staging"
+// @Success 200 {object} object{code=int,data=object{Services=[]models.Service,Total=int},msg=string} "搜索成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/search [get]
+func (api *Api) SearchServices(c *gin.Context) {
+	query := &models.ServiceQuery{
+		Namespace: c.Query("namespace"),
+		Selector:  strings.Join(c.QueryArray("label"), ","),
+		Image:     c.Query("image"),
+		Status:    models.ServiceStatus(c.Query("status")),
+	}
+	if port := c.Query("port"); port != "" {
+		query.Port = utils.StringToInt(port)
+		if query.Port <= 0 {
+			utils.Rfail(c, "invalid port: must be between 1 and 65535")
+			return
+		}
+	}
+
+	ctx := requestContext(c)
+	services, err := api.ser.SearchServices(ctx, query)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "搜索服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	services = service.ScopedServices(ctx, services)
+	serviceList := make([]models.Service, len(services))
+	for i, service := range services {
+		serviceList[i] = *service
+	}
+	utils.Rsucc(c, gin.H{
+		"Services": serviceList,
+		"Total":    len(services),
+	})
+}
+
+// ListNamespaces 列出命名空间
+// @Summary 列出命名空间
+// @Description 列出所有有服务的命名空间及其服务数、副本总数。非默认命名空间的服务全名为 命名空间.名称，可直接用于其他服务接口的路径
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=object{Namespaces=[]models.NamespaceInfo,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/namespaces [get]
+func (api *Api) ListNamespaces(c *gin.Context) {
+	ctx := requestContext(c)
+	namespaces := api.ser.ListNamespaces(ctx)
+	utils.Rsucc(c, gin.H{
+		"Namespaces": namespaces,
+		"Total":      len(namespaces),
+	})
+}
+
+// ListQuotas 列出配额
+// @Summary 列出配额及使用量
+// @Description 列出配置文件中的命名空间配额与令牌配额，以及当前的服务数、副本总数和内存总量。令牌以指纹显示，令牌配额按服务所有者（首次部署该服务的令牌）统计
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=object{Quotas=[]models.QuotaStatus,Total=int},msg=string} "获取成功"
+// @Failure 200 {object} object{code=int,msg=string,data=object} "读取服务状态失败"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/quotas [get]
+func (api *Api) ListQuotas(c *gin.Context) {
+	ctx := requestContext(c)
+	quotas, err := api.ser.ListQuotas(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "获取配额失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Quotas": quotas,
+		"Total":  len(quotas),
+	})
+}
+
 // GetService 获取服务详情
 // @Summary 获取指定服务详情
 // @Description 根据服务名称获取服务的详细信息，包括配置、状态等
@@ -89,10 +263,10 @@ func (api *Api) GetService(c *gin.Context) {
 		utils.Rfail(c, "service name is required")
 		return
 	}
-	ctx := context.Ginform(c)
+	ctx := requestContext(c)
 	service := api.ser.GetService(ctx, name)
 	if service == nil {
-		utils.Rfail(c, "service not found")
+		utils.RfailCode(c, utils.CodeServiceNotFound, "service not found", nil)
 		return
 	}
 	utils.Rsucc(c, service)
@@ -100,12 +274,16 @@ func (api *Api) GetService(c *gin.Context) {
 
 // DeleteService 删除服务
 // @Summary 删除指定服务
-// @Description 删除指定的服务及其所有相关容器和资源，操作不可逆
+// @Description 删除指定的服务及其所有相关容器和资源。trash 为 true（未指定时取配置 trash.default）时放入回收站：保存删除时的配置并为服务保留公共端口，
+// @Description 保留期（trash.retention_days）内可通过 POST /onedock/trash/{name}/restore 恢复；keep_containers 为 true 时停止并保留容器，恢复时直接启动
 // @Tags 服务管理
 // @Accept json
 // @Produce json
 // @Param name path string true "服务名称" example:"nginx-web"
-// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Param trash query bool false "放入回收站，不填则取配置 trash.default" example:"true"
+// @Param keep_containers query bool false "放入回收站时停止并保留容器（隐含 trash=true）" example:"false"
+// @Param Idempotency-Key header string false "幂等键，以相同的键重试时返回第一次成功的结果" example:"5f0c2a9e-8d1b-4c7e-9a43-2b6f1e0d7c11"
+// @Success 200 {object} object{code=int,data=models.TrashedService,msg=string} "删除成功，放入回收站时返回回收站记录，否则 data 为空对象"
 // @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
 // @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
 // @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
@@ -117,17 +295,136 @@ func (api *Api) DeleteService(c *gin.Context) {
 		utils.Rfail(c, "service name is required")
 		return
 	}
-	ctx := context.Ginform(c)
+	ctx := requestContext(c)
+
+	keepContainers := c.Query("keep_containers") == "true"
+	trash := service.TrashByDefault()
+	if value := c.Query("trash"); value != "" {
+		trash = value == "true"
+	}
+	if trash || keepContainers {
+		trashed, err := api.ser.TrashService(ctx, name, keepContainers)
+		if err != nil {
+			log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "服务放入回收站失败"))
+			utils.RfailErr(c, err)
+			return
+		}
+		utils.Rsucc(c, trashed)
+		return
+	}
 
 	err := api.ser.DeleteService(ctx, name)
 	if err != nil {
 		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "删除服务失败"))
-		utils.Rfail(c, err.Error())
+		utils.RfailErr(c, err)
 		return
 	}
 	utils.Rsucc(c, gin.H{})
 }
 
+// DeleteServices 批量删除服务
+// @Summary 批量删除服务
+// @Description 按服务列表，或按命名空间和标签选择器（如 env=preview,team）删除多个服务，适合清理临时环境。
+// @Description confirm 不为 true 时只返回将被删除的服务（Services），不做任何修改；确认后逐个删除，单个服务失败不影响其他服务；trash 不填时取配置 trash.default
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param delete body models.BulkDeleteRequest true "删除范围"
+// @Param Idempotency-Key header string false "幂等键，以相同的键重试时返回第一次成功的结果" example:"5f0c2a9e-8d1b-4c7e-9a43-2b6f1e0d7c11"
+// @Success 200 {object} object{code=int,data=object{Confirmed=bool,Services=[]string,Results=[]models.BulkDeleteResult,Total=int,Succeeded=int,Failed=int},msg=string} "预览或删除完成"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/delete [post]
+func (api *Api) DeleteServices(c *gin.Context) {
+	var req models.BulkDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+
+	names, results, err := api.ser.DeleteServices(ctx, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "批量删除服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	succeeded := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		}
+	}
+	utils.Rsucc(c, gin.H{
+		"Confirmed": req.Confirm,
+		"Services":  names,
+		"Results":   results,
+		"Total":     len(names),
+		"Succeeded": succeeded,
+		"Failed":    len(results) - succeeded,
+	})
+}
+
+// StopService 停止服务
+// @Summary 停止服务（保留容器）
+// @Description 停止服务的全部副本和端口代理，但保留容器及其配置、端口，之后可通过 start 接口立即恢复。与删除服务不同，此操作不会销毁容器
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "停止成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/stop [post]
+func (api *Api) StopService(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+	ctx := requestContext(c)
+	service, err := api.ser.StopService(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "停止服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
+
+// StartService 启动服务
+// @Summary 启动已停止的服务
+// @Description 启动通过 stop 接口停止的服务，恢复全部副本和端口代理
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "启动成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/start [post]
+func (api *Api) StartService(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+	ctx := requestContext(c)
+	service, err := api.ser.StartService(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "启动服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
+
 // GetServiceStatus 获取服务状态
 // @Summary 获取服务运行状态
 // @Description 获取指定服务的详细运行状态，包括副本信息、健康状态、实例详情等
@@ -147,16 +444,40 @@ func (api *Api) GetServiceStatus(c *gin.Context) {
 		utils.Rfail(c, "service name is required")
 		return
 	}
-	ctx := context.Ginform(c)
+	ctx := requestContext(c)
 	status, err := api.ser.GetServiceStatus(ctx, name)
 	if err != nil {
 		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取服务状态失败"))
-		utils.Rfail(c, err.Error())
+		utils.RfailErr(c, err)
 		return
 	}
 	utils.Rsucc(c, status)
 }
 
+// GetServiceStats 获取服务的资源使用情况
+// @Summary 获取服务的资源使用情况
+// @Description 通过 Docker stats 接口并发采样每个运行中副本的 CPU、内存、网络和块设备 I/O，并汇总全部副本。CPU 使用率为约 1 秒内的平均值，网络和块设备为容器启动以来的累计值；单个副本采样失败时在该副本的 error 中说明
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.ServiceStats,msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 404 {object} object{code=int,msg=string,data=object} "服务未找到"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/stats [get]
+func (api *Api) GetServiceStats(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	stats, err := api.ser.GetServiceStats(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取服务资源使用情况失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, stats)
+}
+
 // ScaleService 服务扩缩容
 // @Summary 服务扩缩容
 // @Description 调整指定服务的副本数量，支持扩容和缩容操作，实际创建或删除容器实例
@@ -165,6 +486,7 @@ func (api *Api) GetServiceStatus(c *gin.Context) {
 // @Produce json
 // @Param name path string true "服务名称" example:"nginx-web"
 // @Param scale body models.ScaleRequest true "扩缩容配置"
+// @Param Idempotency-Key header string false "幂等键，以相同的键重试时返回第一次成功的结果" example:"5f0c2a9e-8d1b-4c7e-9a43-2b6f1e0d7c11"
 // @Success 200 {object} object{code=int,data=object,msg=string} "扩缩容成功"
 // @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
 // @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
@@ -181,7 +503,7 @@ func (api *Api) ScaleService(c *gin.Context) {
 	var req models.ScaleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
-		utils.Rfail(c, "invalid request body: "+err.Error())
+		rfailBind(c, err)
 		return
 	}
 
@@ -190,11 +512,11 @@ func (api *Api) ScaleService(c *gin.Context) {
 		utils.Rfail(c, "replicas must be greater than or equal to 0")
 		return
 	}
-	ctx := context.Ginform(c)
+	ctx := requestContext(c)
 	err := api.ser.ScaleService(ctx, name, req.Replicas)
 	if err != nil {
 		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Replicas", req.Replicas), log.Any("Message", "扩缩容失败"))
-		utils.Rfail(c, err.Error())
+		utils.RfailErr(c, err)
 		return
 	}
 	utils.Rsucc(c, gin.H{
@@ -203,18 +525,495 @@ func (api *Api) ScaleService(c *gin.Context) {
 	})
 }
 
+// ScaleServices 批量扩缩容
+// @Summary 批量扩缩容
+// @Description 一次调整多个服务的副本数，副本数为 0 表示删除服务（配置了 trash.default 时放入回收站）。先检查全部服务（是否存在、锁定、发布进行中、令牌服务范围），
+// @Description 任一服务不满足条件时整批都不执行（Applied 为 false）；检查通过后先缩容后扩容逐个执行，单个服务失败不影响其他服务
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param scale body models.BulkScaleRequest true "服务名称到目标副本数的映射"
+// @Param Idempotency-Key header string false "幂等键，以相同的键重试时返回第一次成功的结果" example:"5f0c2a9e-8d1b-4c7e-9a43-2b6f1e0d7c11"
+// @Success 200 {object} object{code=int,data=object{Applied=bool,Results=[]models.BulkScaleResult,Total=int,Succeeded=int,Failed=int},msg=string} "批量扩缩容完成"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/scale [post]
+func (api *Api) ScaleServices(c *gin.Context) {
+	var req models.BulkScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	if len(req.Services) == 0 {
+		utils.Rfail(c, "at least one service is required")
+		return
+	}
+	ctx := requestContext(c)
+
+	results, applied := api.ser.ScaleServices(ctx, req.Services)
+	succeeded := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		}
+	}
+	utils.Rsucc(c, gin.H{
+		"Applied":   applied,
+		"Results":   results,
+		"Total":     len(results),
+		"Succeeded": succeeded,
+		"Failed":    len(results) - succeeded,
+	})
+}
+
+// GetServiceDrift 获取服务漂移报告
+// @Summary 获取服务漂移报告
+// @Description 比较期望状态与实际运行的容器（镜像标签、配置哈希、副本数、运行状态），只报告差异，不做任何修改
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.DriftReport,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/drift [get]
+func (api *Api) GetServiceDrift(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+	ctx := requestContext(c)
+	report, err := api.ser.GetServiceDrift(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取服务漂移报告失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, report)
+}
+
+// DiffService 比较服务配置差异
+// @Summary 比较服务配置差异
+// @Description 比较请求中的服务配置与运行中的服务，逐项返回镜像、环境变量、卷挂载、命令和端口等的变化，不做任何修改
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param service body models.ServiceRequest true "新的服务配置"
+// @Success 200 {object} object{code=int,data=models.ConfigDiff,msg=string} "比较成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/diff [post]
+func (api *Api) DiffService(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+	var req models.ServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "请求参数错误"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	diff, err := api.ser.DiffService(ctx, name, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "比较服务配置差异失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, diff)
+}
+
+// ValidateService 校验服务配置
+// @Summary 校验服务配置
+// @Description 静态检查部署请求：服务名称格式、端口范围和占用、镜像引用格式、卷挂载路径、环境变量文件是否可读、密钥和占位符引用等，返回错误和警告，不访问镜像仓库，不创建或修改任何容器。校验不通过时仍返回 200，valid 为 false
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param service body models.ServiceRequest true "服务配置"
+// @Success 200 {object} object{code=int,data=models.ValidationReport,msg=string} "校验完成"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求体不是合法的 JSON"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/validate [post]
+func (api *Api) ValidateService(c *gin.Context) {
+	// 不使用 ShouldBindJSON：字段校验错误作为报告的一部分返回，而不是直接拒绝请求
+	var req models.ServiceRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		utils.Rfail(c, "invalid request body: "+err.Error())
+		return
+	}
+	ctx := requestContext(c)
+	utils.Rsucc(c, api.ser.ValidateServiceRequest(ctx, &req))
+}
+
+// ExportService 导出服务配置
+// @Summary 导出服务配置
+// @Description 将服务的生效配置导出为 docker-compose YAML（format=compose，默认），或 OneDock 服务配置（format=spec，可直接用于部署接口重新导入）。名称像密码、令牌的明文环境变量导出为 ******，密钥引用原样导出
+// @Tags 服务管理
+// @Accept json
+// @Produce json,application/x-yaml
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param format query string false "导出格式：compose / spec" Enums(compose, spec) default(compose)
+// @Success 200 {object} object{code=int,data=models.ServiceRequest,msg=string} "导出成功（format=spec）；format=compose 时直接返回 YAML 文本"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/export [get]
+func (api *Api) ExportService(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+	ctx := requestContext(c)
+
+	switch format := c.DefaultQuery("format", "compose"); format {
+	case "spec":
+		spec, err := api.ser.ExportSpec(ctx, name)
+		if err != nil {
+			log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "导出服务配置失败"))
+			utils.RfailErr(c, err)
+			return
+		}
+		utils.Rsucc(c, spec)
+	case "compose":
+		out, err := api.ser.ExportCompose(ctx, name)
+		if err != nil {
+			log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "导出 compose 配置失败"))
+			utils.RfailErr(c, err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.compose.yaml", name))
+		c.Data(http.StatusOK, "application/x-yaml; charset=utf-8", out)
+	default:
+		utils.Rfail(c, "format must be compose or spec")
+	}
+}
+
+// GetServiceHistory 获取服务部署历史
+// @Summary 获取服务部署历史
+// @Description 获取服务的部署/更新/回滚记录，包含每次的配置快照、时间和操作者，按版本号倒序
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=object{Revisions=[]models.DeploymentRevision,Total=int},msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/history [get]
+func (api *Api) GetServiceHistory(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+	ctx := requestContext(c)
+	revisions, err := api.ser.GetServiceHistory(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取部署历史失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Revisions": revisions,
+		"Total":     len(revisions),
+	})
+}
+
+// GetServiceSpec 获取服务期望状态
+// @Summary 获取服务期望状态
+// @Description 获取持久化保存的服务完整配置（环境变量、卷挂载、副本数、端口等），扩容和回滚时按此配置创建副本
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.DesiredState,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/spec [get]
+func (api *Api) GetServiceSpec(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+	ctx := requestContext(c)
+	state, err := api.ser.GetDesiredState(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取服务期望状态失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, state)
+}
+
+// RollbackService 回滚服务
+// @Summary 回滚服务到历史版本
+// @Description 使用部署历史中指定版本的配置对服务执行滚动更新，不指定版本时回滚到上一个版本
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param revision query int false "目标版本号，不填则回滚到上一个版本" example:"2"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "回滚成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/rollback [post]
+func (api *Api) RollbackService(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+
+	revision := 0
+	if value := c.Query("revision"); value != "" {
+		var err error
+		if revision, err = strconv.Atoi(value); err != nil || revision <= 0 {
+			utils.Rfail(c, "revision must be a positive integer")
+			return
+		}
+	}
+
+	ctx := requestContext(c)
+	service, err := api.ser.RollbackService(ctx, name, revision)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Revision", revision), log.Any("Message", "回滚服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
+
+// GetRollout 获取滚动更新状态
+// @Summary 获取滚动更新状态
+// @Description 获取服务最近一次滚动更新的状态与进度（更新中、已暂停、已完成、失败或已中止）
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.RolloutStatus,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/rollout [get]
+func (api *Api) GetRollout(c *gin.Context) {
+	api.rolloutAction(c, api.ser.GetRollout, "获取滚动更新状态失败")
+}
+
+// PauseRollout 暂停滚动更新
+// @Summary 暂停滚动更新
+// @Description 暂停服务进行中的滚动更新，当前批次完成后生效。暂停期间端口代理会切换到新旧混合的副本，便于观察新版本表现
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.RolloutStatus,msg=string} "暂停成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/rollout/pause [post]
+func (api *Api) PauseRollout(c *gin.Context) {
+	api.rolloutAction(c, api.ser.PauseRollout, "暂停滚动更新失败")
+}
+
+// ResumeRollout 恢复滚动更新
+// @Summary 恢复滚动更新
+// @Description 恢复已暂停的滚动更新，继续更新剩余副本
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.RolloutStatus,msg=string} "恢复成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/rollout/resume [post]
+func (api *Api) ResumeRollout(c *gin.Context) {
+	api.rolloutAction(c, api.ser.ResumeRollout, "恢复滚动更新失败")
+}
+
+// AbortRollout 中止滚动更新
+// @Summary 中止滚动更新
+// @Description 中止进行中或已暂停的滚动更新，已更新的副本将回滚到更新前的配置
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.RolloutStatus,msg=string} "中止成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/rollout/abort [post]
+func (api *Api) AbortRollout(c *gin.Context) {
+	api.rolloutAction(c, api.ser.AbortRollout, "中止滚动更新失败")
+}
+
+// rolloutAction 滚动更新相关接口的公共处理
+func (api *Api) rolloutAction(c *gin.Context, action func(context.IContext, string) (*models.RolloutStatus, error), failMessage string) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+	ctx := requestContext(c)
+	status, err := action(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", failMessage))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// Reconcile 立即执行一轮调和
+// @Summary 立即执行调和
+// @Description 按期望状态调和所有服务：补齐缺失副本、移除多余副本、启动意外停止的副本并恢复端口代理。已停止或正在滚动更新的服务会被跳过
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=[]models.ReconcileResult,msg=string} "调和完成"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/reconcile [post]
+func (api *Api) Reconcile(c *gin.Context) {
+	ctx := requestContext(c)
+	results, err := api.ser.Reconcile(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "执行调和失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, results)
+}
+
+// CleanupOrphans 清理孤立容器
+// @Summary 清理孤立容器
+// @Description 查找并删除带管理前缀的孤立容器：名称无法解析、服务已删除（仅已停止的容器）、公共端口与期望状态不一致、副本编号重复（保留运行中、符合期望配置或最新的一个）。正在滚动更新的服务会被跳过
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "只列出孤立容器，不删除" example:"true"
+// @Success 200 {object} object{code=int,data=models.CleanupResult,msg=string} "清理完成"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/system/cleanup [post]
+func (api *Api) CleanupOrphans(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+	ctx := requestContext(c)
+	result, err := api.ser.CleanupOrphans(ctx, dryRun)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "清理孤立容器失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, result)
+}
+
 // GetProxyStats 获取代理统计信息
 // @Summary 获取端口代理统计信息
-// @Description 获取所有端口代理的统计信息，包括单副本代理和负载均衡器的详细状态
+// @Description 获取所有端口代理的统计信息：单副本代理和负载均衡器的数量，以及每个端口代理的监听地址、负载均衡策略、请求数、失败数和各后端（副本）的统计，字段与 /onedock/{name}/proxy 相同
 // @Tags 服务管理
 // @Accept json
 // @Produce json
-// @Success 200 {object} object{code=int,data=object,msg=string} "获取成功"
+// @Success 200 {object} object{code=int,data=models.ProxyOverview,msg=string} "获取成功"
 // @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
 // @Security BearerAuth || TokenAuth || QueryAuth
 // @Router /onedock/proxy/stats [get]
 func (api *Api) GetProxyStats(c *gin.Context) {
-	ctx := context.Ginform(c)
+	ctx := requestContext(c)
 	stats := api.ser.PortManager.GetProxyStats(ctx)
 	utils.Rsucc(c, stats)
 }
+
+// GetServiceProxyStats 获取服务的端口代理统计信息
+// @Summary 获取服务的端口代理统计信息
+// @Description 只返回指定服务的端口代理：监听地址、代理类型、负载均衡策略、端口累计请求数和失败数，以及每个后端（副本）的连接数、请求数、失败数。服务没有公共端口或已停止时 proxies 为空
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.ServiceProxyStats,msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 404 {object} object{code=int,msg=string,data=object} "服务未找到"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/proxy [get]
+func (api *Api) GetServiceProxyStats(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	stats, err := api.ser.PortManager.ServiceProxyStats(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取服务代理统计信息失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, stats)
+}
+
+// SetServiceProxyStrategy 设置服务的负载均衡策略
+// @Summary 设置服务的负载均衡策略
+// @Description 运行时切换服务公共端口的负载均衡策略，立即生效，无需重建代理，正在处理的请求不受影响。设置的策略在扩缩容、更新重建代理和热加载配置后保留，服务删除或 OneDock 重启后恢复为配置文件中的默认策略；strategy 为空表示立即恢复默认策略
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param strategy body models.ProxyStrategyRequest true "负载均衡策略"
+// @Success 200 {object} object{code=int,data=models.ServiceProxyStats,msg=string} "设置成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 404 {object} object{code=int,msg=string,data=object} "服务未找到"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/proxy/strategy [put]
+func (api *Api) SetServiceProxyStrategy(c *gin.Context) {
+	name := c.Param("name")
+	var req models.ProxyStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	stats, err := api.ser.PortManager.SetServiceStrategy(ctx, name, req.Strategy)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "设置负载均衡策略失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, stats)
+}
+
+// ReloadProxyConfig 热加载代理配置
+// @Summary 热加载代理配置
+// @Description 重新读取配置文件中的代理相关配置（负载均衡策略、读写超时）并应用到所有运行中的端口代理，不中断现有连接。也可以向进程发送 SIGHUP 触发
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=object,msg=string} "重新加载成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/proxy/reload [post]
+func (api *Api) ReloadProxyConfig(c *gin.Context) {
+	ctx := requestContext(c)
+	result, err := api.ser.PortManager.ReloadConfig(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "重新加载代理配置失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, result)
+}
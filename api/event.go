@@ -0,0 +1,135 @@
+package api
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ListEvents 查询服务生命周期事件
+// @Summary 查询服务事件
+// @Description 查询部署、滚动更新、更新失败、扩缩容、删除、副本崩溃、崩溃循环、自动扩缩容、端口代理重启等事件，按时间倒序返回。事件保留 events.retention_days 天
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param service query string false "服务名称" example:"nginx-web"
+// @Param type query string false "事件类型" Enums(deployed, updated, update_failed, scaled, deleted, restored, replica_crashed, replica_restarted, replica_recreated, crash_loop, autoscaled, proxy_restarted)
+// @Param since query string false "开始时间（RFC3339）" example:"2024-01-15T00:00:00Z"
+// @Param until query string false "结束时间（RFC3339）" example:"2024-01-16T00:00:00Z"
+// @Param limit query int false "返回条数，默认 50，最多 500" default(50)
+// @Param offset query int false "跳过的条数" default(0)
+// @Success 200 {object} object{code=int,data=models.EventList,msg=string} "查询成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/events [get]
+func (api *Api) ListEvents(c *gin.Context) {
+	query := &models.EventQuery{
+		Service: c.Query("service"),
+		Type:    c.Query("type"),
+		Limit:   utils.StringToInt(c.Query("limit")),
+		Offset:  utils.StringToInt(c.Query("offset")),
+	}
+	for param, dest := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				utils.Rfail(c, "invalid "+param+": must be an RFC3339 time")
+				return
+			}
+			*dest = t
+		}
+	}
+
+	ctx := requestContext(c)
+	result, err := api.ser.ListEvents(ctx, query)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, result)
+}
+
+// ListServiceEvents 获取服务最近的事件
+// @Summary 获取服务最近的事件
+// @Description 返回该服务最近的生命周期事件（部署、更新、扩缩容、副本崩溃、代理重启等），按时间倒序。服务删除后仍可查询保留期（events.retention_days）内的事件
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param type query string false "只返回这些类型的事件，多个类型用逗号分隔" example:"replica_crashed,crash_loop"
+// @Param limit query int false "返回条数，默认 20，最多 500" default(20)
+// @Success 200 {object} object{code=int,data=models.EventList,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/events [get]
+func (api *Api) ListServiceEvents(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		utils.Rfail(c, "service name is required")
+		return
+	}
+	ctx := requestContext(c)
+	result, err := api.ser.ListServiceEvents(ctx, name, eventTypes(c), utils.StringToInt(c.Query("limit")))
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, result)
+}
+
+// eventTypes 解析逗号分隔的 type 参数
+func eventTypes(c *gin.Context) []string {
+	var types []string
+	for _, t := range strings.Split(c.Query("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// StreamEvents 实时推送服务事件
+// @Summary 实时推送服务事件
+// @Description 以 Server-Sent Events 推送服务生命周期事件（事件名 event，数据为事件 JSON），以及只在事件流中出现的端口代理启动、停止事件，客户端无需轮询服务列表。只推送连接之后发生的事件，断线期间的生命周期事件可通过 /onedock/events 查询。客户端读取过慢时丢弃事件
+// @Tags 服务管理
+// @Produce text/event-stream
+// @Param service query string false "只推送该服务的事件" example:"nginx-web"
+// @Param type query string false "只推送这些类型的事件，多个类型用逗号分隔" example:"deployed,replica_crashed,proxy_started,proxy_stopped"
+// @Success 200 {object} models.Event "事件流，每个 SSE 事件一条服务事件"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/events/stream [get]
+func (api *Api) StreamEvents(c *gin.Context) {
+	// 客户端断开时取消订阅
+	ctx, cancel := requestContext(c).WithCancel()
+	defer cancel()
+	events := api.ser.StreamEvents(ctx, c.Query("service"), eventTypes(c))
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepalive := time.NewTicker(logStreamKeepalive)
+	defer keepalive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent("event", event)
+			return true
+		case <-keepalive.C:
+			io.WriteString(w, ": keepalive\n\n")
+			return true
+		}
+	})
+}
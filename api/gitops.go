@@ -0,0 +1,46 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetGitOpsStatus 获取 GitOps 同步状态
+// @Summary 获取 GitOps 同步状态
+// @Description 获取 gitops 配置的仓库、分支，以及最近一次同步的提交、时间、错误和各服务的操作结果
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=models.GitOpsStatus,msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/gitops [get]
+func (api *Api) GetGitOpsStatus(c *gin.Context) {
+	ctx := requestContext(c)
+	utils.Rsucc(c, api.ser.GetGitOpsStatus(ctx))
+}
+
+// SyncGitOps 立即执行 GitOps 同步
+// @Summary 立即执行 GitOps 同步
+// @Description 拉取 gitops.repo 的最新提交，部署仓库中新增的服务、滚动更新配置有变化的服务、调整副本数有变化的服务；开启 gitops.prune 时删除仓库中已移除的服务。任一配置文件无效时不执行任何操作
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "只返回将要执行的操作，不修改任何服务" example:"true"
+// @Success 200 {object} object{code=int,data=models.GitOpsStatus,msg=string} "同步完成，各服务的错误见 services[].error"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "未配置 GitOps、拉取仓库或解析配置失败"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/gitops/sync [post]
+func (api *Api) SyncGitOps(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+	ctx := requestContext(c)
+	status, err := api.ser.SyncGitOps(ctx, dryRun)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "GitOps 同步失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aichy126/onedock/library/graphql"
+	"github.com/gin-gonic/gin"
+)
+
+// QueryGraphQL 只读 GraphQL 查询
+// @Summary 只读 GraphQL 查询
+// @Description 一次请求获取服务 → 副本 → 端口 → 代理统计，代替逐个服务调用状态和代理统计接口。只支持 query 操作，对象字段与 REST 接口返回的 JSON 字段同名。
+// @Description 根字段：services(namespace, label, image, status, port) 的条件与搜索接口相同；service(name) 获取单个服务。Service 额外提供 instances（副本）和 proxies（端口代理统计），Instance 额外提供 ports（各公共端口及该副本在代理中的后端统计）。
+// @Description 支持别名、片段、变量和 @include / @skip 指令，查询可用 __typename。响应为标准 GraphQL 格式（data / errors），不使用其他接口的 code / msg 包装：语法或校验错误时 data 为 null；单个字段出错时该字段为 null，错误带 path 列在 errors 中。
+// @Description 接口只读取数据，只读令牌可以使用 POST 调用；限定服务范围的令牌只能查询范围内的服务。也可以用 GET 调用，参数为 query、operationName 和 JSON 格式的 variables
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param request body graphql.Request true "GraphQL 请求"
+// @Success 200 {object} graphql.Response "执行结果"
+// @Failure 400 {object} graphql.Response "请求格式错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/graphql [post]
+func (api *Api) QueryGraphQL(c *gin.Context) {
+	var req graphql.Request
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, &graphql.Response{Errors: []*graphql.Error{{Message: "invalid variables: " + err.Error()}}})
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &graphql.Response{Errors: []*graphql.Error{{Message: "invalid request body: " + err.Error()}}})
+		return
+	}
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, &graphql.Response{Errors: []*graphql.Error{{Message: "query is required"}}})
+		return
+	}
+
+	ctx := requestContext(c)
+	c.JSON(http.StatusOK, api.ser.QueryGraphQL(ctx, &req))
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/aichy126/onedock/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Healthz 存活检查
+// @Summary 存活检查
+// @Description 进程能处理请求即返回 200，不检查依赖，供进程管理器判断是否需要重启。不需要权限验证
+// @Tags 系统监控
+// @Produce json
+// @Success 200 {object} object{code=int,data=object{status=string},msg=string} "进程存活"
+// @Router /healthz [get]
+func (api *Api) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{"status": "ok"},
+		"msg":  "succeed",
+	})
+}
+
+// Readyz 就绪检查
+// @Summary 就绪检查
+// @Description 检查 Docker 守护进程是否可达、状态存储和缓存是否可用、启动时的端口代理恢复是否完成，全部通过返回 200，否则返回 503，供负载均衡判断是否转发请求。不需要权限验证
+// @Tags 系统监控
+// @Produce json
+// @Success 200 {object} object{code=int,data=models.Readiness,msg=string} "已就绪"
+// @Failure 503 {object} object{code=int,data=models.Readiness,msg=string} "未就绪"
+// @Router /readyz [get]
+func (api *Api) Readyz(c *gin.Context) {
+	if api.ser == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"code": 1,
+			"data": nil,
+			"msg":  "service is not initialized",
+		})
+		return
+	}
+	readiness := api.ser.Readiness(requestContext(c))
+	if !readiness.Ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"code": 1,
+			"data": readiness,
+			"msg":  notReadyMessage(readiness),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": readiness,
+		"msg":  "succeed",
+	})
+}
+
+// notReadyMessage 列出未通过的检查项
+func notReadyMessage(readiness *models.Readiness) string {
+	msg := "not ready:"
+	for _, check := range readiness.Checks {
+		if !check.Ready {
+			msg += " " + check.Name
+		}
+	}
+	return msg
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SetImageWatch 设置镜像更新监视
+// @Summary 设置镜像更新监视
+// @Description 定期查询镜像仓库：digest 方式检查当前标签是否指向了新的镜像，semver 方式检查是否发布了更新的版本标签（可用 pattern 正则限定范围，如 ^1\.25\.）。action 为 notify 时只记录结果，为 redeploy 时自动滚动更新服务。已存在的监视会被覆盖
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param watch body models.ImageWatch true "镜像更新监视"
+// @Success 200 {object} object{code=int,data=models.ImageWatchStatus,msg=string} "设置成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/image-watch [put]
+func (api *Api) SetImageWatch(c *gin.Context) {
+	name := c.Param("name")
+	var req models.ImageWatch
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	status, err := api.ser.SetImageWatch(ctx, name, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "设置镜像更新监视失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// GetImageWatch 获取镜像更新监视状态
+// @Summary 获取镜像更新监视状态
+// @Description 获取服务的镜像更新监视配置，以及最近一次检查发现的新标签或摘要、是否有可用更新和错误信息
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.ImageWatchStatus,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/image-watch [get]
+func (api *Api) GetImageWatch(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	status, err := api.ser.GetImageWatch(ctx, name)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// CheckImageWatch 立即检查镜像更新
+// @Summary 立即检查镜像更新
+// @Description 不等待检查间隔，立即查询镜像仓库并返回检查结果；action 为 redeploy 且发现更新时会同步完成滚动更新
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.ImageWatchStatus,msg=string} "检查完成"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/image-watch/check [post]
+func (api *Api) CheckImageWatch(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	status, err := api.ser.CheckImageWatch(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "检查镜像更新失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// DeleteImageWatch 删除镜像更新监视
+// @Summary 删除镜像更新监视
+// @Description 停止检查服务的镜像更新，不影响运行中的副本
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/image-watch [delete]
+func (api *Api) DeleteImageWatch(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	if err := api.ser.DeleteImageWatch(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "删除镜像更新监视失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, nil)
+}
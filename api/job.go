@@ -0,0 +1,132 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RunJob 提交一次性任务
+// @Summary 提交一次性任务
+// @Description 运行一个容器直到结束（数据库迁移、批处理等），接口立即返回任务ID，通过任务详情接口查看状态、退出码和日志。容器不映射端口、不自动重启，结束后自动删除
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param job body models.JobRequest true "任务配置"
+// @Success 200 {object} object{code=int,data=models.Job,msg=string} "提交成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/jobs [post]
+func (api *Api) RunJob(c *gin.Context) {
+	var req models.JobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	job, err := api.ser.RunJob(ctx, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Image", req.Image), log.Any("Message", "提交任务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, job)
+}
+
+// ListJobs 列出任务
+// @Summary 列出任务
+// @Description 按提交时间倒序列出最近的任务（不含日志）
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param limit query int false "返回条数，默认 50" default(50)
+// @Success 200 {object} object{code=int,data=object{Jobs=[]models.Job,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/jobs [get]
+func (api *Api) ListJobs(c *gin.Context) {
+	limit := utils.StringToInt(c.DefaultQuery("limit", "50"))
+	ctx := requestContext(c)
+	jobs, err := api.ser.ListJobs(ctx, limit)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "获取任务列表失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Jobs":  jobs,
+		"Total": len(jobs),
+	})
+}
+
+// GetJob 获取任务详情
+// @Summary 获取任务详情
+// @Description 获取任务状态、退出码和容器输出
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param job path string true "任务ID" example:"3f2a9c1b7d4e"
+// @Success 200 {object} object{code=int,data=models.Job,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/jobs/{job} [get]
+func (api *Api) GetJob(c *gin.Context) {
+	id := c.Param("job")
+	ctx := requestContext(c)
+	job, err := api.ser.GetJob(ctx, id)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, job)
+}
+
+// CancelJob 取消任务
+// @Summary 取消任务
+// @Description 取消运行中的任务，容器会被强制删除，任务状态为 cancelled
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param job path string true "任务ID" example:"3f2a9c1b7d4e"
+// @Success 200 {object} object{code=int,data=object,msg=string} "取消成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/jobs/{job}/cancel [post]
+func (api *Api) CancelJob(c *gin.Context) {
+	id := c.Param("job")
+	ctx := requestContext(c)
+	if err := api.ser.CancelJob(ctx, id); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("JobID", id), log.Any("Message", "取消任务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "job cancelled"})
+}
+
+// DeleteJob 删除任务记录
+// @Summary 删除任务记录
+// @Description 删除已结束的任务记录，运行中的任务需先取消
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param job path string true "任务ID" example:"3f2a9c1b7d4e"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/jobs/{job} [delete]
+func (api *Api) DeleteJob(c *gin.Context) {
+	id := c.Param("job")
+	ctx := requestContext(c)
+	if err := api.ser.DeleteJob(ctx, id); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("JobID", id), log.Any("Message", "删除任务记录失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "job deleted successfully"})
+}
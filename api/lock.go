@@ -0,0 +1,64 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// LockService 锁定服务
+// @Summary 锁定服务
+// @Description 锁定后只有管理员令牌（auth.admin_tokens）可以部署、更新、回滚、扩缩容、启停或删除该服务，定时扩缩容与自动扩缩容暂停。请求体可选
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param request body models.LockRequest false "锁定原因"
+// @Success 200 {object} object{code=int,data=models.ServiceLock,msg=string} "锁定成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/lock [post]
+func (api *Api) LockService(c *gin.Context) {
+	name := c.Param("name")
+	var req models.LockRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("API", log.Any("Error", err), log.Any("Message", "请求参数错误"))
+			rfailBind(c, err)
+			return
+		}
+	}
+	ctx := requestContext(c)
+	lock, err := api.ser.LockService(ctx, name, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "锁定服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, lock)
+}
+
+// UnlockService 解除服务锁定
+// @Summary 解除服务锁定
+// @Description 解除服务锁定，恢复部署、扩缩容和删除等操作
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=object,msg=string} "解除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/unlock [post]
+func (api *Api) UnlockService(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	if err := api.ser.UnlockService(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "解除服务锁定失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "service unlocked successfully"})
+}
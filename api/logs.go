@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// logStreamKeepalive 没有日志时发送注释行的间隔，避免中间代理断开空闲连接
+const logStreamKeepalive = 15 * time.Second
+
+// StreamServiceLogs 实时跟踪服务日志
+// @Summary 实时跟踪服务日志
+// @Description 合并跟踪服务全部副本的日志，以 Server-Sent Events 推送（事件名 log，数据为带副本名称的 JSON）；format=text 时输出 "副本名称 | 日志" 格式的纯文本，便于 curl 使用。扩容、滚动更新或重启后的副本会自动加入，客户端断开后停止
+// @Tags 服务管理
+// @Produce text/event-stream
+// @Produce text/plain
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param tail query string false "每个副本先输出的历史行数，all 表示全部" default(100)
+// @Param since query string false "只输出该时间之后的日志：RFC3339 时间或相对时长" example:"10m"
+// @Param format query string false "输出格式" Enums(sse, text) default(sse)
+// @Success 200 {object} models.LogLine "日志流，每个事件一行日志"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误或服务不存在"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/logs/stream [get]
+func (api *Api) StreamServiceLogs(c *gin.Context) {
+	name := c.Param("name")
+	options := &models.LogStreamOptions{Tail: c.DefaultQuery("tail", "100")}
+	if options.Tail != "all" {
+		if n, err := strconv.Atoi(options.Tail); err != nil || n < 0 {
+			utils.Rfail(c, "invalid tail: must be a non-negative number or all")
+			return
+		}
+	}
+	if value := c.Query("since"); value != "" {
+		since, err := parseSince(value)
+		if err != nil {
+			utils.RfailErr(c, err)
+			return
+		}
+		options.Since = since
+	}
+	format := c.DefaultQuery("format", "sse")
+	if format != "sse" && format != "text" {
+		utils.Rfail(c, "invalid format: must be sse or text")
+		return
+	}
+
+	// 客户端断开时取消跟踪
+	ctx, cancel := requestContext(c).WithCancel()
+	defer cancel()
+	lines, err := api.ser.StreamServiceLogs(ctx, name, options)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+
+	if format == "text" {
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		c.Header("Content-Type", "text/event-stream")
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepalive := time.NewTicker(logStreamKeepalive)
+	defer keepalive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case line, ok := <-lines:
+			if !ok {
+				return false
+			}
+			if format == "text" {
+				fmt.Fprintf(w, "%s | %s\n", line.Replica, line.Line)
+			} else {
+				c.SSEvent("log", line)
+			}
+			return true
+		case <-keepalive.C:
+			if format == "sse" {
+				io.WriteString(w, ": keepalive\n\n")
+			}
+			return true
+		}
+	})
+}
+
+// parseSince 解析 since 参数：RFC3339 时间，或 10m、1h 这样的相对时长
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid since: must be an RFC3339 time or a duration such as 10m")
+	}
+	return time.Now().Add(-d), nil
+}
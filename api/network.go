@@ -0,0 +1,82 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ListNetworks 列出网络
+// @Summary 列出网络
+// @Description 列出 OneDock 创建的网络和受管服务加入的网络（如 bridge），以及加入每个网络的服务和副本（包括已停止的副本），按名称排序；all=true 时列出全部 Docker 网络
+// @Tags 网络管理
+// @Accept json
+// @Produce json
+// @Param all query bool false "列出全部 Docker 网络" example:"false"
+// @Success 200 {object} object{code=int,data=[]models.Network,msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/networks [get]
+func (api *Api) ListNetworks(c *gin.Context) {
+	ctx := requestContext(c)
+	networks, err := api.ser.ListNetworks(ctx, c.Query("all") == "true")
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "列出网络失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, networks)
+}
+
+// CreateNetwork 创建网络
+// @Summary 创建网络
+// @Description 创建带 OneDock 管理标签的 Docker 网络，默认 bridge 驱动，子网和网关不填时由 Docker 分配；同名网络已存在时返回 CONFLICT
+// @Tags 网络管理
+// @Accept json
+// @Produce json
+// @Param request body models.NetworkCreateRequest true "网络配置"
+// @Success 200 {object} object{code=int,data=models.Network,msg=string} "创建成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/networks [post]
+func (api *Api) CreateNetwork(c *gin.Context) {
+	var req models.NetworkCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "请求参数错误"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	network, err := api.ser.CreateNetwork(ctx, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Network", req.Name), log.Any("Message", "创建网络失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, network)
+}
+
+// DeleteNetwork 删除网络
+// @Summary 删除网络
+// @Description 删除 OneDock 创建的网络。不是 OneDock 创建的网络不能删除；仍有受管副本（包括已停止的副本）或其他容器加入时返回 CONFLICT
+// @Tags 网络管理
+// @Accept json
+// @Produce json
+// @Param network path string true "网络名称" example:"backend"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/networks/{network} [delete]
+func (api *Api) DeleteNetwork(c *gin.Context) {
+	name := c.Param("network")
+	ctx := requestContext(c)
+	if err := api.ser.DeleteNetwork(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Network", name), log.Any("Message", "删除网络失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "network deleted successfully"})
+}
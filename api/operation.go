@@ -0,0 +1,31 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetOperationQueue 获取服务操作队列
+// @Summary 获取服务操作队列
+// @Description 同一服务的部署、更新、回滚、扩缩容、启停、发布和副本操作逐个执行，返回正在执行和排队等待的操作。排队超过 container.operation_queue_timeout 秒的操作返回失败
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.OperationQueue,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/operations [get]
+func (api *Api) GetOperationQueue(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	queue, err := api.ser.GetOperationQueue(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取服务操作队列失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, queue)
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetOverview 获取全局运行概况
+// @Summary 获取全局运行概况
+// @Description 一次返回服务按状态统计、副本数不足的服务、未在运行的副本、端口占用、运行中的端口代理以及最近 24 小时的故障事件，适合监控面板使用
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=models.Overview,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "获取失败"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/overview [get]
+func (api *Api) GetOverview(c *gin.Context) {
+	ctx := requestContext(c)
+	overview, err := api.ser.GetOverview(ctx)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, overview)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ListHostPorts 列出使用中的主机端口
+// @Summary 列出使用中的主机端口
+// @Description 列出服务的公共端口（及端口代理是否运行）、每个副本映射到宿主机的端口（所属服务、副本编号、容器）和预留端口，按端口排序；并给出 ports.auto_range 内的空闲端口范围。空闲范围按 OneDock 登记的端口计算，不探测主机上其他进程
+// @Tags 端口管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=models.PortListing,msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/ports [get]
+func (api *Api) ListHostPorts(c *gin.Context) {
+	ctx := requestContext(c)
+	listing, err := api.ser.ListHostPorts(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "列出主机端口失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, listing)
+}
+
+// ListPortReservations 列出端口预留
+// @Summary 列出端口预留
+// @Description 列出全部预留的公共端口，预留端口不能被其他服务部署使用
+// @Tags 端口管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=[]models.PortReservation,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/ports/reservations [get]
+func (api *Api) ListPortReservations(c *gin.Context) {
+	ctx := requestContext(c)
+	reservations, err := api.ser.ListPortReservations(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "列出端口预留失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, reservations)
+}
+
+// ReservePort 预留公共端口
+// @Summary 预留公共端口
+// @Description 为将来的服务预留公共端口。指定 service 时只有该服务可以部署到此端口，首次部署后预留转为分配；系统端口和已被使用的端口不能预留
+// @Tags 端口管理
+// @Accept json
+// @Produce json
+// @Param request body models.PortReservationRequest true "预留端口"
+// @Success 200 {object} object{code=int,data=models.PortReservation,msg=string} "预留成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/ports/reservations [post]
+func (api *Api) ReservePort(c *gin.Context) {
+	var req models.PortReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "请求参数错误"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	reservation, err := api.ser.ReservePort(ctx, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("PublicPort", req.Port), log.Any("Message", "预留端口失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, reservation)
+}
+
+// DeletePortReservation 取消端口预留
+// @Summary 取消端口预留
+// @Description 取消公共端口的预留，已分配给服务的端口不受影响
+// @Tags 端口管理
+// @Accept json
+// @Produce json
+// @Param port path int true "公共端口" example:"9300"
+// @Success 200 {object} object{code=int,data=object,msg=string} "取消成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/ports/reservations/{port} [delete]
+func (api *Api) DeletePortReservation(c *gin.Context) {
+	port, err := strconv.Atoi(c.Param("port"))
+	if err != nil {
+		utils.Rfail(c, "invalid port")
+		return
+	}
+	ctx := requestContext(c)
+	if err := api.ser.DeletePortReservation(ctx, port); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("PublicPort", port), log.Any("Message", "取消端口预留失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, nil)
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// StartCanary 发起金丝雀发布
+// @Summary 发起金丝雀发布
+// @Description 按新配置额外创建 canary_replicas 个金丝雀副本，与现有副本一起由端口代理负载均衡。期望状态不变，结束前不能更新或扩缩容该服务
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param request body models.CanaryRequest true "新版本服务配置与金丝雀副本数"
+// @Success 200 {object} object{code=int,data=models.ReleaseStatus,msg=string} "发起成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/canary [post]
+func (api *Api) StartCanary(c *gin.Context) {
+	name := c.Param("name")
+	var req models.CanaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "请求参数错误"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	status, err := api.ser.StartCanary(ctx, name, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "发起金丝雀发布失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// GetCanary 获取金丝雀发布状态
+// @Summary 获取金丝雀发布状态
+// @Description 获取服务进行中的金丝雀发布，分别返回当前版本与金丝雀版本的镜像和副本数
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.ReleaseStatus,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/canary [get]
+func (api *Api) GetCanary(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	status, err := api.ser.GetCanary(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取金丝雀发布状态失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// PromoteCanary 确认金丝雀发布
+// @Summary 确认金丝雀发布
+// @Description 删除金丝雀副本，并将现有副本滚动更新为金丝雀版本的配置，记录为一次更新
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "确认成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/canary/promote [post]
+func (api *Api) PromoteCanary(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	service, err := api.ser.PromoteCanary(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "确认金丝雀发布失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
+
+// AbortCanary 中止金丝雀发布
+// @Summary 中止金丝雀发布
+// @Description 删除金丝雀副本，服务保持原配置
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=object,msg=string} "中止成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/canary/abort [post]
+func (api *Api) AbortCanary(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	if err := api.ser.AbortCanary(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "中止金丝雀发布失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "canary aborted successfully"})
+}
+
+// GetBlueGreen 获取蓝绿更新状态
+// @Summary 获取蓝绿更新状态
+// @Description 获取服务进行中的蓝绿更新（部署请求中 deployment_mode=blue_green 发起），返回新旧两组副本的镜像、副本数以及新版本是否已接收流量
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.ReleaseStatus,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/blue-green [get]
+func (api *Api) GetBlueGreen(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	status, err := api.ser.GetBlueGreen(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取蓝绿更新状态失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// PromoteBlueGreen 确认蓝绿更新
+// @Summary 确认蓝绿更新
+// @Description 端口代理切换到新版本副本，然后删除旧版本副本并更新期望状态，记录为一次更新。旧副本删除失败时可再次调用
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "切换成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/blue-green/promote [post]
+func (api *Api) PromoteBlueGreen(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	service, err := api.ser.PromoteBlueGreen(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "确认蓝绿更新失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
+
+// AbortBlueGreen 中止蓝绿更新
+// @Summary 中止蓝绿更新
+// @Description 删除尚未接收流量的新版本副本，旧版本副本继续提供服务
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=object,msg=string} "中止成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/blue-green/abort [post]
+func (api *Api) AbortBlueGreen(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	if err := api.ser.AbortBlueGreen(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "中止蓝绿更新失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "blue-green update aborted successfully"})
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RenameService 重命名服务
+// @Summary 重命名服务
+// @Description 按新名称逐个替换副本容器（容器名称和标签），公共端口与端口代理保持不变，期望状态、部署历史、扩缩容规则、锁和端口登记迁移到新名称。已停止、正在滚动更新或发布中的服务不能改名，任一副本失败时恢复旧名称
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param request body models.RenameRequest true "新名称"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "重命名成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/rename [post]
+func (api *Api) RenameService(c *gin.Context) {
+	name := c.Param("name")
+	var req models.RenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "请求参数错误"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	service, err := api.ser.RenameService(ctx, name, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("NewName", req.Name), log.Any("Message", "重命名服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetReplica 获取副本详情
+// @Summary 获取副本详情
+// @Description 获取服务单个副本的检查详情（docker inspect）：环境变量、挂载、网络、运行状态、重启次数和健康检查记录，比服务状态中的实例摘要更完整。期望状态中引用密钥的环境变量显示为 secret://<名称>，不返回明文
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param index path int true "副本编号" example:"0"
+// @Success 200 {object} object{code=int,data=models.ReplicaDetail,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "副本不存在"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/replicas/{index} [get]
+func (api *Api) GetReplica(c *gin.Context) {
+	name := c.Param("name")
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		utils.Rfail(c, "invalid replica index")
+		return
+	}
+	ctx := requestContext(c)
+	replica, err := api.ser.GetReplica(ctx, name, index)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Replica", index), log.Any("Message", "获取副本详情失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, replica)
+}
+
+// GetServiceEnvironment 获取服务实际生效的环境变量
+// @Summary 获取服务实际生效的环境变量
+// @Description 返回副本实际运行时的环境变量：环境变量文件（在 OneDock 所在主机上读取）与 environment 合并、占位符替换后的结果，并标注每个变量来自 env_file、environment 还是镜像。
+// @Description 引用密钥的变量显示为 secret://<名称>，不返回明文。副本创建后修改了配置或环境变量文件时，不同的变量带 differs 和按当前配置将使用的 expected 值，重建副本后生效。服务没有副本时只返回配置中的变量
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param replica query int false "副本编号，不填则使用编号最小的副本" example:"0"
+// @Success 200 {object} object{code=int,data=models.ServiceEnvironment,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "服务或副本不存在"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/env [get]
+func (api *Api) GetServiceEnvironment(c *gin.Context) {
+	name := c.Param("name")
+	index := -1
+	if value := c.Query("replica"); value != "" {
+		var err error
+		if index, err = strconv.Atoi(value); err != nil || index < 0 {
+			utils.Rfail(c, "invalid replica index")
+			return
+		}
+	}
+	ctx := requestContext(c)
+	env, err := api.ser.GetServiceEnvironment(ctx, name, index)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取服务环境变量失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, env)
+}
+
+// RestartReplica 重启单个副本
+// @Summary 重启单个副本
+// @Description 按副本编号重启服务的一个副本（连同边车容器），其余副本继续提供服务，重启后刷新端口代理。服务已停止、正在滚动更新或金丝雀/蓝绿发布期间拒绝操作
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param index path int true "副本编号" example:"0"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "重启成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "副本不存在或服务状态不允许重启"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/replicas/{index}/restart [post]
+func (api *Api) RestartReplica(c *gin.Context) {
+	name := c.Param("name")
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		utils.Rfail(c, "invalid replica index")
+		return
+	}
+	ctx := requestContext(c)
+	service, err := api.ser.RestartReplica(ctx, name, index)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Replica", index), log.Any("Message", "重启副本失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
+
+// RecreateReplica 重建单个副本
+// @Summary 重建单个副本
+// @Description 删除服务的一个副本并按期望状态重新创建，副本编号不变，用于处理卡在异常状态的副本。默认先删除旧副本再创建；surge=true 时先创建并验证新副本再删除旧副本，期间不减少可用副本。服务已停止、正在滚动更新或金丝雀/蓝绿发布期间拒绝操作
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param index path int true "副本编号" example:"0"
+// @Param surge query bool false "先创建新副本再删除旧副本" example:"false"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "重建成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "副本不存在、服务状态不允许重建或新副本启动失败"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/replicas/{index}/recreate [post]
+func (api *Api) RecreateReplica(c *gin.Context) {
+	name := c.Param("name")
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		utils.Rfail(c, "invalid replica index")
+		return
+	}
+	ctx := requestContext(c)
+	service, err := api.ser.RecreateReplica(ctx, name, index, c.Query("surge") == "true")
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Replica", index), log.Any("Message", "重建副本失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
+
+// DisableReplica 停用副本的代理后端
+// @Summary 停用副本的代理后端
+// @Description 将副本从端口代理的负载均衡中摘除，容器继续运行，便于在没有流量的情况下调试单个实例；正在处理的请求不受影响。停用状态在扩缩容、更新重建代理后保留，副本重建、服务删除或 OneDock 重启后恢复。全部副本停用时代理返回 503
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param index path int true "副本编号" example:"0"
+// @Success 200 {object} object{code=int,data=models.ServiceProxyStats,msg=string} "停用成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "副本不存在或服务没有公共端口"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/replicas/{index}/disable [post]
+func (api *Api) DisableReplica(c *gin.Context) {
+	api.setReplicaActive(c, false)
+}
+
+// EnableReplica 启用副本的代理后端
+// @Summary 启用副本的代理后端
+// @Description 恢复停用的副本，重新参与端口代理的负载均衡
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param index path int true "副本编号" example:"0"
+// @Success 200 {object} object{code=int,data=models.ServiceProxyStats,msg=string} "启用成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "副本不存在或服务没有公共端口"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/replicas/{index}/enable [post]
+func (api *Api) EnableReplica(c *gin.Context) {
+	api.setReplicaActive(c, true)
+}
+
+// setReplicaActive 停用或启用副本的代理后端
+func (api *Api) setReplicaActive(c *gin.Context, active bool) {
+	name := c.Param("name")
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		utils.Rfail(c, "invalid replica index")
+		return
+	}
+	ctx := requestContext(c)
+	stats, err := api.ser.PortManager.SetReplicaActive(ctx, name, index, active)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Replica", index), log.Any("Active", active), log.Any("Message", "切换副本后端状态失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, stats)
+}
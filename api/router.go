@@ -1,26 +1,186 @@
 package api
 
 import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/validate"
 	"github.com/aichy126/onedock/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func Router(r *gin.Engine) {
+// Router 注册路由，返回的 Api 用于进程退出时关闭服务
+func Router(r *gin.Engine) *Api {
+	// 只信任配置的代理转发的 X-Forwarded-For，限流、审计日志和幂等记录中的客户端 IP 都由此得出
+	if err := r.SetTrustedProxies(middleware.TrustedProxies()); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "可信代理配置无效，不信任任何代理"))
+		r.SetTrustedProxies(nil)
+	}
 	r.Use(middleware.Cors())
+	r.Use(middleware.RequestLog()) // 分配请求 ID 并记录每个请求的结果和耗时
+	validate.Register()
 	api := NewApi()
 
 	// ping 接口不需要权限验证（健康检查）
-	r.GET("/onedock/ping", api.Ping)
-	r.POST("/onedock/ping", api.Ping)
-
-	// 需要权限验证的服务接口
-	services := r.Group("/onedock")
-	services.Use(middleware.Auth()) // 应用权限验证中间件
-	services.POST("/", api.DeployOrUpdateService)       // 部署或更新服务
-	services.GET("/", api.ListServices)                 // 列出所有服务
-	services.GET("/:name", api.GetService)              // 获取服务
-	services.DELETE("/:name", api.DeleteService)        // 删除服务
-	services.GET("/:name/status", api.GetServiceStatus) // 获取服务状态
-	services.POST("/:name/scale", api.ScaleService)     // 服务扩缩容
-	services.GET("/proxy/stats", api.GetProxyStats)     // 获取代理统计信息
+	for _, path := range []string{"/onedock/ping", "/v1/onedock/ping"} {
+		r.GET(path, api.Ping)
+		r.POST(path, api.Ping)
+	}
+
+	// OIDC 登录入口和回调不需要权限验证
+	for _, prefix := range []string{"/onedock", "/v1/onedock"} {
+		r.GET(prefix+"/auth/login", api.OIDCLogin)
+		r.GET(prefix+"/auth/callback", api.OIDCCallback)
+		r.POST(prefix+"/auth/logout", api.Logout)
+	}
+
+	// 存活与就绪检查不需要权限验证，供负载均衡和进程管理器探测
+	r.GET("/healthz", api.Healthz)
+	r.GET("/readyz", api.Readyz)
+
+	// 需要权限验证的服务接口，/onedock 为 /v1/onedock 的兼容别名
+	// 今后不兼容的响应调整通过新的版本路径（如 /v2/onedock）提供，已有路径保持不变
+	limiter := middleware.RateLimit(api.ser.LookupToken) // 两组路径共享计数
+	idem := middleware.Idempotency(api.ser.Cache)        // 两组路径共享幂等记录
+	for _, prefix := range []string{"/onedock", "/v1/onedock"} {
+		services := r.Group(prefix)
+		services.Use(middleware.Audit(api.ser.RecordAudit)) // 记录修改类请求的审计日志（含权限验证失败的请求）
+		services.Use(limiter)                               // 按客户端 IP 和令牌限流
+		services.Use(middleware.Auth(api.ser.LookupToken))  // 应用权限验证中间件
+		services.Use(middleware.Timeout())                  // 按接口设置请求超时，超时或客户端断开时取消 Docker 操作
+		registerServiceRoutes(services, api, idem)
+	}
+
+	return api
+}
+
+// registerServiceRoutes 注册服务管理接口，同时挂载在 /onedock 和 /v1/onedock 下
+// idem 为幂等键中间件，部署、扩缩容和删除接口携带 Idempotency-Key 时重复请求返回第一次的结果
+func registerServiceRoutes(services *gin.RouterGroup, api *Api, idem gin.HandlerFunc) {
+	services.POST("/", idem, api.DeployOrUpdateService)                     // 部署或更新服务
+	services.POST("/batch", idem, api.DeployBatch)                          // 批量部署服务
+	services.POST("/scale", idem, api.ScaleServices)                        // 批量扩缩容
+	services.POST("/delete", idem, api.DeleteServices)                      // 批量删除服务
+	services.POST("/validate", api.ValidateService)                         // 静态校验服务配置，不部署
+	services.GET("/", api.ListServices)                                     // 列出所有服务
+	services.GET("/search", api.SearchServices)                             // 按标签、镜像、状态和端口搜索服务
+	services.GET("/namespaces", api.ListNamespaces)                         // 列出命名空间
+	services.GET("/quotas", api.ListQuotas)                                 // 列出配额及使用量
+	services.GET("/trash", api.ListTrash)                                   // 列出回收站中的服务
+	services.POST("/trash/:name/restore", idem, api.RestoreService)         // 从回收站恢复服务
+	services.DELETE("/trash/:name", api.PurgeTrashedService)                // 从回收站中彻底删除服务
+	services.GET("/:name", api.GetService)                                  // 获取服务
+	services.DELETE("/:name", idem, api.DeleteService)                      // 删除服务
+	services.GET("/:name/status", api.GetServiceStatus)                     // 获取服务状态
+	services.GET("/:name/stats", api.GetServiceStats)                       // 采样服务每个副本的资源使用情况
+	services.GET("/:name/proxy", api.GetServiceProxyStats)                  // 获取服务的端口代理统计信息
+	services.PUT("/:name/proxy/strategy", api.SetServiceProxyStrategy)      // 运行时切换服务的负载均衡策略
+	services.GET("/:name/logs/stream", api.StreamServiceLogs)               // 实时跟踪服务日志
+	services.GET("/:name/env", api.GetServiceEnvironment)                   // 获取副本实际生效的环境变量
+	services.GET("/:name/replicas/:index", api.GetReplica)                  // 获取副本详情
+	services.POST("/:name/replicas/:index/restart", api.RestartReplica)     // 重启单个副本
+	services.POST("/:name/replicas/:index/recreate", api.RecreateReplica)   // 删除并重建单个副本
+	services.POST("/:name/replicas/:index/disable", api.DisableReplica)     // 将副本从负载均衡中摘除
+	services.POST("/:name/replicas/:index/enable", api.EnableReplica)       // 恢复副本参与负载均衡
+	services.POST("/:name/scale", idem, api.ScaleService)                   // 服务扩缩容
+	services.GET("/:name/schedules", api.ListScaleSchedules)                // 列出定时扩缩容规则
+	services.POST("/:name/schedules", api.AddScaleSchedule)                 // 添加定时扩缩容规则
+	services.DELETE("/:name/schedules/:id", api.DeleteScaleSchedule)        // 删除定时扩缩容规则
+	services.GET("/:name/autoscale", api.GetAutoscaleStatus)                // 获取自动扩缩容状态
+	services.PUT("/:name/autoscale", api.SetAutoscalePolicy)                // 设置自动扩缩容策略
+	services.DELETE("/:name/autoscale", api.DeleteAutoscalePolicy)          // 删除自动扩缩容策略
+	services.GET("/:name/image-watch", api.GetImageWatch)                   // 获取镜像更新监视状态
+	services.PUT("/:name/image-watch", api.SetImageWatch)                   // 设置镜像更新监视
+	services.DELETE("/:name/image-watch", api.DeleteImageWatch)             // 删除镜像更新监视
+	services.POST("/:name/image-watch/check", api.CheckImageWatch)          // 立即检查镜像更新
+	services.POST("/:name/stop", api.StopService)                           // 停止服务（保留容器）
+	services.POST("/:name/start", api.StartService)                         // 启动已停止的服务
+	services.GET("/:name/spec", api.GetServiceSpec)                         // 获取服务期望状态
+	services.GET("/:name/export", api.ExportService)                        // 导出服务配置（compose / spec）
+	services.GET("/:name/drift", api.GetServiceDrift)                       // 获取服务漂移报告
+	services.POST("/:name/diff", api.DiffService)                           // 比较服务配置差异
+	services.GET("/:name/history", api.GetServiceHistory)                   // 获取部署历史
+	services.POST("/:name/rollback", api.RollbackService)                   // 回滚到历史版本
+	services.GET("/:name/rollout", api.GetRollout)                          // 获取滚动更新状态
+	services.POST("/:name/rollout/pause", api.PauseRollout)                 // 暂停滚动更新
+	services.POST("/:name/rollout/resume", api.ResumeRollout)               // 恢复滚动更新
+	services.POST("/:name/rollout/abort", api.AbortRollout)                 // 中止滚动更新
+	services.GET("/:name/operations", api.GetOperationQueue)                // 获取正在执行和排队的操作
+	services.GET("/:name/events", api.ListServiceEvents)                    // 获取服务最近的生命周期事件
+	services.POST("/:name/canary", api.StartCanary)                         // 发起金丝雀发布
+	services.GET("/:name/canary", api.GetCanary)                            // 获取金丝雀发布状态
+	services.POST("/:name/canary/promote", api.PromoteCanary)               // 确认金丝雀发布
+	services.POST("/:name/canary/abort", api.AbortCanary)                   // 中止金丝雀发布
+	services.GET("/:name/blue-green", api.GetBlueGreen)                     // 获取蓝绿更新状态
+	services.POST("/:name/blue-green/promote", api.PromoteBlueGreen)        // 确认蓝绿更新，切换流量
+	services.POST("/:name/blue-green/abort", api.AbortBlueGreen)            // 中止蓝绿更新
+	services.POST("/:name/lock", api.LockService)                           // 锁定服务，禁止非管理员修改
+	services.POST("/:name/unlock", api.UnlockService)                       // 解除服务锁定
+	services.POST("/:name/rename", api.RenameService)                       // 重命名服务，公共端口不变
+	services.POST("/:name/clone", api.CloneService)                         // 克隆服务配置为新服务
+	services.GET("/templates", api.ListTemplates)                           // 列出服务模板
+	services.POST("/templates", api.SaveTemplate)                           // 创建或更新服务模板
+	services.GET("/templates/:template", api.GetTemplate)                   // 获取服务模板
+	services.DELETE("/templates/:template", api.DeleteTemplate)             // 删除服务模板
+	services.POST("/templates/:template/deploy", api.DeployFromTemplate)    // 从模板部署服务
+	services.POST("/jobs", api.RunJob)                                      // 提交一次性任务
+	services.GET("/deployments", api.ListDeployments)                       // 列出异步部署
+	services.GET("/deployments/:id", api.GetDeployment)                     // 获取异步部署进度
+	services.GET("/jobs", api.ListJobs)                                     // 列出任务
+	services.GET("/jobs/:job", api.GetJob)                                  // 获取任务状态与日志
+	services.POST("/jobs/:job/cancel", api.CancelJob)                       // 取消任务
+	services.DELETE("/jobs/:job", api.DeleteJob)                            // 删除任务记录
+	services.GET("/cronjobs", api.ListCronJobs)                             // 列出定时任务
+	services.POST("/cronjobs", api.SaveCronJob)                             // 创建或更新定时任务
+	services.GET("/cronjobs/:cronjob", api.GetCronJob)                      // 获取定时任务及运行记录
+	services.POST("/cronjobs/:cronjob/run", api.RunCronJob)                 // 立即触发定时任务
+	services.DELETE("/cronjobs/:cronjob", api.DeleteCronJob)                // 删除定时任务
+	services.GET("/ports", api.ListHostPorts)                               // 列出使用中的主机端口和空闲范围
+	services.GET("/ports/reservations", api.ListPortReservations)           // 列出端口预留
+	services.POST("/ports/reservations", api.ReservePort)                   // 预留公共端口
+	services.DELETE("/ports/reservations/:port", api.DeletePortReservation) // 取消端口预留
+	services.GET("/volumes", api.ListVolumes)                               // 列出服务使用的卷和主机目录
+	services.GET("/volumes/:volume", api.GetVolume)                         // 获取命名卷
+	services.DELETE("/volumes/:volume", api.DeleteVolume)                   // 删除未被使用的命名卷
+	services.GET("/networks", api.ListNetworks)                             // 列出网络及加入的服务
+	services.POST("/networks", api.CreateNetwork)                           // 创建网络
+	services.DELETE("/networks/:network", api.DeleteNetwork)                // 删除 OneDock 创建的网络
+	services.GET("/secrets", api.ListSecrets)                               // 列出密钥
+	services.PUT("/secrets/:secret", api.SaveSecret)                        // 创建或更新密钥
+	services.DELETE("/secrets/:secret", api.DeleteSecret)                   // 删除密钥
+	services.POST("/reconcile", api.Reconcile)                              // 立即执行一轮调和
+	services.GET("/audit", api.ListAuditLogs)                               // 查询审计日志
+	services.GET("/events", api.ListEvents)                                 // 查询服务生命周期事件
+	services.GET("/events/stream", api.StreamEvents)                        // 实时推送服务事件（SSE）
+	services.GET("/webhooks", api.ListWebhooks)                             // 列出事件订阅
+	services.POST("/webhooks", api.SaveWebhook)                             // 创建或更新事件订阅
+	services.GET("/webhooks/:webhook", api.GetWebhook)                      // 获取事件订阅及最近投递结果
+	services.DELETE("/webhooks/:webhook", api.DeleteWebhook)                // 删除事件订阅
+	services.GET("/overview", api.GetOverview)                              // 获取全局运行概况
+	services.GET("/graphql", api.QueryGraphQL)                              // 只读 GraphQL 查询（GET）
+	services.POST("/graphql", api.QueryGraphQL)                             // 只读 GraphQL 查询服务拓扑与代理统计
+	services.GET("/gitops", api.GetGitOpsStatus)                            // 获取 GitOps 同步状态
+	services.POST("/gitops/sync", api.SyncGitOps)                           // 立即执行 GitOps 同步
+	services.GET("/system/status", api.GetSystemStatus)                     // 获取系统状态
+	services.GET("/system/df", api.GetDiskUsage)                            // 获取 Docker 磁盘占用详情
+	services.GET("/system/config", api.GetRuntimeConfig)                    // 获取运行时配置
+	services.GET("/version", api.GetVersion)                                // 获取版本与构建信息
+	services.GET("/auth/whoami", api.WhoAmI)                                // 获取当前身份
+	services.POST("/system/cleanup", api.CleanupOrphans)                    // 清理孤立容器
+	services.POST("/system/restore", api.RestoreBackup)                     // 在新主机上恢复全量备份
+	services.GET("/proxy/stats", api.GetProxyStats)                         // 获取代理统计信息
+	services.POST("/proxy/reload", api.ReloadProxyConfig)                   // 热加载代理配置
+
+	// 终端可在容器内执行命令、备份包含密钥，虽为 GET 请求也不对只读令牌开放
+	deployer := middleware.RequireRole(middleware.RoleDeployer)
+	services.GET("/:name/replicas/:index/terminal", deployer, api.ReplicaTerminal) // 副本 Web 终端（WebSocket）
+	services.GET("/system/backup", deployer, api.CreateBackup)                     // 下载全量备份
+
+	// 运行时配置和令牌管理仅对管理员令牌开放
+	admin := middleware.RequireRole(middleware.RoleAdmin)
+	services.PUT("/system/config", admin, api.UpdateRuntimeConfig) // 修改运行时配置并热加载
+
+	tokens := services.Group("/tokens", admin)
+	tokens.POST("", api.CreateAPIToken)            // 创建访问令牌
+	tokens.GET("", api.ListAPITokens)              // 列出访问令牌
+	tokens.POST("/:id/revoke", api.RevokeAPIToken) // 吊销访问令牌
+	tokens.POST("/:id/expire", api.ExpireAPIToken) // 设置访问令牌过期时间
 }
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AddScaleSchedule 添加定时扩缩容规则
+// @Summary 添加定时扩缩容规则
+// @Description 到达 cron 表达式（分 时 日 月 周，服务器本地时区）指定的时间时将服务调整到指定副本数，例如工作日 09:00 扩到 5 个副本、22:00 缩到 1 个副本。已停止的服务不执行，服务删除时规则一并删除
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param schedule body models.ScaleScheduleRequest true "定时扩缩容规则"
+// @Success 200 {object} object{code=int,data=models.ScaleSchedule,msg=string} "添加成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/schedules [post]
+func (api *Api) AddScaleSchedule(c *gin.Context) {
+	name := c.Param("name")
+	var req models.ScaleScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	schedule, err := api.ser.AddScaleSchedule(ctx, name, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "添加定时扩缩容规则失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, schedule)
+}
+
+// ListScaleSchedules 列出定时扩缩容规则
+// @Summary 列出定时扩缩容规则
+// @Description 获取服务的定时扩缩容规则及下次、上次执行时间
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=object{Schedules=[]models.ScaleSchedule,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/schedules [get]
+func (api *Api) ListScaleSchedules(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	schedules, err := api.ser.ListScaleSchedules(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "获取定时扩缩容规则失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Schedules": schedules,
+		"Total":     len(schedules),
+	})
+}
+
+// DeleteScaleSchedule 删除定时扩缩容规则
+// @Summary 删除定时扩缩容规则
+// @Description 删除服务的指定定时扩缩容规则，不影响当前副本数
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param id path int true "规则 ID" example:"1"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/schedules/{id} [delete]
+func (api *Api) DeleteScaleSchedule(c *gin.Context) {
+	name := c.Param("name")
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Rfail(c, "invalid schedule id")
+		return
+	}
+	ctx := requestContext(c)
+	if err := api.ser.DeleteScaleSchedule(ctx, name, id); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("ScheduleID", id), log.Any("Message", "删除定时扩缩容规则失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, nil)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SaveSecret 创建或更新密钥
+// @Summary 创建或更新密钥
+// @Description 加密保存密钥（AES-GCM，密钥由 secret.key 配置派生），服务环境变量写成 secret://<名称> 即可在创建容器时注入。更新后已运行的副本在重新创建时才使用新值
+// @Tags 密钥管理
+// @Accept json
+// @Produce json
+// @Param secret path string true "密钥名称" example:"db_password"
+// @Param request body models.SecretRequest true "密钥值"
+// @Success 200 {object} object{code=int,data=models.Secret,msg=string} "保存成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/secrets/{secret} [put]
+func (api *Api) SaveSecret(c *gin.Context) {
+	name := c.Param("secret")
+	var req models.SecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	secret, err := api.ser.SaveSecret(ctx, name, req.Value)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Secret", name), log.Any("Message", "保存密钥失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, secret)
+}
+
+// ListSecrets 列出密钥
+// @Summary 列出密钥
+// @Description 获取全部密钥名称及引用它们的服务，不返回密钥值
+// @Tags 密钥管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=object{Secrets=[]models.Secret,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/secrets [get]
+func (api *Api) ListSecrets(c *gin.Context) {
+	ctx := requestContext(c)
+	secrets, err := api.ser.ListSecrets(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "获取密钥列表失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Secrets": secrets,
+		"Total":   len(secrets),
+	})
+}
+
+// DeleteSecret 删除密钥
+// @Summary 删除密钥
+// @Description 删除指定密钥，仍被服务引用时拒绝删除
+// @Tags 密钥管理
+// @Accept json
+// @Produce json
+// @Param secret path string true "密钥名称" example:"db_password"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/secrets/{secret} [delete]
+func (api *Api) DeleteSecret(c *gin.Context) {
+	name := c.Param("secret")
+	ctx := requestContext(c)
+	if err := api.ser.DeleteSecret(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Secret", name), log.Any("Message", "删除密钥失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "secret deleted successfully"})
+}
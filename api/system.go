@@ -0,0 +1,105 @@
+package api
+
+import (
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetSystemStatus 获取系统状态
+// @Summary 获取系统状态
+// @Description 返回 Docker 守护进程是否可达及其版本、镜像/容器/卷的磁盘占用、受管公共端口使用情况和 OneDock 进程运行时长。Docker 不可达时仍返回成功，原因见 docker.error
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=models.SystemStatus,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "获取失败"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/system/status [get]
+func (api *Api) GetSystemStatus(c *gin.Context) {
+	ctx := requestContext(c)
+	status, err := api.ser.GetSystemStatus(ctx)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, status)
+}
+
+// GetDiskUsage 获取 Docker 磁盘占用详情
+// @Summary 获取 Docker 磁盘占用详情
+// @Description 与 docker system df -v 相当：按镜像、容器、卷和构建缓存汇总占用与可回收空间，并列出每个镜像、容器和命名卷的占用（按占用从大到小排序），标出所属的受管服务。OneDock 能访问 Docker 数据目录时还返回所在文件系统的容量和可用空间。统计需要遍历镜像层和卷，镜像和卷较多时可能需要数秒
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=models.SystemDiskUsage,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "获取失败"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/system/df [get]
+func (api *Api) GetDiskUsage(c *gin.Context) {
+	ctx := requestContext(c)
+	usage, err := api.ser.GetDiskUsage(ctx)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, usage)
+}
+
+// GetRuntimeConfig 获取运行时配置
+// @Summary 获取运行时配置
+// @Description 返回可在运行时修改的配置的当前值：端口映射缓存时间、默认负载均衡策略、公共端口范围、端口代理超时和日志级别，以及修改写回的配置文件
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=models.RuntimeConfig,msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/system/config [get]
+func (api *Api) GetRuntimeConfig(c *gin.Context) {
+	utils.Rsucc(c, api.ser.GetRuntimeConfig())
+}
+
+// UpdateRuntimeConfig 修改运行时配置
+// @Summary 修改运行时配置
+// @Description 只修改提交的字段，写回配置文件（保留注释和其他配置）后立即生效，无需重启：默认负载均衡策略和代理超时应用到运行中的端口代理（通过接口为服务单独设置的策略不受影响），日志级别立即切换，缓存时间和端口范围在下次使用时生效。仅对 admin 角色的令牌开放
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Param request body models.RuntimeConfigUpdate true "要修改的配置"
+// @Success 200 {object} object{code=int,data=models.RuntimeConfig,msg=string} "修改成功，返回修改后的配置"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误或配置文件写入失败"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/system/config [put]
+func (api *Api) UpdateRuntimeConfig(c *gin.Context) {
+	var req models.RuntimeConfigUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	config, err := api.ser.UpdateRuntimeConfig(ctx, &req)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, config)
+}
+
+// GetVersion 获取版本信息
+// @Summary 获取版本信息
+// @Description 返回 OneDock 版本、git 提交、构建时间（构建时通过 ldflags 注入）、Go 版本，以及与 Docker 守护进程协商后使用的 API 版本。Docker 不可达时仍返回成功，原因见 docker_error
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=models.VersionInfo,msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/version [get]
+func (api *Api) GetVersion(c *gin.Context) {
+	ctx := requestContext(c)
+	utils.Rsucc(c, api.ser.GetVersion(ctx))
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SaveTemplate 创建或更新服务模板
+// @Summary 创建或更新服务模板
+// @Description 保存带 ${VAR} 占位符的服务配置模板，同名模板已存在时覆盖。${VAR:-默认值} 表示可选变量
+// @Tags 服务模板
+// @Accept json
+// @Produce json
+// @Param template body models.ServiceTemplate true "模板"
+// @Success 200 {object} object{code=int,data=models.ServiceTemplate,msg=string} "保存成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/templates [post]
+func (api *Api) SaveTemplate(c *gin.Context) {
+	var req models.ServiceTemplate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	template, err := api.ser.SaveTemplate(ctx, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Template", req.Name), log.Any("Message", "保存服务模板失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, template)
+}
+
+// ListTemplates 列出服务模板
+// @Summary 列出服务模板
+// @Description 获取全部服务模板及其使用的变量
+// @Tags 服务模板
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=object{Templates=[]models.ServiceTemplate,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/templates [get]
+func (api *Api) ListTemplates(c *gin.Context) {
+	ctx := requestContext(c)
+	templates, err := api.ser.ListTemplates(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "获取服务模板失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Templates": templates,
+		"Total":     len(templates),
+	})
+}
+
+// GetTemplate 获取服务模板
+// @Summary 获取服务模板
+// @Description 获取指定服务模板
+// @Tags 服务模板
+// @Accept json
+// @Produce json
+// @Param template path string true "模板名称" example:"web-app"
+// @Success 200 {object} object{code=int,data=models.ServiceTemplate,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/templates/{template} [get]
+func (api *Api) GetTemplate(c *gin.Context) {
+	name := c.Param("template")
+	ctx := requestContext(c)
+	template, err := api.ser.GetTemplate(ctx, name)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, template)
+}
+
+// DeleteTemplate 删除服务模板
+// @Summary 删除服务模板
+// @Description 删除指定服务模板，已部署的服务不受影响
+// @Tags 服务模板
+// @Accept json
+// @Produce json
+// @Param template path string true "模板名称" example:"web-app"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/templates/{template} [delete]
+func (api *Api) DeleteTemplate(c *gin.Context) {
+	name := c.Param("template")
+	ctx := requestContext(c)
+	if err := api.ser.DeleteTemplate(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Template", name), log.Any("Message", "删除服务模板失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "template deleted successfully"})
+}
+
+// DeployFromTemplate 从模板部署服务
+// @Summary 从模板部署服务
+// @Description 用变量值渲染模板生成服务配置并部署，服务已存在时执行滚动更新。缺少没有默认值的变量时返回错误
+// @Tags 服务模板
+// @Accept json
+// @Produce json
+// @Param template path string true "模板名称" example:"web-app"
+// @Param request body models.TemplateDeployRequest true "变量值"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "部署成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 500 {object} object{code=int,msg=string,data=object} "服务器内部错误"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/templates/{template}/deploy [post]
+func (api *Api) DeployFromTemplate(c *gin.Context) {
+	name := c.Param("template")
+	var req models.TemplateDeployRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	service, err := api.ser.DeployFromTemplate(ctx, name, req.Variables)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Template", name), log.Any("Message", "从模板部署服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// terminalMessage 终端的文本控制消息
+// 客户端发送 input（输入）、resize（调整大小），服务端在命令结束时发送 exit
+type terminalMessage struct {
+	Type string `json:"type"`
+	Data string `json:"data,omitempty"`
+	Rows uint   `json:"rows,omitempty"`
+	Cols uint   `json:"cols,omitempty"`
+	Code *int   `json:"code,omitempty"`
+}
+
+// terminalFrame 收到的一帧及其类型
+type terminalFrame struct {
+	payloadType byte
+	data        []byte
+}
+
+// terminalCodec 保留帧类型：二进制帧为原始输入，文本帧为控制消息
+var terminalCodec = websocket.Codec{
+	Marshal: func(v interface{}) ([]byte, byte, error) {
+		if data, ok := v.([]byte); ok {
+			return data, websocket.BinaryFrame, nil
+		}
+		data, err := json.Marshal(v)
+		return data, websocket.TextFrame, err
+	},
+	Unmarshal: func(data []byte, payloadType byte, v interface{}) error {
+		frame := v.(*terminalFrame)
+		frame.payloadType = payloadType
+		frame.data = data
+		return nil
+	},
+}
+
+// terminalOriginAllowed 校验浏览器请求的来源：没有 Origin（非浏览器客户端）、与 API 同源或在 terminal.allowed_origins 中时允许
+func terminalOriginAllowed(origin, host string, allowed []string) bool {
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if u.Host == host {
+		return true
+	}
+	for _, item := range allowed {
+		if item == "*" || item == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplicaTerminal 副本 Web 终端
+// @Summary 副本 Web 终端
+// @Description 通过 WebSocket 连接到副本中的交互式命令（docker exec -it）。服务端以二进制帧发送终端输出；客户端以二进制帧发送原始输入，或以文本帧发送 JSON 控制消息：{"type":"input","data":"ls\r"}、{"type":"resize","rows":40,"cols":120}。命令结束时服务端发送 {"type":"exit","code":0} 后关闭连接。浏览器无法设置请求头，可使用 ?token= 进行权限验证；跨域页面需要加入 terminal.allowed_origins
+// @Tags 服务管理
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Param index path int true "副本编号" example:"0"
+// @Param cmd query []string false "命令及参数（可重复），默认 terminal.command" collectionFormat(multi)
+// @Param rows query int false "初始行数" example:"40"
+// @Param cols query int false "初始列数" example:"120"
+// @Success 101 {string} string "切换到 WebSocket"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "副本不存在、未运行或终端已禁用"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/{name}/replicas/{index}/terminal [get]
+func (api *Api) ReplicaTerminal(c *gin.Context) {
+	name := c.Param("name")
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		utils.Rfail(c, "invalid replica index")
+		return
+	}
+	if !terminalOriginAllowed(c.GetHeader("Origin"), c.Request.Host, igo.App.Conf.GetStringSlice("terminal.allowed_origins")) {
+		utils.RfailCode(c, utils.CodeForbidden, "origin is not allowed, add it to terminal.allowed_origins", nil)
+		return
+	}
+	rows := uint(utils.StringToInt(c.Query("rows")))
+	cols := uint(utils.StringToInt(c.Query("cols")))
+
+	// 升级为 WebSocket 前启动命令，失败时返回普通的错误响应
+	ctx := requestContext(c)
+	session, err := api.ser.OpenTerminal(ctx, name, index, c.QueryArray("cmd"), rows, cols)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	defer session.Close()
+
+	server := websocket.Server{
+		// 来源已在上面校验
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			// 输出：命令结束时发送退出码并关闭连接
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				buf := make([]byte, 32<<10)
+				for {
+					n, err := session.Reader.Read(buf)
+					if n > 0 {
+						if terminalCodec.Send(ws, buf[:n]) != nil {
+							return
+						}
+					}
+					if err != nil {
+						break
+					}
+				}
+				if code, err := api.ser.TerminalExitCode(ctx, session); err == nil {
+					terminalCodec.Send(ws, &terminalMessage{Type: "exit", Code: &code})
+				}
+				ws.Close()
+			}()
+
+			// 输入：连接断开时结束命令的输入
+			for {
+				var frame terminalFrame
+				if err := terminalCodec.Receive(ws, &frame); err != nil {
+					break
+				}
+				if frame.payloadType == websocket.BinaryFrame {
+					session.Conn.Write(frame.data)
+					continue
+				}
+				var msg terminalMessage
+				if err := json.Unmarshal(frame.data, &msg); err != nil {
+					// 不是 JSON 的文本帧视为原始输入，兼容简单的命令行客户端
+					session.Conn.Write(frame.data)
+					continue
+				}
+				switch msg.Type {
+				case "input":
+					io.WriteString(session.Conn, msg.Data)
+				case "resize":
+					if msg.Rows > 0 && msg.Cols > 0 {
+						if err := api.ser.ResizeTerminal(ctx, session, msg.Rows, msg.Cols); err != nil {
+							log.Warn("Terminal", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "调整终端大小失败"))
+						}
+					}
+				}
+			}
+			session.Close()
+			<-done
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
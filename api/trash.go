@@ -0,0 +1,79 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ListTrash 列出回收站中的服务
+// @Summary 列出回收站中的服务
+// @Description 列出以 trash=true 删除、仍在保留期内的服务，最近删除的在前，保留期（trash.retention_days）过后自动清除
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=object{Services=[]models.TrashedService,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/trash [get]
+func (api *Api) ListTrash(c *gin.Context) {
+	ctx := requestContext(c)
+	services, err := api.ser.ListTrash(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "获取回收站失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Services": services,
+		"Total":    len(services),
+	})
+}
+
+// RestoreService 从回收站恢复服务
+// @Summary 从回收站恢复服务
+// @Description 按删除时的配置、副本数和公共端口恢复服务。删除时保留了容器的，改回原名称并启动；否则重新部署。同名服务已存在时返回 CONFLICT
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=models.Service,msg=string} "恢复成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 404 {object} object{code=int,msg=string,data=object} "服务不在回收站中"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/trash/{name}/restore [post]
+func (api *Api) RestoreService(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	service, err := api.ser.RestoreService(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "从回收站恢复服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, service)
+}
+
+// PurgeTrashedService 从回收站中彻底删除服务
+// @Summary 从回收站中彻底删除服务
+// @Description 不等保留期结束，立即删除保留的容器和保存的配置，并释放为服务保留的公共端口，操作不可逆
+// @Tags 服务管理
+// @Accept json
+// @Produce json
+// @Param name path string true "服务名称" example:"nginx-web"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Failure 404 {object} object{code=int,msg=string,data=object} "服务不在回收站中"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/trash/{name} [delete]
+func (api *Api) PurgeTrashedService(c *gin.Context) {
+	name := c.Param("name")
+	ctx := requestContext(c)
+	if err := api.ser.PurgeTrashedService(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "清除回收站中的服务失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{})
+}
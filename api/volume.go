@@ -0,0 +1,93 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ListVolumes 列出服务使用的卷和主机目录
+// @Summary 列出服务使用的卷和主机目录
+// @Description 按命名卷名称和主机目录路径汇总受管副本（包括已停止的副本）的挂载，列出使用它的服务、副本和磁盘占用，命名卷在前。
+// @Description 命名卷的占用来自 Docker 磁盘统计；主机目录的占用由 OneDock 遍历目录统计，OneDock 无法访问该目录或 10 秒内未统计完时 size 为 -1，原因记录在 error 中。path 可用于查看单个主机目录
+// @Tags 卷管理
+// @Accept json
+// @Produce json
+// @Param type query string false "只列出该类型" Enums(volume, bind)
+// @Param service query string false "只列出该服务使用的卷" example:"nginx-web"
+// @Param path query string false "只列出该主机路径" example:"/data/nginx"
+// @Param all query bool false "同时列出没有受管服务使用的命名卷" example:"false"
+// @Success 200 {object} object{code=int,data=[]models.Volume,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/volumes [get]
+func (api *Api) ListVolumes(c *gin.Context) {
+	query := &models.VolumeQuery{
+		Type:    c.Query("type"),
+		Service: c.Query("service"),
+		Path:    c.Query("path"),
+		All:     c.Query("all") == "true",
+	}
+	if query.Type != "" && query.Type != models.VolumeTypeVolume && query.Type != models.VolumeTypeBind {
+		utils.Rfail(c, "invalid type: must be volume or bind")
+		return
+	}
+
+	ctx := requestContext(c)
+	volumes, err := api.ser.ListVolumes(ctx, query)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "列出卷失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, volumes)
+}
+
+// GetVolume 获取命名卷
+// @Summary 获取命名卷
+// @Description 获取命名卷的驱动、挂载点、磁盘占用以及挂载它的服务和副本
+// @Tags 卷管理
+// @Accept json
+// @Produce json
+// @Param volume path string true "卷名称" example:"nginx-data"
+// @Success 200 {object} object{code=int,data=models.Volume,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "卷不存在"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/volumes/{volume} [get]
+func (api *Api) GetVolume(c *gin.Context) {
+	name := c.Param("volume")
+	ctx := requestContext(c)
+	volume, err := api.ser.GetVolume(ctx, name)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Volume", name), log.Any("Message", "获取卷失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, volume)
+}
+
+// DeleteVolume 删除命名卷
+// @Summary 删除命名卷
+// @Description 删除命名卷及其中的数据。仍有受管副本（包括已停止的副本）或其他容器挂载该卷时拒绝删除（CONFLICT）；主机目录不通过 OneDock 删除
+// @Tags 卷管理
+// @Accept json
+// @Produce json
+// @Param volume path string true "卷名称" example:"nginx-data"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/volumes/{volume} [delete]
+func (api *Api) DeleteVolume(c *gin.Context) {
+	name := c.Param("volume")
+	ctx := requestContext(c)
+	if err := api.ser.DeleteVolume(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Volume", name), log.Any("Message", "删除卷失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "volume deleted successfully"})
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SaveWebhook 创建或更新事件订阅
+// @Summary 创建或更新事件订阅
+// @Description 服务生命周期事件（部署、更新、扩缩容、删除、副本崩溃等）发生时，以 JSON POST 到订阅的地址，外部系统无需轮询。同名订阅已存在时覆盖配置，包括签名密钥。
+// @Description 请求带 X-OneDock-Event（事件类型）和 X-OneDock-Webhook（订阅名称）请求头；设置了 secret 时还带 X-OneDock-Signature: sha256=<请求体的 HMAC-SHA256>。签名密钥以 secret.key 加密保存，设置密钥前需配置 secret.key
+// @Tags 事件订阅
+// @Accept json
+// @Produce json
+// @Param webhook body models.WebhookRequest true "订阅配置"
+// @Success 200 {object} object{code=int,data=models.Webhook,msg=string} "保存成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/webhooks [post]
+func (api *Api) SaveWebhook(c *gin.Context) {
+	var req models.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "无效的请求参数"))
+		rfailBind(c, err)
+		return
+	}
+	ctx := requestContext(c)
+	webhook, err := api.ser.SaveWebhook(ctx, &req)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Webhook", req.Name), log.Any("Message", "保存事件订阅失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, webhook)
+}
+
+// ListWebhooks 列出事件订阅
+// @Summary 列出事件订阅
+// @Description 获取全部事件订阅及最近一次投递结果，不返回签名密钥
+// @Tags 事件订阅
+// @Accept json
+// @Produce json
+// @Success 200 {object} object{code=int,data=object{Webhooks=[]models.Webhook,Total=int},msg=string} "获取成功"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/webhooks [get]
+func (api *Api) ListWebhooks(c *gin.Context) {
+	ctx := requestContext(c)
+	webhooks, err := api.ser.ListWebhooks(ctx)
+	if err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Message", "获取事件订阅失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{
+		"Webhooks": webhooks,
+		"Total":    len(webhooks),
+	})
+}
+
+// GetWebhook 获取事件订阅
+// @Summary 获取事件订阅
+// @Description 获取事件订阅配置及最近一次投递的状态码、错误和时间，不返回签名密钥
+// @Tags 事件订阅
+// @Accept json
+// @Produce json
+// @Param webhook path string true "订阅名称" example:"deploy-tracker"
+// @Success 200 {object} object{code=int,data=models.Webhook,msg=string} "获取成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/webhooks/{webhook} [get]
+func (api *Api) GetWebhook(c *gin.Context) {
+	name := c.Param("webhook")
+	ctx := requestContext(c)
+	webhook, err := api.ser.GetWebhook(ctx, name)
+	if err != nil {
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, webhook)
+}
+
+// DeleteWebhook 删除事件订阅
+// @Summary 删除事件订阅
+// @Description 删除指定事件订阅，之后的事件不再投递
+// @Tags 事件订阅
+// @Accept json
+// @Produce json
+// @Param webhook path string true "订阅名称" example:"deploy-tracker"
+// @Success 200 {object} object{code=int,data=object,msg=string} "删除成功"
+// @Failure 400 {object} object{code=int,msg=string,data=object} "请求参数错误"
+// @Failure 401 {object} object{code=int,msg=string,data=object} "权限验证失败"
+// @Security BearerAuth || TokenAuth || QueryAuth
+// @Router /onedock/webhooks/{webhook} [delete]
+func (api *Api) DeleteWebhook(c *gin.Context) {
+	name := c.Param("webhook")
+	ctx := requestContext(c)
+	if err := api.ser.DeleteWebhook(ctx, name); err != nil {
+		log.Error("API", log.Any("Error", err), log.Any("Webhook", name), log.Any("Message", "删除事件订阅失败"))
+		utils.RfailErr(c, err)
+		return
+	}
+	utils.Rsucc(c, gin.H{"message": "webhook deleted successfully"})
+}
@@ -0,0 +1,39 @@
+package onedockclient
+
+import "io"
+
+// OneDockAPI 客户端的全部操作。调用方依赖该接口而不是 *Client，单元测试时可以换成 FakeClient，无需运行 OneDock 服务端
+type OneDockAPI interface {
+	Ping() (*PingResponse, error)
+
+	DeployService(req *ServiceRequest) (*Service, error)
+	ListServices() (*ServiceListResponse, error)
+	ListServicesInNamespace(namespace string) (*ServiceListResponse, error)
+	SearchServices(query *ServiceQuery) (*ServiceListResponse, error)
+	IterateServices(query *ServiceQuery, pageSize int) *ServiceIterator
+	ListAllServices(query *ServiceQuery) ([]Service, error)
+	GetService(name string) (*Service, error)
+	GetServiceStatus(name string) (*ServiceStatusResponse, error)
+	DeleteService(name string) error
+	ScaleService(name string, replicas int) error
+	StopService(name string) error
+	StartService(name string) error
+	RollbackService(name string, revision int) (*Service, error)
+
+	GetProxyStats() (*ProxyStats, error)
+	GetServiceProxyStats(name string) (*ServiceProxyStats, error)
+
+	OpenLogStream(name string, opts *LogOptions) (io.ReadCloser, error)
+	StreamLogs(name string, opts *LogOptions) (*LogStream, error)
+	WatchEvents(opts *WatchOptions) (*EventWatch, error)
+	WatchService(name string, types ...string) (*EventWatch, error)
+
+	GetBaseURL() string
+	SetToken(token string)
+	GetToken() string
+}
+
+var (
+	_ OneDockAPI = (*Client)(nil)
+	_ OneDockAPI = (*FakeClient)(nil)
+)
@@ -0,0 +1,102 @@
+package onedockclient
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthMode 令牌的发送方式，对应服务端支持的三种令牌传递方式
+type AuthMode int
+
+const (
+	// AuthBearer 请求头 Authorization: Bearer <token>（默认）
+	AuthBearer AuthMode = iota
+	// AuthTokenHeader 请求头 Token: <token>，用于会改写 Authorization 的代理之后
+	AuthTokenHeader
+	// AuthQuery 查询参数 ?token=<token>，用于无法设置请求头的环境；令牌会出现在代理和访问日志中
+	AuthQuery
+)
+
+// WithAuthMode 设置令牌的发送方式
+func WithAuthMode(mode AuthMode) Option {
+	return func(c *Client) {
+		c.authMode = mode
+	}
+}
+
+// WithTokenFromEnv 从环境变量读取令牌，变量未设置或为空时保留 New 传入的令牌
+func WithTokenFromEnv(key string) Option {
+	return func(c *Client) {
+		if token := strings.TrimSpace(os.Getenv(key)); token != "" {
+			c.token = token
+		}
+	}
+}
+
+// WithTokenFile 从文件读取令牌（忽略首尾空白），文件修改后下一个请求自动使用新令牌，适用于定期轮换的令牌
+// 文件无法读取时请求返回 *ConfigError
+func WithTokenFile(path string) Option {
+	return func(c *Client) {
+		c.tokenFile = &tokenFile{path: path}
+	}
+}
+
+// tokenFile 令牌文件，按修改时间缓存内容
+type tokenFile struct {
+	path    string
+	mutex   sync.Mutex
+	modTime time.Time
+	token   string
+}
+
+// read 返回文件中的令牌，文件未修改时使用缓存
+func (f *tokenFile) read() (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", &ConfigError{Parameter: "token_file", Message: err.Error()}
+	}
+	if f.token != "" && info.ModTime().Equal(f.modTime) {
+		return f.token, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", &ConfigError{Parameter: "token_file", Message: err.Error()}
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", &ConfigError{Parameter: "token_file", Message: f.path + " is empty"}
+	}
+	f.token, f.modTime = token, info.ModTime()
+	return token, nil
+}
+
+// currentToken 当前使用的令牌：设置了令牌文件时读取文件，否则为 New 或 SetToken 设置的令牌
+func (c *Client) currentToken() (string, error) {
+	if c.tokenFile != nil {
+		return c.tokenFile.read()
+	}
+	return c.token, nil
+}
+
+// authorize 按发送方式为请求带上令牌
+func (c *Client) authorize(req *http.Request, token string) {
+	if token == "" {
+		return
+	}
+	switch c.authMode {
+	case AuthTokenHeader:
+		req.Header.Set("Token", token)
+	case AuthQuery:
+		query := req.URL.Query()
+		query.Set("token", token)
+		req.URL.RawQuery = query.Encode()
+	default:
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
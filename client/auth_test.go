@@ -0,0 +1,71 @@
+package onedockclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestAuthModes 测试三种令牌发送方式
+func TestAuthModes(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"code":0,"msg":"succeed","data":{"services":[],"total":0}}`))
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		mode  AuthMode
+		check func(r *http.Request) bool
+	}{
+		{AuthBearer, func(r *http.Request) bool { return r.Header.Get("Authorization") == "Bearer s3cret" }},
+		{AuthTokenHeader, func(r *http.Request) bool {
+			return r.Header.Get("Token") == "s3cret" && r.Header.Get("Authorization") == ""
+		}},
+		{AuthQuery, func(r *http.Request) bool {
+			return r.URL.Query().Get("token") == "s3cret" && r.URL.Query().Get("namespace") == "staging" && r.Header.Get("Authorization") == ""
+		}},
+	}
+	for _, tc := range cases {
+		if _, err := New(server.URL, "s3cret", WithAuthMode(tc.mode)).ListServicesInNamespace("staging"); err != nil {
+			t.Fatalf("mode %d: %v", tc.mode, err)
+		}
+		if !tc.check(got) {
+			t.Errorf("mode %d: 请求头 %v, 查询参数 %s", tc.mode, got.Header, got.URL.RawQuery)
+		}
+	}
+}
+
+// TestTokenSources 测试从环境变量和文件读取令牌，文件更新后使用新令牌
+func TestTokenSources(t *testing.T) {
+	t.Setenv("ONEDOCK_TEST_TOKEN", " from-env\n")
+	if token := New("127.0.0.1:8801", "fallback", WithTokenFromEnv("ONEDOCK_TEST_TOKEN")).GetToken(); token != "from-env" {
+		t.Errorf("WithTokenFromEnv = %q, 期望 from-env", token)
+	}
+	if token := New("127.0.0.1:8801", "fallback", WithTokenFromEnv("ONEDOCK_TEST_UNSET")).GetToken(); token != "fallback" {
+		t.Errorf("环境变量未设置时 = %q, 期望保留 fallback", token)
+	}
+
+	path := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(path, []byte("first\n"), 0600)
+	c := New("127.0.0.1:8801", "", WithTokenFile(path))
+	if token := c.GetToken(); token != "first" {
+		t.Errorf("WithTokenFile = %q, 期望 first", token)
+	}
+	os.WriteFile(path, []byte("rotated\n"), 0600)
+	os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	if token := c.GetToken(); token != "rotated" {
+		t.Errorf("令牌文件更新后 = %q, 期望 rotated", token)
+	}
+
+	missing := New("127.0.0.1:8801", "", WithTokenFile(filepath.Join(t.TempDir(), "missing")))
+	var configErr *ConfigError
+	if _, err := missing.ListServices(); !errors.As(err, &configErr) {
+		t.Errorf("令牌文件不存在时 error = %v, 期望 *ConfigError", err)
+	}
+}
@@ -2,10 +2,14 @@ package onedockclient
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,8 +18,12 @@ import (
 type Client struct {
 	baseURL    string
 	token      string
+	tokenFile  *tokenFile
+	authMode   AuthMode
+	signingKey *signingKey
 	httpClient *http.Client
 	timeout    time.Duration
+	retry      RetryPolicy
 	debug      bool
 }
 
@@ -44,6 +52,20 @@ func WithDebug(debug bool) Option {
 	}
 }
 
+// signingKey 请求签名密钥，对应服务端的 [[auth.hmac_keys]]
+type signingKey struct {
+	id     string
+	secret string
+}
+
+// WithSigningKey 使用 HMAC 签名代替令牌：每个请求带上时间戳和对请求体的签名，
+// 令牌不会出现在请求中，截获的请求也无法重放
+func WithSigningKey(id, secret string) Option {
+	return func(c *Client) {
+		c.signingKey = &signingKey{id: id, secret: secret}
+	}
+}
+
 // New 创建新的 OneDock API 客户端
 func New(baseURL, token string, options ...Option) *Client {
 	// 确保 baseURL 格式正确
@@ -59,6 +81,7 @@ func New(baseURL, token string, options ...Option) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry: DefaultRetryPolicy(),
 		debug: false,
 	}
 
@@ -70,28 +93,42 @@ func New(baseURL, token string, options ...Option) *Client {
 	return client
 }
 
-// doRequest 执行 HTTP 请求
+// doRequest 执行 HTTP 请求，幂等方法遇到暂时性错误时按重试策略重试
 func (c *Client) doRequest(method, endpoint string, body interface{}) (*http.Response, error) {
+	return c.doWithRetry(method, endpoint, body)
+}
+
+// send 使用指定的 HTTP 客户端执行请求，长连接接口使用不带超时的客户端
+func (c *Client) send(httpClient *http.Client, method, endpoint string, body interface{}) (*http.Response, error) {
 	url := c.baseURL + endpoint
 
-	var reqBody io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequest(method, url, bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// 设置请求头
 	req.Header.Set("Content-Type", "application/json")
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	if c.signingKey != nil {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Onedock-Key", c.signingKey.id)
+		req.Header.Set("X-Onedock-Timestamp", timestamp)
+		req.Header.Set("X-Onedock-Signature", signRequest(c.signingKey.secret, timestamp, method, req.URL.RequestURI(), jsonData))
+	} else {
+		token, err := c.currentToken()
+		if err != nil {
+			return nil, err
+		}
+		c.authorize(req, token)
 	}
 
 	if c.debug {
@@ -101,7 +138,7 @@ func (c *Client) doRequest(method, endpoint string, body interface{}) (*http.Res
 		}
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -113,6 +150,21 @@ func (c *Client) doRequest(method, endpoint string, body interface{}) (*http.Res
 	return resp, nil
 }
 
+// streamClient 不带整体超时的 HTTP 客户端，用于实时日志等长连接接口
+func (c *Client) streamClient() *http.Client {
+	stream := *c.httpClient
+	stream.Timeout = 0
+	return &stream
+}
+
+// signRequest 计算请求签名：HMAC-SHA256(secret, 时间戳\n方法\n路径与查询参数\n请求体)，与服务端一致
+func signRequest(secret, timestamp, method, uri string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + method + "\n" + uri + "\n"))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
 // parseResponse 解析响应
 func (c *Client) parseResponse(resp *http.Response, result interface{}) error {
 	defer resp.Body.Close()
@@ -128,23 +180,30 @@ func (c *Client) parseResponse(resp *http.Response, result interface{}) error {
 
 	// 检查 HTTP 状态码
 	if resp.StatusCode >= 400 {
-		var apiError APIError
-		if err := json.Unmarshal(body, &apiError); err != nil {
+		var failure Response
+		if err := json.Unmarshal(body, &failure); err != nil || failure.Msg == "" {
 			return NewAPIError(resp.StatusCode, string(body))
 		}
-		apiError.Code = resp.StatusCode
-		return &apiError
+		return &APIError{Code: resp.StatusCode, ErrorCode: failure.ErrorCode, Message: failure.Msg}
+	}
+
+	var envelope struct {
+		Response
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	// 业务失败时 HTTP 状态码为 200，code 非 0，error_code 为稳定的错误码
+	if envelope.Code != 0 {
+		return &APIError{Code: resp.StatusCode, ErrorCode: envelope.ErrorCode, Message: envelope.Msg}
 	}
 
 	// 如果 result 为 nil，说明不需要解析响应体
-	if result == nil {
+	if result == nil || len(envelope.Data) == 0 {
 		return nil
 	}
-	data := new(Response)
-	data.Data = result
-
-	err = json.Unmarshal(body, data)
-	if err != nil {
+	if err := json.Unmarshal(envelope.Data, result); err != nil {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
@@ -156,12 +215,14 @@ func (c *Client) GetBaseURL() string {
 	return c.baseURL
 }
 
-// SetToken 设置认证 token
+// SetToken 设置认证 token，之后不再读取 WithTokenFile 设置的令牌文件
 func (c *Client) SetToken(token string) {
 	c.token = token
+	c.tokenFile = nil
 }
 
-// GetToken 获取当前 token
+// GetToken 获取当前 token，设置了令牌文件时为文件中的令牌（无法读取时为空）
 func (c *Client) GetToken() string {
-	return c.token
+	token, _ := c.currentToken()
+	return token
 }
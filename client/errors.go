@@ -5,35 +5,66 @@ import (
 	"net/http"
 )
 
+// 服务端返回的错误码（APIError.ErrorCode），与服务端 utils 中的定义一致
+const (
+	ErrInvalidRequest    = "INVALID_REQUEST"
+	ErrValidationFailed  = "VALIDATION_FAILED"
+	ErrUnauthorized      = "UNAUTHORIZED"
+	ErrForbidden         = "FORBIDDEN"
+	ErrRateLimited       = "RATE_LIMITED"
+	ErrNotFound          = "NOT_FOUND"
+	ErrServiceNotFound   = "SERVICE_NOT_FOUND"
+	ErrServiceExists     = "SERVICE_EXISTS"
+	ErrServiceLocked     = "SERVICE_LOCKED"
+	ErrServiceBusy       = "SERVICE_BUSY"
+	ErrConflict          = "CONFLICT"
+	ErrPortInUse         = "PORT_IN_USE"
+	ErrQuotaExceeded     = "QUOTA_EXCEEDED"
+	ErrImagePullFailed   = "IMAGE_PULL_FAILED"
+	ErrDockerUnavailable = "DOCKER_UNAVAILABLE"
+	ErrTimeout           = "TIMEOUT"
+	ErrOperationFailed   = "OPERATION_FAILED"
+)
+
 // APIError API 错误类型
+// 业务失败时 Code 为 HTTP 状态码 200，ErrorCode 为服务端返回的错误码
 type APIError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code      int    `json:"code"`
+	ErrorCode string `json:"error_code,omitempty"`
+	Message   string `json:"message"`
 }
 
 // Error 实现 error 接口
 func (e *APIError) Error() string {
+	if e.ErrorCode != "" {
+		return fmt.Sprintf("API error %s: %s", e.ErrorCode, e.Message)
+	}
 	return fmt.Sprintf("API error %d: %s", e.Code, e.Message)
 }
 
-// IsNotFound 检查是否为 404 错误
+// HasCode 检查错误码
+func (e *APIError) HasCode(errorCode string) bool {
+	return e.ErrorCode == errorCode
+}
+
+// IsNotFound 检查是否为 404 错误，或服务、资源不存在
 func (e *APIError) IsNotFound() bool {
-	return e.Code == http.StatusNotFound
+	return e.Code == http.StatusNotFound || e.ErrorCode == ErrNotFound || e.ErrorCode == ErrServiceNotFound
 }
 
-// IsBadRequest 检查是否为 400 错误
+// IsBadRequest 检查是否为 400 错误，或请求参数错误
 func (e *APIError) IsBadRequest() bool {
-	return e.Code == http.StatusBadRequest
+	return e.Code == http.StatusBadRequest || e.ErrorCode == ErrInvalidRequest || e.ErrorCode == ErrValidationFailed
 }
 
-// IsUnauthorized 检查是否为 401 错误
+// IsUnauthorized 检查是否为 401 错误，或缺少、无效的凭据
 func (e *APIError) IsUnauthorized() bool {
-	return e.Code == http.StatusUnauthorized
+	return e.Code == http.StatusUnauthorized || e.ErrorCode == ErrUnauthorized
 }
 
-// IsForbidden 检查是否为 403 错误
+// IsForbidden 检查是否为 403 错误，或无权执行该操作
 func (e *APIError) IsForbidden() bool {
-	return e.Code == http.StatusForbidden
+	return e.Code == http.StatusForbidden || e.ErrorCode == ErrForbidden
 }
 
 // IsServerError 检查是否为服务器错误 (5xx)
@@ -0,0 +1,167 @@
+package onedockclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EventWatch 实时事件订阅，由 WatchEvents 或 WatchService 创建
+// 连接意外断开时按客户端的重试策略重新连接，并通过事件查询接口补齐断线期间的生命周期事件（代理启动、停止事件无法补齐）
+type EventWatch struct {
+	sseStream
+
+	// Events 服务事件，订阅结束（Close 或无法重连）后关闭
+	Events <-chan *Event
+
+	client     *Client
+	options    WatchOptions
+	events     chan *Event
+	lastID     int64          // 已收到的最大事件 ID
+	since      time.Time      // 补齐断线期间事件的起始时间
+	backfilled map[int64]bool // 最近一次补齐的事件，重连后的事件流中再次出现时跳过
+}
+
+// WatchEvents 订阅服务生命周期事件（部署、更新、扩缩容、副本崩溃、代理启停等），无需轮询服务列表
+// 只推送订阅之后发生的事件；使用完毕后调用 Close，该请求不受 WithTimeout 限制
+func (c *Client) WatchEvents(opts *WatchOptions) (*EventWatch, error) {
+	watch := &EventWatch{
+		sseStream: sseStream{done: make(chan struct{})},
+		client:    c,
+		events:    make(chan *Event, 100),
+		since:     time.Now(),
+	}
+	watch.Events = watch.events
+	if opts != nil {
+		watch.options = *opts
+	}
+
+	body, err := watch.open()
+	if err != nil {
+		return nil, err
+	}
+	watch.body = body
+	go watch.run(body)
+	return watch, nil
+}
+
+// WatchService 订阅单个服务的事件，types 为空时接收全部类型
+func (c *Client) WatchService(name string, types ...string) (*EventWatch, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+	return c.WatchEvents(&WatchOptions{Service: name, Types: types})
+}
+
+// open 打开事件流
+func (w *EventWatch) open() (io.ReadCloser, error) {
+	params := url.Values{}
+	if w.options.Service != "" {
+		params.Set("service", w.options.Service)
+	}
+	if len(w.options.Types) > 0 {
+		params.Set("type", strings.Join(w.options.Types, ","))
+	}
+	endpoint := "/onedock/events/stream"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	return w.client.openStream(endpoint)
+}
+
+// run 读取事件，连接断开后重连并补齐断线期间的事件，直到 Close 或无法重连
+func (w *EventWatch) run(body io.ReadCloser) {
+	defer close(w.events)
+
+	for {
+		err := readSSE(body, func(event, data string) bool {
+			return event != "event" || w.receive(data)
+		})
+		body.Close()
+		if w.isClosed() {
+			return
+		}
+		if err != nil && w.client.debug {
+			fmt.Printf("Event stream interrupted: %v\n", err)
+		}
+
+		body, err = w.reopen(w.client, "event stream", w.open)
+		if err != nil {
+			w.fail(err)
+			return
+		}
+		if body == nil {
+			return // 重连期间已关闭
+		}
+		if !w.backfill() {
+			return
+		}
+	}
+}
+
+// receive 解析并推送事件流中的一条事件；返回 false 表示订阅已关闭
+func (w *EventWatch) receive(data string) bool {
+	event := new(Event)
+	if err := json.Unmarshal([]byte(data), event); err != nil {
+		return true
+	}
+	if event.ID > 0 && w.backfilled[event.ID] {
+		return true
+	}
+	return w.deliver(event)
+}
+
+// backfill 查询断线期间的生命周期事件并按发生顺序推送，查询失败时跳过；返回 false 表示订阅已关闭
+func (w *EventWatch) backfill() bool {
+	params := url.Values{"since": {w.since.UTC().Format(time.RFC3339)}, "limit": {"500"}}
+	if w.options.Service != "" {
+		params.Set("service", w.options.Service)
+	}
+	resp, err := w.client.doRequest("GET", "/onedock/events?"+params.Encode(), nil)
+	if err != nil {
+		return true
+	}
+	var missed EventList
+	if err := w.client.parseResponse(resp, &missed); err != nil {
+		if w.client.debug {
+			fmt.Printf("Backfill events failed: %v\n", err)
+		}
+		return true
+	}
+
+	types := make(map[string]bool, len(w.options.Types))
+	for _, t := range w.options.Types {
+		types[t] = true
+	}
+	sort.Slice(missed.Events, func(i, j int) bool { return missed.Events[i].ID < missed.Events[j].ID })
+	w.backfilled = make(map[int64]bool)
+	for _, event := range missed.Events {
+		if event.ID <= w.lastID || (len(types) > 0 && !types[event.Type]) {
+			continue
+		}
+		w.backfilled[event.ID] = true
+		if !w.deliver(event) {
+			return false
+		}
+	}
+	return true
+}
+
+// deliver 推送事件并记录进度；返回 false 表示订阅已关闭
+func (w *EventWatch) deliver(event *Event) bool {
+	if event.ID > w.lastID {
+		w.lastID = event.ID
+		// 事件时间精确到秒，从该秒开始查询，重复的事件按 ID 过滤
+		w.since = event.CreatedAt.Truncate(time.Second)
+	}
+	select {
+	case w.events <- event:
+		return true
+	case <-w.done:
+		return false
+	}
+}
@@ -0,0 +1,69 @@
+package onedockclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWatchServiceBackfill 测试事件流断开后重连，补齐断线期间的事件且不重复推送
+func TestWatchServiceBackfill(t *testing.T) {
+	var streams int32
+	var query, backfillSince string
+	event := func(w http.ResponseWriter, id int, eventType string) {
+		fmt.Fprintf(w, "event:event\ndata:{\"id\":%d,\"service\":\"web\",\"type\":%q,\"created_at\":\"2024-01-15T10:30:0%dZ\"}\n\n", id, eventType, id)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/onedock/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&streams, 1) {
+		case 1:
+			query = r.URL.RawQuery
+			w.Header().Set("Content-Type", "text/event-stream")
+			event(w, 1, "deployed")
+		case 2:
+			w.Header().Set("Content-Type", "text/event-stream")
+			event(w, 2, "scaled")
+			event(w, 3, "deployed")
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"code":1,"error_code":"FORBIDDEN","msg":"token revoked","data":null}`)
+		}
+	})
+	mux.HandleFunc("/onedock/events", func(w http.ResponseWriter, r *http.Request) {
+		backfillSince = r.URL.Query().Get("since")
+		fmt.Fprint(w, `{"code":0,"msg":"ok","data":{"total":3,"events":[`+
+			`{"id":4,"service":"web","type":"replica_crashed","created_at":"2024-01-15T10:30:04Z"},`+
+			`{"id":2,"service":"web","type":"scaled","created_at":"2024-01-15T10:30:02Z"},`+
+			`{"id":1,"service":"web","type":"deployed","created_at":"2024-01-15T10:30:01Z"}]}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := New(server.URL, "token", WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}))
+	watch, err := c.WatchService("web", EventDeployed, EventScaled)
+	if err != nil {
+		t.Fatalf("WatchService() error = %v", err)
+	}
+	defer watch.Close()
+
+	var got []string
+	for event := range watch.Events {
+		got = append(got, fmt.Sprintf("%d:%s", event.ID, event.Type))
+	}
+	if want := "[1:deployed 2:scaled 3:deployed]"; fmt.Sprint(got) != want {
+		t.Errorf("事件 = %v, 期望 %s", got, want)
+	}
+	if query != "service=web&type=deployed%2Cscaled" {
+		t.Errorf("订阅参数 = %q", query)
+	}
+	if backfillSince != "2024-01-15T10:30:01Z" {
+		t.Errorf("补齐起始时间 = %q, 期望最后一条事件的时间", backfillSince)
+	}
+	if apiErr, ok := watch.Err().(*APIError); !ok || !apiErr.IsForbidden() {
+		t.Errorf("Err() = %v, 期望无权访问", watch.Err())
+	}
+}
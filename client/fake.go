@@ -0,0 +1,770 @@
+package onedockclient
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 自动分配公共端口的范围，与服务端默认的 ports.auto_range 一致
+const (
+	fakeAutoPortStart = 20000
+	fakeAutoPortEnd   = 29999
+)
+
+// FakeClient OneDockAPI 的内存实现，服务、版本历史、事件和日志都保存在内存中，供调用方的单元测试使用
+// 行为与服务端保持一致：副本数为 0 时按 1 部署、未指定公共端口时从 20000 起自动分配、
+// 服务不存在时返回 SERVICE_NOT_FOUND；可以通过 SetError 模拟接口失败，通过 EmitLog 模拟服务输出日志
+//
+//	fake := onedockclient.NewFakeClient()
+//	var api onedockclient.OneDockAPI = fake
+//	api.DeployService(&onedockclient.ServiceRequest{Name: "web", Image: "nginx", Tag: "alpine", InternalPort: 80})
+type FakeClient struct {
+	mutex sync.Mutex
+
+	token    string
+	services map[string]*fakeService
+	errors   map[string]error
+	logs     map[string][]*LogLine
+	events   []*Event
+	watches  map[*EventWatch]bool
+	streams  map[*LogStream]bool
+	serial   int
+	eventID  int64
+}
+
+// fakeService 内存中的服务及其部署版本（按版本号排序，版本号从 1 开始）
+type fakeService struct {
+	service   Service
+	revisions []ServiceRequest
+}
+
+// NewFakeClient 创建没有任何服务的 FakeClient
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		services: make(map[string]*fakeService),
+		errors:   make(map[string]error),
+		logs:     make(map[string][]*LogLine),
+		watches:  make(map[*EventWatch]bool),
+		streams:  make(map[*LogStream]bool),
+	}
+}
+
+// SetError 让方法 method（如 "DeployService"）之后的调用都返回 err，err 为 nil 时恢复正常
+func (f *FakeClient) SetError(method string, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err == nil {
+		delete(f.errors, method)
+		return
+	}
+	f.errors[method] = err
+}
+
+// EmitLog 模拟服务的副本输出一行日志，推送给该服务正在进行的 StreamLogs，并记入日志历史
+func (f *FakeClient) EmitLog(name, replica, line string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	logLine := &LogLine{Replica: replica, Stream: "stdout", Time: time.Now(), Line: line}
+	f.logs[name] = append(f.logs[name], logLine)
+	for stream := range f.streams {
+		if stream.name == name {
+			select {
+			case stream.lines <- logLine:
+			default: // 调用方没有及时读取时丢弃
+			}
+		}
+	}
+}
+
+// Events 到目前为止产生的全部事件，按发生顺序
+func (f *FakeClient) Events() []Event {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	events := make([]Event, 0, len(f.events))
+	for _, event := range f.events {
+		events = append(events, *event)
+	}
+	return events
+}
+
+// Ping 健康检查
+func (f *FakeClient) Ping() (*PingResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.errors["Ping"]; err != nil {
+		return nil, err
+	}
+	return &PingResponse{Message: "pong", Timestamp: time.Now()}, nil
+}
+
+// DeployService 部署新服务，服务已存在时按新配置更新
+func (f *FakeClient) DeployService(req *ServiceRequest) (*Service, error) {
+	if err := validateServiceRequest(req); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.errors["DeployService"]; err != nil {
+		return nil, err
+	}
+
+	spec := *req
+	namespace := spec.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	name := spec.Name
+	if namespace != "default" {
+		name = namespace + "." + spec.Name
+	}
+	if spec.Replicas == 0 {
+		spec.Replicas = 1
+	}
+
+	existing := f.services[name]
+	if spec.PublicPort == 0 && existing != nil {
+		spec.PublicPort = existing.service.PublicPort
+	}
+	if spec.PublicPort == 0 {
+		spec.PublicPort = f.freePort()
+		if spec.PublicPort == 0 {
+			return nil, &APIError{Code: http.StatusOK, ErrorCode: ErrPortInUse,
+				Message: fmt.Sprintf("no free public port in %d-%d", fakeAutoPortStart, fakeAutoPortEnd)}
+		}
+	}
+	if owner := f.portOwner(spec.PublicPort); owner != "" && owner != name {
+		return nil, &APIError{Code: http.StatusOK, ErrorCode: ErrPortInUse,
+			Message: fmt.Sprintf("public port %d is already in use on the host by %s", spec.PublicPort, owner)}
+	}
+
+	if existing == nil {
+		f.serial++
+		existing = &fakeService{service: Service{
+			ID:        fmt.Sprintf("fake-%d", f.serial),
+			Name:      name,
+			Namespace: namespace,
+			CreatedAt: time.Now(),
+		}}
+		f.services[name] = existing
+		f.apply(existing, spec)
+		f.emit(name, EventDeployed, fmt.Sprintf("deployed %s:%s with %d replicas", spec.Image, spec.Tag, spec.Replicas))
+	} else {
+		f.apply(existing, spec)
+		f.emit(name, EventUpdated, fmt.Sprintf("updated to %s:%s", spec.Image, spec.Tag))
+	}
+	existing.revisions = append(existing.revisions, spec)
+
+	service := existing.service
+	return &service, nil
+}
+
+// ListServices 获取所有服务列表
+func (f *FakeClient) ListServices() (*ServiceListResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.errors["ListServices"]; err != nil {
+		return nil, err
+	}
+	return f.search(nil), nil
+}
+
+// ListServicesInNamespace 列出指定命名空间下的服务
+func (f *FakeClient) ListServicesInNamespace(namespace string) (*ServiceListResponse, error) {
+	if namespace == "" {
+		return nil, NewValidationError("namespace", "namespace cannot be empty")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.errors["ListServicesInNamespace"]; err != nil {
+		return nil, err
+	}
+	return f.search(&ServiceQuery{Namespace: namespace}), nil
+}
+
+// SearchServices 按标签、镜像、状态、端口和命名空间搜索服务
+func (f *FakeClient) SearchServices(query *ServiceQuery) (*ServiceListResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.errors["SearchServices"]; err != nil {
+		return nil, err
+	}
+	return f.search(query), nil
+}
+
+// IterateServices 遍历符合条件的服务，query 为 nil 时遍历全部服务；pageSize 小于等于 0 时每页 100 条
+func (f *FakeClient) IterateServices(query *ServiceQuery, pageSize int) *ServiceIterator {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return &ServiceIterator{pageSize: pageSize, fetchPage: func(offset, limit int) (*ServiceListResponse, error) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		if err := f.errors["IterateServices"]; err != nil {
+			return nil, err
+		}
+
+		result := f.search(query)
+		if offset > len(result.Services) {
+			offset = len(result.Services)
+		}
+		end := offset + limit
+		if end > len(result.Services) {
+			end = len(result.Services)
+		}
+		result.Services = result.Services[offset:end]
+		return result, nil
+	}}
+}
+
+// ListAllServices 获取符合条件的全部服务，query 为 nil 时返回全部服务
+func (f *FakeClient) ListAllServices(query *ServiceQuery) ([]Service, error) {
+	services := make([]Service, 0)
+	it := f.IterateServices(query, 0)
+	for it.Next() {
+		services = append(services, *it.Service())
+	}
+	return services, it.Err()
+}
+
+// GetService 获取指定服务信息
+func (f *FakeClient) GetService(name string) (*Service, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	existing, err := f.lookup("GetService", name)
+	if err != nil {
+		return nil, err
+	}
+	service := existing.service
+	return &service, nil
+}
+
+// GetServiceStatus 获取服务详细状态，每个副本对应一个实例
+func (f *FakeClient) GetServiceStatus(name string) (*ServiceStatusResponse, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	existing, err := f.lookup("GetServiceStatus", name)
+	if err != nil {
+		return nil, err
+	}
+
+	service := existing.service
+	status := &ServiceStatusResponse{
+		Service:       service,
+		TotalReplicas: service.Replicas,
+		Instances:     make([]ServiceInstanceInfo, 0, service.Replicas),
+		LoadBalancer:  "round_robin",
+		AccessURL:     fmt.Sprintf("http://localhost:%d", service.PublicPort),
+		CreatedAt:     service.CreatedAt,
+		UpdatedAt:     service.UpdatedAt,
+	}
+	instanceStatus := "exited"
+	if service.Status == StatusRunning {
+		instanceStatus = "running"
+		status.RunningReplicas = service.Replicas
+		status.HealthyReplicas = service.Replicas
+	} else {
+		status.StoppedReplicas = service.Replicas
+	}
+	for i, replica := range replicaNames(&service) {
+		status.Instances = append(status.Instances, ServiceInstanceInfo{
+			ID:            replica,
+			ContainerID:   replica,
+			ContainerName: replica,
+			ServiceName:   service.Name,
+			Status:        instanceStatus,
+			PublicPort:    service.PublicPort,
+			ContainerPort: service.InternalPort,
+			InternalPort:  service.InternalPort,
+			Image:         service.Image + ":" + service.Tag,
+			CreatedAt:     service.UpdatedAt,
+			StartedAt:     service.UpdatedAt,
+			IPAddress:     fmt.Sprintf("172.17.0.%d", i+2),
+			Labels:        service.Labels,
+		})
+	}
+	return status, nil
+}
+
+// DeleteService 删除指定服务，该服务正在进行的 StreamLogs 随之结束
+func (f *FakeClient) DeleteService(name string) error {
+	if name == "" {
+		return NewValidationError("name", "service name cannot be empty")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.lookup("DeleteService", name); err != nil {
+		return err
+	}
+
+	delete(f.services, name)
+	delete(f.logs, name)
+	for stream := range f.streams {
+		if stream.name == name {
+			delete(f.streams, stream)
+			stream.fail(serviceNotFound(name))
+			close(stream.lines)
+		}
+	}
+	f.emit(name, EventDeleted, "service deleted")
+	return nil
+}
+
+// ScaleService 扩缩容服务
+func (f *FakeClient) ScaleService(name string, replicas int) error {
+	if name == "" {
+		return NewValidationError("name", "service name cannot be empty")
+	}
+	if replicas < 0 {
+		return NewValidationError("replicas", "replicas must be non-negative")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	existing, err := f.lookup("ScaleService", name)
+	if err != nil {
+		return err
+	}
+
+	previous := existing.service.Replicas
+	existing.service.Replicas = replicas
+	existing.service.UpdatedAt = time.Now()
+	f.emit(name, EventScaled, fmt.Sprintf("scaled from %d to %d replicas", previous, replicas))
+	return nil
+}
+
+// StopService 停止服务
+func (f *FakeClient) StopService(name string) error {
+	return f.setStatus("StopService", name, StatusStopped)
+}
+
+// StartService 启动通过 StopService 停止的服务
+func (f *FakeClient) StartService(name string) error {
+	return f.setStatus("StartService", name, StatusRunning)
+}
+
+// RollbackService 回滚服务，revision 为 0 时回滚到上一个版本；回滚本身也记为一个新版本
+func (f *FakeClient) RollbackService(name string, revision int) (*Service, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+	if revision < 0 {
+		return nil, NewValidationError("revision", "revision must be non-negative")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	existing, err := f.lookup("RollbackService", name)
+	if err != nil {
+		return nil, err
+	}
+
+	var target ServiceRequest
+	switch {
+	case revision == 0 && len(existing.revisions) < 2:
+		return nil, &APIError{Code: http.StatusOK, ErrorCode: ErrOperationFailed,
+			Message: fmt.Sprintf("service %s has no previous revision", name)}
+	case revision == 0:
+		target = existing.revisions[len(existing.revisions)-2]
+	case revision > len(existing.revisions):
+		return nil, &APIError{Code: http.StatusOK, ErrorCode: ErrNotFound,
+			Message: fmt.Sprintf("revision %d not found for service %s", revision, name)}
+	default:
+		target = existing.revisions[revision-1]
+	}
+
+	f.apply(existing, target)
+	existing.revisions = append(existing.revisions, target)
+	f.emit(name, EventUpdated, fmt.Sprintf("rolled back to %s:%s", target.Image, target.Tag))
+
+	service := existing.service
+	return &service, nil
+}
+
+// GetProxyStats 获取全部端口代理的统计信息，运行中且有公共端口的服务各对应一个代理
+func (f *FakeClient) GetProxyStats() (*ProxyStats, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.errors["GetProxyStats"]; err != nil {
+		return nil, err
+	}
+
+	stats := &ProxyStats{ProxyDetails: make([]ProxyDetail, 0)}
+	for _, existing := range f.services {
+		detail, ok := proxyDetail(&existing.service)
+		if !ok {
+			continue
+		}
+		stats.ProxyDetails = append(stats.ProxyDetails, detail)
+		if detail.Type == "load_balancer" {
+			stats.LoadBalancers++
+		} else {
+			stats.SingleProxies++
+		}
+	}
+	stats.TotalProxies = len(stats.ProxyDetails)
+	sort.Slice(stats.ProxyDetails, func(i, j int) bool {
+		return stats.ProxyDetails[i].PublicPort < stats.ProxyDetails[j].PublicPort
+	})
+	return stats, nil
+}
+
+// GetServiceProxyStats 获取单个服务的端口代理统计信息
+func (f *FakeClient) GetServiceProxyStats(name string) (*ServiceProxyStats, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	existing, err := f.lookup("GetServiceProxyStats", name)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ServiceProxyStats{Service: name, Proxies: make([]ProxyDetail, 0)}
+	if detail, ok := proxyDetail(&existing.service); ok {
+		stats.Proxies = append(stats.Proxies, detail)
+	}
+	return stats, nil
+}
+
+// OpenLogStream 返回服务的历史日志，"副本名称 | 日志" 格式，读完即结束
+func (f *FakeClient) OpenLogStream(name string, opts *LogOptions) (io.ReadCloser, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.lookup("OpenLogStream", name); err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	for _, line := range f.history(name, opts) {
+		text.WriteString(line.String() + "\n")
+	}
+	return io.NopCloser(strings.NewReader(text.String())), nil
+}
+
+// StreamLogs 先推送服务的历史日志，之后推送 EmitLog 产生的日志；服务被删除时结束，Err 为 SERVICE_NOT_FOUND
+func (f *FakeClient) StreamLogs(name string, opts *LogOptions) (*LogStream, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.lookup("StreamLogs", name); err != nil {
+		return nil, err
+	}
+
+	history := f.history(name, opts)
+	stream := &LogStream{
+		sseStream: sseStream{done: make(chan struct{})},
+		name:      name,
+		lines:     make(chan *LogLine, len(history)+100),
+		last:      make(map[string]time.Time),
+		started:   time.Now(),
+	}
+	stream.Lines = stream.lines
+	for _, line := range history {
+		stream.lines <- line
+	}
+	f.streams[stream] = true
+
+	go func() {
+		<-stream.done
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		if f.streams[stream] {
+			delete(f.streams, stream)
+			close(stream.lines)
+		}
+	}()
+	return stream, nil
+}
+
+// WatchEvents 订阅之后产生的服务事件
+func (f *FakeClient) WatchEvents(opts *WatchOptions) (*EventWatch, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.errors["WatchEvents"]; err != nil {
+		return nil, err
+	}
+
+	watch := &EventWatch{
+		sseStream: sseStream{done: make(chan struct{})},
+		events:    make(chan *Event, 100),
+		since:     time.Now(),
+	}
+	watch.Events = watch.events
+	if opts != nil {
+		watch.options = *opts
+	}
+	f.watches[watch] = true
+
+	go func() {
+		<-watch.done
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		delete(f.watches, watch)
+		close(watch.events)
+	}()
+	return watch, nil
+}
+
+// WatchService 订阅单个服务的事件，types 为空时接收全部类型
+func (f *FakeClient) WatchService(name string, types ...string) (*EventWatch, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+	return f.WatchEvents(&WatchOptions{Service: name, Types: types})
+}
+
+// GetBaseURL FakeClient 不连接服务端，返回空字符串
+func (f *FakeClient) GetBaseURL() string {
+	return ""
+}
+
+// SetToken 设置认证 token
+func (f *FakeClient) SetToken(token string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.token = token
+}
+
+// GetToken 获取当前 token
+func (f *FakeClient) GetToken() string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.token
+}
+
+// lookup 检查 SetError 设置的错误并查找服务，调用方持有锁
+func (f *FakeClient) lookup(method, name string) (*fakeService, error) {
+	if err := f.errors[method]; err != nil {
+		return nil, err
+	}
+	existing, ok := f.services[name]
+	if !ok {
+		return nil, serviceNotFound(name)
+	}
+	return existing, nil
+}
+
+// setStatus 修改服务状态
+func (f *FakeClient) setStatus(method, name string, status ServiceStatus) error {
+	if name == "" {
+		return NewValidationError("name", "service name cannot be empty")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	existing, err := f.lookup(method, name)
+	if err != nil {
+		return err
+	}
+	existing.service.Status = status
+	existing.service.UpdatedAt = time.Now()
+	return nil
+}
+
+// apply 将部署配置写入服务，调用方持有锁
+func (f *FakeClient) apply(existing *fakeService, spec ServiceRequest) {
+	service := &existing.service
+	service.Image = spec.Image
+	service.Tag = spec.Tag
+	service.Status = StatusRunning
+	service.PublicPort = spec.PublicPort
+	service.InternalPort = spec.InternalPort
+	service.Replicas = spec.Replicas
+	service.MemoryLimit = spec.MemoryLimit
+	service.BindAddress = spec.BindAddress
+	service.ProxyWorkers = spec.ProxyWorkers
+	service.RequestRules = spec.RequestRules
+	service.Mirror = spec.Mirror
+	service.Labels = spec.Labels
+	service.UpdatedAt = time.Now()
+}
+
+// freePort 自动分配范围内第一个未被使用的公共端口，没有时返回 0，调用方持有锁
+func (f *FakeClient) freePort() int {
+	for port := fakeAutoPortStart; port <= fakeAutoPortEnd; port++ {
+		if f.portOwner(port) == "" {
+			return port
+		}
+	}
+	return 0
+}
+
+// portOwner 使用该公共端口的服务，调用方持有锁
+func (f *FakeClient) portOwner(port int) string {
+	for name, existing := range f.services {
+		if existing.service.PublicPort == port {
+			return name
+		}
+	}
+	return ""
+}
+
+// search 按条件筛选服务，按名称排序，调用方持有锁
+func (f *FakeClient) search(query *ServiceQuery) *ServiceListResponse {
+	services := make([]Service, 0, len(f.services))
+	for _, existing := range f.services {
+		if matchService(&existing.service, query) {
+			services = append(services, existing.service)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return &ServiceListResponse{Services: services, Total: len(services)}
+}
+
+// emit 记录事件并推送给匹配的订阅，订阅方没有及时读取时丢弃，调用方持有锁
+func (f *FakeClient) emit(name, eventType, message string) {
+	f.eventID++
+	event := &Event{ID: f.eventID, Service: name, Type: eventType, Message: message, Actor: "fake", CreatedAt: time.Now()}
+	f.events = append(f.events, event)
+
+	for watch := range f.watches {
+		if watch.options.Service != "" && watch.options.Service != name {
+			continue
+		}
+		if len(watch.options.Types) > 0 && !slices.Contains(watch.options.Types, eventType) {
+			continue
+		}
+		select {
+		case watch.events <- event:
+		default:
+		}
+	}
+}
+
+// history 按 Tail、Since 筛选的历史日志，调用方持有锁
+// Tail 为每个副本的行数（为空时 100，all 表示全部），Since 为 RFC3339 时间或 10m 这样的相对时长
+func (f *FakeClient) history(name string, opts *LogOptions) []*LogLine {
+	tail, since := 100, time.Time{}
+	if opts != nil {
+		if opts.Tail == "all" {
+			tail = -1
+		} else if n, err := strconv.Atoi(opts.Tail); err == nil && n >= 0 {
+			tail = n
+		}
+		if t, err := time.Parse(time.RFC3339Nano, opts.Since); err == nil {
+			since = t
+		} else if d, err := time.ParseDuration(opts.Since); err == nil {
+			since = time.Now().Add(-d)
+		}
+	}
+
+	lines := f.logs[name]
+	kept := make([]*LogLine, 0, len(lines))
+	counts := make(map[string]int)
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := lines[i]
+		if !line.Time.After(since) || (tail >= 0 && counts[line.Replica] >= tail) {
+			continue
+		}
+		counts[line.Replica]++
+		kept = append(kept, line)
+	}
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	return kept
+}
+
+// matchService 服务是否符合搜索条件
+func matchService(service *Service, query *ServiceQuery) bool {
+	if query == nil {
+		return true
+	}
+	if query.Namespace != "" && service.Namespace != query.Namespace {
+		return false
+	}
+	if query.Status != "" && service.Status != query.Status {
+		return false
+	}
+	if query.Port != 0 && service.PublicPort != query.Port && service.InternalPort != query.Port {
+		return false
+	}
+	if query.Image != "" {
+		image, tag, hasTag := strings.Cut(query.Image, ":")
+		if service.Image != image || (hasTag && service.Tag != tag) {
+			return false
+		}
+	}
+	for _, selector := range query.Labels {
+		key, value, hasValue := strings.Cut(selector, "=")
+		actual, ok := service.Labels[key]
+		if !ok || (hasValue && actual != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// replicaNames 服务各副本的容器名称
+func replicaNames(service *Service) []string {
+	names := make([]string, 0, service.Replicas)
+	for i := 1; i <= service.Replicas; i++ {
+		names = append(names, fmt.Sprintf("%s-%d-%d", service.Name, service.PublicPort, i))
+	}
+	return names
+}
+
+// proxyDetail 运行中且有公共端口的服务对应的代理统计，请求计数均为 0
+func proxyDetail(service *Service) (ProxyDetail, bool) {
+	if service.Status != StatusRunning || service.PublicPort == 0 || service.Replicas == 0 {
+		return ProxyDetail{}, false
+	}
+
+	detail := ProxyDetail{
+		Service:     service.Name,
+		PublicPort:  service.PublicPort,
+		ListenAddr:  net.JoinHostPort(service.BindAddress, strconv.Itoa(service.PublicPort)),
+		BindAddress: service.BindAddress,
+		Workers:     service.ProxyWorkers,
+		Type:        "single",
+		Backends:    make([]BackendStat, 0, service.Replicas),
+	}
+	if service.Replicas > 1 {
+		detail.Type = "load_balancer"
+		detail.Strategy = "round_robin"
+	}
+	for _, replica := range replicaNames(service) {
+		detail.Backends = append(detail.Backends, BackendStat{ContainerID: replica, ContainerPort: service.InternalPort, Active: true, Weight: 1})
+	}
+	for _, rule := range service.RequestRules {
+		detail.RequestRules = append(detail.RequestRules, RequestRuleStat{Name: rule.Name})
+	}
+	if service.Mirror != nil {
+		detail.Mirror = &MirrorStat{Service: service.Mirror.Service, Percent: service.Mirror.Percent, Target: service.Mirror.Service}
+	}
+	return detail, true
+}
+
+// serviceNotFound 与服务端一致的服务不存在错误
+func serviceNotFound(name string) *APIError {
+	return &APIError{Code: http.StatusOK, ErrorCode: ErrServiceNotFound, Message: fmt.Sprintf("service %s not found", name)}
+}
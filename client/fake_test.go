@@ -0,0 +1,169 @@
+package onedockclient
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestFakeClientServices 测试 FakeClient 的部署、查询、扩缩容、回滚和删除
+func TestFakeClientServices(t *testing.T) {
+	var api OneDockAPI = NewFakeClient()
+
+	web, err := api.DeployService(&ServiceRequest{Name: "web", Image: "nginx", Tag: "1.25", InternalPort: 80,
+		Labels: map[string]string{"team": "payments"}})
+	if err != nil {
+		t.Fatalf("DeployService() 失败: %v", err)
+	}
+	if web.Replicas != 1 || web.PublicPort != 20000 || web.Status != StatusRunning {
+		t.Errorf("部署结果 = %+v, 期望 1 个副本、公共端口 20000、运行中", web)
+	}
+	if _, err := api.DeployService(&ServiceRequest{Name: "api", Namespace: "staging", Image: "app", Tag: "v1",
+		InternalPort: 8080, PublicPort: 20000}); !isErrorCode(err, ErrPortInUse) {
+		t.Errorf("端口冲突时错误 = %v, 期望 %s", err, ErrPortInUse)
+	}
+	if _, err := api.DeployService(&ServiceRequest{Name: "api", Namespace: "staging", Image: "app", Tag: "v1",
+		InternalPort: 8080, Replicas: 3}); err != nil {
+		t.Fatalf("DeployService() 失败: %v", err)
+	}
+	if _, err := api.DeployService(&ServiceRequest{Name: "web"}); err == nil {
+		t.Error("缺少镜像时期望返回校验错误")
+	}
+
+	staging, err := api.ListServicesInNamespace("staging")
+	if err != nil || staging.Total != 1 || staging.Services[0].Name != "staging.api" {
+		t.Errorf("ListServicesInNamespace() = %+v, %v, 期望 staging.api", staging, err)
+	}
+	found, err := api.SearchServices(&ServiceQuery{Labels: []string{"team=payments"}, Image: "nginx:1.25"})
+	if err != nil || found.Total != 1 || found.Services[0].Name != "web" {
+		t.Errorf("SearchServices() = %+v, %v, 期望 web", found, err)
+	}
+	all, err := api.ListAllServices(nil)
+	if err != nil || len(all) != 2 {
+		t.Errorf("ListAllServices() = %d 个服务, %v, 期望 2 个", len(all), err)
+	}
+
+	if err := api.ScaleService("web", 2); err != nil {
+		t.Fatalf("ScaleService() 失败: %v", err)
+	}
+	status, err := api.GetServiceStatus("web")
+	if err != nil || status.RunningReplicas != 2 || len(status.Instances) != 2 {
+		t.Errorf("GetServiceStatus() = %+v, %v, 期望 2 个运行中的副本", status, err)
+	}
+	proxies, err := api.GetProxyStats()
+	if err != nil || proxies.TotalProxies != 2 || proxies.LoadBalancers != 2 {
+		t.Errorf("GetProxyStats() = %+v, %v, 期望 2 个负载均衡代理", proxies, err)
+	}
+
+	if _, err := api.RollbackService("web", 0); err == nil {
+		t.Error("只有一个版本时回滚期望返回错误")
+	}
+	if _, err := api.DeployService(&ServiceRequest{Name: "web", Image: "nginx", Tag: "1.26", InternalPort: 80}); err != nil {
+		t.Fatalf("更新服务失败: %v", err)
+	}
+	rolled, err := api.RollbackService("web", 0)
+	if err != nil || rolled.Tag != "1.25" || rolled.PublicPort != 20000 {
+		t.Errorf("RollbackService() = %+v, %v, 期望回滚到 1.25", rolled, err)
+	}
+	if _, err := api.RollbackService("web", 9); !isErrorCode(err, ErrNotFound) {
+		t.Errorf("版本不存在时错误 = %v, 期望 %s", err, ErrNotFound)
+	}
+
+	if err := api.DeleteService("web"); err != nil {
+		t.Fatalf("DeleteService() 失败: %v", err)
+	}
+	_, err = api.GetService("web")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+		t.Errorf("删除后 GetService() 错误 = %v, 期望服务不存在", err)
+	}
+}
+
+// TestFakeClientSetError 测试 SetError 模拟接口失败以及恢复
+func TestFakeClientSetError(t *testing.T) {
+	fake := NewFakeClient()
+	failure := NewAPIError(503, "docker unavailable")
+	fake.SetError("ListServices", failure)
+
+	if _, err := fake.ListServices(); err != failure {
+		t.Errorf("ListServices() 错误 = %v, 期望 %v", err, failure)
+	}
+	if _, err := fake.Ping(); err != nil {
+		t.Errorf("其他方法不受影响，Ping() 错误 = %v", err)
+	}
+
+	fake.SetError("ListServices", nil)
+	if _, err := fake.ListServices(); err != nil {
+		t.Errorf("恢复后 ListServices() 错误 = %v", err)
+	}
+}
+
+// TestFakeClientWatchAndLogs 测试 FakeClient 的事件订阅和实时日志
+func TestFakeClientWatchAndLogs(t *testing.T) {
+	fake := NewFakeClient()
+	watch, err := fake.WatchService("web", EventDeployed, EventScaled)
+	if err != nil {
+		t.Fatalf("WatchService() 失败: %v", err)
+	}
+	defer watch.Close()
+
+	fake.DeployService(&ServiceRequest{Name: "other", Image: "nginx", Tag: "alpine", InternalPort: 80})
+	fake.DeployService(&ServiceRequest{Name: "web", Image: "nginx", Tag: "alpine", InternalPort: 80})
+	fake.DeployService(&ServiceRequest{Name: "web", Image: "nginx", Tag: "1.26", InternalPort: 80})
+	fake.ScaleService("web", 3)
+	for _, want := range []string{EventDeployed, EventScaled} {
+		select {
+		case event := <-watch.Events:
+			if event.Service != "web" || event.Type != want {
+				t.Errorf("事件 = %+v, 期望 web %s", event, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("没有收到 %s 事件", want)
+		}
+	}
+
+	fake.EmitLog("web", "web-1", "history")
+	stream, err := fake.StreamLogs("web", nil)
+	if err != nil {
+		t.Fatalf("StreamLogs() 失败: %v", err)
+	}
+	fake.EmitLog("web", "web-2", "live")
+	for _, want := range []string{"web-1 | history", "web-2 | live"} {
+		if line := <-stream.Lines; line == nil || line.String() != want {
+			t.Errorf("日志 = %v, 期望 %q", line, want)
+		}
+	}
+
+	text, err := fake.OpenLogStream("web", &LogOptions{Tail: "1"})
+	if err != nil {
+		t.Fatalf("OpenLogStream() 失败: %v", err)
+	}
+	data, _ := io.ReadAll(text)
+	if string(data) != "web-1 | history\nweb-2 | live\n" {
+		t.Errorf("OpenLogStream() = %q", data)
+	}
+
+	fake.DeleteService("web")
+	if _, ok := <-stream.Lines; ok {
+		t.Error("服务删除后日志流期望结束")
+	}
+	if !isErrorCode(stream.Err(), ErrServiceNotFound) {
+		t.Errorf("日志流结束原因 = %v, 期望 %s", stream.Err(), ErrServiceNotFound)
+	}
+	stream.Close()
+
+	watch.Close()
+	if _, ok := <-watch.Events; ok {
+		t.Error("Close 后事件通道期望关闭")
+	}
+	if n := len(fake.Events()); n != 5 {
+		t.Errorf("事件数 = %d, 期望 5: %v", n, fake.Events())
+	}
+}
+
+// isErrorCode err 是否为指定错误码的 APIError
+func isErrorCode(err error, code string) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.HasCode(code)
+}
@@ -0,0 +1,103 @@
+package onedockclient
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// defaultPageSize 迭代服务列表时每页请求的条数
+const defaultPageSize = 100
+
+// ServiceIterator 逐个返回服务，按页（limit、offset）向服务端请求，由 IterateServices 创建
+// 服务端返回的条数达到 Total 时结束；服务端不分页时第一页即为全部服务
+//
+//	it := c.IterateServices(nil, 50)
+//	for it.Next() {
+//	    fmt.Println(it.Service().Name)
+//	}
+//	if err := it.Err(); err != nil { ... }
+type ServiceIterator struct {
+	fetchPage func(offset, limit int) (*ServiceListResponse, error)
+	pageSize  int
+
+	page    []Service
+	index   int
+	offset  int
+	done    bool
+	current *Service
+	err     error
+}
+
+// IterateServices 遍历符合条件的服务，query 为 nil 时遍历全部服务；pageSize 小于等于 0 时每页 100 条
+func (c *Client) IterateServices(query *ServiceQuery, pageSize int) *ServiceIterator {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	endpoint := "/onedock/"
+	if query != nil {
+		endpoint = "/onedock/search"
+	}
+	params := serviceQueryParams(query)
+	return &ServiceIterator{pageSize: pageSize, fetchPage: func(offset, limit int) (*ServiceListResponse, error) {
+		params.Set("limit", strconv.Itoa(limit))
+		params.Set("offset", strconv.Itoa(offset))
+		resp, err := c.doRequest("GET", fmt.Sprintf("%s?%s", endpoint, params.Encode()), nil)
+		if err != nil {
+			return nil, NewNetworkError(err)
+		}
+		result := new(ServiceListResponse)
+		if err := c.parseResponse(resp, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}}
+}
+
+// ListAllServices 翻页获取符合条件的全部服务，query 为 nil 时返回全部服务
+func (c *Client) ListAllServices(query *ServiceQuery) ([]Service, error) {
+	services := make([]Service, 0)
+	it := c.IterateServices(query, 0)
+	for it.Next() {
+		services = append(services, *it.Service())
+	}
+	return services, it.Err()
+}
+
+// Next 前进到下一个服务，没有更多服务或请求失败时返回 false，失败原因见 Err
+func (it *ServiceIterator) Next() bool {
+	for it.index >= len(it.page) {
+		if it.done || it.err != nil {
+			it.current = nil
+			return false
+		}
+		it.fetch()
+	}
+	it.current = &it.page[it.index]
+	it.index++
+	return true
+}
+
+// Service 当前的服务，在 Next 返回 true 之后调用
+func (it *ServiceIterator) Service() *Service {
+	return it.current
+}
+
+// Err 遍历过程中的请求错误
+func (it *ServiceIterator) Err() error {
+	return it.err
+}
+
+// fetch 请求下一页
+func (it *ServiceIterator) fetch() {
+	result, err := it.fetchPage(it.offset, it.pageSize)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	it.page, it.index = result.Services, 0
+	it.offset += len(result.Services)
+	if len(result.Services) == 0 || it.offset >= result.Total {
+		it.done = true
+	}
+}
@@ -0,0 +1,65 @@
+package onedockclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// serviceListServer 返回 total 个服务的测试服务端，paged 为 false 时忽略 limit、offset 一次返回全部
+func serviceListServer(total int, paged bool, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.Path+"?"+r.URL.RawQuery)
+		services := make([]Service, 0, total)
+		for i := 0; i < total; i++ {
+			services = append(services, Service{Name: fmt.Sprintf("web-%d", i)})
+		}
+		if paged {
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			if offset > total {
+				offset = total
+			}
+			if end := offset + limit; end < total {
+				services = services[offset:end]
+			} else {
+				services = services[offset:]
+			}
+		}
+		data, _ := json.Marshal(map[string]interface{}{"Services": services, "Total": total})
+		fmt.Fprintf(w, `{"code":0,"msg":"succeed","data":%s}`, data)
+	}))
+}
+
+// TestServiceIterator 测试按页遍历服务，以及服务端不分页时只请求一次
+func TestServiceIterator(t *testing.T) {
+	var requests []string
+	server := serviceListServer(5, true, &requests)
+	defer server.Close()
+
+	it := New(server.URL, "token").IterateServices(&ServiceQuery{Namespace: "staging"}, 2)
+	var names []string
+	for it.Next() {
+		names = append(names, it.Service().Name)
+	}
+	if it.Err() != nil || fmt.Sprint(names) != "[web-0 web-1 web-2 web-3 web-4]" {
+		t.Errorf("遍历结果 = %v, %v", names, it.Err())
+	}
+	if len(requests) != 3 || requests[2] != "/onedock/search?limit=2&namespace=staging&offset=4" {
+		t.Errorf("请求 = %v, 期望 3 页", requests)
+	}
+
+	requests = nil
+	unpaged := serviceListServer(5, false, &requests)
+	defer unpaged.Close()
+	services, err := New(unpaged.URL, "token").ListAllServices(nil)
+	if err != nil || len(services) != 5 {
+		t.Errorf("ListAllServices() = %d 个服务, %v, 期望 5 个", len(services), err)
+	}
+	if len(requests) != 1 || requests[0] != "/onedock/?limit=100&offset=0" {
+		t.Errorf("请求 = %v, 期望只请求一次", requests)
+	}
+}
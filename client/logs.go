@@ -0,0 +1,125 @@
+package onedockclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// LogStream 实时日志流，由 StreamLogs 创建
+// 连接意外断开时按客户端的重试策略重新连接，从断开前最后一行的时间继续，已收到的行不会重复推送
+type LogStream struct {
+	sseStream
+
+	// Lines 日志行，日志流结束（Close、服务被删除或无法重连）后关闭
+	Lines <-chan *LogLine
+
+	client  *Client
+	name    string
+	lines   chan *LogLine
+	last    map[string]time.Time // 每个副本最后一行的时间，用于重连后去重
+	latest  time.Time            // 所有副本最后一行的时间，重连时作为 since
+	started time.Time
+}
+
+// StreamLogs 实时跟踪服务全部副本的日志，逐行通过 LogStream.Lines 返回
+// 服务不存在等错误在建立连接时返回；之后连接断开会自动重连，无法重连时 Lines 关闭，原因见 Err
+// 使用完毕后调用 Close；该请求不受 WithTimeout 限制
+func (c *Client) StreamLogs(name string, opts *LogOptions) (*LogStream, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+
+	stream := &LogStream{
+		sseStream: sseStream{done: make(chan struct{})},
+		client:    c,
+		name:      name,
+		lines:     make(chan *LogLine, 100),
+		last:      make(map[string]time.Time),
+		started:   time.Now(),
+	}
+	stream.Lines = stream.lines
+
+	var tail, since string
+	if opts != nil {
+		tail, since = opts.Tail, opts.Since
+	}
+	body, err := c.openLogEvents(name, tail, since)
+	if err != nil {
+		return nil, err
+	}
+	stream.body = body
+	go stream.run(body)
+	return stream, nil
+}
+
+// run 读取日志事件，连接断开后从最后收到的日志时间继续，直到 Close 或无法重连
+func (s *LogStream) run(body io.ReadCloser) {
+	defer close(s.lines)
+
+	for {
+		err := readSSE(body, func(event, data string) bool {
+			return event != "log" || s.deliver(data)
+		})
+		body.Close()
+		if s.isClosed() {
+			return
+		}
+		if err != nil && s.client.debug {
+			fmt.Printf("Log stream %s interrupted: %v\n", s.name, err)
+		}
+
+		since := s.latest
+		if since.IsZero() {
+			since = s.started
+		}
+		body, err = s.reopen(s.client, "log stream "+s.name, func() (io.ReadCloser, error) {
+			return s.client.openLogEvents(s.name, "all", since.Format(time.RFC3339Nano))
+		})
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		if body == nil {
+			return // 重连期间已关闭
+		}
+	}
+}
+
+// deliver 推送一行日志，重连后时间不晚于该副本最后一行的日志视为已收到；返回 false 表示日志流已关闭
+func (s *LogStream) deliver(data string) bool {
+	line := new(LogLine)
+	if err := json.Unmarshal([]byte(data), line); err != nil {
+		return true
+	}
+	if !line.Time.IsZero() {
+		if last, ok := s.last[line.Replica]; ok && !line.Time.After(last) {
+			return true
+		}
+		s.last[line.Replica] = line.Time
+		if line.Time.After(s.latest) {
+			s.latest = line.Time
+		}
+	}
+
+	select {
+	case s.lines <- line:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// openLogEvents 以 Server-Sent Events 格式打开服务日志流
+func (c *Client) openLogEvents(name, tail, since string) (io.ReadCloser, error) {
+	params := url.Values{"format": {"sse"}}
+	if tail != "" {
+		params.Set("tail", tail)
+	}
+	if since != "" {
+		params.Set("since", since)
+	}
+	return c.openStream(fmt.Sprintf("/onedock/%s/logs/stream?%s", name, params.Encode()))
+}
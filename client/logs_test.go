@@ -0,0 +1,88 @@
+package onedockclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestStreamLogsReconnect 测试连接断开后从最后一行的时间继续，重复的行不再推送，服务删除后结束
+func TestStreamLogsReconnect(t *testing.T) {
+	var calls int32
+	var resumedSince string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := func(replica, at, line string) {
+			fmt.Fprintf(w, "event:log\ndata:{\"replica\":%q,\"stream\":\"stdout\",\"time\":%q,\"line\":%q}\n\n", replica, at, line)
+		}
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Content-Type", "text/event-stream")
+			event("web-1", "2024-01-15T10:30:00.1Z", "first")
+			fmt.Fprint(w, ": keepalive\n\n")
+			event("web-2", "2024-01-15T10:30:00.2Z", "second")
+		case 2:
+			resumedSince = r.URL.Query().Get("since")
+			w.Header().Set("Content-Type", "text/event-stream")
+			event("web-2", "2024-01-15T10:30:00.2Z", "second")
+			event("web-1", "2024-01-15T10:30:00.3Z", "third")
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"code":1,"error_code":"SERVICE_NOT_FOUND","msg":"service web not found","data":null}`)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, "token", WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}))
+	stream, err := c.StreamLogs("web", &LogOptions{Tail: "10"})
+	if err != nil {
+		t.Fatalf("StreamLogs() error = %v", err)
+	}
+	defer stream.Close()
+
+	var got []string
+	for line := range stream.Lines {
+		got = append(got, line.String())
+	}
+	want := []string{"web-1 | first", "web-2 | second", "web-1 | third"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("日志行 = %v, 期望 %v", got, want)
+	}
+	if resumedSince != "2024-01-15T10:30:00.2Z" {
+		t.Errorf("重连时 since = %q, 期望最后一行的时间", resumedSince)
+	}
+	if apiErr, ok := stream.Err().(*APIError); !ok || !apiErr.IsNotFound() {
+		t.Errorf("Err() = %v, 期望服务不存在", stream.Err())
+	}
+}
+
+// TestStreamLogsClose 测试 Close 后 Lines 关闭且不再重连
+func TestStreamLogsClose(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	stream, err := New(server.URL, "token").StreamLogs("web", nil)
+	if err != nil {
+		t.Fatalf("StreamLogs() error = %v", err)
+	}
+	stream.Close()
+	select {
+	case _, ok := <-stream.Lines:
+		if ok {
+			t.Error("Close 后不应再收到日志")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close 后 Lines 未关闭")
+	}
+	if stream.Err() != nil || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Err() = %v, 请求次数 = %d, 期望 nil 和 1", stream.Err(), calls)
+	}
+}
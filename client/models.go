@@ -7,6 +7,16 @@ import (
 // ServiceStatus 服务状态
 type ServiceStatus string
 
+// 服务状态（Service.Status）
+const (
+	StatusStopped  ServiceStatus = "stopped"
+	StatusStarting ServiceStatus = "starting"
+	StatusRunning  ServiceStatus = "running"
+	StatusStopping ServiceStatus = "stopping"
+	StatusFailed   ServiceStatus = "failed"
+	StatusUpdating ServiceStatus = "updating"
+)
+
 // VolumeMount 卷挂载配置
 type VolumeMount struct {
 	HostPath      string `json:"host_path"`
@@ -21,18 +31,40 @@ type PortMapping struct {
 	Protocol      string `json:"protocol"` // "tcp" 或 "udp"
 }
 
+// RequestRule 请求过滤规则，已设置的条件全部命中时代理返回 403
+type RequestRule struct {
+	Name      string            `json:"name"`
+	Path      string            `json:"path,omitempty"`       // 路径正则
+	Methods   []string          `json:"methods,omitempty"`    // HTTP 方法列表
+	UserAgent string            `json:"user_agent,omitempty"` // User-Agent 正则
+	Headers   map[string]string `json:"headers,omitempty"`    // 请求头名称 -> 值正则
+}
+
+// MirrorConfig 流量镜像配置
+type MirrorConfig struct {
+	Service string `json:"service"` // 镜像目标服务名称
+	Percent int    `json:"percent"` // 镜像比例 1-100
+}
+
 // Service API 响应用的服务信息
 type Service struct {
-	ID           string        `json:"id"`
-	Name         string        `json:"name"`
-	Image        string        `json:"image"`
-	Tag          string        `json:"tag"`
-	Status       ServiceStatus `json:"status"`
-	PublicPort   int           `json:"public_port"`
-	InternalPort int           `json:"internal_port"`
-	Replicas     int           `json:"replicas"`
-	CreatedAt    time.Time     `json:"created_at"`
-	UpdatedAt    time.Time     `json:"updated_at"`
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace"`
+	Image        string            `json:"image"`
+	Tag          string            `json:"tag"`
+	Status       ServiceStatus     `json:"status"`
+	PublicPort   int               `json:"public_port"`
+	InternalPort int               `json:"internal_port"`
+	Replicas     int               `json:"replicas"`
+	MemoryLimit  int               `json:"memory_limit,omitempty"`
+	BindAddress  string            `json:"bind_address,omitempty"`
+	ProxyWorkers int               `json:"proxy_workers,omitempty"`
+	RequestRules []RequestRule     `json:"request_rules,omitempty"`
+	Mirror       *MirrorConfig     `json:"mirror,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
 }
 
 type ServiceListResponse struct {
@@ -40,9 +72,19 @@ type ServiceListResponse struct {
 	Total    int       `json:"total"`
 }
 
+// ServiceQuery 服务搜索条件，空值表示不限
+type ServiceQuery struct {
+	Labels    []string // 标签选择器，如 "team=payments" 或 "env"
+	Image     string   // 镜像名称，可带标签，如 nginx 或 nginx:alpine
+	Status    ServiceStatus
+	Port      int // 公共端口或内部端口
+	Namespace string
+}
+
 // ServiceRequest 服务部署/更新请求
 type ServiceRequest struct {
 	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace,omitempty"`
 	Image        string            `json:"image"`
 	Tag          string            `json:"tag"`
 	InternalPort int               `json:"internal_port"`
@@ -53,7 +95,41 @@ type ServiceRequest struct {
 	Entrypoint   []string          `json:"entrypoint,omitempty"`
 	Command      []string          `json:"command,omitempty"`
 	WorkingDir   string            `json:"working_dir,omitempty"`
+	MemoryLimit  int               `json:"memory_limit,omitempty"`
 	PublicPort   int               `json:"public_port,omitempty"`
+	BindAddress  string            `json:"bind_address,omitempty"`
+	ProxyWorkers int               `json:"proxy_workers,omitempty"`
+	RequestRules []RequestRule     `json:"request_rules,omitempty"`
+	Mirror       *MirrorConfig     `json:"mirror,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+
+	UpdateStrategy *UpdateStrategy `json:"update_strategy,omitempty"`
+}
+
+// UpdateStrategy 滚动更新策略
+type UpdateStrategy struct {
+	MaxSurge       int   `json:"max_surge"`               // 每批先建后删的副本数
+	MaxUnavailable int   `json:"max_unavailable"`         // 每批先删后建的副本数
+	AutoRollback   *bool `json:"auto_rollback,omitempty"` // 更新失败时是否自动回滚
+	PauseAfter     int   `json:"pause_after,omitempty"`   // 更新完指定数量副本后自动暂停
+}
+
+// RolloutStatus 滚动更新进度
+type RolloutStatus struct {
+	Service        string     `json:"service"`
+	State          string     `json:"state"`
+	Image          string     `json:"image"`
+	Total          int        `json:"total"`
+	Updated        int        `json:"updated"`
+	Failed         int        `json:"failed"`
+	MaxSurge       int        `json:"max_surge"`
+	MaxUnavailable int        `json:"max_unavailable"`
+	PauseAfter     int        `json:"pause_after,omitempty"`
+	Actor          string     `json:"actor"`
+	Message        string     `json:"message,omitempty"`
+	StartedAt      time.Time  `json:"started_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
 }
 
 // ScaleRequest 扩缩容请求
@@ -61,6 +137,65 @@ type ScaleRequest struct {
 	Replicas int `json:"replicas"`
 }
 
+// LogOptions 实时日志选项
+type LogOptions struct {
+	Tail  string // 每个副本先输出的历史行数，all 表示全部，为空时为 100
+	Since string // 只输出该时间之后的日志：RFC3339 时间或 10m 这样的相对时长
+}
+
+// LogLine 实时日志中的一行
+type LogLine struct {
+	Replica string    `json:"replica"` // 副本容器名称
+	Stream  string    `json:"stream"`  // stdout / stderr
+	Time    time.Time `json:"time"`    // Docker 记录的时间
+	Line    string    `json:"line"`
+}
+
+// String 返回 "副本名称 | 日志" 格式，与 OpenLogStream 的输出一致
+func (l *LogLine) String() string {
+	return l.Replica + " | " + l.Line
+}
+
+// 服务生命周期事件类型（Event.Type）
+const (
+	EventDeployed         = "deployed"          // 首次部署完成
+	EventUpdated          = "updated"           // 滚动更新完成
+	EventUpdateFailed     = "update_failed"     // 滚动更新失败或被中止
+	EventScaled           = "scaled"            // 副本数变化
+	EventDeleted          = "deleted"           // 服务已删除（包括放入回收站）
+	EventRestored         = "restored"          // 服务已从回收站恢复
+	EventReplicaCrashed   = "replica_crashed"   // 副本意外退出
+	EventReplicaRestarted = "replica_restarted" // 手动重启了单个副本
+	EventReplicaRecreated = "replica_recreated" // 删除并重建了单个副本
+	EventCrashLoop        = "crash_loop"        // 时间窗口内副本反复崩溃
+	EventAutoscaled       = "autoscaled"        // 自动扩缩容调整了副本数
+	EventProxyRestarted   = "proxy_restarted"   // 端口代理未运行，已重新启动
+	EventProxyStarted     = "proxy_started"     // 端口代理已启动（只在实时事件中出现）
+	EventProxyStopped     = "proxy_stopped"     // 端口代理已停止（只在实时事件中出现）
+)
+
+// Event 服务生命周期事件
+type Event struct {
+	ID        int64     `json:"id"` // 实时事件中的代理启动、停止事件没有 ID
+	Service   string    `json:"service"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EventList 事件查询结果
+type EventList struct {
+	Total  int64    `json:"total"`
+	Events []*Event `json:"events"`
+}
+
+// WatchOptions 实时事件订阅条件，空值表示不限
+type WatchOptions struct {
+	Service string   // 只接收该服务的事件
+	Types   []string // 只接收这些类型的事件
+}
+
 // ServiceInstanceInfo 服务实例详细信息
 type ServiceInstanceInfo struct {
 	ID            string            `json:"id"`
@@ -99,42 +234,65 @@ type ServiceStatusResponse struct {
 	UpdatedAt       time.Time             `json:"updated_at"`
 }
 
-// ProxyStats 代理统计信息
+// ProxyStats 全部端口代理的统计信息，对应服务端的 models.ProxyOverview
 type ProxyStats struct {
-	TotalProxies      int                         `json:"total_proxies"`
-	SingleProxies     int                         `json:"single_proxies"`
-	LoadBalancers     int                         `json:"load_balancers"`
-	ProxyDetails      []ProxyDetail               `json:"proxy_details"`
-	LoadBalancerStats map[string]LoadBalancerStat `json:"load_balancer_stats"`
+	TotalProxies  int           `json:"total_proxies"`
+	SingleProxies int           `json:"single_proxies"`
+	LoadBalancers int           `json:"load_balancers"`
+	ProxyDetails  []ProxyDetail `json:"proxy_details"` // 按公共端口排序
 }
 
-// ProxyDetail 代理详细信息
-type ProxyDetail struct {
-	PublicPort    int    `json:"public_port"`
-	ServiceName   string `json:"service_name"`
-	ProxyType     string `json:"proxy_type"`
-	BackendCount  int    `json:"backend_count"`
-	TotalRequests int64  `json:"total_requests"`
-	ErrorCount    int64  `json:"error_count"`
-	Status        string `json:"status"`
+// ServiceProxyStats 服务的端口代理统计信息
+type ServiceProxyStats struct {
+	Service string        `json:"service"`
+	Proxies []ProxyDetail `json:"proxies"` // 没有公共端口或服务已停止时为空
 }
 
-// LoadBalancerStat 负载均衡器统计
-type LoadBalancerStat struct {
-	Strategy      string                 `json:"strategy"`
-	BackendCount  int                    `json:"backend_count"`
-	TotalRequests int64                  `json:"total_requests"`
-	BackendStats  map[string]BackendStat `json:"backend_stats"`
+// ProxyDetail 单个端口代理的统计信息，对应服务端的 models.ProxyStats
+// 后端的请求数和失败数在代理重建（扩缩容、更新）后重新计数，端口的累计请求数和失败数继续累计
+type ProxyDetail struct {
+	Service            string            `json:"service"`
+	PublicPort         int               `json:"public_port"`
+	ListenAddr         string            `json:"listen_addr"`
+	BindAddress        string            `json:"bind_address,omitempty"`
+	Workers            int               `json:"workers"`
+	Type               string            `json:"type"`                          // single / load_balancer
+	Strategy           string            `json:"strategy,omitempty"`            // 负载均衡策略
+	StrategyOverridden bool              `json:"strategy_overridden,omitempty"` // 策略是否通过接口设置
+	RequestsTotal      int64             `json:"requests_total"`
+	ActiveRequests     int64             `json:"active_requests"`
+	ErrorsTotal        int64             `json:"errors_total"` // 返回 5xx 的请求数
+	Backends           []BackendStat     `json:"backends"`
+	RequestRules       []RequestRuleStat `json:"request_rules,omitempty"`
+	Mirror             *MirrorStat       `json:"mirror,omitempty"`
 }
 
-// BackendStat 后端统计
+// BackendStat 后端（副本）统计
 type BackendStat struct {
-	Address     string `json:"address"`
-	Requests    int64  `json:"requests"`
-	Errors      int64  `json:"errors"`
-	Connections int    `json:"connections"`
-	Weight      int    `json:"weight"`
-	Available   bool   `json:"available"`
+	ContainerID   string    `json:"container_id"`
+	ContainerPort int       `json:"container_port"`
+	Active        bool      `json:"active"`      // 是否参与负载均衡
+	Connections   int64     `json:"connections"` // 正在处理的请求数
+	Requests      int64     `json:"requests"`
+	Errors        int64     `json:"errors"` // 后端不可达或返回 5xx 的请求数
+	Weight        int       `json:"weight"`
+	LastUsed      time.Time `json:"last_used"`
+}
+
+// RequestRuleStat 请求过滤规则的拦截计数
+type RequestRuleStat struct {
+	Name    string `json:"name"`
+	Blocked int64  `json:"blocked"`
+}
+
+// MirrorStat 流量镜像计数
+type MirrorStat struct {
+	Service  string `json:"service"`
+	Percent  int    `json:"percent"`
+	Target   string `json:"target"`
+	Mirrored int64  `json:"mirrored"`
+	Failed   int64  `json:"failed"`
+	Skipped  int64  `json:"skipped"` // 因请求体过大或并发已满跳过的请求数
 }
 
 // PingResponse Ping 响应
@@ -146,7 +304,8 @@ type PingResponse struct {
 }
 
 type Response struct {
-	Code int    `json:"code"`
-	Msg  string `json:"msg"`
-	Data any    `json:"data"`
+	Code      int    `json:"code"`
+	ErrorCode string `json:"error_code,omitempty"`
+	Msg       string `json:"msg"`
+	Data      any    `json:"data"`
 }
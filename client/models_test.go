@@ -0,0 +1,40 @@
+package onedockclient
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestProxyStatsMatchesServer 测试客户端的代理统计结构与服务端响应一致，解码时不丢失字段
+func TestProxyStatsMatchesServer(t *testing.T) {
+	server := models.ProxyOverview{
+		TotalProxies:  1,
+		LoadBalancers: 1,
+		ProxyDetails: []models.ProxyStats{{
+			Service: "nginx-web", PublicPort: 9303, ListenAddr: "127.0.0.1:9303", BindAddress: "127.0.0.1", Workers: 2,
+			Type: "load_balancer", Strategy: "least_connections", StrategyOverridden: true,
+			RequestsTotal: 4096, ActiveRequests: 3, ErrorsTotal: 7,
+			Backends: []models.ProxyBackendStats{{ContainerID: "3f2a1b4c5d6e", ContainerPort: 32768, Active: true,
+				Connections: 2, Requests: 1024, Errors: 3, Weight: 100, LastUsed: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)}},
+			RequestRules: []models.RequestRuleStats{{Name: "block-admin", Blocked: 12}},
+			Mirror:       &models.MirrorStats{Service: "nginx-web-shadow", Percent: 10, Target: "http://127.0.0.1:9304", Mirrored: 120, Failed: 1, Skipped: 5},
+		}},
+	}
+	data, _ := json.Marshal(server)
+
+	var stats ProxyStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	decoded, _ := json.Marshal(stats)
+	var want, got map[string]interface{}
+	json.Unmarshal(data, &want)
+	json.Unmarshal(decoded, &got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("客户端解码后 = %s, 期望 %s", decoded, data)
+	}
+}
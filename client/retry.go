@@ -0,0 +1,130 @@
+package onedockclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy 请求重试策略：只重试幂等方法（GET、HEAD、PUT、DELETE、OPTIONS），
+// 遇到网络错误（超时除外）或服务端返回 429、502、503、504 时按指数退避重试，等待时间带随机抖动
+type RetryPolicy struct {
+	MaxAttempts    int           // 最多尝试次数（含第一次），小于等于 1 表示不重试
+	InitialBackoff time.Duration // 第一次重试前的等待时间，之后每次翻倍
+	MaxBackoff     time.Duration // 单次等待的上限；响应的 Retry-After 超过该值时不再重试，直接返回该响应
+}
+
+// DefaultRetryPolicy 默认重试策略：最多尝试 3 次，等待 200ms 起，单次最多 5s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, InitialBackoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second}
+}
+
+// WithRetry 设置重试策略，WithRetry(RetryPolicy{}) 关闭重试
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// idempotentMethods 可以安全重试的 HTTP 方法
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// retryableStatus 表示服务端暂时不可用、可以稍后重试的 HTTP 状态码
+var retryableStatus = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// shouldRetry 判断请求结果是否可以重试：超过整体超时的请求不重试，避免等待时间成倍增加；配置错误不重试
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		var configErr *ConfigError
+		return !(errors.As(err, &netErr) && netErr.Timeout()) && !errors.As(err, &configErr)
+	}
+	return retryableStatus[resp.StatusCode]
+}
+
+// backoff 第 attempt 次重试前的等待时间：InitialBackoff * 2^(attempt-1)，不超过 MaxBackoff，
+// 实际取其一半到全部之间的随机值，避免多个客户端同时重试
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// parseRetryAfter 解析 Retry-After 响应头（秒数或 HTTP 日期）
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// doWithRetry 按重试策略执行请求，非幂等方法只执行一次
+func (c *Client) doWithRetry(method, endpoint string, body interface{}) (*http.Response, error) {
+	attempts := c.retry.MaxAttempts
+	if attempts < 1 || !idempotentMethods[method] {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(c.httpClient, method, endpoint, body)
+		if attempt >= attempts || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait := c.retry.backoff(attempt)
+		if resp != nil {
+			if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if c.retry.MaxBackoff > 0 && after > c.retry.MaxBackoff {
+					return resp, nil
+				}
+				if after > wait {
+					wait = after
+				}
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		// 签名精确到秒，同一秒内重发的请求签名相同，会被服务端当作重放拒绝
+		if c.signingKey != nil && wait < time.Second {
+			wait = time.Second
+		}
+
+		if c.debug {
+			fmt.Printf("Retry %d/%d: %s %s after %s\n", attempt, attempts-1, method, endpoint, wait)
+		}
+		time.Sleep(wait)
+	}
+}
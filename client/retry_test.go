@@ -0,0 +1,81 @@
+package onedockclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRetryIdempotentRequests 测试幂等请求遇到 503 时重试，非幂等请求只发送一次
+func TestRetryIdempotentRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":0,"msg":"ok","data":{"services":[],"total":0}}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, "token", WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}))
+	if _, err := c.ListServices(); err != nil {
+		t.Fatalf("ListServices() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("请求次数 = %d, 期望 3", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	if err := c.ScaleService("web", 2); err == nil {
+		t.Error("POST 请求返回 503 时应返回错误")
+	}
+	if calls != 1 {
+		t.Errorf("POST 请求次数 = %d, 期望不重试", calls)
+	}
+}
+
+// TestRetryAfter 测试 Retry-After 超过单次等待上限时不再重试
+func TestRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := New(server.URL, "token", WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second}))
+	_, err := c.GetService("web")
+	if apiErr, ok := err.(*APIError); !ok || apiErr.Code != http.StatusTooManyRequests {
+		t.Fatalf("GetService() error = %v, 期望 429", err)
+	}
+	if calls != 1 {
+		t.Errorf("请求次数 = %d, 期望 1", calls)
+	}
+
+	now := time.Now()
+	if wait, ok := parseRetryAfter("3", now); !ok || wait != 3*time.Second {
+		t.Errorf("parseRetryAfter(3) = %v, %v", wait, ok)
+	}
+	if wait, ok := parseRetryAfter(now.Add(2*time.Second).UTC().Format(http.TimeFormat), now); !ok || wait <= 0 || wait > 2*time.Second {
+		t.Errorf("parseRetryAfter(date) = %v, %v", wait, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("无法解析的 Retry-After 应忽略")
+	}
+}
+
+// TestBackoff 测试退避时间按指数增长、带抖动且不超过上限
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 10: 300 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			if wait := policy.backoff(attempt); wait < max/2 || wait > max {
+				t.Errorf("backoff(%d) = %v, 期望在 [%v, %v] 之间", attempt, wait, max/2, max)
+			}
+		}
+	}
+}
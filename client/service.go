@@ -2,6 +2,10 @@ package onedockclient
 
 import (
 	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 // Ping 健康检查
@@ -21,7 +25,7 @@ func (c *Client) Ping() (*PingResponse, error) {
 
 // DeployService 部署新服务
 func (c *Client) DeployService(req *ServiceRequest) (*Service, error) {
-	if err := c.validateServiceRequest(req); err != nil {
+	if err := validateServiceRequest(req); err != nil {
 		return nil, err
 	}
 
@@ -53,6 +57,64 @@ func (c *Client) ListServices() (*ServiceListResponse, error) {
 	return result, nil
 }
 
+// ListServicesInNamespace 列出指定命名空间下的服务
+func (c *Client) ListServicesInNamespace(namespace string) (*ServiceListResponse, error) {
+	if namespace == "" {
+		return nil, NewValidationError("namespace", "namespace cannot be empty")
+	}
+
+	resp, err := c.doRequest("GET", "/onedock/?namespace="+url.QueryEscape(namespace), nil)
+	if err != nil {
+		return nil, NewNetworkError(err)
+	}
+
+	result := new(ServiceListResponse)
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SearchServices 按标签、镜像、状态、端口和命名空间搜索服务
+func (c *Client) SearchServices(query *ServiceQuery) (*ServiceListResponse, error) {
+	resp, err := c.doRequest("GET", "/onedock/search?"+serviceQueryParams(query).Encode(), nil)
+	if err != nil {
+		return nil, NewNetworkError(err)
+	}
+
+	result := new(ServiceListResponse)
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// serviceQueryParams 服务搜索条件对应的查询参数
+func serviceQueryParams(query *ServiceQuery) url.Values {
+	params := url.Values{}
+	if query == nil {
+		return params
+	}
+	for _, label := range query.Labels {
+		params.Add("label", label)
+	}
+	if query.Image != "" {
+		params.Set("image", query.Image)
+	}
+	if query.Status != "" {
+		params.Set("status", string(query.Status))
+	}
+	if query.Port != 0 {
+		params.Set("port", fmt.Sprintf("%d", query.Port))
+	}
+	if query.Namespace != "" {
+		params.Set("namespace", query.Namespace)
+	}
+	return params
+}
+
 // GetService 获取指定服务信息
 func (c *Client) GetService(name string) (*Service, error) {
 	if name == "" {
@@ -130,7 +192,7 @@ func (c *Client) ScaleService(name string, replicas int) error {
 	return c.parseResponse(resp, nil)
 }
 
-// GetProxyStats 获取代理统计信息
+// GetProxyStats 获取全部端口代理的统计信息
 func (c *Client) GetProxyStats() (*ProxyStats, error) {
 	resp, err := c.doRequest("GET", "/onedock/proxy/stats", nil)
 	if err != nil {
@@ -145,18 +207,119 @@ func (c *Client) GetProxyStats() (*ProxyStats, error) {
 	return &result, nil
 }
 
-// StopService 停止服务（缩容到0）
+// GetServiceProxyStats 获取单个服务的端口代理统计信息
+func (c *Client) GetServiceProxyStats(name string) (*ServiceProxyStats, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+
+	resp, err := c.doRequest("GET", fmt.Sprintf("/onedock/%s/proxy", name), nil)
+	if err != nil {
+		return nil, NewNetworkError(err)
+	}
+
+	var result ServiceProxyStats
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// StopService 停止服务（保留容器，可通过 StartService 立即恢复）
 func (c *Client) StopService(name string) error {
-	return c.ScaleService(name, 0)
+	if name == "" {
+		return NewValidationError("name", "service name cannot be empty")
+	}
+
+	endpoint := fmt.Sprintf("/onedock/%s/stop", name)
+	resp, err := c.doRequest("POST", endpoint, nil)
+	if err != nil {
+		return NewNetworkError(err)
+	}
+
+	return c.parseResponse(resp, nil)
 }
 
-// StartService 启动服务（如果已停止，恢复到1个副本）
+// StartService 启动通过 StopService 停止的服务
 func (c *Client) StartService(name string) error {
-	return c.ScaleService(name, 1)
+	if name == "" {
+		return NewValidationError("name", "service name cannot be empty")
+	}
+
+	endpoint := fmt.Sprintf("/onedock/%s/start", name)
+	resp, err := c.doRequest("POST", endpoint, nil)
+	if err != nil {
+		return NewNetworkError(err)
+	}
+
+	return c.parseResponse(resp, nil)
+}
+
+// RollbackService 回滚服务，revision 为 0 时回滚到上一个版本
+func (c *Client) RollbackService(name string, revision int) (*Service, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+	if revision < 0 {
+		return nil, NewValidationError("revision", "revision must be non-negative")
+	}
+
+	endpoint := fmt.Sprintf("/onedock/%s/rollback", name)
+	if revision > 0 {
+		endpoint += "?revision=" + strconv.Itoa(revision)
+	}
+	resp, err := c.doRequest("POST", endpoint, nil)
+	if err != nil {
+		return nil, NewNetworkError(err)
+	}
+
+	var result Service
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// OpenLogStream 实时跟踪服务全部副本的日志，返回 "副本名称 | 日志" 格式的纯文本流
+// 调用方读取完毕或不再需要时关闭；该请求不受 WithTimeout 限制
+func (c *Client) OpenLogStream(name string, opts *LogOptions) (io.ReadCloser, error) {
+	if name == "" {
+		return nil, NewValidationError("name", "service name cannot be empty")
+	}
+
+	params := url.Values{"format": {"text"}}
+	if opts != nil {
+		if opts.Tail != "" {
+			params.Set("tail", opts.Tail)
+		}
+		if opts.Since != "" {
+			params.Set("since", opts.Since)
+		}
+	}
+	return c.openStream(fmt.Sprintf("/onedock/%s/logs/stream?%s", name, params.Encode()))
+}
+
+// openStream 打开长连接接口，返回响应体
+func (c *Client) openStream(endpoint string) (io.ReadCloser, error) {
+	resp, err := c.send(c.streamClient(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, NewNetworkError(err)
+	}
+
+	// 服务不存在等错误在开始推送前以 JSON 响应返回
+	if resp.StatusCode >= 400 || strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if err := c.parseResponse(resp, nil); err != nil {
+			return nil, err
+		}
+		return nil, NewAPIError(resp.StatusCode, "unexpected response from stream")
+	}
+	return resp.Body, nil
 }
 
 // validateServiceRequest 验证服务请求参数
-func (c *Client) validateServiceRequest(req *ServiceRequest) error {
+func validateServiceRequest(req *ServiceRequest) error {
 	if req == nil {
 		return NewValidationError("request", "service request cannot be nil")
 	}
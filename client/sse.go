@@ -0,0 +1,126 @@
+package onedockclient
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sseStream 可自动重连的 Server-Sent Events 长连接：当前连接、关闭状态和结束原因
+type sseStream struct {
+	mutex  sync.Mutex
+	body   io.ReadCloser
+	closed bool
+	err    error
+	done   chan struct{}
+}
+
+// Err 结束的原因：无法重连时的错误，服务已被删除时为 SERVICE_NOT_FOUND；调用 Close 结束时为 nil
+func (s *sseStream) Err() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.err
+}
+
+// Close 停止接收并关闭连接，对应的通道随后关闭
+func (s *sseStream) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	body := s.body
+	s.mutex.Unlock()
+
+	if body != nil {
+		return body.Close()
+	}
+	return nil
+}
+
+func (s *sseStream) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.closed
+}
+
+// attach 记录当前连接，已关闭时关闭该连接并返回 false
+func (s *sseStream) attach(body io.ReadCloser) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		body.Close()
+		return false
+	}
+	s.body = body
+	return true
+}
+
+func (s *sseStream) fail(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.err = err
+}
+
+// reopen 连接断开后按客户端的重试策略退避并重新打开，最多连续尝试 MaxAttempts 次（至少 1 次）
+// 服务端明确拒绝（如服务不存在）时不再重试；已关闭时返回 nil, nil
+func (s *sseStream) reopen(c *Client, name string, open func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	attempts := c.retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		select {
+		case <-s.done:
+			return nil, nil
+		case <-time.After(c.retry.backoff(attempt)):
+		}
+
+		var body io.ReadCloser
+		if body, err = open(); err == nil {
+			if !s.attach(body) {
+				return nil, nil
+			}
+			return body, nil
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !retryableStatus[apiErr.Code] {
+			return nil, err
+		}
+		if c.debug {
+			fmt.Printf("Reconnect %s (%d/%d): %v\n", name, attempt, attempts, err)
+		}
+	}
+	return nil, err
+}
+
+// readSSE 解析 Server-Sent Events，每个事件调用一次 handle，handle 返回 false 时停止读取；注释行（keepalive）忽略
+func readSSE(body io.Reader, handle func(event, data string) bool) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	var event, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data != "" && !handle(event, data) {
+				return nil
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")
+		}
+	}
+	return scanner.Err()
+}
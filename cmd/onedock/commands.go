@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	onedockclient "github.com/aichy126/onedock/client"
+	"github.com/spf13/cobra"
+)
+
+// outputFormats -o 参数可选的输出格式
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// addOutputFlag 注册 -o/--output 参数并补全可选的输出格式
+func addOutputFlag(cmd *cobra.Command, output *string) {
+	cmd.Flags().StringVarP(output, "output", "o", outputTable, "输出格式：table / json")
+	cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{outputTable, outputJSON}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// newDeployCommand 部署或更新服务
+func newDeployCommand(a *app) *cobra.Command {
+	var file, output string
+	cmd := &cobra.Command{
+		Use:   "deploy -f spec.yaml",
+		Short: "部署或更新服务，配置文件字段与部署接口相同（YAML 或 JSON，- 表示标准输入）",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if file == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return err
+			}
+			spec, err := parseSpec(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+
+			service, err := a.connect().DeployService(spec)
+			if err != nil {
+				return err
+			}
+			if output == outputJSON {
+				return printJSON(cmd.OutOrStdout(), service)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "service %s deployed: %s:%s, %d replicas, public port %d\n",
+				service.Name, service.Image, service.Tag, service.Replicas, service.PublicPort)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "服务配置文件（YAML 或 JSON），- 表示标准输入")
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagFilename("file", "yaml", "yml", "json")
+	addOutputFlag(cmd, &output)
+	return cmd
+}
+
+// newListCommand 列出服务
+func newListCommand(a *app) *cobra.Command {
+	var namespace, output string
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "列出服务",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var list *onedockclient.ServiceListResponse
+			var err error
+			if namespace != "" {
+				list, err = a.connect().ListServicesInNamespace(namespace)
+			} else {
+				list, err = a.connect().ListServices()
+			}
+			if err != nil {
+				return err
+			}
+			if output == outputJSON {
+				return printJSON(cmd.OutOrStdout(), list)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "NAME\tNAMESPACE\tIMAGE\tREPLICAS\tPUBLIC PORT\tSTATUS\tAGE")
+			for _, service := range list.Services {
+				fmt.Fprintf(w, "%s\t%s\t%s:%s\t%d\t%d\t%s\t%s\n", service.Name, service.Namespace, service.Image, service.Tag,
+					service.Replicas, service.PublicPort, service.Status, age(service.CreatedAt))
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "只列出该命名空间下的服务")
+	addOutputFlag(cmd, &output)
+	return cmd
+}
+
+// newStatusCommand 查看服务状态及各副本
+func newStatusCommand(a *app) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:               "status NAME",
+		Short:             "查看服务状态及各副本",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeServiceName(a),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := a.connect().GetServiceStatus(args[0])
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if output == outputJSON {
+				return printJSON(out, status)
+			}
+
+			service := status.Service
+			fmt.Fprintf(out, "Name:       %s\n", service.Name)
+			fmt.Fprintf(out, "Namespace:  %s\n", service.Namespace)
+			fmt.Fprintf(out, "Image:      %s:%s\n", service.Image, service.Tag)
+			fmt.Fprintf(out, "Status:     %s\n", service.Status)
+			fmt.Fprintf(out, "Replicas:   %d running, %d healthy, %d stopped, %d failed (%d total)\n",
+				status.RunningReplicas, status.HealthyReplicas, status.StoppedReplicas, status.FailedReplicas, status.TotalReplicas)
+			if status.AccessURL != "" {
+				fmt.Fprintf(out, "Access URL: %s\n", status.AccessURL)
+			}
+			fmt.Fprintln(out)
+
+			w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "REPLICA\tSTATUS\tHEALTH\tIP\tRESTARTS\tUPTIME")
+			for _, instance := range status.Instances {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", instance.ContainerName, instance.Status, dash(instance.HealthStatus),
+					dash(instance.IPAddress), instance.RestartCount, dash(instance.Uptime))
+			}
+			return w.Flush()
+		},
+	}
+	addOutputFlag(cmd, &output)
+	return cmd
+}
+
+// newScaleCommand 调整服务副本数
+func newScaleCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "scale NAME REPLICAS",
+		Short:             "调整服务副本数",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeServiceName(a),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			replicas, err := strconv.Atoi(args[1])
+			if err != nil || replicas < 0 {
+				return fmt.Errorf("invalid replicas %q: must be a non-negative number", args[1])
+			}
+
+			if err := a.connect().ScaleService(args[0], replicas); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "service %s scaled to %d replicas\n", args[0], replicas)
+			return nil
+		},
+	}
+}
+
+// newLogsCommand 实时跟踪服务日志，直到服务被删除、无法重连或进程被中断
+func newLogsCommand(a *app) *cobra.Command {
+	var tail, since string
+	cmd := &cobra.Command{
+		Use:               "logs NAME",
+		Short:             "实时跟踪服务全部副本的日志，Ctrl+C 退出",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeServiceName(a),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stream, err := a.connect().StreamLogs(args[0], &onedockclient.LogOptions{Tail: tail, Since: since})
+			if err != nil {
+				return err
+			}
+			defer stream.Close()
+
+			// 逐行转发，管道下游（如 grep）可以立即看到输出；连接断开时客户端自动重连
+			for line := range stream.Lines {
+				fmt.Fprintln(cmd.OutOrStdout(), line)
+			}
+			return stream.Err()
+		},
+	}
+	cmd.Flags().StringVar(&tail, "tail", "100", "每个副本先输出的历史行数，all 表示全部")
+	cmd.Flags().StringVar(&since, "since", "", "只输出该时间之后的日志：RFC3339 时间或 10m 这样的相对时长")
+	return cmd
+}
+
+// newRollbackCommand 回滚服务
+func newRollbackCommand(a *app) *cobra.Command {
+	var revision int
+	cmd := &cobra.Command{
+		Use:               "rollback NAME",
+		Short:             "回滚到指定版本，不指定时回滚到上一个版本",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeServiceName(a),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			service, err := a.connect().RollbackService(args[0], revision)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "service %s rolled back to %s:%s\n", service.Name, service.Image, service.Tag)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&revision, "revision", 0, "目标版本号，0 表示上一个版本")
+	return cmd
+}
+
+// printJSON 以缩进的 JSON 输出，便于 jq 等工具处理
+func printJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// age 距今的时长，只保留最大的单位，如 3d、5h、12m
+func age(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	switch d := time.Since(t); {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
+
+// dash 空值显示为 -
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/websocket"
+)
+
+// terminalMessage 终端的文本控制消息，与服务端 api.terminalMessage 相同
+type terminalMessage struct {
+	Type string `json:"type"`
+	Code *int   `json:"code,omitempty"`
+}
+
+// terminalFrame 收到的一帧及其类型
+type terminalFrame struct {
+	payloadType byte
+	data        []byte
+}
+
+// terminalCodec 二进制帧为终端输入输出，文本帧为控制消息
+var terminalCodec = websocket.Codec{
+	Marshal: func(v interface{}) ([]byte, byte, error) {
+		return v.([]byte), websocket.BinaryFrame, nil
+	},
+	Unmarshal: func(data []byte, payloadType byte, v interface{}) error {
+		frame := v.(*terminalFrame)
+		frame.payloadType = payloadType
+		frame.data = data
+		return nil
+	},
+}
+
+// newExecCommand 通过副本 Web 终端接口执行命令：标准输入逐块转发，标准输入结束时发送 EOT（Ctrl+D），
+// 命令结束后以其退出码退出。本地终端不切换到原始模式，适合执行单条命令和脚本，不适合全屏程序
+func newExecCommand(a *app) *cobra.Command {
+	var replica int
+	cmd := &cobra.Command{
+		Use:               "exec NAME [--replica 0] [-- CMD ARGS...]",
+		Short:             "在副本中执行命令，退出码与命令相同；不指定命令时启动 terminal.command",
+		Example:           "  onedock exec nginx-web --replica 1 -- nginx -t",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeServiceName(a),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a.connect()
+			return runExec(a.server, a.token, args[0], replica, args[1:], cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().IntVar(&replica, "replica", 0, "副本编号")
+	return cmd
+}
+
+// runExec 打开副本终端执行 command，转发 stdin 和 stdout
+func runExec(server, token, name string, replica int, command []string, stdin io.Reader, stdout io.Writer) error {
+	config, err := terminalConfig(server, token, name, replica, command)
+	if err != nil {
+		return err
+	}
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to open terminal on %s replica %d, check that the replica is running and terminal.enabled is on: %w", name, replica, err)
+	}
+	defer ws.Close()
+
+	go func() {
+		buf := make([]byte, 32<<10)
+		for {
+			n, err := stdin.Read(buf)
+			if n > 0 && terminalCodec.Send(ws, buf[:n]) != nil {
+				return
+			}
+			if err != nil {
+				terminalCodec.Send(ws, []byte{4})
+				return
+			}
+		}
+	}()
+
+	for {
+		var frame terminalFrame
+		if err := terminalCodec.Receive(ws, &frame); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if frame.payloadType == websocket.BinaryFrame {
+			stdout.Write(frame.data)
+			continue
+		}
+		var msg terminalMessage
+		if json.Unmarshal(frame.data, &msg) == nil && msg.Type == "exit" && msg.Code != nil {
+			if *msg.Code != 0 {
+				return &exitError{code: *msg.Code}
+			}
+			return nil
+		}
+	}
+}
+
+// terminalConfig 终端接口的 WebSocket 连接配置，令牌放在 Authorization 请求头中
+func terminalConfig(server, token, name string, replica int, cmd []string) (*websocket.Config, error) {
+	if !strings.HasPrefix(server, "http://") && !strings.HasPrefix(server, "https://") {
+		server = "http://" + server
+	}
+	origin, err := url.Parse(strings.TrimSuffix(server, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid server %q: %w", server, err)
+	}
+
+	location := *origin
+	location.Scheme = "ws"
+	if origin.Scheme == "https" {
+		location.Scheme = "wss"
+	}
+	location.Path += "/onedock/" + url.PathEscape(name) + "/replicas/" + strconv.Itoa(replica) + "/terminal"
+	location.RawQuery = url.Values{"cmd": cmd}.Encode()
+
+	config, err := websocket.NewConfig(location.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+	config.Header = http.Header{}
+	if token != "" {
+		config.Header.Set("Authorization", "Bearer "+token)
+	}
+	return config, nil
+}
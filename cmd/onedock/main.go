@@ -0,0 +1,119 @@
+// onedock 命令行工具：通过 client 包调用 OneDock API，便于在终端和 CI 脚本中部署、扩缩容、查看日志和回滚
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	onedockclient "github.com/aichy126/onedock/client"
+	"github.com/spf13/cobra"
+)
+
+// 连接配置的环境变量，命令行参数优先
+const (
+	envServer    = "ONEDOCK_URL"
+	envToken     = "ONEDOCK_TOKEN"
+	envTokenFile = "ONEDOCK_TOKEN_FILE"
+
+	defaultServer = "http://127.0.0.1:8801"
+)
+
+// app 全局参数及按需创建的客户端
+type app struct {
+	server    string
+	token     string
+	tokenFile string
+	timeout   time.Duration
+	client    *onedockclient.Client
+}
+
+// connect 按全局参数创建客户端，补全和 help 不需要连接时不会创建
+func (a *app) connect() *onedockclient.Client {
+	if a.client == nil {
+		options := []onedockclient.Option{onedockclient.WithTimeout(a.timeout)}
+		if a.tokenFile != "" {
+			options = append(options, onedockclient.WithTokenFile(a.tokenFile))
+		}
+		a.client = onedockclient.New(a.server, a.token, options...)
+		a.token = a.client.GetToken()
+	}
+	return a.client
+}
+
+// exitError 以指定退出码结束进程，如 exec 转发远程命令的退出码
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
+		fmt.Fprintln(os.Stderr, "onedock:", err)
+		os.Exit(1)
+	}
+}
+
+// newRootCommand 根命令：全局连接参数和全部子命令，help 和 completion 子命令由 cobra 生成
+func newRootCommand() *cobra.Command {
+	a := &app{}
+	root := &cobra.Command{
+		Use:   "onedock",
+		Short: "OneDock 命令行工具",
+		Long:  "通过 OneDock API 部署、扩缩容、查看日志和回滚服务。\n连接参数也可以通过环境变量 " + envServer + "、" + envToken + "、" + envTokenFile + " 设置，命令行参数优先。",
+		// 错误由 main 统一输出；参数错误时通过 --help 查看用法
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.server, "server", envDefault(envServer, defaultServer), "OneDock API 地址（环境变量 "+envServer+"）")
+	flags.StringVar(&a.token, "token", os.Getenv(envToken), "访问令牌（环境变量 "+envToken+"）")
+	flags.StringVar(&a.tokenFile, "token-file", os.Getenv(envTokenFile), "从文件读取访问令牌，优先于 --token（环境变量 "+envTokenFile+"）")
+	flags.DurationVar(&a.timeout, "timeout", 30*time.Minute, "单个请求的超时时间，实时日志和 exec 不受限制")
+
+	root.AddCommand(
+		newDeployCommand(a),
+		newListCommand(a),
+		newStatusCommand(a),
+		newScaleCommand(a),
+		newLogsCommand(a),
+		newRollbackCommand(a),
+		newExecCommand(a),
+	)
+	return root
+}
+
+// envDefault 读取环境变量，未设置时返回 def
+func envDefault(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// completeServiceName 补全第一个位置参数为服务名称，无法连接服务端时不提供候选
+func completeServiceName(a *app) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		list, err := a.connect().ListServices()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names := make([]string, 0, len(list.Services))
+		for _, service := range list.Services {
+			names = append(names, service.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
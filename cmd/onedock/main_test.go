@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExecArgs 测试参数与位置参数交替出现，以及 "--" 之后原样保留
+func TestExecArgs(t *testing.T) {
+	cmd := newExecCommand(&app{})
+	if err := cmd.ParseFlags([]string{"web", "--replica", "2", "--", "ls", "-la", "--replica"}); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	replica, _ := cmd.Flags().GetInt("replica")
+	if rest := cmd.Flags().Args(); replica != 2 || !reflect.DeepEqual(rest, []string{"web", "ls", "-la", "--replica"}) {
+		t.Errorf("ParseFlags() = %v, replica %d", rest, replica)
+	}
+}
+
+// TestRootCommand 测试生成的帮助、补全脚本以及参数错误
+func TestRootCommand(t *testing.T) {
+	out, err := runCLI("http://127.0.0.1:1", "--help")
+	if err != nil {
+		t.Fatalf("--help error = %v", err)
+	}
+	for _, name := range []string{"deploy", "ls", "status", "scale", "logs", "rollback", "exec", "completion", "--token-file"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("帮助信息缺少 %s: %q", name, out)
+		}
+	}
+	if out, err := runCLI("http://127.0.0.1:1", "completion", "bash"); err != nil || !strings.Contains(out, "__start_onedock") {
+		t.Errorf("completion bash = %v, 输出长度 %d", err, len(out))
+	}
+
+	if _, err := runCLI("http://127.0.0.1:1", "scale", "web", "--unknown"); err == nil {
+		t.Error("未知参数应返回错误")
+	}
+	if _, err := runCLI("http://127.0.0.1:1", "status"); err == nil {
+		t.Error("缺少服务名称应返回错误")
+	}
+	if _, err := runCLI("http://127.0.0.1:1", "deploy"); err == nil || !strings.Contains(err.Error(), "file") {
+		t.Errorf("deploy 缺少 -f 应返回错误: %v", err)
+	}
+}
+
+// runCLI 以指定的服务端地址执行命令，返回标准输出
+func runCLI(server string, args ...string) (string, error) {
+	var out bytes.Buffer
+	root := newRootCommand()
+	root.SetArgs(append([]string{"--server", server, "--token", "token"}, args...))
+	root.SetOut(&out)
+	root.SetErr(io.Discard)
+	err := root.Execute()
+	return out.String(), err
+}
+
+// TestParseSpec 测试 YAML 和 JSON 服务配置，不支持的字段视为错误
+func TestParseSpec(t *testing.T) {
+	spec, err := parseSpec([]byte(`
+name: nginx-web
+image: nginx
+tag: alpine
+internal_port: 80
+replicas: 2
+labels:
+  team: payments
+update_strategy:
+  max_surge: 1
+`))
+	if err != nil {
+		t.Fatalf("parseSpec(yaml) error = %v", err)
+	}
+	if spec.Name != "nginx-web" || spec.Replicas != 2 || spec.Labels["team"] != "payments" || spec.UpdateStrategy == nil || spec.UpdateStrategy.MaxSurge != 1 {
+		t.Errorf("parseSpec(yaml) = %+v", spec)
+	}
+
+	if spec, err := parseSpec([]byte(`{"name":"api","image":"app","tag":"1.0","internal_port":8080}`)); err != nil || spec.InternalPort != 8080 {
+		t.Errorf("parseSpec(json) = %+v, %v", spec, err)
+	}
+	if _, err := parseSpec([]byte("name: web\nhealth_check: {}\n")); err == nil || !strings.Contains(err.Error(), "health_check") {
+		t.Errorf("不支持的字段应返回错误: %v", err)
+	}
+}
+
+// TestCommands 测试 ls 和 scale 调用的接口及输出
+func TestCommands(t *testing.T) {
+	var scaled map[string]int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/onedock/":
+			io.WriteString(w, `{"code":0,"msg":"succeed","data":{"services":[{"name":"nginx-web","namespace":"default","image":"nginx","tag":"alpine","replicas":2,"public_port":9203,"status":"running"}],"total":1}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/onedock/nginx-web/scale":
+			json.NewDecoder(r.Body).Decode(&scaled)
+			io.WriteString(w, `{"code":0,"msg":"succeed","data":{}}`)
+		default:
+			io.WriteString(w, `{"code":1,"error_code":"SERVICE_NOT_FOUND","msg":"service not found","data":null}`)
+		}
+	}))
+	defer server.Close()
+
+	out, err := runCLI(server.URL, "ls")
+	if err != nil {
+		t.Fatalf("ls error = %v", err)
+	}
+	if !strings.Contains(out, "nginx-web") || !strings.Contains(out, "nginx:alpine") {
+		t.Errorf("ls 输出 = %q", out)
+	}
+
+	if _, err := runCLI(server.URL, "scale", "nginx-web", "3"); err != nil || scaled["replicas"] != 3 {
+		t.Errorf("scale = %v, 请求 %v", err, scaled)
+	}
+	if _, err := runCLI(server.URL, "scale", "nginx-web", "--", "-1"); err == nil {
+		t.Error("副本数为负数时应返回错误")
+	}
+	if _, err := runCLI(server.URL, "status", "missing"); err == nil || !strings.Contains(err.Error(), "SERVICE_NOT_FOUND") {
+		t.Errorf("status 不存在的服务 = %v", err)
+	}
+}
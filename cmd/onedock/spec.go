@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	onedockclient "github.com/aichy126/onedock/client"
+	"gopkg.in/yaml.v2"
+)
+
+// parseSpec 解析 YAML 或 JSON 服务配置（JSON 也是合法的 YAML），
+// client.ServiceRequest 不支持的字段视为错误，避免配置被静默忽略
+func parseSpec(data []byte) (*onedockclient.ServiceRequest, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	converted, err := json.Marshal(jsonCompatible(value))
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &onedockclient.ServiceRequest{}
+	decoder := json.NewDecoder(bytes.NewReader(converted))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(spec); err != nil {
+		return nil, fmt.Errorf("invalid service spec: %w", err)
+	}
+	return spec, nil
+}
+
+// jsonCompatible 将 yaml.v2 解析出的 map[interface{}]interface{} 转换为可编码为 JSON 的 map[string]interface{}
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	}
+	return value
+}
@@ -15,6 +15,42 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/healthz": {
+            "get": {
+                "description": "进程能处理请求即返回 200，不检查依赖，供进程管理器判断是否需要重启。不需要权限验证",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "存活检查",
+                "responses": {
+                    "200": {
+                        "description": "进程存活",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/onedock": {
             "get": {
                 "security": [
@@ -24,7 +60,7 @@ const docTemplate = `{
                         "TokenAuth": []
                     }
                 ],
-                "description": "获取系统中所有部署的服务列表，包括服务基本信息、状态和副本数量",
+                "description": "获取系统中所有部署的服务列表，包括服务基本信息、状态和副本数量，可按命名空间过滤；令牌限定了服务范围时只返回范围内的服务",
                 "consumes": [
                     "application/json"
                 ],
@@ -35,6 +71,14 @@ const docTemplate = `{
                     "服务管理"
                 ],
                 "summary": "列出所有服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "只列出指定命名空间的服务",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "获取成功",
@@ -91,7 +135,7 @@ const docTemplate = `{
                         "TokenAuth": []
                     }
                 ],
-                "description": "部署新的服务或更新现有服务配置，支持容器镜像、端口映射、环境变量、卷挂载等完整配置",
+                "description": "部署新的服务或更新现有服务配置，支持容器镜像、端口映射、环境变量、卷挂载等完整配置。\ndry_run 为 true（请求体字段或查询参数）时只校验请求、检查镜像是否可拉取和端口是否可用，返回 models.DeployPlan，不创建或修改任何容器\nasync 为 true 时立即返回 models.Deployment，部署或更新在后台执行，通过 GET /onedock/deployments/{id} 查询进度，避免拉取大镜像时请求超时",
                 "consumes": [
                     "application/json"
                 ],
@@ -111,6 +155,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ServiceRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "只预演，不部署",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "异步部署，立即返回部署ID",
+                        "name": "async",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，以相同的键重试时返回第一次成功的结果",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -185,9 +247,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/onedock/ping": {
+        "/onedock/audit": {
             "get": {
-                "description": "用于检查 OneDock 服务的健康状态和连通性，返回服务状态信息",
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "查询修改类 API 调用（POST、PUT、PATCH、DELETE）的审计记录：操作者、时间、请求体（敏感字段已脱敏）和结果，按时间倒序返回。记录保留 audit.retention_days 天",
                 "consumes": [
                     "application/json"
                 ],
@@ -195,12 +264,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "系统监控"
+                    "服务管理"
+                ],
+                "summary": "查询审计日志",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作者",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HTTP 方法",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "请求 ID（响应头 X-Request-ID）",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "只返回失败的请求",
+                        "name": "failed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间（RFC3339）",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间（RFC3339）",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "返回条数，默认 50，最多 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "跳过的条数",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "健康检查",
                 "responses": {
                     "200": {
-                        "description": "服务正常运行",
+                        "description": "查询成功",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -208,40 +335,16 @@ const docTemplate = `{
                                     "type": "integer"
                                 },
                                 "data": {
-                                    "type": "object"
+                                    "$ref": "#/definitions/models.AuditList"
                                 },
                                 "msg": {
                                     "type": "string"
                                 }
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/onedock/proxy/stats": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": [],
-                        "QueryAuth": [],
-                        "TokenAuth": []
-                    }
-                ],
-                "description": "获取所有端口代理的统计信息，包括单副本代理和负载均衡器的详细状态",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "服务管理"
-                ],
-                "summary": "获取端口代理统计信息",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
+                    },
+                    "400": {
+                        "description": "请求参数错误",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -277,38 +380,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/onedock/{name}": {
+        "/onedock/auth/callback": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": [],
-                        "QueryAuth": [],
-                        "TokenAuth": []
-                    }
-                ],
-                "description": "根据服务名称获取服务的详细信息，包括配置、状态等",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "身份提供方登录完成后的回调地址（auth.oidc.redirect_url），校验 ID Token 并按用户组映射角色，写入会话 Cookie 后跳转回登录前的页面",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "服务管理"
+                    "系统监控"
                 ],
-                "summary": "获取指定服务详情",
+                "summary": "OIDC 登录回调",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "服务名称",
-                        "name": "name",
-                        "in": "path",
+                        "description": "授权码",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "登录请求的 state",
+                        "name": "state",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "获取成功",
+                        "description": "登录失败",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -316,7 +416,7 @@ const docTemplate = `{
                                     "type": "integer"
                                 },
                                 "data": {
-                                    "$ref": "#/definitions/models.Service"
+                                    "type": "object"
                                 },
                                 "msg": {
                                     "type": "string"
@@ -324,8 +424,33 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "请求参数错误",
+                    "302": {
+                        "description": "登录成功，跳转回登录前的页面"
+                    }
+                }
+            }
+        },
+        "/onedock/auth/login": {
+            "get": {
+                "description": "跳转到身份提供方（auth.oidc）登录，完成后回到 redirect 指定的本站路径。登录后浏览器携带会话 Cookie 访问接口和 Swagger UI，无需令牌",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "OIDC 登录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "登录后跳转的本站路径",
+                        "name": "redirect",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "未开启 OIDC 登录",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -341,8 +466,25 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "权限验证失败",
+                    "302": {
+                        "description": "跳转到身份提供方"
+                    }
+                }
+            }
+        },
+        "/onedock/auth/logout": {
+            "post": {
+                "description": "清除 OIDC 登录的会话 Cookie，令牌和 JWT 不受影响",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "退出登录",
+                "responses": {
+                    "200": {
+                        "description": "已退出",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -357,9 +499,50 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/onedock/auth/whoami": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "返回当前请求的操作者标识和角色，可用于确认令牌、JWT 或 OIDC 登录是否生效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "获取当前身份",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Identity"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     },
-                    "404": {
-                        "description": "服务未找到",
+                    "401": {
+                        "description": "权限验证失败",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -376,8 +559,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/onedock/batch": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": [],
@@ -385,7 +570,7 @@ const docTemplate = `{
                         "TokenAuth": []
                     }
                 ],
-                "description": "删除指定的服务及其所有相关容器和资源，操作不可逆",
+                "description": "按顺序部署或更新多个服务，返回每个服务的结果，单个服务失败不影响其他服务。批内服务名称和公共端口不能重复",
                 "consumes": [
                     "application/json"
                 ],
@@ -395,19 +580,30 @@ const docTemplate = `{
                 "tags": [
                     "服务管理"
                 ],
-                "summary": "删除指定服务",
+                "summary": "批量部署服务",
                 "parameters": [
+                    {
+                        "description": "服务配置列表",
+                        "name": "services",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ServiceRequest"
+                            }
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "服务名称",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "description": "幂等键，以相同的键重试时返回第一次成功的结果",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "删除成功",
+                        "description": "批量部署完成",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -415,7 +611,24 @@ const docTemplate = `{
                                     "type": "integer"
                                 },
                                 "data": {
-                                    "type": "object"
+                                    "type": "object",
+                                    "properties": {
+                                        "Failed": {
+                                            "type": "integer"
+                                        },
+                                        "Results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.BatchDeployResult"
+                                            }
+                                        },
+                                        "Succeeded": {
+                                            "type": "integer"
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
                                 },
                                 "msg": {
                                     "type": "string"
@@ -456,15 +669,67 @@ const docTemplate = `{
                                 }
                             }
                         }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "code": {
-                                    "type": "integer"
-                                },
+                    }
+                }
+            }
+        },
+        "/onedock/cronjobs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取全部定时任务及其下次触发时间、运行中的任务",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务管理"
+                ],
+                "summary": "列出定时任务",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "CronJobs": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CronJob"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
                                 "data": {
                                     "type": "object"
                                 },
@@ -475,9 +740,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/onedock/{name}/scale": {
+            },
             "post": {
                 "security": [
                     {
@@ -486,7 +749,7 @@ const docTemplate = `{
                         "TokenAuth": []
                     }
                 ],
-                "description": "调整指定服务的副本数量，支持扩容和缩容操作，实际创建或删除容器实例",
+                "description": "按 cron 表达式周期性运行一次性任务，同名定时任务已存在时覆盖配置。concurrency_policy 决定上一次运行未结束时的处理：allow 同时运行、forbid 跳过（默认）、replace 取消上一次",
                 "consumes": [
                     "application/json"
                 ],
@@ -494,30 +757,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "服务管理"
+                    "任务管理"
                 ],
-                "summary": "服务扩缩容",
+                "summary": "创建或更新定时任务",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "服务名称",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "扩缩容配置",
-                        "name": "scale",
+                        "description": "定时任务配置",
+                        "name": "cronjob",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ScaleRequest"
+                            "$ref": "#/definitions/models.CronJobRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "扩缩容成功",
+                        "description": "保存成功",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -525,7 +781,7 @@ const docTemplate = `{
                                     "type": "integer"
                                 },
                                 "data": {
-                                    "type": "object"
+                                    "$ref": "#/definitions/models.CronJob"
                                 },
                                 "msg": {
                                     "type": "string"
@@ -566,9 +822,76 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/onedock/cronjobs/{cronjob}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取定时任务配置及最近的运行记录（含退出码和日志）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务管理"
+                ],
+                "summary": "获取定时任务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "定时任务名称",
+                        "name": "cronjob",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.CronJob"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     },
-                    "500": {
-                        "description": "服务器内部错误",
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -585,10 +908,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/onedock/{name}/status": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": [],
@@ -596,7 +917,7 @@ const docTemplate = `{
                         "TokenAuth": []
                     }
                 ],
-                "description": "获取指定服务的详细运行状态，包括副本信息、健康状态、实例详情等",
+                "description": "删除定时任务及其运行记录，运行中的任务会被取消",
                 "consumes": [
                     "application/json"
                 ],
@@ -604,21 +925,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "服务管理"
+                    "任务管理"
                 ],
-                "summary": "获取服务运行状态",
+                "summary": "删除定时任务",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "服务名称",
-                        "name": "name",
+                        "description": "定时任务名称",
+                        "name": "cronjob",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "获取成功",
+                        "description": "删除成功",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -626,7 +947,7 @@ const docTemplate = `{
                                     "type": "integer"
                                 },
                                 "data": {
-                                    "$ref": "#/definitions/models.ServiceStatusResponse"
+                                    "type": "object"
                                 },
                                 "msg": {
                                     "type": "string"
@@ -667,9 +988,76 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/onedock/cronjobs/{cronjob}/run": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "不等待调度时间立即运行一次，同样遵循并发策略；暂停的定时任务也可以手动触发",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务管理"
+                ],
+                "summary": "立即触发定时任务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "定时任务名称",
+                        "name": "cronjob",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "触发成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Job"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     },
-                    "404": {
-                        "description": "服务未找到",
+                    "401": {
+                        "description": "权限验证失败",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -687,303 +1075,13693 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ScaleRequest": {
-            "description": "服务扩缩容请求参数",
-            "type": "object",
-            "required": [
-                "replicas"
-            ],
-            "properties": {
-                "replicas": {
-                    "type": "integer",
-                    "example": 3
-                }
-            }
         },
-        "models.Service": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "svc_1234567890"
-                },
-                "image": {
-                    "type": "string",
-                    "example": "nginx"
-                },
-                "internal_port": {
-                    "type": "integer",
-                    "example": 80
-                },
-                "name": {
-                    "type": "string",
-                    "example": "nginx-web"
-                },
-                "public_port": {
-                    "type": "integer",
+        "/onedock/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按服务列表，或按命名空间和标签选择器（如 env=preview,team）删除多个服务，适合清理临时环境。\nconfirm 不为 true 时只返回将被删除的服务（Services），不做任何修改；确认后逐个删除，单个服务失败不影响其他服务；trash 不填时取配置 trash.default",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "批量删除服务",
+                "parameters": [
+                    {
+                        "description": "删除范围",
+                        "name": "delete",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkDeleteRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，以相同的键重试时返回第一次成功的结果",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "预览或删除完成",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Confirmed": {
+                                            "type": "boolean"
+                                        },
+                                        "Failed": {
+                                            "type": "integer"
+                                        },
+                                        "Results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.BulkDeleteResult"
+                                            }
+                                        },
+                                        "Services": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        },
+                                        "Succeeded": {
+                                            "type": "integer"
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/deployments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按提交时间倒序列出通过 async=true 提交的部署，可按服务过滤",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "列出异步部署",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "返回条数，默认 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Deployments": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Deployment"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/deployments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取异步部署的状态（pending / running / succeeded / failed）、当前阶段（pulling / creating / updating）、副本进度和失败原因",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取异步部署进度",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "部署ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Deployment"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "部署不存在",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "查询部署、滚动更新、更新失败、扩缩容、删除、副本崩溃、崩溃循环、自动扩缩容、端口代理重启等事件，按时间倒序返回。事件保留 events.retention_days 天",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "查询服务事件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "deployed",
+                            "updated",
+                            "update_failed",
+                            "scaled",
+                            "deleted",
+                            "restored",
+                            "replica_crashed",
+                            "replica_restarted",
+                            "replica_recreated",
+                            "crash_loop",
+                            "autoscaled",
+                            "proxy_restarted"
+                        ],
+                        "type": "string",
+                        "description": "事件类型",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间（RFC3339）",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间（RFC3339）",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "返回条数，默认 50，最多 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "跳过的条数",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.EventList"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/events/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "以 Server-Sent Events 推送服务生命周期事件（事件名 event，数据为事件 JSON），以及只在事件流中出现的端口代理启动、停止事件，客户端无需轮询服务列表。只推送连接之后发生的事件，断线期间的生命周期事件可通过 /onedock/events 查询。客户端读取过慢时丢弃事件",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "实时推送服务事件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "只推送该服务的事件",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "只推送这些类型的事件，多个类型用逗号分隔",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "事件流，每个 SSE 事件一条服务事件",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/gitops": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取 gitops 配置的仓库、分支，以及最近一次同步的提交、时间、错误和各服务的操作结果",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取 GitOps 同步状态",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.GitOpsStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/gitops/sync": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "拉取 gitops.repo 的最新提交，部署仓库中新增的服务、滚动更新配置有变化的服务、调整副本数有变化的服务；开启 gitops.prune 时删除仓库中已移除的服务。任一配置文件无效时不执行任何操作",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "立即执行 GitOps 同步",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "只返回将要执行的操作，不修改任何服务",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "同步完成，各服务的错误见 services[].error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.GitOpsStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "未配置 GitOps、拉取仓库或解析配置失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "一次请求获取服务 → 副本 → 端口 → 代理统计，代替逐个服务调用状态和代理统计接口。只支持 query 操作，对象字段与 REST 接口返回的 JSON 字段同名。\n根字段：services(namespace, label, image, status, port) 的条件与搜索接口相同；service(name) 获取单个服务。Service 额外提供 instances（副本）和 proxies（端口代理统计），Instance 额外提供 ports（各公共端口及该副本在代理中的后端统计）。\n支持别名、片段、变量和 @include / @skip 指令，查询可用 __typename。响应为标准 GraphQL 格式（data / errors），不使用其他接口的 code / msg 包装：语法或校验错误时 data 为 null；单个字段出错时该字段为 null，错误带 path 列在 errors 中。\n接口只读取数据，只读令牌可以使用 POST 调用；限定服务范围的令牌只能查询范围内的服务。也可以用 GET 调用，参数为 query、operationName 和 JSON 格式的 variables",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "只读 GraphQL 查询",
+                "parameters": [
+                    {
+                        "description": "GraphQL 请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "执行结果",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "请求格式错误",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按提交时间倒序列出最近的任务（不含日志）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务管理"
+                ],
+                "summary": "列出任务",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "返回条数，默认 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Jobs": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Job"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "运行一个容器直到结束（数据库迁移、批处理等），接口立即返回任务ID，通过任务详情接口查看状态、退出码和日志。容器不映射端口、不自动重启，结束后自动删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务管理"
+                ],
+                "summary": "提交一次性任务",
+                "parameters": [
+                    {
+                        "description": "任务配置",
+                        "name": "job",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.JobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "提交成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Job"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/jobs/{job}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取任务状态、退出码和容器输出",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务管理"
+                ],
+                "summary": "获取任务详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务ID",
+                        "name": "job",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Job"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除已结束的任务记录，运行中的任务需先取消",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务管理"
+                ],
+                "summary": "删除任务记录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务ID",
+                        "name": "job",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/jobs/{job}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "取消运行中的任务，容器会被强制删除，任务状态为 cancelled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "任务管理"
+                ],
+                "summary": "取消任务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务ID",
+                        "name": "job",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "取消成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/namespaces": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "列出所有有服务的命名空间及其服务数、副本总数。非默认命名空间的服务全名为 命名空间.名称，可直接用于其他服务接口的路径",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "列出命名空间",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Namespaces": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.NamespaceInfo"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/networks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "列出 OneDock 创建的网络和受管服务加入的网络（如 bridge），以及加入每个网络的服务和副本（包括已停止的副本），按名称排序；all=true 时列出全部 Docker 网络",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "网络管理"
+                ],
+                "summary": "列出网络",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "列出全部 Docker 网络",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.Network"
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "创建带 OneDock 管理标签的 Docker 网络，默认 bridge 驱动，子网和网关不填时由 Docker 分配；同名网络已存在时返回 CONFLICT",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "网络管理"
+                ],
+                "summary": "创建网络",
+                "parameters": [
+                    {
+                        "description": "网络配置",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NetworkCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Network"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/networks/{network}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除 OneDock 创建的网络。不是 OneDock 创建的网络不能删除；仍有受管副本（包括已停止的副本）或其他容器加入时返回 CONFLICT",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "网络管理"
+                ],
+                "summary": "删除网络",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "网络名称",
+                        "name": "network",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/overview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "一次返回服务按状态统计、副本数不足的服务、未在运行的副本、端口占用、运行中的端口代理以及最近 24 小时的故障事件，适合监控面板使用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取全局运行概况",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Overview"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "获取失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/ping": {
+            "get": {
+                "description": "用于检查 OneDock 服务的健康状态和连通性，返回服务状态信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "健康检查",
+                "responses": {
+                    "200": {
+                        "description": "服务正常运行",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/ports": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "列出服务的公共端口（及端口代理是否运行）、每个副本映射到宿主机的端口（所属服务、副本编号、容器）和预留端口，按端口排序；并给出 ports.auto_range 内的空闲端口范围。空闲范围按 OneDock 登记的端口计算，不探测主机上其他进程",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "端口管理"
+                ],
+                "summary": "列出使用中的主机端口",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.PortListing"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/ports/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "列出全部预留的公共端口，预留端口不能被其他服务部署使用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "端口管理"
+                ],
+                "summary": "列出端口预留",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.PortReservation"
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "为将来的服务预留公共端口。指定 service 时只有该服务可以部署到此端口，首次部署后预留转为分配；系统端口和已被使用的端口不能预留",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "端口管理"
+                ],
+                "summary": "预留公共端口",
+                "parameters": [
+                    {
+                        "description": "预留端口",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PortReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "预留成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.PortReservation"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/ports/reservations/{port}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "取消公共端口的预留，已分配给服务的端口不受影响",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "端口管理"
+                ],
+                "summary": "取消端口预留",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "公共端口",
+                        "name": "port",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "取消成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/proxy/reload": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "重新读取配置文件中的代理相关配置（负载均衡策略、读写超时）并应用到所有运行中的端口代理，不中断现有连接。也可以向进程发送 SIGHUP 触发",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "热加载代理配置",
+                "responses": {
+                    "200": {
+                        "description": "重新加载成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/proxy/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取所有端口代理的统计信息：单副本代理和负载均衡器的数量，以及每个端口代理的监听地址、负载均衡策略、请求数、失败数和各后端（副本）的统计，字段与 /onedock/{name}/proxy 相同",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取端口代理统计信息",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ProxyOverview"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/quotas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "列出配置文件中的命名空间配额与令牌配额，以及当前的服务数、副本总数和内存总量。令牌以指纹显示，令牌配额按服务所有者（首次部署该服务的令牌）统计",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "列出配额及使用量",
+                "responses": {
+                    "200": {
+                        "description": "读取服务状态失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/reconcile": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按期望状态调和所有服务：补齐缺失副本、移除多余副本、启动意外停止的副本并恢复端口代理。已停止或正在滚动更新的服务会被跳过",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "立即执行调和",
+                "responses": {
+                    "200": {
+                        "description": "调和完成",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.ReconcileResult"
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/scale": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "一次调整多个服务的副本数，副本数为 0 表示删除服务（配置了 trash.default 时放入回收站）。先检查全部服务（是否存在、锁定、发布进行中、令牌服务范围），\n任一服务不满足条件时整批都不执行（Applied 为 false）；检查通过后先缩容后扩容逐个执行，单个服务失败不影响其他服务",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "批量扩缩容",
+                "parameters": [
+                    {
+                        "description": "服务名称到目标副本数的映射",
+                        "name": "scale",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkScaleRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，以相同的键重试时返回第一次成功的结果",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "批量扩缩容完成",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Applied": {
+                                            "type": "boolean"
+                                        },
+                                        "Failed": {
+                                            "type": "integer"
+                                        },
+                                        "Results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.BulkScaleResult"
+                                            }
+                                        },
+                                        "Succeeded": {
+                                            "type": "integer"
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按用户标签、镜像、状态、端口和命名空间筛选服务，条件之间为且的关系，结果按名称排序；令牌限定了服务范围时只返回范围内的服务。\nlabel 为标签选择器：逗号分隔的 key=value 或 key（只要求存在该标签），可重复传入；image 匹配镜像名称或仓库路径的最后一段，可带标签（nginx:alpine）；port 匹配公共端口或内部端口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "搜索服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "标签选择器",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "镜像名称",
+                        "name": "image",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "stopped",
+                            "starting",
+                            "running",
+                            "stopping",
+                            "failed",
+                            "updating"
+                        ],
+                        "type": "string",
+                        "description": "服务状态",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "公共端口或内部端口",
+                        "name": "port",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "命名空间",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "搜索成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Services": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Service"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/secrets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取全部密钥名称及引用它们的服务，不返回密钥值",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "密钥管理"
+                ],
+                "summary": "列出密钥",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Secrets": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Secret"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/secrets/{secret}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "加密保存密钥（AES-GCM，密钥由 secret.key 配置派生），服务环境变量写成 secret://\u003c名称\u003e 即可在创建容器时注入。更新后已运行的副本在重新创建时才使用新值",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "密钥管理"
+                ],
+                "summary": "创建或更新密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "密钥名称",
+                        "name": "secret",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "密钥值",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "保存成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Secret"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除指定密钥，仍被服务引用时拒绝删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "密钥管理"
+                ],
+                "summary": "删除密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "密钥名称",
+                        "name": "secret",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/system/backup": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "将所有服务的期望状态、部署历史、端口登记与预留、密钥（保持 secret.key 加密）、服务模板、定时与自动扩缩容、定时任务、服务锁、镜像更新监视及全局代理配置打包为 tar.gz 归档，用于灾难恢复和迁移主机",
+                "produces": [
+                    "application/gzip"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "下载全量备份",
+                "responses": {
+                    "200": {
+                        "description": "tar.gz 备份归档",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "备份失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/system/cleanup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "查找并删除带管理前缀的孤立容器：名称无法解析、服务已删除（仅已停止的容器）、公共端口与期望状态不一致、副本编号重复（保留运行中、符合期望配置或最新的一个）。正在滚动更新的服务会被跳过",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "清理孤立容器",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "只列出孤立容器，不删除",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "清理完成",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.CleanupResult"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/system/config": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "返回可在运行时修改的配置的当前值：端口映射缓存时间、默认负载均衡策略、公共端口范围、端口代理超时和日志级别，以及修改写回的配置文件",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "获取运行时配置",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.RuntimeConfig"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "只修改提交的字段，写回配置文件（保留注释和其他配置）后立即生效，无需重启：默认负载均衡策略和代理超时应用到运行中的端口代理（通过接口为服务单独设置的策略不受影响），日志级别立即切换，缓存时间和端口范围在下次使用时生效。仅对 admin 角色的令牌开放",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "修改运行时配置",
+                "parameters": [
+                    {
+                        "description": "要修改的配置",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RuntimeConfigUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功，返回修改后的配置",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.RuntimeConfig"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或配置文件写入失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/system/df": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "与 docker system df -v 相当：按镜像、容器、卷和构建缓存汇总占用与可回收空间，并列出每个镜像、容器和命名卷的占用（按占用从大到小排序），标出所属的受管服务。OneDock 能访问 Docker 数据目录时还返回所在文件系统的容量和可用空间。统计需要遍历镜像层和卷，镜像和卷较多时可能需要数秒",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "获取 Docker 磁盘占用详情",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.SystemDiskUsage"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "获取失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/system/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "请求体为 GET /onedock/system/backup 下载的归档。仅能在没有受管服务的主机上执行：用备份替换本机的全部状态，再执行一轮调和，按期望状态创建副本并启动端口代理。备份中有密钥时本机 secret.key 必须与备份时一致",
+                "consumes": [
+                    "application/gzip"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "恢复全量备份",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "只校验归档并返回将恢复的内容和警告",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "恢复完成",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.RestoreResult"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "归档无效、密钥不匹配或主机上已有服务",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/system/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "返回 Docker 守护进程是否可达及其版本、镜像/容器/卷的磁盘占用、受管公共端口使用情况和 OneDock 进程运行时长。Docker 不可达时仍返回成功，原因见 docker.error",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "获取系统状态",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.SystemStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "获取失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取全部服务模板及其使用的变量",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务模板"
+                ],
+                "summary": "列出服务模板",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Templates": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ServiceTemplate"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "保存带 ${VAR} 占位符的服务配置模板，同名模板已存在时覆盖。${VAR:-默认值} 表示可选变量",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务模板"
+                ],
+                "summary": "创建或更新服务模板",
+                "parameters": [
+                    {
+                        "description": "模板",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "保存成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceTemplate"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/templates/{template}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取指定服务模板",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务模板"
+                ],
+                "summary": "获取服务模板",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "模板名称",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceTemplate"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除指定服务模板，已部署的服务不受影响",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务模板"
+                ],
+                "summary": "删除服务模板",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "模板名称",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/templates/{template}/deploy": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "用变量值渲染模板生成服务配置并部署，服务已存在时执行滚动更新。缺少没有默认值的变量时返回错误",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务模板"
+                ],
+                "summary": "从模板部署服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "模板名称",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "变量值",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TemplateDeployRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "部署成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "列出通过接口创建的访问令牌及其状态，不返回令牌明文；配置文件中的令牌不在列表中。仅管理员令牌可用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "令牌管理"
+                ],
+                "summary": "列出访问令牌",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Tokens": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.APIToken"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "创建带角色和有效期的访问令牌，仅管理员令牌可用。服务端只保存令牌摘要，明文只在本次响应中返回，请妥善保存",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "令牌管理"
+                ],
+                "summary": "创建访问令牌",
+                "parameters": [
+                    {
+                        "description": "令牌名称、角色和有效期",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.APITokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.APIToken"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/tokens/{id}/expire": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "不传 expires_at 时令牌立即过期；传入将来的时间可在轮换时给旧令牌留出切换时间。仅管理员令牌可用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "令牌管理"
+                ],
+                "summary": "设置访问令牌过期时间",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "令牌 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "过期时间",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.APITokenExpireRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.APIToken"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/tokens/{id}/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "令牌立即失效且不可恢复。仅管理员令牌可用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "令牌管理"
+                ],
+                "summary": "吊销访问令牌",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "令牌 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "吊销成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.APIToken"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "列出以 trash=true 删除、仍在保留期内的服务，最近删除的在前，保留期（trash.retention_days）过后自动清除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "列出回收站中的服务",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Services": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TrashedService"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/trash/{name}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "不等保留期结束，立即删除保留的容器和保存的配置，并释放为服务保留的公共端口，操作不可逆",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "从回收站中彻底删除服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "服务不在回收站中",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/trash/{name}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按删除时的配置、副本数和公共端口恢复服务。删除时保留了容器的，改回原名称并启动；否则重新部署。同名服务已存在时返回 CONFLICT",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "从回收站恢复服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "恢复成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "服务不在回收站中",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/validate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "静态检查部署请求：服务名称格式、端口范围和占用、镜像引用格式、卷挂载路径、环境变量文件是否可读、密钥和占位符引用等，返回错误和警告，不访问镜像仓库，不创建或修改任何容器。校验不通过时仍返回 200，valid 为 false",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "校验服务配置",
+                "parameters": [
+                    {
+                        "description": "服务配置",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "校验完成",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ValidationReport"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求体不是合法的 JSON",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/version": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "返回 OneDock 版本、git 提交、构建时间（构建时通过 ldflags 注入）、Go 版本，以及与 Docker 守护进程协商后使用的 API 版本。Docker 不可达时仍返回成功，原因见 docker_error",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "获取版本信息",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.VersionInfo"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/volumes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按命名卷名称和主机目录路径汇总受管副本（包括已停止的副本）的挂载，列出使用它的服务、副本和磁盘占用，命名卷在前。\n命名卷的占用来自 Docker 磁盘统计；主机目录的占用由 OneDock 遍历目录统计，OneDock 无法访问该目录或 10 秒内未统计完时 size 为 -1，原因记录在 error 中。path 可用于查看单个主机目录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "卷管理"
+                ],
+                "summary": "列出服务使用的卷和主机目录",
+                "parameters": [
+                    {
+                        "enum": [
+                            "volume",
+                            "bind"
+                        ],
+                        "type": "string",
+                        "description": "只列出该类型",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "只列出该服务使用的卷",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "只列出该主机路径",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "同时列出没有受管服务使用的命名卷",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.Volume"
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/volumes/{volume}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取命名卷的驱动、挂载点、磁盘占用以及挂载它的服务和副本",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "卷管理"
+                ],
+                "summary": "获取命名卷",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "卷名称",
+                        "name": "volume",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Volume"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "卷不存在",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除命名卷及其中的数据。仍有受管副本（包括已停止的副本）或其他容器挂载该卷时拒绝删除（CONFLICT）；主机目录不通过 OneDock 删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "卷管理"
+                ],
+                "summary": "删除命名卷",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "卷名称",
+                        "name": "volume",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取全部事件订阅及最近一次投递结果，不返回签名密钥",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "事件订阅"
+                ],
+                "summary": "列出事件订阅",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Total": {
+                                            "type": "integer"
+                                        },
+                                        "Webhooks": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Webhook"
+                                            }
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "服务生命周期事件（部署、更新、扩缩容、删除、副本崩溃等）发生时，以 JSON POST 到订阅的地址，外部系统无需轮询。同名订阅已存在时覆盖配置，包括签名密钥。\n请求带 X-OneDock-Event（事件类型）和 X-OneDock-Webhook（订阅名称）请求头；设置了 secret 时还带 X-OneDock-Signature: sha256=\u003c请求体的 HMAC-SHA256\u003e。签名密钥以 secret.key 加密保存，设置密钥前需配置 secret.key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "事件订阅"
+                ],
+                "summary": "创建或更新事件订阅",
+                "parameters": [
+                    {
+                        "description": "订阅配置",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "保存成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Webhook"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/webhooks/{webhook}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取事件订阅配置及最近一次投递的状态码、错误和时间，不返回签名密钥",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "事件订阅"
+                ],
+                "summary": "获取事件订阅",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订阅名称",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Webhook"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除指定事件订阅，之后的事件不再投递",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "事件订阅"
+                ],
+                "summary": "删除事件订阅",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订阅名称",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "根据服务名称获取服务的详细信息，包括配置、状态等",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取指定服务详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "服务未找到",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除指定的服务及其所有相关容器和资源。trash 为 true（未指定时取配置 trash.default）时放入回收站：保存删除时的配置并为服务保留公共端口，\n保留期（trash.retention_days）内可通过 POST /onedock/trash/{name}/restore 恢复；keep_containers 为 true 时停止并保留容器，恢复时直接启动",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "删除指定服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "放入回收站，不填则取配置 trash.default",
+                        "name": "trash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "放入回收站时停止并保留容器（隐含 trash=true）",
+                        "name": "keep_containers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，以相同的键重试时返回第一次成功的结果",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功，放入回收站时返回回收站记录，否则 data 为空对象",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.TrashedService"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/autoscale": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取服务的自动扩缩容策略，以及最近一次评估的平均使用率、请求速率、期望副本数和结论",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取自动扩缩容状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.AutoscaleStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "定期采集副本的 CPU、内存使用率及端口代理的请求速率、并发请求数，按 ceil(当前副本数 × 当前值 / 目标值) 在最小、最大副本数之间自动调整副本数，多个目标取最大值。扩容、缩容分别有冷却时间，已存在的策略会被覆盖",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "设置自动扩缩容策略",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "自动扩缩容策略",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AutoscalePolicy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.AutoscaleStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "关闭服务的自动扩缩容，当前副本数保持不变",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "删除自动扩缩容策略",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/blue-green": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取服务进行中的蓝绿更新（部署请求中 deployment_mode=blue_green 发起），返回新旧两组副本的镜像、副本数以及新版本是否已接收流量",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取蓝绿更新状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ReleaseStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/blue-green/abort": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除尚未接收流量的新版本副本，旧版本副本继续提供服务",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "中止蓝绿更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "中止成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/blue-green/promote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "端口代理切换到新版本副本，然后删除旧版本副本并更新期望状态，记录为一次更新。旧副本删除失败时可再次调用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "确认蓝绿更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "切换成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/canary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取服务进行中的金丝雀发布，分别返回当前版本与金丝雀版本的镜像和副本数",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取金丝雀发布状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ReleaseStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按新配置额外创建 canary_replicas 个金丝雀副本，与现有副本一起由端口代理负载均衡。期望状态不变，结束前不能更新或扩缩容该服务",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "发起金丝雀发布",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新版本服务配置与金丝雀副本数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CanaryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "发起成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ReleaseStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/canary/abort": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除金丝雀副本，服务保持原配置",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "中止金丝雀发布",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "中止成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/canary/promote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除金丝雀副本，并将现有副本滚动更新为金丝雀版本的配置，记录为一次更新",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "确认金丝雀发布",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "确认成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "复制现有服务的配置部署为新服务，可覆盖镜像标签、环境变量、公共端口和副本数，用于快速创建评审或预发副本。新服务不能已存在，不填公共端口时自动分配，部署受端口登记和配额限制",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "克隆服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "原服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新服务名称与覆盖的配置",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CloneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "克隆成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/diff": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "比较请求中的服务配置与运行中的服务，逐项返回镜像、环境变量、卷挂载、命令和端口等的变化，不做任何修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "比较服务配置差异",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新的服务配置",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "比较成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ConfigDiff"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/drift": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "比较期望状态与实际运行的容器（镜像标签、配置哈希、副本数、运行状态），只报告差异，不做任何修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取服务漂移报告",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.DriftReport"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/env": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "返回副本实际运行时的环境变量：环境变量文件（在 OneDock 所在主机上读取）与 environment 合并、占位符替换后的结果，并标注每个变量来自 env_file、environment 还是镜像。\n引用密钥的变量显示为 secret://\u003c名称\u003e，不返回明文。副本创建后修改了配置或环境变量文件时，不同的变量带 differs 和按当前配置将使用的 expected 值，重建副本后生效。服务没有副本时只返回配置中的变量",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取服务实际生效的环境变量",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "副本编号，不填则使用编号最小的副本",
+                        "name": "replica",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceEnvironment"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "服务或副本不存在",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "返回该服务最近的生命周期事件（部署、更新、扩缩容、副本崩溃、代理重启等），按时间倒序。服务删除后仍可查询保留期（events.retention_days）内的事件",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取服务最近的事件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "只返回这些类型的事件，多个类型用逗号分隔",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "返回条数，默认 20，最多 500",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.EventList"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "将服务的生效配置导出为 docker-compose YAML（format=compose，默认），或 OneDock 服务配置（format=spec，可直接用于部署接口重新导入）。名称像密码、令牌的明文环境变量导出为 ******，密钥引用原样导出",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-yaml"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "导出服务配置",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "compose",
+                            "spec"
+                        ],
+                        "type": "string",
+                        "default": "compose",
+                        "description": "导出格式：compose / spec",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "导出成功（format=spec）；format=compose 时直接返回 YAML 文本",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceRequest"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取服务的部署/更新/回滚记录，包含每次的配置快照、时间和操作者，按版本号倒序",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取服务部署历史",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Revisions": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DeploymentRevision"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/image-watch": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取服务的镜像更新监视配置，以及最近一次检查发现的新标签或摘要、是否有可用更新和错误信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取镜像更新监视状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ImageWatchStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "定期查询镜像仓库：digest 方式检查当前标签是否指向了新的镜像，semver 方式检查是否发布了更新的版本标签（可用 pattern 正则限定范围，如 ^1\\.25\\.）。action 为 notify 时只记录结果，为 redeploy 时自动滚动更新服务。已存在的监视会被覆盖",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "设置镜像更新监视",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "镜像更新监视",
+                        "name": "watch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImageWatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ImageWatchStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "停止检查服务的镜像更新，不影响运行中的副本",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "删除镜像更新监视",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/image-watch/check": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "不等待检查间隔，立即查询镜像仓库并返回检查结果；action 为 redeploy 且发现更新时会同步完成滚动更新",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "立即检查镜像更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "检查完成",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ImageWatchStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/lock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "锁定后只有管理员令牌（auth.admin_tokens）可以部署、更新、回滚、扩缩容、启停或删除该服务，定时扩缩容与自动扩缩容暂停。请求体可选",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "锁定服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "锁定原因",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.LockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "锁定成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceLock"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/logs/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "合并跟踪服务全部副本的日志，以 Server-Sent Events 推送（事件名 log，数据为带副本名称的 JSON）；format=text 时输出 \"副本名称 | 日志\" 格式的纯文本，便于 curl 使用。扩容、滚动更新或重启后的副本会自动加入，客户端断开后停止",
+                "produces": [
+                    "text/event-stream",
+                    "text/plain"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "实时跟踪服务日志",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "100",
+                        "description": "每个副本先输出的历史行数，all 表示全部",
+                        "name": "tail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "只输出该时间之后的日志：RFC3339 时间或相对时长",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sse",
+                            "text"
+                        ],
+                        "type": "string",
+                        "default": "sse",
+                        "description": "输出格式",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "日志流，每个事件一行日志",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogLine"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或服务不存在",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/operations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "同一服务的部署、更新、回滚、扩缩容、启停、发布和副本操作逐个执行，返回正在执行和排队等待的操作。排队超过 container.operation_queue_timeout 秒的操作返回失败",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取服务操作队列",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.OperationQueue"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/proxy": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "只返回指定服务的端口代理：监听地址、代理类型、负载均衡策略、端口累计请求数和失败数，以及每个后端（副本）的连接数、请求数、失败数。服务没有公共端口或已停止时 proxies 为空",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取服务的端口代理统计信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceProxyStats"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "服务未找到",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/proxy/strategy": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "运行时切换服务公共端口的负载均衡策略，立即生效，无需重建代理，正在处理的请求不受影响。设置的策略在扩缩容、更新重建代理和热加载配置后保留，服务删除或 OneDock 重启后恢复为配置文件中的默认策略；strategy 为空表示立即恢复默认策略",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "设置服务的负载均衡策略",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "负载均衡策略",
+                        "name": "strategy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProxyStrategyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceProxyStats"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "服务未找到",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/rename": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按新名称逐个替换副本容器（容器名称和标签），公共端口与端口代理保持不变，期望状态、部署历史、扩缩容规则、锁和端口登记迁移到新名称。已停止、正在滚动更新或发布中的服务不能改名，任一副本失败时恢复旧名称",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "重命名服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新名称",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RenameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "重命名成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/replicas/{index}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取服务单个副本的检查详情（docker inspect）：环境变量、挂载、网络、运行状态、重启次数和健康检查记录，比服务状态中的实例摘要更完整。期望状态中引用密钥的环境变量显示为 secret://\u003c名称\u003e，不返回明文",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取副本详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "副本编号",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ReplicaDetail"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "副本不存在",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/replicas/{index}/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "将副本从端口代理的负载均衡中摘除，容器继续运行，便于在没有流量的情况下调试单个实例；正在处理的请求不受影响。停用状态在扩缩容、更新重建代理后保留，副本重建、服务删除或 OneDock 重启后恢复。全部副本停用时代理返回 503",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "停用副本的代理后端",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "副本编号",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "停用成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceProxyStats"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "副本不存在或服务没有公共端口",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/replicas/{index}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "恢复停用的副本，重新参与端口代理的负载均衡",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "启用副本的代理后端",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "副本编号",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "启用成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceProxyStats"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "副本不存在或服务没有公共端口",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/replicas/{index}/recreate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除服务的一个副本并按期望状态重新创建，副本编号不变，用于处理卡在异常状态的副本。默认先删除旧副本再创建；surge=true 时先创建并验证新副本再删除旧副本，期间不减少可用副本。服务已停止、正在滚动更新或金丝雀/蓝绿发布期间拒绝操作",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "重建单个副本",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "副本编号",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "先创建新副本再删除旧副本",
+                        "name": "surge",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "重建成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "副本不存在、服务状态不允许重建或新副本启动失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/replicas/{index}/restart": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "按副本编号重启服务的一个副本（连同边车容器），其余副本继续提供服务，重启后刷新端口代理。服务已停止、正在滚动更新或金丝雀/蓝绿发布期间拒绝操作",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "重启单个副本",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "副本编号",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "重启成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "副本不存在或服务状态不允许重启",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/replicas/{index}/terminal": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "通过 WebSocket 连接到副本中的交互式命令（docker exec -it）。服务端以二进制帧发送终端输出；客户端以二进制帧发送原始输入，或以文本帧发送 JSON 控制消息：{\"type\":\"input\",\"data\":\"ls\\r\"}、{\"type\":\"resize\",\"rows\":40,\"cols\":120}。命令结束时服务端发送 {\"type\":\"exit\",\"code\":0} 后关闭连接。浏览器无法设置请求头，可使用 ?token= 进行权限验证；跨域页面需要加入 terminal.allowed_origins",
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "副本 Web 终端",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "副本编号",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "命令及参数（可重复），默认 terminal.command",
+                        "name": "cmd",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "初始行数",
+                        "name": "rows",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "初始列数",
+                        "name": "cols",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "切换到 WebSocket",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "副本不存在、未运行或终端已禁用",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "使用部署历史中指定版本的配置对服务执行滚动更新，不指定版本时回滚到上一个版本",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "回滚服务到历史版本",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "目标版本号，不填则回滚到上一个版本",
+                        "name": "revision",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "回滚成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/rollout": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取服务最近一次滚动更新的状态与进度（更新中、已暂停、已完成、失败或已中止）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取滚动更新状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.RolloutStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/rollout/abort": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "中止进行中或已暂停的滚动更新，已更新的副本将回滚到更新前的配置",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "中止滚动更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "中止成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.RolloutStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/rollout/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "暂停服务进行中的滚动更新，当前批次完成后生效。暂停期间端口代理会切换到新旧混合的副本，便于观察新版本表现",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "暂停滚动更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "暂停成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.RolloutStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/rollout/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "恢复已暂停的滚动更新，继续更新剩余副本",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "恢复滚动更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "恢复成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.RolloutStatus"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/scale": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "调整指定服务的副本数量，支持扩容和缩容操作，实际创建或删除容器实例",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "服务扩缩容",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "扩缩容配置",
+                        "name": "scale",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ScaleRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，以相同的键重试时返回第一次成功的结果",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "扩缩容成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取服务的定时扩缩容规则及下次、上次执行时间",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "列出定时扩缩容规则",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object",
+                                    "properties": {
+                                        "Schedules": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ScaleSchedule"
+                                            }
+                                        },
+                                        "Total": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "到达 cron 表达式（分 时 日 月 周，服务器本地时区）指定的时间时将服务调整到指定副本数，例如工作日 09:00 扩到 5 个副本、22:00 缩到 1 个副本。已停止的服务不执行，服务删除时规则一并删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "添加定时扩缩容规则",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "定时扩缩容规则",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ScaleScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "添加成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ScaleSchedule"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/schedules/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "删除服务的指定定时扩缩容规则，不影响当前副本数",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "删除定时扩缩容规则",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "规则 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/spec": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取持久化保存的服务完整配置（环境变量、卷挂载、副本数、端口等），扩容和回滚时按此配置创建副本",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取服务期望状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.DesiredState"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "启动通过 stop 接口停止的服务，恢复全部副本和端口代理",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "启动已停止的服务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "启动成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "通过 Docker stats 接口并发采样每个运行中副本的 CPU、内存、网络和块设备 I/O，并汇总全部副本。CPU 使用率为约 1 秒内的平均值，网络和块设备为容器启动以来的累计值；单个副本采样失败时在该副本的 error 中说明",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取服务的资源使用情况",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceStats"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "服务未找到",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "获取指定服务的详细运行状态，包括副本信息、健康状态、实例详情等",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "获取服务运行状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.ServiceStatusResponse"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "服务未找到",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/stop": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "停止服务的全部副本和端口代理，但保留容器及其配置、端口，之后可通过 start 接口立即恢复。与删除服务不同，此操作不会销毁容器",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "停止服务（保留容器）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "停止成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Service"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/onedock/{name}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": [],
+                        "QueryAuth": [],
+                        "TokenAuth": []
+                    }
+                ],
+                "description": "解除服务锁定，恢复部署、扩缩容和删除等操作",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务管理"
+                ],
+                "summary": "解除服务锁定",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "解除成功",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "权限验证失败",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "type": "object"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "检查 Docker 守护进程是否可达、状态存储和缓存是否可用、启动时的端口代理恢复是否完成，全部通过返回 200，否则返回 503，供负载均衡判断是否转发请求。不需要权限验证",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统监控"
+                ],
+                "summary": "就绪检查",
+                "responses": {
+                    "200": {
+                        "description": "已就绪",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Readiness"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "未就绪",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "integer"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/models.Readiness"
+                                },
+                                "msg": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dockerclient.VolumeMount": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "description": "容器内路径",
+                    "type": "string"
+                },
+                "readOnly": {
+                    "description": "是否只读挂载",
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "主机路径",
+                    "type": "string"
+                }
+            }
+        },
+        "graphql.Error": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "service nginx-web not found"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "graphql.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string",
+                    "example": "Topology"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "{ services { name instances { container_name } } }"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "graphql.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/graphql.Error"
+                    }
+                }
+            }
+        },
+        "models.APIToken": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "token:abcd****"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2024-01-16T08:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci-deploy"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "odk_3f9a"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2024-01-20T00:00:00Z"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "deployer"
+                },
+                "scope": {
+                    "$ref": "#/definitions/models.TokenScope"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "odk_3f9a..."
+                }
+            }
+        },
+        "models.APITokenExpireRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00Z"
+                }
+            }
+        },
+        "models.APITokenRequest": {
+            "description": "令牌明文只在创建时返回一次，服务端只保存摘要",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "expires_in": {
+                    "type": "integer",
+                    "example": 2592000
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci-deploy"
+                },
+                "namespaces": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "staging"
+                    ]
+                },
+                "role": {
+                    "type": "string",
+                    "example": "deployer"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "app-a",
+                        "staging.app-a"
+                    ]
+                }
+            }
+        },
+        "models.AuditList": {
+            "type": "object",
+            "properties": {
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditRecord"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 128
+                }
+            }
+        },
+        "models.AuditRecord": {
+            "description": "谁（操作者、令牌指纹、客户端 IP）在什么时间调用了哪个接口，请求体与结果",
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "token:abcd****"
+                },
+                "body": {
+                    "type": "string",
+                    "example": "{\"replicas\":3}"
+                },
+                "client_ip": {
+                    "type": "string",
+                    "example": "10.0.0.8"
+                },
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 1530
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/onedock/nginx-web/scale"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "8d3e6f0a-4b1c-4e2d-9a7b-1c2d3e4f5a6b"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "token_id": {
+                    "type": "string",
+                    "example": "3f2a9c1d0b7e"
+                }
+            }
+        },
+        "models.AutoscalePolicy": {
+            "description": "定期采集副本的资源使用率和端口代理的请求速率，按 ceil(当前副本数 × 当前值 / 目标值) 在最小、最大副本数之间调整，至少设置一个目标",
+            "type": "object",
+            "required": [
+                "max_replicas",
+                "min_replicas"
+            ],
+            "properties": {
+                "max_replicas": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 10
+                },
+                "min_replicas": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
+                },
+                "scale_down_cooldown": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "scale_up_cooldown": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "target_connections": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "target_cpu": {
+                    "type": "integer",
+                    "example": 70
+                },
+                "target_memory": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "target_rps": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "models.AutoscaleStatus": {
+            "description": "策略及最近一次评估的指标和结论",
+            "type": "object",
+            "properties": {
+                "active_requests": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "cpu_percent": {
+                    "type": "number",
+                    "example": 85.2
+                },
+                "current_replicas": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "desired_replicas": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "last_check_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "last_scale_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:25:00Z"
+                },
+                "memory_percent": {
+                    "type": "number",
+                    "example": 40.5
+                },
+                "message": {
+                    "type": "string",
+                    "example": "scaled from 3 to 4 replicas"
+                },
+                "policy": {
+                    "$ref": "#/definitions/models.AutoscalePolicy"
+                },
+                "requests_per_sec": {
+                    "type": "number",
+                    "example": 320.5
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                }
+            }
+        },
+        "models.BackupManifest": {
+            "description": "归档中的 manifest.json",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "format": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "hostname": {
+                    "type": "string",
+                    "example": "docker-01"
+                },
+                "secret_key_fingerprint": {
+                    "type": "string",
+                    "example": "3f2a9c1d0b7e4a55"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.BatchDeployResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "public port cannot be empty"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "service": {
+                    "$ref": "#/definitions/models.Service"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.BulkDeleteRequest": {
+            "description": "按服务列表，或按命名空间和标签选择器删除多个服务；confirm 不为 true 时只返回将被删除的服务 trash 不填时取配置 trash.default，为 true 时逐个放入回收站",
+            "type": "object",
+            "properties": {
+                "confirm": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "preview-42"
+                },
+                "selector": {
+                    "type": "string",
+                    "example": "env=preview,team"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "preview-42.web",
+                        "preview-42.api"
+                    ]
+                },
+                "trash": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.BulkDeleteResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "service preview-42.web not found"
+                },
+                "error_code": {
+                    "type": "string",
+                    "example": "SERVICE_NOT_FOUND"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "preview-42.web"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "trashed": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.BulkScaleRequest": {
+            "description": "一次调整多个服务的副本数，副本数为 0 表示删除服务，适合整体启停一个环境",
+            "type": "object",
+            "required": [
+                "services"
+            ],
+            "properties": {
+                "services": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.BulkScaleResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "service api is locked by token:abcd****, unlock it first"
+                },
+                "error_code": {
+                    "type": "string",
+                    "example": "SERVICE_LOCKED"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "previous": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "replicas": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.CanaryRequest": {
+            "description": "按新配置额外创建金丝雀副本，与现有副本一起接收流量；确认后调用 promote 全量更新，或调用 abort 删除金丝雀副本",
+            "type": "object",
+            "required": [
+                "image",
+                "internal_port",
+                "name",
+                "tag"
+            ],
+            "properties": {
+                "bind_address": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "canary_replicas": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "command": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "deployment_mode": {
+                    "type": "string",
+                    "enum": [
+                        "rolling",
+                        "blue_green"
+                    ],
+                    "example": "blue_green"
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "entrypoint": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "env_file": {
+                    "type": "string"
+                },
+                "environment": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx"
+                },
+                "init_containers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InitContainer"
+                    }
+                },
+                "internal_port": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "memory_limit": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 512
+                },
+                "mirror": {
+                    "$ref": "#/definitions/models.MirrorConfig"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "staging"
+                },
+                "proxy_workers": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 4
+                },
+                "public_port": {
+                    "type": "integer",
+                    "example": 30000
+                },
+                "replicas": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "request_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RequestRule"
+                    }
+                },
+                "sidecars": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Sidecar"
+                    }
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "alpine"
+                },
+                "update_strategy": {
+                    "$ref": "#/definitions/models.UpdateStrategy"
+                },
+                "volumes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VolumeMount"
+                    }
+                },
+                "working_dir": {
+                    "type": "string",
+                    "example": "/app"
+                }
+            }
+        },
+        "models.CleanupResult": {
+            "description": "dry_run 时只列出孤立容器，不做删除",
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "notes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "orphans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrphanContainer"
+                    }
+                },
+                "removed": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.CloneRequest": {
+            "description": "复制现有服务的配置部署为新服务，未填写的字段沿用原服务的配置",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "environment": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nginx-web-review"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "review"
+                },
+                "public_port": {
+                    "type": "integer",
+                    "example": 9310
+                },
+                "replicas": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "1.27"
+                }
+            }
+        },
+        "models.ConfigChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "environment.LOG_LEVEL"
+                },
+                "new": {
+                    "type": "string",
+                    "example": "debug"
+                },
+                "old": {
+                    "type": "string",
+                    "example": "info"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "changed"
+                }
+            }
+        },
+        "models.ConfigDiff": {
+            "description": "请求中的配置与运行中服务的差异，changed 为 false 时更新不会重建容器",
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConfigChange"
+                    }
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                }
+            }
+        },
+        "models.ContainerDiskUsage": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "a1b2c3d4e5f6"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx:alpine"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "onedock-nginx-web-9303-30000-0"
+                },
+                "replica": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "size_root_fs": {
+                    "type": "integer",
+                    "example": 53477376
+                },
+                "size_rw": {
+                    "type": "integer",
+                    "example": 1048576
+                },
+                "state": {
+                    "type": "string",
+                    "example": "running"
+                }
+            }
+        },
+        "models.CronJob": {
+            "description": "定时任务配置、调度状态和最近的运行记录",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "3f2a9c1b7d4e"
+                    ]
+                },
+                "concurrency_policy": {
+                    "type": "string",
+                    "example": "forbid"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "cron": {
+                    "type": "string",
+                    "example": "0 2 * * *"
+                },
+                "history_limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "job": {
+                    "$ref": "#/definitions/models.JobRequest"
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": "skipped: previous run 3f2a9c1b7d4e is still running"
+                },
+                "last_job_id": {
+                    "type": "string",
+                    "example": "3f2a9c1b7d4e"
+                },
+                "last_run_at": {
+                    "type": "string",
+                    "example": "2024-01-15T02:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nightly-report"
+                },
+                "next_run_at": {
+                    "type": "string",
+                    "example": "2024-01-16T02:00:00Z"
+                },
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Job"
+                    }
+                },
+                "suspended": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                }
+            }
+        },
+        "models.CronJobRequest": {
+            "description": "按 cron 表达式周期性运行一次性任务，表达式格式与定时扩缩容相同",
+            "type": "object",
+            "required": [
+                "cron",
+                "job",
+                "name"
+            ],
+            "properties": {
+                "concurrency_policy": {
+                    "type": "string",
+                    "example": "forbid"
+                },
+                "cron": {
+                    "type": "string",
+                    "example": "0 2 * * *"
+                },
+                "history_limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "job": {
+                    "$ref": "#/definitions/models.JobRequest"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nightly-report"
+                },
+                "suspended": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.DegradedService": {
+            "type": "object",
+            "properties": {
+                "desired": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "running": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                }
+            }
+        },
+        "models.Deployment": {
+            "description": "通过 async=true 提交的部署或更新的进度，step 和 current/total 反映当前阶段和副本进度",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor": {
+                    "type": "string",
+                    "example": "ci-token"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "current": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "error": {
+                    "type": "string",
+                    "example": "failed to pull image: manifest unknown"
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:31:20Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7c1e9a04b2d3"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx:1.25"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "creating replica 2/3"
+                },
+                "request": {
+                    "$ref": "#/definitions/models.ServiceRequest"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "step": {
+                    "type": "string",
+                    "example": "creating"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.DeploymentRevision": {
+            "description": "每次部署或更新成功后记录一条，包含完整的服务配置快照",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor": {
+                    "type": "string",
+                    "example": "token:abcd****"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx:alpine"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "rollback to revision 2"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "spec": {
+                    "$ref": "#/definitions/models.ServiceRequest"
+                }
+            }
+        },
+        "models.DesiredState": {
+            "description": "持久化保存的服务完整配置，用于扩容、回滚及重启后恢复环境变量、卷挂载等无法从容器标签获取的配置",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "persistent": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "replicas": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "spec": {
+                    "$ref": "#/definitions/models.ServiceRequest"
+                },
+                "stopped": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                }
+            }
+        },
+        "models.DiskStatus": {
+            "type": "object",
+            "properties": {
+                "build_cache_bytes": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "containers": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "containers_bytes": {
+                    "type": "integer",
+                    "example": 10485760
+                },
+                "error": {
+                    "type": "string"
+                },
+                "filesystem_free": {
+                    "type": "integer",
+                    "example": 53687091200
+                },
+                "filesystem_total": {
+                    "type": "integer",
+                    "example": 107374182400
+                },
+                "images": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "images_bytes": {
+                    "type": "integer",
+                    "example": 4831838208
+                },
+                "volumes": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "volumes_bytes": {
+                    "type": "integer",
+                    "example": 2147483648
+                }
+            }
+        },
+        "models.DiskUsageSummary": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "reclaimable": {
+                    "type": "integer",
+                    "example": 1073741824
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 4831838208
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "type": {
+                    "type": "string",
+                    "example": "images"
+                }
+            }
+        },
+        "models.DockerStatus": {
+            "type": "object",
+            "properties": {
+                "api_version": {
+                    "type": "string",
+                    "example": "1.51"
+                },
+                "arch": {
+                    "type": "string",
+                    "example": "amd64"
+                },
+                "client_version": {
+                    "type": "string",
+                    "example": "1.51"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "kernel_version": {
+                    "type": "string",
+                    "example": "6.8.0-45-generic"
+                },
+                "os": {
+                    "type": "string",
+                    "example": "linux"
+                },
+                "reachable": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "root_dir": {
+                    "type": "string",
+                    "example": "/var/lib/docker"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "28.3.3"
+                }
+            }
+        },
+        "models.DriftReport": {
+            "description": "比较期望状态与实际运行的容器（镜像、配置哈希、副本数），只报告差异，不做任何修改",
+            "type": "object",
+            "properties": {
+                "actual_replicas": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "checked_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "desired_config_hash": {
+                    "type": "string",
+                    "example": "3f2a9c1d0b7e4a55"
+                },
+                "desired_image": {
+                    "type": "string",
+                    "example": "nginx:alpine"
+                },
+                "desired_replicas": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "differences": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "replicas 2",
+                        " expected 3"
+                    ]
+                },
+                "drifted": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "replicas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReplicaDrift"
+                    }
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "stopped": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.EnvVariable": {
+            "type": "object",
+            "properties": {
+                "differs": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "expected": {
+                    "type": "string",
+                    "example": "10.0.0.6"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "DB_HOST"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "environment"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "10.0.0.5"
+                }
+            }
+        },
+        "models.Event": {
+            "description": "服务在什么时间发生了什么（部署、扩缩容、副本崩溃、重启或重建、更新失败、代理重启）",
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "system"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "message": {
+                    "type": "string",
+                    "example": "replica nginx-web-1 was Exited (137) 2 minutes ago, restarted"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "replica_crashed"
+                }
+            }
+        },
+        "models.EventList": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Event"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 128
+                }
+            }
+        },
+        "models.FieldError": {
+            "type": "object",
+            "properties": {
+                "constraint": {
+                    "type": "string",
+                    "example": "port"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "internal_port"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "internal_port must be a port between 1 and 65535"
+                },
+                "param": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "70000"
+                }
+            }
+        },
+        "models.GitOpsServiceResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConfigChange"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "file": {
+                    "type": "string",
+                    "example": "apps/nginx-web.yaml"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                }
+            }
+        },
+        "models.GitOpsStatus": {
+            "description": "仓库配置及最近一次同步的提交和各服务的结果",
+            "type": "object",
+            "properties": {
+                "branch": {
+                    "type": "string",
+                    "example": "main"
+                },
+                "commit": {
+                    "type": "string",
+                    "example": "9fceb02d0ae598e95dc970b74767f19372d61af8"
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_sync_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "services"
+                },
+                "prune": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "repo": {
+                    "type": "string",
+                    "example": "https://github.com/acme/deploy.git"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GitOpsServiceResult"
+                    }
+                }
+            }
+        },
+        "models.HostPort": {
+            "type": "object",
+            "properties": {
+                "container_id": {
+                    "type": "string",
+                    "example": "3f2a1b4c5d6e"
+                },
+                "container_port": {
+                    "type": "string",
+                    "example": "80/tcp"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "计费服务下周上线"
+                },
+                "port": {
+                    "type": "integer",
+                    "example": 9303
+                },
+                "proxy": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "replica": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "public"
+                }
+            }
+        },
+        "models.Identity": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "oidc:alice@example.com"
+                },
+                "auth_enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "role": {
+                    "type": "string",
+                    "example": "deployer"
+                },
+                "scope": {
+                    "$ref": "#/definitions/models.TokenScope"
+                }
+            }
+        },
+        "models.ImageDiskUsage": {
+            "type": "object",
+            "properties": {
+                "containers": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "a1b2c3d4e5f6"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "nginx-web"
+                    ]
+                },
+                "shared_size": {
+                    "type": "integer",
+                    "example": 8388608
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 52428800
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "nginx:alpine"
+                    ]
+                },
+                "unique_size": {
+                    "type": "integer",
+                    "example": 44040192
+                }
+            }
+        },
+        "models.ImageWatch": {
+            "type": "object",
+            "required": [
+                "mode"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "notify"
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "semver"
+                },
+                "pattern": {
+                    "type": "string",
+                    "example": "^1\\.25\\."
+                }
+            }
+        },
+        "models.ImageWatchStatus": {
+            "description": "监视配置及最近一次检查结果",
+            "type": "object",
+            "properties": {
+                "image": {
+                    "type": "string",
+                    "example": "nginx:1.25.3"
+                },
+                "last_check_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_update_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:05Z"
+                },
+                "latest_digest": {
+                    "type": "string",
+                    "example": "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+                },
+                "latest_tag": {
+                    "type": "string",
+                    "example": "1.25.4"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "update_available": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "watch": {
+                    "$ref": "#/definitions/models.ImageWatch"
+                }
+            }
+        },
+        "models.InitContainer": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "description": "启动命令",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "entrypoint": {
+                    "description": "入口点",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "environment": {
+                    "description": "额外的环境变量",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "image": {
+                    "description": "镜像名称",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string"
+                },
+                "tag": {
+                    "description": "镜像标签",
+                    "type": "string"
+                },
+                "timeout": {
+                    "description": "超时时间（秒），0 表示默认 300 秒",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Job": {
+            "description": "任务的运行状态、退出码和日志",
+            "type": "object",
+            "properties": {
+                "container_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4e5f6"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "cron_job": {
+                    "type": "string",
+                    "example": "nightly-report"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "timed out after 600s"
+                },
+                "exit_code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:31:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3f2a9c1b7d4e"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "myapp:1.2.0"
+                },
+                "logs": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "db-migrate"
+                },
+                "request": {
+                    "$ref": "#/definitions/models.JobRequest"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:05Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "succeeded"
+                }
+            }
+        },
+        "models.JobRequest": {
+            "description": "运行一个容器直到结束（数据库迁移、批处理等），不映射端口、不自动重启",
+            "type": "object",
+            "required": [
+                "image",
+                "tag"
+            ],
+            "properties": {
+                "command": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "./migrate",
+                        "up"
+                    ]
+                },
+                "entrypoint": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "/bin/sh",
+                        "-c"
+                    ]
+                },
+                "env_file": {
+                    "type": "string",
+                    "example": "/etc/myapp/.env"
+                },
+                "environment": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "image": {
+                    "type": "string",
+                    "example": "myapp"
+                },
+                "memory_limit": {
+                    "type": "integer",
+                    "example": 512
+                },
+                "name": {
+                    "type": "string",
+                    "example": "db-migrate"
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "1.2.0"
+                },
+                "timeout": {
+                    "type": "integer",
+                    "example": 600
+                },
+                "volumes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VolumeMount"
+                    }
+                },
+                "working_dir": {
+                    "type": "string",
+                    "example": "/app"
+                }
+            }
+        },
+        "models.LockRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "大促期间封版"
+                }
+            }
+        },
+        "models.LogLine": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "string",
+                    "example": "GET /health 200"
+                },
+                "replica": {
+                    "type": "string",
+                    "example": "nginx-web-9300-1"
+                },
+                "stream": {
+                    "type": "string",
+                    "example": "stdout"
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00.123456789Z"
+                }
+            }
+        },
+        "models.MirrorConfig": {
+            "type": "object",
+            "properties": {
+                "percent": {
+                    "description": "镜像比例 1-100",
+                    "type": "integer"
+                },
+                "service": {
+                    "description": "镜像目标服务名称",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MirrorStats": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "mirrored": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "percent": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web-shadow"
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "target": {
+                    "type": "string",
+                    "example": "http://127.0.0.1:9304"
+                }
+            }
+        },
+        "models.NamespaceInfo": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "staging"
+                },
+                "replicas": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "services": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.Network": {
+            "description": "managed 为 true 的网络由 OneDock 创建，只有这类网络可以通过 OneDock 删除",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "driver": {
+                    "type": "string",
+                    "example": "bridge"
+                },
+                "gateways": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "172.30.0.1"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3f2a1b4c5d6e"
+                },
+                "internal": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "managed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "backend"
+                },
+                "replicas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NetworkAttachment"
+                    }
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "local"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "nginx-web"
+                    ]
+                },
+                "subnets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "172.30.0.0/16"
+                    ]
+                }
+            }
+        },
+        "models.NetworkAttachment": {
+            "type": "object",
+            "properties": {
+                "container_id": {
+                    "type": "string",
+                    "example": "a1b2c3d4e5f6"
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "172.30.0.2"
+                },
+                "replica": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "running"
+                }
+            }
+        },
+        "models.NetworkCreateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "driver": {
+                    "type": "string",
+                    "enum": [
+                        "bridge",
+                        "macvlan",
+                        "ipvlan"
+                    ],
+                    "example": "bridge"
+                },
+                "gateway": {
+                    "type": "string",
+                    "example": "172.30.0.1"
+                },
+                "internal": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "backend"
+                },
+                "subnet": {
+                    "type": "string",
+                    "example": "172.30.0.0/16"
+                }
+            }
+        },
+        "models.Operation": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "token:abcd****"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "scale"
+                },
+                "queued_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:05Z"
+                }
+            }
+        },
+        "models.OperationQueue": {
+            "description": "同一服务的部署、更新、扩缩容、启停等修改操作逐个执行，后到的操作排队等待",
+            "type": "object",
+            "properties": {
+                "queued": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Operation"
+                    }
+                },
+                "running": {
+                    "$ref": "#/definitions/models.Operation"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                }
+            }
+        },
+        "models.OrphanContainer": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "replica 0 also used by onedock-nginx-web-p9203-c30002-0"
+                },
+                "error": {
+                    "type": "string",
+                    "example": ""
+                },
+                "id": {
+                    "type": "string",
+                    "example": "a1b2c3d4e5f6"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "onedock-nginx-web-p9203-c30001-0"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "duplicate_replica"
+                },
+                "removed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "exited"
+                }
+            }
+        },
+        "models.Overview": {
+            "description": "服务、副本、端口、代理和最近故障的汇总，一次调用即可用于监控面板",
+            "type": "object",
+            "properties": {
+                "active_rollouts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "degraded_services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DegradedService"
+                    }
+                },
+                "generated_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "missing_proxies": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "ports_in_use": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "ports_reserved": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "proxies_running": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "recent_failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Event"
+                    }
+                },
+                "replicas": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "running_replicas": {
+                    "type": "integer",
+                    "example": 29
+                },
+                "services": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "services_by_status": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "unhealthy_replicas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UnhealthyReplica"
+                    }
+                }
+            }
+        },
+        "models.PortListing": {
+            "description": "空闲范围按 OneDock 登记的端口计算，不探测主机上其他进程占用的端口",
+            "type": "object",
+            "properties": {
+                "auto_range": {
+                    "type": "string",
+                    "example": "20000-29999"
+                },
+                "free_count": {
+                    "type": "integer",
+                    "example": 9990
+                },
+                "free_ranges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PortRange"
+                    }
+                },
+                "ports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HostPort"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "models.PortRange": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer",
+                    "example": 20099
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 20000
+                }
+            }
+        },
+        "models.PortReservation": {
+            "description": "预留的端口不会被其他服务占用，指定服务首次部署到该端口时预留转为分配",
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "token:abcd****"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "计费服务下周上线"
+                },
+                "port": {
+                    "type": "integer",
+                    "example": 9300
+                },
+                "service": {
+                    "type": "string",
+                    "example": "billing"
+                }
+            }
+        },
+        "models.PortReservationRequest": {
+            "type": "object",
+            "required": [
+                "port"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "计费服务下周上线"
+                },
+                "port": {
+                    "type": "integer",
+                    "example": 9300
+                },
+                "service": {
+                    "type": "string",
+                    "example": "billing"
+                }
+            }
+        },
+        "models.PortUsage": {
+            "type": "object",
+            "properties": {
+                "allocated": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "auto_range": {
+                    "type": "string",
+                    "example": "9300-9399"
+                },
+                "auto_range_size": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "auto_range_used": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "proxies_running": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "reserved": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.ProxyBackendStats": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "connections": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "container_id": {
+                    "type": "string",
+                    "example": "3f2a1b4c5d6e"
+                },
+                "container_port": {
+                    "type": "integer",
+                    "example": 32768
+                },
+                "errors": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "last_used": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "weight": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "models.ProxyOverview": {
+            "type": "object",
+            "properties": {
+                "load_balancers": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "proxy_details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProxyStats"
+                    }
+                },
+                "single_proxies": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total_proxies": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.ProxyStats": {
+            "description": "后端的请求数和失败数在代理重建（扩缩容、更新）后重新计数，端口的累计请求数和失败数继续累计",
+            "type": "object",
+            "properties": {
+                "active_requests": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "backends": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProxyBackendStats"
+                    }
+                },
+                "bind_address": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "errors_total": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "listen_addr": {
+                    "type": "string",
+                    "example": ":9303"
+                },
+                "mirror": {
+                    "$ref": "#/definitions/models.MirrorStats"
+                },
+                "public_port": {
+                    "type": "integer",
+                    "example": 9303
+                },
+                "request_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RequestRuleStats"
+                    }
+                },
+                "requests_total": {
+                    "type": "integer",
+                    "example": 4096
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "strategy": {
+                    "type": "string",
+                    "example": "round_robin"
+                },
+                "strategy_overridden": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "type": "string",
+                    "example": "load_balancer"
+                },
+                "workers": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.ProxyStrategyRequest": {
+            "type": "object",
+            "properties": {
+                "strategy": {
+                    "type": "string",
+                    "enum": [
+                        "round_robin",
+                        "least_connections",
+                        "weighted"
+                    ],
+                    "example": "least_connections"
+                }
+            }
+        },
+        "models.QuotaLimit": {
+            "type": "object",
+            "properties": {
+                "max_memory": {
+                    "type": "integer",
+                    "example": 8192
+                },
+                "max_replicas": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "max_services": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "port_ranges": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "9300-9399"
+                    ]
+                }
+            }
+        },
+        "models.QuotaStatus": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "$ref": "#/definitions/models.QuotaLimit"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "staging"
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "namespace"
+                },
+                "usage": {
+                    "$ref": "#/definitions/models.QuotaUsage"
+                }
+            }
+        },
+        "models.QuotaUsage": {
+            "type": "object",
+            "properties": {
+                "memory": {
+                    "type": "integer",
+                    "example": 3072
+                },
+                "replicas": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "services": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.Readiness": {
+            "description": "全部检查项通过时就绪，供负载均衡和进程管理器判断是否可以转发管理请求",
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReadinessCheck"
+                    }
+                },
+                "ready": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.ReadinessCheck": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "docker"
+                },
+                "ready": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.ReconcileResult": {
+            "description": "调和循环比较期望状态与实际容器，补齐缺失副本、移除多余副本、启动意外停止的副本并恢复端口代理",
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "created 1 replicas"
+                    ]
+                },
+                "actual": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "desired": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "error": {
+                    "type": "string"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "skipped": {
+                    "type": "string",
+                    "example": "rollout in progress"
+                }
+            }
+        },
+        "models.ReleaseStatus": {
+            "description": "服务进行中的金丝雀或蓝绿发布，分别统计当前版本与新版本的副本",
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "token:abcd****"
+                },
+                "candidate_image": {
+                    "type": "string",
+                    "example": "nginx:1.27"
+                },
+                "candidate_replicas": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "canary"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "serving": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "stable_image": {
+                    "type": "string",
+                    "example": "nginx:1.25"
+                },
+                "stable_replicas": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.RenameRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "web-frontend"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "staging"
+                }
+            }
+        },
+        "models.ReplicaDetail": {
+            "description": "单个副本的检查详情（docker inspect），比服务状态中的实例摘要更完整；引用密钥的环境变量显示为 secret://\u003c名称\u003e",
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "container_id": {
+                    "type": "string",
+                    "example": "abc123def4567890"
+                },
+                "container_name": {
+                    "type": "string",
+                    "example": "onedock-nginx-web-9203-30001-0"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "entrypoint": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "environment": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "host_port": {
+                    "type": "integer",
+                    "example": 30001
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx:alpine"
+                },
+                "image_id": {
+                    "type": "string",
+                    "example": "sha256:1f3b..."
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "internal_port": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "memory_limit": {
+                    "type": "integer",
+                    "example": 134217728
+                },
+                "mounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReplicaMount"
+                    }
+                },
+                "network_mode": {
+                    "type": "string",
+                    "example": "bridge"
+                },
+                "networks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReplicaNetwork"
+                    }
+                },
+                "restart_policy": {
+                    "type": "string",
+                    "example": "always"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "state": {
+                    "$ref": "#/definitions/models.ReplicaState"
+                },
+                "working_dir": {
+                    "type": "string",
+                    "example": "/app"
+                }
+            }
+        },
+        "models.ReplicaDrift": {
+            "type": "object",
+            "properties": {
+                "config_hash": {
+                    "type": "string",
+                    "example": "3f2a9c1d0b7e4a55"
+                },
+                "container": {
+                    "type": "string",
+                    "example": "onedock-nginx-web-p9203-c30001-0"
+                },
+                "differences": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "image nginx:1.25",
+                        " expected nginx:alpine"
+                    ]
+                },
+                "drifted": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx:1.25"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "running"
+                }
+            }
+        },
+        "models.ReplicaMount": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "type": "string",
+                    "example": "/usr/share/nginx/html"
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "ro"
+                },
+                "name": {
+                    "type": "string",
+                    "example": ""
+                },
+                "read_write": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "source": {
+                    "type": "string",
+                    "example": "/data/nginx"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "bind"
+                }
+            }
+        },
+        "models.ReplicaNetwork": {
+            "type": "object",
+            "properties": {
+                "gateway": {
+                    "type": "string",
+                    "example": "172.17.0.1"
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "172.17.0.2"
+                },
+                "mac_address": {
+                    "type": "string",
+                    "example": "02:42:ac:11:00:02"
+                },
+                "network": {
+                    "type": "string",
+                    "example": "bridge"
+                }
+            }
+        },
+        "models.ReplicaState": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "exit_code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "failing_streak": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:29:55Z"
+                },
+                "health": {
+                    "type": "string",
+                    "example": "healthy"
+                },
+                "health_log": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "oom_killed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "pid": {
+                    "type": "integer",
+                    "example": 12345
+                },
+                "restart_count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "running": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                }
+            }
+        },
+        "models.ReplicaStats": {
+            "type": "object",
+            "properties": {
+                "block_read": {
+                    "type": "integer",
+                    "example": 4096
+                },
+                "block_write": {
+                    "type": "integer",
+                    "example": 8192
+                },
+                "container_id": {
+                    "type": "string",
+                    "example": "3f2a1b4c5d6e"
+                },
+                "cpu_percent": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "error": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "memory_limit": {
+                    "type": "integer",
+                    "example": 536870912
+                },
+                "memory_percent": {
+                    "type": "number",
+                    "example": 25
+                },
+                "memory_usage": {
+                    "type": "integer",
+                    "example": 134217728
+                },
+                "network_rx": {
+                    "type": "integer",
+                    "example": 1048576
+                },
+                "network_tx": {
+                    "type": "integer",
+                    "example": 2097152
+                },
+                "pids": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "state": {
+                    "type": "string",
+                    "example": "running"
+                }
+            }
+        },
+        "models.RequestRule": {
+            "type": "object",
+            "properties": {
+                "headers": {
+                    "description": "请求头名称 -\u003e 值正则",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "methods": {
+                    "description": "HTTP 方法列表",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "规则名称",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "路径正则",
+                    "type": "string"
+                },
+                "user_agent": {
+                    "description": "User-Agent 正则",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RequestRuleStats": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "name": {
+                    "type": "string",
+                    "example": "block-admin"
+                }
+            }
+        },
+        "models.RestoreResult": {
+            "description": "恢复的状态数量、与本机配置的差异，以及恢复后调和创建副本的结果",
+            "type": "object",
+            "properties": {
+                "cron_jobs": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "manifest": {
+                    "$ref": "#/definitions/models.BackupManifest"
+                },
+                "ports": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "reconcile": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReconcileResult"
+                    }
+                },
+                "secrets": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "templates": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RolloutState": {
+            "type": "string",
+            "enum": [
+                "running",
+                "paused",
+                "completed",
+                "failed",
+                "aborted"
+            ],
+            "x-enum-comments": {
+                "RolloutAborted": "已中止并回滚",
+                "RolloutCompleted": "已完成",
+                "RolloutFailed": "更新失败",
+                "RolloutPaused": "已暂停",
+                "RolloutRunning": "更新中"
+            },
+            "x-enum-varnames": [
+                "RolloutRunning",
+                "RolloutPaused",
+                "RolloutCompleted",
+                "RolloutFailed",
+                "RolloutAborted"
+            ]
+        },
+        "models.RolloutStatus": {
+            "description": "服务最近一次滚动更新的状态与进度",
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "token:abcd****"
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:32:00Z"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx:1.25"
+                },
+                "max_surge": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "max_unavailable": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "message": {
+                    "type": "string",
+                    "example": "paused after 1 replicas"
+                },
+                "pause_after": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "state": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RolloutState"
+                        }
+                    ],
+                    "example": "paused"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:31:00Z"
+                }
+            }
+        },
+        "models.RuntimeConfig": {
+            "description": "修改后写回配置文件并立即生效，无需重启 OneDock",
+            "type": "object",
+            "properties": {
+                "auto_range": {
+                    "type": "string",
+                    "example": "20000-29999"
+                },
+                "cache_ttl": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "config_file": {
+                    "type": "string",
+                    "example": "./config.toml"
+                },
+                "load_balance_strategy": {
+                    "type": "string",
+                    "example": "round_robin"
+                },
+                "log_level": {
+                    "type": "string",
+                    "example": "INFO"
+                },
+                "min_public_port": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "proxy_read_timeout": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "proxy_write_timeout": {
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "models.RuntimeConfigUpdate": {
+            "type": "object",
+            "properties": {
+                "auto_range": {
+                    "type": "string",
+                    "example": "20000-24999"
+                },
+                "cache_ttl": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 60
+                },
+                "load_balance_strategy": {
+                    "type": "string",
+                    "enum": [
+                        "round_robin",
+                        "least_connections",
+                        "weighted"
+                    ],
+                    "example": "least_connections"
+                },
+                "log_level": {
+                    "type": "string",
+                    "enum": [
+                        "DEBUG",
+                        "INFO",
+                        "WARN",
+                        "ERROR",
+                        "debug",
+                        "info",
+                        "warn",
+                        "error"
+                    ],
+                    "example": "DEBUG"
+                },
+                "min_public_port": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "proxy_read_timeout": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 60
+                },
+                "proxy_write_timeout": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 60
+                }
+            }
+        },
+        "models.ScaleRequest": {
+            "description": "服务扩缩容请求参数",
+            "type": "object",
+            "required": [
+                "replicas"
+            ],
+            "properties": {
+                "replicas": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.ScaleSchedule": {
+            "description": "到达 cron 表达式指定的时间时将服务调整到指定副本数",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-10T10:30:00Z"
+                },
+                "cron": {
+                    "type": "string",
+                    "example": "0 9 * * 1-5"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": ""
+                },
+                "last_run_at": {
+                    "type": "string",
+                    "example": "2024-01-12T09:00:00+08:00"
+                },
+                "next_run_at": {
+                    "type": "string",
+                    "example": "2024-01-15T09:00:00+08:00"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "工作日早高峰"
+                },
+                "replicas": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                }
+            }
+        },
+        "models.ScaleScheduleRequest": {
+            "description": "cron 表达式为 5 段（分 时 日 月 周），按服务器本地时区执行，如 \"0 9 * * 1-5\" 表示工作日 09:00",
+            "type": "object",
+            "required": [
+                "cron",
+                "replicas"
+            ],
+            "properties": {
+                "cron": {
+                    "type": "string",
+                    "example": "0 9 * * 1-5"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "工作日早高峰"
+                },
+                "replicas": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 5
+                }
+            }
+        },
+        "models.Secret": {
+            "description": "服务环境变量写成 secret://\u003c名称\u003e 即可在创建容器时注入密钥值",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "db_password"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "used_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "nginx-web"
+                    ]
+                }
+            }
+        },
+        "models.SecretRequest": {
+            "description": "密钥值加密保存，任何接口都不会返回明文",
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "value": {
+                    "type": "string",
+                    "example": "s3cr3t"
+                }
+            }
+        },
+        "models.Service": {
+            "type": "object",
+            "properties": {
+                "bind_address": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "svc_1234567890"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx"
+                },
+                "internal_port": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "memory_limit": {
+                    "type": "integer",
+                    "example": 512
+                },
+                "mirror": {
+                    "$ref": "#/definitions/models.MirrorConfig"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "proxy_workers": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "public_port": {
+                    "type": "integer",
+                    "example": 30000
+                },
+                "replicas": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "request_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RequestRule"
+                    }
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ServiceStatus"
+                        }
+                    ],
+                    "example": "running"
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "alpine"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.ServiceEnvironment": {
+            "description": "合并环境变量文件与 environment、替换 ${host_ip} 等占位符后副本实际运行的环境变量，引用密钥的变量不返回明文",
+            "type": "object",
+            "properties": {
+                "env_file": {
+                    "type": "string",
+                    "example": "/etc/onedock/nginx-web.env"
+                },
+                "replica": {
+                    "type": "string",
+                    "example": "onedock-nginx-web-9203-30001-0"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EnvVariable"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ServiceInstanceInfo": {
+            "type": "object",
+            "properties": {
+                "candidate": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "container_id": {
+                    "type": "string",
+                    "example": "abc123def456"
+                },
+                "container_name": {
+                    "type": "string",
+                    "example": "onedock-nginx-1"
+                },
+                "container_port": {
+                    "type": "integer",
+                    "example": 30001
+                },
+                "cpu_usage": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "health_status": {
+                    "type": "string",
+                    "example": "healthy"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "inst_1234567890"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx:alpine"
+                },
+                "internal_port": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "172.17.0.2"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "memory_limit": {
+                    "type": "number",
+                    "example": 128
+                },
+                "memory_usage": {
+                    "type": "number",
+                    "example": 64.5
+                },
+                "public_port": {
+                    "type": "integer",
+                    "example": 30000
+                },
+                "restart_count": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "uptime": {
+                    "type": "string",
+                    "example": "2h30m"
+                }
+            }
+        },
+        "models.ServiceLock": {
+            "description": "锁定期间只有管理员令牌可以部署、更新、扩缩容、启停或删除该服务",
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "token:abcd****"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "大促期间封版"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                }
+            }
+        },
+        "models.ServiceProxyStats": {
+            "type": "object",
+            "properties": {
+                "proxies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProxyStats"
+                    }
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                }
+            }
+        },
+        "models.ServiceRequest": {
+            "type": "object",
+            "required": [
+                "image",
+                "internal_port",
+                "name",
+                "tag"
+            ],
+            "properties": {
+                "bind_address": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "command": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "deployment_mode": {
+                    "type": "string",
+                    "enum": [
+                        "rolling",
+                        "blue_green"
+                    ],
+                    "example": "blue_green"
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "entrypoint": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "env_file": {
+                    "type": "string"
+                },
+                "environment": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx"
+                },
+                "init_containers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InitContainer"
+                    }
+                },
+                "internal_port": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "memory_limit": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 512
+                },
+                "mirror": {
+                    "$ref": "#/definitions/models.MirrorConfig"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "staging"
+                },
+                "proxy_workers": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 4
+                },
+                "public_port": {
+                    "type": "integer",
                     "example": 30000
                 },
+                "replicas": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "request_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RequestRule"
+                    }
+                },
+                "sidecars": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Sidecar"
+                    }
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "alpine"
+                },
+                "update_strategy": {
+                    "$ref": "#/definitions/models.UpdateStrategy"
+                },
+                "volumes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VolumeMount"
+                    }
+                },
+                "working_dir": {
+                    "type": "string",
+                    "example": "/app"
+                }
+            }
+        },
+        "models.ServiceStats": {
+            "description": "由 Docker stats 接口采样，CPU 使用率为约 1 秒内的平均值，网络和块设备为容器启动以来的累计值",
+            "type": "object",
+            "properties": {
+                "block_read": {
+                    "type": "integer",
+                    "example": 8192
+                },
+                "block_write": {
+                    "type": "integer",
+                    "example": 16384
+                },
+                "cpu_percent": {
+                    "type": "number",
+                    "example": 25
+                },
+                "memory_usage": {
+                    "type": "integer",
+                    "example": 268435456
+                },
+                "network_rx": {
+                    "type": "integer",
+                    "example": 2097152
+                },
+                "network_tx": {
+                    "type": "integer",
+                    "example": 4194304
+                },
+                "replicas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReplicaStats"
+                    }
+                },
+                "sampled_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                }
+            }
+        },
+        "models.ServiceStatus": {
+            "type": "string",
+            "enum": [
+                "stopped",
+                "starting",
+                "running",
+                "stopping",
+                "failed",
+                "updating"
+            ],
+            "x-enum-varnames": [
+                "StatusStopped",
+                "StatusStarting",
+                "StatusRunning",
+                "StatusStopping",
+                "StatusFailed",
+                "StatusUpdating"
+            ]
+        },
+        "models.ServiceStatusResponse": {
+            "type": "object",
+            "properties": {
+                "access_url": {
+                    "type": "string",
+                    "example": "http://localhost:30000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "failed_replicas": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "healthy_replicas": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "instances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ServiceInstanceInfo"
+                    }
+                },
+                "load_balancer": {
+                    "type": "string",
+                    "example": "round_robin"
+                },
+                "lock": {
+                    "$ref": "#/definitions/models.ServiceLock"
+                },
+                "operations": {
+                    "$ref": "#/definitions/models.OperationQueue"
+                },
+                "release": {
+                    "$ref": "#/definitions/models.ReleaseStatus"
+                },
+                "running_replicas": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "service": {
+                    "$ref": "#/definitions/models.Service"
+                },
+                "stopped_replicas": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total_replicas": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                }
+            }
+        },
+        "models.ServiceTemplate": {
+            "description": "带 ${VAR} 占位符的服务配置，部署时传入变量值生成具体服务；${VAR:-默认值} 表示可选变量",
+            "type": "object",
+            "required": [
+                "name",
+                "spec"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "标准 Web 服务"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "web-app"
+                },
+                "spec": {
+                    "type": "object"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "NAME",
+                        "PORT"
+                    ]
+                }
+            }
+        },
+        "models.Sidecar": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "description": "启动命令",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "entrypoint": {
+                    "description": "入口点",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "environment": {
+                    "description": "环境变量",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "image": {
+                    "description": "镜像名称",
+                    "type": "string"
+                },
+                "memory_limit": {
+                    "description": "内存上限（MB），0 表示不限制",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string"
+                },
+                "tag": {
+                    "description": "镜像标签",
+                    "type": "string"
+                },
+                "volumes": {
+                    "description": "额外的卷挂载",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dockerclient.VolumeMount"
+                    }
+                }
+            }
+        },
+        "models.SystemDiskUsage": {
+            "description": "与 docker system df -v 相当：按资源类型汇总占用和可回收空间，并列出每个镜像、容器和卷的占用（按占用从大到小排序），标出所属的受管服务",
+            "type": "object",
+            "properties": {
+                "containers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ContainerDiskUsage"
+                    }
+                },
+                "filesystem_free": {
+                    "type": "integer",
+                    "example": 53687091200
+                },
+                "filesystem_total": {
+                    "type": "integer",
+                    "example": 107374182400
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImageDiskUsage"
+                    }
+                },
+                "summary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiskUsageSummary"
+                    }
+                },
+                "volumes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VolumeDiskUsage"
+                    }
+                }
+            }
+        },
+        "models.SystemStatus": {
+            "description": "Docker 守护进程、磁盘占用、受管端口和 OneDock 进程运行时长",
+            "type": "object",
+            "properties": {
+                "disk": {
+                    "$ref": "#/definitions/models.DiskStatus"
+                },
+                "docker": {
+                    "$ref": "#/definitions/models.DockerStatus"
+                },
+                "persistent": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "ports": {
+                    "$ref": "#/definitions/models.PortUsage"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-15T08:00:00Z"
+                },
+                "uptime": {
+                    "type": "string",
+                    "example": "2h30m0s"
+                },
+                "uptime_seconds": {
+                    "type": "integer",
+                    "example": 9000
+                }
+            }
+        },
+        "models.TemplateDeployRequest": {
+            "description": "按模板生成服务配置并部署，服务已存在时执行滚动更新",
+            "type": "object",
+            "properties": {
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.TokenScope": {
+            "description": "受限的令牌只能查看和修改范围内的服务，不能调用密钥、模板、系统维护等全局修改接口",
+            "type": "object",
+            "properties": {
+                "namespaces": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "staging"
+                    ]
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "app-a",
+                        "staging.app-a"
+                    ]
+                }
+            }
+        },
+        "models.TrashedService": {
+            "description": "放入回收站的服务保存了删除时的完整配置，公共端口为它保留，保留期内可以恢复",
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "deleted_by": {
+                    "type": "string",
+                    "example": "token:abcd****"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-01-22T10:30:00Z"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "nginx:1.27-alpine"
+                },
+                "kept_containers": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "public_port": {
+                    "type": "integer",
+                    "example": 9203
+                },
                 "replicas": {
                     "type": "integer",
                     "example": 3
+                }
+            }
+        },
+        "models.UnhealthyReplica": {
+            "type": "object",
+            "properties": {
+                "container": {
+                    "type": "string",
+                    "example": "nginx-web-9300-1"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "nginx-web"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "exited"
                 },
                 "status": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.ServiceStatus"
-                        }
-                    ],
-                    "example": "running"
+                    "type": "string",
+                    "example": "Exited (137) 2 minutes ago"
+                }
+            }
+        },
+        "models.UpdateStrategy": {
+            "description": "每批更新 max_surge + max_unavailable 个副本，批内并行执行",
+            "type": "object",
+            "properties": {
+                "auto_rollback": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "max_surge": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "max_unavailable": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "pause_after": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.ValidationReport": {
+            "description": "只做静态检查（字段格式、端口、镜像引用、卷路径、环境变量文件），不访问镜像仓库，不创建或修改任何容器",
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
                 },
-                "tag": {
+                "service": {
                     "type": "string",
-                    "example": "alpine"
+                    "example": "nginx-web"
                 },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
+                "valid": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
                 }
             }
         },
-        "models.ServiceInstanceInfo": {
+        "models.VersionInfo": {
+            "description": "版本号、提交和构建时间在构建时通过 ldflags 注入，Docker API 版本为与守护进程协商后使用的版本",
             "type": "object",
             "properties": {
-                "container_id": {
+                "build_date": {
                     "type": "string",
-                    "example": "abc123def456"
+                    "example": "2024-01-15T10:30:00Z"
                 },
-                "container_name": {
+                "docker_api_version": {
                     "type": "string",
-                    "example": "onedock-nginx-1"
-                },
-                "container_port": {
-                    "type": "integer",
-                    "example": 30001
+                    "example": "1.51"
                 },
-                "cpu_usage": {
-                    "type": "number",
-                    "example": 0.5
+                "docker_error": {
+                    "type": "string"
                 },
-                "created_at": {
+                "docker_version": {
                     "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
+                    "example": "28.3.3"
                 },
-                "health_status": {
+                "git_commit": {
                     "type": "string",
-                    "example": "healthy"
+                    "example": "9fceb02d0ae598e95dc970b74767f19372d61af8"
                 },
-                "id": {
+                "go_version": {
                     "type": "string",
-                    "example": "inst_1234567890"
+                    "example": "go1.24.4"
                 },
-                "image": {
+                "platform": {
                     "type": "string",
-                    "example": "nginx:alpine"
-                },
-                "internal_port": {
-                    "type": "integer",
-                    "example": 80
+                    "example": "linux/amd64"
                 },
-                "ip_address": {
+                "version": {
                     "type": "string",
-                    "example": "172.17.0.2"
+                    "example": "v1.2.0"
+                }
+            }
+        },
+        "models.Volume": {
+            "description": "命名卷按卷名称、主机目录按路径汇总挂载它的副本；size 为 -1 表示无法统计占用",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
                 },
-                "labels": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "driver": {
+                    "type": "string",
+                    "example": "local"
                 },
-                "memory_limit": {
-                    "type": "number",
-                    "example": 128
+                "error": {
+                    "type": "string"
                 },
-                "memory_usage": {
-                    "type": "number",
-                    "example": 64.5
+                "name": {
+                    "type": "string",
+                    "example": "nginx-data"
                 },
-                "public_port": {
-                    "type": "integer",
-                    "example": 30000
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "nginx-web"
+                    ]
                 },
-                "restart_count": {
+                "size": {
                     "type": "integer",
-                    "example": 0
-                },
-                "service_name": {
-                    "type": "string",
-                    "example": "nginx-web"
+                    "example": 52428800
                 },
-                "started_at": {
+                "source": {
                     "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
+                    "example": "/var/lib/docker/volumes/nginx-data/_data"
                 },
-                "status": {
+                "type": {
                     "type": "string",
-                    "example": "running"
+                    "example": "volume"
                 },
-                "uptime": {
-                    "type": "string",
-                    "example": "2h30m"
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VolumeUser"
+                    }
                 }
             }
         },
-        "models.ServiceRequest": {
+        "models.VolumeDiskUsage": {
             "type": "object",
-            "required": [
-                "image",
-                "internal_port",
-                "name",
-                "tag"
-            ],
             "properties": {
-                "command": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "name": {
+                    "type": "string",
+                    "example": "nginx-data"
                 },
-                "entrypoint": {
+                "ref_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "services": {
                     "type": "array",
                     "items": {
                         "type": "string"
-                    }
+                    },
+                    "example": [
+                        "nginx-web"
+                    ]
                 },
-                "env_file": {
+                "size": {
+                    "type": "integer",
+                    "example": 52428800
+                }
+            }
+        },
+        "models.VolumeMount": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "description": "容器内路径",
                     "type": "string"
                 },
-                "environment": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "readOnly": {
+                    "description": "是否只读挂载",
+                    "type": "boolean"
                 },
-                "image": {
+                "source": {
+                    "description": "主机路径",
+                    "type": "string"
+                }
+            }
+        },
+        "models.VolumeUser": {
+            "type": "object",
+            "properties": {
+                "container_id": {
                     "type": "string",
-                    "example": "nginx"
-                },
-                "internal_port": {
-                    "type": "integer",
-                    "example": 80
+                    "example": "a1b2c3d4e5f6"
                 },
-                "name": {
+                "destination": {
                     "type": "string",
-                    "example": "nginx-web"
+                    "example": "/usr/share/nginx/html"
                 },
-                "public_port": {
-                    "type": "integer",
-                    "example": 30000
+                "read_write": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "replicas": {
+                "replica": {
                     "type": "integer",
-                    "example": 1
+                    "example": 0
                 },
-                "tag": {
+                "service": {
                     "type": "string",
-                    "example": "alpine"
-                },
-                "volumes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.VolumeMount"
-                    }
+                    "example": "nginx-web"
                 },
-                "working_dir": {
+                "state": {
                     "type": "string",
-                    "example": "/app"
+                    "example": "running"
                 }
             }
         },
-        "models.ServiceStatus": {
-            "type": "string",
-            "enum": [
-                "stopped",
-                "starting",
-                "running",
-                "stopping",
-                "failed",
-                "updating"
-            ],
-            "x-enum-varnames": [
-                "StatusStopped",
-                "StatusStarting",
-                "StatusRunning",
-                "StatusStopping",
-                "StatusFailed",
-                "StatusUpdating"
-            ]
-        },
-        "models.ServiceStatusResponse": {
+        "models.Webhook": {
+            "description": "订阅配置及最近一次投递结果",
             "type": "object",
             "properties": {
-                "access_url": {
-                    "type": "string",
-                    "example": "http://localhost:30000"
-                },
                 "created_at": {
                     "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
-                },
-                "failed_replicas": {
-                    "type": "integer",
-                    "example": 0
+                    "example": "2024-01-15T10:30:00Z"
                 },
-                "healthy_replicas": {
-                    "type": "integer",
-                    "example": 3
+                "disabled": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "instances": {
+                "events": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.ServiceInstanceInfo"
-                    }
+                        "type": "string"
+                    },
+                    "example": [
+                        "deployed",
+                        "update_failed"
+                    ]
                 },
-                "load_balancer": {
-                    "type": "string",
-                    "example": "round_robin"
+                "has_secret": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "running_replicas": {
-                    "type": "integer",
-                    "example": 2
+                "last_delivery_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
                 },
-                "service": {
-                    "$ref": "#/definitions/models.Service"
+                "last_error": {
+                    "type": "string",
+                    "example": "https://ci.example.com/hooks/onedock responded with status 502"
                 },
-                "stopped_replicas": {
+                "last_status": {
                     "type": "integer",
-                    "example": 1
+                    "example": 200
                 },
-                "total_replicas": {
-                    "type": "integer",
-                    "example": 3
+                "name": {
+                    "type": "string",
+                    "example": "deploy-tracker"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "nginx-web"
+                    ]
                 },
                 "updated_at": {
                     "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://ci.example.com/hooks/onedock"
                 }
             }
         },
-        "models.VolumeMount": {
+        "models.WebhookRequest": {
+            "description": "服务生命周期事件发生时以 JSON POST 到 url，同名订阅已存在时覆盖配置（包括签名密钥）",
             "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
             "properties": {
-                "destination": {
-                    "description": "容器内路径",
-                    "type": "string"
+                "disabled": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "readOnly": {
-                    "description": "是否只读挂载",
-                    "type": "boolean"
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "deployed",
+                        "update_failed"
+                    ]
                 },
-                "source": {
-                    "description": "主机路径",
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "deploy-tracker"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_3f2a9c1b"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "nginx-web"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://ci.example.com/hooks/onedock"
                 }
             }
         }
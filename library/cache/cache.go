@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/utils"
+)
+
+// 缓存后端
+const (
+	BackendMemory = "memory" // 进程内缓存（默认），重启后丢失
+	BackendRedis  = "redis"  // Redis 缓存，多个实例及重启后共享
+)
+
+// Cache 键值缓存，MemCache 与 RedisCache 均实现该接口
+type Cache interface {
+	Get(ctx context.IContext, key string, value interface{}) error
+	Set(ctx context.IContext, key string, value interface{}, seconds int) error
+	Delete(ctx context.IContext, key string) error
+	Ping(ctx context.IContext) error
+	Close() error
+}
+
+// NewCache 按 cache.backend 创建缓存，redis 时使用 cache.redis 指定的 igo Redis 实例（默认 default）
+func NewCache() (Cache, error) {
+	backend := utils.ConfGetString("cache.backend")
+	switch backend {
+	case "", BackendMemory:
+		return NewMemCache(), nil
+	case BackendRedis:
+		name := utils.ConfGetString("cache.redis")
+		if name == "" {
+			name = "default"
+		}
+		return OpenRedisCache(name)
+	}
+	return nil, fmt.Errorf("unsupported cache backend %q, must be memory or redis", backend)
+}
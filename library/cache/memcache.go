@@ -23,6 +23,17 @@ func NewMemCache() *MemCache {
 	}
 }
 
+// Ping 进程内缓存始终可用
+func (s *MemCache) Ping(ctx context.IContext) error {
+	return nil
+}
+
+// Close 清空全部缓存
+func (s *MemCache) Close() error {
+	s.mem.Flush()
+	return nil
+}
+
 func (s *MemCache) Get(ctx context.IContext, key string, value interface{}) error {
 	item, has := s.mem.Get(key)
 	if !has {
@@ -96,3 +107,8 @@ func (s *MemCache) Del(ctx context.IContext, Key string) error {
 	s.mem.Delete(Key)
 	return nil
 }
+
+// Delete 删除缓存
+func (s *MemCache) Delete(ctx context.IContext, key string) error {
+	return s.Del(ctx, key)
+}
@@ -18,13 +18,25 @@ type RedisCache struct {
 
 // NewRedisCache
 func NewRedisCache() *RedisCache {
-	redis, err := igo.App.Cache.Get("default")
+	cache, err := OpenRedisCache("default")
 	if err != nil {
-		panic(fmt.Errorf("redis get error:%s", err.Error()))
+		panic(err)
+	}
+	return cache
+}
+
+// OpenRedisCache 使用 igo 中名为 name 的 Redis 实例（[redis.<name>]），不可用时返回错误
+func OpenRedisCache(name string) (*RedisCache, error) {
+	if igo.App == nil || igo.App.Cache == nil {
+		return nil, fmt.Errorf("redis get error:cache not initialized")
+	}
+	redis, err := igo.App.Cache.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("redis get error:%s", err.Error())
 	}
 	return &RedisCache{
 		Redis: redis,
-	}
+	}, nil
 }
 
 func (R *RedisCache) Colation(ctx context.IContext, Colation string, redisTimeBySecond int) bool {
@@ -76,6 +88,22 @@ func (R *RedisCache) Del(ctx context.IContext, rediskey string) (int64, error) {
 	return R.Redis.Del(ctx, rediskey).Result()
 }
 
+// Delete 删除缓存
+func (R *RedisCache) Delete(ctx context.IContext, rediskey string) error {
+	_, err := R.Del(ctx, rediskey)
+	return err
+}
+
+// Ping 检查 Redis 是否可用
+func (R *RedisCache) Ping(ctx context.IContext) error {
+	return R.Redis.Ping(ctx).Err()
+}
+
+// Close 关闭 Redis 连接
+func (R *RedisCache) Close() error {
+	return R.Redis.Close()
+}
+
 func (R *RedisCache) redisTime(redisTime int) time.Duration {
 	var nTTL time.Duration
 	if redisTime > 0 {
@@ -0,0 +1,82 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParse 测试表达式解析
+func TestParse(t *testing.T) {
+	valid := []string{"* * * * *", "0 9 * * 1-5", "*/15 * * * *", "0 9,18 * * mon-fri", "30 22 1 jan *", "@daily", "0 0 * * 7"}
+	for _, expr := range valid {
+		if _, err := Parse(expr); err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", expr, err)
+		}
+	}
+
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "5-1 * * * *", "*/0 * * * *", "abc * * * *"}
+	for _, expr := range invalid {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) expected error", expr)
+		}
+	}
+}
+
+// TestMatch 测试时间匹配
+func TestMatch(t *testing.T) {
+	// 2025-06-02 是星期一
+	monday := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	sunday := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{"0 9 * * mon-fri", monday, true},
+		{"0 9 * * mon-fri", sunday, false},
+		{"0 9 * * 7", sunday, true},
+		{"0 9 * * 0", sunday, true},
+		{"*/15 9 * * *", monday.Add(45 * time.Minute), true},
+		{"*/15 9 * * *", monday.Add(50 * time.Minute), false},
+		{"0 9 1 * mon", sunday, true}, // 日和周都有限制时满足其一即可
+		{"0 9 15 * mon", sunday, false},
+	}
+
+	for _, tc := range cases {
+		s, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tc.expr, err)
+		}
+		if got := s.Match(tc.t); got != tc.want {
+			t.Errorf("%q Match(%s) = %v, want %v", tc.expr, tc.t, got, tc.want)
+		}
+	}
+}
+
+// TestNext 测试下次执行时间
+func TestNext(t *testing.T) {
+	// 2025-06-06 是星期五
+	friday := time.Date(2025, 6, 6, 22, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 9 * * mon-fri", time.Date(2025, 6, 9, 9, 0, 0, 0, time.UTC)},
+		{"0 22 * * *", time.Date(2025, 6, 7, 22, 0, 0, 0, time.UTC)},
+		{"45 22 * * *", time.Date(2025, 6, 6, 22, 45, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+
+	for _, tc := range cases {
+		s, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tc.expr, err)
+		}
+		if got := s.Next(friday); !got.Equal(tc.want) {
+			t.Errorf("%q Next = %s, want %s", tc.expr, got, tc.want)
+		}
+	}
+}
@@ -1,20 +1,30 @@
 package dockerclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aichy126/igo/context"
 
 	"github.com/aichy126/igo/log"
 	"github.com/aichy126/onedock/utils"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
+	"github.com/google/uuid"
 )
 
 // NewDockerClient 创建新的Docker客户端实例
@@ -27,13 +37,42 @@ func NewDockerClient() (*DockerClient, error) {
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
 	}
 
+	hostIP := strings.Trim(utils.ConfGetString("container.host_ip"), "[]")
+	if hostIP == "" {
+		hostIP = "127.0.0.1"
+	}
+
 	return &DockerClient{
 		cli:               cli,
 		containerPrefix:   utils.ConfGetString("container.prefix"),
 		internalPortStart: utils.ConfGetInt("container.internal_port_start"),
+		hostIP:            hostIP,
+		verifyTimeout:     time.Duration(utils.ConfGetInt("container.update_verify_timeout")) * time.Second,
 	}, nil
 }
 
+// SetEnvResolver 设置创建容器前的环境变量解析函数
+func (dc *DockerClient) SetEnvResolver(resolver EnvResolver) {
+	dc.envResolver = resolver
+}
+
+// progressKey 上下文中进度回调的键
+const progressKey = "onedock-progress"
+
+// WithProgress 在上下文中设置进度回调，之后使用该上下文的拉取镜像、创建副本等操作会报告进度
+func WithProgress(ctx context.IContext, fn ProgressFunc) {
+	ctx.Set(progressKey, fn)
+}
+
+// ReportProgress 报告进度，上下文中没有进度回调时忽略
+func ReportProgress(ctx context.IContext, progress Progress) {
+	if value, ok := ctx.Get(progressKey); ok {
+		if fn, ok := value.(ProgressFunc); ok {
+			fn(progress)
+		}
+	}
+}
+
 // PullImage 拉取Docker镜像
 // 参数:
 //   - ctx: 上下文对象，用于控制超时和取消操作
@@ -43,11 +82,12 @@ func (dc *DockerClient) PullImage(ctx context.IContext, imageName, tag string) e
 	fullImage := fmt.Sprintf("%s:%s", imageName, tag)
 
 	log.Info("Docker", log.Any("Image", fullImage), log.Any("Message", "开始拉取镜像"))
+	ReportProgress(ctx, Progress{Step: "pulling", Message: "pulling image " + fullImage})
 
 	reader, err := dc.cli.ImagePull(ctx, fullImage, image.PullOptions{})
 	if err != nil {
 		log.Error("Docker", log.Any("Error", err), log.Any("Image", fullImage), log.Any("Message", "镜像拉取失败"))
-		return fmt.Errorf("failed to pull image %s: %w", fullImage, err)
+		return utils.NewError(utils.CodeImagePullFailed, "failed to pull image %s: %w", fullImage, err)
 	}
 	defer reader.Close()
 
@@ -55,13 +95,52 @@ func (dc *DockerClient) PullImage(ctx context.IContext, imageName, tag string) e
 	_, err = io.Copy(io.Discard, reader)
 	if err != nil {
 		log.Error("Docker", log.Any("Error", err), log.Any("Message", "读取拉取输出失败"))
-		return fmt.Errorf("failed to read pull output: %w", err)
+		return utils.NewError(utils.CodeImagePullFailed, "failed to read pull output: %w", err)
 	}
 
 	log.Info("Docker", log.Any("Image", fullImage), log.Any("Message", "镜像拉取完成"))
 	return nil
 }
 
+// CheckImage 检查镜像是否可用，不拉取镜像
+// 本地已有镜像时返回 "local"，否则查询镜像仓库的 manifest，存在时返回 "registry"
+// 参数:
+//   - ctx: 上下文对象
+//   - imageName: 镜像名称
+//   - tag: 镜像标签
+func (dc *DockerClient) CheckImage(ctx context.IContext, imageName, tag string) (string, error) {
+	fullImage := fmt.Sprintf("%s:%s", imageName, tag)
+	if _, err := dc.cli.ImageInspect(ctx, fullImage); err == nil {
+		return "local", nil
+	}
+	if _, err := dc.cli.DistributionInspect(ctx, fullImage, ""); err != nil {
+		return "", utils.NewError(utils.CodeImagePullFailed, "image %s not found locally and not pullable: %w", fullImage, err)
+	}
+	return "registry", nil
+}
+
+// ImageDigests 返回本地镜像从仓库拉取时记录的 manifest 摘要（RepoDigests 中 @ 之后的部分），本地没有镜像时返回空列表
+// 参数:
+//   - ctx: 上下文对象
+//   - imageName: 镜像名称
+//   - tag: 镜像标签
+func (dc *DockerClient) ImageDigests(ctx context.IContext, imageName, tag string) ([]string, error) {
+	inspect, err := dc.cli.ImageInspect(ctx, fmt.Sprintf("%s:%s", imageName, tag))
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to inspect image %s:%s: %w", imageName, tag, err)
+	}
+	digests := make([]string, 0, len(inspect.RepoDigests))
+	for _, repoDigest := range inspect.RepoDigests {
+		if i := strings.Index(repoDigest, "@"); i >= 0 {
+			digests = append(digests, repoDigest[i+1:])
+		}
+	}
+	return digests, nil
+}
+
 // CreateContainerWithReplica 创建带副本编号的容器
 // 根据服务配置创建Docker容器，支持端口映射、环境变量、卷挂载等配置
 // 参数:
@@ -79,6 +158,28 @@ func (dc *DockerClient) CreateContainer(ctx context.IContext, service *Service,
 	containerPort := nat.Port(fmt.Sprintf("%d/tcp", service.InternalPort))
 	exposedPorts[containerPort] = struct{}{}
 
+	// 拉取镜像（在分配端口之前完成，避免长时间持有创建锁）
+	if err := dc.PullImage(ctx, service.Image, service.Tag); err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("ReplicaIndex", replicaIndex), log.Any("Message", "拉取镜像失败"))
+		return "", fmt.Errorf("failed to pull image: %w", err)
+	}
+
+	for _, sidecar := range service.Sidecars {
+		if err := dc.PullImage(ctx, sidecar.Image, sidecar.Tag); err != nil {
+			return "", fmt.Errorf("failed to pull image of sidecar %s: %w", sidecar.Name, err)
+		}
+	}
+
+	// 运行初始化容器（在加锁之前，避免长时间阻塞其他副本的创建）
+	if err := dc.runInitContainers(ctx, service, replicaIndex); err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("ServiceName", service.Name), log.Any("ReplicaIndex", replicaIndex), log.Any("Message", "初始化容器运行失败"))
+		return "", err
+	}
+
+	// 端口分配到容器创建完成之间加锁，支持并发创建副本
+	dc.createMutex.Lock()
+	defer dc.createMutex.Unlock()
+
 	// 重新获取最新的容器列表以确保端口分配正确
 	latestContainers, err := dc.ListContainers(ctx)
 	if err != nil {
@@ -90,50 +191,19 @@ func (dc *DockerClient) CreateContainer(ctx context.IContext, service *Service,
 	canUsePort := dc.findAvailablePortForService(latestContainers, service.Name)
 	service.DockerPort = canUsePort
 
-	// Docker主机映射端口 - 绑定到0.0.0.0允许外部访问
+	// Docker主机映射端口 - 绑定到 container.host_ip（默认 127.0.0.1，IPv6 可配置为 ::1）
 	portBindings[containerPort] = []nat.PortBinding{
 		{
-			HostIP:   "127.0.0.1",
+			HostIP:   dc.hostIP,
 			HostPort: strconv.Itoa(service.DockerPort),
 		},
 	}
 
-	// 处理环境变量：先读取EnvFile，再添加直接指定的Environment
-	allEnvVars := make(map[string]string)
-
-	// 1. 先从EnvFile读取环境变量
-	if service.EnvFile != "" {
-		envFileVars, err := dc.readEnvFile(service.EnvFile)
-		if err != nil {
-			log.Error("Docker", log.Any("Error", err), log.Any("EnvFile", service.EnvFile), log.Any("Message", "读取环境变量文件失败"))
-			return "", fmt.Errorf("failed to read env file: %w", err)
-		}
-		for k, v := range envFileVars {
-			allEnvVars[k] = v
-		}
-		log.Info("Docker", log.Any("EnvFile", service.EnvFile), log.Any("Count", len(envFileVars)), log.Any("Message", "成功读取环境变量文件"))
-	}
-
-	// 2. 直接指定的Environment会覆盖EnvFile中的同名变量
-	for k, v := range service.Environment {
-		allEnvVars[k] = v
-	}
-
-	// 3. 构建最终的环境变量列表
-	env := make([]string, 0, len(allEnvVars))
-	for k, v := range allEnvVars {
-		env = append(env, fmt.Sprintf("%s=%s", k, v))
-	}
-
-	// 构建卷挂载
-	binds := make([]string, 0, len(service.Volumes))
-	for _, volume := range service.Volumes {
-		bind := fmt.Sprintf("%s:%s", volume.Source, volume.Destination)
-		if volume.ReadOnly {
-			bind += ":ro"
-		}
-		binds = append(binds, bind)
+	env, secretEnv, err := dc.buildEnv(ctx, service.Name, service.EnvFile, service.Environment)
+	if err != nil {
+		return "", err
 	}
+	binds := buildBinds(service.Volumes)
 
 	// 构建标签
 	labels := map[string]string{
@@ -142,7 +212,37 @@ func (dc *DockerClient) CreateContainer(ctx context.IContext, service *Service,
 		dc.containerPrefix + ".image":       service.Image,
 		dc.containerPrefix + ".tag":         service.Tag,
 		dc.containerPrefix + ".public_port": strconv.Itoa(service.PublicPort),
+		dc.LabelKey("internal_port"):        strconv.Itoa(service.InternalPort),
 		dc.containerPrefix + ".platform":    runtime.GOOS, // 记录运行平台
+		dc.LabelKey("config_hash"):          dc.ConfigHash(service),
+	}
+	if service.Namespace != "" {
+		labels[dc.LabelKey("namespace")] = service.Namespace
+	}
+	if service.MemoryLimit > 0 {
+		labels[dc.LabelKey("memory_limit")] = strconv.Itoa(service.MemoryLimit)
+	}
+	if service.BindAddress != "" {
+		labels[dc.LabelKey("bind_address")] = service.BindAddress
+	}
+	if service.ProxyWorkers > 0 {
+		labels[dc.LabelKey("proxy_workers")] = strconv.Itoa(service.ProxyWorkers)
+	}
+	if len(service.RequestRules) > 0 {
+		if rules, err := utils.EnJson(service.RequestRules); err == nil {
+			labels[dc.LabelKey("request_rules")] = rules
+		}
+	}
+	if service.Mirror != nil && service.Mirror.Service != "" {
+		if mirror, err := utils.EnJson(service.Mirror); err == nil {
+			labels[dc.LabelKey("mirror")] = mirror
+		}
+	}
+	// 记录创建时来自密钥的变量，之后修改配置也能按创建时的来源隐藏明文
+	if len(secretEnv) > 0 {
+		if refs, err := utils.EnJson(secretEnv); err == nil {
+			labels[dc.LabelKey("secret_env")] = refs
+		}
 	}
 
 	// 容器配置
@@ -176,13 +276,18 @@ func (dc *DockerClient) CreateContainer(ctx context.IContext, service *Service,
 
 	// 添加重启策略 --restart always
 	hostConfig.RestartPolicy = container.RestartPolicy{
-		Name: "always",
+		Name: container.RestartPolicyAlways,
 	}
 
 	// 添加安全参数
 	hostConfig.ReadonlyRootfs = false // 默认不启用只读文件系统，避免影响应用写入
 	hostConfig.Privileged = false     // 禁用特权模式
 
+	// 内存上限
+	if service.MemoryLimit > 0 {
+		hostConfig.Resources.Memory = int64(service.MemoryLimit) * 1024 * 1024
+	}
+
 	// 日志配置
 	hostConfig.LogConfig = container.LogConfig{
 		Type: "json-file",
@@ -192,12 +297,6 @@ func (dc *DockerClient) CreateContainer(ctx context.IContext, service *Service,
 		},
 	}
 
-	// 拉取镜像
-	if err := dc.PullImage(ctx, service.Image, service.Tag); err != nil {
-		log.Error("Docker", log.Any("Error", err), log.Any("ReplicaIndex", replicaIndex), log.Any("Message", "拉取镜像失败"))
-		return "", fmt.Errorf("failed to pull image: %w", err)
-	}
-
 	// 创建容器 - 使用新的命名规则：prefix-serviceName-p{publicPort}-c{containerPort}-{replicaIndex}
 	containerName := dc.generateContainerName(service.Name, service.PublicPort, service.DockerPort, replicaIndex)
 
@@ -208,9 +307,82 @@ func (dc *DockerClient) CreateContainer(ctx context.IContext, service *Service,
 	}
 
 	log.Info("Docker", log.Any("ContainerName", containerName), log.Any("ID", resp.ID[:12]), log.Any("Message", "容器创建成功"))
+
+	// 创建边车容器，失败时连同主容器一起删除
+	for _, sidecar := range service.Sidecars {
+		if err := dc.createSidecar(ctx, resp.ID, containerName, service, sidecar); err != nil {
+			dc.RemoveContainer(ctx, resp.ID)
+			return "", err
+		}
+	}
 	return resp.ID, nil
 }
 
+// createSidecar 为副本创建边车容器，加入主容器的网络命名空间并挂载主容器的卷
+func (dc *DockerClient) createSidecar(ctx context.IContext, mainID, mainName string, service *Service, sidecar Sidecar) error {
+	env, _, err := dc.buildEnv(ctx, service.Name, "", sidecar.Environment)
+	if err != nil {
+		return fmt.Errorf("sidecar %s: %w", sidecar.Name, err)
+	}
+
+	config := &container.Config{
+		Image: fmt.Sprintf("%s:%s", sidecar.Image, sidecar.Tag),
+		Env:   env,
+		Labels: map[string]string{
+			dc.containerPrefix + ".managed": "true",
+			dc.containerPrefix + ".service": service.Name,
+			dc.LabelKey("sidecar"):          sidecar.Name,
+			dc.LabelKey("sidecar_of"):       mainID,
+		},
+	}
+	if len(sidecar.Command) > 0 {
+		config.Cmd = sidecar.Command
+	}
+	if len(sidecar.Entrypoint) > 0 {
+		config.Entrypoint = sidecar.Entrypoint
+	}
+
+	hostConfig := &container.HostConfig{
+		NetworkMode:   container.NetworkMode("container:" + mainID),
+		VolumesFrom:   []string{mainID},
+		Binds:         buildBinds(sidecar.Volumes),
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyAlways},
+		LogConfig: container.LogConfig{
+			Type:   "json-file",
+			Config: map[string]string{"max-size": "10m", "max-file": "3"},
+		},
+	}
+	if sidecar.MemoryLimit > 0 {
+		hostConfig.Resources.Memory = int64(sidecar.MemoryLimit) * 1024 * 1024
+	}
+
+	name := fmt.Sprintf("%s-sidecar-%s", mainName, sidecar.Name)
+	resp, err := dc.cli.ContainerCreate(ctx, config, hostConfig, nil, nil, name)
+	if err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", name), log.Any("Message", "边车容器创建失败"))
+		return fmt.Errorf("failed to create sidecar %s: %w", sidecar.Name, err)
+	}
+	log.Info("Docker", log.Any("ContainerName", name), log.Any("ID", resp.ID[:12]), log.Any("Message", "边车容器创建成功"))
+	return nil
+}
+
+// sidecarsOf 列出主容器的边车容器ID
+func (dc *DockerClient) sidecarsOf(ctx context.IContext, mainID string) []string {
+	containers, err := dc.cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", dc.LabelKey("sidecar_of")+"="+mainID)),
+	})
+	if err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("ID", mainID[:12]), log.Any("Message", "获取边车容器失败"))
+		return nil
+	}
+	ids := make([]string, 0, len(containers))
+	for _, c := range containers {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
 // StartContainer 启动指定的Docker容器
 // 参数:
 //   - ctx: 上下文对象
@@ -225,6 +397,14 @@ func (dc *DockerClient) StartContainer(ctx context.IContext, containerID string)
 	}
 
 	log.Info("Docker", log.Any("ID", containerID[:12]), log.Any("Message", "容器启动成功"))
+
+	// 边车容器加入主容器的网络命名空间，必须在主容器启动后启动
+	for _, id := range dc.sidecarsOf(ctx, containerID) {
+		if err := dc.cli.ContainerStart(ctx, id, container.StartOptions{}); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ID", id[:12]), log.Any("Message", "边车容器启动失败"))
+			return fmt.Errorf("failed to start sidecar %s: %w", id[:12], err)
+		}
+	}
 	return nil
 }
 
@@ -235,6 +415,14 @@ func (dc *DockerClient) StartContainer(ctx context.IContext, containerID string)
 //   - containerID: 容器ID
 func (dc *DockerClient) StopContainer(ctx context.IContext, containerID string) error {
 	timeout := 30 // 30秒超时
+
+	// 先停止边车容器，主容器停止后它们会失去网络
+	for _, id := range dc.sidecarsOf(ctx, containerID) {
+		if err := dc.cli.ContainerStop(ctx, id, container.StopOptions{Timeout: &timeout}); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ID", id[:12]), log.Any("Message", "边车容器停止失败"))
+		}
+	}
+
 	err := dc.cli.ContainerStop(ctx, containerID, container.StopOptions{
 		Timeout: &timeout,
 	})
@@ -247,12 +435,55 @@ func (dc *DockerClient) StopContainer(ctx context.IContext, containerID string)
 	return nil
 }
 
+// SetRestartPolicy 更新容器的重启策略
+// 参数:
+//   - ctx: 上下文对象
+//   - containerID: 容器ID
+//   - policy: 重启策略，如 always / no
+func (dc *DockerClient) SetRestartPolicy(ctx context.IContext, containerID string, policy container.RestartPolicyMode) error {
+	// 边车容器与主容器使用相同的重启策略
+	for _, id := range append(dc.sidecarsOf(ctx, containerID), containerID) {
+		_, err := dc.cli.ContainerUpdate(ctx, id, container.UpdateConfig{
+			RestartPolicy: container.RestartPolicy{Name: policy},
+		})
+		if err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ID", id[:12]), log.Any("Policy", policy), log.Any("Message", "更新重启策略失败"))
+			return fmt.Errorf("failed to update restart policy of container %s: %w", id[:12], err)
+		}
+	}
+	return nil
+}
+
+// StopReplica 停止副本但保留容器
+// 先将重启策略改为 no，避免 Docker 守护进程重启后自动拉起已停止的副本
+func (dc *DockerClient) StopReplica(ctx context.IContext, containerID string) error {
+	if err := dc.SetRestartPolicy(ctx, containerID, container.RestartPolicyDisabled); err != nil {
+		return err
+	}
+	return dc.StopContainer(ctx, containerID)
+}
+
+// StartReplica 启动已停止的副本，并恢复 always 重启策略
+func (dc *DockerClient) StartReplica(ctx context.IContext, containerID string) error {
+	if err := dc.SetRestartPolicy(ctx, containerID, container.RestartPolicyAlways); err != nil {
+		return err
+	}
+	return dc.StartContainer(ctx, containerID)
+}
+
 // RemoveContainer 删除指定的Docker容器
 // 强制删除，即使容器正在运行
 // 参数:
 //   - ctx: 上下文对象
 //   - containerID: 容器ID
 func (dc *DockerClient) RemoveContainer(ctx context.IContext, containerID string) error {
+	// 先删除边车容器，否则主容器会因被引用而无法删除
+	for _, id := range dc.sidecarsOf(ctx, containerID) {
+		if err := dc.cli.ContainerRemove(ctx, id, container.RemoveOptions{Force: true}); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ID", id[:12]), log.Any("Message", "边车容器删除失败"))
+		}
+	}
+
 	err := dc.cli.ContainerRemove(ctx, containerID, container.RemoveOptions{
 		Force: true, // 强制删除，即使容器正在运行
 	})
@@ -270,6 +501,49 @@ func (dc *DockerClient) RemoveContainer(ctx context.IContext, containerID string
 // 参数:
 //   - ctx: 上下文对象
 func (dc *DockerClient) ListContainers(ctx context.IContext) ([]ContainerInfo, error) {
+	return dc.listContainers(ctx, func(name string, labels map[string]string) bool {
+		// 边车容器的名称以副本名称开头，按标签排除
+		if labels[dc.LabelKey("sidecar_of")] != "" {
+			return false
+		}
+		_, err := dc.ParseContainerName(name)
+		return err == nil
+	})
+}
+
+// ListPrefixedContainers 列出所有带管理前缀的容器，包括名称无法解析的容器，用于清理孤立容器
+// 参数:
+//   - ctx: 上下文对象
+func (dc *DockerClient) ListPrefixedContainers(ctx context.IContext) ([]ContainerInfo, error) {
+	return dc.listContainers(ctx, func(name string, labels map[string]string) bool {
+		return dc.containerPrefix != "" && strings.HasPrefix(name, dc.containerPrefix+"-")
+	})
+}
+
+// HostPortContainer 返回发布了指定主机端口的容器名称（包括非 OneDock 管理的容器），没有时返回空字符串
+// 参数:
+//   - ctx: 上下文对象
+//   - port: 主机端口
+func (dc *DockerClient) HostPortContainer(ctx context.IContext, port int) (string, error) {
+	containers, err := dc.listContainers(ctx, func(name string, labels map[string]string) bool {
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	hostPort := strconv.Itoa(port)
+	for _, cont := range containers {
+		for _, mapping := range cont.Ports {
+			if mapping.HostPort == hostPort {
+				return cont.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// listContainers 列出名称和标签满足条件的容器
+func (dc *DockerClient) listContainers(ctx context.IContext, match func(name string, labels map[string]string) bool) ([]ContainerInfo, error) {
 	containers, err := dc.cli.ContainerList(ctx, container.ListOptions{
 		All: true,
 	})
@@ -287,9 +561,8 @@ func (dc *DockerClient) ListContainers(ctx context.IContext) ([]ContainerInfo, e
 		}
 
 		// 只处理管理的容器
-		_, err := dc.ParseContainerName(name)
-		if err != nil {
-			continue // 跳过非管理的容器
+		if !match(name, cont.Labels) {
+			continue
 		}
 
 		// 解析端口映射
@@ -304,6 +577,34 @@ func (dc *DockerClient) ListContainers(ctx context.IContext) ([]ContainerInfo, e
 			}
 		}
 
+		mounts := make([]MountInfo, 0, len(cont.Mounts))
+		for _, m := range cont.Mounts {
+			mounts = append(mounts, MountInfo{
+				Type:        string(m.Type),
+				Name:        m.Name,
+				Source:      m.Source,
+				Destination: m.Destination,
+				Mode:        m.Mode,
+				ReadWrite:   m.RW,
+			})
+		}
+
+		networks := make([]NetworkEndpoint, 0)
+		if cont.NetworkSettings != nil {
+			for networkName, endpoint := range cont.NetworkSettings.Networks {
+				if endpoint == nil {
+					continue
+				}
+				networks = append(networks, NetworkEndpoint{
+					Network:    networkName,
+					IPAddress:  endpoint.IPAddress,
+					Gateway:    endpoint.Gateway,
+					MacAddress: endpoint.MacAddress,
+				})
+			}
+			sort.Slice(networks, func(i, j int) bool { return networks[i].Network < networks[j].Network })
+		}
+
 		info := ContainerInfo{
 			ID:        cont.ID,
 			Name:      name,
@@ -313,6 +614,8 @@ func (dc *DockerClient) ListContainers(ctx context.IContext) ([]ContainerInfo, e
 			Ports:     ports,
 			Labels:    cont.Labels,
 			CreatedAt: fmt.Sprintf("%d", cont.Created),
+			Mounts:    mounts,
+			Networks:  networks,
 		}
 
 		result = append(result, info)
@@ -332,7 +635,11 @@ func (dc *DockerClient) InspectContainer(ctx context.IContext, containerID strin
 		log.Error("Docker", log.Any("Error", err), log.Any("ID", containerID[:12]), log.Any("Message", "检查容器详情失败"))
 		return nil, fmt.Errorf("failed to inspect container %s: %w", containerID[:12], err)
 	}
+	return containerInfoFromInspect(inspect), nil
+}
 
+// containerInfoFromInspect 将检查结果转换为容器基本信息
+func containerInfoFromInspect(inspect container.InspectResponse) *ContainerInfo {
 	// 解析端口映射
 	ports := make([]PortMapping, 0)
 	if inspect.NetworkSettings != nil && inspect.NetworkSettings.Ports != nil {
@@ -352,7 +659,7 @@ func (dc *DockerClient) InspectContainer(ctx context.IContext, containerID strin
 	// 获取容器名称
 	name := strings.TrimPrefix(inspect.Name, "/")
 
-	info := &ContainerInfo{
+	return &ContainerInfo{
 		ID:        inspect.ID,
 		Name:      name,
 		Image:     inspect.Config.Image,
@@ -362,8 +669,76 @@ func (dc *DockerClient) InspectContainer(ctx context.IContext, containerID strin
 		Labels:    inspect.Config.Labels,
 		CreatedAt: inspect.Created,
 	}
+}
 
-	return info, nil
+// InspectContainerDetail 获取容器的完整检查详情：配置、挂载、网络、运行状态和健康检查
+func (dc *DockerClient) InspectContainerDetail(ctx context.IContext, containerID string) (*ContainerDetail, error) {
+	inspect, err := dc.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("ID", containerID[:12]), log.Any("Message", "检查容器详情失败"))
+		return nil, fmt.Errorf("failed to inspect container %s: %w", containerID[:12], err)
+	}
+
+	detail := &ContainerDetail{
+		ContainerInfo: *containerInfoFromInspect(inspect),
+		ImageID:       inspect.Image,
+		Entrypoint:    inspect.Config.Entrypoint,
+		Command:       inspect.Config.Cmd,
+		WorkingDir:    inspect.Config.WorkingDir,
+		Env:           inspect.Config.Env,
+		Mounts:        make([]MountInfo, 0, len(inspect.Mounts)),
+		Networks:      make([]NetworkEndpoint, 0),
+		RestartCount:  inspect.RestartCount,
+	}
+	if inspect.HostConfig != nil {
+		detail.NetworkMode = string(inspect.HostConfig.NetworkMode)
+		detail.RestartPolicy = string(inspect.HostConfig.RestartPolicy.Name)
+		detail.MemoryLimit = inspect.HostConfig.Memory
+	}
+	for _, m := range inspect.Mounts {
+		detail.Mounts = append(detail.Mounts, MountInfo{
+			Type:        string(m.Type),
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+			Mode:        m.Mode,
+			ReadWrite:   m.RW,
+		})
+	}
+	if inspect.NetworkSettings != nil {
+		for name, endpoint := range inspect.NetworkSettings.Networks {
+			if endpoint == nil {
+				continue
+			}
+			detail.Networks = append(detail.Networks, NetworkEndpoint{
+				Network:    name,
+				IPAddress:  endpoint.IPAddress,
+				Gateway:    endpoint.Gateway,
+				MacAddress: endpoint.MacAddress,
+			})
+		}
+		sort.Slice(detail.Networks, func(i, j int) bool { return detail.Networks[i].Network < detail.Networks[j].Network })
+	}
+
+	if state := inspect.State; state != nil {
+		detail.Running = state.Running
+		detail.OOMKilled = state.OOMKilled
+		detail.Pid = state.Pid
+		detail.ExitCode = state.ExitCode
+		detail.Error = state.Error
+		detail.StartedAt, _ = time.Parse(time.RFC3339Nano, state.StartedAt)
+		detail.FinishedAt, _ = time.Parse(time.RFC3339Nano, state.FinishedAt)
+		if state.Health != nil {
+			detail.Health = string(state.Health.Status)
+			detail.FailingStreak = state.Health.FailingStreak
+			for _, result := range state.Health.Log {
+				if result != nil {
+					detail.HealthLog = append(detail.HealthLog, strings.TrimSpace(result.Output))
+				}
+			}
+		}
+	}
+	return detail, nil
 }
 
 // GetNextReplicaIndex 获取服务的下一个可用副本编号
@@ -407,6 +782,12 @@ func (dc *DockerClient) GetNextReplicaIndex(ctx context.IContext, serviceName st
 //   - serviceName: 服务名称
 //   - targetReplicas: 目标副本数量
 func (dc *DockerClient) ScaleService(ctx context.IContext, serviceName string, targetReplicas int) error {
+	return dc.ScaleServiceWithConfig(ctx, serviceName, targetReplicas, nil)
+}
+
+// ScaleServiceWithConfig 缩放服务副本数量，扩容时使用指定的服务配置创建新副本
+// config 为空时从现有容器标签提取配置（环境变量、卷挂载等无法从标签恢复）
+func (dc *DockerClient) ScaleServiceWithConfig(ctx context.IContext, serviceName string, targetReplicas int, config *Service) error {
 	// 第一步：查看当前服务容器数量
 	containers, err := dc.ListContainers(ctx)
 	if err != nil {
@@ -433,10 +814,13 @@ func (dc *DockerClient) ScaleService(ctx context.IContext, serviceName string, t
 
 	currentReplicas := len(serviceContainers)
 
-	// 第二步：从其中一个容器提取Service配置
-	serviceConfig, err := dc.ExtractServiceFromContainer(serviceContainers[0])
-	if err != nil {
-		return fmt.Errorf("failed to extract service config from container: %w", err)
+	// 第二步：未指定配置时从其中一个容器提取Service配置
+	serviceConfig := config
+	if serviceConfig == nil {
+		serviceConfig, err = dc.ExtractServiceFromContainer(serviceContainers[0])
+		if err != nil {
+			return fmt.Errorf("failed to extract service config from container: %w", err)
+		}
 	}
 
 	// 第三步：根据当前副本数与目标副本数执行扩容或缩容
@@ -457,6 +841,9 @@ func (dc *DockerClient) ScaleService(ctx context.IContext, serviceName string, t
 //   - targetReplicas: 目标副本数
 func (dc *DockerClient) scaleUp(ctx context.IContext, serviceConfig *Service, currentReplicas, targetReplicas int) error {
 	for i := currentReplicas; i < targetReplicas; i++ {
+		ReportProgress(ctx, Progress{Step: "creating", Current: i, Total: targetReplicas,
+			Message: fmt.Sprintf("creating replica %d/%d", i+1, targetReplicas)})
+
 		// 获取下一个可用的副本编号
 		replicaIndex, err := dc.GetNextReplicaIndex(ctx, serviceConfig.Name)
 		if err != nil {
@@ -476,20 +863,7 @@ func (dc *DockerClient) scaleUp(ctx context.IContext, serviceConfig *Service, cu
 		newDockerPort := canUsePort
 
 		// 创建副本服务配置
-		replicaService := &Service{
-			Name:         serviceConfig.Name,
-			Image:        serviceConfig.Image,
-			Tag:          serviceConfig.Tag,
-			PublicPort:   serviceConfig.PublicPort,
-			InternalPort: serviceConfig.InternalPort,
-			DockerPort:   newDockerPort,
-			Environment:  serviceConfig.Environment,
-			Volumes:      serviceConfig.Volumes,
-			Entrypoint:   serviceConfig.Entrypoint,
-			Command:      serviceConfig.Command,
-			WorkingDir:   serviceConfig.WorkingDir,
-			Replicas:     1,
-		}
+		replicaService := replicaConfig(serviceConfig, newDockerPort)
 
 		// 创建容器
 		containerID, err := dc.CreateContainer(ctx, replicaService, replicaIndex)
@@ -510,6 +884,15 @@ func (dc *DockerClient) scaleUp(ctx context.IContext, serviceConfig *Service, cu
 	return nil
 }
 
+// replicaConfig 由服务配置生成单个副本的配置：复制全部字段（包括初始化容器和边车容器），副本数为 1
+// dockerPort 为 0 时由 CreateContainer 分配
+func replicaConfig(config *Service, dockerPort int) *Service {
+	replica := *config
+	replica.DockerPort = dockerPort
+	replica.Replicas = 1
+	return &replica
+}
+
 // scaleDown 缩容操作 - 删除多余的副本容器
 // 参数:
 //   - ctx: 上下文对象
@@ -560,6 +943,109 @@ func (dc *DockerClient) removeReplica(ctx context.IContext, container ContainerI
 	return nil
 }
 
+// trashedName 回收站中容器的名称，不以 "{prefix}-" 开头，不会被当作受管副本或孤立容器
+func (dc *DockerClient) trashedName(name string) string {
+	return dc.containerPrefix + "_trash_" + name
+}
+
+// TrashReplica 停止副本（含边车容器）并改名移出管理范围，服务放入回收站时保留容器
+// 参数:
+//   - ctx: 上下文对象
+//   - containerID: 主容器ID
+func (dc *DockerClient) TrashReplica(ctx context.IContext, containerID string) error {
+	if err := dc.StopReplica(ctx, containerID); err != nil {
+		return err
+	}
+	return dc.renameReplica(ctx, containerID, dc.trashedName)
+}
+
+// RestoreReplica 将回收站中的副本（含边车容器）改回原名称，不启动容器
+// 容器已不存在时返回 NOT_FOUND
+// 参数:
+//   - ctx: 上下文对象
+//   - containerID: 主容器ID
+func (dc *DockerClient) RestoreReplica(ctx context.IContext, containerID string) error {
+	return dc.renameReplica(ctx, containerID, func(name string) string {
+		return strings.TrimPrefix(name, dc.trashedName(""))
+	})
+}
+
+// CheckTrashedReplica 检查回收站中的副本能否恢复：原名称和主机端口没有被其他副本使用，主机端口没有被其他进程占用
+// 副本停止期间端口可能已分配给新的副本，恢复前检查以免启动时绑定端口失败
+// 容器已不存在时返回 NOT_FOUND，名称或端口已被占用时返回 CONFLICT
+// 参数:
+//   - ctx: 上下文对象
+//   - containerID: 主容器ID
+func (dc *DockerClient) CheckTrashedReplica(ctx context.IContext, containerID string) error {
+	info, err := dc.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return utils.NewError(utils.CodeNotFound, "container %.12s not found", containerID)
+		}
+		return fmt.Errorf("failed to inspect container %.12s: %w", containerID, err)
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(info.Name, "/"), dc.trashedName(""))
+	replica, err := dc.ParseContainerName(name)
+	if err != nil {
+		return fmt.Errorf("failed to parse name of trashed container %.12s: %w", containerID, err)
+	}
+
+	containers, err := dc.ListContainers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, container := range containers {
+		if container.Name == name {
+			return utils.NewError(utils.CodeConflict, "container %s already exists", name)
+		}
+		if used, err := dc.ParseContainerName(container.Name); err == nil && used.ContainerPort == replica.ContainerPort {
+			return utils.NewError(utils.CodeConflict, "host port %d of container %s is used by container %s", replica.ContainerPort, name, container.Name)
+		}
+	}
+	if dc.isPortOccupied(replica.ContainerPort) {
+		return utils.NewError(utils.CodeConflict, "host port %d of container %s is occupied", replica.ContainerPort, name)
+	}
+	return nil
+}
+
+// RemoveTrashedReplica 删除回收站中的副本（含边车容器），容器已不存在时忽略
+// 参数:
+//   - ctx: 上下文对象
+//   - containerID: 主容器ID
+func (dc *DockerClient) RemoveTrashedReplica(ctx context.IContext, containerID string) error {
+	if _, err := dc.cli.ContainerInspect(ctx, containerID); cerrdefs.IsNotFound(err) {
+		return nil
+	}
+	return dc.RemoveContainer(ctx, containerID)
+}
+
+// renameReplica 按 rename 修改主容器及其边车容器的名称
+func (dc *DockerClient) renameReplica(ctx context.IContext, containerID string, rename func(string) string) error {
+	for _, id := range append(dc.sidecarsOf(ctx, containerID), containerID) {
+		info, err := dc.cli.ContainerInspect(ctx, id)
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				return utils.NewError(utils.CodeNotFound, "container %.12s not found", id)
+			}
+			return fmt.Errorf("failed to inspect container %.12s: %w", id, err)
+		}
+		name := strings.TrimPrefix(info.Name, "/")
+		if err := dc.cli.ContainerRename(ctx, id, rename(name)); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", name), log.Any("Message", "容器改名失败"))
+			return fmt.Errorf("failed to rename container %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// RemoveReplica 停止并删除单个容器
+// 参数:
+//   - ctx: 上下文对象
+//   - container: 要删除的容器信息
+func (dc *DockerClient) RemoveReplica(ctx context.IContext, container ContainerInfo) error {
+	return dc.removeReplica(ctx, container)
+}
+
 // UpdateContainer 滚动更新容器 - 创建新容器替换旧容器
 // 此方法实现零停机的滚动更新：创建新容器，启动成功后删除旧容器
 // 参数:
@@ -568,6 +1054,22 @@ func (dc *DockerClient) removeReplica(ctx context.IContext, container ContainerI
 //   - newService: 新的服务配置
 //   - replicaIndex: 要更新的副本索引
 func (dc *DockerClient) UpdateContainer(ctx context.IContext, serviceName string, newService *Service, replicaIndex int) (string, int, error) {
+	return dc.updateContainer(ctx, serviceName, newService, replicaIndex, false)
+}
+
+// ReplaceContainer 替换容器 - 先删除旧容器再创建新容器
+// 更新期间该副本不可用，用于 max_unavailable 策略，不额外占用资源
+// 参数:
+//   - ctx: 上下文对象
+//   - serviceName: 服务名称
+//   - newService: 新的服务配置
+//   - replicaIndex: 要替换的副本索引
+func (dc *DockerClient) ReplaceContainer(ctx context.IContext, serviceName string, newService *Service, replicaIndex int) (string, int, error) {
+	return dc.updateContainer(ctx, serviceName, newService, replicaIndex, true)
+}
+
+// updateContainer 更新单个副本，removeFirst 为 true 时先删除旧容器
+func (dc *DockerClient) updateContainer(ctx context.IContext, serviceName string, newService *Service, replicaIndex int, removeFirst bool) (string, int, error) {
 	// 第一步：查找要更新的旧容器
 	containers, err := dc.ListContainers(ctx)
 	if err != nil {
@@ -594,43 +1096,30 @@ func (dc *DockerClient) UpdateContainer(ctx context.IContext, serviceName string
 	log.Info("Docker", log.Any("ServiceName", serviceName), log.Any("ReplicaIndex", replicaIndex),
 		log.Any("OldContainer", oldContainer.ID[:12]), log.Any("Message", "开始滚动更新容器"))
 
-	// 第二步：为新容器分配端口
-	latestContainers, err := dc.ListContainers(ctx)
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to get latest containers: %w", err)
-	}
-
-	newDockerPort := dc.findAvailablePortForService(latestContainers, serviceName)
+	// 第二步：创建新服务配置（端口在 CreateContainer 中分配）
+	updateService := replicaConfig(newService, 0)
 
-	// 第三步：创建新服务配置（使用新端口）
-	updateService := &Service{
-		Name:         newService.Name,
-		Image:        newService.Image,
-		Tag:          newService.Tag,
-		PublicPort:   newService.PublicPort,
-		InternalPort: newService.InternalPort,
-		DockerPort:   newDockerPort,
-		Environment:  newService.Environment,
-		EnvFile:      newService.EnvFile,
-		Volumes:      newService.Volumes,
-		Entrypoint:   newService.Entrypoint,
-		Command:      newService.Command,
-		WorkingDir:   newService.WorkingDir,
-		Replicas:     1,
-	}
-
-	// 第四步：拉取新镜像
+	// 第三步：拉取新镜像（先删除旧容器时也要确保镜像可用，避免副本长时间缺失）
 	log.Info("Docker", log.Any("Image", fmt.Sprintf("%s:%s", updateService.Image, updateService.Tag)),
 		log.Any("Message", "开始拉取新镜像"))
 	if err := dc.PullImage(ctx, updateService.Image, updateService.Tag); err != nil {
 		return "", 0, fmt.Errorf("failed to pull new image: %w", err)
 	}
 
+	// 第四步：先删除旧容器（仅 removeFirst）
+	if removeFirst {
+		log.Info("Docker", log.Any("OldContainer", oldContainer.ID[:12]), log.Any("Message", "先删除旧容器"))
+		if err := dc.removeReplica(ctx, *oldContainer); err != nil {
+			return "", 0, fmt.Errorf("failed to remove old container: %w", err)
+		}
+	}
+
 	// 第五步：创建新容器
 	newContainerID, err := dc.CreateContainer(ctx, updateService, replicaIndex)
 	if err != nil {
 		return "", 0, fmt.Errorf("failed to create new container: %w", err)
 	}
+	newDockerPort := updateService.DockerPort
 
 	// 第六步：启动新容器
 	if err := dc.StartContainer(ctx, newContainerID); err != nil {
@@ -643,23 +1132,29 @@ func (dc *DockerClient) UpdateContainer(ctx context.IContext, serviceName string
 		log.Any("NewContainer", newContainerID[:12]), log.Any("NewPort", newDockerPort),
 		log.Any("Message", "新容器启动成功"))
 
-	// 第七步：等待一段时间确保新容器稳定运行
-	// TODO: 这里可以添加健康检查逻辑
-	// time.Sleep(5 * time.Second)
-
-	// 第八步：停止旧容器
-	log.Info("Docker", log.Any("OldContainer", oldContainer.ID[:12]), log.Any("Message", "停止旧容器"))
-	if err := dc.StopContainer(ctx, oldContainer.ID); err != nil {
-		log.Error("Docker", log.Any("Error", err), log.Any("OldContainer", oldContainer.ID[:12]),
-			log.Any("Message", "停止旧容器失败，但新容器已启动"))
+	// 第七步：观察新容器，确认没有崩溃且健康检查通过
+	if err := dc.WaitContainerReady(ctx, newContainerID, dc.verifyTimeout); err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("NewContainer", newContainerID[:12]), log.Any("Message", "新容器验证失败"))
+		dc.StopContainer(ctx, newContainerID)
+		dc.RemoveContainer(ctx, newContainerID)
+		return "", 0, fmt.Errorf("new container failed verification: %w", err)
 	}
 
-	// 第九步：删除旧容器
-	if err := dc.RemoveContainer(ctx, oldContainer.ID); err != nil {
-		log.Error("Docker", log.Any("Error", err), log.Any("OldContainer", oldContainer.ID[:12]),
-			log.Any("Message", "删除旧容器失败，但新容器已启动"))
-	} else {
-		log.Info("Docker", log.Any("OldContainer", oldContainer.ID[:12]), log.Any("Message", "旧容器已删除"))
+	if !removeFirst {
+		// 第八步：停止旧容器
+		log.Info("Docker", log.Any("OldContainer", oldContainer.ID[:12]), log.Any("Message", "停止旧容器"))
+		if err := dc.StopContainer(ctx, oldContainer.ID); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("OldContainer", oldContainer.ID[:12]),
+				log.Any("Message", "停止旧容器失败，但新容器已启动"))
+		}
+
+		// 第九步：删除旧容器
+		if err := dc.RemoveContainer(ctx, oldContainer.ID); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("OldContainer", oldContainer.ID[:12]),
+				log.Any("Message", "删除旧容器失败，但新容器已启动"))
+		} else {
+			log.Info("Docker", log.Any("OldContainer", oldContainer.ID[:12]), log.Any("Message", "旧容器已删除"))
+		}
 	}
 
 	log.Info("Docker", log.Any("ServiceName", serviceName), log.Any("ReplicaIndex", replicaIndex),
@@ -667,3 +1162,673 @@ func (dc *DockerClient) UpdateContainer(ctx context.IContext, serviceName string
 
 	return newContainerID, newDockerPort, nil
 }
+
+// WaitContainerReady 在观察时间内确认容器稳定运行
+// 容器退出、发生重启或健康检查失败时返回错误；配置了 HEALTHCHECK 的容器需在观察时间内变为 healthy
+// 参数:
+//   - ctx: 上下文对象
+//   - containerID: 容器ID
+//   - window: 观察时间，为 0 时直接返回
+func (dc *DockerClient) WaitContainerReady(ctx context.IContext, containerID string, window time.Duration) error {
+	if window <= 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(window)
+	for {
+		inspect, err := dc.cli.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return fmt.Errorf("failed to inspect container: %w", err)
+		}
+
+		state := inspect.State
+		if state == nil {
+			return fmt.Errorf("container state unavailable")
+		}
+		if state.OOMKilled {
+			return fmt.Errorf("container was OOM killed")
+		}
+		if state.Status == "exited" || state.Status == "dead" {
+			return fmt.Errorf("container exited with code %d", state.ExitCode)
+		}
+		if state.Restarting || inspect.RestartCount > 0 {
+			return fmt.Errorf("container is crash looping (restart count %d)", inspect.RestartCount)
+		}
+		if state.Health != nil {
+			switch state.Health.Status {
+			case "healthy":
+				return nil
+			case "unhealthy":
+				return fmt.Errorf("container health check failed")
+			}
+		}
+
+		if time.Now().After(deadline) {
+			if state.Health != nil {
+				return fmt.Errorf("container did not become healthy within %s", window)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// CreateReplicas 按服务配置创建指定数量的新副本
+// 用于更新失败回滚等场景，副本编号和端口自动分配
+func (dc *DockerClient) CreateReplicas(ctx context.IContext, service *Service, count int) error {
+	if count <= 0 {
+		return nil
+	}
+	return dc.scaleUp(ctx, service, 0, count)
+}
+
+// ContainerStats 获取容器的 CPU、内存、网络和块设备 I/O 使用情况
+// 使用非流式接口，Docker 会采样两次以计算 CPU 使用率，耗时约 1 秒
+// 参数:
+//   - ctx: 上下文对象
+//   - containerID: 容器ID
+func (dc *DockerClient) ContainerStats(ctx context.IContext, containerID string) (*ContainerStats, error) {
+	resp, err := dc.cli.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats of container %s: %w", containerID[:12], err)
+	}
+	defer resp.Body.Close()
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("failed to decode stats of container %s: %w", containerID[:12], err)
+	}
+	return calculateStats(&stats), nil
+}
+
+// Ping 检查 Docker 守护进程是否可达
+func (dc *DockerClient) Ping(ctx context.IContext) error {
+	if _, err := dc.cli.Ping(ctx); err != nil {
+		return utils.NewError(utils.CodeDockerUnavailable, "docker daemon is unreachable: %w", err)
+	}
+	return nil
+}
+
+// DaemonInfo 查询 Docker 守护进程版本，守护进程不可达时返回错误
+func (dc *DockerClient) DaemonInfo(ctx context.IContext) (*DaemonInfo, error) {
+	version, err := dc.cli.ServerVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("docker daemon is unreachable: %w", err)
+	}
+	info := &DaemonInfo{
+		Version:       version.Version,
+		APIVersion:    version.APIVersion,
+		ClientVersion: dc.cli.ClientVersion(),
+		OS:            version.Os,
+		Arch:          version.Arch,
+		KernelVersion: version.KernelVersion,
+	}
+	if system, err := dc.cli.Info(ctx); err == nil {
+		info.RootDir = system.DockerRootDir
+	}
+	return info, nil
+}
+
+// DiskUsage 统计镜像、容器、卷和构建缓存的磁盘占用
+func (dc *DockerClient) DiskUsage(ctx context.IContext) (*DiskUsage, error) {
+	df, err := dc.cli.DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get docker disk usage: %w", err)
+	}
+	usage := &DiskUsage{
+		Images:         len(df.Images),
+		ImagesSize:     df.LayersSize,
+		Containers:     len(df.Containers),
+		Volumes:        len(df.Volumes),
+		LayersSize:     df.LayersSize,
+		ImageItems:     make([]ImageUsage, 0, len(df.Images)),
+		ContainerItems: make([]ContainerUsage, 0, len(df.Containers)),
+		VolumeItems:    make([]VolumeInfo, 0, len(df.Volumes)),
+	}
+	for _, img := range df.Images {
+		if img == nil {
+			continue
+		}
+		usage.ImageItems = append(usage.ImageItems, ImageUsage{
+			ID:         img.ID,
+			Tags:       img.RepoTags,
+			Size:       img.Size,
+			SharedSize: img.SharedSize,
+			Containers: img.Containers,
+			Created:    img.Created,
+		})
+	}
+	for _, c := range df.Containers {
+		if c == nil {
+			continue
+		}
+		usage.ContainersSize += c.SizeRw
+		item := ContainerUsage{
+			ID:         c.ID,
+			Image:      c.Image,
+			ImageID:    c.ImageID,
+			State:      c.State,
+			SizeRw:     c.SizeRw,
+			SizeRootFs: c.SizeRootFs,
+		}
+		if len(c.Names) > 0 {
+			item.Name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		for _, m := range c.Mounts {
+			if m.Type == "volume" && m.Name != "" {
+				item.Volumes = append(item.Volumes, m.Name)
+			}
+		}
+		usage.ContainerItems = append(usage.ContainerItems, item)
+	}
+	for _, v := range df.Volumes {
+		if v == nil {
+			continue
+		}
+		if v.UsageData != nil && v.UsageData.Size > 0 {
+			usage.VolumesSize += v.UsageData.Size
+		}
+		usage.VolumeItems = append(usage.VolumeItems, volumeInfo(v))
+	}
+	for _, record := range df.BuildCache {
+		if record == nil {
+			continue
+		}
+		usage.BuildCacheCount++
+		usage.BuildCacheSize += record.Size
+		if record.InUse {
+			usage.BuildCacheActive++
+		} else if !record.Shared {
+			usage.BuildCacheReclaimableSize += record.Size
+		}
+	}
+	return usage, nil
+}
+
+// ListVolumes 列出全部 Docker 卷及其占用，按名称排序
+func (dc *DockerClient) ListVolumes(ctx context.IContext) ([]VolumeInfo, error) {
+	df, err := dc.cli.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	result := make([]VolumeInfo, 0, len(df.Volumes))
+	for _, v := range df.Volumes {
+		if v != nil {
+			result = append(result, volumeInfo(v))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// InspectVolume 获取指定卷，卷不存在时返回 NOT_FOUND
+// 参数:
+//   - ctx: 上下文对象
+//   - name: 卷名称
+func (dc *DockerClient) InspectVolume(ctx context.IContext, name string) (*VolumeInfo, error) {
+	v, err := dc.cli.VolumeInspect(ctx, name)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, utils.NewError(utils.CodeNotFound, "volume %s not found", name)
+		}
+		return nil, fmt.Errorf("failed to inspect volume %s: %w", name, err)
+	}
+	info := volumeInfo(&v)
+	// 卷检查结果不含占用，从磁盘占用统计中补充
+	if info.Size < 0 {
+		if volumes, err := dc.ListVolumes(ctx); err == nil {
+			for _, listed := range volumes {
+				if listed.Name == name {
+					info.Size, info.RefCount = listed.Size, listed.RefCount
+					break
+				}
+			}
+		}
+	}
+	return &info, nil
+}
+
+// RemoveVolume 删除卷，不强制删除：卷仍被容器使用时返回 CONFLICT
+// 参数:
+//   - ctx: 上下文对象
+//   - name: 卷名称
+func (dc *DockerClient) RemoveVolume(ctx context.IContext, name string) error {
+	if err := dc.cli.VolumeRemove(ctx, name, false); err != nil {
+		switch {
+		case cerrdefs.IsNotFound(err):
+			return utils.NewError(utils.CodeNotFound, "volume %s not found", name)
+		case cerrdefs.IsConflict(err):
+			return utils.NewError(utils.CodeConflict, "volume %s is in use: %w", name, err)
+		}
+		log.Error("Docker", log.Any("Error", err), log.Any("Volume", name), log.Any("Message", "删除卷失败"))
+		return fmt.Errorf("failed to remove volume %s: %w", name, err)
+	}
+	return nil
+}
+
+// ListNetworks 列出全部 Docker 网络，按名称排序
+func (dc *DockerClient) ListNetworks(ctx context.IContext) ([]NetworkInfo, error) {
+	networks, err := dc.cli.NetworkList(ctx, network.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list networks: %w", err)
+	}
+	result := make([]NetworkInfo, 0, len(networks))
+	for _, n := range networks {
+		result = append(result, dc.networkInfo(&n))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// CreateNetwork 创建带 OneDock 管理标签的网络，同名网络已存在时返回 CONFLICT
+// 参数:
+//   - ctx: 上下文对象
+//   - config: 网络配置
+func (dc *DockerClient) CreateNetwork(ctx context.IContext, config *NetworkConfig) (*NetworkInfo, error) {
+	labels := make(map[string]string, len(config.Labels)+1)
+	for k, v := range config.Labels {
+		labels[k] = v
+	}
+	labels[dc.containerPrefix+".managed"] = "true"
+
+	options := network.CreateOptions{
+		Driver:   config.Driver,
+		Internal: config.Internal,
+		Labels:   labels,
+	}
+	if options.Driver == "" {
+		options.Driver = "bridge"
+	}
+	if config.Subnet != "" || config.Gateway != "" {
+		options.IPAM = &network.IPAM{Config: []network.IPAMConfig{{Subnet: config.Subnet, Gateway: config.Gateway}}}
+	}
+
+	resp, err := dc.cli.NetworkCreate(ctx, config.Name, options)
+	if err != nil {
+		if cerrdefs.IsConflict(err) {
+			return nil, utils.NewError(utils.CodeConflict, "network %s already exists", config.Name)
+		}
+		if cerrdefs.IsInvalidArgument(err) {
+			return nil, utils.NewError(utils.CodeInvalidRequest, "invalid network config: %w", err)
+		}
+		log.Error("Docker", log.Any("Error", err), log.Any("Network", config.Name), log.Any("Message", "创建网络失败"))
+		return nil, fmt.Errorf("failed to create network %s: %w", config.Name, err)
+	}
+
+	inspect, err := dc.cli.NetworkInspect(ctx, resp.ID, network.InspectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect network %s: %w", config.Name, err)
+	}
+	info := dc.networkInfo(&inspect)
+	return &info, nil
+}
+
+// RemoveNetwork 删除网络，仍有容器加入时返回 CONFLICT
+// 参数:
+//   - ctx: 上下文对象
+//   - id: 网络ID或名称
+func (dc *DockerClient) RemoveNetwork(ctx context.IContext, id string) error {
+	if err := dc.cli.NetworkRemove(ctx, id); err != nil {
+		switch {
+		case cerrdefs.IsNotFound(err):
+			return utils.NewError(utils.CodeNotFound, "network %s not found", id)
+		case cerrdefs.IsConflict(err), cerrdefs.IsPermissionDenied(err):
+			return utils.NewError(utils.CodeConflict, "network %s cannot be removed: %w", id, err)
+		}
+		log.Error("Docker", log.Any("Error", err), log.Any("Network", id), log.Any("Message", "删除网络失败"))
+		return fmt.Errorf("failed to remove network %s: %w", id, err)
+	}
+	return nil
+}
+
+// networkInfo 转换 Docker 网络
+func (dc *DockerClient) networkInfo(n *network.Inspect) NetworkInfo {
+	info := NetworkInfo{
+		ID:        n.ID,
+		Name:      n.Name,
+		Driver:    n.Driver,
+		Scope:     n.Scope,
+		Internal:  n.Internal,
+		Subnets:   make([]string, 0, len(n.IPAM.Config)),
+		Gateways:  make([]string, 0, len(n.IPAM.Config)),
+		Labels:    n.Labels,
+		Managed:   n.Labels[dc.containerPrefix+".managed"] == "true",
+		CreatedAt: n.Created,
+	}
+	for _, config := range n.IPAM.Config {
+		if config.Subnet != "" {
+			info.Subnets = append(info.Subnets, config.Subnet)
+		}
+		if config.Gateway != "" {
+			info.Gateways = append(info.Gateways, config.Gateway)
+		}
+	}
+	return info
+}
+
+// volumeInfo 转换 Docker 卷，没有占用统计时 Size 和 RefCount 为 -1
+func volumeInfo(v *volume.Volume) VolumeInfo {
+	info := VolumeInfo{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Mountpoint: v.Mountpoint,
+		CreatedAt:  v.CreatedAt,
+		Labels:     v.Labels,
+		Size:       -1,
+		RefCount:   -1,
+	}
+	if v.UsageData != nil {
+		info.Size = v.UsageData.Size
+		info.RefCount = v.UsageData.RefCount
+	}
+	return info
+}
+
+// JobContainerName 任务容器名称，不符合副本命名格式，不会被当作服务副本
+func (dc *DockerClient) JobContainerName(jobID string) string {
+	return fmt.Sprintf("%s-job-%s", dc.containerPrefix, jobID)
+}
+
+// CreateJobContainer 创建一次性任务容器
+// 参数:
+//   - ctx: 上下文对象
+//   - job: 任务配置
+func (dc *DockerClient) CreateJobContainer(ctx context.IContext, job *Job) (string, error) {
+	if err := dc.PullImage(ctx, job.Image, job.Tag); err != nil {
+		return "", fmt.Errorf("failed to pull image: %w", err)
+	}
+
+	env, _, err := dc.buildEnv(ctx, job.ID, job.EnvFile, job.Environment)
+	if err != nil {
+		return "", err
+	}
+
+	labels := map[string]string{
+		dc.containerPrefix + ".managed": "true",
+		dc.LabelKey("job"):              job.ID,
+	}
+	for k, v := range job.Labels {
+		labels[dc.LabelKey(k)] = v
+	}
+
+	config := &container.Config{
+		Image:      fmt.Sprintf("%s:%s", job.Image, job.Tag),
+		Env:        env,
+		Labels:     labels,
+		WorkingDir: job.WorkingDir,
+	}
+	if len(job.Command) > 0 {
+		config.Cmd = job.Command
+	}
+	if len(job.Entrypoint) > 0 {
+		config.Entrypoint = job.Entrypoint
+	}
+
+	hostConfig := &container.HostConfig{
+		Binds:         buildBinds(job.Volumes),
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyDisabled},
+		LogConfig: container.LogConfig{
+			Type:   "json-file",
+			Config: map[string]string{"max-size": "10m", "max-file": "1"},
+		},
+	}
+	if job.MemoryLimit > 0 {
+		hostConfig.Resources.Memory = int64(job.MemoryLimit) * 1024 * 1024
+	}
+
+	containerName := dc.JobContainerName(job.ID)
+	resp, err := dc.cli.ContainerCreate(ctx, config, hostConfig, nil, nil, containerName)
+	if err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", containerName), log.Any("Message", "任务容器创建失败"))
+		return "", fmt.Errorf("failed to create job container: %w", err)
+	}
+	log.Info("Docker", log.Any("ContainerName", containerName), log.Any("ID", resp.ID[:12]), log.Any("Message", "任务容器创建成功"))
+	return resp.ID, nil
+}
+
+// WaitContainer 等待容器退出并返回退出码，ctx 取消或超时时返回错误
+func (dc *DockerClient) WaitContainer(ctx context.IContext, containerID string) (int64, error) {
+	statusCh, errCh := dc.cli.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
+	select {
+	case status := <-statusCh:
+		if status.Error != nil {
+			return status.StatusCode, fmt.Errorf("container %s exited with error: %s", containerID[:12], status.Error.Message)
+		}
+		return status.StatusCode, nil
+	case err := <-errCh:
+		return -1, fmt.Errorf("failed to wait container %s: %w", containerID[:12], err)
+	}
+}
+
+// ContainerLogs 读取容器的标准输出与标准错误（合并），tail 为保留的末尾行数，0 表示全部
+func (dc *DockerClient) ContainerLogs(ctx context.IContext, containerID string, tail int) (string, error) {
+	options := container.LogsOptions{ShowStdout: true, ShowStderr: true}
+	if tail > 0 {
+		options.Tail = strconv.Itoa(tail)
+	}
+	reader, err := dc.cli.ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logs of container %s: %w", containerID[:12], err)
+	}
+	defer reader.Close()
+
+	// 未分配 TTY 的容器日志为多路复用格式，需要拆分
+	var out strings.Builder
+	if _, err := stdcopy.StdCopy(&out, &out, reader); err != nil {
+		return "", fmt.Errorf("failed to read logs of container %s: %w", containerID[:12], err)
+	}
+	return out.String(), nil
+}
+
+// FollowLogs 持续读取容器日志直到容器停止或 ctx 取消，每行带 RFC3339Nano 时间戳前缀
+// 参数:
+//   - tail: 开始时输出的历史行数，"all" 表示全部
+//   - since: 只输出该时间之后的日志，零值表示不限制
+//   - stdout/stderr: 分配了 TTY 的容器两者合并输出到 stdout
+func (dc *DockerClient) FollowLogs(ctx context.IContext, containerID, tail string, since time.Time, stdout, stderr io.Writer) error {
+	inspect, err := dc.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container %s: %w", containerID[:12], err)
+	}
+
+	options := container.LogsOptions{ShowStdout: true, ShowStderr: true, Follow: true, Timestamps: true, Tail: tail}
+	if !since.IsZero() {
+		options.Since = since.Format(time.RFC3339Nano)
+	}
+	reader, err := dc.cli.ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		return fmt.Errorf("failed to follow logs of container %s: %w", containerID[:12], err)
+	}
+	defer reader.Close()
+
+	if inspect.Config != nil && inspect.Config.Tty {
+		_, err = io.Copy(stdout, reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, reader)
+	}
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to follow logs of container %s: %w", containerID[:12], err)
+	}
+	return nil
+}
+
+// StartExec 在容器中启动分配了 TTY 的交互式命令
+// 参数:
+//   - rows/cols: 初始终端大小，0 表示使用 Docker 默认值
+func (dc *DockerClient) StartExec(ctx context.IContext, containerID string, cmd []string, rows, cols uint) (*ExecSession, error) {
+	options := container.ExecOptions{
+		Tty:          true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+		Env:          []string{"TERM=xterm-256color"},
+	}
+	if rows > 0 && cols > 0 {
+		options.ConsoleSize = &[2]uint{rows, cols}
+	}
+	exec, err := dc.cli.ContainerExecCreate(ctx, containerID, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec in container %s: %w", containerID[:12], err)
+	}
+	resp, err := dc.cli.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{Tty: true, ConsoleSize: options.ConsoleSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach exec in container %s: %w", containerID[:12], err)
+	}
+	return &ExecSession{ID: exec.ID, Conn: resp.Conn, Reader: resp.Reader, close: resp.Close}, nil
+}
+
+// ResizeExec 调整交互式命令的终端大小
+func (dc *DockerClient) ResizeExec(ctx context.IContext, execID string, rows, cols uint) error {
+	if err := dc.cli.ContainerExecResize(ctx, execID, container.ResizeOptions{Height: rows, Width: cols}); err != nil {
+		return fmt.Errorf("failed to resize exec: %w", err)
+	}
+	return nil
+}
+
+// ExecExitCode 查询已结束命令的退出码
+func (dc *DockerClient) ExecExitCode(ctx context.IContext, execID string) (int, error) {
+	inspect, err := dc.cli.ContainerExecInspect(ctx, execID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	return inspect.ExitCode, nil
+}
+
+// defaultInitTimeout 初始化容器默认超时
+const defaultInitTimeout = 300 * time.Second
+
+// runInitContainers 按顺序运行服务的初始化容器，全部以退出码 0 结束才返回 nil
+// 参数:
+//   - ctx: 上下文对象
+//   - service: 服务配置
+//   - replicaIndex: 副本编号，用于区分初始化容器名称
+func (dc *DockerClient) runInitContainers(ctx context.IContext, service *Service, replicaIndex int) error {
+	for _, init := range service.InitContainers {
+		env := make(map[string]string, len(service.Environment)+len(init.Environment))
+		for k, v := range service.Environment {
+			env[k] = v
+		}
+		for k, v := range init.Environment {
+			env[k] = v
+		}
+
+		job := &Job{
+			ID:          fmt.Sprintf("%s-%d-init-%s-%s", service.Name, replicaIndex, init.Name, strings.ReplaceAll(uuid.NewString(), "-", "")[:6]),
+			Image:       init.Image,
+			Tag:         init.Tag,
+			Entrypoint:  init.Entrypoint,
+			Command:     init.Command,
+			Environment: env,
+			EnvFile:     service.EnvFile,
+			Volumes:     service.Volumes,
+			MemoryLimit: service.MemoryLimit,
+			Labels:      map[string]string{"init": service.Name},
+		}
+		timeout := defaultInitTimeout
+		if init.Timeout > 0 {
+			timeout = time.Duration(init.Timeout) * time.Second
+		}
+
+		log.Info("Docker", log.Any("ServiceName", service.Name), log.Any("ReplicaIndex", replicaIndex), log.Any("Init", init.Name), log.Any("Message", "运行初始化容器"))
+		if err := dc.runInitContainer(ctx, job, timeout); err != nil {
+			return fmt.Errorf("init container %s failed: %w", init.Name, err)
+		}
+	}
+	return nil
+}
+
+// runInitContainer 运行单个初始化容器直到结束，结束后删除容器
+func (dc *DockerClient) runInitContainer(ctx context.IContext, job *Job, timeout time.Duration) error {
+	runCtx, cancel := ctx.WithTimeout(timeout)
+	defer cancel()
+
+	containerID, err := dc.CreateJobContainer(runCtx, job)
+	if err != nil {
+		return err
+	}
+	// 日志读取与容器删除不受超时影响
+	defer dc.RemoveContainer(context.Background(), containerID)
+
+	if err := dc.StartContainer(runCtx, containerID); err != nil {
+		return err
+	}
+	exitCode, err := dc.WaitContainer(runCtx, containerID)
+	if err != nil {
+		if runCtx.Err() != nil {
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+		return err
+	}
+	if exitCode != 0 {
+		logs, _ := dc.ContainerLogs(context.Background(), containerID, 20)
+		return fmt.Errorf("exited with code %d: %s", exitCode, strings.TrimSpace(logs))
+	}
+	return nil
+}
+
+// buildEnv 合并环境变量文件与直接指定的环境变量，并解析密钥引用等
+// 直接指定的 Environment 会覆盖 EnvFile 中的同名变量；同时返回引用密钥的变量及其引用（变量名 → secret://<名称>）
+func (dc *DockerClient) buildEnv(ctx context.IContext, name, envFile string, environment map[string]string) ([]string, map[string]string, error) {
+	allEnvVars := make(map[string]string)
+
+	// 1. 先从EnvFile读取环境变量
+	if envFile != "" {
+		envFileVars, err := dc.readEnvFile(envFile)
+		if err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("EnvFile", envFile), log.Any("Message", "读取环境变量文件失败"))
+			return nil, nil, fmt.Errorf("failed to read env file: %w", err)
+		}
+		for k, v := range envFileVars {
+			allEnvVars[k] = v
+		}
+		log.Info("Docker", log.Any("EnvFile", envFile), log.Any("Count", len(envFileVars)), log.Any("Message", "成功读取环境变量文件"))
+	}
+
+	// 2. 直接指定的Environment会覆盖EnvFile中的同名变量
+	for k, v := range environment {
+		allEnvVars[k] = v
+	}
+
+	secretEnv := make(map[string]string)
+	for k, v := range allEnvVars {
+		if strings.HasPrefix(v, secretRefPrefix) {
+			secretEnv[k] = v
+		}
+	}
+
+	// 3. 解析密钥引用等，解析结果只注入容器，不进入标签和配置哈希
+	if dc.envResolver != nil {
+		resolved, err := dc.envResolver(ctx, allEnvVars)
+		if err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("Name", name), log.Any("Message", "解析环境变量失败"))
+			return nil, nil, fmt.Errorf("failed to resolve environment: %w", err)
+		}
+		allEnvVars = resolved
+	}
+
+	// 4. 构建最终的环境变量列表
+	env := make([]string, 0, len(allEnvVars))
+	for k, v := range allEnvVars {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env, secretEnv, nil
+}
+
+// buildBinds 构建卷挂载
+func buildBinds(volumes []VolumeMount) []string {
+	binds := make([]string, 0, len(volumes))
+	for _, volume := range volumes {
+		bind := fmt.Sprintf("%s:%s", volume.Source, volume.Destination)
+		if volume.ReadOnly {
+			bind += ":ro"
+		}
+		binds = append(binds, bind)
+	}
+	return binds
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/aichy126/igo"
@@ -21,12 +22,17 @@ var devContainers = &Service{
 	InternalPort: 80,
 }
 
+var initOnce sync.Once
+
+// Init 加载配置并初始化应用，多个测试都会调用，只执行一次，避免重复定义 config 参数
 func Init() {
-	confPath := flag.String("config", "../../config.toml", "configure file")
-	flag.Parse()
+	initOnce.Do(func() {
+		confPath := flag.String("config", "../../config.toml", "configure file")
+		flag.Parse()
 
-	igo.App = igo.NewApp(*confPath)
-	ctx = context.Background()
+		igo.App = igo.NewApp(*confPath)
+		ctx = context.Background()
+	})
 }
 
 // TestDockerClient 测试Docker客户端基础功能
@@ -86,7 +92,7 @@ func TestCreateContainer(t *testing.T) {
 
 	dockerID, err := client.CreateContainer(ctx, devContainers, 0)
 	if err != nil {
-		log.Error("Docker", log.Any("Error", fmt.Sprintf("failed to create container: %v", err)))
+		t.Fatalf("创建容器失败: %v", err)
 	}
 	fmt.Println("dockerID", dockerID)
 	err = client.StartContainer(ctx, dockerID)
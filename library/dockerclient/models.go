@@ -1,10 +1,18 @@
 package dockerclient
 
-import "github.com/docker/docker/client"
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/docker/docker/client"
+)
 
 // Service 服务配置结构体，用于Docker操作
 type Service struct {
-	Name         string            // 服务名称
+	Name         string            // 服务名称（非默认命名空间时带命名空间前缀）
+	Namespace    string            // 命名空间
 	Image        string            // Docker镜像名称
 	Tag          string            // 镜像标签
 	PublicPort   int               // 公共端口（用户访问端口）
@@ -16,7 +24,15 @@ type Service struct {
 	Entrypoint   []string          // 入口
 	Command      []string          // 启动命令
 	WorkingDir   string            // 工作目录
+	MemoryLimit  int               // 单个副本内存上限（MB，0 表示不限制）
 	Replicas     int               // 副本数量
+	BindAddress  string            // 代理监听地址（为空则使用全局配置）
+	ProxyWorkers int               // 代理监听器数量（0 则使用全局配置）
+	RequestRules []RequestRule     // 请求过滤规则
+	Mirror       *MirrorConfig     // 流量镜像配置
+
+	InitContainers []InitContainer // 初始化容器，每个副本的主容器创建前按顺序运行
+	Sidecars       []Sidecar       // 边车容器，随每个副本创建、启动、停止和删除
 }
 
 // VolumeMount 卷挂载结构体
@@ -26,6 +42,49 @@ type VolumeMount struct {
 	ReadOnly    bool   // 是否只读挂载
 }
 
+// RequestRule 请求过滤规则，由端口代理执行
+// 规则中已设置的条件全部命中时拦截请求，未设置的条件视为匹配
+type RequestRule struct {
+	Name      string            `json:"name" yaml:"name"`                                 // 规则名称
+	Path      string            `json:"path,omitempty" yaml:"path,omitempty"`             // 路径正则
+	Methods   []string          `json:"methods,omitempty" yaml:"methods,omitempty"`       // HTTP 方法列表
+	UserAgent string            `json:"user_agent,omitempty" yaml:"user_agent,omitempty"` // User-Agent 正则
+	Headers   map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`       // 请求头名称 -> 值正则
+}
+
+// MirrorConfig 流量镜像配置
+// 按百分比将请求异步复制到另一个服务，镜像请求的响应会被丢弃
+type MirrorConfig struct {
+	Service string `json:"service" yaml:"service"` // 镜像目标服务名称
+	Percent int    `json:"percent" yaml:"percent"` // 镜像比例 1-100
+}
+
+// InitContainer 初始化容器
+// 每个副本的主容器创建前按顺序运行到结束，退出码非 0 或超时时副本创建失败
+// 与主容器共享卷挂载和环境变量（Environment 中的同名变量覆盖服务的环境变量）
+type InitContainer struct {
+	Name        string            `json:"name" yaml:"name"`                                   // 名称
+	Image       string            `json:"image" yaml:"image"`                                 // 镜像名称
+	Tag         string            `json:"tag" yaml:"tag"`                                     // 镜像标签
+	Entrypoint  []string          `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`   // 入口点
+	Command     []string          `json:"command,omitempty" yaml:"command,omitempty"`         // 启动命令
+	Environment map[string]string `json:"environment,omitempty" yaml:"environment,omitempty"` // 额外的环境变量
+	Timeout     int               `json:"timeout,omitempty" yaml:"timeout,omitempty"`         // 超时时间（秒），0 表示默认 300 秒
+}
+
+// Sidecar 边车容器
+// 与副本的主容器共享网络命名空间（可通过 localhost 访问主容器端口）和卷挂载，随主容器启动、停止和删除
+type Sidecar struct {
+	Name        string            `json:"name" yaml:"name"`                                     // 名称
+	Image       string            `json:"image" yaml:"image"`                                   // 镜像名称
+	Tag         string            `json:"tag" yaml:"tag"`                                       // 镜像标签
+	Entrypoint  []string          `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`     // 入口点
+	Command     []string          `json:"command,omitempty" yaml:"command,omitempty"`           // 启动命令
+	Environment map[string]string `json:"environment,omitempty" yaml:"environment,omitempty"`   // 环境变量
+	Volumes     []VolumeMount     `json:"volumes,omitempty" yaml:"volumes,omitempty"`           // 额外的卷挂载
+	MemoryLimit int               `json:"memory_limit,omitempty" yaml:"memory_limit,omitempty"` // 内存上限（MB），0 表示不限制
+}
+
 // ContainerNameInfo 容器名称解析结果
 type ContainerNameInfo struct {
 	ServiceName   string // 服务名称
@@ -39,8 +98,29 @@ type DockerClient struct {
 	cli               client.APIClient // Docker API客户端
 	containerPrefix   string           // 容器名称前缀
 	internalPortStart int              // 内部端口起始
+	hostIP            string           // 容器端口绑定的主机地址（支持 IPv6，如 ::1）
+	createMutex       sync.Mutex       // 串行化端口分配与容器创建，避免并发更新分配到相同端口
+	verifyTimeout     time.Duration    // 更新后验证新容器的观察时间，为 0 表示不验证
+	envResolver       EnvResolver      // 创建容器前解析环境变量（如密钥引用），为 nil 时原样使用
+}
+
+// secretRefPrefix 环境变量中引用密钥的前缀，与 models.SecretRefPrefix 相同
+const secretRefPrefix = "secret://"
+
+// EnvResolver 环境变量解析函数，返回实际注入容器的环境变量，不应修改传入的映射
+type EnvResolver func(ctx context.IContext, env map[string]string) (map[string]string, error)
+
+// Progress 长时间操作（拉取镜像、创建副本）的进度
+type Progress struct {
+	Step    string // 当前阶段：pulling / creating
+	Current int    // 已完成的副本数
+	Total   int    // 副本总数，不适用时为 0
+	Message string // 进度描述
 }
 
+// ProgressFunc 进度回调，通过 WithProgress 设置到上下文中
+type ProgressFunc func(progress Progress)
+
 // ContainerInfo 容器信息结构体
 type ContainerInfo struct {
 	ID        string            // 容器ID
@@ -51,6 +131,8 @@ type ContainerInfo struct {
 	Labels    map[string]string // 标签
 	State     string            // 运行状态
 	CreatedAt string            // 创建时间
+	Mounts    []MountInfo       // 挂载（仅列表结果）
+	Networks  []NetworkEndpoint // 加入的网络（仅列表结果），按名称排序
 }
 
 // PortMapping 端口映射信息结构体
@@ -59,3 +141,175 @@ type PortMapping struct {
 	ContainerPort string // 容器端口
 	Protocol      string // 协议类型
 }
+
+// ContainerDetail 容器的检查详情（docker inspect）
+type ContainerDetail struct {
+	ContainerInfo
+	ImageID       string            // 镜像ID
+	Entrypoint    []string          // 入口
+	Command       []string          // 启动命令
+	WorkingDir    string            // 工作目录
+	Env           []string          // 环境变量，KEY=VALUE
+	Mounts        []MountInfo       // 挂载
+	Networks      []NetworkEndpoint // 加入的网络
+	NetworkMode   string            // 网络模式
+	RestartPolicy string            // 重启策略
+	RestartCount  int               // 守护进程自动重启的次数
+	MemoryLimit   int64             // 内存上限（字节，0 表示不限制）
+	Running       bool              // 是否运行中
+	OOMKilled     bool              // 是否因内存不足被杀死
+	Pid           int               // 主进程 PID
+	ExitCode      int               // 最近一次退出码
+	Error         string            // 最近一次启动错误
+	StartedAt     time.Time         // 最近一次启动时间
+	FinishedAt    time.Time         // 最近一次退出时间
+	Health        string            // 健康检查状态，未配置 HEALTHCHECK 时为空
+	FailingStreak int               // 连续健康检查失败次数
+	HealthLog     []string          // 最近的健康检查输出
+}
+
+// MountInfo 容器挂载
+type MountInfo struct {
+	Type        string // bind / volume / tmpfs
+	Name        string // 卷名称（仅 volume）
+	Source      string // 主机路径
+	Destination string // 容器内路径
+	Mode        string // 挂载选项
+	ReadWrite   bool   // 是否可写
+}
+
+// VolumeInfo Docker 卷
+type VolumeInfo struct {
+	Name       string            // 卷名称
+	Driver     string            // 驱动
+	Mountpoint string            // 主机上的挂载点
+	CreatedAt  string            // 创建时间
+	Labels     map[string]string // 标签
+	Size       int64             // 占用（字节），无法统计时为 -1
+	RefCount   int64             // 引用该卷的容器数，无法统计时为 -1
+}
+
+// NetworkInfo Docker 网络
+type NetworkInfo struct {
+	ID        string            // 网络ID
+	Name      string            // 网络名称
+	Driver    string            // 驱动
+	Scope     string            // 作用范围
+	Internal  bool              // 是否为内部网络（不能访问外部）
+	Subnets   []string          // 子网
+	Gateways  []string          // 网关
+	Labels    map[string]string // 标签
+	Managed   bool              // 是否由 OneDock 创建
+	CreatedAt time.Time         // 创建时间
+}
+
+// NetworkConfig 创建网络的配置
+type NetworkConfig struct {
+	Name     string            // 网络名称
+	Driver   string            // 驱动，为空时为 bridge
+	Internal bool              // 是否为内部网络
+	Subnet   string            // 子网，为空时由 Docker 分配
+	Gateway  string            // 网关，为空时由 Docker 分配
+	Labels   map[string]string // 标签
+}
+
+// NetworkEndpoint 容器在某个网络中的地址
+type NetworkEndpoint struct {
+	Network    string // 网络名称
+	IPAddress  string // IPv4 地址
+	Gateway    string // 网关
+	MacAddress string // MAC 地址
+}
+
+// ContainerStats 容器资源使用快照
+type ContainerStats struct {
+	CPUPercent    float64 // CPU 使用率（100 表示占满一个核）
+	MemoryUsage   uint64  // 内存使用量（字节，不含页缓存）
+	MemoryLimit   uint64  // 内存上限（字节，未设置限制时为主机内存）
+	MemoryPercent float64 // 内存使用率
+	NetworkRx     uint64  // 全部网卡累计接收（字节）
+	NetworkTx     uint64  // 全部网卡累计发送（字节）
+	BlockRead     uint64  // 块设备累计读取（字节）
+	BlockWrite    uint64  // 块设备累计写入（字节）
+	PIDs          uint64  // 进程（线程）数
+}
+
+// DaemonInfo Docker 守护进程信息
+type DaemonInfo struct {
+	Version       string // Docker 版本
+	APIVersion    string // 守护进程支持的 API 版本
+	ClientVersion string // 协商后客户端使用的 API 版本
+	OS            string // 操作系统
+	Arch          string // CPU 架构
+	KernelVersion string // 内核版本
+	RootDir       string // Docker 数据目录
+}
+
+// DiskUsage Docker 磁盘占用汇总（docker system df）
+type DiskUsage struct {
+	Images         int   // 镜像数量
+	ImagesSize     int64 // 镜像层占用（字节）
+	Containers     int   // 容器数量
+	ContainersSize int64 // 容器可写层占用（字节）
+	Volumes        int   // 卷数量
+	VolumesSize    int64 // 卷占用（字节，无法统计的卷不计入）
+	BuildCacheSize int64 // 构建缓存占用（字节）
+
+	LayersSize                int64            // 全部镜像层的去重占用（字节）
+	ImageItems                []ImageUsage     // 每个镜像的占用
+	ContainerItems            []ContainerUsage // 每个容器的占用
+	VolumeItems               []VolumeInfo     // 每个卷的占用
+	BuildCacheCount           int              // 构建缓存记录数
+	BuildCacheActive          int              // 使用中的构建缓存记录数
+	BuildCacheReclaimableSize int64            // 未使用的构建缓存占用（字节）
+}
+
+// ImageUsage 单个镜像的磁盘占用
+type ImageUsage struct {
+	ID         string   // 镜像ID
+	Tags       []string // 镜像标签
+	Size       int64    // 镜像总大小（字节）
+	SharedSize int64    // 与其他镜像共享的层大小（字节），无法统计时为 -1
+	Containers int64    // 使用该镜像的容器数，无法统计时为 -1
+	Created    int64    // 创建时间（Unix 秒）
+}
+
+// ContainerUsage 单个容器的磁盘占用
+type ContainerUsage struct {
+	ID         string   // 容器ID
+	Name       string   // 容器名称
+	Image      string   // 镜像名称
+	ImageID    string   // 镜像ID
+	State      string   // 运行状态
+	SizeRw     int64    // 可写层大小（字节）
+	SizeRootFs int64    // 包括镜像在内的总大小（字节）
+	Volumes    []string // 挂载的命名卷
+}
+
+// ExecSession 交互式 exec 会话（分配 TTY），Conn 写入标准输入，Reader 读取输出
+type ExecSession struct {
+	ID     string
+	Conn   io.Writer
+	Reader io.Reader
+	close  func()
+}
+
+// Close 结束会话，关闭与 Docker 的连接
+func (s *ExecSession) Close() {
+	s.close()
+}
+
+// Job 一次性任务容器配置，运行到结束，不映射端口也不自动重启
+type Job struct {
+	ID          string            // 任务ID，容器名称为 {prefix}-job-{ID}
+	Image       string            // Docker镜像名称
+	Tag         string            // 镜像标签
+	Entrypoint  []string          // 容器入口点
+	Command     []string          // 容器启动命令
+	WorkingDir  string            // 工作目录
+	Environment map[string]string // 环境变量
+	EnvFile     string            // 环境变量文件路径
+	Volumes     []VolumeMount     // 卷挂载
+	MemoryLimit int               // 内存上限（MB），0 表示不限制
+	Labels      map[string]string // 额外标签
+}
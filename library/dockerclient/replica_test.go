@@ -0,0 +1,65 @@
+package dockerclient
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestReplicaConfig 测试扩容和滚动更新生成副本配置时保留全部字段，包括初始化容器和边车容器
+func TestReplicaConfig(t *testing.T) {
+	config := &Service{
+		Name:         "staging.web",
+		Namespace:    "staging",
+		Image:        "nginx",
+		Tag:          "alpine",
+		PublicPort:   9300,
+		InternalPort: 80,
+		DockerPort:   30001,
+		Environment:  map[string]string{"MODE": "prod"},
+		EnvFile:      "/etc/onedock/web.env",
+		Volumes:      []VolumeMount{{Source: "/data", Destination: "/usr/share/nginx/html", ReadOnly: true}},
+		Entrypoint:   []string{"/docker-entrypoint.sh"},
+		Command:      []string{"nginx", "-g", "daemon off;"},
+		WorkingDir:   "/srv",
+		MemoryLimit:  256,
+		Replicas:     3,
+		BindAddress:  "127.0.0.1",
+		ProxyWorkers: 2,
+		RequestRules: []RequestRule{{Name: "block-admin", Path: "^/admin"}},
+		Mirror:       &MirrorConfig{Service: "web-shadow", Percent: 10},
+
+		InitContainers: []InitContainer{{Name: "migrate", Image: "migrate", Tag: "v1"}},
+		Sidecars:       []Sidecar{{Name: "log-agent", Image: "fluent-bit", Tag: "2.2"}},
+	}
+	// 新增字段时需要在上面补充，确保复制时不会遗漏
+	fields := reflect.ValueOf(config).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		if fields.Field(i).IsZero() {
+			t.Fatalf("测试配置缺少字段 %s", fields.Type().Field(i).Name)
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		dockerPort int
+	}{
+		{"扩容", 30002},
+		{"滚动更新", 0},
+	} {
+		replica := replicaConfig(config, tc.dockerPort)
+		if replica.Replicas != 1 || replica.DockerPort != tc.dockerPort {
+			t.Errorf("%s: Replicas = %d, DockerPort = %d, 期望 1, %d", tc.name, replica.Replicas, replica.DockerPort, tc.dockerPort)
+		}
+		if !reflect.DeepEqual(replica.InitContainers, config.InitContainers) || !reflect.DeepEqual(replica.Sidecars, config.Sidecars) {
+			t.Errorf("%s: 初始化容器或边车容器丢失: %+v, %+v", tc.name, replica.InitContainers, replica.Sidecars)
+		}
+		expected := *config
+		expected.Replicas, expected.DockerPort = 1, tc.dockerPort
+		if !reflect.DeepEqual(*replica, expected) {
+			t.Errorf("%s: 副本配置 = %+v, 期望 %+v", tc.name, *replica, expected)
+		}
+	}
+	if config.Replicas != 3 || config.DockerPort != 30001 {
+		t.Error("生成副本配置时不应修改服务配置")
+	}
+}
@@ -2,6 +2,9 @@ package dockerclient
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
@@ -11,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
 	"github.com/docker/docker/api/types/container"
 )
 
@@ -20,6 +24,12 @@ func (dc *DockerClient) generateContainerName(serviceName string, publicPort, co
 	return fmt.Sprintf("%s-%s-p%d-c%d-%d", dc.containerPrefix, serviceName, publicPort, containerPort, replicaIndex)
 }
 
+// LabelKey 返回带前缀的容器标签名
+// 例如 LabelKey("public_port") 返回 "onedock.public_port"
+func (dc *DockerClient) LabelKey(name string) string {
+	return dc.containerPrefix + "." + name
+}
+
 // ParseContainerName 解析容器名称，提取服务信息
 // 从标准格式的容器名称中解析出服务名、端口和副本信息
 func (dc *DockerClient) ParseContainerName(containerName string) (*ContainerNameInfo, error) {
@@ -117,16 +127,20 @@ func (dc *DockerClient) ExtractServiceFromContainer(container ContainerInfo) (*S
 		return nil, fmt.Errorf("invalid public port in labels: %s", publicPortStr)
 	}
 
-	// 从端口映射中提取内部端口
-	internalPort := 80 // 默认值
-	if len(container.Ports) > 0 {
-		if port, err := strconv.Atoi(container.Ports[0].ContainerPort); err == nil {
-			internalPort = port
+	// 优先从标签读取内部端口（已停止的容器没有端口映射），其次从端口映射中提取
+	internalPort := utils.StringToInt(labels[dc.LabelKey("internal_port")])
+	if internalPort <= 0 {
+		internalPort = 80 // 默认值
+		if len(container.Ports) > 0 {
+			if port, err := strconv.Atoi(container.Ports[0].ContainerPort); err == nil {
+				internalPort = port
+			}
 		}
 	}
 
 	return &Service{
 		Name:         serviceName,
+		Namespace:    labels[dc.LabelKey("namespace")],
 		Image:        image,
 		Tag:          tag,
 		PublicPort:   publicPort,
@@ -137,10 +151,57 @@ func (dc *DockerClient) ExtractServiceFromContainer(container ContainerInfo) (*S
 		Entrypoint:   []string{},              // 无法从容器中完整恢复，使用空值
 		Command:      []string{},              // 无法从容器中完整恢复，使用空值
 		WorkingDir:   "",                      // 无法从容器中完整恢复，使用空值
-		Replicas:     1,                       // 单个容器的副本数为1
+		MemoryLimit:  utils.StringToInt(labels[dc.LabelKey("memory_limit")]),
+		Replicas:     1, // 单个容器的副本数为1
+		BindAddress:  labels[dc.LabelKey("bind_address")],
+		ProxyWorkers: utils.StringToInt(labels[dc.LabelKey("proxy_workers")]),
+		RequestRules: dc.ParseRequestRules(labels),
+		Mirror:       dc.ParseMirrorConfig(labels),
 	}, nil
 }
 
+// ParseMirrorConfig 从容器标签中解析流量镜像配置
+func (dc *DockerClient) ParseMirrorConfig(labels map[string]string) *MirrorConfig {
+	raw := labels[dc.LabelKey("mirror")]
+	if raw == "" {
+		return nil
+	}
+	mirror := new(MirrorConfig)
+	if err := utils.DeJson(raw, mirror); err != nil {
+		log.Warn("Docker", log.Any("Error", err), log.Any("Message", "解析流量镜像标签失败"))
+		return nil
+	}
+	return mirror
+}
+
+// ParseSecretEnv 从容器标签中解析创建容器时引用密钥的变量（变量名 → secret://<名称>），没有记录时返回 nil
+func (dc *DockerClient) ParseSecretEnv(labels map[string]string) map[string]string {
+	raw := labels[dc.LabelKey("secret_env")]
+	if raw == "" {
+		return nil
+	}
+	var refs map[string]string
+	if err := utils.DeJson(raw, &refs); err != nil {
+		log.Warn("Docker", log.Any("Error", err), log.Any("Message", "解析密钥变量标签失败"))
+		return nil
+	}
+	return refs
+}
+
+// ParseRequestRules 从容器标签中解析请求过滤规则
+func (dc *DockerClient) ParseRequestRules(labels map[string]string) []RequestRule {
+	raw := labels[dc.LabelKey("request_rules")]
+	if raw == "" {
+		return nil
+	}
+	var rules []RequestRule
+	if err := utils.DeJson(raw, &rules); err != nil {
+		log.Warn("Docker", log.Any("Error", err), log.Any("Message", "解析请求过滤规则标签失败"))
+		return nil
+	}
+	return rules
+}
+
 // findAvailablePortForService 查找服务的第一个可用端口号
 // 从起始端口开始递增查找，跳过已被占用的端口
 func (dc *DockerClient) findAvailablePortForService(containers []ContainerInfo, serviceName string) int {
@@ -163,6 +224,11 @@ func (dc *DockerClient) findAvailablePortForService(containers []ContainerInfo,
 	}
 }
 
+// IsPortOccupied 检测主机端口是否被占用
+func (dc *DockerClient) IsPortOccupied(port int) bool {
+	return dc.isPortOccupied(port)
+}
+
 // isPortOccupied 检测指定端口是否被占用
 // 通过尝试绑定端口来检测端口是否可用
 func (dc *DockerClient) isPortOccupied(port int) bool {
@@ -180,6 +246,11 @@ func (dc *DockerClient) isPortOccupied(port int) bool {
 	return false
 }
 
+// ReadEnvFile 按创建容器时的规则读取环境变量文件，用于查看服务实际生效的环境变量
+func (dc *DockerClient) ReadEnvFile(envFilePath string) (map[string]string, error) {
+	return dc.readEnvFile(envFilePath)
+}
+
 // readEnvFile 读取环境变量文件并返回键值对
 // 简单实现：读取KEY=VALUE格式，跳过注释和空行
 func (dc *DockerClient) readEnvFile(envFilePath string) (map[string]string, error) {
@@ -271,6 +342,44 @@ func (dc *DockerClient) CompareServiceConfig(oldService, newService *Service) bo
 		return true
 	}
 
+	// 检查内存上限
+	if oldService.MemoryLimit != newService.MemoryLimit {
+		return true
+	}
+
+	// 检查代理监听配置
+	if oldService.BindAddress != newService.BindAddress || oldService.ProxyWorkers != newService.ProxyWorkers {
+		return true
+	}
+
+	// 检查请求过滤规则
+	oldRules, _ := utils.EnJson(oldService.RequestRules)
+	newRules, _ := utils.EnJson(newService.RequestRules)
+	if len(oldService.RequestRules)+len(newService.RequestRules) > 0 && oldRules != newRules {
+		return true
+	}
+
+	// 检查初始化容器
+	oldInits, _ := utils.EnJson(oldService.InitContainers)
+	newInits, _ := utils.EnJson(newService.InitContainers)
+	if len(oldService.InitContainers)+len(newService.InitContainers) > 0 && oldInits != newInits {
+		return true
+	}
+
+	// 检查边车容器
+	oldSidecars, _ := utils.EnJson(oldService.Sidecars)
+	newSidecars, _ := utils.EnJson(newService.Sidecars)
+	if len(oldService.Sidecars)+len(newService.Sidecars) > 0 && oldSidecars != newSidecars {
+		return true
+	}
+
+	// 检查流量镜像配置
+	oldMirror, _ := utils.EnJson(oldService.Mirror)
+	newMirror, _ := utils.EnJson(newService.Mirror)
+	if oldMirror != newMirror {
+		return true
+	}
+
 	return false // 没有差异
 }
 
@@ -325,3 +434,124 @@ func (dc *DockerClient) compareCommands(old, new []string) bool {
 
 	return true
 }
+
+// ConfigHash 计算服务容器配置的哈希
+// 包含环境变量、卷挂载等无法从标签恢复的配置，写入容器标签后可用于判断容器是否与期望配置一致
+func (dc *DockerClient) ConfigHash(service *Service) string {
+	config := struct {
+		Image        string
+		Tag          string
+		InternalPort int
+		Environment  map[string]string
+		EnvFile      string
+		Volumes      []VolumeMount
+		Entrypoint   []string
+		Command      []string
+		WorkingDir   string
+		MemoryLimit  int `json:",omitempty"`
+		BindAddress  string
+		ProxyWorkers int
+		RequestRules []RequestRule
+		Mirror       *MirrorConfig
+		// 新增字段为空时不参与哈希，避免升级后已有容器被判定为配置不一致
+		InitContainers []InitContainer `json:",omitempty"`
+		Sidecars       []Sidecar       `json:",omitempty"`
+	}{
+		Image:        service.Image,
+		Tag:          service.Tag,
+		InternalPort: service.InternalPort,
+		Environment:  service.Environment,
+		EnvFile:      service.EnvFile,
+		Volumes:      service.Volumes,
+		Entrypoint:   service.Entrypoint,
+		Command:      service.Command,
+		WorkingDir:   service.WorkingDir,
+		MemoryLimit:  service.MemoryLimit,
+		BindAddress:  service.BindAddress,
+		ProxyWorkers: service.ProxyWorkers,
+		RequestRules: service.RequestRules,
+		Mirror:       service.Mirror,
+
+		InitContainers: service.InitContainers,
+		Sidecars:       service.Sidecars,
+	}
+	// 空集合与 nil 视为相同
+	if len(config.Environment) == 0 {
+		config.Environment = nil
+	}
+	if len(config.Volumes) == 0 {
+		config.Volumes = nil
+	}
+	if len(config.Entrypoint) == 0 {
+		config.Entrypoint = nil
+	}
+	if len(config.Command) == 0 {
+		config.Command = nil
+	}
+	if len(config.RequestRules) == 0 {
+		config.RequestRules = nil
+	}
+
+	raw, _ := json.Marshal(config)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:8])
+}
+
+// MatchesConfig 判断容器是否按指定配置创建
+// 优先比较 config_hash 标签；旧版本创建的容器没有该标签时，退化为比较可从标签恢复的配置
+func (dc *DockerClient) MatchesConfig(container ContainerInfo, service *Service) bool {
+	if hash := container.Labels[dc.LabelKey("config_hash")]; hash != "" {
+		return hash == dc.ConfigHash(service)
+	}
+	current, err := dc.ExtractServiceFromContainer(container)
+	if err != nil {
+		return false
+	}
+	return current.Image == service.Image && current.Tag == service.Tag && current.InternalPort == service.InternalPort
+}
+
+// calculateStats 按 docker stats 的算法计算 CPU、内存使用率
+func calculateStats(stats *container.StatsResponse) *ContainerStats {
+	result := &ContainerStats{}
+
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		result.CPUPercent = cpuDelta / systemDelta * onlineCPUs * 100
+	}
+
+	// 与 docker stats 一致，内存使用量扣除页缓存（cgroup v1 为 total_inactive_file，v2 为 inactive_file）
+	usage := stats.MemoryStats.Usage
+	cache := stats.MemoryStats.Stats["total_inactive_file"]
+	if v, ok := stats.MemoryStats.Stats["inactive_file"]; ok && cache == 0 {
+		cache = v
+	}
+	if cache < usage {
+		usage -= cache
+	}
+	result.MemoryUsage = usage
+	result.MemoryLimit = stats.MemoryStats.Limit
+	if result.MemoryLimit > 0 {
+		result.MemoryPercent = float64(usage) / float64(result.MemoryLimit) * 100
+	}
+
+	for _, network := range stats.Networks {
+		result.NetworkRx += network.RxBytes
+		result.NetworkTx += network.TxBytes
+	}
+	// cgroup v1 的操作名为 Read / Write，v2 为 read / write
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			result.BlockRead += entry.Value
+		case "write":
+			result.BlockWrite += entry.Value
+		}
+	}
+	result.PIDs = stats.PidsStats.Current
+	return result
+}
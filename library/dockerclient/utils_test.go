@@ -0,0 +1,73 @@
+package dockerclient
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// TestConfigHash 测试配置哈希：端口、副本数不影响哈希，环境变量变化影响哈希
+func TestConfigHash(t *testing.T) {
+	client := &DockerClient{containerPrefix: "onedock"}
+
+	base := &Service{Name: "web", Image: "nginx", Tag: "alpine", InternalPort: 80, Environment: map[string]string{"ENV": "prod"}}
+	scaled := *base
+	scaled.PublicPort, scaled.DockerPort, scaled.Replicas = 9200, 30001, 3
+	if client.ConfigHash(base) != client.ConfigHash(&scaled) {
+		t.Error("端口和副本数不应影响配置哈希")
+	}
+
+	changed := *base
+	changed.Environment = map[string]string{"ENV": "dev"}
+	if client.ConfigHash(base) == client.ConfigHash(&changed) {
+		t.Error("环境变量变化应改变配置哈希")
+	}
+
+	withInit := *base
+	withInit.InitContainers = []InitContainer{{Name: "migrate", Image: "myapp", Tag: "1.0", Command: []string{"./migrate"}}}
+	if client.ConfigHash(base) == client.ConfigHash(&withInit) || !client.CompareServiceConfig(base, &withInit) {
+		t.Error("初始化容器变化应改变配置哈希")
+	}
+	withInit.InitContainers = []InitContainer{}
+	if client.ConfigHash(base) != client.ConfigHash(&withInit) || client.CompareServiceConfig(base, &withInit) {
+		t.Error("空的初始化容器列表不应影响配置哈希")
+	}
+
+	withSidecar := *base
+	withSidecar.Sidecars = []Sidecar{{Name: "log-shipper", Image: "fluent/fluent-bit", Tag: "3.0"}}
+	if client.ConfigHash(base) == client.ConfigHash(&withSidecar) || !client.CompareServiceConfig(base, &withSidecar) {
+		t.Error("边车容器变化应改变配置哈希")
+	}
+
+	labeled := ContainerInfo{Labels: map[string]string{client.LabelKey("config_hash"): client.ConfigHash(base)}}
+	if !client.MatchesConfig(labeled, &scaled) || client.MatchesConfig(labeled, &changed) {
+		t.Error("MatchesConfig 应按 config_hash 标签判断")
+	}
+}
+
+// TestCalculateStats 测试 CPU、内存使用率计算
+func TestCalculateStats(t *testing.T) {
+	stats := &container.StatsResponse{}
+	stats.PreCPUStats.CPUUsage.TotalUsage = 1000
+	stats.PreCPUStats.SystemUsage = 10000
+	stats.CPUStats.CPUUsage.TotalUsage = 1500
+	stats.CPUStats.SystemUsage = 12000
+	stats.CPUStats.OnlineCPUs = 4
+	stats.MemoryStats.Usage = 300 << 20
+	stats.MemoryStats.Limit = 512 << 20
+	stats.MemoryStats.Stats = map[string]uint64{"inactive_file": 44 << 20}
+	stats.Networks = map[string]container.NetworkStats{"eth0": {RxBytes: 100, TxBytes: 200}, "eth1": {RxBytes: 1, TxBytes: 2}}
+	stats.BlkioStats.IoServiceBytesRecursive = []container.BlkioStatEntry{{Op: "Read", Value: 4096}, {Op: "write", Value: 8192}, {Op: "Total", Value: 12288}}
+	stats.PidsStats.Current = 7
+
+	result := calculateStats(stats)
+	if result.CPUPercent != 100 {
+		t.Errorf("CPUPercent = %v, want 100", result.CPUPercent)
+	}
+	if result.MemoryUsage != 256<<20 || result.MemoryPercent != 50 {
+		t.Errorf("MemoryUsage = %d, MemoryPercent = %v, want 256MB, 50", result.MemoryUsage, result.MemoryPercent)
+	}
+	if result.NetworkRx != 101 || result.NetworkTx != 202 || result.BlockRead != 4096 || result.BlockWrite != 8192 || result.PIDs != 7 {
+		t.Errorf("network %d/%d, block %d/%d, pids %d, want 101/202, 4096/8192, 7", result.NetworkRx, result.NetworkTx, result.BlockRead, result.BlockWrite, result.PIDs)
+	}
+}
@@ -0,0 +1,453 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/aichy126/igo/context"
+)
+
+// typenameField 所有对象类型都支持的内省字段
+const typenameField = "__typename"
+
+// Request GraphQL 请求
+type Request struct {
+	Query         string                 `json:"query" example:"{ services { name instances { container_name } } }" description:"查询文档"`
+	OperationName string                 `json:"operationName,omitempty" example:"Topology" description:"文档包含多个操作时要执行的操作名称"`
+	Variables     map[string]interface{} `json:"variables,omitempty" description:"变量"`
+}
+
+// Response GraphQL 响应，解析或校验失败时 data 为 null
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error GraphQL 错误，path 为出错字段在结果中的路径
+type Error struct {
+	Message string        `json:"message" example:"service nginx-web not found" description:"错误信息"`
+	Path    []interface{} `json:"path,omitempty" description:"出错字段在结果中的路径"`
+}
+
+// orderedMap 按查询中字段的顺序序列化的对象
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON 按字段顺序输出
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// executor 一次请求的执行状态
+type executor struct {
+	doc       *document
+	defined   map[string]bool // 操作中定义的变量
+	variables map[string]interface{}
+	validated map[fragmentOn]bool // 已在对应类型上校验过的命名片段
+	errors    []*Error
+}
+
+// fragmentOn 命名片段及其展开位置的对象类型
+type fragmentOn struct {
+	name string
+	obj  *Object
+}
+
+// size 选择集展开片段后的嵌套层数和字段数
+type size struct {
+	depth  int
+	fields int
+}
+
+// Execute 执行查询：解析、校验后依次解析各字段
+// 解析和校验错误时不执行任何字段；字段解析出错时该字段为 null，错误带路径列在 errors 中，其余字段照常返回
+func (s *Schema) Execute(ctx context.IContext, req *Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return errorResponse(err)
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if op.kind != "query" {
+		return errorResponse(fmt.Errorf("%s operations are not supported, the schema is read-only", op.kind))
+	}
+	variables, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	e := &executor{doc: doc, defined: make(map[string]bool), variables: variables, validated: make(map[fragmentOn]bool)}
+	for _, def := range op.variables {
+		e.defined[def.name] = true
+	}
+	e.validate(op.selections, s.Query, nil, make(map[string]bool))
+	if len(e.errors) > 0 {
+		return &Response{Errors: e.errors}
+	}
+	if err := s.checkSize(e.measure(op.selections, s.maxFields(), make(map[string]size))); err != nil {
+		return errorResponse(err)
+	}
+	data := e.executeSelections(ctx, op.selections, s.Query, nil, nil)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func (s *Schema) maxDepth() int {
+	if s.MaxDepth > 0 {
+		return s.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+func (s *Schema) maxFields() int {
+	if s.MaxFields > 0 {
+		return s.MaxFields
+	}
+	return DefaultMaxFields
+}
+
+// checkSize 执行前检查查询的嵌套层数和展开片段后的字段数，避免深层嵌套或片段反复展开耗尽资源
+func (s *Schema) checkSize(sz size) error {
+	if sz.depth > s.maxDepth() {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", sz.depth, s.maxDepth())
+	}
+	if sz.fields > s.maxFields() {
+		return fmt.Errorf("query selects more than %d fields after expanding fragments", s.maxFields())
+	}
+	return nil
+}
+
+func errorResponse(err error) *Response {
+	return &Response{Errors: []*Error{{Message: err.Error()}}}
+}
+
+// selectOperation 选择要执行的操作：指定名称时按名称查找，否则文档只能包含一个操作
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document contains multiple operations")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// coerceVariables 合并请求中的变量和默认值，检查必填变量
+func coerceVariables(op *operation, provided map[string]interface{}) (map[string]interface{}, error) {
+	variables := make(map[string]interface{})
+	for _, def := range op.variables {
+		value, ok := provided[def.name]
+		if !ok && def.hasDefault {
+			value, ok = def.defaultValue, true
+		}
+		if def.nonNull && value == nil {
+			return nil, fmt.Errorf("variable $%s is required", def.name)
+		}
+		if ok {
+			variables[def.name] = value
+		}
+	}
+	return variables, nil
+}
+
+// validate 校验选择集：字段和参数存在，对象字段有子选择集、标量字段没有，片段存在且类型匹配，指令受支持
+// 命名片段在同一对象类型上只校验一次，片段多次展开时校验时间不会随展开次数指数增长
+func (e *executor) validate(selections []*selection, obj *Object, path []interface{}, fragments map[string]bool) {
+	for _, sel := range selections {
+		for _, d := range sel.directives {
+			if d.name != "include" && d.name != "skip" {
+				e.fail(path, "unknown directive @%s", d.name)
+			} else if _, ok := d.arguments["if"]; !ok || len(d.arguments) != 1 {
+				e.fail(path, "directive @%s requires exactly the argument \"if\"", d.name)
+			}
+			e.validateVariables(d.arguments, path)
+		}
+		switch {
+		case sel.spread != "":
+			frag, ok := e.doc.fragments[sel.spread]
+			if !ok {
+				e.fail(path, "unknown fragment %q", sel.spread)
+				continue
+			}
+			if fragments[sel.spread] {
+				e.fail(path, "fragment %q spreads itself", sel.spread)
+				continue
+			}
+			key := fragmentOn{name: sel.spread, obj: obj}
+			if e.validated[key] {
+				continue
+			}
+			e.validateFragment(frag, obj, path)
+			fragments[sel.spread] = true
+			e.validate(frag.selections, obj, path, fragments)
+			delete(fragments, sel.spread)
+			e.validated[key] = true
+		case sel.inline != nil:
+			e.validateFragment(sel.inline, obj, path)
+			e.validate(sel.inline.selections, obj, path, fragments)
+		default:
+			e.validateField(sel.field, obj, path, fragments)
+		}
+	}
+}
+
+// validateFragment 片段的类型条件必须为当前对象类型
+func (e *executor) validateFragment(frag *fragment, obj *Object, path []interface{}) {
+	if frag.typeCondition != "" && frag.typeCondition != obj.Name {
+		e.fail(path, "fragment on %s cannot be spread on type %s", frag.typeCondition, obj.Name)
+	}
+}
+
+func (e *executor) validateField(f *field, obj *Object, path []interface{}, fragments map[string]bool) {
+	path = appendPath(path, f.responseKey())
+	if f.name == typenameField {
+		if len(f.arguments) > 0 || f.selections != nil {
+			e.fail(path, "field %s does not accept arguments or selections", typenameField)
+		}
+		return
+	}
+	def, ok := obj.Fields[f.name]
+	if !ok {
+		e.fail(path, "cannot query field %q on type %s", f.name, obj.Name)
+		return
+	}
+	for name := range f.arguments {
+		if !containsString(def.Args, name) {
+			e.fail(path, "unknown argument %q on field %s.%s", name, obj.Name, f.name)
+		}
+	}
+	e.validateVariables(f.arguments, path)
+	switch {
+	case def.Type != nil && f.selections == nil:
+		e.fail(path, "field %q of type %s must have a selection of subfields", f.name, def.Type.Name)
+	case def.Type == nil && f.selections != nil:
+		e.fail(path, "field %q is a scalar and cannot have a selection of subfields", f.name)
+	case def.Type != nil:
+		e.validate(f.selections, def.Type, path, fragments)
+	}
+}
+
+// validateVariables 参数值中引用的变量必须在操作中定义
+func (e *executor) validateVariables(value interface{}, path []interface{}) {
+	switch value := value.(type) {
+	case variable:
+		if !e.defined[string(value)] {
+			e.fail(path, "variable $%s is not defined", string(value))
+		}
+	case []interface{}:
+		for _, item := range value {
+			e.validateVariables(item, path)
+		}
+	case map[string]interface{}:
+		for _, item := range value {
+			e.validateVariables(item, path)
+		}
+	}
+}
+
+// measure 计算选择集展开片段后的嵌套层数和字段数（不考虑 @include / @skip），命名片段的结果缓存在 memo 中；
+// 字段数超过 limit 后按 limit+1 计，避免片段反复展开时计数溢出。调用前片段必须已通过校验（不存在循环）
+func (e *executor) measure(selections []*selection, limit int, memo map[string]size) size {
+	var total size
+	add := func(sub size) {
+		if sub.depth > total.depth {
+			total.depth = sub.depth
+		}
+		total.fields += sub.fields
+		if total.fields > limit {
+			total.fields = limit + 1
+		}
+	}
+	for _, sel := range selections {
+		switch {
+		case sel.spread != "":
+			sub, ok := memo[sel.spread]
+			if !ok {
+				sub = e.measure(e.doc.fragments[sel.spread].selections, limit, memo)
+				memo[sel.spread] = sub
+			}
+			add(sub)
+		case sel.inline != nil:
+			add(e.measure(sel.inline.selections, limit, memo))
+		default:
+			sub := e.measure(sel.field.selections, limit, memo)
+			add(size{depth: sub.depth + 1, fields: sub.fields + 1})
+		}
+	}
+	return total
+}
+
+// executeSelections 解析对象的选择集，同名（别名）字段合并子选择集
+func (e *executor) executeSelections(ctx context.IContext, selections []*selection, obj *Object, source interface{}, path []interface{}) *orderedMap {
+	result := &orderedMap{values: make(map[string]interface{})}
+	grouped := make(map[string][]*field)
+	var keys []string
+	e.collectFields(selections, grouped, &keys)
+	for _, key := range keys {
+		result.set(key, e.executeField(ctx, obj, grouped[key], source, appendPath(path, key)))
+	}
+	return result
+}
+
+// collectFields 展开片段、按指令跳过字段，按结果名称分组并保持首次出现的顺序
+func (e *executor) collectFields(selections []*selection, grouped map[string][]*field, keys *[]string) {
+	for _, sel := range selections {
+		if !e.included(sel.directives) {
+			continue
+		}
+		switch {
+		case sel.spread != "":
+			e.collectFields(e.doc.fragments[sel.spread].selections, grouped, keys)
+		case sel.inline != nil:
+			e.collectFields(sel.inline.selections, grouped, keys)
+		default:
+			key := sel.field.responseKey()
+			if _, exists := grouped[key]; !exists {
+				*keys = append(*keys, key)
+			}
+			grouped[key] = append(grouped[key], sel.field)
+		}
+	}
+}
+
+// included 按 @include(if:) 和 @skip(if:) 判断是否包含该项
+func (e *executor) included(directives []*directive) bool {
+	for _, d := range directives {
+		value, _ := e.resolveValue(d.arguments["if"]).(bool)
+		if d.name == "include" && !value || d.name == "skip" && value {
+			return false
+		}
+	}
+	return true
+}
+
+// executeField 解析字段并按字段类型生成结果
+func (e *executor) executeField(ctx context.IContext, obj *Object, fields []*field, source interface{}, path []interface{}) interface{} {
+	f := fields[0]
+	if f.name == typenameField {
+		return obj.Name
+	}
+	def := obj.Fields[f.name]
+
+	var value interface{}
+	if def.Resolve != nil {
+		args, _ := e.resolveValue(f.arguments).(map[string]interface{})
+		if args == nil {
+			args = make(map[string]interface{})
+		}
+		var err error
+		if value, err = def.Resolve(ctx, source, args); err != nil {
+			e.errors = append(e.errors, &Error{Message: err.Error(), Path: path})
+			return nil
+		}
+	} else {
+		value = defaultResolve(source, f.name)
+	}
+	if def.Type == nil {
+		return value
+	}
+
+	var selections []*selection
+	for _, f := range fields {
+		selections = append(selections, f.selections...)
+	}
+	return e.complete(ctx, def.Type, value, selections, path)
+}
+
+// complete 对象值解析子选择集，切片逐个元素处理，nil 返回 null
+func (e *executor) complete(ctx context.IContext, obj *Object, value interface{}, selections []*selection, path []interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = e.complete(ctx, obj, v.Index(i).Interface(), selections, appendPath(path, i))
+		}
+		return list
+	}
+	return e.executeSelections(ctx, selections, obj, value, path)
+}
+
+// resolveValue 代入参数值中的变量，未提供的变量为 null
+func (e *executor) resolveValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case variable:
+		return e.variables[string(value)]
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, item := range value {
+			list[i] = e.resolveValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			object[key] = e.resolveValue(item)
+		}
+		return object
+	}
+	return value
+}
+
+func (e *executor) fail(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, &Error{Message: fmt.Sprintf(format, args...), Path: path})
+}
+
+// appendPath 复制路径后追加，避免兄弟字段共用底层数组
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	result := make([]interface{}, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, key)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aichy126/igo/context"
+)
+
+type testBackend struct {
+	Port     int   `json:"port"`
+	Requests int64 `json:"requests"`
+}
+
+type testReplica struct {
+	Name     string            `json:"name"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Backends []testBackend     `json:"backends"`
+	internal string
+}
+
+type testService struct {
+	Name     string         `json:"name"`
+	Replicas int            `json:"replicas"`
+	Items    []*testReplica `json:"items"`
+}
+
+// testSchema services(name) → Service{name replicas items{...} upper}
+func testSchema() *Schema {
+	services := []*testService{
+		{Name: "api", Replicas: 2, Items: []*testReplica{
+			{Name: "api-1", Labels: map[string]string{"zone": "a"}, Backends: []testBackend{{Port: 32768, Requests: 10}}},
+			{Name: "api-2"},
+		}},
+		{Name: "web", Replicas: 0},
+	}
+	service := ObjectOf(testService{})
+	service.AddField("upper", &Field{Resolve: func(ctx context.IContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+		return strings.ToUpper(source.(*testService).Name), nil
+	}})
+	service.AddField("broken", &Field{Resolve: func(ctx context.IContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("broken %s", source.(*testService).Name)
+	}})
+	query := NewObject("Query").
+		AddField("services", &Field{Type: service, Args: []string{"name", "limit"}, Resolve: func(ctx context.IContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+			name, err := StringArg(args, "name")
+			if err != nil {
+				return nil, err
+			}
+			limit, err := IntArg(args, "limit")
+			if err != nil {
+				return nil, err
+			}
+			var result []*testService
+			for _, s := range services {
+				if name == "" || s.Name == name {
+					result = append(result, s)
+				}
+			}
+			if limit > 0 && limit < len(result) {
+				result = result[:limit]
+			}
+			return result, nil
+		}})
+	return &Schema{Query: query}
+}
+
+func execute(t *testing.T, req *Request) string {
+	t.Helper()
+	data, err := json.Marshal(testSchema().Execute(context.Background(), req))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return string(data)
+}
+
+// TestObjectOf 测试按 JSON 标签生成对象类型
+func TestObjectOf(t *testing.T) {
+	obj := ObjectOf(&testService{})
+	if obj.Name != "testService" {
+		t.Errorf("Name = %s, 期望 testService", obj.Name)
+	}
+	for _, name := range []string{"name", "replicas", "items"} {
+		if obj.Fields[name] == nil {
+			t.Errorf("缺少字段 %s", name)
+		}
+	}
+	items := obj.Fields["items"].Type
+	if items == nil || items.Name != "testReplica" {
+		t.Fatalf("items 类型 = %v, 期望 testReplica", items)
+	}
+	if items.Fields["labels"] == nil || items.Fields["labels"].Type != nil {
+		t.Errorf("labels 应为标量字段")
+	}
+	if items.Fields["internal"] != nil {
+		t.Errorf("未导出字段不应生成")
+	}
+	if items.Fields["backends"].Type == nil || items.Fields["backends"].Type.Fields["requests"] == nil {
+		t.Errorf("backends 应为对象列表")
+	}
+}
+
+// TestExecute 测试查询的执行结果
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *Request
+		want string
+	}{
+		{
+			name: "嵌套选择集按查询顺序输出",
+			req:  &Request{Query: `{ services { replicas name items { name backends { port requests } labels } } }`},
+			want: `{"data":{"services":[` +
+				`{"replicas":2,"name":"api","items":[{"name":"api-1","backends":[{"port":32768,"requests":10}],"labels":{"zone":"a"}},{"name":"api-2","backends":null,"labels":null}]},` +
+				`{"replicas":0,"name":"web","items":null}]}}`,
+		},
+		{
+			name: "别名、参数和 __typename",
+			req:  &Request{Query: `query { first: services(limit: 1) { __typename name } web: services(name: "web") { upper } }`},
+			want: `{"data":{"first":[{"__typename":"testService","name":"api"}],"web":[{"upper":"WEB"}]}}`,
+		},
+		{
+			name: "变量、默认值和指令",
+			req: &Request{
+				Query:     `query Q($name: String!, $withItems: Boolean = false) { services(name: $name) { name items @include(if: $withItems) { name } replicas @skip(if: true) } }`,
+				Variables: map[string]interface{}{"name": "api"},
+			},
+			want: `{"data":{"services":[{"name":"api"}]}}`,
+		},
+		{
+			name: "JSON 变量中的整数",
+			req:  &Request{Query: `query ($limit: Int) { services(limit: $limit) { name } }`, Variables: map[string]interface{}{"limit": float64(1)}},
+			want: `{"data":{"services":[{"name":"api"}]}}`,
+		},
+		{
+			name: "片段合并同名字段的子选择集",
+			req: &Request{Query: `
+				# 注释
+				query A { services(name: "api") { ...Items items { backends { port } } ... on testService { name } } }
+				fragment Items on testService { items { name } }
+				query B { services { name } }`, OperationName: "A"},
+			want: `{"data":{"services":[{"items":[{"name":"api-1","backends":[{"port":32768}]},{"name":"api-2","backends":null}],"name":"api"}]}}`,
+		},
+		{
+			name: "字段错误只影响该字段",
+			req:  &Request{Query: `{ services { name broken } }`},
+			want: `{"data":{"services":[{"name":"api","broken":null},{"name":"web","broken":null}]},` +
+				`"errors":[{"message":"broken api","path":["services",0,"broken"]},{"message":"broken web","path":["services",1,"broken"]}]}`,
+		},
+		{
+			name: "参数类型错误",
+			req:  &Request{Query: `{ services(limit: "one") { name } }`},
+			want: `{"data":{"services":null},"errors":[{"message":"argument \"limit\" must be an integer","path":["services"]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		if got := execute(t, tt.req); got != tt.want {
+			t.Errorf("%s:\n得到 %s\n期望 %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestExecuteErrors 测试语法和校验错误时不执行任何字段
+func TestExecuteErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`{ services { name }`, "syntax error at line 1 column 20: unexpected end of document"},
+		{"{\n  services(name: \"a\n) { name } }", "syntax error at line 2 column 18: unterminated string"},
+		{`{ services { } }`, "selection set cannot be empty"},
+		{`mutation { services { name } }`, "mutation operations are not supported"},
+		{`query A { services { name } } query B { services { name } }`, "operationName is required"},
+		{`{ unknown }`, `cannot query field "unknown" on type Query`},
+		{`{ services }`, "must have a selection of subfields"},
+		{`{ services { name { length } } }`, "is a scalar"},
+		{`{ services(region: "x") { name } }`, `unknown argument "region"`},
+		{`{ services(name: $name) { name } }`, "variable $name is not defined"},
+		{`query ($name: String!) { services(name: $name) { name } }`, "variable $name is required"},
+		{`{ services { ...Missing } }`, `unknown fragment "Missing"`},
+		{`{ services { ...A } } fragment A on testService { ...A }`, `fragment "A" spreads itself`},
+		{`{ services { ... on Query { name } } }`, "cannot be spread on type testService"},
+		{`{ services { name @deprecated } }`, "unknown directive @deprecated"},
+	}
+	for _, tt := range tests {
+		resp := testSchema().Execute(context.Background(), &Request{Query: tt.query})
+		if resp.Data != nil {
+			t.Errorf("%s: data = %v, 期望 null", tt.query, resp.Data)
+		}
+		if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.want) {
+			t.Errorf("%s: errors = %v, 期望包含 %q", tt.query, resp.Errors, tt.want)
+		}
+	}
+}
+
+// TestExecuteLimits 测试片段反复展开和深层嵌套的查询在执行前被拒绝
+func TestExecuteLimits(t *testing.T) {
+	// 每个片段展开下一个片段两次，完全展开后有 2^40 个字段
+	var doc strings.Builder
+	doc.WriteString("{ services { ...F0 } }")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&doc, " fragment F%d on testService { name ...F%d ...F%d }", i, i+1, i+1)
+	}
+	doc.WriteString(" fragment F40 on testService { name }")
+
+	done := make(chan *Response, 1)
+	go func() { done <- testSchema().Execute(context.Background(), &Request{Query: doc.String()}) }()
+	select {
+	case resp := <-done:
+		if resp.Data != nil || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "more than 1000 fields") {
+			t.Errorf("片段反复展开: data = %v, errors = %v, 期望超出字段数上限", resp.Data, resp.Errors)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("片段反复展开的查询没有在执行前被拒绝")
+	}
+
+	schema := testSchema()
+	schema.MaxDepth = 3
+	query := `{ services { items { backends { port } } } }`
+	if resp := schema.Execute(context.Background(), &Request{Query: query}); resp.Data != nil || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "depth 4 exceeds the limit of 3") {
+		t.Errorf("嵌套层数超限: data = %v, errors = %v", resp.Data, resp.Errors)
+	}
+	schema.MaxDepth = 4
+	if resp := schema.Execute(context.Background(), &Request{Query: query}); len(resp.Errors) != 0 {
+		t.Errorf("未超出上限时 errors = %v", resp.Errors)
+	}
+
+	schema.MaxFields = 3
+	if resp := schema.Execute(context.Background(), &Request{Query: `{ services { ...N ...N } } fragment N on testService { name replicas }`}); len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, "more than 3 fields") {
+		t.Errorf("字段数超限: errors = %v", resp.Errors)
+	}
+}
@@ -0,0 +1,658 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token 词法单元，pos 为在查询文本中的字节偏移
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// document 解析后的查询文档
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation 操作定义（query / mutation / subscription）
+type operation struct {
+	kind       string
+	name       string
+	variables  []*variableDef
+	selections []*selection
+}
+
+// variableDef 变量定义，只记录是否必填和默认值，不校验变量类型
+type variableDef struct {
+	name         string
+	nonNull      bool
+	hasDefault   bool
+	defaultValue interface{}
+}
+
+// fragment 具名片段或内联片段，typeCondition 为空表示不限类型
+type fragment struct {
+	name          string
+	typeCondition string
+	selections    []*selection
+}
+
+// selection 选择集中的一项：字段、具名片段引用（spread）或内联片段（inline）之一
+type selection struct {
+	field      *field
+	spread     string
+	inline     *fragment
+	directives []*directive
+}
+
+// field 查询的字段
+type field struct {
+	alias      string
+	name       string
+	arguments  map[string]interface{}
+	selections []*selection
+}
+
+// responseKey 字段在结果中的名称：有别名时为别名
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// directive 指令，只支持 @include 和 @skip
+type directive struct {
+	name      string
+	arguments map[string]interface{}
+}
+
+// variable 参数值中的变量引用
+type variable string
+
+// parser 递归下降解析器，tok 为当前词法单元
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+// parse 解析查询文档
+func parse(src string) (*document, error) {
+	p := &parser{src: src}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunct, "{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selections: selections})
+		case p.peek(tokenName, "query"), p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peek(tokenName, "fragment"):
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.fragments[frag.name]; exists {
+				return nil, fmt.Errorf("fragment %q is defined more than once", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("document does not contain any operation")
+	}
+	return doc, nil
+}
+
+// parseOperation 解析带关键字的操作定义
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek(tokenPunct, "(") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for !p.peek(tokenPunct, ")") {
+			def, err := p.parseVariableDef()
+			if err != nil {
+				return nil, err
+			}
+			op.variables = append(op.variables, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+	return op, nil
+}
+
+// parseVariableDef 解析变量定义 $name: Type = default
+func (p *parser) parseVariableDef() (*variableDef, error) {
+	if err := p.expect(tokenPunct, "$"); err != nil {
+		return nil, err
+	}
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokenPunct, ":"); err != nil {
+		return nil, err
+	}
+	def := &variableDef{name: name}
+	if def.nonNull, err = p.parseType(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunct, "=") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if def.defaultValue, err = p.parseValue(true); err != nil {
+			return nil, err
+		}
+		def.hasDefault = true
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// parseType 解析变量类型（Name、[Type]，可带 !），返回是否必填
+func (p *parser) parseType() (bool, error) {
+	if p.peek(tokenPunct, "[") {
+		if err := p.advance(); err != nil {
+			return false, err
+		}
+		if _, err := p.parseType(); err != nil {
+			return false, err
+		}
+		if err := p.expect(tokenPunct, "]"); err != nil {
+			return false, err
+		}
+	} else if _, err := p.parseName(); err != nil {
+		return false, err
+	}
+	if p.peek(tokenPunct, "!") {
+		return true, p.advance()
+	}
+	return false, nil
+}
+
+// parseFragment 解析具名片段 fragment Name on Type { ... }
+func (p *parser) parseFragment() (*fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, fmt.Errorf("fragment cannot be named \"on\"")
+	}
+	if err := p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+	frag := &fragment{name: name}
+	if frag.typeCondition, err = p.parseName(); err != nil {
+		return nil, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if frag.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return frag, nil
+}
+
+// parseSelectionSet 解析 { ... } 选择集
+func (p *parser) parseSelectionSet() ([]*selection, error) {
+	if err := p.expect(tokenPunct, "{"); err != nil {
+		return nil, err
+	}
+	var selections []*selection
+	for !p.peek(tokenPunct, "}") {
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	if len(selections) == 0 {
+		return nil, p.errorf("selection set cannot be empty")
+	}
+	return selections, p.advance()
+}
+
+// parseSelection 解析选择集中的一项
+func (p *parser) parseSelection() (*selection, error) {
+	var err error
+	sel := &selection{}
+	if !p.peek(tokenPunct, "...") {
+		if sel.field, err = p.parseField(); err != nil {
+			return nil, err
+		}
+		sel.directives, err = p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek(tokenPunct, "{") {
+			sel.field.selections, err = p.parseSelectionSet()
+		}
+		return sel, err
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		sel.spread = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		sel.directives, err = p.parseDirectives()
+		return sel, err
+	}
+	sel.inline = &fragment{}
+	if p.peek(tokenName, "on") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if sel.inline.typeCondition, err = p.parseName(); err != nil {
+			return nil, err
+		}
+	}
+	if sel.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	sel.inline.selections, err = p.parseSelectionSet()
+	return sel, err
+}
+
+// parseField 解析字段的别名、名称和参数，不包括指令和子选择集
+func (p *parser) parseField() (*field, error) {
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	f := &field{name: name}
+	if p.peek(tokenPunct, ":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		f.alias = name
+		if f.name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+	}
+	if f.arguments, err = p.parseArguments(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseArguments 解析 (name: value, ...)，没有参数时返回 nil
+func (p *parser) parseArguments() (map[string]interface{}, error) {
+	if !p.peek(tokenPunct, "(") {
+		return nil, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	args := make(map[string]interface{})
+	for !p.peek(tokenPunct, ")") {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+		if _, exists := args[name]; exists {
+			return nil, p.errorf("argument %q is specified more than once", name)
+		}
+		if args[name], err = p.parseValue(false); err != nil {
+			return nil, err
+		}
+	}
+	return args, p.advance()
+}
+
+// parseDirectives 解析 @name(args) 指令列表
+func (p *parser) parseDirectives() ([]*directive, error) {
+	var directives []*directive
+	for p.peek(tokenPunct, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, &directive{name: name, arguments: args})
+	}
+	return directives, nil
+}
+
+// parseValue 解析参数值，constant 为 true 时不允许变量（变量默认值）
+// 整数解析为 int，浮点数为 float64，枚举值按字符串处理
+func (p *parser) parseValue(constant bool) (interface{}, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt:
+		n, err := strconv.Atoi(tok.value)
+		if err != nil {
+			return nil, p.errorf("invalid integer %s", tok.value)
+		}
+		return n, p.advance()
+	case tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %s", tok.value)
+		}
+		return f, p.advance()
+	case tokenString:
+		return tok.value, p.advance()
+	case tokenName:
+		var value interface{} = tok.value
+		switch tok.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		}
+		return value, p.advance()
+	}
+
+	switch tok.value {
+	case "$":
+		if constant {
+			return nil, p.errorf("variables are not allowed in default values")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		return variable(name), err
+	case "[":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, 0)
+		for !p.peek(tokenPunct, "]") {
+			item, err := p.parseValue(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, p.advance()
+	case "{":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		object := make(map[string]interface{})
+		for !p.peek(tokenPunct, "}") {
+			name, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenPunct, ":"); err != nil {
+				return nil, err
+			}
+			if object[name], err = p.parseValue(constant); err != nil {
+				return nil, err
+			}
+		}
+		return object, p.advance()
+	}
+	return nil, p.unexpected()
+}
+
+// parseName 读取一个名称
+func (p *parser) parseName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+// peek 当前词法单元是否为指定类型和值
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+// expect 要求当前词法单元为指定类型和值并前进
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.peek(kind, value) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+// unexpected 当前词法单元不符合语法
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return p.errorf("unexpected end of document")
+	}
+	return p.errorf("unexpected %q", p.tok.value)
+}
+
+// errorf 带行列位置的语法错误
+func (p *parser) errorf(format string, args ...interface{}) error {
+	line, column := 1, 1
+	for _, r := range p.src[:p.tok.pos] {
+		if r == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+	return fmt.Errorf("syntax error at line %d column %d: %s", line, column, fmt.Sprintf(format, args...))
+}
+
+// advance 读取下一个词法单元，跳过空白、逗号和注释
+func (p *parser) advance() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+		} else if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		} else if strings.HasPrefix(p.src[p.pos:], "\uFEFF") {
+			p.pos += len("\uFEFF")
+		} else {
+			break
+		}
+	}
+	start := p.pos
+	p.tok = token{kind: tokenEOF, pos: start}
+	if p.pos >= len(p.src) {
+		return nil
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokenPunct, value: "...", pos: start}
+	case strings.IndexByte("!$()[]{}:=@|&", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokenPunct, value: string(c), pos: start}
+	case c == '_' || isLetter(c):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || isLetter(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenName, value: p.src[start:p.pos], pos: start}
+	case c == '-' || isDigit(c):
+		return p.readNumber()
+	case c == '"':
+		return p.readString()
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		p.tok.value = string(r)
+		return p.errorf("unexpected character %q", r)
+	}
+	return nil
+}
+
+// readNumber 读取整数或浮点数
+func (p *parser) readNumber() error {
+	start := p.pos
+	kind := tokenInt
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	digits := func() int {
+		from := p.pos
+		for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+			p.pos++
+		}
+		return p.pos - from
+	}
+	if digits() == 0 {
+		return p.errorf("invalid number")
+	}
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		p.pos++
+		kind = tokenFloat
+		if digits() == 0 {
+			return p.errorf("invalid number")
+		}
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		p.pos++
+		kind = tokenFloat
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		if digits() == 0 {
+			return p.errorf("invalid number")
+		}
+	}
+	p.tok = token{kind: kind, value: p.src[start:p.pos], pos: start}
+	return nil
+}
+
+// readString 读取字符串，支持转义和 """ 块字符串（块字符串不处理缩进）
+func (p *parser) readString() error {
+	start := p.pos
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		end := strings.Index(p.src[p.pos+3:], `"""`)
+		if end < 0 {
+			return p.errorf("unterminated string")
+		}
+		value := strings.ReplaceAll(p.src[p.pos+3:p.pos+3+end], `\"""`, `"""`)
+		p.pos += end + 6
+		p.tok = token{kind: tokenString, value: value, pos: start}
+		return nil
+	}
+
+	var b strings.Builder
+	p.pos++
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			p.tok = token{kind: tokenString, value: b.String(), pos: start}
+			return nil
+		case c == '\n' || c == '\r':
+			return p.errorf("unterminated string")
+		case c == '\\' && p.pos+1 < len(p.src):
+			escape := p.src[p.pos+1]
+			p.pos += 2
+			switch escape {
+			case '"', '\\', '/':
+				b.WriteByte(escape)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.src) {
+					return p.errorf("invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return p.errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(code))
+				p.pos += 4
+			default:
+				return p.errorf("invalid escape \\%c", escape)
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return p.errorf("unterminated string")
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
@@ -0,0 +1,187 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aichy126/igo/context"
+)
+
+// ResolveFunc 字段解析函数，source 为父对象的值（根字段为 nil），args 为已代入变量的参数
+type ResolveFunc func(ctx context.IContext, source interface{}, args map[string]interface{}) (interface{}, error)
+
+// Field 对象类型的字段
+type Field struct {
+	Type    *Object     // 对象类型，返回切片时为元素的类型；标量字段（包括 map）为 nil
+	Args    []string    // 接受的参数名称
+	Resolve ResolveFunc // 为空时按 JSON 名称读取 source 中的字段
+}
+
+// Object 对象类型
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// NewObject 创建没有字段的对象类型
+func NewObject(name string) *Object {
+	return &Object{Name: name, Fields: make(map[string]*Field)}
+}
+
+// AddField 添加或替换字段，返回对象本身便于链式调用
+func (o *Object) AddField(name string, field *Field) *Object {
+	o.Fields[name] = field
+	return o
+}
+
+// 查询复杂度的默认上限
+const (
+	DefaultMaxDepth  = 10   // 字段最大嵌套层数
+	DefaultMaxFields = 1000 // 展开片段后的最大字段数
+)
+
+// Schema 只读 GraphQL 模式，只有查询根类型
+type Schema struct {
+	Query     *Object
+	MaxDepth  int // 字段最大嵌套层数，为 0 时使用 DefaultMaxDepth
+	MaxFields int // 展开片段后的最大字段数，为 0 时使用 DefaultMaxFields
+}
+
+// timeType 按标量处理的结构体类型
+var timeType = reflect.TypeOf(time.Time{})
+
+// ObjectOf 按结构体的 JSON 标签生成对象类型：嵌套结构体及其切片生成子对象类型，其余字段为标量
+// 对象类型名称为 Go 类型名称，同一类型只生成一次
+func ObjectOf(sample interface{}) *Object {
+	return objectOf(reflect.TypeOf(sample), make(map[reflect.Type]*Object))
+}
+
+func objectOf(t reflect.Type, seen map[reflect.Type]*Object) *Object {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if obj, ok := seen[t]; ok {
+		return obj
+	}
+	obj := NewObject(t.Name())
+	seen[t] = obj
+	addStructFields(obj, t, seen)
+	return obj
+}
+
+// addStructFields 添加结构体的导出字段，匿名嵌入的结构体字段提升到外层
+func addStructFields(obj *Object, t reflect.Type, seen map[reflect.Type]*Object) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, ok := jsonName(sf)
+		if !ok {
+			continue
+		}
+		ft := sf.Type
+		if sf.Anonymous && ft.Kind() == reflect.Struct && !strings.Contains(string(sf.Tag), "json:") {
+			addStructFields(obj, ft, seen)
+			continue
+		}
+		for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+			ft = ft.Elem()
+		}
+		field := &Field{}
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			field.Type = objectOf(ft, seen)
+		}
+		obj.Fields[name] = field
+	}
+}
+
+// jsonName 结构体字段的 JSON 名称，json:"-" 的字段返回 false
+func jsonName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name, true
+	}
+	return sf.Name, true
+}
+
+// defaultResolve 按 JSON 名称读取结构体字段或 map 中的值
+func defaultResolve(source interface{}, name string) interface{} {
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return nil
+		}
+		return value.Interface()
+	case reflect.Struct:
+		if value, ok := structField(v, name); ok {
+			return value.Interface()
+		}
+	}
+	return nil
+}
+
+// structField 查找 JSON 名称为 name 的字段，包括匿名嵌入结构体中的字段
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && !strings.Contains(string(sf.Tag), "json:") {
+			if value, ok := structField(v.Field(i), name); ok {
+				return value, true
+			}
+			continue
+		}
+		if fieldName, ok := jsonName(sf); ok && fieldName == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// StringArg 读取字符串参数，未传入或为 null 时返回空字符串
+func StringArg(args map[string]interface{}, name string) (string, error) {
+	switch value := args[name].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	default:
+		return "", fmt.Errorf("argument %q must be a string", name)
+	}
+}
+
+// IntArg 读取整数参数，未传入或为 null 时返回 0；兼容 JSON 变量解析出的 float64
+func IntArg(args map[string]interface{}, name string) (int, error) {
+	switch value := args[name].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return value, nil
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) <= math.MaxInt32 {
+			return int(value), nil
+		}
+	}
+	return 0, fmt.Errorf("argument %q must be an integer", name)
+}
@@ -0,0 +1,284 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DockerHub Docker Hub 镜像仓库地址，未指定仓库的镜像默认使用
+const DockerHub = "registry-1.docker.io"
+
+// manifestAccept 查询 manifest 时接受的格式，包含多架构索引，与 docker pull 记录的 RepoDigests 一致
+var manifestAccept = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Reference 镜像在仓库中的位置
+type Reference struct {
+	Registry   string // 仓库地址，如 registry-1.docker.io、ghcr.io
+	Repository string // 仓库内路径，如 library/nginx
+}
+
+// ParseReference 解析不带标签的镜像名称
+// 第一段包含 "." 或 ":"，或为 localhost 时视为仓库地址，否则为 Docker Hub；Docker Hub 官方镜像补全 library/ 前缀
+func ParseReference(image string) Reference {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+
+	registry := DockerHub
+	if i := strings.Index(name, "/"); i > 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			registry = first
+			name = name[i+1:]
+		}
+	}
+	registry = NormalizeHost(registry)
+	if registry == DockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	return Reference{Registry: registry, Repository: name}
+}
+
+// NormalizeHost 规范化仓库地址，docker.io、index.docker.io 统一为 Docker Hub 的 API 地址
+func NormalizeHost(host string) string {
+	if host == "docker.io" || host == "index.docker.io" {
+		return DockerHub
+	}
+	return host
+}
+
+// Credential 仓库登录凭据
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Client 镜像仓库（Registry HTTP API v2）客户端，只读查询标签与 manifest 摘要
+// 支持匿名访问和 Bearer 令牌认证，配置了凭据的仓库使用 Basic 认证获取令牌
+type Client struct {
+	http        *http.Client
+	credentials map[string]Credential
+	insecure    map[string]bool
+}
+
+// NewClient 创建镜像仓库客户端，credentials 以仓库地址为键，insecure 中的仓库使用 HTTP 访问
+func NewClient(timeout time.Duration, credentials map[string]Credential, insecure []string) *Client {
+	c := &Client{
+		http:        &http.Client{Timeout: timeout},
+		credentials: credentials,
+		insecure:    make(map[string]bool),
+	}
+	for _, registry := range insecure {
+		c.insecure[registry] = true
+	}
+	return c
+}
+
+// Tags 列出镜像的全部标签
+func (c *Client) Tags(ctx context.Context, image string) ([]string, error) {
+	ref := ParseReference(image)
+	endpoint := c.endpoint(ref, "tags/list")
+	var tags []string
+	for endpoint != "" {
+		resp, err := c.do(ctx, http.MethodGet, endpoint, ref, nil)
+		if err != nil {
+			return nil, err
+		}
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode tags of %s: %w", image, err)
+		}
+		tags = append(tags, body.Tags...)
+		endpoint = nextPage(endpoint, resp.Header.Get("Link"))
+	}
+	return tags, nil
+}
+
+// Digest 查询标签当前指向的 manifest 摘要，多架构镜像返回索引的摘要
+func (c *Client) Digest(ctx context.Context, image, tag string) (string, error) {
+	ref := ParseReference(image)
+	resp, err := c.do(ctx, http.MethodHead, c.endpoint(ref, "manifests/"+tag), ref, manifestAccept)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("registry returned no digest for %s:%s", image, tag)
+	}
+	return digest, nil
+}
+
+// endpoint 拼接仓库 API 地址
+func (c *Client) endpoint(ref Reference, path string) string {
+	scheme := "https"
+	if c.insecure[ref.Registry] {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/%s", scheme, ref.Registry, ref.Repository, path)
+}
+
+// nextPage 按 Link 头返回下一页地址，没有时返回空字符串
+func nextPage(current, link string) string {
+	if link == "" {
+		return ""
+	}
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end <= start || !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+	base, err := url.Parse(current)
+	if err != nil {
+		return ""
+	}
+	next, err := base.Parse(link[start+1 : end])
+	if err != nil {
+		return ""
+	}
+	return next.String()
+}
+
+// do 发送请求，返回 401 时按 WWW-Authenticate 获取令牌后重试一次
+func (c *Client) do(ctx context.Context, method, endpoint string, ref Reference, accept []string) (*http.Response, error) {
+	resp, err := c.request(ctx, method, endpoint, ref, accept, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		authorization, err := c.authorize(ctx, ref, challenge)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = c.request(ctx, method, endpoint, ref, accept, authorization)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry %s returned %s for %s", ref.Registry, resp.Status, ref.Repository)
+	}
+	return resp, nil
+}
+
+func (c *Client) request(ctx context.Context, method, endpoint string, ref Reference, accept []string, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, mediaType := range accept {
+		req.Header.Add("Accept", mediaType)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query registry %s: %w", ref.Registry, err)
+	}
+	return resp, nil
+}
+
+// authorize 按认证质询生成 Authorization 头：Basic 质询直接使用凭据，Bearer 质询向认证服务换取令牌
+func (c *Client) authorize(ctx context.Context, ref Reference, challenge string) (string, error) {
+	credential, hasCredential := c.credentials[ref.Registry]
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if !hasCredential {
+			return "", fmt.Errorf("registry %s requires credentials", ref.Registry)
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(credential.Username, credential.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("registry %s returned unsupported authentication challenge %q", ref.Registry, challenge)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("registry %s returned invalid token realm %q", ref.Registry, params["realm"])
+	}
+	query := realm.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", ref.Repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCredential {
+		req.SetBasicAuth(credential.Username, credential.Password)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token service returned %s", resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("registry token service returned an empty token")
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge 解析 WWW-Authenticate 头，例如 Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, ", "), "=")
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			params[strings.ToLower(key)] = value
+		}
+	}
+	return strings.ToLower(scheme), params
+}
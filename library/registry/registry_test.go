@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseReference 测试镜像名称解析
+func TestParseReference(t *testing.T) {
+	cases := map[string]Reference{
+		"nginx":                       {Registry: DockerHub, Repository: "library/nginx"},
+		"bitnami/redis":               {Registry: DockerHub, Repository: "bitnami/redis"},
+		"docker.io/library/alpine":    {Registry: DockerHub, Repository: "library/alpine"},
+		"ghcr.io/acme/api":            {Registry: "ghcr.io", Repository: "acme/api"},
+		"localhost:5000/web":          {Registry: "localhost:5000", Repository: "web"},
+		"registry.local/team/app@sha": {Registry: "registry.local", Repository: "team/app"},
+	}
+	for image, want := range cases {
+		if got := ParseReference(image); got != want {
+			t.Errorf("ParseReference(%q) = %+v, 期望 %+v", image, got, want)
+		}
+	}
+}
+
+// TestLatestTag 测试按语义化版本选出最新标签
+func TestLatestTag(t *testing.T) {
+	tags := []string{"latest", "1.24", "1.25.3", "1.25.10", "1.26.0-rc1", "v2.0.0", "1.9"}
+	if tag := LatestTag("1.25.3", tags, regexp.MustCompile(`^1\.25\.`)); tag != "1.25.10" {
+		t.Errorf("LatestTag(1.25.x) = %q, 期望 1.25.10", tag)
+	}
+	if tag := LatestTag("1.25.3", tags, nil); tag != "v2.0.0" {
+		t.Errorf("LatestTag(全部) = %q, 期望 v2.0.0", tag)
+	}
+	if tag := LatestTag("v2.0.0", tags, nil); tag != "" {
+		t.Errorf("LatestTag 已是最新版本时 = %q, 期望为空", tag)
+	}
+	if tag := LatestTag("latest", tags, regexp.MustCompile(`^1\.`)); tag != "1.25.10" {
+		t.Errorf("LatestTag 当前标签不是版本号时 = %q, 期望 1.25.10", tag)
+	}
+}
+
+// TestClient 测试 Bearer 令牌认证、标签分页与摘要查询
+func TestClient(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:team/app:pull" {
+				t.Errorf("令牌请求 scope = %q", r.URL.Query().Get("scope"))
+			}
+			w.Write([]byte(`{"token":"secret"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v2/team/app/tags/list" && r.URL.Query().Get("last") == "":
+			w.Header().Set("Link", `</v2/team/app/tags/list?n=2&last=1.1>; rel="next"`)
+			w.Write([]byte(`{"name":"team/app","tags":["1.0","1.1"]}`))
+		case r.URL.Path == "/v2/team/app/tags/list":
+			w.Write([]byte(`{"name":"team/app","tags":["1.2"]}`))
+		case r.URL.Path == "/v2/team/app/manifests/1.2" && r.Method == http.MethodHead:
+			if !strings.Contains(strings.Join(r.Header.Values("Accept"), ","), "manifest.list.v2+json") {
+				t.Error("查询摘要时应接受多架构索引")
+			}
+			w.Header().Set("Docker-Content-Digest", "sha256:abc")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	client := NewClient(5*time.Second, nil, []string{host})
+	tags, err := client.Tags(t.Context(), host+"/team/app")
+	if err != nil || !reflect.DeepEqual(tags, []string{"1.0", "1.1", "1.2"}) {
+		t.Fatalf("Tags = %v, %v, 期望 [1.0 1.1 1.2]", tags, err)
+	}
+	digest, err := client.Digest(t.Context(), host+"/team/app", "1.2")
+	if err != nil || digest != "sha256:abc" {
+		t.Fatalf("Digest = %q, %v, 期望 sha256:abc", digest, err)
+	}
+	if _, err := client.Digest(t.Context(), host+"/team/app", "missing"); err == nil {
+		t.Error("标签不存在时应返回错误")
+	}
+}
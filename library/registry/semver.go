@@ -0,0 +1,64 @@
+package registry
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version 语义化版本号，只比较 主.次.修订 三段，缺少的段视为 0
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion 解析 1.2.3、v1.2、1 形式的标签；带预发布后缀（如 1.2.3-rc1）或非数字的标签返回 false
+func ParseVersion(tag string) (Version, bool) {
+	parts := strings.Split(strings.TrimPrefix(tag, "v"), ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return Version{}, false
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || part == "" || part[0] == '+' {
+			return Version{}, false
+		}
+		numbers[i] = n
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, true
+}
+
+// Less 版本号是否小于 other
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// LatestTag 在匹配 pattern 的语义化版本标签中选出最新且比 current 新的标签，没有时返回空字符串
+// pattern 为空时匹配全部标签；current 不是语义化版本时返回匹配标签中最新的一个
+func LatestTag(current string, tags []string, pattern *regexp.Regexp) string {
+	currentVersion, hasCurrent := ParseVersion(current)
+	latest := ""
+	var latestVersion Version
+	for _, tag := range tags {
+		if pattern != nil && !pattern.MatchString(tag) {
+			continue
+		}
+		version, ok := ParseVersion(tag)
+		if !ok {
+			continue
+		}
+		if hasCurrent && !currentVersion.Less(version) {
+			continue
+		}
+		if latest == "" || latestVersion.Less(version) {
+			latest, latestVersion = tag, version
+		}
+	}
+	return latest
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// APIToken 通过接口创建的访问令牌，只保存令牌的 SHA-256 摘要，明文仅在创建时返回一次
+type APIToken struct {
+	ID         int64      `xorm:"pk autoincr 'id'"`
+	Name       string     `xorm:"varchar(128) notnull 'name'"`
+	Role       string     `xorm:"varchar(32) notnull 'role'"`
+	Hash       string     `xorm:"varchar(64) notnull unique 'hash'"`
+	Prefix     string     `xorm:"varchar(16) 'prefix'"` // 明文前几位，便于辨认
+	Services   []string   `xorm:"json 'services'"`      // 可操作的服务，与 Namespaces 都为空表示不限制
+	Namespaces []string   `xorm:"json 'namespaces'"`    // 可操作的命名空间
+	Actor      string     `xorm:"varchar(128) 'actor'"`
+	ExpiresAt  *time.Time `xorm:"'expires_at'"`
+	RevokedAt  *time.Time `xorm:"'revoked_at'"`
+	LastUsedAt *time.Time `xorm:"'last_used_at'"`
+	CreatedAt  time.Time  `xorm:"created 'created_at'"`
+}
+
+// TableName 表名
+func (APIToken) TableName() string {
+	return "api_token"
+}
+
+// AddAPIToken 保存令牌
+func (s *Store) AddAPIToken(token *APIToken) error {
+	if _, err := s.engine.Insert(token); err != nil {
+		return fmt.Errorf("failed to insert api token: %w", err)
+	}
+	return nil
+}
+
+// GetAPIToken 按 ID 获取令牌，不存在时返回 nil
+func (s *Store) GetAPIToken(id int64) (*APIToken, error) {
+	token := new(APIToken)
+	has, err := s.engine.ID(id).Get(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api token: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return token, nil
+}
+
+// GetAPITokenByHash 按摘要获取令牌，不存在时返回 nil
+func (s *Store) GetAPITokenByHash(hash string) (*APIToken, error) {
+	token := new(APIToken)
+	has, err := s.engine.Where("hash = ?", hash).Get(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api token: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return token, nil
+}
+
+// ListAPITokens 列出全部令牌
+func (s *Store) ListAPITokens() ([]*APIToken, error) {
+	tokens := make([]*APIToken, 0)
+	if err := s.engine.Asc("id").Find(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// UpdateAPIToken 更新令牌的过期、吊销和最近使用时间
+func (s *Store) UpdateAPIToken(token *APIToken) error {
+	if _, err := s.engine.ID(token.ID).Cols("expires_at", "revoked_at", "last_used_at").Update(token); err != nil {
+		return fmt.Errorf("failed to update api token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// AuditLog 管理接口的调用记录，每个修改类请求一条
+type AuditLog struct {
+	ID         int64     `xorm:"pk autoincr 'id'"`
+	Actor      string    `xorm:"varchar(255) index 'actor'"`
+	TokenID    string    `xorm:"varchar(64) 'token_id'"`
+	RequestID  string    `xorm:"varchar(128) index 'request_id'"`
+	ClientIP   string    `xorm:"varchar(64) 'client_ip'"`
+	Method     string    `xorm:"varchar(8) 'method'"`
+	Path       string    `xorm:"varchar(255) 'path'"`
+	Service    string    `xorm:"varchar(128) index 'service'"`
+	Body       string    `xorm:"text 'body'"`
+	Status     int       `xorm:"'status'"`
+	Code       int       `xorm:"'code'"`
+	Error      string    `xorm:"text 'error'"`
+	DurationMs int64     `xorm:"'duration_ms'"`
+	CreatedAt  time.Time `xorm:"created index 'created_at'"`
+}
+
+// TableName 表名
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// AuditQuery 审计日志查询条件，零值表示不限制
+type AuditQuery struct {
+	Service   string
+	Actor     string
+	Method    string
+	RequestID string
+	Failed    bool // 只查询失败的请求
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+	Offset    int
+}
+
+// AddAuditLog 追加一条审计日志，并删除 retention 之前的记录（retention 为 0 时不清理）
+func (s *Store) AddAuditLog(entry *AuditLog, retention time.Duration) error {
+	if _, err := s.engine.Insert(entry); err != nil {
+		return fmt.Errorf("failed to insert audit log: %w", err)
+	}
+	if retention > 0 {
+		if _, err := s.engine.Where("created_at < ?", s.timeParam(time.Now().Add(-retention))).Delete(new(AuditLog)); err != nil {
+			return fmt.Errorf("failed to prune audit logs: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListAuditLogs 按时间倒序查询审计日志，同时返回符合条件的总数
+func (s *Store) ListAuditLogs(query AuditQuery) ([]*AuditLog, int64, error) {
+	session := s.engine.NewSession()
+	defer session.Close()
+
+	if query.Service != "" {
+		session.And("service = ?", query.Service)
+	}
+	if query.Actor != "" {
+		session.And("actor = ?", query.Actor)
+	}
+	if query.Method != "" {
+		session.And("method = ?", query.Method)
+	}
+	if query.RequestID != "" {
+		session.And("request_id = ?", query.RequestID)
+	}
+	if query.Failed {
+		session.And("(code <> 0 OR status >= 400)")
+	}
+	if !query.Since.IsZero() {
+		session.And("created_at >= ?", s.timeParam(query.Since))
+	}
+	if !query.Until.IsZero() {
+		session.And("created_at <= ?", s.timeParam(query.Until))
+	}
+
+	logs := make([]*AuditLog, 0)
+	total, err := session.Desc("id").Limit(query.Limit, query.Offset).FindAndCount(&logs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	return logs, total, nil
+}
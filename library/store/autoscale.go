@@ -0,0 +1,91 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// AutoscalePolicy 自动扩缩容策略，每个服务一条
+type AutoscalePolicy struct {
+	ID                int64     `xorm:"pk autoincr 'id'"`
+	Service           string    `xorm:"varchar(128) notnull unique 'service'"`
+	MinReplicas       int       `xorm:"'min_replicas'"`
+	MaxReplicas       int       `xorm:"'max_replicas'"`
+	TargetCPU         int       `xorm:"'target_cpu'"`
+	TargetMemory      int       `xorm:"'target_memory'"`
+	TargetRPS         int       `xorm:"'target_rps'"`
+	TargetConnections int       `xorm:"'target_connections'"`
+	ScaleUpCooldown   int       `xorm:"'scale_up_cooldown'"`
+	ScaleDownCooldown int       `xorm:"'scale_down_cooldown'"`
+	LastScaleAt       time.Time `xorm:"'last_scale_at'"`
+	CreatedAt         time.Time `xorm:"created 'created_at'"`
+	UpdatedAt         time.Time `xorm:"updated 'updated_at'"`
+}
+
+// TableName 表名
+func (AutoscalePolicy) TableName() string {
+	return "autoscale_policy"
+}
+
+// SaveAutoscalePolicy 保存自动扩缩容策略，已存在时覆盖（保留上次扩缩容时间）
+func (s *Store) SaveAutoscalePolicy(policy *AutoscalePolicy) error {
+	existing := new(AutoscalePolicy)
+	has, err := s.engine.Where("service = ?", policy.Service).Get(existing)
+	if err != nil {
+		return fmt.Errorf("failed to query autoscale policy: %w", err)
+	}
+
+	if !has {
+		if _, err := s.engine.Insert(policy); err != nil {
+			return fmt.Errorf("failed to insert autoscale policy: %w", err)
+		}
+		return nil
+	}
+
+	policy.ID = existing.ID
+	policy.CreatedAt = existing.CreatedAt
+	policy.LastScaleAt = existing.LastScaleAt
+	if _, err := s.engine.ID(existing.ID).AllCols().Update(policy); err != nil {
+		return fmt.Errorf("failed to update autoscale policy: %w", err)
+	}
+	return nil
+}
+
+// GetAutoscalePolicy 获取服务的自动扩缩容策略，不存在时返回 nil
+func (s *Store) GetAutoscalePolicy(service string) (*AutoscalePolicy, error) {
+	policy := new(AutoscalePolicy)
+	has, err := s.engine.Where("service = ?", service).Get(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query autoscale policy: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// ListAutoscalePolicies 列出全部自动扩缩容策略
+func (s *Store) ListAutoscalePolicies() ([]*AutoscalePolicy, error) {
+	policies := make([]*AutoscalePolicy, 0)
+	if err := s.engine.Asc("service").Find(&policies); err != nil {
+		return nil, fmt.Errorf("failed to list autoscale policies: %w", err)
+	}
+	return policies, nil
+}
+
+// UpdateAutoscaleLastScale 记录自动扩缩容时间，用于冷却判断
+func (s *Store) UpdateAutoscaleLastScale(service string, at time.Time) error {
+	if _, err := s.engine.Where("service = ?", service).Cols("last_scale_at").Update(&AutoscalePolicy{LastScaleAt: at}); err != nil {
+		return fmt.Errorf("failed to update autoscale policy: %w", err)
+	}
+	return nil
+}
+
+// DeleteAutoscalePolicy 删除服务的自动扩缩容策略，返回策略是否存在
+func (s *Store) DeleteAutoscalePolicy(service string) (bool, error) {
+	affected, err := s.engine.Where("service = ?", service).Delete(new(AutoscalePolicy))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete autoscale policy: %w", err)
+	}
+	return affected > 0, nil
+}
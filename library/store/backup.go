@@ -0,0 +1,118 @@
+package store
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+// Snapshot 状态存储快照，用于整机备份与恢复
+// 不包含一次性任务记录、进行中的发布和回收站，这些数据依赖原主机上的容器
+type Snapshot struct {
+	ServiceSpecs      []*ServiceSpec     `json:"service_specs"`
+	Revisions         []*Revision        `json:"revisions"`
+	Ports             []*PortRecord      `json:"ports"`
+	Secrets           []*Secret          `json:"secrets"` // Value 为 secret.key 加密后的密文
+	Templates         []*Template        `json:"templates"`
+	ScaleSchedules    []*ScaleSchedule   `json:"scale_schedules"`
+	AutoscalePolicies []*AutoscalePolicy `json:"autoscale_policies"`
+	CronJobs          []*CronJob         `json:"cron_jobs"`
+	ServiceLocks      []*ServiceLock     `json:"service_locks"`
+	ImageWatches      []*ImageWatch      `json:"image_watches"`
+	GitOpsServices    []*GitOpsService   `json:"gitops_services"`
+	Webhooks          []*Webhook         `json:"webhooks"` // Secret 为 secret.key 加密后的密文
+}
+
+// tableRows 快照中的一张表及其记录
+type tableRows struct {
+	table string
+	rows  []interface{}
+}
+
+// toRows 将记录切片转换为 []interface{}，便于按表统一写入
+func toRows[T any](table string, items []*T) tableRows {
+	rows := make([]interface{}, len(items))
+	for i, item := range items {
+		rows[i] = item
+	}
+	return tableRows{table: table, rows: rows}
+}
+
+// tables 快照涉及的表，恢复时按此顺序写入
+func (snapshot *Snapshot) tables() []tableRows {
+	return []tableRows{
+		toRows("service_spec", snapshot.ServiceSpecs),
+		toRows("service_revision", snapshot.Revisions),
+		toRows("public_port", snapshot.Ports),
+		toRows("secret", snapshot.Secrets),
+		toRows("service_template", snapshot.Templates),
+		toRows("scale_schedule", snapshot.ScaleSchedules),
+		toRows("autoscale_policy", snapshot.AutoscalePolicies),
+		toRows("cron_job", snapshot.CronJobs),
+		toRows("service_lock", snapshot.ServiceLocks),
+		toRows("image_watch", snapshot.ImageWatches),
+		toRows("gitops_service", snapshot.GitOpsServices),
+		toRows("webhook", snapshot.Webhooks),
+	}
+}
+
+// ExportSnapshot 读取全部可备份的状态
+func (s *Store) ExportSnapshot() (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	finds := []struct {
+		table string
+		dest  interface{}
+	}{
+		{"service_spec", &snapshot.ServiceSpecs},
+		{"service_revision", &snapshot.Revisions},
+		{"public_port", &snapshot.Ports},
+		{"secret", &snapshot.Secrets},
+		{"service_template", &snapshot.Templates},
+		{"scale_schedule", &snapshot.ScaleSchedules},
+		{"autoscale_policy", &snapshot.AutoscalePolicies},
+		{"cron_job", &snapshot.CronJobs},
+		{"service_lock", &snapshot.ServiceLocks},
+		{"image_watch", &snapshot.ImageWatches},
+		{"gitops_service", &snapshot.GitOpsServices},
+		{"webhook", &snapshot.Webhooks},
+	}
+	for _, find := range finds {
+		if err := s.engine.Asc("id").Find(find.dest); err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", find.table, err)
+		}
+	}
+	return snapshot, nil
+}
+
+// RestoreSnapshot 在一个事务中用快照替换全部可备份的状态，保留记录原有的 ID 和时间
+func (s *Store) RestoreSnapshot(snapshot *Snapshot) error {
+	session := s.engine.NewSession()
+	defer session.Close()
+
+	if err := session.Begin(); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	for _, table := range snapshot.tables() {
+		if err := replaceRows(session, table); err != nil {
+			session.Rollback()
+			return err
+		}
+	}
+	if err := session.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// replaceRows 清空表并写入快照中的记录
+func replaceRows(session *xorm.Session, table tableRows) error {
+	if _, err := session.Exec("DELETE FROM " + table.table); err != nil {
+		return fmt.Errorf("failed to clear %s: %w", table.table, err)
+	}
+	for _, row := range table.rows {
+		if _, err := session.NoAutoTime().Insert(row); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", table.table, err)
+		}
+	}
+	return nil
+}
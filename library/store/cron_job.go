@@ -0,0 +1,95 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// CronJob 定时任务：按 cron 表达式周期性运行一次性任务
+// Spec 为任务请求（JSON）
+type CronJob struct {
+	ID                int64     `xorm:"pk autoincr 'id'"`
+	Name              string    `xorm:"varchar(128) notnull unique 'name'"`
+	Cron              string    `xorm:"varchar(128) notnull 'cron'"`
+	ConcurrencyPolicy string    `xorm:"varchar(32) 'concurrency_policy'"`
+	HistoryLimit      int       `xorm:"'history_limit'"`
+	Suspended         bool      `xorm:"'suspended'"`
+	Spec              string    `xorm:"text 'spec'"`
+	LastRunAt         time.Time `xorm:"'last_run_at'"`
+	LastJobID         string    `xorm:"varchar(64) 'last_job_id'"`
+	LastError         string    `xorm:"varchar(512) 'last_error'"`
+	CreatedAt         time.Time `xorm:"created 'created_at'"`
+	UpdatedAt         time.Time `xorm:"updated 'updated_at'"`
+}
+
+// TableName 表名
+func (CronJob) TableName() string {
+	return "cron_job"
+}
+
+// SaveCronJob 保存定时任务，同名定时任务已存在时覆盖配置并保留最近一次运行记录
+func (s *Store) SaveCronJob(job *CronJob) error {
+	existing := new(CronJob)
+	has, err := s.engine.Where("name = ?", job.Name).Get(existing)
+	if err != nil {
+		return fmt.Errorf("failed to query cron job: %w", err)
+	}
+
+	if !has {
+		if _, err := s.engine.Insert(job); err != nil {
+			return fmt.Errorf("failed to insert cron job: %w", err)
+		}
+		return nil
+	}
+
+	job.ID = existing.ID
+	job.CreatedAt = existing.CreatedAt
+	job.LastRunAt = existing.LastRunAt
+	job.LastJobID = existing.LastJobID
+	job.LastError = existing.LastError
+	if _, err := s.engine.ID(existing.ID).AllCols().Update(job); err != nil {
+		return fmt.Errorf("failed to update cron job: %w", err)
+	}
+	return nil
+}
+
+// GetCronJob 获取定时任务，不存在时返回 nil
+func (s *Store) GetCronJob(name string) (*CronJob, error) {
+	job := new(CronJob)
+	has, err := s.engine.Where("name = ?", name).Get(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cron job: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return job, nil
+}
+
+// ListCronJobs 列出全部定时任务
+func (s *Store) ListCronJobs() ([]*CronJob, error) {
+	jobs := make([]*CronJob, 0)
+	if err := s.engine.OrderBy("name").Find(&jobs); err != nil {
+		return nil, fmt.Errorf("failed to list cron jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// UpdateCronJobRun 记录定时任务最近一次触发的时间、任务ID和错误
+func (s *Store) UpdateCronJobRun(name string, at time.Time, jobID, lastError string) error {
+	_, err := s.engine.Where("name = ?", name).Cols("last_run_at", "last_job_id", "last_error").
+		Update(&CronJob{LastRunAt: at, LastJobID: jobID, LastError: lastError})
+	if err != nil {
+		return fmt.Errorf("failed to update cron job run: %w", err)
+	}
+	return nil
+}
+
+// DeleteCronJob 删除定时任务，返回是否存在
+func (s *Store) DeleteCronJob(name string) (bool, error) {
+	affected, err := s.engine.Where("name = ?", name).Delete(new(CronJob))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete cron job: %w", err)
+	}
+	return affected > 0, nil
+}
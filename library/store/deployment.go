@@ -0,0 +1,100 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Deployment 异步部署的进度记录
+// Step 为当前阶段，Current/Total 为副本进度；Spec 为部署请求（JSON）
+type Deployment struct {
+	ID           int64     `xorm:"pk autoincr 'id'"`
+	DeploymentID string    `xorm:"varchar(64) notnull unique 'deployment_id'"`
+	Service      string    `xorm:"varchar(128) index 'service'"`
+	Action       string    `xorm:"varchar(32) 'action'"`
+	Image        string    `xorm:"varchar(256) 'image'"`
+	Spec         string    `xorm:"text 'spec'"`
+	Status       string    `xorm:"varchar(32) index 'status'"`
+	Step         string    `xorm:"varchar(32) 'step'"`
+	Message      string    `xorm:"text 'message'"`
+	Current      int       `xorm:"'current'"`
+	Total        int       `xorm:"'total'"`
+	Error        string    `xorm:"text 'error'"`
+	Actor        string    `xorm:"varchar(128) 'actor'"`
+	StartedAt    time.Time `xorm:"'started_at'"`
+	FinishedAt   time.Time `xorm:"'finished_at'"`
+	CreatedAt    time.Time `xorm:"created 'created_at'"`
+}
+
+// TableName 表名
+func (Deployment) TableName() string {
+	return "deployment"
+}
+
+// AddDeployment 新增部署记录，并只保留最近 limit 条（limit 为 0 表示不清理）
+func (s *Store) AddDeployment(deployment *Deployment, limit int) error {
+	if _, err := s.engine.Insert(deployment); err != nil {
+		return fmt.Errorf("failed to insert deployment: %w", err)
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	var ids []int64
+	if err := s.engine.Table(new(Deployment)).Desc("id").Limit(1, limit).Cols("id").Find(&ids); err != nil {
+		return fmt.Errorf("failed to query expired deployments: %w", err)
+	}
+	if len(ids) > 0 {
+		if _, err := s.engine.Where("id <= ?", ids[0]).Delete(new(Deployment)); err != nil {
+			return fmt.Errorf("failed to delete expired deployments: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateDeployment 更新部署记录的全部字段
+func (s *Store) UpdateDeployment(deployment *Deployment) error {
+	if _, err := s.engine.ID(deployment.ID).AllCols().Update(deployment); err != nil {
+		return fmt.Errorf("failed to update deployment: %w", err)
+	}
+	return nil
+}
+
+// GetDeployment 获取部署记录，不存在时返回 nil
+func (s *Store) GetDeployment(deploymentID string) (*Deployment, error) {
+	deployment := new(Deployment)
+	has, err := s.engine.Where("deployment_id = ?", deploymentID).Get(deployment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deployment: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return deployment, nil
+}
+
+// ListDeployments 按创建时间倒序列出部署记录，service 不为空时只列出该服务的记录，limit 为 0 表示不限制
+func (s *Store) ListDeployments(service string, limit int) ([]*Deployment, error) {
+	deployments := make([]*Deployment, 0)
+	session := s.engine.Desc("id")
+	if service != "" {
+		session = session.Where("service = ?", service)
+	}
+	if limit > 0 {
+		session = session.Limit(limit)
+	}
+	if err := session.Find(&deployments); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	return deployments, nil
+}
+
+// FailUnfinishedDeployments 将未结束的部署标记为失败，用于进程重启后清理中断的部署
+func (s *Store) FailUnfinishedDeployments(unfinished []string, status, reason string) (int64, error) {
+	affected, err := s.engine.In("status", unfinished).Cols("status", "error", "finished_at").
+		Update(&Deployment{Status: status, Error: reason, FinishedAt: time.Now()})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update unfinished deployments: %w", err)
+	}
+	return affected, nil
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event 服务生命周期事件（部署、扩缩容、副本崩溃、更新失败、代理重启等）
+type Event struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	Service   string    `xorm:"varchar(128) index 'service'"`
+	Type      string    `xorm:"varchar(32) index 'type'"`
+	Message   string    `xorm:"text 'message'"`
+	Actor     string    `xorm:"varchar(255) 'actor'"`
+	CreatedAt time.Time `xorm:"created index 'created_at'"`
+}
+
+// TableName 表名
+func (Event) TableName() string {
+	return "service_event"
+}
+
+// EventQuery 事件查询条件，零值表示不限制
+type EventQuery struct {
+	Service string
+	Type    string
+	Types   []string // 多个事件类型，与 Type 同时指定时都需满足
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+	Offset  int
+}
+
+// AddEvent 追加一条事件，并删除 retention 之前的事件（retention 为 0 时不清理）
+func (s *Store) AddEvent(event *Event, retention time.Duration) error {
+	if _, err := s.engine.Insert(event); err != nil {
+		return fmt.Errorf("failed to insert event: %w", err)
+	}
+	if retention > 0 {
+		if _, err := s.engine.Where("created_at < ?", s.timeParam(time.Now().Add(-retention))).Delete(new(Event)); err != nil {
+			return fmt.Errorf("failed to prune events: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListEvents 按时间倒序查询事件，同时返回符合条件的总数
+func (s *Store) ListEvents(query EventQuery) ([]*Event, int64, error) {
+	session := s.engine.NewSession()
+	defer session.Close()
+
+	if query.Service != "" {
+		session.And("service = ?", query.Service)
+	}
+	if query.Type != "" {
+		session.And("type = ?", query.Type)
+	}
+	if len(query.Types) > 0 {
+		session.In("type", query.Types)
+	}
+	if !query.Since.IsZero() {
+		session.And("created_at >= ?", s.timeParam(query.Since))
+	}
+	if !query.Until.IsZero() {
+		session.And("created_at <= ?", s.timeParam(query.Until))
+	}
+
+	events := make([]*Event, 0)
+	total, err := session.Desc("id").Limit(query.Limit, query.Offset).FindAndCount(&events)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events, total, nil
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// GitOpsService 由 GitOps 同步创建或接管的服务，用于判断仓库中删除的服务是否应被清理
+type GitOpsService struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	Service   string    `xorm:"varchar(128) notnull unique 'service'"`
+	File      string    `xorm:"varchar(255) 'file'"`  // 服务配置在仓库中的路径
+	Commit    string    `xorm:"varchar(64) 'commit'"` // 最近一次同步的提交
+	CreatedAt time.Time `xorm:"created 'created_at'"`
+	UpdatedAt time.Time `xorm:"updated 'updated_at'"`
+}
+
+// TableName 表名
+func (GitOpsService) TableName() string {
+	return "gitops_service"
+}
+
+// SaveGitOpsService 记录服务由 GitOps 管理，已存在时更新配置文件和提交
+func (s *Store) SaveGitOpsService(service, file, commit string) error {
+	existing := new(GitOpsService)
+	has, err := s.engine.Where("service = ?", service).Get(existing)
+	if err != nil {
+		return fmt.Errorf("failed to query gitops service: %w", err)
+	}
+
+	if !has {
+		if _, err := s.engine.Insert(&GitOpsService{Service: service, File: file, Commit: commit}); err != nil {
+			return fmt.Errorf("failed to insert gitops service: %w", err)
+		}
+		return nil
+	}
+
+	existing.File = file
+	existing.Commit = commit
+	if _, err := s.engine.ID(existing.ID).Cols("file", "commit").Update(existing); err != nil {
+		return fmt.Errorf("failed to update gitops service: %w", err)
+	}
+	return nil
+}
+
+// ListGitOpsServices 列出由 GitOps 管理的服务
+func (s *Store) ListGitOpsServices() ([]*GitOpsService, error) {
+	services := make([]*GitOpsService, 0)
+	if err := s.engine.Asc("service").Find(&services); err != nil {
+		return nil, fmt.Errorf("failed to list gitops services: %w", err)
+	}
+	return services, nil
+}
+
+// DeleteGitOpsService 取消服务的 GitOps 管理记录
+func (s *Store) DeleteGitOpsService(service string) error {
+	if _, err := s.engine.Where("service = ?", service).Delete(new(GitOpsService)); err != nil {
+		return fmt.Errorf("failed to delete gitops service: %w", err)
+	}
+	return nil
+}
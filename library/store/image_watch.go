@@ -0,0 +1,95 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// ImageWatch 镜像更新监视，每个服务一条
+// Mode 为 digest 时监视当前标签的摘要变化，为 semver 时监视匹配 Pattern 的更新版本标签
+type ImageWatch struct {
+	ID              int64     `xorm:"pk autoincr 'id'"`
+	Service         string    `xorm:"varchar(128) notnull unique 'service'"`
+	Mode            string    `xorm:"varchar(16) 'mode'"`
+	Pattern         string    `xorm:"varchar(255) 'pattern'"`
+	Action          string    `xorm:"varchar(16) 'action'"`
+	LatestTag       string    `xorm:"varchar(128) 'latest_tag'"`
+	LatestDigest    string    `xorm:"varchar(128) 'latest_digest'"`
+	UpdateAvailable bool      `xorm:"'update_available'"`
+	LastError       string    `xorm:"text 'last_error'"`
+	LastCheckAt     time.Time `xorm:"'last_check_at'"`
+	LastUpdateAt    time.Time `xorm:"'last_update_at'"`
+	CreatedAt       time.Time `xorm:"created 'created_at'"`
+	UpdatedAt       time.Time `xorm:"updated 'updated_at'"`
+}
+
+// TableName 表名
+func (ImageWatch) TableName() string {
+	return "image_watch"
+}
+
+// SaveImageWatch 保存镜像更新监视，已存在时覆盖配置并清空检查结果
+func (s *Store) SaveImageWatch(watch *ImageWatch) error {
+	existing := new(ImageWatch)
+	has, err := s.engine.Where("service = ?", watch.Service).Get(existing)
+	if err != nil {
+		return fmt.Errorf("failed to query image watch: %w", err)
+	}
+
+	if !has {
+		if _, err := s.engine.Insert(watch); err != nil {
+			return fmt.Errorf("failed to insert image watch: %w", err)
+		}
+		return nil
+	}
+
+	watch.ID = existing.ID
+	watch.CreatedAt = existing.CreatedAt
+	watch.LastUpdateAt = existing.LastUpdateAt
+	if _, err := s.engine.ID(existing.ID).AllCols().Update(watch); err != nil {
+		return fmt.Errorf("failed to update image watch: %w", err)
+	}
+	return nil
+}
+
+// GetImageWatch 获取服务的镜像更新监视，不存在时返回 nil
+func (s *Store) GetImageWatch(service string) (*ImageWatch, error) {
+	watch := new(ImageWatch)
+	has, err := s.engine.Where("service = ?", service).Get(watch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query image watch: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return watch, nil
+}
+
+// ListImageWatches 列出全部镜像更新监视
+func (s *Store) ListImageWatches() ([]*ImageWatch, error) {
+	watches := make([]*ImageWatch, 0)
+	if err := s.engine.Asc("service").Find(&watches); err != nil {
+		return nil, fmt.Errorf("failed to list image watches: %w", err)
+	}
+	return watches, nil
+}
+
+// UpdateImageWatchResult 记录一次检查的结果
+func (s *Store) UpdateImageWatchResult(watch *ImageWatch) error {
+	_, err := s.engine.ID(watch.ID).
+		Cols("latest_tag", "latest_digest", "update_available", "last_error", "last_check_at", "last_update_at").
+		Update(watch)
+	if err != nil {
+		return fmt.Errorf("failed to update image watch: %w", err)
+	}
+	return nil
+}
+
+// DeleteImageWatch 删除服务的镜像更新监视，返回是否存在
+func (s *Store) DeleteImageWatch(service string) (bool, error) {
+	affected, err := s.engine.Where("service = ?", service).Delete(new(ImageWatch))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete image watch: %w", err)
+	}
+	return affected > 0, nil
+}
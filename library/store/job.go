@@ -0,0 +1,115 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Job 一次性任务的运行记录
+// Spec 为任务请求（JSON），Logs 只保留末尾部分；CronJob 为触发该任务的定时任务，手动提交时为空
+type Job struct {
+	ID          int64     `xorm:"pk autoincr 'id'"`
+	JobID       string    `xorm:"varchar(64) notnull unique 'job_id'"`
+	Name        string    `xorm:"varchar(128) 'name'"`
+	CronJob     string    `xorm:"varchar(128) index 'cron_job'"`
+	Image       string    `xorm:"varchar(256) 'image'"`
+	Spec        string    `xorm:"text 'spec'"`
+	Status      string    `xorm:"varchar(32) index 'status'"`
+	ExitCode    *int64    `xorm:"'exit_code'"`
+	Logs        string    `xorm:"text 'logs'"`
+	Error       string    `xorm:"text 'error'"`
+	ContainerID string    `xorm:"varchar(64) 'container_id'"`
+	StartedAt   time.Time `xorm:"'started_at'"`
+	FinishedAt  time.Time `xorm:"'finished_at'"`
+	CreatedAt   time.Time `xorm:"created 'created_at'"`
+}
+
+// TableName 表名
+func (Job) TableName() string {
+	return "job"
+}
+
+// AddJob 新增任务记录，并只保留同一定时任务（或全部手动任务）最近 limit 条（limit 为 0 表示不清理）
+func (s *Store) AddJob(job *Job, limit int) error {
+	if _, err := s.engine.Insert(job); err != nil {
+		return fmt.Errorf("failed to insert job: %w", err)
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	var ids []int64
+	if err := s.engine.Table(new(Job)).Where("cron_job = ?", job.CronJob).Desc("id").Limit(1, limit).Cols("id").Find(&ids); err != nil {
+		return fmt.Errorf("failed to query expired jobs: %w", err)
+	}
+	if len(ids) > 0 {
+		if _, err := s.engine.Where("cron_job = ? AND id <= ?", job.CronJob, ids[0]).Delete(new(Job)); err != nil {
+			return fmt.Errorf("failed to delete expired jobs: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateJob 更新任务记录的全部字段
+func (s *Store) UpdateJob(job *Job) error {
+	if _, err := s.engine.ID(job.ID).AllCols().Update(job); err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	return nil
+}
+
+// GetJob 获取任务记录，不存在时返回 nil
+func (s *Store) GetJob(jobID string) (*Job, error) {
+	job := new(Job)
+	has, err := s.engine.Where("job_id = ?", jobID).Get(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return job, nil
+}
+
+// ListJobs 按创建时间倒序列出任务记录，cronJob 不为空时只列出该定时任务触发的记录，limit 为 0 表示不限制
+func (s *Store) ListJobs(cronJob string, limit int) ([]*Job, error) {
+	jobs := make([]*Job, 0)
+	session := s.engine.Desc("id")
+	if cronJob != "" {
+		session = session.Where("cron_job = ?", cronJob)
+	}
+	if limit > 0 {
+		session = session.Limit(limit)
+	}
+	if err := session.Find(&jobs); err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// DeleteJob 删除任务记录，返回是否存在
+func (s *Store) DeleteJob(jobID string) (bool, error) {
+	affected, err := s.engine.Where("job_id = ?", jobID).Delete(new(Job))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete job: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// DeleteJobs 删除定时任务触发的全部任务记录
+func (s *Store) DeleteJobs(cronJob string) error {
+	if _, err := s.engine.Where("cron_job = ?", cronJob).Delete(new(Job)); err != nil {
+		return fmt.Errorf("failed to delete jobs of cron job %s: %w", cronJob, err)
+	}
+	return nil
+}
+
+// FailUnfinishedJobs 将未结束的任务标记为失败，用于进程重启后清理中断的任务
+func (s *Store) FailUnfinishedJobs(unfinished []string, status, reason string) (int64, error) {
+	affected, err := s.engine.In("status", unfinished).Cols("status", "error", "finished_at").
+		Update(&Job{Status: status, Error: reason, FinishedAt: time.Now()})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update unfinished jobs: %w", err)
+	}
+	return affected, nil
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// PortRecord 公共端口登记：已分配给服务（Reserved 为 false），或预留（Reserved 为 true，Service 为空表示不限定服务）
+type PortRecord struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	Port      int       `xorm:"notnull unique 'port'"`
+	Service   string    `xorm:"varchar(128) index 'service'"`
+	Reserved  bool      `xorm:"'reserved'"`
+	Note      string    `xorm:"varchar(255) 'note'"`
+	Actor     string    `xorm:"varchar(128) 'actor'"`
+	CreatedAt time.Time `xorm:"created 'created_at'"`
+}
+
+// TableName 表名
+func (PortRecord) TableName() string {
+	return "public_port"
+}
+
+// GetPort 获取端口登记，未登记时返回 nil
+func (s *Store) GetPort(port int) (*PortRecord, error) {
+	record := new(PortRecord)
+	has, err := s.engine.Where("port = ?", port).Get(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query public port: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return record, nil
+}
+
+// ListPorts 按端口列出登记，reserved 为 true 时只列出预留
+func (s *Store) ListPorts(reserved bool) ([]*PortRecord, error) {
+	records := make([]*PortRecord, 0)
+	session := s.engine.OrderBy("port")
+	if reserved {
+		session = session.Where("reserved = ?", true)
+	}
+	if err := session.Find(&records); err != nil {
+		return nil, fmt.Errorf("failed to list public ports: %w", err)
+	}
+	return records, nil
+}
+
+// AllocatePort 将端口分配给服务：端口已分配给其他服务或为其他服务预留时返回错误
+// 为该服务预留（或不限定服务）的端口转为已分配
+func (s *Store) AllocatePort(port int, service, actor string) error {
+	existing, err := s.GetPort(port)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		if _, err := s.engine.Insert(&PortRecord{Port: port, Service: service, Actor: actor}); err != nil {
+			return fmt.Errorf("failed to insert public port: %w", err)
+		}
+		return nil
+	}
+	if !existing.Reserved {
+		if existing.Service == service {
+			return nil
+		}
+		return fmt.Errorf("public port %d is allocated to service %s", port, existing.Service)
+	}
+	if existing.Service != "" && existing.Service != service {
+		return fmt.Errorf("public port %d is reserved for service %s", port, existing.Service)
+	}
+	existing.Service = service
+	existing.Reserved = false
+	existing.Actor = actor
+	if _, err := s.engine.ID(existing.ID).Cols("service", "reserved", "actor").Update(existing); err != nil {
+		return fmt.Errorf("failed to update public port: %w", err)
+	}
+	return nil
+}
+
+// ReservePort 预留端口，端口已登记时返回错误
+func (s *Store) ReservePort(record *PortRecord) error {
+	existing, err := s.GetPort(record.Port)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.Reserved {
+			return fmt.Errorf("public port %d is already reserved", record.Port)
+		}
+		return fmt.Errorf("public port %d is allocated to service %s", record.Port, existing.Service)
+	}
+	record.Reserved = true
+	if _, err := s.engine.Insert(record); err != nil {
+		return fmt.Errorf("failed to insert public port: %w", err)
+	}
+	return nil
+}
+
+// ReleasePorts 释放分配给服务的端口，预留不受影响
+func (s *Store) ReleasePorts(service string) error {
+	if _, err := s.engine.Where("service = ? AND reserved = ?", service, false).Delete(new(PortRecord)); err != nil {
+		return fmt.Errorf("failed to release public ports: %w", err)
+	}
+	return nil
+}
+
+// DeletePortReservation 取消端口预留，返回是否存在
+func (s *Store) DeletePortReservation(port int) (bool, error) {
+	affected, err := s.engine.Where("port = ? AND reserved = ?", port, true).Delete(new(PortRecord))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete port reservation: %w", err)
+	}
+	return affected > 0, nil
+}
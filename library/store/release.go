@@ -0,0 +1,71 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Release 进行中的发布（金丝雀或蓝绿），每个服务最多一个；Spec 为新版本的完整服务配置（JSON）
+type Release struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	Service   string    `xorm:"varchar(128) notnull unique 'service'"`
+	Mode      string    `xorm:"varchar(32) 'mode'"`
+	Image     string    `xorm:"varchar(255) 'image'"`
+	Tag       string    `xorm:"varchar(128) 'tag'"`
+	Replicas  int       `xorm:"'replicas'"`
+	Spec      string    `xorm:"text 'spec'"`
+	Promoted  bool      `xorm:"'promoted'"`
+	Actor     string    `xorm:"varchar(128) 'actor'"`
+	CreatedAt time.Time `xorm:"created 'created_at'"`
+}
+
+// TableName 表名
+func (Release) TableName() string {
+	return "release"
+}
+
+// AddRelease 保存发布，同一服务已有进行中的发布时返回错误
+func (s *Store) AddRelease(release *Release) error {
+	existing, err := s.GetRelease(release.Service)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("service %s already has a %s release in progress", release.Service, existing.Mode)
+	}
+	if _, err := s.engine.Insert(release); err != nil {
+		return fmt.Errorf("failed to insert release: %w", err)
+	}
+	return nil
+}
+
+// GetRelease 获取服务进行中的发布，不存在时返回 nil
+func (s *Store) GetRelease(service string) (*Release, error) {
+	release := new(Release)
+	has, err := s.engine.Where("service = ?", service).Get(release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query release: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return release, nil
+}
+
+// MarkReleasePromoted 标记发布已确认，端口代理随即切换到新版本副本
+func (s *Store) MarkReleasePromoted(service string) error {
+	_, err := s.engine.Where("service = ?", service).Cols("promoted").Update(&Release{Promoted: true})
+	if err != nil {
+		return fmt.Errorf("failed to update release: %w", err)
+	}
+	return nil
+}
+
+// DeleteRelease 删除服务的发布记录，返回是否存在
+func (s *Store) DeleteRelease(service string) (bool, error) {
+	affected, err := s.engine.Where("service = ?", service).Delete(new(Release))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete release: %w", err)
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Revision 部署历史记录
+type Revision struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	Service   string    `xorm:"varchar(128) notnull index 'service'"`
+	Revision  int       `xorm:"notnull 'revision'"`
+	Action    string    `xorm:"varchar(32) 'action'"`
+	Actor     string    `xorm:"varchar(255) 'actor'"`
+	Image     string    `xorm:"varchar(255) 'image'"`
+	Note      string    `xorm:"varchar(255) 'note'"`
+	Spec      string    `xorm:"text 'spec'"`
+	CreatedAt time.Time `xorm:"created 'created_at'"`
+}
+
+// TableName 表名
+func (Revision) TableName() string {
+	return "service_revision"
+}
+
+// AddRevision 追加一条部署历史，自动分配递增的版本号，并只保留最近 limit 条
+func (s *Store) AddRevision(rev *Revision, limit int) error {
+	session := s.engine.NewSession()
+	defer session.Close()
+
+	if err := session.Begin(); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	last := new(Revision)
+	has, err := session.Where("service = ?", rev.Service).Desc("revision").Get(last)
+	if err != nil {
+		session.Rollback()
+		return fmt.Errorf("failed to query last revision: %w", err)
+	}
+	rev.Revision = 1
+	if has {
+		rev.Revision = last.Revision + 1
+	}
+
+	if _, err := session.Insert(rev); err != nil {
+		session.Rollback()
+		return fmt.Errorf("failed to insert revision: %w", err)
+	}
+
+	if limit > 0 {
+		if _, err := session.Where("service = ? AND revision <= ?", rev.Service, rev.Revision-limit).Delete(new(Revision)); err != nil {
+			session.Rollback()
+			return fmt.Errorf("failed to prune revisions: %w", err)
+		}
+	}
+
+	return session.Commit()
+}
+
+// ListRevisions 按版本号倒序列出服务的部署历史
+func (s *Store) ListRevisions(service string) ([]*Revision, error) {
+	revisions := make([]*Revision, 0)
+	if err := s.engine.Where("service = ?", service).Desc("revision").Find(&revisions); err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetRevision 获取指定版本，不存在时返回 nil
+func (s *Store) GetRevision(service string, revision int) (*Revision, error) {
+	rev := new(Revision)
+	has, err := s.engine.Where("service = ? AND revision = ?", service, revision).Get(rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revision: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return rev, nil
+}
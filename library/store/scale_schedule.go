@@ -0,0 +1,70 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScaleSchedule 定时扩缩容规则
+type ScaleSchedule struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	Service   string    `xorm:"varchar(128) notnull index 'service'"`
+	Cron      string    `xorm:"varchar(128) notnull 'cron'"`
+	Replicas  int       `xorm:"'replicas'"`
+	Note      string    `xorm:"varchar(255) 'note'"`
+	LastRunAt time.Time `xorm:"'last_run_at'"`
+	LastError string    `xorm:"varchar(512) 'last_error'"`
+	CreatedAt time.Time `xorm:"created 'created_at'"`
+}
+
+// TableName 表名
+func (ScaleSchedule) TableName() string {
+	return "scale_schedule"
+}
+
+// AddScaleSchedule 添加定时扩缩容规则
+func (s *Store) AddScaleSchedule(schedule *ScaleSchedule) error {
+	if _, err := s.engine.Insert(schedule); err != nil {
+		return fmt.Errorf("failed to insert scale schedule: %w", err)
+	}
+	return nil
+}
+
+// ListScaleSchedules 列出定时扩缩容规则，service 为空时列出全部
+func (s *Store) ListScaleSchedules(service string) ([]*ScaleSchedule, error) {
+	schedules := make([]*ScaleSchedule, 0)
+	session := s.engine.Asc("service", "id")
+	if service != "" {
+		session = session.Where("service = ?", service)
+	}
+	if err := session.Find(&schedules); err != nil {
+		return nil, fmt.Errorf("failed to list scale schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// UpdateScaleScheduleRun 记录规则的执行时间和结果
+func (s *Store) UpdateScaleScheduleRun(id int64, runAt time.Time, lastError string) error {
+	_, err := s.engine.ID(id).Cols("last_run_at", "last_error").Update(&ScaleSchedule{LastRunAt: runAt, LastError: lastError})
+	if err != nil {
+		return fmt.Errorf("failed to update scale schedule: %w", err)
+	}
+	return nil
+}
+
+// DeleteScaleSchedule 删除服务的指定规则，返回规则是否存在
+func (s *Store) DeleteScaleSchedule(service string, id int64) (bool, error) {
+	affected, err := s.engine.Where("service = ? AND id = ?", service, id).Delete(new(ScaleSchedule))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete scale schedule: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// DeleteScaleSchedules 删除服务的全部规则（服务删除时调用）
+func (s *Store) DeleteScaleSchedules(service string) error {
+	if _, err := s.engine.Where("service = ?", service).Delete(new(ScaleSchedule)); err != nil {
+		return fmt.Errorf("failed to delete scale schedules: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Secret 密钥，Value 为加密后的密文（base64），明文不落盘
+type Secret struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	Name      string    `xorm:"varchar(128) notnull unique 'name'"`
+	Value     string    `xorm:"text 'value'"`
+	CreatedAt time.Time `xorm:"created 'created_at'"`
+	UpdatedAt time.Time `xorm:"updated 'updated_at'"`
+}
+
+// TableName 表名
+func (Secret) TableName() string {
+	return "secret"
+}
+
+// SaveSecret 保存密钥，同名密钥已存在时覆盖
+func (s *Store) SaveSecret(secret *Secret) error {
+	existing := new(Secret)
+	has, err := s.engine.Where("name = ?", secret.Name).Get(existing)
+	if err != nil {
+		return fmt.Errorf("failed to query secret: %w", err)
+	}
+
+	if !has {
+		if _, err := s.engine.Insert(secret); err != nil {
+			return fmt.Errorf("failed to insert secret: %w", err)
+		}
+		return nil
+	}
+
+	secret.ID = existing.ID
+	secret.CreatedAt = existing.CreatedAt
+	if _, err := s.engine.ID(existing.ID).AllCols().Update(secret); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	return nil
+}
+
+// GetSecret 获取密钥，不存在时返回 nil
+func (s *Store) GetSecret(name string) (*Secret, error) {
+	secret := new(Secret)
+	has, err := s.engine.Where("name = ?", name).Get(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query secret: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return secret, nil
+}
+
+// ListSecrets 列出全部密钥
+func (s *Store) ListSecrets() ([]*Secret, error) {
+	secrets := make([]*Secret, 0)
+	if err := s.engine.OrderBy("name").Find(&secrets); err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	return secrets, nil
+}
+
+// DeleteSecret 删除密钥，返回是否存在
+func (s *Store) DeleteSecret(name string) (bool, error) {
+	affected, err := s.engine.Where("name = ?", name).Delete(new(Secret))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return affected > 0, nil
+}
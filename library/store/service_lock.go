@@ -0,0 +1,57 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// ServiceLock 服务锁定记录，锁定期间非管理员令牌不能部署、扩缩容或删除服务
+type ServiceLock struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	Service   string    `xorm:"varchar(128) notnull unique 'service'"`
+	Reason    string    `xorm:"varchar(255) 'reason'"`
+	Actor     string    `xorm:"varchar(128) 'actor'"`
+	CreatedAt time.Time `xorm:"created 'created_at'"`
+}
+
+// TableName 表名
+func (ServiceLock) TableName() string {
+	return "service_lock"
+}
+
+// AddServiceLock 锁定服务，已锁定时返回错误
+func (s *Store) AddServiceLock(lock *ServiceLock) error {
+	existing, err := s.GetServiceLock(lock.Service)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("service %s is already locked by %s", lock.Service, existing.Actor)
+	}
+	if _, err := s.engine.Insert(lock); err != nil {
+		return fmt.Errorf("failed to insert service lock: %w", err)
+	}
+	return nil
+}
+
+// GetServiceLock 获取服务锁定记录，未锁定时返回 nil
+func (s *Store) GetServiceLock(service string) (*ServiceLock, error) {
+	lock := new(ServiceLock)
+	has, err := s.engine.Where("service = ?", service).Get(lock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query service lock: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return lock, nil
+}
+
+// DeleteServiceLock 解除服务锁定，返回是否存在
+func (s *Store) DeleteServiceLock(service string) (bool, error) {
+	affected, err := s.engine.Where("service = ?", service).Delete(new(ServiceLock))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete service lock: %w", err)
+	}
+	return affected > 0, nil
+}
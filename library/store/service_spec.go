@@ -0,0 +1,149 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// ServiceSpec 服务期望状态
+// Spec 为完整的服务配置（JSON），包括环境变量、卷挂载等容器标签中无法保存的配置
+type ServiceSpec struct {
+	ID         int64     `xorm:"pk autoincr 'id'" json:"-"`
+	Name       string    `xorm:"varchar(128) notnull unique 'name'" json:"name"`
+	Image      string    `xorm:"varchar(255) 'image'" json:"image"`
+	Tag        string    `xorm:"varchar(128) 'tag'" json:"tag"`
+	PublicPort int       `xorm:"'public_port'" json:"public_port"`
+	Replicas   int       `xorm:"'replicas'" json:"replicas"`
+	Memory     int       `xorm:"'memory_limit'" json:"memory_limit"`
+	Stopped    bool      `xorm:"'stopped'" json:"stopped"`
+	Owner      string    `xorm:"varchar(64) index 'owner'" json:"owner"`
+	Spec       string    `xorm:"text 'spec'" json:"spec"`
+	CreatedAt  time.Time `xorm:"created 'created_at'" json:"created_at"`
+	UpdatedAt  time.Time `xorm:"updated 'updated_at'" json:"updated_at"`
+}
+
+// TableName 表名
+func (ServiceSpec) TableName() string {
+	return "service_spec"
+}
+
+// SaveServiceSpec 保存服务期望状态，已存在时覆盖
+func (s *Store) SaveServiceSpec(spec *ServiceSpec) error {
+	existing := new(ServiceSpec)
+	has, err := s.engine.Where("name = ?", spec.Name).Get(existing)
+	if err != nil {
+		return fmt.Errorf("failed to query service spec: %w", err)
+	}
+
+	if !has {
+		if _, err := s.engine.Insert(spec); err != nil {
+			return fmt.Errorf("failed to insert service spec: %w", err)
+		}
+		return nil
+	}
+
+	spec.ID = existing.ID
+	spec.CreatedAt = existing.CreatedAt
+	spec.Stopped = existing.Stopped
+	if existing.Owner != "" {
+		spec.Owner = existing.Owner // 所有者始终为首次部署的令牌
+	}
+	if _, err := s.engine.ID(existing.ID).AllCols().Update(spec); err != nil {
+		return fmt.Errorf("failed to update service spec: %w", err)
+	}
+	return nil
+}
+
+// GetServiceSpec 获取服务期望状态，不存在时返回 nil
+func (s *Store) GetServiceSpec(name string) (*ServiceSpec, error) {
+	spec := new(ServiceSpec)
+	has, err := s.engine.Where("name = ?", name).Get(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query service spec: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return spec, nil
+}
+
+// ListServiceSpecs 列出全部服务期望状态
+func (s *Store) ListServiceSpecs() ([]*ServiceSpec, error) {
+	specs := make([]*ServiceSpec, 0)
+	if err := s.engine.OrderBy("name").Find(&specs); err != nil {
+		return nil, fmt.Errorf("failed to list service specs: %w", err)
+	}
+	return specs, nil
+}
+
+// UpdateServiceReplicas 更新期望副本数
+func (s *Store) UpdateServiceReplicas(name string, replicas int) error {
+	if _, err := s.engine.Where("name = ?", name).Cols("replicas").Update(&ServiceSpec{Replicas: replicas}); err != nil {
+		return fmt.Errorf("failed to update service replicas: %w", err)
+	}
+	return nil
+}
+
+// UpdateServiceStopped 更新服务是否处于停止状态
+func (s *Store) UpdateServiceStopped(name string, stopped bool) error {
+	if _, err := s.engine.Where("name = ?", name).Cols("stopped").Update(&ServiceSpec{Stopped: stopped}); err != nil {
+		return fmt.Errorf("failed to update service stopped state: %w", err)
+	}
+	return nil
+}
+
+// DeleteServiceSpec 删除服务期望状态
+func (s *Store) DeleteServiceSpec(name string) error {
+	if _, err := s.engine.Where("name = ?", name).Delete(new(ServiceSpec)); err != nil {
+		return fmt.Errorf("failed to delete service spec: %w", err)
+	}
+	return nil
+}
+
+// serviceTables 以服务名称关联的表及其服务名称列
+var serviceTables = map[string]string{
+	"service_revision": "service",
+	"scale_schedule":   "service",
+	"autoscale_policy": "service",
+	"release":          "service",
+	"service_lock":     "service",
+	"public_port":      "service",
+	"image_watch":      "service",
+}
+
+// RenameService 在一个事务中将服务的期望状态、部署历史、定时扩缩容、自动扩缩容策略、发布、锁、端口登记和镜像更新监视改为新名称
+// spec 为改名后的完整服务配置（JSON）
+func (s *Store) RenameService(oldName, newName, spec string) error {
+	session := s.engine.NewSession()
+	defer session.Close()
+
+	if err := session.Begin(); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	exists, err := session.Where("name = ?", newName).Exist(new(ServiceSpec))
+	if err != nil {
+		session.Rollback()
+		return fmt.Errorf("failed to query service spec: %w", err)
+	}
+	if exists {
+		session.Rollback()
+		return fmt.Errorf("service spec %s already exists", newName)
+	}
+
+	if _, err := session.Table(new(ServiceSpec)).Where("name = ?", oldName).Update(map[string]interface{}{"name": newName, "spec": spec}); err != nil {
+		session.Rollback()
+		return fmt.Errorf("failed to rename service spec: %w", err)
+	}
+	for table, column := range serviceTables {
+		if _, err := session.Table(table).Where(column+" = ?", oldName).Update(map[string]interface{}{column: newName}); err != nil {
+			session.Rollback()
+			return fmt.Errorf("failed to rename service in %s: %w", table, err)
+		}
+	}
+
+	if err := session.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/log"
+	"xorm.io/xorm"
+)
+
+// DBName igo 中的数据库配置名称，对应配置文件中的 [sqlite.onedock]
+const DBName = "onedock"
+
+// Store 嵌入式状态存储（SQLite）
+// 保存服务的期望状态、部署历史等无法从容器名称和标签中恢复的数据
+type Store struct {
+	engine *xorm.Engine
+	memory bool // 是否为内存数据库（未配置持久化存储时的退化模式）
+}
+
+// NewStore 创建状态存储
+// 优先使用 igo 管理的 [sqlite.onedock] 数据库；未配置或不可用时退化为内存数据库，进程重启后数据丢失
+func NewStore() (*Store, error) {
+	if igo.App != nil && igo.App.DB != nil {
+		if dm := igo.App.DB.Get(DBName); dm != nil && dm.WriteDB != nil {
+			return newStore(dm.WriteDB, false)
+		}
+	}
+
+	log.Warn("Store", log.Any("Message", "未配置 [sqlite.onedock] 或数据库不可用，使用内存存储，重启后期望状态将丢失"))
+	return newMemoryStore()
+}
+
+// newMemoryStore 创建内存数据库存储
+func newMemoryStore() (*Store, error) {
+	engine, err := xorm.NewEngine("sqlite3", "file:onedock?mode=memory&cache=shared")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open memory store: %w", err)
+	}
+	engine.SetMaxOpenConns(1)
+	return newStore(engine, true)
+}
+
+// newStore 同步表结构并创建存储
+func newStore(engine *xorm.Engine, memory bool) (*Store, error) {
+	if err := engine.Sync2(new(ServiceSpec), new(Revision), new(Template), new(ScaleSchedule), new(AutoscalePolicy), new(Secret), new(Job), new(CronJob), new(Release), new(ServiceLock), new(PortRecord), new(ImageWatch), new(GitOpsService), new(AuditLog), new(Event), new(Deployment), new(APIToken), new(Webhook), new(TrashedService)); err != nil {
+		return nil, fmt.Errorf("failed to sync store tables: %w", err)
+	}
+	return &Store{engine: engine, memory: memory}, nil
+}
+
+// Persistent 数据是否持久化到磁盘
+func (s *Store) Persistent() bool {
+	return !s.memory
+}
+
+// Ping 检查数据库连接是否可用
+func (s *Store) Ping() error {
+	return s.engine.Ping()
+}
+
+// Close 关闭数据库连接，进程退出前调用
+func (s *Store) Close() error {
+	return s.engine.Close()
+}
+
+// timeParam 按 xorm 写入时间字段的格式（精确到秒的文本）格式化查询参数
+// 直接传入 time.Time 时驱动会带上小数秒和时区，按字符串比较的结果不正确
+func (s *Store) timeParam(t time.Time) string {
+	return t.In(s.engine.TZLocation).Format("2006-01-02 15:04:05")
+}
+
+// Engine 返回底层 xorm 引擎
+func (s *Store) Engine() *xorm.Engine {
+	return s.engine
+}
@@ -0,0 +1,735 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+// TestServiceSpecAndRevisions 测试期望状态与部署历史的读写（未配置数据库时使用内存存储）
+func TestServiceSpecAndRevisions(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.SaveServiceSpec(&ServiceSpec{Name: "web", Image: "nginx", Tag: "1.25", Replicas: 2, Spec: "{}"}); err != nil {
+		t.Fatalf("保存期望状态失败: %v", err)
+	}
+	if err := s.SaveServiceSpec(&ServiceSpec{Name: "web", Image: "nginx", Tag: "1.26", Replicas: 2, Spec: "{}"}); err != nil {
+		t.Fatalf("覆盖期望状态失败: %v", err)
+	}
+	if err := s.UpdateServiceReplicas("web", 5); err != nil {
+		t.Fatalf("更新副本数失败: %v", err)
+	}
+
+	spec, err := s.GetServiceSpec("web")
+	if err != nil || spec == nil {
+		t.Fatalf("读取期望状态失败: %v", err)
+	}
+	if spec.Tag != "1.26" || spec.Replicas != 5 {
+		t.Errorf("期望 tag=1.26 replicas=5，实际 tag=%s replicas=%d", spec.Tag, spec.Replicas)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := s.AddRevision(&Revision{Service: "web", Action: "update"}, 3); err != nil {
+			t.Fatalf("追加历史失败: %v", err)
+		}
+	}
+	revisions, err := s.ListRevisions("web")
+	if err != nil {
+		t.Fatalf("读取历史失败: %v", err)
+	}
+	if len(revisions) != 3 || revisions[0].Revision != 5 || revisions[2].Revision != 3 {
+		t.Errorf("期望保留版本 5..3，实际 %d 条", len(revisions))
+	}
+
+	if err := s.DeleteServiceSpec("web"); err != nil {
+		t.Fatalf("删除期望状态失败: %v", err)
+	}
+	if spec, _ := s.GetServiceSpec("web"); spec != nil {
+		t.Error("删除后仍能读取期望状态")
+	}
+}
+
+// TestScaleSchedules 测试定时扩缩容规则的读写
+func TestScaleSchedules(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+	for _, replicas := range []int{5, 1} {
+		if err := s.AddScaleSchedule(&ScaleSchedule{Service: "schedule-web", Cron: "0 9 * * *", Replicas: replicas}); err != nil {
+			t.Fatalf("添加规则失败: %v", err)
+		}
+	}
+
+	rules, err := s.ListScaleSchedules("schedule-web")
+	if err != nil || len(rules) != 2 {
+		t.Fatalf("读取规则失败: %v, %d", err, len(rules))
+	}
+	now := time.Now().Truncate(time.Second)
+	if err := s.UpdateScaleScheduleRun(rules[0].ID, now, "boom"); err != nil {
+		t.Fatalf("记录执行结果失败: %v", err)
+	}
+	rules, _ = s.ListScaleSchedules("schedule-web")
+	if rules[0].LastError != "boom" || !rules[0].LastRunAt.Equal(now) {
+		t.Errorf("执行结果未保存: %+v", rules[0])
+	}
+
+	if exists, err := s.DeleteScaleSchedule("other", rules[0].ID); err != nil || exists {
+		t.Errorf("不应删除其他服务的规则: %v, %v", exists, err)
+	}
+	if err := s.DeleteScaleSchedules("schedule-web"); err != nil {
+		t.Fatalf("删除规则失败: %v", err)
+	}
+	if rules, _ := s.ListScaleSchedules("schedule-web"); len(rules) != 0 {
+		t.Errorf("规则未删除: %d", len(rules))
+	}
+}
+
+// TestSecrets 测试密钥的保存、覆盖与删除
+func TestSecrets(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.SaveSecret(&Secret{Name: "db_password", Value: "v1"}); err != nil {
+		t.Fatalf("保存密钥失败: %v", err)
+	}
+	if err := s.SaveSecret(&Secret{Name: "db_password", Value: "v2"}); err != nil {
+		t.Fatalf("覆盖密钥失败: %v", err)
+	}
+	secret, err := s.GetSecret("db_password")
+	if err != nil || secret == nil || secret.Value != "v2" {
+		t.Fatalf("GetSecret = %+v, %v, 期望值为 v2", secret, err)
+	}
+
+	if exists, err := s.DeleteSecret("db_password"); err != nil || !exists {
+		t.Errorf("DeleteSecret = %v, %v, 期望 true", exists, err)
+	}
+	if exists, _ := s.DeleteSecret("db_password"); exists {
+		t.Error("重复删除应返回不存在")
+	}
+}
+
+// TestReleases 测试发布记录每个服务只能有一个
+func TestReleases(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.AddRelease(&Release{Service: "web", Mode: "canary", Image: "nginx", Tag: "1.27", Replicas: 1}); err != nil {
+		t.Fatalf("保存发布失败: %v", err)
+	}
+	if err := s.AddRelease(&Release{Service: "web", Mode: "blue_green", Image: "nginx", Tag: "1.28", Replicas: 3}); err == nil {
+		t.Error("同一服务重复发起发布应返回错误")
+	}
+	release, err := s.GetRelease("web")
+	if err != nil || release == nil || release.Tag != "1.27" || release.Promoted {
+		t.Fatalf("GetRelease = %+v, %v, 期望标签为 1.27 且未确认", release, err)
+	}
+
+	if err := s.MarkReleasePromoted("web"); err != nil {
+		t.Fatalf("标记发布已确认失败: %v", err)
+	}
+	if release, _ := s.GetRelease("web"); release == nil || !release.Promoted {
+		t.Errorf("GetRelease = %+v, 期望已确认", release)
+	}
+
+	if exists, err := s.DeleteRelease("web"); err != nil || !exists {
+		t.Errorf("DeleteRelease = %v, %v, 期望 true", exists, err)
+	}
+	if release, _ := s.GetRelease("web"); release != nil {
+		t.Error("删除后不应再返回发布")
+	}
+}
+
+// TestServiceLocks 测试服务锁定与解除
+func TestServiceLocks(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.AddServiceLock(&ServiceLock{Service: "web", Reason: "双十一封版", Actor: "token:abcd****"}); err != nil {
+		t.Fatalf("锁定服务失败: %v", err)
+	}
+	if err := s.AddServiceLock(&ServiceLock{Service: "web"}); err == nil {
+		t.Error("重复锁定应返回错误")
+	}
+	lock, err := s.GetServiceLock("web")
+	if err != nil || lock == nil || lock.Reason != "双十一封版" {
+		t.Fatalf("GetServiceLock = %+v, %v, 期望返回锁定原因", lock, err)
+	}
+	if lock, _ := s.GetServiceLock("api"); lock != nil {
+		t.Errorf("未锁定的服务不应返回记录: %+v", lock)
+	}
+
+	if exists, err := s.DeleteServiceLock("web"); err != nil || !exists {
+		t.Errorf("DeleteServiceLock = %v, %v, 期望 true", exists, err)
+	}
+	if exists, _ := s.DeleteServiceLock("web"); exists {
+		t.Error("重复解除锁定应返回 false")
+	}
+}
+
+// TestPorts 测试公共端口的分配、预留与释放
+func TestPorts(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.AllocatePort(9203, "web", "system"); err != nil {
+		t.Fatalf("分配端口失败: %v", err)
+	}
+	if err := s.AllocatePort(9203, "web", "system"); err != nil {
+		t.Errorf("重复分配给同一服务不应报错: %v", err)
+	}
+	if err := s.AllocatePort(9203, "api", "system"); err == nil {
+		t.Error("已分配给其他服务的端口应返回错误")
+	}
+
+	if err := s.ReservePort(&PortRecord{Port: 9300, Service: "billing"}); err != nil {
+		t.Fatalf("预留端口失败: %v", err)
+	}
+	if err := s.ReservePort(&PortRecord{Port: 9203}); err == nil {
+		t.Error("已分配的端口不能预留")
+	}
+	if err := s.AllocatePort(9300, "api", "system"); err == nil {
+		t.Error("为其他服务预留的端口应返回错误")
+	}
+	if err := s.AllocatePort(9300, "billing", "system"); err != nil {
+		t.Fatalf("为本服务预留的端口应可分配: %v", err)
+	}
+	if record, _ := s.GetPort(9300); record == nil || record.Reserved || record.Service != "billing" {
+		t.Errorf("GetPort = %+v, 期望已分配给 billing", record)
+	}
+
+	if err := s.ReservePort(&PortRecord{Port: 9400}); err != nil {
+		t.Fatalf("预留端口失败: %v", err)
+	}
+	if reserved, _ := s.ListPorts(true); len(reserved) != 1 || reserved[0].Port != 9400 {
+		t.Errorf("ListPorts(true) = %+v, 期望只有 9400", reserved)
+	}
+
+	if err := s.ReleasePorts("web"); err != nil {
+		t.Fatalf("释放端口失败: %v", err)
+	}
+	if record, _ := s.GetPort(9203); record != nil {
+		t.Errorf("释放后不应再登记: %+v", record)
+	}
+	if exists, err := s.DeletePortReservation(9300); err != nil || exists {
+		t.Errorf("DeletePortReservation(9300) = %v, %v, 已分配的端口不是预留", exists, err)
+	}
+	if exists, err := s.DeletePortReservation(9400); err != nil || !exists {
+		t.Errorf("DeletePortReservation(9400) = %v, %v, 期望 true", exists, err)
+	}
+}
+
+// TestJobs 测试任务记录的保存、数量上限与中断清理
+func TestJobs(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	for _, id := range []string{"job-a", "job-b", "job-c"} {
+		if err := s.AddJob(&Job{JobID: id, Image: "busybox:latest", Status: "running"}, 2); err != nil {
+			t.Fatalf("添加任务失败: %v", err)
+		}
+	}
+	jobs, err := s.ListJobs("", 0)
+	if err != nil || len(jobs) != 2 || jobs[0].JobID != "job-c" {
+		t.Fatalf("ListJobs = %d, %v, 期望只保留最近 2 条且倒序", len(jobs), err)
+	}
+
+	exitCode := int64(3)
+	jobs[0].Status = "failed"
+	jobs[0].ExitCode = &exitCode
+	if err := s.UpdateJob(jobs[0]); err != nil {
+		t.Fatalf("更新任务失败: %v", err)
+	}
+	if job, _ := s.GetJob("job-c"); job == nil || job.ExitCode == nil || *job.ExitCode != 3 {
+		t.Errorf("GetJob = %+v, 期望退出码 3", job)
+	}
+
+	affected, err := s.FailUnfinishedJobs([]string{"pending", "running"}, "failed", "interrupted")
+	if err != nil || affected != 1 {
+		t.Errorf("FailUnfinishedJobs = %d, %v, 期望 1", affected, err)
+	}
+	if job, _ := s.GetJob("job-b"); job == nil || job.Status != "failed" || job.Error != "interrupted" {
+		t.Errorf("中断的任务应标记为失败: %+v", job)
+	}
+}
+
+// TestCronJobs 测试定时任务的保存、触发记录与按定时任务保留运行记录
+func TestCronJobs(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.SaveCronJob(&CronJob{Name: "nightly", Cron: "0 2 * * *", HistoryLimit: 2, Spec: "{}"}); err != nil {
+		t.Fatalf("保存定时任务失败: %v", err)
+	}
+	now := time.Now().Truncate(time.Second)
+	if err := s.UpdateCronJobRun("nightly", now, "run-1", "skipped"); err != nil {
+		t.Fatalf("记录触发结果失败: %v", err)
+	}
+	if err := s.UpdateCronJobRun("nightly", now, "run-2", ""); err != nil {
+		t.Fatalf("记录触发结果失败: %v", err)
+	}
+	if err := s.SaveCronJob(&CronJob{Name: "nightly", Cron: "30 2 * * *", HistoryLimit: 2, Spec: "{}"}); err != nil {
+		t.Fatalf("覆盖定时任务失败: %v", err)
+	}
+	job, err := s.GetCronJob("nightly")
+	if err != nil || job == nil || job.Cron != "30 2 * * *" || job.LastJobID != "run-2" || job.LastError != "" {
+		t.Fatalf("GetCronJob = %+v, %v, 期望覆盖配置并保留最近一次触发记录", job, err)
+	}
+
+	for _, id := range []string{"cron-run-a", "cron-run-b", "cron-run-c"} {
+		if err := s.AddJob(&Job{JobID: id, CronJob: "nightly", Status: "succeeded"}, 2); err != nil {
+			t.Fatalf("添加运行记录失败: %v", err)
+		}
+	}
+	if err := s.AddJob(&Job{JobID: "adhoc-run", Status: "succeeded"}, 100); err != nil {
+		t.Fatalf("添加任务失败: %v", err)
+	}
+	if runs, _ := s.ListJobs("nightly", 0); len(runs) != 2 || runs[0].JobID != "cron-run-c" {
+		t.Errorf("定时任务应只保留最近 2 次运行记录: %d", len(runs))
+	}
+
+	if err := s.DeleteJobs("nightly"); err != nil {
+		t.Fatalf("删除运行记录失败: %v", err)
+	}
+	if runs, _ := s.ListJobs("nightly", 0); len(runs) != 0 {
+		t.Errorf("删除后不应有运行记录: %d", len(runs))
+	}
+	if job, _ := s.GetJob("adhoc-run"); job == nil {
+		t.Error("手动任务不应被删除")
+	}
+	if exists, err := s.DeleteCronJob("nightly"); err != nil || !exists {
+		t.Errorf("DeleteCronJob = %v, %v, 期望 true", exists, err)
+	}
+}
+
+// TestRenameService 测试改名时迁移服务的全部记录
+func TestRenameService(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.SaveServiceSpec(&ServiceSpec{Name: "shop", Image: "nginx", Tag: "1.25", PublicPort: 9210, Replicas: 2, Spec: `{"name":"shop"}`}); err != nil {
+		t.Fatalf("保存期望状态失败: %v", err)
+	}
+	if err := s.AddRevision(&Revision{Service: "shop", Action: "deploy"}, 10); err != nil {
+		t.Fatalf("记录部署历史失败: %v", err)
+	}
+	if err := s.AddServiceLock(&ServiceLock{Service: "shop", Reason: "freeze"}); err != nil {
+		t.Fatalf("锁定服务失败: %v", err)
+	}
+	if err := s.AllocatePort(9210, "shop", "test"); err != nil {
+		t.Fatalf("分配端口失败: %v", err)
+	}
+	if err := s.SaveServiceSpec(&ServiceSpec{Name: "shop-api", Spec: "{}"}); err != nil {
+		t.Fatalf("保存期望状态失败: %v", err)
+	}
+
+	if err := s.RenameService("shop", "shop-api", "{}"); err == nil {
+		t.Error("新名称已有期望状态时应返回错误")
+	}
+	if err := s.RenameService("shop", "storefront", `{"name":"storefront"}`); err != nil {
+		t.Fatalf("改名失败: %v", err)
+	}
+
+	if spec, _ := s.GetServiceSpec("shop"); spec != nil {
+		t.Error("改名后旧名称不应有期望状态")
+	}
+	spec, err := s.GetServiceSpec("storefront")
+	if err != nil || spec == nil || spec.Replicas != 2 || spec.Spec != `{"name":"storefront"}` {
+		t.Fatalf("GetServiceSpec = %+v, %v, 期望保留副本数并更新配置", spec, err)
+	}
+	if revisions, _ := s.ListRevisions("storefront"); len(revisions) != 1 {
+		t.Errorf("部署历史应迁移到新名称: %d", len(revisions))
+	}
+	if lock, _ := s.GetServiceLock("storefront"); lock == nil || lock.Reason != "freeze" {
+		t.Errorf("服务锁应迁移到新名称: %+v", lock)
+	}
+	if record, _ := s.GetPort(9210); record == nil || record.Service != "storefront" {
+		t.Errorf("端口登记应迁移到新名称: %+v", record)
+	}
+}
+
+// TestImageWatches 测试镜像更新监视的保存与检查结果
+func TestImageWatches(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.SaveImageWatch(&ImageWatch{Service: "watched", Mode: "digest", Action: "notify"}); err != nil {
+		t.Fatalf("保存镜像更新监视失败: %v", err)
+	}
+	watch, err := s.GetImageWatch("watched")
+	if err != nil || watch == nil {
+		t.Fatalf("GetImageWatch = %+v, %v", watch, err)
+	}
+	watch.LatestDigest = "sha256:abc"
+	watch.UpdateAvailable = true
+	watch.LastUpdateAt = time.Now()
+	if err := s.UpdateImageWatchResult(watch); err != nil {
+		t.Fatalf("记录检查结果失败: %v", err)
+	}
+
+	if err := s.SaveImageWatch(&ImageWatch{Service: "watched", Mode: "semver", Pattern: `^1\.`, Action: "redeploy"}); err != nil {
+		t.Fatalf("覆盖镜像更新监视失败: %v", err)
+	}
+	watch, _ = s.GetImageWatch("watched")
+	if watch.Mode != "semver" || watch.UpdateAvailable || watch.LatestDigest != "" || watch.LastUpdateAt.IsZero() {
+		t.Errorf("覆盖后 = %+v, 期望更新配置、清空检查结果并保留上次更新时间", watch)
+	}
+
+	if exists, err := s.DeleteImageWatch("watched"); err != nil || !exists {
+		t.Errorf("DeleteImageWatch = %v, %v, 期望 true", exists, err)
+	}
+}
+
+// TestGitOpsServices 测试 GitOps 管理记录
+func TestGitOpsServices(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.SaveGitOpsService("synced-web", "web.yaml", "abc"); err != nil {
+		t.Fatalf("保存 GitOps 管理记录失败: %v", err)
+	}
+	if err := s.SaveGitOpsService("synced-web", "apps/web.yaml", "def"); err != nil {
+		t.Fatalf("更新 GitOps 管理记录失败: %v", err)
+	}
+	services, err := s.ListGitOpsServices()
+	if err != nil {
+		t.Fatalf("列出 GitOps 管理记录失败: %v", err)
+	}
+	found := 0
+	for _, service := range services {
+		if service.Service == "synced-web" {
+			found++
+			if service.File != "apps/web.yaml" || service.Commit != "def" {
+				t.Errorf("更新后 = %+v, 期望 apps/web.yaml、def", service)
+			}
+		}
+	}
+	if found != 1 {
+		t.Errorf("synced-web 记录数 = %d, 期望 1", found)
+	}
+
+	if err := s.DeleteGitOpsService("synced-web"); err != nil {
+		t.Fatalf("删除 GitOps 管理记录失败: %v", err)
+	}
+	services, _ = s.ListGitOpsServices()
+	for _, service := range services {
+		if service.Service == "synced-web" {
+			t.Error("删除后仍存在 synced-web")
+		}
+	}
+}
+
+// TestSnapshot 测试快照导出与恢复
+func TestSnapshot(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.SaveSecret(&Secret{Name: "snapshot-token", Value: "ciphertext"}); err != nil {
+		t.Fatalf("保存密钥失败: %v", err)
+	}
+	if err := s.SaveServiceSpec(&ServiceSpec{Name: "snapshot-web", Image: "nginx", Tag: "1.25", PublicPort: 9220, Replicas: 2, Spec: "{}"}); err != nil {
+		t.Fatalf("保存期望状态失败: %v", err)
+	}
+	before, _ := s.GetSecret("snapshot-token")
+
+	snapshot, err := s.ExportSnapshot()
+	if err != nil {
+		t.Fatalf("导出快照失败: %v", err)
+	}
+	if _, err := s.DeleteSecret("snapshot-token"); err != nil {
+		t.Fatalf("删除密钥失败: %v", err)
+	}
+	if err := s.DeleteServiceSpec("snapshot-web"); err != nil {
+		t.Fatalf("删除期望状态失败: %v", err)
+	}
+
+	if err := s.RestoreSnapshot(snapshot); err != nil {
+		t.Fatalf("恢复快照失败: %v", err)
+	}
+	secret, _ := s.GetSecret("snapshot-token")
+	if secret == nil || secret.ID != before.ID || secret.Value != "ciphertext" || !secret.CreatedAt.Equal(before.CreatedAt) {
+		t.Errorf("恢复后的密钥 = %+v, 期望 %+v", secret, before)
+	}
+	spec, _ := s.GetServiceSpec("snapshot-web")
+	if spec == nil || spec.Replicas != 2 || spec.PublicPort != 9220 {
+		t.Errorf("恢复后的期望状态 = %+v", spec)
+	}
+
+	after, err := s.ExportSnapshot()
+	if err != nil {
+		t.Fatalf("再次导出快照失败: %v", err)
+	}
+	if len(after.ServiceSpecs) != len(snapshot.ServiceSpecs) || len(after.Revisions) != len(snapshot.Revisions) || len(after.Secrets) != len(snapshot.Secrets) {
+		t.Errorf("恢复后记录数与快照不一致")
+	}
+}
+
+// TestAuditLogs 测试审计日志的写入、过滤与保留期清理
+func TestAuditLogs(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	entries := []*AuditLog{
+		{Actor: "token:audi****", Method: "POST", Path: "/onedock/", Service: "audited-web", Status: 200},
+		{Actor: "token:audi****", Method: "POST", Path: "/onedock/audited-web/scale", Service: "audited-web", RequestID: "ci-build-1234", Status: 200, Code: 1, Error: "quota exceeded"},
+		{Actor: "token:othe****", Method: "DELETE", Path: "/onedock/audited-api", Service: "audited-api", Status: 200},
+	}
+	for _, entry := range entries {
+		if err := s.AddAuditLog(entry, 0); err != nil {
+			t.Fatalf("写入审计日志失败: %v", err)
+		}
+	}
+
+	logs, total, err := s.ListAuditLogs(AuditQuery{Service: "audited-web", Limit: 10})
+	if err != nil || total != 2 || len(logs) != 2 || logs[0].Path != "/onedock/audited-web/scale" {
+		t.Fatalf("按服务查询 = %d 条（总数 %d）, %v, 期望 2 条且按时间倒序", len(logs), total, err)
+	}
+	logs, total, _ = s.ListAuditLogs(AuditQuery{Service: "audited-web", Failed: true, Limit: 10})
+	if total != 1 || logs[0].Error != "quota exceeded" {
+		t.Errorf("查询失败请求 = %d 条, 期望 1 条", total)
+	}
+	_, total, _ = s.ListAuditLogs(AuditQuery{Actor: "token:othe****", Method: "DELETE", Limit: 10})
+	if total != 1 {
+		t.Errorf("按操作者和方法查询 = %d 条, 期望 1 条", total)
+	}
+	logs, total, _ = s.ListAuditLogs(AuditQuery{RequestID: "ci-build-1234", Limit: 10})
+	if total != 1 || logs[0].Path != "/onedock/audited-web/scale" {
+		t.Errorf("按请求 ID 查询 = %d 条, 期望 1 条", total)
+	}
+	_, total, _ = s.ListAuditLogs(AuditQuery{Service: "audited-web", Since: time.Now().Add(time.Hour), Limit: 10})
+	if total != 0 {
+		t.Errorf("查询未来时间 = %d 条, 期望 0 条", total)
+	}
+
+	// 时间字段精确到秒，将已有记录改为两小时前写入
+	if _, err := s.engine.Exec("UPDATE audit_log SET created_at = ? WHERE service = ?", s.timeParam(time.Now().Add(-2*time.Hour)), "audited-web"); err != nil {
+		t.Fatalf("修改记录时间失败: %v", err)
+	}
+	if err := s.AddAuditLog(&AuditLog{Actor: "token:audi****", Method: "POST", Service: "audited-web"}, time.Hour); err != nil {
+		t.Fatalf("写入审计日志失败: %v", err)
+	}
+	_, total, _ = s.ListAuditLogs(AuditQuery{Service: "audited-web", Limit: 10})
+	if total != 1 {
+		t.Errorf("保留期清理后 = %d 条, 期望只剩最新 1 条", total)
+	}
+}
+
+// TestEvents 测试事件的写入、过滤与保留期清理
+func TestEvents(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	events := []*Event{
+		{Service: "evented-web", Type: "deployed", Message: "deployed nginx:1.25 with 2 replicas", Actor: "token:even****"},
+		{Service: "evented-web", Type: "replica_crashed", Message: "replica evented-web-0 exited", Actor: "system"},
+		{Service: "evented-api", Type: "scaled", Message: "scaled from 1 to 3 replicas", Actor: "token:even****"},
+	}
+	for _, event := range events {
+		if err := s.AddEvent(event, 0); err != nil {
+			t.Fatalf("写入事件失败: %v", err)
+		}
+	}
+
+	list, total, err := s.ListEvents(EventQuery{Service: "evented-web", Limit: 10})
+	if err != nil || total != 2 || len(list) != 2 || list[0].Type != "replica_crashed" {
+		t.Fatalf("按服务查询 = %d 条（总数 %d）, %v, 期望 2 条且按时间倒序", len(list), total, err)
+	}
+	_, total, _ = s.ListEvents(EventQuery{Service: "evented-api", Type: "scaled", Limit: 10})
+	if total != 1 {
+		t.Errorf("按服务和类型查询 = %d 条, 期望 1 条", total)
+	}
+	_, total, _ = s.ListEvents(EventQuery{Service: "evented-web", Types: []string{"replica_crashed", "crash_loop"}, Limit: 10})
+	if total != 1 {
+		t.Errorf("按多个类型查询 = %d 条, 期望 1 条", total)
+	}
+	_, total, _ = s.ListEvents(EventQuery{Service: "evented-web", Until: time.Now().Add(-time.Hour), Limit: 10})
+	if total != 0 {
+		t.Errorf("查询过去时间 = %d 条, 期望 0 条", total)
+	}
+
+	if _, err := s.engine.Exec("UPDATE service_event SET created_at = ? WHERE service = ?", s.timeParam(time.Now().Add(-2*time.Hour)), "evented-web"); err != nil {
+		t.Fatalf("修改事件时间失败: %v", err)
+	}
+	if err := s.AddEvent(&Event{Service: "evented-web", Type: "scaled"}, time.Hour); err != nil {
+		t.Fatalf("写入事件失败: %v", err)
+	}
+	_, total, _ = s.ListEvents(EventQuery{Service: "evented-web", Limit: 10})
+	if total != 1 {
+		t.Errorf("保留期清理后 = %d 条, 期望只剩最新 1 条", total)
+	}
+}
+
+// TestDeployments 测试异步部署记录的保存、进度更新与中断清理
+func TestDeployments(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	for _, id := range []string{"deploy-a", "deploy-b", "deploy-c"} {
+		if err := s.AddDeployment(&Deployment{DeploymentID: id, Service: "async-web", Status: "running"}, 2); err != nil {
+			t.Fatalf("添加部署记录失败: %v", err)
+		}
+	}
+	deployments, err := s.ListDeployments("async-web", 0)
+	if err != nil || len(deployments) != 2 || deployments[0].DeploymentID != "deploy-c" {
+		t.Fatalf("ListDeployments = %d, %v, 期望只保留最近 2 条且倒序", len(deployments), err)
+	}
+
+	deployments[0].Status = "succeeded"
+	deployments[0].Current, deployments[0].Total = 3, 3
+	if err := s.UpdateDeployment(deployments[0]); err != nil {
+		t.Fatalf("更新部署记录失败: %v", err)
+	}
+	if deployment, _ := s.GetDeployment("deploy-c"); deployment == nil || deployment.Status != "succeeded" || deployment.Current != 3 {
+		t.Errorf("GetDeployment = %+v, 期望已完成 3/3", deployment)
+	}
+	if deployment, _ := s.GetDeployment("deploy-a"); deployment != nil {
+		t.Errorf("超出保留数的记录应被删除: %+v", deployment)
+	}
+
+	affected, err := s.FailUnfinishedDeployments([]string{"pending", "running"}, "failed", "interrupted")
+	if err != nil || affected != 1 {
+		t.Errorf("FailUnfinishedDeployments = %d, %v, 期望 1", affected, err)
+	}
+	if deployment, _ := s.GetDeployment("deploy-b"); deployment == nil || deployment.Status != "failed" || deployment.Error != "interrupted" {
+		t.Errorf("中断的部署应标记为失败: %+v", deployment)
+	}
+}
+
+// TestAPITokens 测试访问令牌按摘要查找以及过期、吊销时间的更新
+func TestAPITokens(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	token := &APIToken{Name: "ci", Role: "deployer", Hash: "hash-1", Prefix: "odk_1234", Services: []string{"app-a"}}
+	if err := s.AddAPIToken(token); err != nil {
+		t.Fatalf("保存令牌失败: %v", err)
+	}
+	if err := s.AddAPIToken(&APIToken{Name: "dup", Role: "admin", Hash: "hash-1"}); err == nil {
+		t.Error("摘要重复时应返回错误")
+	}
+	got, err := s.GetAPITokenByHash("hash-1")
+	if err != nil || got == nil || got.ID != token.ID || got.ExpiresAt != nil {
+		t.Fatalf("GetAPITokenByHash = %+v, %v", got, err)
+	}
+	if len(got.Services) != 1 || got.Services[0] != "app-a" || len(got.Namespaces) != 0 {
+		t.Errorf("服务范围 = %v %v, 期望 [app-a] []", got.Services, got.Namespaces)
+	}
+	if got, _ := s.GetAPITokenByHash("missing"); got != nil {
+		t.Errorf("不存在的摘要应返回 nil, 实际 %+v", got)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	got.ExpiresAt = &now
+	got.RevokedAt = &now
+	if err := s.UpdateAPIToken(got); err != nil {
+		t.Fatalf("更新令牌失败: %v", err)
+	}
+	got, err = s.GetAPIToken(token.ID)
+	if err != nil || got == nil || got.ExpiresAt == nil || !got.ExpiresAt.Equal(now) || got.RevokedAt == nil {
+		t.Fatalf("GetAPIToken = %+v, %v, 期望已设置过期和吊销时间", got, err)
+	}
+	if tokens, err := s.ListAPITokens(); err != nil || len(tokens) != 1 {
+		t.Errorf("ListAPITokens = %d, %v, 期望 1", len(tokens), err)
+	}
+}
+
+// TestWebhooks 测试事件订阅的覆盖保存保留投递结果
+func TestWebhooks(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	if err := s.SaveWebhook(&Webhook{Name: "tracker", URL: "https://ci.example.com/a", Events: []string{"deployed"}}); err != nil {
+		t.Fatalf("保存订阅失败: %v", err)
+	}
+	at := time.Now().Truncate(time.Second)
+	if err := s.UpdateWebhookDelivery("tracker", 502, "responded with status 502", at); err != nil {
+		t.Fatalf("记录投递结果失败: %v", err)
+	}
+	if err := s.SaveWebhook(&Webhook{Name: "tracker", URL: "https://ci.example.com/b", Disabled: true}); err != nil {
+		t.Fatalf("覆盖订阅失败: %v", err)
+	}
+	webhook, err := s.GetWebhook("tracker")
+	if err != nil || webhook == nil || webhook.URL != "https://ci.example.com/b" || !webhook.Disabled || len(webhook.Events) != 0 {
+		t.Fatalf("GetWebhook = %+v, %v, 期望覆盖后的配置", webhook, err)
+	}
+	if webhook.LastStatus != 502 || !webhook.LastDeliveryAt.Equal(at) {
+		t.Errorf("投递结果 = %d %v, 期望保留 502 %v", webhook.LastStatus, webhook.LastDeliveryAt, at)
+	}
+
+	if exists, err := s.DeleteWebhook("tracker"); err != nil || !exists {
+		t.Errorf("DeleteWebhook = %v, %v, 期望 true", exists, err)
+	}
+	if webhooks, _ := s.ListWebhooks(); len(webhooks) != 0 {
+		t.Errorf("订阅未删除: %d", len(webhooks))
+	}
+}
+
+// TestTrashedServices 测试回收站的保存、覆盖和过期查询
+func TestTrashedServices(t *testing.T) {
+	s, err := newMemoryStore()
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	if err := s.SaveTrashedService(&TrashedService{Name: "old", PublicPort: 9200, DeletedAt: now.Add(-48 * time.Hour), ExpiresAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+	if err := s.SaveTrashedService(&TrashedService{Name: "api", PublicPort: 9201, DeletedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+	if err := s.SaveTrashedService(&TrashedService{Name: "api", PublicPort: 9202, Containers: []string{"onedock_trash_onedock-api-p9202-c30000-1"}, DeletedAt: now, ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("覆盖失败: %v", err)
+	}
+
+	trashed, err := s.GetTrashedService("api")
+	if err != nil || trashed == nil || trashed.PublicPort != 9202 || len(trashed.Containers) != 1 {
+		t.Fatalf("GetTrashedService = %+v, %v, 期望覆盖后的记录", trashed, err)
+	}
+	if list, _ := s.ListTrashedServices(); len(list) != 2 || list[0].Name != "api" {
+		t.Errorf("ListTrashedServices = %+v, 期望最近删除的在前", list)
+	}
+	expired, err := s.ListExpiredTrashedServices(now)
+	if err != nil || len(expired) != 1 || expired[0].Name != "old" {
+		t.Errorf("ListExpiredTrashedServices = %+v, %v, 期望只有 old", expired, err)
+	}
+
+	if exists, err := s.DeleteTrashedService("old"); err != nil || !exists {
+		t.Errorf("DeleteTrashedService = %v, %v, 期望 true", exists, err)
+	}
+	if trashed, _ := s.GetTrashedService("old"); trashed != nil {
+		t.Errorf("记录未删除: %+v", trashed)
+	}
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Template 服务模板
+// Spec 为带 ${VAR} 占位符的服务配置（JSON）
+type Template struct {
+	ID          int64     `xorm:"pk autoincr 'id'"`
+	Name        string    `xorm:"varchar(128) notnull unique 'name'"`
+	Description string    `xorm:"varchar(512) 'description'"`
+	Spec        string    `xorm:"text 'spec'"`
+	CreatedAt   time.Time `xorm:"created 'created_at'"`
+	UpdatedAt   time.Time `xorm:"updated 'updated_at'"`
+}
+
+// TableName 表名
+func (Template) TableName() string {
+	return "service_template"
+}
+
+// SaveTemplate 保存模板，同名模板已存在时覆盖
+func (s *Store) SaveTemplate(template *Template) error {
+	existing := new(Template)
+	has, err := s.engine.Where("name = ?", template.Name).Get(existing)
+	if err != nil {
+		return fmt.Errorf("failed to query template: %w", err)
+	}
+
+	if !has {
+		if _, err := s.engine.Insert(template); err != nil {
+			return fmt.Errorf("failed to insert template: %w", err)
+		}
+		return nil
+	}
+
+	template.ID = existing.ID
+	template.CreatedAt = existing.CreatedAt
+	if _, err := s.engine.ID(existing.ID).AllCols().Update(template); err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+	return nil
+}
+
+// GetTemplate 获取模板，不存在时返回 nil
+func (s *Store) GetTemplate(name string) (*Template, error) {
+	template := new(Template)
+	has, err := s.engine.Where("name = ?", name).Get(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query template: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return template, nil
+}
+
+// ListTemplates 列出全部模板
+func (s *Store) ListTemplates() ([]*Template, error) {
+	templates := make([]*Template, 0)
+	if err := s.engine.OrderBy("name").Find(&templates); err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	return templates, nil
+}
+
+// DeleteTemplate 删除模板，返回是否存在
+func (s *Store) DeleteTemplate(name string) (bool, error) {
+	affected, err := s.engine.Where("name = ?", name).Delete(new(Template))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete template: %w", err)
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// TrashedService 回收站中的服务：删除时保存的期望状态，保留期内可以恢复
+// Containers 为保留的已停止容器（改名后的名称），为空表示容器已删除，恢复时按 Spec 重新部署
+type TrashedService struct {
+	ID         int64     `xorm:"pk autoincr 'id'"`
+	Name       string    `xorm:"varchar(128) notnull unique 'name'"`
+	Image      string    `xorm:"varchar(255) 'image'"`
+	Tag        string    `xorm:"varchar(128) 'tag'"`
+	PublicPort int       `xorm:"'public_port'"`
+	Replicas   int       `xorm:"'replicas'"`
+	Memory     int       `xorm:"'memory_limit'"`
+	Owner      string    `xorm:"varchar(64) 'owner'"`
+	Spec       string    `xorm:"text 'spec'"`
+	Containers []string  `xorm:"json 'containers'"`
+	DeletedBy  string    `xorm:"varchar(128) 'deleted_by'"`
+	DeletedAt  time.Time `xorm:"'deleted_at'"`
+	ExpiresAt  time.Time `xorm:"index 'expires_at'"`
+}
+
+// TableName 表名
+func (TrashedService) TableName() string {
+	return "trashed_service"
+}
+
+// SaveTrashedService 将服务放入回收站，同名服务已在回收站中时覆盖
+func (s *Store) SaveTrashedService(trashed *TrashedService) error {
+	existing := new(TrashedService)
+	has, err := s.engine.Where("name = ?", trashed.Name).Get(existing)
+	if err != nil {
+		return fmt.Errorf("failed to query trashed service: %w", err)
+	}
+
+	if !has {
+		if _, err := s.engine.Insert(trashed); err != nil {
+			return fmt.Errorf("failed to insert trashed service: %w", err)
+		}
+		return nil
+	}
+
+	trashed.ID = existing.ID
+	if _, err := s.engine.ID(existing.ID).AllCols().Update(trashed); err != nil {
+		return fmt.Errorf("failed to update trashed service: %w", err)
+	}
+	return nil
+}
+
+// GetTrashedService 获取回收站中的服务，不存在时返回 nil
+func (s *Store) GetTrashedService(name string) (*TrashedService, error) {
+	trashed := new(TrashedService)
+	has, err := s.engine.Where("name = ?", name).Get(trashed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trashed service: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return trashed, nil
+}
+
+// ListTrashedServices 列出回收站中的服务，最近删除的在前
+func (s *Store) ListTrashedServices() ([]*TrashedService, error) {
+	trashed := make([]*TrashedService, 0)
+	if err := s.engine.Desc("deleted_at").Find(&trashed); err != nil {
+		return nil, fmt.Errorf("failed to list trashed services: %w", err)
+	}
+	return trashed, nil
+}
+
+// ListExpiredTrashedServices 列出保留期已过的服务
+func (s *Store) ListExpiredTrashedServices(now time.Time) ([]*TrashedService, error) {
+	trashed := make([]*TrashedService, 0)
+	if err := s.engine.Where("expires_at < ?", s.timeParam(now)).Find(&trashed); err != nil {
+		return nil, fmt.Errorf("failed to list expired trashed services: %w", err)
+	}
+	return trashed, nil
+}
+
+// DeleteTrashedService 从回收站中删除服务，返回是否存在
+func (s *Store) DeleteTrashedService(name string) (bool, error) {
+	affected, err := s.engine.Where("name = ?", name).Delete(new(TrashedService))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete trashed service: %w", err)
+	}
+	return affected > 0, nil
+}
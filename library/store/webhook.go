@@ -0,0 +1,95 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Webhook 事件订阅：服务生命周期事件发生时以 JSON POST 到 URL
+// Secret 为 secret.key 加密后的签名密钥（base64），为空表示不签名
+type Webhook struct {
+	ID             int64     `xorm:"pk autoincr 'id'"`
+	Name           string    `xorm:"varchar(128) notnull unique 'name'"`
+	URL            string    `xorm:"varchar(1024) notnull 'url'"`
+	Secret         string    `xorm:"text 'secret'"`
+	Events         []string  `xorm:"json 'events'"`   // 为空表示全部事件
+	Services       []string  `xorm:"json 'services'"` // 为空表示全部服务
+	Disabled       bool      `xorm:"'disabled'"`
+	LastStatus     int       `xorm:"'last_status'"` // 最近一次投递的 HTTP 状态码，连接失败时为 0
+	LastError      string    `xorm:"varchar(512) 'last_error'"`
+	LastDeliveryAt time.Time `xorm:"'last_delivery_at'"`
+	CreatedAt      time.Time `xorm:"created 'created_at'"`
+	UpdatedAt      time.Time `xorm:"updated 'updated_at'"`
+}
+
+// TableName 表名
+func (Webhook) TableName() string {
+	return "webhook"
+}
+
+// SaveWebhook 保存事件订阅，同名订阅已存在时覆盖配置并保留最近一次投递结果
+func (s *Store) SaveWebhook(webhook *Webhook) error {
+	existing := new(Webhook)
+	has, err := s.engine.Where("name = ?", webhook.Name).Get(existing)
+	if err != nil {
+		return fmt.Errorf("failed to query webhook: %w", err)
+	}
+
+	if !has {
+		if _, err := s.engine.Insert(webhook); err != nil {
+			return fmt.Errorf("failed to insert webhook: %w", err)
+		}
+		return nil
+	}
+
+	webhook.ID = existing.ID
+	webhook.CreatedAt = existing.CreatedAt
+	webhook.LastStatus = existing.LastStatus
+	webhook.LastError = existing.LastError
+	webhook.LastDeliveryAt = existing.LastDeliveryAt
+	if _, err := s.engine.ID(existing.ID).AllCols().Update(webhook); err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+// GetWebhook 获取事件订阅，不存在时返回 nil
+func (s *Store) GetWebhook(name string) (*Webhook, error) {
+	webhook := new(Webhook)
+	has, err := s.engine.Where("name = ?", name).Get(webhook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook: %w", err)
+	}
+	if !has {
+		return nil, nil
+	}
+	return webhook, nil
+}
+
+// ListWebhooks 列出全部事件订阅
+func (s *Store) ListWebhooks() ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0)
+	if err := s.engine.OrderBy("name").Find(&webhooks); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhookDelivery 记录最近一次投递的状态码、错误和时间
+func (s *Store) UpdateWebhookDelivery(name string, status int, lastError string, at time.Time) error {
+	_, err := s.engine.Where("name = ?", name).Cols("last_status", "last_error", "last_delivery_at").
+		Update(&Webhook{LastStatus: status, LastError: lastError, LastDeliveryAt: at})
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// DeleteWebhook 删除事件订阅，返回是否存在
+func (s *Store) DeleteWebhook(name string) (bool, error) {
+	affected, err := s.engine.Where("name = ?", name).Delete(new(Webhook))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return affected > 0, nil
+}
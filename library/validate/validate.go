@@ -0,0 +1,139 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aichy126/onedock/models"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var registerOnce sync.Once
+
+// Register 为请求绑定注册自定义校验（servicename、namespace），字段错误使用 JSON 名称
+// 需在处理请求前调用，重复调用无副作用
+func Register() {
+	registerOnce.Do(func() {
+		engine, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+		// 内置的 port 只支持无符号整数，请求中的端口都是 int
+		engine.RegisterValidation("port", func(fl validator.FieldLevel) bool {
+			field := fl.Field()
+			switch field.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return field.Int() >= 1 && field.Int() <= 65535
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return field.Uint() >= 1 && field.Uint() <= 65535
+			}
+			return false
+		})
+		engine.RegisterValidation("servicename", func(fl validator.FieldLevel) bool {
+			return models.ValidServiceName(fl.Field().String())
+		})
+		engine.RegisterValidation("namespace", func(fl validator.FieldLevel) bool {
+			return models.ValidNamespace(fl.Field().String())
+		})
+	})
+}
+
+// Struct 按 binding 标签校验结构体，用于不经过请求绑定的入口（批量部署、模板部署、克隆、GitOps 等）
+// 校验失败时返回 *models.ValidationError
+func Struct(obj interface{}) error {
+	Register()
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		if validation := Errors(err); validation != nil {
+			return validation
+		}
+		return err
+	}
+	return nil
+}
+
+// Errors 将绑定校验错误转换为字段级错误，不是校验错误时返回 nil
+func Errors(err error) *models.ValidationError {
+	var errs []error
+	var slice binding.SliceValidationError
+	if errors.As(err, &slice) {
+		errs = slice
+	} else {
+		errs = []error{err}
+	}
+
+	result := &models.ValidationError{}
+	for _, err := range errs {
+		var fields validator.ValidationErrors
+		if !errors.As(err, &fields) {
+			return nil
+		}
+		for _, field := range fields {
+			result.Fields = append(result.Fields, models.FieldError{
+				Field:      fieldPath(field),
+				Constraint: field.Tag(),
+				Param:      field.Param(),
+				Value:      fieldValue(field),
+				Message:    fieldMessage(field),
+			})
+		}
+	}
+	if len(result.Fields) == 0 {
+		return nil
+	}
+	return result
+}
+
+// fieldPath 去掉结构体名称的字段路径，如 init_containers[0].image
+func fieldPath(field validator.FieldError) string {
+	path := field.Namespace()
+	if i := strings.Index(path, "."); i >= 0 {
+		path = path[i+1:]
+	}
+	return path
+}
+
+// fieldValue 提交的值，零值不返回
+func fieldValue(field validator.FieldError) interface{} {
+	value := reflect.ValueOf(field.Value())
+	if !value.IsValid() || value.IsZero() {
+		return nil
+	}
+	return field.Value()
+}
+
+// fieldMessage 字段错误的说明
+func fieldMessage(field validator.FieldError) string {
+	name := fieldPath(field)
+	switch field.Tag() {
+	case "required":
+		return name + " is required"
+	case "port":
+		return name + " must be a port between 1 and 65535"
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", name, field.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", name, field.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", name, strings.ReplaceAll(field.Param(), " ", ", "))
+	case "servicename":
+		return name + " must be letters, digits, '_' or '-' (at most 63 characters), optionally prefixed with a namespace"
+	case "namespace":
+		return name + " must be lowercase letters, digits or '-', at most 32 characters"
+	default:
+		return fmt.Sprintf("%s failed on the %s constraint", name, field.Tag())
+	}
+}
@@ -0,0 +1,64 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestStruct 测试按 binding 标签生成字段级错误
+func TestStruct(t *testing.T) {
+	valid := &models.ServiceRequest{Name: "staging.app-a", Image: "nginx", Tag: "alpine", InternalPort: 80, PublicPort: 9300}
+	if err := Struct(valid); err != nil {
+		t.Fatalf("合法请求校验失败: %v", err)
+	}
+
+	req := &models.ServiceRequest{Name: "app a", Tag: "alpine", InternalPort: 70000, PublicPort: -1, DeploymentMode: "canary"}
+	err := Struct(req)
+	var validation *models.ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Struct() = %v, 期望 *models.ValidationError", err)
+	}
+	want := map[string]string{
+		"name":            "servicename",
+		"image":           "required",
+		"internal_port":   "port",
+		"public_port":     "port",
+		"deployment_mode": "oneof",
+	}
+	if len(validation.Fields) != len(want) {
+		t.Fatalf("字段错误 = %+v, 期望 %d 个", validation.Fields, len(want))
+	}
+	for _, field := range validation.Fields {
+		if want[field.Field] != field.Constraint {
+			t.Errorf("字段 %s 的约束 = %s, 期望 %s", field.Field, field.Constraint, want[field.Field])
+		}
+		if field.Message == "" {
+			t.Errorf("字段 %s 缺少错误说明", field.Field)
+		}
+	}
+	for _, field := range validation.Fields {
+		if field.Field == "internal_port" && field.Value != 70000 {
+			t.Errorf("internal_port 的值 = %v, 期望 70000", field.Value)
+		}
+		if field.Field == "image" && field.Value != nil {
+			t.Errorf("空值不应返回, 实际 %v", field.Value)
+		}
+	}
+}
+
+// TestErrorsSlice 测试批量请求的校验错误合并为一个列表，非校验错误返回 nil
+func TestErrorsSlice(t *testing.T) {
+	reqs := []models.ServiceRequest{
+		{Name: "web", Image: "nginx", Tag: "alpine", InternalPort: 80},
+		{Name: "api", Image: "api", Tag: "1.0"},
+	}
+	var validation *models.ValidationError
+	if !errors.As(Struct(reqs), &validation) || len(validation.Fields) != 1 || validation.Fields[0].Field != "internal_port" {
+		t.Fatalf("Errors() = %+v, 期望 internal_port 一个错误", validation)
+	}
+	if Errors(errors.New("unexpected EOF")) != nil {
+		t.Error("非校验错误应返回 nil")
+	}
+}
@@ -1,11 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/log"
 	"github.com/aichy126/onedock/api"
 	"github.com/aichy126/onedock/docs"
+	"github.com/aichy126/onedock/middleware"
 	"github.com/aichy126/onedock/utils"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -27,17 +35,72 @@ import (
 // @description Token as query parameter.
 func main() {
 	igo.App = igo.NewApp("")
-	api.Router(igo.App.Web.Router)
+	onedock := api.Router(igo.App.Web.Router)
 
 	//swagger
 	swaggerShow := utils.ConfGetbool("swaggerui.show")
 	if swaggerShow {
 		InitSwaggerDocs()
 		urlfmt := fmt.Sprintf("%s://%s%s/swagger/doc.json", utils.ConfGetString("swaggerui.protocol"), utils.ConfGetString("swaggerui.host"), utils.ConfGetString("swaggerui.address"))
-		igo.App.Web.Router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL(urlfmt)))
+		igo.App.Web.Router.GET("/swagger/*any", middleware.SwaggerAuth(), ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL(urlfmt)))
 	}
 
-	igo.App.Web.Run()
+	run(onedock)
+}
+
+// defaultShutdownTimeout 默认的优雅退出等待时间（秒）
+const defaultShutdownTimeout = 30
+
+// run 启动 API 服务，收到 SIGINT / SIGTERM 后优雅退出：
+// 先停止接收新的 API 请求并等待进行中的请求完成，再排空端口代理、关闭状态存储，容器保持运行
+func run(onedock *api.Api) {
+	server := &http.Server{
+		Addr:    utils.ConfGetString("local.address"),
+		Handler: igo.App.Web.Router,
+	}
+	tlsConfig, err := utils.ServerTLSConfig()
+	if err != nil {
+		log.Error("Main", log.Any("Error", err), log.Any("Message", "TLS 配置无效"))
+		log.Sync()
+		os.Exit(1)
+	}
+	server.TLSConfig = tlsConfig
+	// Shutdown 不会中断长连接，开始退出时主动结束实时日志流
+	server.RegisterOnShutdown(onedock.CloseStreams)
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Info("Main", log.Any("Address", server.Addr), log.Any("TLS", tlsConfig != nil), log.Any("Version", utils.Version),
+			log.Any("GitCommit", utils.BuildCommit()), log.Any("Message", "API 服务已启动"))
+		if tlsConfig != nil {
+			serveErr <- server.ListenAndServeTLS("", "") // 证书由 TLSConfig.GetCertificate 提供
+		} else {
+			serveErr <- server.ListenAndServe()
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serveErr:
+		log.Error("Main", log.Any("Error", err), log.Any("Message", "API 服务启动失败"))
+		log.Sync()
+		os.Exit(1)
+	case sig := <-quit:
+		log.Info("Main", log.Any("Signal", sig.String()), log.Any("Message", "收到退出信号，开始优雅退出"))
+	}
+
+	timeout := utils.ConfGetIntDefault("local.shutdown_timeout", defaultShutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Error("Main", log.Any("Error", err), log.Any("Message", "等待 API 请求完成超时"))
+	}
+	if err := onedock.Shutdown(ctx); err != nil {
+		log.Error("Main", log.Any("Error", err), log.Any("Message", "关闭服务失败"))
+	}
+	log.Info("Main", log.Any("Message", "OneDock 已退出，容器保持运行"))
+	log.Sync()
 }
 
 // 加载执行程序
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ActorKey gin 上下文中保存操作者标识的键
+	ActorKey = "onedock-actor"
+	// TokenIDKey gin 上下文中保存令牌指纹的键
+	TokenIDKey = "onedock-token-id"
+	// RoleKey gin 上下文中保存令牌角色的键
+	RoleKey = "onedock-role"
+	// ScopeKey gin 上下文中保存令牌服务范围（*models.TokenScope）的键，未限制时不设置
+	ScopeKey = "onedock-scope"
+	// AdminKey gin 上下文中标记管理员令牌的键
+	AdminKey = "onedock-admin"
+	// ActorHeader 调用方可通过该请求头声明操作者名称
+	ActorHeader = "X-Onedock-Actor"
+)
+
+// Actor 返回当前请求的操作者标识，用于部署历史等记录
+// 基础标识为通过验证的令牌（脱敏）或客户端 IP；若请求头声明了操作者名称则附加在前面
+func Actor(c *gin.Context) string {
+	base := c.GetString(ActorKey)
+	if base == "" {
+		base = c.ClientIP()
+	}
+	if name := c.GetHeader(ActorHeader); name != "" {
+		return name + " (" + base + ")"
+	}
+	return base
+}
+
+// maskToken 令牌脱敏，仅保留前 4 位
+func maskToken(token string) string {
+	if len(token) <= 4 {
+		return "token:****"
+	}
+	return "token:" + token[:4] + "****"
+}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAuditMaxBody = 64 << 10 // 审计记录中请求体的默认上限（字节）
+	auditResponseLimit  = 4 << 10  // 解析响应结果时最多读取的字节数
+	redacted            = "******"
+)
+
+// sensitiveKeys 请求体中需要脱敏的字段（不区分大小写，包含即匹配）
+var sensitiveKeys = []string{"password", "secret", "token", "credential"}
+
+// auditWriter 记录响应开头的一段内容，用于解析业务码和错误信息
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(data []byte) (int, error) {
+	if remain := auditResponseLimit - w.body.Len(); remain > 0 {
+		if len(data) < remain {
+			remain = len(data)
+		}
+		w.body.Write(data[:remain])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Audit 记录修改类请求（POST、PUT、PATCH、DELETE）的操作者、请求体和结果，由 record 持久化；
+// 只读取数据的 POST 接口（readRoutes）不记录。需注册在 Auth 之前，这样权限验证失败的请求同样会被记录
+func Audit(record func(*models.AuditRecord)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case "POST", "PUT", "PATCH", "DELETE":
+		default:
+			c.Next()
+			return
+		}
+		if isReadRequest(c.Request.Method, c.FullPath()) {
+			c.Next()
+			return
+		}
+		if !utils.ConfGetboolDefault("audit.enabled", true) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		body := auditBody(c)
+		writer := &auditWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		entry := &models.AuditRecord{
+			Actor:      Actor(c),
+			TokenID:    c.GetString(TokenIDKey),
+			RequestID:  c.GetString(RequestIDKey),
+			ClientIP:   c.ClientIP(),
+			Method:     c.Request.Method,
+			Path:       auditPath(c.Request.URL),
+			Service:    c.Param("name"),
+			Body:       body,
+			Status:     writer.Status(),
+			DurationMs: time.Since(start).Milliseconds(),
+			CreatedAt:  start,
+		}
+		var response struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		if json.Unmarshal(writer.body.Bytes(), &response) == nil {
+			entry.Code = response.Code
+			if response.Code != 0 {
+				entry.Error = response.Msg
+			}
+		}
+		record(entry)
+	}
+}
+
+// auditBody 审计记录中的请求体，最多读取 audit.max_body 字节
+func auditBody(c *gin.Context) string {
+	limit := utils.ConfGetIntDefault("audit.max_body", defaultAuditMaxBody)
+	if limit <= 0 {
+		limit = defaultAuditMaxBody
+	}
+	return readAuditBody(c.Request, c.ContentType(), c.FullPath(), limit)
+}
+
+// readAuditBody JSON 请求体脱敏后返回，其他格式只记录大小，不读取请求体
+// 最多读取 limit 字节，超出时只记录大小；读取的部分放回请求体，后续处理仍能读到完整内容
+func readAuditBody(req *http.Request, contentType, route string, limit int) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	if contentType != "" && contentType != "application/json" {
+		return bodySummary(contentType, req.ContentLength)
+	}
+
+	data, err := peekBody(req, int64(limit)+1)
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+	if len(data) > limit {
+		return bodySummary("application/json", req.ContentLength) + " (exceeds audit.max_body)"
+	}
+	return redactBody(route, data)
+}
+
+// peekBody 读取请求体的前 limit 字节并放回，未读取的部分仍由原请求体提供
+func peekBody(req *http.Request, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(req.Body, limit))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), req.Body), req.Body}
+	return data, err
+}
+
+// bodySummary 只记录请求体的格式和大小，大小未知（分块传输）时不记录
+func bodySummary(contentType string, size int64) string {
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if size < 0 {
+		return "<" + contentType + ">"
+	}
+	return "<" + contentType + ", " + strconv.FormatInt(size, 10) + " bytes>"
+}
+
+// redactBody 脱敏 JSON 请求体：密钥接口的 value，以及名称包含 password、secret、token、credential 的字段
+func redactBody(route string, data []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return string(data)
+	}
+	if strings.HasPrefix(strings.TrimPrefix(route, "/v1"), "/onedock/secrets/") {
+		if m, ok := value.(map[string]interface{}); ok {
+			if _, has := m["value"]; has {
+				m["value"] = redacted
+			}
+		}
+	}
+	redactValue(value)
+	out, err := json.Marshal(value)
+	if err != nil {
+		return string(data)
+	}
+	return string(out)
+}
+
+func redactValue(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				if _, isString := item.(string); isString {
+					v[key] = redacted
+					continue
+				}
+			}
+			redactValue(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactValue(item)
+		}
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// auditPath 请求路径，查询参数中的 token 脱敏
+func auditPath(u *url.URL) string {
+	query := u.Query()
+	if query.Has("token") {
+		query.Set("token", redacted)
+		return u.Path + "?" + query.Encode()
+	}
+	if u.RawQuery != "" {
+		return u.Path + "?" + u.RawQuery
+	}
+	return u.Path
+}
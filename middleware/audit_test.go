@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestRedactBody 测试请求体脱敏
+func TestRedactBody(t *testing.T) {
+	body := redactBody("/onedock/secrets/:secret", []byte(`{"value":"s3cret"}`))
+	if strings.Contains(body, "s3cret") {
+		t.Errorf("密钥值未脱敏: %s", body)
+	}
+
+	body = redactBody("/v1/onedock/secrets/:secret", []byte(`{"value":"s3cret"}`))
+	if strings.Contains(body, "s3cret") {
+		t.Errorf("带版本路径的密钥值未脱敏: %s", body)
+	}
+
+	body = redactBody("/onedock/", []byte(`{"name":"web","environment":{"DB_PASSWORD":"p@ss","LOG_LEVEL":"info"},"value":"kept"}`))
+	if strings.Contains(body, "p@ss") || !strings.Contains(body, `"LOG_LEVEL":"info"`) || !strings.Contains(body, `"value":"kept"`) {
+		t.Errorf("redactBody = %s, 期望只脱敏 DB_PASSWORD", body)
+	}
+
+	if body := redactBody("/onedock/", []byte("not json")); body != "not json" {
+		t.Errorf("非 JSON 请求体 = %q, 期望原样返回", body)
+	}
+}
+
+// TestAuditPath 测试查询参数中的令牌脱敏
+func TestAuditPath(t *testing.T) {
+	u, _ := url.Parse("/onedock/web/scale?token=abcdef&dry_run=true")
+	if path := auditPath(u); strings.Contains(path, "abcdef") || !strings.Contains(path, "dry_run=true") {
+		t.Errorf("auditPath = %q", path)
+	}
+	u, _ = url.Parse("/onedock/system/cleanup?dry_run=true")
+	if path := auditPath(u); path != "/onedock/system/cleanup?dry_run=true" {
+		t.Errorf("auditPath = %q", path)
+	}
+}
+
+// unreadBody 记录是否被读取的请求体
+type unreadBody struct {
+	io.Reader
+	read bool
+}
+
+func (b *unreadBody) Read(p []byte) (int, error) {
+	b.read = true
+	return b.Reader.Read(p)
+}
+
+func (b *unreadBody) Close() error { return nil }
+
+// TestReadAuditBody 测试审计只读取上限内的 JSON 请求体，其他格式不读取，后续处理仍能读到完整请求体
+func TestReadAuditBody(t *testing.T) {
+	small := `{"name":"nginx-web","password":"p@ss"}`
+	req := httptest.NewRequest("POST", "/onedock/", strings.NewReader(small))
+	if body := readAuditBody(req, "application/json", "/onedock/", 1024); strings.Contains(body, "p@ss") || !strings.Contains(body, "nginx-web") {
+		t.Errorf("审计请求体 = %s, 期望脱敏后的 JSON", body)
+	}
+	if rest, _ := io.ReadAll(req.Body); string(rest) != small {
+		t.Errorf("放回的请求体 = %q", rest)
+	}
+
+	large := `{"name":"` + strings.Repeat("a", 4096) + `"}`
+	req = httptest.NewRequest("POST", "/onedock/", strings.NewReader(large))
+	if body := readAuditBody(req, "application/json", "/onedock/", 1024); body != "<application/json, 4107 bytes> (exceeds audit.max_body)" {
+		t.Errorf("超出上限时审计请求体 = %s", body)
+	}
+	if rest, _ := io.ReadAll(req.Body); string(rest) != large {
+		t.Errorf("超出上限时放回的请求体长度 = %d, 期望 %d", len(rest), len(large))
+	}
+
+	archive := &unreadBody{Reader: strings.NewReader("backup")}
+	req = httptest.NewRequest("POST", "/onedock/system/restore", nil)
+	req.Body, req.ContentLength = archive, 6
+	if body := readAuditBody(req, "application/gzip", "/onedock/system/restore", 1024); body != "<application/gzip, 6 bytes>" || archive.read {
+		t.Errorf("非 JSON 请求体 = %s, 是否被读取 = %v, 期望只记录大小", body, archive.read)
+	}
+}
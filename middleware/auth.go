@@ -1,14 +1,19 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aichy126/igo/util"
+	"github.com/aichy126/onedock/models"
 	"github.com/aichy126/onedock/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func Auth() gin.HandlerFunc {
+// Auth 权限验证，lookup 用于查找通过接口创建的令牌，可为 nil
+func Auth(lookup TokenLookup) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 检查是否启用权限验证
 		if !utils.ConfGetbool("auth.enabled") {
@@ -26,20 +31,40 @@ func Auth() gin.HandlerFunc {
 			}
 		}
 
-		// 从多个位置获取 token
-		token := extractToken(c)
-		if token == "" {
-			utils.Rfail(c, "权限验证失败：缺少访问令牌")
+		// 带签名的请求按签名验证；否则从多个位置获取 token，没有 token 时使用 OIDC 登录的会话
+		var identity *tokenIdentity
+		var err error
+		if c.GetHeader(SignatureHeader) != "" {
+			identity, err = signedIdentity(c)
+		} else if token := extractToken(c); token != "" {
+			identity, err = authenticate(token, lookup)
+		} else {
+			identity, err = sessionIdentity(c)
+		}
+		if err != nil {
+			utils.RfailCode(c, utils.CodeUnauthorized, "权限验证失败："+err.Error(), nil)
 			c.Abort()
 			return
 		}
-
-		// 验证 token
-		if !isValidToken(token) {
-			utils.Rfail(c, "权限验证失败：无效的访问令牌")
+		// 只读令牌只能查询
+		if !roleAllows(identity.role, RoleDeployer) && !isReadRequest(c.Request.Method, c.FullPath()) {
+			utils.RfailCode(c, utils.CodeForbidden, "权限验证失败：只读令牌不能执行修改操作", nil)
+			c.Abort()
+			return
+		}
+		c.Set(ActorKey, identity.actor)
+		c.Set(TokenIDKey, identity.id)
+		c.Set(RoleKey, identity.role)
+		c.Set(AdminKey, identity.role == RoleAdmin)
+		// 受限令牌只能访问范围内的服务
+		if err := checkScope(identity.scope, c.Request.Method, c.FullPath(), c.Param("name")); err != nil {
+			utils.RfailCode(c, utils.CodeForbidden, "权限验证失败："+err.Error(), nil)
 			c.Abort()
 			return
 		}
+		if identity.scope.Restricted() {
+			c.Set(ScopeKey, identity.scope)
+		}
 
 		c.Next()
 	}
@@ -72,15 +97,50 @@ func extractToken(c *gin.Context) string {
 	return ""
 }
 
-// isValidToken 验证 token 是否有效
-func isValidToken(token string) bool {
-	validTokens := getValidTokens()
-	for _, validToken := range validTokens {
-		if token == validToken {
-			return true
+// tokenIdentity 通过验证的令牌
+type tokenIdentity struct {
+	role  string
+	actor string // 操作者标识，静态令牌为脱敏后的令牌，JWT 为 jwt:<sub>
+	id    string // 令牌指纹，用于限流、配额和端口登记
+	scope *models.TokenScope
+}
+
+// errInvalidToken 令牌无效
+var errInvalidToken = errors.New("无效的访问令牌")
+
+// jwtKeys 验证 JWT 使用的公钥缓存
+var jwtKeys = &jwksCache{}
+
+// authenticate 验证令牌：依次查配置文件中的令牌、接口创建的令牌，开启 auth.jwt 时再按 JWT 验证
+func authenticate(token string, lookup TokenLookup) (*tokenIdentity, error) {
+	if role := tokenRole(token); role != "" {
+		return &tokenIdentity{role: role, actor: maskToken(token), id: utils.TokenID(token), scope: configTokenScope(token)}, nil
+	}
+	if lookup != nil {
+		if role, scope := lookup(token); role != "" {
+			return &tokenIdentity{role: role, actor: maskToken(token), id: utils.TokenID(token), scope: scope}, nil
 		}
 	}
-	return false
+	cfg := loadJWTConfig()
+	if cfg == nil || !looksLikeJWT(token) {
+		return nil, errInvalidToken
+	}
+	claims, err := cfg.verify(token, jwtKeys, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("无效的 JWT：%w", err)
+	}
+	role, err := cfg.role(claims)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 JWT：%w", err)
+	}
+	subject := "jwt:" + claims.stringClaim("sub")
+	scope := &models.TokenScope{Services: claims.stringsClaim("services"), Namespaces: claims.stringsClaim("namespaces")}
+	return &tokenIdentity{role: role, actor: subject, id: utils.TokenID(subject), scope: scope}, nil
+}
+
+// isAdminToken 是否为管理员令牌（auth.admin_tokens），管理员可以操作已锁定的服务
+func isAdminToken(token string) bool {
+	return containsToken(util.ConfGetStringSlice("auth.admin_tokens"), token)
 }
 
 // getValidTokens 从配置中获取有效的 token 列表
@@ -107,5 +167,5 @@ func getWhitelistPaths() []string {
 	}
 
 	// 默认白名单（ping 接口用于健康检查）
-	return []string{"/onedock/ping"}
+	return []string{"/onedock/ping", "/v1/onedock/ping"}
 }
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// SignatureKeyHeader 签名使用的密钥 ID
+	SignatureKeyHeader = "X-Onedock-Key"
+	// SignatureTimestampHeader 签名时间（Unix 秒）
+	SignatureTimestampHeader = "X-Onedock-Timestamp"
+	// SignatureHeader 请求签名：sha256=<hex>
+	SignatureHeader = "X-Onedock-Signature"
+
+	defaultSignatureMaxSkew = 300      // 默认允许的签名时间偏差（秒）
+	defaultSignatureMaxBody = 32 << 20 // 默认签名请求的请求体上限（字节）
+	signaturePrefix         = "sha256="
+)
+
+// hmacKey 签名密钥（[[auth.hmac_keys]]）
+type hmacKey struct {
+	ID                string `mapstructure:"id"`
+	Secret            string `mapstructure:"secret"`
+	Role              string `mapstructure:"role"`
+	models.TokenScope `mapstructure:",squash"`
+}
+
+// hmacKeys 读取签名密钥，每次读取以便配置热加载
+func hmacKeys() []hmacKey {
+	var keys []hmacKey
+	if err := igo.App.Conf.UnmarshalKey("auth.hmac_keys", &keys); err != nil {
+		log.Error("Auth", log.Any("Error", err), log.Any("Message", "解析签名密钥失败"))
+	}
+	return keys
+}
+
+// signRequest 计算请求签名：HMAC-SHA256(secret, 时间戳\n方法\n路径与查询参数\n请求体)
+// 签名覆盖方法和路径，截获的签名不能用于其他接口
+func signRequest(secret, timestamp, method, uri string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + method + "\n" + uri + "\n"))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// replayCache 记录时间窗口内已使用的签名，拒绝重放
+type replayCache struct {
+	mutex     sync.Mutex
+	seen      map[string]time.Time // 签名 -> 过期时间
+	lastSweep time.Time
+}
+
+// use 记录签名，窗口内已使用过时返回 false
+func (r *replayCache) use(signature string, expires, now time.Time) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.seen == nil {
+		r.seen = make(map[string]time.Time)
+	}
+	if now.Sub(r.lastSweep) >= time.Minute {
+		for k, exp := range r.seen {
+			if now.After(exp) {
+				delete(r.seen, k)
+			}
+		}
+		r.lastSweep = now
+	}
+	if exp, ok := r.seen[signature]; ok && !now.After(exp) {
+		return false
+	}
+	r.seen[signature] = expires
+	return true
+}
+
+// signedRequests 已验证的签名，用于防重放
+var signedRequests = &replayCache{}
+
+// errNotSigned 请求未携带签名
+var errNotSigned = errors.New("request is not signed")
+
+// verifySignature 校验请求签名：密钥存在、时间戳在允许偏差内、签名匹配且未被使用过
+func verifySignature(keys []hmacKey, keyID, timestamp, signature, method, uri string, body []byte,
+	maxSkew time.Duration, replays *replayCache, now time.Time) (*hmacKey, error) {
+	key, signedAt, err := signingKey(keys, keyID, timestamp, signature, maxSkew, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSignature(key, signedAt, timestamp, signature, method, uri, body, maxSkew, replays, now); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// signingKey 查找签名密钥并检查时间戳，不需要请求体，在读取请求体之前完成
+func signingKey(keys []hmacKey, keyID, timestamp, signature string, maxSkew time.Duration, now time.Time) (*hmacKey, time.Time, error) {
+	if signature == "" {
+		return nil, time.Time{}, errNotSigned
+	}
+	var key *hmacKey
+	for i := range keys {
+		if keys[i].ID == keyID && keys[i].Secret != "" {
+			key = &keys[i]
+			break
+		}
+	}
+	if key == nil {
+		return nil, time.Time{}, fmt.Errorf("unknown signing key %q", keyID)
+	}
+	if roleLevels[key.Role] == 0 {
+		return nil, time.Time{}, fmt.Errorf("signing key %q has no valid role", keyID)
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid %s", SignatureTimestampHeader)
+	}
+	signedAt := time.Unix(seconds, 0)
+	if signedAt.Before(now.Add(-maxSkew)) || signedAt.After(now.Add(maxSkew)) {
+		return nil, time.Time{}, fmt.Errorf("signature timestamp is outside the allowed window of %s", maxSkew)
+	}
+	return key, signedAt, nil
+}
+
+// checkSignature 校验签名与请求内容匹配且未被使用过
+func checkSignature(key *hmacKey, signedAt time.Time, timestamp, signature, method, uri string, body []byte,
+	maxSkew time.Duration, replays *replayCache, now time.Time) error {
+	expected := signRequest(key.Secret, timestamp, method, uri, body)
+	if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	// 时间戳之外的重放由签名记录拒绝，记录保留到时间戳失效为止
+	if !replays.use(expected, signedAt.Add(maxSkew), now) {
+		return fmt.Errorf("signature has already been used")
+	}
+	return nil
+}
+
+// signedIdentity 校验签名请求：先检查密钥和时间戳，再读取不超过 auth.signature_max_body 的请求体，读取的请求体放回供后续处理
+func signedIdentity(c *gin.Context) (*tokenIdentity, error) {
+	maxSkew := time.Duration(utils.ConfGetIntDefault("auth.signature_max_skew", defaultSignatureMaxSkew)) * time.Second
+	timestamp, signature := c.GetHeader(SignatureTimestampHeader), c.GetHeader(SignatureHeader)
+	now := time.Now()
+	key, signedAt, err := signingKey(hmacKeys(), c.GetHeader(SignatureKeyHeader), timestamp, signature, maxSkew, now)
+	if err != nil {
+		return nil, fmt.Errorf("无效的请求签名：%w", err)
+	}
+
+	body, err := signedBody(c.Request, int64(utils.ConfGetIntDefault("auth.signature_max_body", defaultSignatureMaxBody)))
+	if err != nil {
+		return nil, fmt.Errorf("无效的请求签名：%w", err)
+	}
+	if err := checkSignature(key, signedAt, timestamp, signature, c.Request.Method, c.Request.URL.RequestURI(), body,
+		maxSkew, signedRequests, now); err != nil {
+		return nil, fmt.Errorf("无效的请求签名：%w", err)
+	}
+	actor := "hmac:" + key.ID
+	return &tokenIdentity{role: key.Role, actor: actor, id: utils.TokenID(actor), scope: &key.TokenScope}, nil
+}
+
+// signedBody 读取签名请求的请求体并放回，超过 limit 字节时拒绝
+func signedBody(req *http.Request, limit int64) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = defaultSignatureMaxBody
+	}
+	if req.ContentLength > limit {
+		return nil, fmt.Errorf("request body exceeds %d bytes", limit)
+	}
+	data, err := peekBody(req, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("request body exceeds %d bytes", limit)
+	}
+	return data, nil
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestVerifySignature 测试请求签名的校验与防重放
+func TestVerifySignature(t *testing.T) {
+	keys := []hmacKey{{ID: "ci", Secret: "s3cret", Role: RoleDeployer}, {ID: "norole", Secret: "x"}}
+	now := time.Now()
+	ts := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"name":"nginx-web","image":"nginx"}`)
+	signature := signRequest("s3cret", ts, "POST", "/onedock/", body)
+	replays := &replayCache{}
+
+	key, err := verifySignature(keys, "ci", ts, signature, "POST", "/onedock/", body, time.Minute, replays, now)
+	if err != nil || key.Role != RoleDeployer {
+		t.Fatalf("verifySignature() = %+v, %v", key, err)
+	}
+	if _, err := verifySignature(keys, "ci", ts, signature, "POST", "/onedock/", body, time.Minute, replays, now); err == nil {
+		t.Error("重放的签名应被拒绝")
+	}
+
+	old := strconv.FormatInt(now.Add(-2*time.Minute).Unix(), 10)
+	tests := []struct {
+		name                       string
+		keyID, timestamp, sig, uri string
+		body                       []byte
+	}{
+		{"未签名", "ci", ts, "", "/onedock/", body},
+		{"未知密钥", "other", ts, signRequest("s3cret", ts, "POST", "/onedock/", body), "/onedock/", body},
+		{"密钥没有角色", "norole", ts, signRequest("x", ts, "POST", "/onedock/", body), "/onedock/", body},
+		{"时间戳过旧", "ci", old, signRequest("s3cret", old, "POST", "/onedock/", body), "/onedock/", body},
+		{"时间戳无效", "ci", "abc", signRequest("s3cret", "abc", "POST", "/onedock/", body), "/onedock/", body},
+		{"请求体被篡改", "ci", ts, signRequest("s3cret", ts, "POST", "/onedock/", body), "/onedock/", []byte(`{"name":"evil"}`)},
+		{"用于其他接口", "ci", ts, signRequest("s3cret", ts, "POST", "/onedock/", body), "/onedock/nginx-web/scale", body},
+	}
+	for _, tt := range tests {
+		if _, err := verifySignature(keys, tt.keyID, tt.timestamp, tt.sig, "POST", tt.uri, tt.body, time.Minute, &replayCache{}, now); err == nil {
+			t.Errorf("%s: 应验证失败", tt.name)
+		}
+	}
+}
+
+// TestReplayCache 测试签名记录在过期后清理
+func TestReplayCache(t *testing.T) {
+	var cache replayCache
+	now := time.Now()
+	if !cache.use("sig", now.Add(time.Minute), now) {
+		t.Fatal("首次使用应成功")
+	}
+	if cache.use("sig", now.Add(time.Minute), now.Add(30*time.Second)) {
+		t.Error("窗口内重复使用应失败")
+	}
+	if !cache.use("sig", now.Add(3*time.Minute), now.Add(2*time.Minute)) {
+		t.Error("过期后的签名记录应被清理")
+	}
+	if len(cache.seen) != 1 {
+		t.Errorf("记录数 = %d, 期望 1", len(cache.seen))
+	}
+}
+
+// TestSignedBody 测试签名请求的请求体上限，读取的请求体放回供后续处理
+func TestSignedBody(t *testing.T) {
+	req := httptest.NewRequest("POST", "/onedock/", strings.NewReader(`{"name":"nginx-web"}`))
+	body, err := signedBody(req, 64)
+	if err != nil || string(body) != `{"name":"nginx-web"}` {
+		t.Fatalf("signedBody() = %q, %v", body, err)
+	}
+	if rest, _ := io.ReadAll(req.Body); string(rest) != string(body) {
+		t.Errorf("放回的请求体 = %q", rest)
+	}
+
+	req = httptest.NewRequest("POST", "/onedock/system/restore", strings.NewReader(strings.Repeat("x", 100)))
+	if _, err := signedBody(req, 64); err == nil {
+		t.Error("Content-Length 超出上限时应拒绝")
+	}
+	req.ContentLength = -1 // 分块传输时按实际读取的长度判断
+	if _, err := signedBody(req, 64); err == nil {
+		t.Error("请求体超出上限时应拒绝")
+	}
+}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"      // 幂等键请求头
+	IdempotentReplayedHeader  = "Idempotent-Replayed"  // 重放保存的结果时设置为 true
+	defaultIdempotencyTTL     = 86400                  // 幂等键的默认保留时间（秒）
+	defaultIdempotencyMaxBody = 1 << 20                // 带幂等键的请求体默认上限（字节）
+	maxIdempotencyKeyLength   = 255                    // 幂等键的最大长度
+	maxIdempotencyResponse    = 1 << 20                // 超过该大小的响应不保存（字节）
+	idempotencyCacheKeyPrefix = "onedock:idempotency:" // 缓存键前缀
+)
+
+// IdempotencyStore 保存幂等请求的结果，library/cache 的缓存均实现该接口
+type IdempotencyStore interface {
+	Get(ctx context.IContext, key string, value interface{}) error
+	Set(ctx context.IContext, key string, value interface{}, seconds int) error
+}
+
+// idempotencyRecord 保存的请求结果
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"` // 请求方法、路径和请求体的摘要
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// idempotencyWriter 记录完整响应，超过 maxIdempotencyResponse 时放弃保存
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	if !w.overflow {
+		if w.body.Len()+len(data) > maxIdempotencyResponse {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(data)
+		}
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Idempotency 支持 Idempotency-Key 请求头：同一令牌以相同的键重复请求时，直接返回第一次成功的结果而不再执行，
+// 避免客户端重试时重复部署或扩缩容。只保存成功（code 为 0）的结果，失败的请求可以用同一个键重试；
+// 同一个键用于不同的请求（方法、路径或请求体不同）时返回 INVALID_REQUEST，第一次请求仍在执行时返回 CONFLICT。
+// 结果保存在 store 中 idempotency.ttl 秒，使用 Redis 缓存时多个实例共享；请求体超过 idempotency.max_body 字节时返回 INVALID_REQUEST
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return idempotency(store, func() int {
+		return utils.ConfGetIntDefault("idempotency.ttl", defaultIdempotencyTTL)
+	}, func() int {
+		limit := utils.ConfGetIntDefault("idempotency.max_body", defaultIdempotencyMaxBody)
+		if limit <= 0 {
+			limit = defaultIdempotencyMaxBody
+		}
+		return limit
+	})
+}
+
+// idempotency 幂等键中间件，ttl 返回结果的保留时间（秒），maxBody 返回请求体的上限（字节）
+func idempotency(store IdempotencyStore, ttl func() int, maxBody func() int) gin.HandlerFunc {
+	var mutex sync.Mutex
+	inflight := make(map[string]bool)
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			utils.RfailCode(c, utils.CodeInvalidRequest, "Idempotency-Key must not be longer than 255 characters", nil)
+			c.Abort()
+			return
+		}
+
+		// 请求体用于计算摘要，最多读取 maxBody 字节，读取的部分放回请求体
+		var body []byte
+		if c.Request.Body != nil {
+			limit := maxBody()
+			var err error
+			if body, err = peekBody(c.Request, int64(limit)+1); err != nil {
+				utils.RfailCode(c, utils.CodeInvalidRequest, "failed to read request body: "+err.Error(), nil)
+				c.Abort()
+				return
+			}
+			if len(body) > limit {
+				utils.RfailCode(c, utils.CodeInvalidRequest, fmt.Sprintf("request body with Idempotency-Key must not be larger than %d bytes", limit), nil)
+				c.Abort()
+				return
+			}
+		}
+		cacheKey := idempotencyCacheKey(idempotencyOwner(c), key)
+		fingerprint := idempotencyFingerprint(c.Request.Method, c.Request.URL.RequestURI(), body)
+		ctx := context.Background()
+
+		mutex.Lock()
+		if inflight[cacheKey] {
+			mutex.Unlock()
+			utils.RfailCode(c, utils.CodeConflict, "a request with the same Idempotency-Key is still in progress", nil)
+			c.Abort()
+			return
+		}
+		var record idempotencyRecord
+		if store.Get(ctx, cacheKey, &record) == nil && record.Fingerprint != "" {
+			mutex.Unlock()
+			if record.Fingerprint != fingerprint {
+				utils.RfailCode(c, utils.CodeInvalidRequest, "Idempotency-Key has already been used for a different request", nil)
+				c.Abort()
+				return
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(record.Status, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+		inflight[cacheKey] = true
+		mutex.Unlock()
+		defer func() {
+			mutex.Lock()
+			delete(inflight, cacheKey)
+			mutex.Unlock()
+		}()
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if writer.overflow || !idempotencySucceeded(writer.Status(), writer.body.Bytes()) {
+			return
+		}
+		record = idempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      writer.Status(),
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}
+		if err := store.Set(ctx, cacheKey, &record, ttl()); err != nil {
+			log.Error("Idempotency", log.Any("Error", err), log.Any("Path", c.Request.URL.Path), log.Any("Message", "保存幂等请求结果失败"))
+		}
+	}
+}
+
+// idempotencyOwner 幂等键的归属：令牌指纹，没有令牌（如 OIDC 会话）时为操作者，未启用权限验证时为客户端 IP
+// 不使用 X-Actor 请求头，客户端重试时修改该请求头不影响幂等
+func idempotencyOwner(c *gin.Context) string {
+	if id := c.GetString(TokenIDKey); id != "" {
+		return id
+	}
+	if actor := c.GetString(ActorKey); actor != "" {
+		return actor
+	}
+	return c.ClientIP()
+}
+
+// idempotencyCacheKey 幂等键按归属隔离，不同令牌使用相同的键互不影响
+func idempotencyCacheKey(owner, key string) string {
+	sum := sha256.Sum256([]byte(owner + "\n" + key))
+	return idempotencyCacheKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// idempotencyFingerprint 请求的摘要，用于识别同一个键被用于不同的请求
+func idempotencyFingerprint(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + uri + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencySucceeded 响应是否为成功结果：HTTP 2xx 且业务码为 0
+func idempotencySucceeded(status int, body []byte) bool {
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return false
+	}
+	var response struct {
+		Code *int `json:"code"`
+	}
+	return json.Unmarshal(body, &response) == nil && response.Code != nil && *response.Code == 0
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aichy126/igo/context"
+	"github.com/gin-gonic/gin"
+)
+
+// memoryIdempotencyStore 测试用的结果存储
+type memoryIdempotencyStore map[string][]byte
+
+func (s memoryIdempotencyStore) Get(ctx context.IContext, key string, value interface{}) error {
+	data, ok := s[key]
+	if !ok {
+		return fmt.Errorf("not found")
+	}
+	return json.Unmarshal(data, value)
+}
+
+func (s memoryIdempotencyStore) Set(ctx context.IContext, key string, value interface{}, seconds int) error {
+	data, err := json.Marshal(value)
+	s[key] = data
+	return err
+}
+
+// TestIdempotency 测试相同幂等键的请求只执行一次
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := memoryIdempotencyStore{}
+	executed := 0
+	r := gin.New()
+	r.POST("/:name/scale", func(c *gin.Context) { c.Set(TokenIDKey, c.GetHeader("X-Token")) }, idempotency(store, func() int { return 60 }, func() int { return 64 }), func(c *gin.Context) {
+		executed++
+		if c.Query("fail") == "true" {
+			c.JSON(http.StatusOK, gin.H{"code": 1, "error_code": "SERVICE_BUSY", "msg": "busy"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"code": 0, "data": gin.H{"run": executed}, "msg": "succeed"})
+	})
+	request := func(path, token, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("X-Token", token)
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := request("/web/scale", "a", "key-1", `{"replicas":3}`)
+	retry := request("/web/scale", "a", "key-1", `{"replicas":3}`)
+	if executed != 1 || retry.Body.String() != first.Body.String() || retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("重复请求应返回第一次的结果: 执行 %d 次, %s / %s", executed, first.Body, retry.Body)
+	}
+
+	// 同一个键用于不同的请求
+	if w := request("/web/scale", "a", "key-1", `{"replicas":5}`); !strings.Contains(w.Body.String(), "INVALID_REQUEST") || executed != 1 {
+		t.Errorf("不同请求体应被拒绝: %s", w.Body)
+	}
+	// 其他令牌使用相同的键互不影响；没有幂等键时每次都执行
+	request("/web/scale", "b", "key-1", `{"replicas":3}`)
+	request("/web/scale", "a", "", `{"replicas":3}`)
+	request("/web/scale", "a", "", `{"replicas":3}`)
+	if executed != 4 {
+		t.Errorf("执行次数 = %d, 期望 4", executed)
+	}
+
+	// 失败的结果不保存，可以用同一个键重试
+	request("/web/scale?fail=true", "a", "key-2", "")
+	request("/web/scale?fail=true", "a", "key-2", "")
+	if executed != 6 {
+		t.Errorf("失败的请求应可以重试, 执行次数 = %d, 期望 6", executed)
+	}
+
+	if w := request("/web/scale", "a", strings.Repeat("k", 256), ""); !strings.Contains(w.Body.String(), "INVALID_REQUEST") {
+		t.Errorf("过长的幂等键应被拒绝: %s", w.Body)
+	}
+
+	// 请求体超过上限时不读取完整内容，直接拒绝
+	if w := request("/web/scale", "a", "key-3", `{"replicas":3,"note":"`+strings.Repeat("x", 64)+`"}`); !strings.Contains(w.Body.String(), "INVALID_REQUEST") || executed != 6 {
+		t.Errorf("过大的请求体应被拒绝: %s", w.Body)
+	}
+}
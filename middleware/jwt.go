@@ -0,0 +1,306 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aichy126/onedock/utils"
+)
+
+const (
+	defaultJWTRoleClaim = "role"           // 默认从 role 声明读取角色
+	defaultJWTLeeway    = 30               // 默认允许的时钟偏差（秒）
+	jwksCacheTTL        = time.Hour        // JWKS 缓存时间
+	jwksMinRefresh      = time.Minute      // 遇到未知 kid 时重新获取 JWKS 的最小间隔
+	jwksFetchTimeout    = 10 * time.Second // 获取 JWKS 的超时
+	jwksMaxSize         = 1 << 20          // JWKS 响应的大小上限
+)
+
+// jwtAlgorithms 支持的签名算法及其摘要算法
+var jwtAlgorithms = map[string]crypto.Hash{
+	"HS256": crypto.SHA256,
+	"HS384": crypto.SHA384,
+	"HS512": crypto.SHA512,
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+}
+
+// jwtConfig JWT 验证配置（auth.jwt）
+type jwtConfig struct {
+	secret      string        // HMAC 密钥，用于 HS256/384/512
+	jwksURL     string        // RSA 公钥集合地址，用于 RS256/384/512
+	issuer      string        // 要求的 iss，为空不检查
+	audience    string        // 要求的 aud，为空不检查
+	roleClaim   string        // 读取角色的声明
+	defaultRole string        // 未携带角色声明时的角色，不是有效角色（如 none）时拒绝
+	leeway      time.Duration // 检查 exp、nbf 时允许的时钟偏差
+}
+
+// loadJWTConfig 读取 JWT 验证配置，未开启时返回 nil
+func loadJWTConfig() *jwtConfig {
+	if !utils.ConfGetbool("auth.jwt.enabled") {
+		return nil
+	}
+	cfg := &jwtConfig{
+		secret:      utils.ConfGetString("auth.jwt.secret"),
+		jwksURL:     utils.ConfGetString("auth.jwt.jwks_url"),
+		issuer:      utils.ConfGetString("auth.jwt.issuer"),
+		audience:    utils.ConfGetString("auth.jwt.audience"),
+		roleClaim:   utils.ConfGetString("auth.jwt.role_claim"),
+		defaultRole: utils.ConfGetString("auth.jwt.default_role"),
+		leeway:      time.Duration(utils.ConfGetIntDefault("auth.jwt.leeway", defaultJWTLeeway)) * time.Second,
+	}
+	if cfg.roleClaim == "" {
+		cfg.roleClaim = defaultJWTRoleClaim
+	}
+	if cfg.defaultRole == "" {
+		cfg.defaultRole = RoleReadOnly
+	}
+	return cfg
+}
+
+// looksLikeJWT 令牌是否为 JWT 格式（三段 base64url）
+func looksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// jwtClaims JWT 的声明
+type jwtClaims map[string]interface{}
+
+// stringClaim 读取字符串声明
+func (claims jwtClaims) stringClaim(name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// timeClaim 读取时间声明（Unix 秒），不存在时返回 false
+func (claims jwtClaims) timeClaim(name string) (time.Time, bool) {
+	value, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(value), 0), true
+}
+
+// stringsClaim 读取字符串或字符串数组声明
+func (claims jwtClaims) stringsClaim(name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// verify 校验 JWT 的签名和声明，返回声明
+func (cfg *jwtConfig) verify(token string, keys *jwksCache, now time.Time) (jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	hash, ok := jwtAlgorithms[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding")
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+
+	// 算法由配置决定可用范围，防止用公钥冒充 HMAC 密钥
+	if strings.HasPrefix(header.Alg, "HS") {
+		if cfg.secret == "" {
+			return nil, fmt.Errorf("algorithm %s is not enabled", header.Alg)
+		}
+		mac := hmac.New(hash.New, []byte(cfg.secret))
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return nil, fmt.Errorf("signature mismatch")
+		}
+	} else {
+		if cfg.jwksURL == "" {
+			return nil, fmt.Errorf("algorithm %s is not enabled", header.Alg)
+		}
+		key, err := keys.key(cfg.jwksURL, header.Kid, now)
+		if err != nil {
+			return nil, err
+		}
+		digest := hash.New()
+		digest.Write(signed)
+		if err := rsa.VerifyPKCS1v15(key, hash, digest.Sum(nil), signature); err != nil {
+			return nil, fmt.Errorf("signature mismatch")
+		}
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid claims: %w", err)
+	}
+	// 只接受短期凭据，必须带 exp
+	exp, ok := claims.timeClaim("exp")
+	if !ok {
+		return nil, fmt.Errorf("token has no exp claim")
+	}
+	if now.After(exp.Add(cfg.leeway)) {
+		return nil, fmt.Errorf("token expired")
+	}
+	if nbf, ok := claims.timeClaim("nbf"); ok && now.Add(cfg.leeway).Before(nbf) {
+		return nil, fmt.Errorf("token not valid yet")
+	}
+	if cfg.issuer != "" && claims.stringClaim("iss") != cfg.issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.stringClaim("iss"))
+	}
+	if cfg.audience != "" && !containsToken(claims.stringsClaim("aud"), cfg.audience) {
+		return nil, fmt.Errorf("token is not issued for audience %q", cfg.audience)
+	}
+	return claims, nil
+}
+
+// role 从声明中读取角色，声明为数组时取权限最高的有效角色
+func (cfg *jwtConfig) role(claims jwtClaims) (string, error) {
+	values := claims.stringsClaim(cfg.roleClaim)
+	if len(values) == 0 {
+		if roleLevels[cfg.defaultRole] == 0 {
+			return "", fmt.Errorf("token has no %s claim", cfg.roleClaim)
+		}
+		return cfg.defaultRole, nil
+	}
+	role := ""
+	for _, value := range values {
+		if roleLevels[value] > roleLevels[role] {
+			role = value
+		}
+	}
+	if role == "" {
+		return "", fmt.Errorf("token has no valid role in %s claim", cfg.roleClaim)
+	}
+	return role, nil
+}
+
+// decodeJWTPart 解码 JWT 的 base64url 段
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// jwksCache 按 kid 缓存的 RSA 公钥
+type jwksCache struct {
+	mutex   sync.Mutex
+	url     string
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+// key 获取 kid 对应的公钥，缓存过期或遇到未知 kid 时重新获取
+func (c *jwksCache) key(url, kid string, now time.Time) (*rsa.PublicKey, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.url != url {
+		c.url, c.keys, c.fetched = url, nil, time.Time{}
+	}
+	key, ok := c.lookup(kid)
+	stale := now.Sub(c.fetched) >= jwksCacheTTL
+	if (!ok && now.Sub(c.fetched) >= jwksMinRefresh) || stale {
+		keys, err := fetchJWKS(url)
+		if err != nil {
+			if ok {
+				return key, nil // 获取失败时继续使用缓存的公钥
+			}
+			return nil, err
+		}
+		c.keys, c.fetched = keys, now
+		key, ok = c.lookup(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("no signing key found for kid %q", kid)
+	}
+	return key, nil
+}
+
+// lookup 查找缓存的公钥，kid 为空且只有一个公钥时使用该公钥
+func (c *jwksCache) lookup(kid string) (*rsa.PublicKey, bool) {
+	if key, ok := c.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// fetchJWKS 获取 JWKS 中的 RSA 公钥
+func fetchJWKS(url string) (map[string]*rsa.PublicKey, error) {
+	client := &http.Client{Timeout: jwksFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jwks: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks contains no RSA signing keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signJWT 生成测试用 JWT，key 为 HMAC 密钥（[]byte）或 RSA 私钥
+func signJWT(t *testing.T, alg, kid string, claims map[string]interface{}, key interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatalf("签名失败: %v", err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// TestJWTVerifyHMAC 测试 HMAC 签名与声明校验
+func TestJWTVerifyHMAC(t *testing.T) {
+	secret := []byte("jwt-secret")
+	cfg := &jwtConfig{secret: string(secret), issuer: "https://idp.example.com", audience: "onedock",
+		roleClaim: "role", defaultRole: RoleReadOnly, leeway: 30 * time.Second}
+	now := time.Now()
+	claims := func(extra map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"sub": "alice", "iss": "https://idp.example.com", "aud": []string{"onedock"}, "exp": now.Add(time.Minute).Unix()}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"有效", signJWT(t, "HS256", "", claims(nil), secret), true},
+		{"时钟偏差内", signJWT(t, "HS256", "", claims(map[string]interface{}{"exp": now.Add(-10 * time.Second).Unix()}), secret), true},
+		{"已过期", signJWT(t, "HS256", "", claims(map[string]interface{}{"exp": now.Add(-time.Minute).Unix()}), secret), false},
+		{"缺少 exp", signJWT(t, "HS256", "", claims(map[string]interface{}{"exp": nil}), secret), false},
+		{"尚未生效", signJWT(t, "HS256", "", claims(map[string]interface{}{"nbf": now.Add(time.Minute).Unix()}), secret), false},
+		{"签发者不符", signJWT(t, "HS256", "", claims(map[string]interface{}{"iss": "other"}), secret), false},
+		{"受众不符", signJWT(t, "HS256", "", claims(map[string]interface{}{"aud": "other"}), secret), false},
+		{"密钥错误", signJWT(t, "HS256", "", claims(nil), []byte("wrong")), false},
+		{"不支持的算法", signJWT(t, "none", "", claims(nil), secret), false},
+		{"未配置 JWKS", signJWT(t, "RS256", "", claims(nil), secret), false},
+		{"格式错误", "a.b.c", false},
+	}
+	for _, tt := range tests {
+		_, err := cfg.verify(tt.token, &jwksCache{}, now)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: verify() error = %v, 期望通过 %v", tt.name, err, tt.ok)
+		}
+	}
+
+	// 篡改声明后签名不再匹配
+	token := signJWT(t, "HS256", "", claims(nil), secret)
+	parts := strings.Split(token, ".")
+	forged, _ := json.Marshal(claims(map[string]interface{}{"role": RoleAdmin}))
+	parts[1] = base64.RawURLEncoding.EncodeToString(forged)
+	if _, err := cfg.verify(strings.Join(parts, "."), &jwksCache{}, now); err == nil {
+		t.Error("篡改声明的令牌应验证失败")
+	}
+}
+
+// TestJWTVerifyJWKS 测试 RSA 签名按 kid 从 JWKS 获取公钥
+func TestJWTVerifyJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("生成 RSA 密钥失败: %v", err)
+	}
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "key-1", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	defer server.Close()
+
+	cfg := &jwtConfig{jwksURL: server.URL, roleClaim: "role", defaultRole: RoleReadOnly}
+	cache := &jwksCache{}
+	now := time.Now()
+	claims := map[string]interface{}{"sub": "ci", "exp": now.Add(time.Minute).Unix(), "role": RoleDeployer}
+
+	if _, err := cfg.verify(signJWT(t, "RS256", "key-1", claims, key), cache, now); err != nil {
+		t.Fatalf("verify() = %v", err)
+	}
+	if _, err := cfg.verify(signJWT(t, "RS256", "key-1", claims, key), cache, now); err != nil || fetches != 1 {
+		t.Errorf("verify() = %v, 获取 JWKS %d 次, 期望使用缓存", err, fetches)
+	}
+	if _, err := cfg.verify(signJWT(t, "RS256", "key-2", claims, key), cache, now); err == nil {
+		t.Error("未知 kid 应验证失败")
+	}
+	if fetches != 1 {
+		t.Errorf("短时间内遇到未知 kid 不应重复获取 JWKS, 实际 %d 次", fetches)
+	}
+	// 未配置 HMAC 密钥时不接受 HS256，防止用公钥冒充密钥
+	if _, err := cfg.verify(signJWT(t, "HS256", "key-1", claims, []byte("x")), cache, now); err == nil {
+		t.Error("未配置 secret 时 HS256 应验证失败")
+	}
+}
+
+// TestJWTRole 测试从声明读取角色
+func TestJWTRole(t *testing.T) {
+	cfg := &jwtConfig{roleClaim: "groups", defaultRole: RoleReadOnly}
+	tests := []struct {
+		claims jwtClaims
+		want   string
+		ok     bool
+	}{
+		{jwtClaims{}, RoleReadOnly, true},
+		{jwtClaims{"groups": RoleDeployer}, RoleDeployer, true},
+		{jwtClaims{"groups": []interface{}{"dev", RoleReadOnly, RoleAdmin}}, RoleAdmin, true},
+		{jwtClaims{"groups": []interface{}{"dev"}}, "", false},
+	}
+	for i, tt := range tests {
+		got, err := cfg.role(tt.claims)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("用例 %d: role() = %q, %v, 期望 %q", i, got, err, tt.want)
+		}
+	}
+
+	cfg.defaultRole = "none"
+	if _, err := cfg.role(jwtClaims{}); err == nil {
+		t.Error("default_role 为 none 时缺少角色声明应拒绝")
+	}
+}
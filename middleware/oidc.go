@@ -0,0 +1,412 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/igo/util"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// SessionCookie OIDC 登录后保存会话的 Cookie
+	SessionCookie = "onedock_session"
+
+	oidcStateCookie        = "onedock_oidc_state"
+	oidcStateTTL           = 10 * time.Minute // 从跳转登录到回调的最长时间
+	oidcDiscoveryTTL       = time.Hour        // 身份提供方配置的缓存时间
+	oidcHTTPTimeout        = 10 * time.Second // 请求身份提供方的超时
+	defaultOIDCSessionTTL  = 8 * 3600         // 默认会话有效期（秒）
+	defaultOIDCGroupsClaim = "groups"         // 默认从 groups 声明读取用户组
+	sessionIssuer          = "onedock"
+	sessionAudience        = "onedock-session"
+	stateAudience          = "onedock-oidc-state"
+)
+
+// oidcGroupRole 用户组到角色的映射（[[auth.oidc.group_roles]]）
+type oidcGroupRole struct {
+	Group string `mapstructure:"group"`
+	Role  string `mapstructure:"role"`
+}
+
+// oidcConfig OIDC 登录配置（auth.oidc）
+type oidcConfig struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string // 身份提供方回调地址，指向 /onedock/auth/callback
+	scopes       []string
+	groupsClaim  string
+	groupRoles   []oidcGroupRole
+	defaultRole  string // 没有匹配的用户组时的角色，为空表示拒绝登录
+	sessionTTL   time.Duration
+}
+
+// loadOIDCConfig 读取 OIDC 配置，未开启时返回 nil
+func loadOIDCConfig() *oidcConfig {
+	if !utils.ConfGetbool("auth.oidc.enabled") {
+		return nil
+	}
+	cfg := &oidcConfig{
+		issuer:       strings.TrimSuffix(utils.ConfGetString("auth.oidc.issuer"), "/"),
+		clientID:     utils.ConfGetString("auth.oidc.client_id"),
+		clientSecret: utils.ConfGetString("auth.oidc.client_secret"),
+		redirectURL:  utils.ConfGetString("auth.oidc.redirect_url"),
+		scopes:       util.ConfGetStringSlice("auth.oidc.scopes"),
+		groupsClaim:  utils.ConfGetString("auth.oidc.groups_claim"),
+		defaultRole:  utils.ConfGetString("auth.oidc.default_role"),
+		sessionTTL:   time.Duration(utils.ConfGetIntDefault("auth.oidc.session_ttl", defaultOIDCSessionTTL)) * time.Second,
+	}
+	if len(cfg.scopes) == 0 {
+		cfg.scopes = []string{"openid", "profile", "email", "groups"}
+	}
+	if cfg.groupsClaim == "" {
+		cfg.groupsClaim = defaultOIDCGroupsClaim
+	}
+	if err := igo.App.Conf.UnmarshalKey("auth.oidc.group_roles", &cfg.groupRoles); err != nil {
+		log.Error("OIDC", log.Any("Error", err), log.Any("Message", "解析用户组角色映射失败"))
+	}
+	return cfg
+}
+
+// role 按用户组确定角色，匹配多个用户组时取权限最高的角色
+func (cfg *oidcConfig) role(groups []string) (string, error) {
+	role := ""
+	for _, mapping := range cfg.groupRoles {
+		if containsToken(groups, mapping.Group) && roleLevels[mapping.Role] > roleLevels[role] {
+			role = mapping.Role
+		}
+	}
+	if role == "" {
+		role = cfg.defaultRole
+	}
+	if roleLevels[role] == 0 {
+		return "", fmt.Errorf("none of the user's groups is mapped to a role")
+	}
+	return role, nil
+}
+
+// oidcProvider 身份提供方的配置（/.well-known/openid-configuration）
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcDiscovery 缓存身份提供方的配置
+type oidcDiscovery struct {
+	mutex    sync.Mutex
+	issuer   string
+	provider *oidcProvider
+	fetched  time.Time
+}
+
+// get 获取身份提供方配置，缓存过期后重新获取
+func (d *oidcDiscovery) get(issuer string, now time.Time) (*oidcProvider, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.issuer == issuer && d.provider != nil && now.Sub(d.fetched) < oidcDiscoveryTTL {
+		return d.provider, nil
+	}
+
+	client := &http.Client{Timeout: oidcHTTPTimeout}
+	resp, err := client.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to discover oidc provider: status %d", resp.StatusCode)
+	}
+	provider := &oidcProvider{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxSize)).Decode(provider); err != nil {
+		return nil, fmt.Errorf("invalid oidc provider configuration: %w", err)
+	}
+	if provider.Issuer != issuer {
+		return nil, fmt.Errorf("oidc provider issuer %q does not match %q", provider.Issuer, issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc provider configuration is incomplete")
+	}
+	d.issuer, d.provider, d.fetched = issuer, provider, now
+	return provider, nil
+}
+
+var (
+	oidcProviders = &oidcDiscovery{}
+	oidcKeys      = &jwksCache{} // 验证 ID Token 的公钥缓存，与 auth.jwt 分开
+
+	sessionKeyOnce sync.Once
+	sessionKey     string // 未配置 auth.oidc.session_secret 时随机生成，重启后会话失效
+)
+
+// sessionSecret 签发会话使用的密钥
+func sessionSecret() string {
+	if secret := utils.ConfGetString("auth.oidc.session_secret"); secret != "" {
+		return secret
+	}
+	sessionKeyOnce.Do(func() {
+		sessionKey = randomString(32)
+	})
+	return sessionKey
+}
+
+// randomString 随机字符串（base64url）
+func randomString(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// signSession 用 HS256 签发 OneDock 自己的会话令牌
+func signSession(claims jwtClaims, secret string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload, _ := json.Marshal(claims)
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifySession 校验 signSession 签发的令牌
+func verifySession(token, audience, secret string, now time.Time) (jwtClaims, error) {
+	cfg := &jwtConfig{secret: secret, issuer: sessionIssuer, audience: audience}
+	return cfg.verify(token, nil, now)
+}
+
+// safeRedirect 登录后跳转的地址只允许本站路径，防止开放重定向
+func safeRedirect(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		return "/onedock/auth/whoami"
+	}
+	return redirect
+}
+
+// setCookie 写入 HttpOnly、SameSite=Lax 的 Cookie，HTTPS 下带 Secure
+// SameSite=Lax 阻止跨站发起的修改请求携带会话
+func setCookie(c *gin.Context, name, value string, maxAge int, secure bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// secureCookie 回调地址为 HTTPS 或请求经由 TLS 时 Cookie 带 Secure
+func (cfg *oidcConfig) secureCookie(c *gin.Context) bool {
+	return c.Request.TLS != nil || strings.HasPrefix(cfg.redirectURL, "https://")
+}
+
+// OIDCEnabled 是否开启 OIDC 登录
+func OIDCEnabled() bool {
+	return utils.ConfGetbool("auth.oidc.enabled")
+}
+
+// OIDCLoginURL 生成跳转到身份提供方的登录地址，并把 state、nonce 和 PKCE 校验码写入签名的 Cookie
+// redirect 为登录完成后跳转的本站路径
+func OIDCLoginURL(c *gin.Context, redirect string) (string, error) {
+	cfg := loadOIDCConfig()
+	if cfg == nil {
+		return "", fmt.Errorf("oidc login is not enabled")
+	}
+	provider, err := oidcProviders.get(cfg.issuer, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	state, nonce, verifier := randomString(16), randomString(16), randomString(32)
+	now := time.Now()
+	setCookie(c, oidcStateCookie, signSession(jwtClaims{
+		"iss":      sessionIssuer,
+		"aud":      stateAudience,
+		"exp":      now.Add(oidcStateTTL).Unix(),
+		"state":    state,
+		"nonce":    nonce,
+		"verifier": verifier,
+		"redirect": safeRedirect(redirect),
+	}, sessionSecret()), int(oidcStateTTL.Seconds()), cfg.secureCookie(c))
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {cfg.clientID},
+		"redirect_uri":          {cfg.redirectURL},
+		"scope":                 {strings.Join(cfg.scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return provider.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// OIDCCallback 处理身份提供方的回调：校验 state，用授权码换取 ID Token，按用户组确定角色后写入会话 Cookie
+// 返回登录的用户、角色和登录前请求的跳转地址
+func OIDCCallback(c *gin.Context) (user, role, redirect string, err error) {
+	cfg := loadOIDCConfig()
+	if cfg == nil {
+		return "", "", "", fmt.Errorf("oidc login is not enabled")
+	}
+	if e := c.Query("error"); e != "" {
+		return "", "", "", fmt.Errorf("identity provider returned %s: %s", e, c.Query("error_description"))
+	}
+	now := time.Now()
+	cookie, _ := c.Cookie(oidcStateCookie)
+	state, err := verifySession(cookie, stateAudience, sessionSecret(), now)
+	if err != nil {
+		return "", "", "", fmt.Errorf("login session expired, please sign in again")
+	}
+	setCookie(c, oidcStateCookie, "", -1, cfg.secureCookie(c))
+	if given := c.Query("state"); given == "" || !hmac.Equal([]byte(given), []byte(state.stringClaim("state"))) {
+		return "", "", "", fmt.Errorf("state mismatch")
+	}
+
+	provider, err := oidcProviders.get(cfg.issuer, now)
+	if err != nil {
+		return "", "", "", err
+	}
+	idToken, err := exchangeCode(cfg, provider, c.Query("code"), state.stringClaim("verifier"))
+	if err != nil {
+		return "", "", "", err
+	}
+	claims, err := (&jwtConfig{jwksURL: provider.JWKSURI, issuer: provider.Issuer, audience: cfg.clientID, leeway: defaultJWTLeeway * time.Second}).
+		verify(idToken, oidcKeys, now)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid id token: %w", err)
+	}
+	if claims.stringClaim("nonce") != state.stringClaim("nonce") {
+		return "", "", "", fmt.Errorf("invalid id token: nonce mismatch")
+	}
+
+	user = oidcUser(claims)
+	if role, err = cfg.role(claims.stringsClaim(cfg.groupsClaim)); err != nil {
+		return "", "", "", fmt.Errorf("user %s: %w", user, err)
+	}
+	setCookie(c, SessionCookie, signSession(jwtClaims{
+		"iss":  sessionIssuer,
+		"aud":  sessionAudience,
+		"exp":  now.Add(cfg.sessionTTL).Unix(),
+		"sub":  user,
+		"role": role,
+	}, sessionSecret()), int(cfg.sessionTTL.Seconds()), cfg.secureCookie(c))
+	return user, role, state.stringClaim("redirect"), nil
+}
+
+// oidcUser 用户标识，依次取 email、preferred_username、sub
+func oidcUser(claims jwtClaims) string {
+	for _, name := range []string{"email", "preferred_username", "sub"} {
+		if value := claims.stringClaim(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// exchangeCode 用授权码向身份提供方换取 ID Token
+func exchangeCode(cfg *oidcConfig, provider *oidcProvider, code, verifier string) (string, error) {
+	if code == "" {
+		return "", fmt.Errorf("missing authorization code")
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {cfg.redirectURL},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(cfg.clientID), url.QueryEscape(cfg.clientSecret))
+
+	client := &http.Client{Timeout: oidcHTTPTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxSize)).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("failed to exchange authorization code: %s %s", result.Error, result.ErrorDescription)
+	}
+	if result.IDToken == "" {
+		return "", fmt.Errorf("token response contains no id_token")
+	}
+	return result.IDToken, nil
+}
+
+// ClearSession 退出登录，清除会话 Cookie
+func ClearSession(c *gin.Context) {
+	setCookie(c, SessionCookie, "", -1, c.Request.TLS != nil)
+}
+
+// errNoSession 请求没有携带会话
+var errNoSession = errors.New("缺少访问令牌")
+
+// sessionIdentity 从会话 Cookie 中读取 OIDC 登录的用户
+func sessionIdentity(c *gin.Context) (*tokenIdentity, error) {
+	cookie, err := c.Cookie(SessionCookie)
+	if err != nil || cookie == "" || !OIDCEnabled() {
+		return nil, errNoSession
+	}
+	claims, err := verifySession(cookie, sessionAudience, sessionSecret(), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("登录已失效，请重新登录")
+	}
+	role := claims.stringClaim("role")
+	if roleLevels[role] == 0 {
+		return nil, fmt.Errorf("登录已失效，请重新登录")
+	}
+	actor := "oidc:" + claims.stringClaim("sub")
+	return &tokenIdentity{role: role, actor: actor, id: utils.TokenID(actor)}, nil
+}
+
+// SwaggerAuth 开启 OIDC 登录时，Swagger UI 需要先登录，未登录时跳转到登录页
+func SwaggerAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !utils.ConfGetbool("auth.enabled") || !OIDCEnabled() {
+			c.Next()
+			return
+		}
+		if _, err := sessionIdentity(c); err != nil {
+			c.Redirect(http.StatusFound, "/onedock/auth/login?redirect="+url.QueryEscape(c.Request.URL.RequestURI()))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestOIDCRole 测试用户组到角色的映射
+func TestOIDCRole(t *testing.T) {
+	cfg := &oidcConfig{groupRoles: []oidcGroupRole{
+		{Group: "platform", Role: RoleAdmin},
+		{Group: "dev", Role: RoleDeployer},
+		{Group: "oncall", Role: RoleReadOnly},
+	}}
+	tests := []struct {
+		groups []string
+		want   string
+	}{
+		{[]string{"dev"}, RoleDeployer},
+		{[]string{"oncall", "platform", "dev"}, RoleAdmin},
+		{[]string{"Dev"}, ""}, // 用户组区分大小写
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		got, err := cfg.role(tt.groups)
+		if got != tt.want || (err == nil) != (tt.want != "") {
+			t.Errorf("role(%v) = %q, %v, 期望 %q", tt.groups, got, err, tt.want)
+		}
+	}
+
+	cfg.defaultRole = RoleReadOnly
+	if got, err := cfg.role([]string{"marketing"}); err != nil || got != RoleReadOnly {
+		t.Errorf("未匹配的用户组应使用 default_role, 实际 %q, %v", got, err)
+	}
+}
+
+// TestSession 测试会话令牌的签发与校验
+func TestSession(t *testing.T) {
+	now := time.Now()
+	token := signSession(jwtClaims{"iss": sessionIssuer, "aud": sessionAudience, "exp": now.Add(time.Hour).Unix(), "sub": "alice", "role": RoleDeployer}, "secret")
+
+	claims, err := verifySession(token, sessionAudience, "secret", now)
+	if err != nil || claims.stringClaim("sub") != "alice" || claims.stringClaim("role") != RoleDeployer {
+		t.Fatalf("verifySession() = %v, %v", claims, err)
+	}
+	if _, err := verifySession(token, sessionAudience, "other", now); err == nil {
+		t.Error("密钥不同时应校验失败")
+	}
+	if _, err := verifySession(token, stateAudience, "secret", now); err == nil {
+		t.Error("会话令牌不能当作登录 state 使用")
+	}
+	if _, err := verifySession(token, sessionAudience, "secret", now.Add(2*time.Hour)); err == nil {
+		t.Error("过期的会话应校验失败")
+	}
+}
+
+// TestSafeRedirect 测试登录后只跳转到本站路径
+func TestSafeRedirect(t *testing.T) {
+	tests := map[string]string{
+		"/swagger/index.html":     "/swagger/index.html",
+		"":                        "/onedock/auth/whoami",
+		"https://evil.example":    "/onedock/auth/whoami",
+		"//evil.example/path":     "/onedock/auth/whoami",
+		"/\\evil.example":         "/onedock/auth/whoami",
+		"/onedock/?namespace=dev": "/onedock/?namespace=dev",
+	}
+	for redirect, want := range tests {
+		if got := safeRedirect(redirect); got != want {
+			t.Errorf("safeRedirect(%q) = %q, 期望 %q", redirect, got, want)
+		}
+	}
+}
+
+// TestOIDCProvider 测试发现身份提供方配置与授权码换取 ID Token
+func TestOIDCProvider(t *testing.T) {
+	var issuer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                 issuer,
+				"authorization_endpoint": issuer + "/authorize",
+				"token_endpoint":         issuer + "/token",
+				"jwks_uri":               issuer + "/jwks",
+			})
+		case "/token":
+			id, secret, _ := r.BasicAuth()
+			if id != "onedock" || secret != "s3cret" || r.FormValue("code") != "code-1" || r.FormValue("code_verifier") != "verifier" {
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"id_token": "id-token", "access_token": "access"})
+		}
+	}))
+	defer server.Close()
+	issuer = server.URL
+
+	discovery := &oidcDiscovery{}
+	provider, err := discovery.get(issuer, time.Now())
+	if err != nil || provider.TokenEndpoint != issuer+"/token" {
+		t.Fatalf("get() = %+v, %v", provider, err)
+	}
+	if _, err := (&oidcDiscovery{}).get(issuer+"/other", time.Now()); err == nil {
+		t.Error("issuer 不一致时应返回错误")
+	}
+
+	cfg := &oidcConfig{clientID: "onedock", clientSecret: "s3cret", redirectURL: "http://127.0.0.1:8801/onedock/auth/callback"}
+	if idToken, err := exchangeCode(cfg, provider, "code-1", "verifier"); err != nil || idToken != "id-token" {
+		t.Errorf("exchangeCode() = %q, %v", idToken, err)
+	}
+	if _, err := exchangeCode(cfg, provider, "code-2", "verifier"); err == nil {
+		t.Error("授权码无效时应返回错误")
+	}
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/igo/util"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultRateLimitBurst = 20          // 默认允许的突发请求数
+	rateLimitSweep        = time.Minute // 清理空闲计数的间隔
+	rateLimitKeyToken     = "token:"    // 按令牌限流的键前缀
+	rateLimitKeyIP        = "ip:"       // 按客户端 IP 限流的键前缀
+)
+
+// rateLimit 一个请求需要满足的限流规则
+type rateLimit struct {
+	key       string
+	perMinute int
+}
+
+// rateBucket 令牌桶
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // 额度补满的时间，之后可以清理
+}
+
+// rateLimiter 按键（令牌或客户端 IP）限流的令牌桶集合
+type rateLimiter struct {
+	mutex     sync.Mutex
+	buckets   map[string]*rateBucket
+	lastSweep time.Time
+}
+
+// allow 按每分钟 perMinute 个、最多突发 burst 个的速率消耗一次请求额度
+// 额度不足时返回 false 和需要等待的时间
+func (l *rateLimiter) allow(key string, perMinute, burst int, now time.Time) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	rate := float64(perMinute) / 60 // 每秒补充的额度
+	if l.buckets == nil {
+		l.buckets = make(map[string]*rateBucket)
+	}
+	// 定期清理已补满的桶，避免客户端 IP 过多时占用内存
+	if now.Sub(l.lastSweep) >= rateLimitSweep {
+		for k, b := range l.buckets {
+			if !now.Before(b.full) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &rateBucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.full = now.Add(time.Duration((float64(burst) - b.tokens) / rate * float64(time.Second)))
+	if allowed {
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// TrustedProxies 可信代理的 IP 或网段（rate_limit.trusted_proxies），默认为空
+// 只有来自这些地址的请求才按 X-Forwarded-For 识别客户端 IP，否则使用连接的对端地址，避免伪造请求头绕过限流
+func TrustedProxies() []string {
+	return util.ConfGetStringSlice("rate_limit.trusted_proxies")
+}
+
+// RateLimit 管理接口限流：按客户端 IP（rate_limit.ip_per_minute）和有效令牌（rate_limit.token_per_minute）分别计数，
+// 超出时返回 429 和 Retry-After，避免自动化脚本频繁调用压垮 Docker 守护进程；端口代理的流量不受影响
+// lookup 与 Auth 相同，用于识别通过接口创建的令牌
+func RateLimit(lookup TokenLookup) gin.HandlerFunc {
+	limiter := &rateLimiter{}
+	return func(c *gin.Context) {
+		if !utils.ConfGetbool("rate_limit.enabled") {
+			c.Next()
+			return
+		}
+		burst := utils.ConfGetIntDefault("rate_limit.burst", defaultRateLimitBurst)
+		if burst < 1 {
+			burst = 1
+		}
+		now := time.Now()
+
+		limits := make([]rateLimit, 0, 2)
+		if perMinute := utils.ConfGetInt("rate_limit.ip_per_minute"); perMinute > 0 {
+			limits = append(limits, rateLimit{key: rateLimitKeyIP + c.ClientIP(), perMinute: perMinute})
+		}
+		// 只按有效令牌计数，无效令牌由权限验证拒绝，不会为其建立计数
+		if perMinute := utils.ConfGetInt("rate_limit.token_per_minute"); perMinute > 0 {
+			if token := extractToken(c); token != "" {
+				if identity, err := authenticate(token, lookup); err == nil {
+					limits = append(limits, rateLimit{key: rateLimitKeyToken + identity.id, perMinute: perMinute})
+				}
+			}
+		}
+
+		for _, limit := range limits {
+			ok, wait := limiter.allow(limit.key, limit.perMinute, burst, now)
+			if ok {
+				continue
+			}
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			log.Warn("RateLimit", log.Any("Key", limit.key), log.Any("Path", c.Request.URL.Path), log.Any("RetryAfter", retryAfter),
+				log.Any("Message", "管理接口请求过于频繁"))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"code":       1,
+				"error_code": utils.CodeRateLimited,
+				"msg":        fmt.Sprintf("rate limit exceeded, retry after %d seconds", retryAfter),
+				"data":       nil,
+			})
+			return
+		}
+		c.Next()
+	}
+}
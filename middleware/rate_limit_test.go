@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRateLimiter 测试令牌桶的突发额度、补充速率和按键隔离
+func TestRateLimiter(t *testing.T) {
+	var limiter rateLimiter
+	now := time.Now()
+
+	// 每分钟 60 次即每秒补充 1 次，突发 3 次
+	for i := 0; i < 3; i++ {
+		if ok, _ := limiter.allow("ip:10.0.0.1", 60, 3, now); !ok {
+			t.Fatalf("第 %d 次请求应在突发额度内", i+1)
+		}
+	}
+	ok, wait := limiter.allow("ip:10.0.0.1", 60, 3, now)
+	if ok {
+		t.Fatal("超出突发额度的请求应被拒绝")
+	}
+	if wait <= 0 || wait > time.Second {
+		t.Errorf("等待时间 = %s, 期望 (0, 1s]", wait)
+	}
+
+	if ok, _ := limiter.allow("ip:10.0.0.2", 60, 3, now); !ok {
+		t.Error("其他客户端不应受影响")
+	}
+	if ok, _ := limiter.allow("ip:10.0.0.1", 60, 3, now.Add(time.Second)); !ok {
+		t.Error("1 秒后应补充 1 次额度")
+	}
+
+	// 额度补满后的计数会被清理
+	later := now.Add(2 * rateLimitSweep)
+	limiter.allow("ip:10.0.0.3", 60, 3, later)
+	if _, ok := limiter.buckets["ip:10.0.0.1"]; ok {
+		t.Error("已补满的计数应被清理")
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDKey gin 上下文中保存请求 ID 的键
+	RequestIDKey = "onedock-request-id"
+	// RequestIDHeader 请求 ID 请求头：调用方传入时沿用，否则生成；响应中总是返回
+	RequestIDHeader = "X-Request-ID"
+)
+
+// requestIDPattern 调用方传入的请求 ID 格式，不符合时重新生成，避免日志注入
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// quietPaths 探测类请求成功时不记录请求日志
+var quietPaths = map[string]bool{
+	"/healthz":         true,
+	"/readyz":          true,
+	"/onedock/ping":    true,
+	"/v1/onedock/ping": true,
+}
+
+// requestOutcome 从响应开头解析出的业务码、错误码和错误信息
+type requestOutcome struct {
+	Code      int    `json:"code"`
+	ErrorCode string `json:"error_code"`
+	Msg       string `json:"msg"`
+}
+
+// RequestLog 为每个请求分配请求 ID（X-Request-ID）并在响应头中返回，请求结束后记录一行请求日志：
+// 请求 ID、方法、路径、HTTP 状态码、业务码、耗时、操作者（令牌已脱敏）和客户端 IP。需注册在其他中间件之前
+func RequestLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+
+		if !utils.ConfGetboolDefault("request_log.enabled", true) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		writer := &auditWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		status := writer.Status()
+		outcome := parseOutcome(writer.body.Bytes())
+		if quietPaths[c.Request.URL.Path] && status < 400 {
+			return
+		}
+
+		fields := []log.Field{
+			log.Any("RequestID", id),
+			log.Any("Method", c.Request.Method),
+			log.Any("Path", auditPath(c.Request.URL)),
+			log.Any("Status", status),
+			log.Any("Code", outcome.Code),
+			log.Any("LatencyMs", time.Since(start).Milliseconds()),
+			log.Any("Actor", Actor(c)),
+			log.Any("ClientIP", c.ClientIP()),
+		}
+		switch {
+		case status >= 500:
+			log.Error("Request", append(fields, log.Any("ErrorCode", outcome.ErrorCode), log.Any("Error", outcome.Msg), log.Any("Message", "请求失败"))...)
+		case status >= 400 || outcome.Code != 0:
+			log.Warn("Request", append(fields, log.Any("ErrorCode", outcome.ErrorCode), log.Any("Error", outcome.Msg), log.Any("Message", "请求失败"))...)
+		default:
+			log.Info("Request", append(fields, log.Any("Message", "请求完成"))...)
+		}
+	}
+}
+
+// parseOutcome 解析 JSON 响应中的业务码和错误信息，非 JSON 响应（如日志流、指标）返回零值
+func parseOutcome(body []byte) requestOutcome {
+	var outcome requestOutcome
+	if json.Unmarshal(body, &outcome) != nil {
+		return requestOutcome{}
+	}
+	return outcome
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRequestIDPattern 测试调用方传入的请求 ID 校验
+func TestRequestIDPattern(t *testing.T) {
+	cases := map[string]bool{
+		"3f2a9c1d-0b7e-4c1a-9d2e-5f6a7b8c9d0e": true,
+		"deploy.nginx:42_a":                    true,
+		"":                                     false,
+		"has space":                            false,
+		"line\nbreak":                          false,
+		strings.Repeat("a", 128):               true,
+		strings.Repeat("a", 129):               false,
+	}
+	for id, want := range cases {
+		if got := requestIDPattern.MatchString(id); got != want {
+			t.Errorf("requestIDPattern.MatchString(%q) = %v, 期望 %v", id, got, want)
+		}
+	}
+}
+
+// TestParseOutcome 测试从响应中解析请求结果
+func TestParseOutcome(t *testing.T) {
+	outcome := parseOutcome([]byte(`{"code":1,"error_code":"NOT_FOUND","msg":"service nginx-web not found","data":null}`))
+	if outcome.Code != 1 || outcome.ErrorCode != "NOT_FOUND" || outcome.Msg != "service nginx-web not found" {
+		t.Errorf("parseOutcome = %+v, 期望解析出业务码、错误码和错误信息", outcome)
+	}
+	if outcome := parseOutcome([]byte("2024-01-15 log line")); outcome != (requestOutcome{}) {
+		t.Errorf("parseOutcome(non-json) = %+v, 期望零值", outcome)
+	}
+	// 响应超过记录上限被截断时不是完整的 JSON
+	if outcome := parseOutcome([]byte(`{"code":0,"data":{"name":`)); outcome != (requestOutcome{}) {
+		t.Errorf("parseOutcome(truncated) = %+v, 期望零值", outcome)
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/aichy126/igo/util"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// 令牌角色，权限依次递增
+const (
+	RoleReadOnly = models.RoleReadOnly
+	RoleDeployer = models.RoleDeployer
+	RoleAdmin    = models.RoleAdmin
+)
+
+// TokenLookup 查找配置文件以外的令牌（如通过接口创建的令牌），返回角色和服务范围，无效时角色为空字符串
+type TokenLookup func(token string) (string, *models.TokenScope)
+
+// roleLevels 角色的权限等级
+var roleLevels = map[string]int{
+	RoleReadOnly: 1,
+	RoleDeployer: 2,
+	RoleAdmin:    3,
+}
+
+// roleAllows 角色 role 是否具有 required 角色的权限
+func roleAllows(role, required string) bool {
+	return roleLevels[role] > 0 && roleLevels[role] >= roleLevels[required]
+}
+
+// isReadMethod 是否为只读请求方法
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// readRoutes 使用 POST 提交查询、但不修改任何数据的接口，只读令牌和限定服务范围的令牌也可以调用，不记录审计日志
+var readRoutes = []string{"/graphql"}
+
+// isReadRequest 是否为只读请求：只读方法，或 readRoutes 中的接口
+func isReadRequest(method, route string) bool {
+	if isReadMethod(method) {
+		return true
+	}
+	route = strings.TrimPrefix(strings.TrimPrefix(route, "/v1"), "/onedock")
+	for _, read := range readRoutes {
+		if route == read {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenRole 按配置确定静态令牌的角色，未配置的令牌返回空字符串
+// auth.admin_tokens 为管理员，auth.tokens 为部署者，auth.readonly_tokens 为只读
+func tokenRole(token string) string {
+	switch {
+	case isAdminToken(token):
+		return RoleAdmin
+	case containsToken(getValidTokens(), token):
+		return RoleDeployer
+	case containsToken(util.ConfGetStringSlice("auth.readonly_tokens"), token):
+		return RoleReadOnly
+	}
+	return ""
+}
+
+// containsToken 令牌是否在列表中
+func containsToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if token == t {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole 要求请求令牌至少具有 role 角色，用于终端、备份等虽为 GET 但不应对只读令牌开放的接口
+// 未启用权限验证时不限制
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !utils.ConfGetbool("auth.enabled") {
+			c.Next()
+			return
+		}
+		if !roleAllows(c.GetString(RoleKey), role) {
+			utils.RfailCode(c, utils.CodeForbidden, "权限验证失败：需要 "+role+" 角色的访问令牌", nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
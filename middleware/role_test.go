@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestRoleAllows 测试角色的权限等级
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		required string
+		want     bool
+	}{
+		{RoleReadOnly, RoleReadOnly, true},
+		{RoleReadOnly, RoleDeployer, false},
+		{RoleDeployer, RoleReadOnly, true},
+		{RoleDeployer, RoleAdmin, false},
+		{RoleAdmin, RoleDeployer, true},
+		{"", RoleReadOnly, false},
+		{"unknown", RoleReadOnly, false},
+	}
+	for _, tt := range tests {
+		if got := roleAllows(tt.role, tt.required); got != tt.want {
+			t.Errorf("roleAllows(%q, %q) = %v, 期望 %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+
+	for method, want := range map[string]bool{
+		http.MethodGet:    true,
+		http.MethodHead:   true,
+		http.MethodPost:   false,
+		http.MethodPut:    false,
+		http.MethodDelete: false,
+	} {
+		if got := isReadMethod(method); got != want {
+			t.Errorf("isReadMethod(%s) = %v, 期望 %v", method, got, want)
+		}
+	}
+
+	for _, tt := range []struct {
+		method string
+		route  string
+		want   bool
+	}{
+		{http.MethodGet, "/onedock/:name", true},
+		{http.MethodPost, "/onedock/graphql", true},
+		{http.MethodPost, "/v1/onedock/graphql", true},
+		{http.MethodPost, "/onedock/:name/scale", false},
+	} {
+		if got := isReadRequest(tt.method, tt.route); got != tt.want {
+			t.Errorf("isReadRequest(%s %s) = %v, 期望 %v", tt.method, tt.route, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+)
+
+// tokenScopeConfig 静态令牌的服务范围（[[auth.token_scopes]]）
+type tokenScopeConfig struct {
+	Token             string `mapstructure:"token"`
+	models.TokenScope `mapstructure:",squash"`
+}
+
+// configTokenScope 读取静态令牌的服务范围，未配置时返回 nil
+func configTokenScope(token string) *models.TokenScope {
+	var items []tokenScopeConfig
+	if err := igo.App.Conf.UnmarshalKey("auth.token_scopes", &items); err != nil {
+		log.Error("Auth", log.Any("Error", err), log.Any("Message", "解析令牌服务范围失败"))
+	}
+	for _, item := range items {
+		if item.Token == token {
+			scope := item.TokenScope
+			return &scope
+		}
+	}
+	return nil
+}
+
+// scopedRoutes 不带服务名称、但在服务层按请求体中的服务名称校验范围的修改接口，受限令牌可以调用
+var scopedRoutes = []string{"/", "/batch", "/scale", "/delete", "/templates/:template/deploy"}
+
+// globalReadRoutes 返回全部服务数据且不按服务范围过滤的查询接口，受限令牌不能调用
+// 备份包含所有服务的配置（明文环境变量）、历史和密钥
+var globalReadRoutes = []string{"/system/backup", "/audit", "/events", "/events/stream", "/overview", "/ports", "/proxy/stats"}
+
+// checkScope 校验受限令牌能否调用该接口：
+// 带服务名称的接口要求服务在范围内；其余接口只允许查询（globalReadRoutes 除外），以及 scopedRoutes 中的部署接口
+func checkScope(scope *models.TokenScope, method, route, name string) error {
+	if !scope.Restricted() {
+		return nil
+	}
+	if name != "" {
+		if !scope.Allows(name) {
+			return fmt.Errorf("令牌无权访问服务 %s", name)
+		}
+		return nil
+	}
+	route = strings.TrimPrefix(strings.TrimPrefix(route, "/v1"), "/onedock")
+	if isReadRequest(method, route) {
+		for _, global := range globalReadRoutes {
+			if route == global {
+				return fmt.Errorf("限定服务范围的令牌不能查询全部服务的数据")
+			}
+		}
+		return nil
+	}
+	for _, allowed := range scopedRoutes {
+		if route == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("限定服务范围的令牌不能调用该接口")
+}
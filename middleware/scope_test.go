@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestCheckScope 测试受限令牌能调用的接口
+func TestCheckScope(t *testing.T) {
+	scope := &models.TokenScope{Services: []string{"app-a"}, Namespaces: []string{"staging"}}
+	tests := []struct {
+		method string
+		route  string
+		name   string
+		ok     bool
+	}{
+		{http.MethodPost, "/onedock/:name/scale", "app-a", true},
+		{http.MethodDelete, "/v1/onedock/:name", "staging.app-b", true},
+		{http.MethodDelete, "/onedock/:name", "app-b", false},
+		{http.MethodGet, "/onedock/:name/status", "app-b", false},
+		{http.MethodGet, "/onedock/", "", true},
+		{http.MethodPost, "/onedock/", "", true},
+		{http.MethodPost, "/v1/onedock/batch", "", true},
+		{http.MethodPost, "/onedock/scale", "", true},
+		{http.MethodPost, "/onedock/delete", "", true},
+		{http.MethodPost, "/onedock/templates/:template/deploy", "", true},
+		{http.MethodPut, "/onedock/secrets/:secret", "", false},
+		{http.MethodPost, "/onedock/system/cleanup", "", false},
+		{http.MethodPost, "/onedock/graphql", "", true},
+		{http.MethodGet, "/onedock/system/backup", "", false},
+		{http.MethodGet, "/v1/onedock/audit", "", false},
+		{http.MethodGet, "/onedock/events", "", false},
+		{http.MethodGet, "/onedock/events/stream", "", false},
+		{http.MethodGet, "/onedock/overview", "", false},
+		{http.MethodGet, "/onedock/ports", "", false},
+		{http.MethodGet, "/onedock/proxy/stats", "", false},
+		{http.MethodGet, "/onedock/ports/reservations", "", true},
+	}
+	for _, tt := range tests {
+		if err := checkScope(scope, tt.method, tt.route, tt.name); (err == nil) != tt.ok {
+			t.Errorf("checkScope(%s %s, %q) = %v, 期望通过 %v", tt.method, tt.route, tt.name, err, tt.ok)
+		}
+	}
+
+	// 未限制范围的令牌不受影响
+	for _, unrestricted := range []*models.TokenScope{nil, {}} {
+		if err := checkScope(unrestricted, http.MethodPut, "/onedock/secrets/:secret", ""); err != nil {
+			t.Errorf("未限制范围时不应拒绝: %v", err)
+		}
+		if err := checkScope(unrestricted, http.MethodGet, "/onedock/system/backup", ""); err != nil {
+			t.Errorf("未限制范围时不应拒绝备份: %v", err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultRequestTimeout = 120 // 管理接口默认的请求超时（秒）
+
+// routeTimeout 单个接口的超时配置（[[timeout.routes]]），method 为空时匹配所有方法
+type routeTimeout struct {
+	Method  string `mapstructure:"method"`
+	Route   string `mapstructure:"route"`
+	Seconds int    `mapstructure:"seconds"`
+}
+
+// defaultRouteTimeouts 内置的接口超时（秒）：长连接接口不限制，会拉取镜像或逐个替换副本的接口放宽到 30 分钟
+// 可被 [[timeout.routes]] 覆盖
+var defaultRouteTimeouts = []routeTimeout{
+	{http.MethodGet, "/:name/logs/stream", 0},
+	{http.MethodGet, "/events/stream", 0},
+	{http.MethodGet, "/:name/replicas/:index/terminal", 0},
+	{http.MethodGet, "/system/backup", 0},
+	{http.MethodPost, "/system/restore", 0},
+	{http.MethodPost, "/", 1800},
+	{http.MethodPost, "/batch", 1800},
+	{http.MethodPost, "/templates/:template/deploy", 1800},
+	{http.MethodPost, "/:name/rollback", 1800},
+	{http.MethodPost, "/:name/canary", 1800},
+	{http.MethodPost, "/:name/canary/promote", 1800},
+	{http.MethodPost, "/:name/blue-green/promote", 1800},
+	{http.MethodPost, "/:name/clone", 1800},
+	{http.MethodPost, "/:name/replicas/:index/recreate", 1800},
+	{http.MethodPost, "/trash/:name/restore", 1800},
+	{http.MethodPost, "/gitops/sync", 1800},
+}
+
+// Timeout 按接口设置请求超时：超时或客户端断开时请求的上下文被取消，进行中的 Docker 操作（拉取镜像、创建容器等）随之中止，
+// 接口返回 TIMEOUT 错误码。超时时间依次取 [[timeout.routes]]、内置的 defaultRouteTimeouts 和 timeout.default，0 表示不限制
+func Timeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var routes []routeTimeout
+		if err := igo.App.Conf.UnmarshalKey("timeout.routes", &routes); err != nil {
+			log.Error("Timeout", log.Any("Error", err), log.Any("Message", "解析接口超时配置失败"))
+		}
+		def := utils.ConfGetIntDefault("timeout.default", defaultRequestTimeout)
+		seconds := requestTimeout(routes, def, c.Request.Method, c.FullPath())
+		if seconds <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(seconds)*time.Second)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// requestTimeout 接口的超时时间（秒）：配置的接口优先于内置的接口，都没有时为 def
+func requestTimeout(routes []routeTimeout, def int, method, route string) int {
+	route = strings.TrimPrefix(strings.TrimPrefix(route, "/v1"), "/onedock")
+	for _, list := range [][]routeTimeout{routes, defaultRouteTimeouts} {
+		for _, r := range list {
+			if r.Route == route && (r.Method == "" || strings.EqualFold(r.Method, method)) {
+				return r.Seconds
+			}
+		}
+	}
+	return def
+}
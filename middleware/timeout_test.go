@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestRequestTimeout 测试接口超时时间的优先级
+func TestRequestTimeout(t *testing.T) {
+	routes := []routeTimeout{
+		{Method: "post", Route: "/", Seconds: 3600},
+		{Route: "/:name/scale", Seconds: 10},
+	}
+	tests := []struct {
+		method string
+		route  string
+		want   int
+	}{
+		{http.MethodPost, "/onedock/", 3600},
+		{http.MethodGet, "/onedock/", 60},
+		{http.MethodPost, "/v1/onedock/:name/scale", 10},
+		{http.MethodPost, "/onedock/batch", 1800},
+		{http.MethodGet, "/onedock/:name/logs/stream", 0},
+		{http.MethodGet, "/onedock/:name/status", 60},
+	}
+	for _, tt := range tests {
+		if got := requestTimeout(routes, 60, tt.method, tt.route); got != tt.want {
+			t.Errorf("requestTimeout(%s %s) = %d, 期望 %d", tt.method, tt.route, got, tt.want)
+		}
+	}
+}
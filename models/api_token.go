@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// 访问令牌的角色，权限依次递增
+const (
+	RoleReadOnly = "read-only" // 只能查询：列表、状态、日志等 GET 请求
+	RoleDeployer = "deployer"  // 可以部署、扩缩容、删除等全部操作，不能操作已锁定的服务
+	RoleAdmin    = "admin"     // 全部操作，包括已锁定的服务和令牌管理
+)
+
+// 访问令牌的状态
+const (
+	APITokenActive  = "active"
+	APITokenExpired = "expired"
+	APITokenRevoked = "revoked"
+)
+
+// APITokenRequest 创建访问令牌的请求
+// @Description 令牌明文只在创建时返回一次，服务端只保存摘要
+type APITokenRequest struct {
+	Name      string `json:"name" binding:"required" example:"ci-deploy" description:"令牌名称，便于辨认用途"`
+	Role      string `json:"role,omitempty" example:"deployer" description:"角色：read-only / deployer / admin，默认 deployer"`
+	ExpiresIn int    `json:"expires_in,omitempty" example:"2592000" description:"有效期（秒），0 表示不过期"`
+	TokenScope
+}
+
+// APITokenExpireRequest 设置访问令牌过期时间的请求
+type APITokenExpireRequest struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2024-02-01T00:00:00Z" description:"过期时间，为空表示立即过期"`
+}
+
+// APIToken 访问令牌信息
+type APIToken struct {
+	ID         int64       `json:"id" example:"1" description:"令牌 ID"`
+	Name       string      `json:"name" example:"ci-deploy" description:"令牌名称"`
+	Role       string      `json:"role" example:"deployer" description:"角色"`
+	Prefix     string      `json:"prefix" example:"odk_3f9a" description:"令牌明文的前几位"`
+	Token      string      `json:"token,omitempty" example:"odk_3f9a..." description:"令牌明文，只在创建时返回"`
+	Status     string      `json:"status" example:"active" description:"状态：active / expired / revoked"`
+	Scope      *TokenScope `json:"scope,omitempty" description:"可操作的服务范围，为空表示不限制"`
+	Actor      string      `json:"actor,omitempty" example:"token:abcd****" description:"创建者"`
+	ExpiresAt  *time.Time  `json:"expires_at,omitempty" example:"2024-02-01T00:00:00Z" description:"过期时间"`
+	RevokedAt  *time.Time  `json:"revoked_at,omitempty" example:"2024-01-20T00:00:00Z" description:"吊销时间"`
+	LastUsedAt *time.Time  `json:"last_used_at,omitempty" example:"2024-01-16T08:00:00Z" description:"最近使用时间（按分钟记录）"`
+	CreatedAt  time.Time   `json:"created_at" example:"2024-01-15T10:30:00Z" description:"创建时间"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// AuditRecord 一次修改类 API 调用的审计记录
+// @Description 谁（操作者、令牌指纹、客户端 IP）在什么时间调用了哪个接口，请求体与结果
+type AuditRecord struct {
+	ID         int64     `json:"id" example:"42" description:"记录 ID"`
+	Actor      string    `json:"actor" example:"token:abcd****" description:"操作者"`
+	TokenID    string    `json:"token_id,omitempty" example:"3f2a9c1d0b7e" description:"令牌指纹"`
+	RequestID  string    `json:"request_id,omitempty" example:"8d3e6f0a-4b1c-4e2d-9a7b-1c2d3e4f5a6b" description:"请求 ID，与响应头 X-Request-ID 和服务端请求日志一致"`
+	ClientIP   string    `json:"client_ip" example:"10.0.0.8" description:"客户端 IP"`
+	Method     string    `json:"method" example:"POST" description:"HTTP 方法"`
+	Path       string    `json:"path" example:"/onedock/nginx-web/scale" description:"请求路径（含查询参数，令牌已脱敏）"`
+	Service    string    `json:"service,omitempty" example:"nginx-web" description:"涉及的服务"`
+	Body       string    `json:"body,omitempty" example:"{\"replicas\":3}" description:"请求体（密钥值等敏感字段已脱敏，过长时截断）"`
+	Status     int       `json:"status" example:"200" description:"HTTP 状态码"`
+	Code       int       `json:"code" example:"0" description:"响应中的业务码，0 表示成功"`
+	Error      string    `json:"error,omitempty" description:"失败原因"`
+	DurationMs int64     `json:"duration_ms" example:"1530" description:"处理耗时（毫秒）"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-15T10:30:00Z" description:"调用时间"`
+}
+
+// AuditList 审计日志查询结果
+type AuditList struct {
+	Total   int64          `json:"total" example:"128" description:"符合条件的记录总数"`
+	Records []*AuditRecord `json:"records" description:"按时间倒序的记录"`
+}
+
+// AuditQuery 审计日志查询条件
+type AuditQuery struct {
+	Service   string
+	Actor     string
+	Method    string
+	RequestID string
+	Failed    bool
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+	Offset    int
+}
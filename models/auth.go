@@ -0,0 +1,40 @@
+package models
+
+// Identity 当前请求的身份
+type Identity struct {
+	Actor       string      `json:"actor" example:"oidc:alice@example.com" description:"操作者标识：脱敏的令牌、jwt:<sub> 或 oidc:<用户>"`
+	Role        string      `json:"role,omitempty" example:"deployer" description:"角色：read-only / deployer / admin，未启用权限验证时为空"`
+	AuthEnabled bool        `json:"auth_enabled" example:"true" description:"是否启用权限验证"`
+	Scope       *TokenScope `json:"scope,omitempty" description:"令牌可操作的服务范围，为空表示不限制"`
+}
+
+// TokenScope 令牌可操作的服务范围，Services 和 Namespaces 都为空表示不限制
+// @Description 受限的令牌只能查看和修改范围内的服务，不能调用密钥、模板、系统维护等全局修改接口
+type TokenScope struct {
+	Services   []string `json:"services,omitempty" mapstructure:"services" example:"app-a,staging.app-a" description:"可操作的服务全名"`
+	Namespaces []string `json:"namespaces,omitempty" mapstructure:"namespaces" example:"staging" description:"可操作的命名空间，包含其中全部服务"`
+}
+
+// Restricted 是否限制了服务范围
+func (scope *TokenScope) Restricted() bool {
+	return scope != nil && (len(scope.Services) > 0 || len(scope.Namespaces) > 0)
+}
+
+// Allows 服务是否在范围内，未限制时全部允许
+func (scope *TokenScope) Allows(name string) bool {
+	if !scope.Restricted() {
+		return true
+	}
+	for _, service := range scope.Services {
+		if service == name {
+			return true
+		}
+	}
+	namespace, _ := SplitServiceName(name)
+	for _, ns := range scope.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// BackupManifest 备份归档的元信息
+// @Description 归档中的 manifest.json
+type BackupManifest struct {
+	Format               int       `json:"format" example:"1" description:"归档格式版本"`
+	CreatedAt            time.Time `json:"created_at" example:"2024-01-15T10:30:00Z" description:"备份时间"`
+	Hostname             string    `json:"hostname" example:"docker-01" description:"备份来源主机"`
+	Services             []string  `json:"services" description:"备份中的服务"`
+	SecretKeyFingerprint string    `json:"secret_key_fingerprint,omitempty" example:"3f2a9c1d0b7e4a55" description:"加密密钥的 secret.key 指纹，恢复时需一致"`
+}
+
+// RestoreResult 恢复结果
+// @Description 恢复的状态数量、与本机配置的差异，以及恢复后调和创建副本的结果
+type RestoreResult struct {
+	Manifest  BackupManifest     `json:"manifest" description:"备份元信息"`
+	DryRun    bool               `json:"dry_run,omitempty" example:"false" description:"是否只校验不恢复"`
+	Services  []string           `json:"services" description:"恢复的服务"`
+	Ports     int                `json:"ports" example:"5" description:"恢复的端口登记与预留数"`
+	Secrets   int                `json:"secrets" example:"2" description:"恢复的密钥数"`
+	Templates int                `json:"templates" example:"1" description:"恢复的服务模板数"`
+	CronJobs  int                `json:"cron_jobs" example:"1" description:"恢复的定时任务数"`
+	Warnings  []string           `json:"warnings,omitempty" description:"需要人工处理的问题，如代理配置与备份不同、公共端口已被占用"`
+	Reconcile []*ReconcileResult `json:"reconcile,omitempty" description:"恢复后调和的结果（按期望状态创建副本并启动端口代理）"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// CloneRequest 克隆服务请求
+// @Description 复制现有服务的配置部署为新服务，未填写的字段沿用原服务的配置
+type CloneRequest struct {
+	Name        string            `json:"name" binding:"required,servicename" example:"nginx-web-review" description:"新服务名称，可写成 命名空间.名称"`
+	Namespace   string            `json:"namespace,omitempty" binding:"omitempty,namespace" example:"review" description:"可选的命名空间，不填时取名称中的命名空间，都没有则为 default"`
+	Tag         string            `json:"tag,omitempty" example:"1.27" description:"覆盖镜像标签"`
+	PublicPort  int               `json:"public_port,omitempty" binding:"omitempty,port" example:"9310" description:"新服务的公共端口，不填则从 ports.auto_range 自动分配"`
+	Replicas    int               `json:"replicas,omitempty" binding:"min=0" example:"1" description:"覆盖副本数量"`
+	Environment map[string]string `json:"environment,omitempty" description:"覆盖的环境变量，与原服务的环境变量合并，值为空字符串时删除该变量"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// 异步部署状态
+const (
+	DeploymentPending   = "pending"   // 已提交，等待执行
+	DeploymentRunning   = "running"   // 执行中
+	DeploymentSucceeded = "succeeded" // 部署或更新完成
+	DeploymentFailed    = "failed"    // 部署或更新失败
+)
+
+// 异步部署阶段
+const (
+	DeployStepQueued   = "queued"   // 等待执行
+	DeployStepPulling  = "pulling"  // 拉取镜像
+	DeployStepCreating = "creating" // 创建副本
+	DeployStepUpdating = "updating" // 滚动更新副本
+	DeployStepDone     = "done"     // 已结束
+)
+
+// 异步部署操作
+const (
+	DeploymentActionDeploy = "deploy" // 首次部署
+	DeploymentActionUpdate = "update" // 更新已存在的服务
+)
+
+// Deployment 异步部署
+// @Description 通过 async=true 提交的部署或更新的进度，step 和 current/total 反映当前阶段和副本进度
+type Deployment struct {
+	ID         string         `json:"id" example:"7c1e9a04b2d3" description:"部署ID"`
+	Service    string         `json:"service" example:"nginx-web" description:"服务名称"`
+	Action     string         `json:"action" example:"update" description:"操作：deploy（首次部署）/ update（更新已存在的服务）"`
+	Image      string         `json:"image" example:"nginx:1.25" description:"镜像"`
+	Status     string         `json:"status" example:"running" description:"状态：pending / running / succeeded / failed"`
+	Step       string         `json:"step" example:"creating" description:"当前阶段：queued / pulling / creating / updating / done"`
+	Message    string         `json:"message,omitempty" example:"creating replica 2/3" description:"当前进度描述"`
+	Current    int            `json:"current" example:"1" description:"已完成的副本数"`
+	Total      int            `json:"total" example:"3" description:"需要创建或更新的副本数，未知时为 0"`
+	Error      string         `json:"error,omitempty" example:"failed to pull image: manifest unknown" description:"失败原因"`
+	Actor      string         `json:"actor" example:"ci-token" description:"提交者"`
+	Request    ServiceRequest `json:"request" description:"部署请求"`
+	CreatedAt  time.Time      `json:"created_at" example:"2024-01-15T10:30:00Z" description:"提交时间"`
+	StartedAt  *time.Time     `json:"started_at,omitempty" example:"2024-01-15T10:30:00Z" description:"开始执行时间"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty" example:"2024-01-15T10:31:20Z" description:"结束时间"`
+}
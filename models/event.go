@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// 服务生命周期事件类型
+const (
+	EventDeployed         = "deployed"          // 首次部署完成
+	EventUpdated          = "updated"           // 滚动更新完成
+	EventUpdateFailed     = "update_failed"     // 滚动更新失败或被中止
+	EventScaled           = "scaled"            // 副本数变化
+	EventDeleted          = "deleted"           // 服务已删除（包括放入回收站）
+	EventRestored         = "restored"          // 服务已从回收站恢复
+	EventReplicaCrashed   = "replica_crashed"   // 副本意外退出，由调和循环发现
+	EventReplicaRestarted = "replica_restarted" // 通过接口手动重启了单个副本
+	EventReplicaRecreated = "replica_recreated" // 通过接口删除并重建了单个副本
+	EventCrashLoop        = "crash_loop"        // 时间窗口内副本反复崩溃
+	EventAutoscaled       = "autoscaled"        // 自动扩缩容调整了副本数
+	EventProxyRestarted   = "proxy_restarted"   // 端口代理未运行，已重新启动
+	EventProxyStarted     = "proxy_started"     // 端口代理已启动（只在实时事件流中推送）
+	EventProxyStopped     = "proxy_stopped"     // 端口代理已停止（只在实时事件流中推送）
+)
+
+// Event 服务生命周期事件
+// @Description 服务在什么时间发生了什么（部署、扩缩容、副本崩溃、重启或重建、更新失败、代理重启）
+type Event struct {
+	ID        int64     `json:"id" example:"42" description:"事件 ID"`
+	Service   string    `json:"service" example:"nginx-web" description:"服务名称"`
+	Type      string    `json:"type" example:"replica_crashed" description:"事件类型：deployed / updated / update_failed / scaled / deleted / restored / replica_crashed / replica_restarted / replica_recreated / crash_loop / autoscaled / proxy_restarted，实时事件流中还有 proxy_started / proxy_stopped"`
+	Message   string    `json:"message" example:"replica nginx-web-1 was Exited (137) 2 minutes ago, restarted" description:"事件描述"`
+	Actor     string    `json:"actor" example:"system" description:"触发者，后台循环触发时为 system"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z" description:"发生时间"`
+}
+
+// EventList 事件查询结果
+type EventList struct {
+	Total  int64    `json:"total" example:"128" description:"符合条件的事件总数"`
+	Events []*Event `json:"events" description:"按时间倒序的事件"`
+}
+
+// EventQuery 事件查询条件
+type EventQuery struct {
+	Service string
+	Type    string
+	Types   []string // 多个事件类型，满足任一即可
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+	Offset  int
+}
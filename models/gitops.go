@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// GitOps 同步中单个服务的操作
+const (
+	GitOpsCreate    = "create"    // 仓库中新增的服务，已部署
+	GitOpsUpdate    = "update"    // 配置有变化，已滚动更新
+	GitOpsScale     = "scale"     // 只有副本数变化，已扩缩容
+	GitOpsUnchanged = "unchanged" // 与运行中的服务一致
+	GitOpsDelete    = "delete"    // 仓库中已删除的服务，已清理
+	GitOpsOrphaned  = "orphaned"  // 仓库中已删除，但未开启 prune，保留运行
+)
+
+// GitOpsServiceResult 单个服务的同步结果
+type GitOpsServiceResult struct {
+	Service string         `json:"service" example:"nginx-web" description:"服务名称"`
+	File    string         `json:"file,omitempty" example:"apps/nginx-web.yaml" description:"服务配置在仓库中的路径"`
+	Action  string         `json:"action" example:"update" description:"操作：create / update / scale / unchanged / delete / orphaned"`
+	Changes []ConfigChange `json:"changes,omitempty" description:"配置变化"`
+	Error   string         `json:"error,omitempty" description:"同步失败原因"`
+}
+
+// GitOpsStatus GitOps 同步状态
+// @Description 仓库配置及最近一次同步的提交和各服务的结果
+type GitOpsStatus struct {
+	Enabled    bool                   `json:"enabled" example:"true" description:"是否配置了 gitops.repo"`
+	Repo       string                 `json:"repo,omitempty" example:"https://github.com/acme/deploy.git" description:"服务配置仓库"`
+	Branch     string                 `json:"branch,omitempty" example:"main" description:"同步的分支"`
+	Path       string                 `json:"path,omitempty" example:"services" description:"服务配置所在目录"`
+	Prune      bool                   `json:"prune" example:"false" description:"是否删除仓库中已移除的服务"`
+	Commit     string                 `json:"commit,omitempty" example:"9fceb02d0ae598e95dc970b74767f19372d61af8" description:"最近一次同步的提交"`
+	DryRun     bool                   `json:"dry_run,omitempty" example:"false" description:"是否为只预演不执行的同步"`
+	LastSyncAt *time.Time             `json:"last_sync_at,omitempty" example:"2024-01-15T10:30:00Z" description:"最近同步时间"`
+	LastError  string                 `json:"last_error,omitempty" description:"最近一次同步的错误（拉取仓库或解析配置失败）"`
+	Services   []*GitOpsServiceResult `json:"services" description:"各服务的同步结果"`
+}
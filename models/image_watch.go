@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// 镜像更新监视方式
+const (
+	ImageWatchDigest = "digest" // 监视当前标签在仓库中的摘要变化（如 latest、1.25 被重新推送）
+	ImageWatchSemver = "semver" // 监视匹配 pattern 的更新版本标签
+)
+
+// 发现镜像更新后的操作
+const (
+	ImageWatchNotify   = "notify"   // 只记录并输出日志
+	ImageWatchRedeploy = "redeploy" // 自动滚动更新
+)
+
+// ImageWatch 镜像更新监视配置
+type ImageWatch struct {
+	Mode    string `json:"mode" binding:"required" example:"semver" description:"监视方式：digest（当前标签的摘要变化）/ semver（更新的版本标签）"`
+	Pattern string `json:"pattern,omitempty" example:"^1\\.25\\." description:"semver 方式下候选标签需匹配的正则表达式，不填则匹配全部版本标签"`
+	Action  string `json:"action,omitempty" example:"notify" description:"发现更新后的操作：notify（默认，只记录）/ redeploy（自动滚动更新）"`
+}
+
+// ImageWatchStatus 镜像更新监视状态
+// @Description 监视配置及最近一次检查结果
+type ImageWatchStatus struct {
+	Service         string     `json:"service" example:"nginx-web" description:"服务名称"`
+	Watch           ImageWatch `json:"watch" description:"监视配置"`
+	Image           string     `json:"image" example:"nginx:1.25.3" description:"服务当前镜像"`
+	LatestTag       string     `json:"latest_tag,omitempty" example:"1.25.4" description:"semver 方式下发现的最新标签"`
+	LatestDigest    string     `json:"latest_digest,omitempty" example:"sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac" description:"digest 方式下仓库中的最新摘要"`
+	UpdateAvailable bool       `json:"update_available" example:"true" description:"是否有尚未部署的更新"`
+	LastError       string     `json:"last_error,omitempty" description:"最近一次检查或自动更新的错误"`
+	LastCheckAt     *time.Time `json:"last_check_at,omitempty" example:"2024-01-15T10:30:00Z" description:"最近检查时间"`
+	LastUpdateAt    *time.Time `json:"last_update_at,omitempty" example:"2024-01-15T10:30:05Z" description:"最近一次自动更新时间"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// 任务状态
+const (
+	JobStatusPending   = "pending"   // 已提交，正在拉取镜像或创建容器
+	JobStatusRunning   = "running"   // 容器运行中
+	JobStatusSucceeded = "succeeded" // 退出码为 0
+	JobStatusFailed    = "failed"    // 退出码非 0、超时或无法启动
+	JobStatusCancelled = "cancelled" // 被手动取消
+)
+
+// JobRequest 一次性任务请求
+// @Description 运行一个容器直到结束（数据库迁移、批处理等），不映射端口、不自动重启
+type JobRequest struct {
+	Name        string            `json:"name,omitempty" example:"db-migrate" description:"任务名称（可选，便于识别）"`
+	Image       string            `json:"image" binding:"required" example:"myapp" description:"Docker镜像名称"`
+	Tag         string            `json:"tag" binding:"required" example:"1.2.0" description:"镜像标签"`
+	Entrypoint  []string          `json:"entrypoint,omitempty" example:"/bin/sh,-c" description:"容器入口点"`
+	Command     []string          `json:"command,omitempty" example:"./migrate,up" description:"容器启动命令"`
+	WorkingDir  string            `json:"working_dir,omitempty" example:"/app" description:"工作目录"`
+	Environment map[string]string `json:"environment,omitempty" description:"环境变量，值可以写成 secret://<名称> 引用密钥"`
+	EnvFile     string            `json:"env_file,omitempty" example:"/etc/myapp/.env" description:"环境变量文件路径"`
+	Volumes     []VolumeMount     `json:"volumes,omitempty" description:"卷挂载配置"`
+	MemoryLimit int               `json:"memory_limit,omitempty" example:"512" description:"内存上限（MB），0 表示不限制"`
+	Timeout     int               `json:"timeout,omitempty" example:"600" description:"超时时间（秒），超时后强制结束并标记失败，0 使用 container.job_timeout"`
+}
+
+// Job 一次性任务
+// @Description 任务的运行状态、退出码和日志
+type Job struct {
+	ID          string     `json:"id" example:"3f2a9c1b7d4e" description:"任务ID"`
+	Name        string     `json:"name,omitempty" example:"db-migrate" description:"任务名称"`
+	CronJob     string     `json:"cron_job,omitempty" example:"nightly-report" description:"触发该任务的定时任务"`
+	Image       string     `json:"image" example:"myapp:1.2.0" description:"镜像"`
+	Status      string     `json:"status" example:"succeeded" description:"状态: pending / running / succeeded / failed / cancelled"`
+	ExitCode    *int64     `json:"exit_code,omitempty" example:"0" description:"容器退出码"`
+	Error       string     `json:"error,omitempty" example:"timed out after 600s" description:"失败原因"`
+	Logs        string     `json:"logs,omitempty" description:"容器输出（标准输出与标准错误，只保留末尾部分；列表接口不返回）"`
+	ContainerID string     `json:"container_id,omitempty" example:"a1b2c3d4e5f6" description:"容器ID"`
+	Request     JobRequest `json:"request" description:"任务请求"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-01-15T10:30:00Z" description:"提交时间"`
+	StartedAt   *time.Time `json:"started_at,omitempty" example:"2024-01-15T10:30:05Z" description:"容器启动时间"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty" example:"2024-01-15T10:31:00Z" description:"结束时间"`
+}
+
+// 定时任务并发策略：上一次运行尚未结束时如何处理新的触发
+const (
+	ConcurrencyAllow   = "allow"   // 同时运行
+	ConcurrencyForbid  = "forbid"  // 跳过本次触发（默认）
+	ConcurrencyReplace = "replace" // 取消上一次运行，再启动新的
+)
+
+// CronJobRequest 创建或更新定时任务的请求
+// @Description 按 cron 表达式周期性运行一次性任务，表达式格式与定时扩缩容相同
+type CronJobRequest struct {
+	Name              string     `json:"name" binding:"required" example:"nightly-report" description:"定时任务名称"`
+	Cron              string     `json:"cron" binding:"required" example:"0 2 * * *" description:"cron 表达式（分 时 日 月 周，服务器本地时间）"`
+	ConcurrencyPolicy string     `json:"concurrency_policy,omitempty" example:"forbid" description:"并发策略: allow / forbid（默认）/ replace"`
+	HistoryLimit      int        `json:"history_limit,omitempty" example:"10" description:"保留的运行记录数，默认 10"`
+	Suspended         bool       `json:"suspended,omitempty" example:"false" description:"暂停调度（仍可手动触发）"`
+	Job               JobRequest `json:"job" binding:"required" description:"每次运行的任务配置"`
+}
+
+// CronJob 定时任务
+// @Description 定时任务配置、调度状态和最近的运行记录
+type CronJob struct {
+	Name              string     `json:"name" example:"nightly-report" description:"定时任务名称"`
+	Cron              string     `json:"cron" example:"0 2 * * *" description:"cron 表达式"`
+	ConcurrencyPolicy string     `json:"concurrency_policy" example:"forbid" description:"并发策略"`
+	HistoryLimit      int        `json:"history_limit" example:"10" description:"保留的运行记录数"`
+	Suspended         bool       `json:"suspended" example:"false" description:"是否暂停调度"`
+	Job               JobRequest `json:"job" description:"任务配置"`
+	NextRunAt         *time.Time `json:"next_run_at,omitempty" example:"2024-01-16T02:00:00Z" description:"下次触发时间"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty" example:"2024-01-15T02:00:00Z" description:"最近一次触发时间"`
+	LastJobID         string     `json:"last_job_id,omitempty" example:"3f2a9c1b7d4e" description:"最近一次触发的任务ID"`
+	LastError         string     `json:"last_error,omitempty" example:"skipped: previous run 3f2a9c1b7d4e is still running" description:"最近一次触发的错误或跳过原因"`
+	Active            []string   `json:"active,omitempty" example:"3f2a9c1b7d4e" description:"运行中的任务ID"`
+	Runs              []*Job     `json:"runs,omitempty" description:"最近的运行记录（含退出码和日志，只在详情接口返回）"`
+	CreatedAt         time.Time  `json:"created_at" example:"2024-01-15T10:30:00Z" description:"创建时间"`
+	UpdatedAt         time.Time  `json:"updated_at" example:"2024-01-15T10:30:00Z" description:"更新时间"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// LockRequest 锁定服务请求
+type LockRequest struct {
+	Reason string `json:"reason" example:"大促期间封版" description:"锁定原因，可选"`
+}
+
+// ServiceLock 服务锁定信息
+// @Description 锁定期间只有管理员令牌可以部署、更新、扩缩容、启停或删除该服务
+type ServiceLock struct {
+	Service   string    `json:"service" example:"nginx-web" description:"服务名称"`
+	Reason    string    `json:"reason" example:"大促期间封版" description:"锁定原因"`
+	Actor     string    `json:"actor" example:"token:abcd****" description:"锁定服务的操作者"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z" description:"锁定时间"`
+}
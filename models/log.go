@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// LogLine 实时日志中的一行
+type LogLine struct {
+	Replica string    `json:"replica" example:"nginx-web-9300-1" description:"副本容器名称"`
+	Stream  string    `json:"stream" example:"stdout" description:"输出流：stdout / stderr（分配了 TTY 的容器均为 stdout）"`
+	Time    time.Time `json:"time" example:"2024-01-15T10:30:00.123456789Z" description:"Docker 记录的时间"`
+	Line    string    `json:"line" example:"GET /health 200" description:"日志内容"`
+}
+
+// LogStreamOptions 实时日志选项
+type LogStreamOptions struct {
+	Tail  string    // 每个副本开始时输出的历史行数，"all" 表示全部
+	Since time.Time // 只输出该时间之后的日志
+}
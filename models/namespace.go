@@ -0,0 +1,46 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	DefaultNamespace   = "default" // 默认命名空间，服务名称不带前缀
+	NamespaceSeparator = "."       // 命名空间与服务名称之间的分隔符
+)
+
+// namespacePattern 命名空间格式：小写字母、数字和连字符，以字母或数字开头和结尾
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,30}[a-z0-9])?$`)
+
+// ValidateNamespace 校验命名空间名称
+func ValidateNamespace(namespace string) error {
+	if !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("invalid namespace %q: must be lowercase letters, digits or '-', at most 32 characters", namespace)
+	}
+	return nil
+}
+
+// QualifiedName 返回带命名空间的服务全名，默认命名空间下不加前缀以兼容已有服务
+func QualifiedName(namespace, name string) string {
+	if namespace == "" || namespace == DefaultNamespace {
+		return name
+	}
+	return namespace + NamespaceSeparator + name
+}
+
+// SplitServiceName 将服务全名拆分为命名空间和短名称
+func SplitServiceName(name string) (namespace, short string) {
+	if i := strings.Index(name, NamespaceSeparator); i > 0 {
+		return name[:i], name[i+len(NamespaceSeparator):]
+	}
+	return DefaultNamespace, name
+}
+
+// NamespaceInfo 命名空间概况
+type NamespaceInfo struct {
+	Name     string `json:"name" example:"staging" description:"命名空间"`
+	Services int    `json:"services" example:"3" description:"服务数"`
+	Replicas int    `json:"replicas" example:"6" description:"副本总数"`
+}
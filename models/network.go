@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// NetworkCreateRequest 创建网络请求
+type NetworkCreateRequest struct {
+	Name     string            `json:"name" binding:"required" example:"backend" description:"网络名称：字母、数字、下划线、点和连字符，以字母或数字开头"`
+	Driver   string            `json:"driver,omitempty" binding:"omitempty,oneof=bridge macvlan ipvlan" example:"bridge" description:"网络驱动，默认 bridge"`
+	Internal bool              `json:"internal,omitempty" example:"false" description:"内部网络，加入的容器不能访问外部网络"`
+	Subnet   string            `json:"subnet,omitempty" binding:"omitempty,cidr" example:"172.30.0.0/16" description:"子网，不填由 Docker 分配"`
+	Gateway  string            `json:"gateway,omitempty" binding:"omitempty,ip" example:"172.30.0.1" description:"网关，不填由 Docker 分配"`
+	Labels   map[string]string `json:"labels,omitempty" description:"网络标签"`
+}
+
+// NetworkAttachment 加入网络的副本
+type NetworkAttachment struct {
+	Service     string `json:"service" example:"nginx-web" description:"服务名称"`
+	Replica     int    `json:"replica" example:"0" description:"副本编号"`
+	ContainerID string `json:"container_id" example:"a1b2c3d4e5f6" description:"容器ID（短格式）"`
+	State       string `json:"state" example:"running" description:"容器状态"`
+	IPAddress   string `json:"ip_address,omitempty" example:"172.30.0.2" description:"副本在该网络中的 IPv4 地址，容器未运行时为空"`
+}
+
+// Network Docker 网络及加入它的服务
+// @Description managed 为 true 的网络由 OneDock 创建，只有这类网络可以通过 OneDock 删除
+type Network struct {
+	ID        string              `json:"id" example:"3f2a1b4c5d6e" description:"网络ID（短格式）"`
+	Name      string              `json:"name" example:"backend" description:"网络名称"`
+	Driver    string              `json:"driver" example:"bridge" description:"网络驱动"`
+	Scope     string              `json:"scope" example:"local" description:"作用范围"`
+	Internal  bool                `json:"internal" example:"false" description:"是否为内部网络"`
+	Subnets   []string            `json:"subnets" example:"172.30.0.0/16" description:"子网"`
+	Gateways  []string            `json:"gateways" example:"172.30.0.1" description:"网关"`
+	Labels    map[string]string   `json:"labels,omitempty" description:"网络标签"`
+	Managed   bool                `json:"managed" example:"true" description:"是否由 OneDock 创建"`
+	CreatedAt time.Time           `json:"created_at" example:"2024-01-15T10:30:00Z" description:"创建时间"`
+	Services  []string            `json:"services" example:"nginx-web" description:"加入该网络的服务"`
+	Replicas  []NetworkAttachment `json:"replicas" description:"加入该网络的副本"`
+}
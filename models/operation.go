@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// 服务操作队列中的操作类型
+const (
+	OperationDeploy          = "deploy"
+	OperationUpdate          = "update"
+	OperationRollback        = "rollback"
+	OperationScale           = "scale"
+	OperationStop            = "stop"
+	OperationStart           = "start"
+	OperationRename          = "rename"
+	OperationCanary          = "canary"
+	OperationBlueGreen       = "blue_green"
+	OperationRestartReplica  = "restart_replica"
+	OperationRecreateReplica = "recreate_replica"
+	OperationReconcile       = "reconcile"
+)
+
+// Operation 服务操作队列中的一项操作
+type Operation struct {
+	Operation string     `json:"operation" example:"scale" description:"操作类型：deploy / update / rollback / scale / stop / start / rename / canary / blue_green / restart_replica / recreate_replica / reconcile"`
+	Actor     string     `json:"actor,omitempty" example:"token:abcd****" description:"发起操作的请求方"`
+	QueuedAt  time.Time  `json:"queued_at" example:"2024-01-15T10:30:00Z" description:"进入队列的时间"`
+	StartedAt *time.Time `json:"started_at,omitempty" example:"2024-01-15T10:30:05Z" description:"开始执行的时间，排队中为空"`
+}
+
+// OperationQueue 服务的操作队列
+// @Description 同一服务的部署、更新、扩缩容、启停等修改操作逐个执行，后到的操作排队等待
+type OperationQueue struct {
+	Service string       `json:"service" example:"nginx-web" description:"服务名称"`
+	Running *Operation   `json:"running,omitempty" description:"正在执行的操作，空闲时为空"`
+	Queued  []*Operation `json:"queued" description:"排队等待的操作，按执行顺序排列"`
+}
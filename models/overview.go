@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// UnhealthyReplica 未在运行的副本
+type UnhealthyReplica struct {
+	Service   string `json:"service" example:"nginx-web" description:"服务名称"`
+	Container string `json:"container" example:"nginx-web-9300-1" description:"容器名称"`
+	State     string `json:"state" example:"exited" description:"容器状态"`
+	Status    string `json:"status" example:"Exited (137) 2 minutes ago" description:"容器状态描述"`
+}
+
+// DegradedService 运行中副本少于期望副本数的服务
+type DegradedService struct {
+	Service string `json:"service" example:"nginx-web" description:"服务名称"`
+	Desired int    `json:"desired" example:"3" description:"期望副本数"`
+	Running int    `json:"running" example:"1" description:"运行中副本数"`
+}
+
+// Overview 全局运行概况
+// @Description 服务、副本、端口、代理和最近故障的汇总，一次调用即可用于监控面板
+type Overview struct {
+	Services          int                 `json:"services" example:"12" description:"服务总数"`
+	ServicesByStatus  map[string]int      `json:"services_by_status" description:"按状态统计的服务数"`
+	DegradedServices  []*DegradedService  `json:"degraded_services" description:"运行中副本少于期望副本数的服务（已停止的服务除外）"`
+	ActiveRollouts    []string            `json:"active_rollouts" description:"正在滚动更新的服务"`
+	Replicas          int                 `json:"replicas" example:"30" description:"副本总数"`
+	RunningReplicas   int                 `json:"running_replicas" example:"29" description:"运行中副本数"`
+	UnhealthyReplicas []*UnhealthyReplica `json:"unhealthy_replicas" description:"未在运行的副本"`
+	PortsInUse        int                 `json:"ports_in_use" example:"12" description:"已分配给服务的公共端口数"`
+	PortsReserved     int                 `json:"ports_reserved" example:"2" description:"预留的公共端口数"`
+	ProxiesRunning    int                 `json:"proxies_running" example:"12" description:"运行中的端口代理数"`
+	MissingProxies    []int               `json:"missing_proxies" description:"有运行中副本但端口代理未运行的公共端口"`
+	RecentFailures    []*Event            `json:"recent_failures" description:"最近 24 小时的故障事件（更新失败、副本崩溃、崩溃循环），最多 20 条"`
+	GeneratedAt       time.Time           `json:"generated_at" example:"2024-01-15T10:30:00Z" description:"生成时间"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// PortReservationRequest 预留公共端口请求
+type PortReservationRequest struct {
+	Port    int    `json:"port" binding:"required,port" example:"9300" description:"要预留的公共端口"`
+	Service string `json:"service,omitempty" example:"billing" description:"只允许该服务使用，不填则任何服务均可使用"`
+	Note    string `json:"note,omitempty" example:"计费服务下周上线" description:"备注"`
+}
+
+// PortReservation 公共端口预留
+// @Description 预留的端口不会被其他服务占用，指定服务首次部署到该端口时预留转为分配
+type PortReservation struct {
+	Port      int       `json:"port" example:"9300" description:"公共端口"`
+	Service   string    `json:"service,omitempty" example:"billing" description:"限定使用的服务，为空表示不限定"`
+	Note      string    `json:"note,omitempty" example:"计费服务下周上线" description:"备注"`
+	Actor     string    `json:"actor" example:"token:abcd****" description:"预留端口的操作者"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z" description:"预留时间"`
+}
+
+// 端口用途
+const (
+	PortTypePublic    = "public"    // 服务的公共端口，由端口代理监听
+	PortTypeContainer = "container" // 副本容器映射到宿主机的端口，端口代理转发到该端口
+	PortTypeReserved  = "reserved"  // 预留的公共端口
+)
+
+// HostPort 主机端口的使用情况
+type HostPort struct {
+	Port          int    `json:"port" example:"9303" description:"主机端口"`
+	Type          string `json:"type" example:"public" description:"用途：public（公共端口）/ container（副本映射端口）/ reserved（预留）"`
+	Service       string `json:"service,omitempty" example:"nginx-web" description:"所属服务，预留端口不限定服务时为空"`
+	Replica       *int   `json:"replica,omitempty" example:"0" description:"副本编号（副本映射端口）"`
+	ContainerID   string `json:"container_id,omitempty" example:"3f2a1b4c5d6e" description:"容器ID（副本映射端口）"`
+	ContainerPort string `json:"container_port,omitempty" example:"80/tcp" description:"容器内端口（副本映射端口）"`
+	Proxy         bool   `json:"proxy,omitempty" example:"true" description:"端口代理是否在运行（公共端口）"`
+	Note          string `json:"note,omitempty" example:"计费服务下周上线" description:"备注（预留端口）"`
+}
+
+// PortRange 端口范围，包含两端
+type PortRange struct {
+	Start int `json:"start" example:"20000" description:"起始端口"`
+	End   int `json:"end" example:"20099" description:"结束端口"`
+}
+
+// PortListing 主机端口使用情况
+// @Description 空闲范围按 OneDock 登记的端口计算，不探测主机上其他进程占用的端口
+type PortListing struct {
+	Ports      []HostPort  `json:"ports" description:"使用中的端口，按端口排序"`
+	Total      int         `json:"total" example:"12" description:"使用中的端口数"`
+	AutoRange  string      `json:"auto_range" example:"20000-29999" description:"自动分配公共端口的范围（ports.auto_range）"`
+	FreeRanges []PortRange `json:"free_ranges" description:"自动分配范围内的空闲端口范围"`
+	FreeCount  int         `json:"free_count" example:"9990" description:"自动分配范围内的空闲端口数"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// ProxyBackendStats 端口代理的单个后端（副本）
+type ProxyBackendStats struct {
+	ContainerID   string    `json:"container_id" example:"3f2a1b4c5d6e" description:"容器ID"`
+	ContainerPort int       `json:"container_port" example:"32768" description:"容器映射到宿主机的端口"`
+	Active        bool      `json:"active" example:"true" description:"是否参与负载均衡"`
+	Connections   int64     `json:"connections" example:"2" description:"正在处理的请求数"`
+	Requests      int64     `json:"requests" example:"1024" description:"代理启动以来转发的请求数"`
+	Errors        int64     `json:"errors" example:"3" description:"代理启动以来的失败请求数（后端不可达或返回 5xx）"`
+	Weight        int       `json:"weight" example:"100" description:"权重"`
+	LastUsed      time.Time `json:"last_used" example:"2024-01-15T10:30:00Z" description:"最近一次转发请求的时间"`
+}
+
+// RequestRuleStats 请求过滤规则的拦截计数
+type RequestRuleStats struct {
+	Name    string `json:"name" example:"block-admin" description:"规则名称"`
+	Blocked int64  `json:"blocked" example:"12" description:"拦截的请求数"`
+}
+
+// MirrorStats 流量镜像计数
+type MirrorStats struct {
+	Service  string `json:"service" example:"nginx-web-shadow" description:"镜像目标服务"`
+	Percent  int    `json:"percent" example:"10" description:"镜像比例"`
+	Target   string `json:"target" example:"http://127.0.0.1:9304" description:"镜像目标地址"`
+	Mirrored int64  `json:"mirrored" example:"120" description:"已镜像的请求数"`
+	Failed   int64  `json:"failed" example:"1" description:"镜像失败的请求数"`
+	Skipped  int64  `json:"skipped" example:"5" description:"因请求体过大或并发已满跳过的请求数"`
+}
+
+// ProxyStats 单个端口代理的统计信息
+// @Description 后端的请求数和失败数在代理重建（扩缩容、更新）后重新计数，端口的累计请求数和失败数继续累计
+type ProxyStats struct {
+	Service            string              `json:"service" example:"nginx-web" description:"所属服务"`
+	PublicPort         int                 `json:"public_port" example:"9303" description:"公共端口"`
+	ListenAddr         string              `json:"listen_addr" example:":9303" description:"监听地址"`
+	BindAddress        string              `json:"bind_address,omitempty" example:"127.0.0.1" description:"绑定的网卡地址，为空表示所有网卡"`
+	Workers            int                 `json:"workers" example:"1" description:"监听器数量"`
+	Type               string              `json:"type" example:"load_balancer" description:"代理类型：single / load_balancer"`
+	Strategy           string              `json:"strategy,omitempty" example:"round_robin" description:"负载均衡策略（负载均衡器）"`
+	StrategyOverridden bool                `json:"strategy_overridden,omitempty" example:"true" description:"负载均衡策略是否通过接口设置（否则为配置文件中的默认策略）"`
+	RequestsTotal      int64               `json:"requests_total" example:"4096" description:"端口累计转发的请求数"`
+	ActiveRequests     int64               `json:"active_requests" example:"3" description:"正在处理的请求数"`
+	ErrorsTotal        int64               `json:"errors_total" example:"7" description:"端口累计的失败请求数（返回 5xx）"`
+	Backends           []ProxyBackendStats `json:"backends" description:"后端（副本）"`
+	RequestRules       []RequestRuleStats  `json:"request_rules,omitempty" description:"请求过滤规则"`
+	Mirror             *MirrorStats        `json:"mirror,omitempty" description:"流量镜像"`
+}
+
+// ProxyStrategyRequest 设置负载均衡策略请求
+type ProxyStrategyRequest struct {
+	Strategy string `json:"strategy" binding:"omitempty,oneof=round_robin least_connections weighted" example:"least_connections" description:"负载均衡策略：round_robin / least_connections / weighted，为空表示恢复配置文件中的默认策略"`
+}
+
+// ServiceProxyStats 服务的端口代理统计信息
+type ServiceProxyStats struct {
+	Service string       `json:"service" example:"nginx-web" description:"服务名称"`
+	Proxies []ProxyStats `json:"proxies" description:"服务的端口代理，没有公共端口或服务已停止时为空"`
+}
+
+// ProxyOverview 全部端口代理的统计信息
+type ProxyOverview struct {
+	TotalProxies  int          `json:"total_proxies" example:"3" description:"端口代理总数"`
+	SingleProxies int          `json:"single_proxies" example:"1" description:"单副本代理数"`
+	LoadBalancers int          `json:"load_balancers" example:"2" description:"负载均衡器数"`
+	ProxyDetails  []ProxyStats `json:"proxy_details" description:"各端口代理，按公共端口排序"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// 配额范围
+const (
+	QuotaScopeNamespace = "namespace"
+	QuotaScopeToken     = "token"
+)
+
+// QuotaLimit 配额限制，0 或空表示不限制
+type QuotaLimit struct {
+	MaxServices int      `mapstructure:"max_services" json:"max_services,omitempty" example:"10" description:"最大服务数"`
+	MaxReplicas int      `mapstructure:"max_replicas" json:"max_replicas,omitempty" example:"20" description:"最大副本总数"`
+	MaxMemory   int      `mapstructure:"max_memory" json:"max_memory,omitempty" example:"8192" description:"最大内存总量（MB），设置后服务必须指定 memory_limit"`
+	PortRanges  []string `mapstructure:"port_ranges" json:"port_ranges,omitempty" example:"9300-9399" description:"允许使用的公共端口范围"`
+}
+
+// QuotaUsage 配额使用量
+type QuotaUsage struct {
+	Services int `json:"services" example:"3" description:"服务数"`
+	Replicas int `json:"replicas" example:"6" description:"副本总数"`
+	Memory   int `json:"memory" example:"3072" description:"内存总量（MB），副本数 × memory_limit"`
+}
+
+// QuotaStatus 配额及当前使用量
+type QuotaStatus struct {
+	Scope string     `json:"scope" example:"namespace" description:"配额范围: namespace / token"`
+	Name  string     `json:"name" example:"staging" description:"命名空间或令牌指纹"`
+	Limit QuotaLimit `json:"limit" description:"配额限制"`
+	Usage QuotaUsage `json:"usage" description:"当前使用量"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// 部署方式
+const (
+	DeploymentModeRolling   = "rolling"    // 滚动更新（默认）
+	DeploymentModeBlueGreen = "blue_green" // 蓝绿：新版本副本全部就绪后，由 promote 一次切换流量
+	DeploymentModeCanary    = "canary"     // 金丝雀：新旧版本副本同时接收流量，只能通过金丝雀接口发起
+)
+
+// CanaryRequest 金丝雀发布请求
+// @Description 按新配置额外创建金丝雀副本，与现有副本一起接收流量；确认后调用 promote 全量更新，或调用 abort 删除金丝雀副本
+type CanaryRequest struct {
+	ServiceRequest
+	CanaryReplicas int `json:"canary_replicas" example:"1" description:"金丝雀副本数，默认 1"`
+}
+
+// ReleaseStatus 进行中的发布状态
+// @Description 服务进行中的金丝雀或蓝绿发布，分别统计当前版本与新版本的副本
+type ReleaseStatus struct {
+	Service           string    `json:"service" example:"nginx-web" description:"服务名称"`
+	Mode              string    `json:"mode" example:"canary" description:"发布方式：canary / blue_green"`
+	StableImage       string    `json:"stable_image" example:"nginx:1.25" description:"当前版本镜像"`
+	StableReplicas    int       `json:"stable_replicas" example:"3" description:"当前版本副本数"`
+	CandidateImage    string    `json:"candidate_image" example:"nginx:1.27" description:"新版本镜像"`
+	CandidateReplicas int       `json:"candidate_replicas" example:"1" description:"新版本副本数"`
+	Serving           bool      `json:"serving" example:"true" description:"新版本副本是否已接收流量（蓝绿发布确认前为 false）"`
+	Actor             string    `json:"actor" example:"token:abcd****" description:"发起发布的操作者"`
+	CreatedAt         time.Time `json:"created_at" example:"2024-01-15T10:30:00Z" description:"开始时间"`
+}
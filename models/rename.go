@@ -0,0 +1,7 @@
+package models
+
+// RenameRequest 重命名服务请求
+type RenameRequest struct {
+	Name      string `json:"name" binding:"required,servicename" example:"web-frontend" description:"新的服务名称，可写成 命名空间.名称"`
+	Namespace string `json:"namespace,omitempty" binding:"omitempty,namespace" example:"staging" description:"可选的新命名空间，不填时取名称中的命名空间，都没有则为 default"`
+}
@@ -0,0 +1,119 @@
+package models
+
+import "time"
+
+// ReplicaMount 副本挂载
+type ReplicaMount struct {
+	Type        string `json:"type" example:"bind" description:"挂载类型：bind / volume / tmpfs"`
+	Name        string `json:"name,omitempty" example:"" description:"卷名称（仅 volume）"`
+	Source      string `json:"source" example:"/data/nginx" description:"主机路径"`
+	Destination string `json:"destination" example:"/usr/share/nginx/html" description:"容器内路径"`
+	Mode        string `json:"mode,omitempty" example:"ro" description:"挂载选项"`
+	ReadWrite   bool   `json:"read_write" example:"false" description:"是否可写"`
+}
+
+// ReplicaNetwork 副本在某个网络中的地址
+type ReplicaNetwork struct {
+	Network    string `json:"network" example:"bridge" description:"网络名称"`
+	IPAddress  string `json:"ip_address" example:"172.17.0.2" description:"IPv4 地址"`
+	Gateway    string `json:"gateway" example:"172.17.0.1" description:"网关"`
+	MacAddress string `json:"mac_address" example:"02:42:ac:11:00:02" description:"MAC 地址"`
+}
+
+// ReplicaState 副本运行状态
+type ReplicaState struct {
+	Status        string     `json:"status" example:"running" description:"容器状态：created / running / restarting / exited / dead"`
+	Running       bool       `json:"running" example:"true" description:"是否运行中"`
+	OOMKilled     bool       `json:"oom_killed" example:"false" description:"是否因内存不足被杀死"`
+	Pid           int        `json:"pid" example:"12345" description:"主进程 PID"`
+	ExitCode      int        `json:"exit_code" example:"0" description:"最近一次退出码"`
+	Error         string     `json:"error,omitempty" description:"最近一次启动错误"`
+	StartedAt     *time.Time `json:"started_at,omitempty" example:"2024-01-15T10:30:00Z" description:"最近一次启动时间"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty" example:"2024-01-15T10:29:55Z" description:"最近一次退出时间"`
+	RestartCount  int        `json:"restart_count" example:"2" description:"Docker 自动重启的次数"`
+	Health        string     `json:"health,omitempty" example:"healthy" description:"健康检查状态，未配置 HEALTHCHECK 时为空"`
+	FailingStreak int        `json:"failing_streak,omitempty" example:"0" description:"连续健康检查失败次数"`
+	HealthLog     []string   `json:"health_log,omitempty" description:"最近的健康检查输出"`
+}
+
+// ReplicaDetail 副本详情
+// @Description 单个副本的检查详情（docker inspect），比服务状态中的实例摘要更完整；引用密钥的环境变量显示为 secret://<名称>
+type ReplicaDetail struct {
+	Service       string            `json:"service" example:"nginx-web" description:"服务名称"`
+	Index         int               `json:"index" example:"0" description:"副本编号"`
+	ContainerID   string            `json:"container_id" example:"abc123def4567890" description:"容器 ID"`
+	ContainerName string            `json:"container_name" example:"onedock-nginx-web-9203-30001-0" description:"容器名称"`
+	Image         string            `json:"image" example:"nginx:alpine" description:"镜像"`
+	ImageID       string            `json:"image_id" example:"sha256:1f3b..." description:"镜像 ID"`
+	CreatedAt     time.Time         `json:"created_at" example:"2024-01-15T10:30:00Z" description:"创建时间"`
+	Entrypoint    []string          `json:"entrypoint,omitempty" description:"入口"`
+	Command       []string          `json:"command,omitempty" description:"启动命令"`
+	WorkingDir    string            `json:"working_dir,omitempty" example:"/app" description:"工作目录"`
+	Environment   map[string]string `json:"environment" description:"环境变量，引用密钥的变量显示为 secret://<名称>，名称像密码、令牌的变量显示为 ******"`
+	Labels        map[string]string `json:"labels" description:"容器标签"`
+	Mounts        []ReplicaMount    `json:"mounts" description:"挂载"`
+	NetworkMode   string            `json:"network_mode" example:"bridge" description:"网络模式"`
+	Networks      []ReplicaNetwork  `json:"networks" description:"加入的网络"`
+	HostPort      int               `json:"host_port,omitempty" example:"30001" description:"映射到主机的端口，端口代理转发到此端口"`
+	InternalPort  int               `json:"internal_port,omitempty" example:"80" description:"容器内部端口"`
+	RestartPolicy string            `json:"restart_policy" example:"always" description:"重启策略，通过 stop 接口停止的副本为 no"`
+	MemoryLimit   int64             `json:"memory_limit" example:"134217728" description:"内存上限（字节，0 表示不限制）"`
+	State         ReplicaState      `json:"state" description:"运行状态"`
+}
+
+// ReplicaStats 副本的资源使用快照
+type ReplicaStats struct {
+	Index         int     `json:"index" example:"0" description:"副本编号"`
+	ContainerID   string  `json:"container_id" example:"3f2a1b4c5d6e" description:"容器 ID"`
+	State         string  `json:"state" example:"running" description:"容器状态，非 running 的副本不采样"`
+	CPUPercent    float64 `json:"cpu_percent" example:"12.5" description:"CPU 使用率（100 表示占满一个核）"`
+	MemoryUsage   uint64  `json:"memory_usage" example:"134217728" description:"内存使用量（字节，不含页缓存）"`
+	MemoryLimit   uint64  `json:"memory_limit" example:"536870912" description:"内存上限（字节，未设置限制时为主机内存）"`
+	MemoryPercent float64 `json:"memory_percent" example:"25" description:"内存使用率"`
+	NetworkRx     uint64  `json:"network_rx" example:"1048576" description:"累计接收（字节）"`
+	NetworkTx     uint64  `json:"network_tx" example:"2097152" description:"累计发送（字节）"`
+	BlockRead     uint64  `json:"block_read" example:"4096" description:"块设备累计读取（字节）"`
+	BlockWrite    uint64  `json:"block_write" example:"8192" description:"块设备累计写入（字节）"`
+	PIDs          uint64  `json:"pids" example:"7" description:"进程（线程）数"`
+	Error         string  `json:"error,omitempty" description:"采样失败的原因"`
+}
+
+// ServiceStats 服务全部副本的资源使用快照
+// @Description 由 Docker stats 接口采样，CPU 使用率为约 1 秒内的平均值，网络和块设备为容器启动以来的累计值
+type ServiceStats struct {
+	Service     string         `json:"service" example:"nginx-web" description:"服务名称"`
+	SampledAt   time.Time      `json:"sampled_at" example:"2024-01-15T10:30:00Z" description:"采样时间"`
+	Replicas    []ReplicaStats `json:"replicas" description:"按副本编号排序"`
+	CPUPercent  float64        `json:"cpu_percent" example:"25" description:"成功采样副本的 CPU 使用率之和"`
+	MemoryUsage uint64         `json:"memory_usage" example:"268435456" description:"成功采样副本的内存使用量之和（字节）"`
+	NetworkRx   uint64         `json:"network_rx" example:"2097152" description:"成功采样副本的累计接收之和（字节）"`
+	NetworkTx   uint64         `json:"network_tx" example:"4194304" description:"成功采样副本的累计发送之和（字节）"`
+	BlockRead   uint64         `json:"block_read" example:"8192" description:"成功采样副本的块设备读取之和（字节）"`
+	BlockWrite  uint64         `json:"block_write" example:"16384" description:"成功采样副本的块设备写入之和（字节）"`
+}
+
+// 环境变量来源
+const (
+	EnvSourceImage       = "image"       // 镜像或 Docker 设置的变量
+	EnvSourceEnvFile     = "env_file"    // 服务的环境变量文件
+	EnvSourceEnvironment = "environment" // 服务配置中直接指定的变量，覆盖环境变量文件中的同名变量
+)
+
+// EnvVariable 副本中的一个环境变量
+type EnvVariable struct {
+	Name     string `json:"name" example:"DB_HOST" description:"变量名"`
+	Value    string `json:"value" example:"10.0.0.5" description:"副本中的值；创建副本时引用密钥的变量显示为 secret://<名称>，名称像密码、令牌的变量显示为 ******，不返回明文"`
+	Source   string `json:"source" example:"environment" description:"来源：image / env_file / environment"`
+	Differs  bool   `json:"differs,omitempty" example:"false" description:"副本中的值与当前配置不同（副本在配置或环境变量文件修改前创建），重建副本后生效"`
+	Expected string `json:"expected,omitempty" example:"10.0.0.6" description:"与副本不同时，按当前配置创建副本将使用的值"`
+}
+
+// ServiceEnvironment 服务实际生效的环境变量
+// @Description 合并环境变量文件与 environment、替换 ${host_ip} 等占位符后副本实际运行的环境变量，引用密钥的变量不返回明文
+type ServiceEnvironment struct {
+	Service   string        `json:"service" example:"nginx-web" description:"服务名称"`
+	Replica   string        `json:"replica,omitempty" example:"onedock-nginx-web-9203-30001-0" description:"读取环境变量的副本容器，服务没有副本时为空，只返回配置中的变量"`
+	EnvFile   string        `json:"env_file,omitempty" example:"/etc/onedock/nginx-web.env" description:"环境变量文件路径（在 OneDock 所在主机上读取）"`
+	Variables []EnvVariable `json:"variables" description:"按变量名排序的环境变量"`
+	Warnings  []string      `json:"warnings,omitempty" description:"环境变量文件不可读、占位符无法解析等提示"`
+}
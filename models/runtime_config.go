@@ -0,0 +1,25 @@
+package models
+
+// RuntimeConfig 可在运行时修改的配置
+// @Description 修改后写回配置文件并立即生效，无需重启 OneDock
+type RuntimeConfig struct {
+	CacheTTL            int    `json:"cache_ttl" example:"300" description:"端口映射缓存时间（秒），container.cache_ttl"`
+	LoadBalanceStrategy string `json:"load_balance_strategy" example:"round_robin" description:"默认负载均衡策略，container.load_balance_strategy；通过接口为服务单独设置的策略不受影响"`
+	AutoRange           string `json:"auto_range" example:"20000-29999" description:"自动分配公共端口的范围，ports.auto_range"`
+	MinPublicPort       int    `json:"min_public_port" example:"1024" description:"低于该值的公共端口视为系统端口，ports.min_public_port"`
+	ProxyReadTimeout    int    `json:"proxy_read_timeout" example:"30" description:"端口代理读取请求超时（秒），proxy.read_timeout"`
+	ProxyWriteTimeout   int    `json:"proxy_write_timeout" example:"30" description:"端口代理写入响应超时（秒），proxy.write_timeout"`
+	LogLevel            string `json:"log_level" example:"INFO" description:"日志级别，local.logger.level"`
+	ConfigFile          string `json:"config_file" example:"./config.toml" description:"修改写回的配置文件"`
+}
+
+// RuntimeConfigUpdate 修改运行时配置请求，只修改提交的字段
+type RuntimeConfigUpdate struct {
+	CacheTTL            *int    `json:"cache_ttl,omitempty" binding:"omitempty,min=1" example:"60" description:"端口映射缓存时间（秒）"`
+	LoadBalanceStrategy *string `json:"load_balance_strategy,omitempty" binding:"omitempty,oneof=round_robin least_connections weighted" example:"least_connections" description:"默认负载均衡策略：round_robin / least_connections / weighted"`
+	AutoRange           *string `json:"auto_range,omitempty" example:"20000-24999" description:"自动分配公共端口的范围，格式 起始-结束"`
+	MinPublicPort       *int    `json:"min_public_port,omitempty" binding:"omitempty,port" example:"1024" description:"最小公共端口"`
+	ProxyReadTimeout    *int    `json:"proxy_read_timeout,omitempty" binding:"omitempty,min=1" example:"60" description:"端口代理读取请求超时（秒）"`
+	ProxyWriteTimeout   *int    `json:"proxy_write_timeout,omitempty" binding:"omitempty,min=1" example:"60" description:"端口代理写入响应超时（秒）"`
+	LogLevel            *string `json:"log_level,omitempty" binding:"omitempty,oneof=DEBUG INFO WARN ERROR debug info warn error" example:"DEBUG" description:"日志级别：DEBUG / INFO / WARN / ERROR"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SecretRefPrefix 环境变量中引用密钥的前缀，如 secret://db_password
+const SecretRefPrefix = "secret://"
+
+// SecretRequest 创建或更新密钥的请求
+// @Description 密钥值加密保存，任何接口都不会返回明文
+type SecretRequest struct {
+	Value string `json:"value" binding:"required" example:"s3cr3t" description:"密钥值"`
+}
+
+// Secret 密钥信息（不含密钥值）
+// @Description 服务环境变量写成 secret://<名称> 即可在创建容器时注入密钥值
+type Secret struct {
+	Name      string    `json:"name" example:"db_password" description:"密钥名称"`
+	UsedBy    []string  `json:"used_by,omitempty" example:"nginx-web" description:"引用该密钥的服务"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z" description:"创建时间"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-15T10:30:00Z" description:"更新时间"`
+}
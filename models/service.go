@@ -22,35 +22,99 @@ const (
 type VolumeMount = dockerclient.VolumeMount
 type ContainerInfo = dockerclient.ContainerInfo
 type PortMapping = dockerclient.PortMapping
+type RequestRule = dockerclient.RequestRule
+type MirrorConfig = dockerclient.MirrorConfig
+type InitContainer = dockerclient.InitContainer
+type Sidecar = dockerclient.Sidecar
 
 // Service API响应用的服务信息
 type Service struct {
-	ID           string        `json:"id" example:"svc_1234567890" description:"服务唯一标识"`
-	Name         string        `json:"name" example:"nginx-web" description:"服务名称"`
-	Image        string        `json:"image" example:"nginx" description:"Docker镜像名称"`
-	Tag          string        `json:"tag" example:"alpine" description:"镜像标签"`
-	Status       ServiceStatus `json:"status" example:"running" description:"服务运行状态"`
-	PublicPort   int           `json:"public_port" example:"30000" description:"对外暴露端口"`
-	InternalPort int           `json:"internal_port" example:"80" description:"容器内部端口"`
-	Replicas     int           `json:"replicas" example:"3" description:"实际运行的副本数量"`
-	CreatedAt    time.Time     `json:"created_at" example:"2023-01-01T00:00:00Z" description:"创建时间"`
-	UpdatedAt    time.Time     `json:"updated_at" example:"2023-01-01T00:00:00Z" description:"更新时间"`
+	ID           string            `json:"id" example:"svc_1234567890" description:"服务唯一标识"`
+	Name         string            `json:"name" example:"nginx-web" description:"服务名称（非默认命名空间时为 命名空间.名称）"`
+	Namespace    string            `json:"namespace" example:"default" description:"命名空间"`
+	Image        string            `json:"image" example:"nginx" description:"Docker镜像名称"`
+	Tag          string            `json:"tag" example:"alpine" description:"镜像标签"`
+	Status       ServiceStatus     `json:"status" example:"running" description:"服务运行状态"`
+	PublicPort   int               `json:"public_port" example:"30000" description:"对外暴露端口"`
+	InternalPort int               `json:"internal_port" example:"80" description:"容器内部端口"`
+	Replicas     int               `json:"replicas" example:"3" description:"实际运行的副本数量"`
+	MemoryLimit  int               `json:"memory_limit,omitempty" example:"512" description:"单个副本内存上限（MB）"`
+	BindAddress  string            `json:"bind_address,omitempty" example:"127.0.0.1" description:"代理监听地址"`
+	ProxyWorkers int               `json:"proxy_workers,omitempty" example:"4" description:"代理监听器数量"`
+	RequestRules []RequestRule     `json:"request_rules,omitempty" description:"请求过滤规则"`
+	Mirror       *MirrorConfig     `json:"mirror,omitempty" description:"流量镜像配置"`
+	Labels       map[string]string `json:"labels,omitempty" description:"用户标签（服务列表和搜索接口返回）"`
+	CreatedAt    time.Time         `json:"created_at" example:"2023-01-01T00:00:00Z" description:"创建时间"`
+	UpdatedAt    time.Time         `json:"updated_at" example:"2023-01-01T00:00:00Z" description:"更新时间"`
 }
 
 // ServiceRequest 直接使用dockerclient.Service结构（继承并添加JSON标签）
 type ServiceRequest struct {
-	Name         string            `json:"name" binding:"required" example:"nginx-web" description:"服务名称"`
+	Name         string            `json:"name" binding:"required,servicename" example:"nginx-web" description:"服务名称"`
+	Namespace    string            `json:"namespace,omitempty" binding:"omitempty,namespace" example:"staging" description:"可选的命名空间，不填则为 default；非默认命名空间的服务全名为 命名空间.名称"`
 	Image        string            `json:"image" binding:"required" example:"nginx" description:"Docker镜像名称"`
 	Tag          string            `json:"tag" binding:"required" example:"alpine" description:"镜像标签"`
-	InternalPort int               `json:"internal_port" binding:"required" example:"80" description:"容器内部端口"`
-	Replicas     int               `json:"replicas" example:"1" description:"副本数量"`
+	InternalPort int               `json:"internal_port" binding:"required,port" example:"80" description:"容器内部端口"`
+	Replicas     int               `json:"replicas" binding:"min=0" example:"1" description:"副本数量"`
 	Environment  map[string]string `json:"environment" description:"环境变量"`
 	EnvFile      string            `json:"env_file" description:"环境变量文件路径"`
 	Volumes      []VolumeMount     `json:"volumes" description:"卷挂载配置"`
 	Entrypoint   []string          `json:"entrypoint" description:"容器入口点覆盖"`
 	Command      []string          `json:"command" description:"启动命令覆盖"`
 	WorkingDir   string            `json:"working_dir" example:"/app" description:"工作目录"`
-	PublicPort   int               `json:"public_port,omitempty" example:"30000" description:"可选的对外暴露端口，不填则自动分配"`
+	MemoryLimit  int               `json:"memory_limit,omitempty" binding:"min=0" example:"512" description:"可选的单个副本内存上限（MB），不填则不限制"`
+	PublicPort   int               `json:"public_port,omitempty" binding:"omitempty,port" example:"30000" description:"可选的对外暴露端口，不填则自动分配"`
+	BindAddress  string            `json:"bind_address,omitempty" example:"127.0.0.1" description:"可选的代理监听地址，不填则使用全局配置 proxy.bind_address"`
+	ProxyWorkers int               `json:"proxy_workers,omitempty" binding:"min=0" example:"4" description:"可选的代理监听器数量，大于1时使用 SO_REUSEPORT 多监听器，不填则使用全局配置 proxy.workers"`
+	RequestRules []RequestRule     `json:"request_rules,omitempty" description:"请求过滤规则，命中任一规则的请求由代理直接返回 403"`
+	Mirror       *MirrorConfig     `json:"mirror,omitempty" description:"流量镜像配置，按比例将请求异步复制到另一个服务"`
+	Labels       map[string]string `json:"labels,omitempty" description:"用户标签，如 {\"team\": \"payments\", \"env\": \"preview\"}，可按标签搜索服务或批量删除"`
+
+	InitContainers []InitContainer `json:"init_containers,omitempty" description:"初始化容器，每个副本的主容器创建前按顺序运行，全部以退出码 0 结束后才创建主容器"`
+	Sidecars       []Sidecar       `json:"sidecars,omitempty" description:"边车容器，随每个副本创建，与主容器共享网络和卷挂载，随主容器启动、停止和删除"`
+
+	UpdateStrategy *UpdateStrategy `json:"update_strategy,omitempty" description:"滚动更新策略，仅在更新已有服务时生效，不填则逐个先建后删"`
+	DeploymentMode string          `json:"deployment_mode,omitempty" binding:"omitempty,oneof=rolling blue_green" example:"blue_green" description:"更新方式：rolling（默认）/ blue_green，蓝绿更新需调用 promote 切换流量"`
+	DryRun         bool            `json:"dry_run,omitempty" example:"false" description:"只校验请求并返回将要执行的变更，不创建或修改任何容器"`
+}
+
+// UpdateStrategy 滚动更新策略
+// @Description 每批更新 max_surge + max_unavailable 个副本，批内并行执行
+type UpdateStrategy struct {
+	MaxSurge       int   `json:"max_surge" example:"1" description:"每批先创建新容器再删除旧容器的副本数，更新期间最多多出的副本数"`
+	MaxUnavailable int   `json:"max_unavailable" example:"0" description:"每批先删除旧容器再创建新容器的副本数，更新期间最多不可用的副本数"`
+	AutoRollback   *bool `json:"auto_rollback,omitempty" example:"true" description:"更新失败时是否自动回滚到旧配置，不填则使用全局配置 container.auto_rollback"`
+	PauseAfter     int   `json:"pause_after,omitempty" example:"1" description:"更新完指定数量的副本后自动暂停，通过 resume 接口继续或 abort 接口中止"`
+}
+
+// RolloutState 滚动更新状态
+type RolloutState string
+
+const (
+	RolloutRunning   RolloutState = "running"   // 更新中
+	RolloutPaused    RolloutState = "paused"    // 已暂停
+	RolloutCompleted RolloutState = "completed" // 已完成
+	RolloutFailed    RolloutState = "failed"    // 更新失败
+	RolloutAborted   RolloutState = "aborted"   // 已中止并回滚
+)
+
+// RolloutStatus 滚动更新进度
+// @Description 服务最近一次滚动更新的状态与进度
+type RolloutStatus struct {
+	Service        string       `json:"service" example:"nginx-web" description:"服务名称"`
+	State          RolloutState `json:"state" example:"paused" description:"状态：running / paused / completed / failed / aborted"`
+	Image          string       `json:"image" example:"nginx:1.25" description:"目标镜像"`
+	Total          int          `json:"total" example:"3" description:"需要更新的副本数"`
+	Updated        int          `json:"updated" example:"1" description:"已更新成功的副本数"`
+	Failed         int          `json:"failed" example:"0" description:"更新失败的副本数"`
+	MaxSurge       int          `json:"max_surge" example:"1" description:"每批先建后删的副本数"`
+	MaxUnavailable int          `json:"max_unavailable" example:"0" description:"每批先删后建的副本数"`
+	PauseAfter     int          `json:"pause_after,omitempty" example:"1" description:"自动暂停的副本数"`
+	Actor          string       `json:"actor" example:"token:abcd****" description:"发起更新的操作者"`
+	Message        string       `json:"message,omitempty" example:"paused after 1 replicas" description:"状态说明"`
+	StartedAt      time.Time    `json:"started_at" example:"2024-01-15T10:30:00Z" description:"开始时间"`
+	UpdatedAt      time.Time    `json:"updated_at" example:"2024-01-15T10:31:00Z" description:"最近状态变化时间"`
+	FinishedAt     *time.Time   `json:"finished_at,omitempty" example:"2024-01-15T10:32:00Z" description:"结束时间"`
 }
 
 // ScaleRequest 扩缩容请求
@@ -59,6 +123,51 @@ type ScaleRequest struct {
 	Replicas int `json:"replicas" binding:"required" example:"3" description:"目标副本数量"`
 }
 
+// BulkScaleRequest 批量扩缩容请求
+// @Description 一次调整多个服务的副本数，副本数为 0 表示删除服务，适合整体启停一个环境
+type BulkScaleRequest struct {
+	Services map[string]int `json:"services" binding:"required" description:"服务名称到目标副本数的映射，如 {\"nginx-web\": 3, \"api\": 0}"`
+}
+
+// BulkScaleResult 批量扩缩容中单个服务的结果
+type BulkScaleResult struct {
+	Name      string `json:"name" example:"nginx-web" description:"服务名称"`
+	Success   bool   `json:"success" example:"true" description:"是否扩缩容成功"`
+	Previous  int    `json:"previous" example:"1" description:"调整前的副本数"`
+	Replicas  int    `json:"replicas" example:"3" description:"目标副本数"`
+	Error     string `json:"error,omitempty" example:"service api is locked by token:abcd****, unlock it first" description:"失败原因"`
+	ErrorCode string `json:"error_code,omitempty" example:"SERVICE_LOCKED" description:"失败的错误码"`
+}
+
+// BulkDeleteRequest 批量删除请求
+// @Description 按服务列表，或按命名空间和标签选择器删除多个服务；confirm 不为 true 时只返回将被删除的服务
+// @Description trash 不填时取配置 trash.default，为 true 时逐个放入回收站
+type BulkDeleteRequest struct {
+	Services  []string `json:"services,omitempty" example:"preview-42.web,preview-42.api" description:"要删除的服务名称，与 namespace、selector 二选一"`
+	Namespace string   `json:"namespace,omitempty" binding:"omitempty,namespace" example:"preview-42" description:"删除命名空间中的服务"`
+	Selector  string   `json:"selector,omitempty" example:"env=preview,team" description:"标签选择器：逗号分隔的 key=value（标签值相等）或 key（存在该标签），全部满足才选中"`
+	Confirm   bool     `json:"confirm" example:"true" description:"确认删除，不为 true 时只预览选中的服务"`
+	Trash     *bool    `json:"trash,omitempty" example:"true" description:"放入回收站，不填则取配置 trash.default"`
+}
+
+// BulkDeleteResult 批量删除中单个服务的结果
+type BulkDeleteResult struct {
+	Name      string `json:"name" example:"preview-42.web" description:"服务名称"`
+	Success   bool   `json:"success" example:"true" description:"是否删除成功"`
+	Trashed   bool   `json:"trashed,omitempty" example:"true" description:"是否放入了回收站"`
+	Error     string `json:"error,omitempty" example:"service preview-42.web not found" description:"失败原因"`
+	ErrorCode string `json:"error_code,omitempty" example:"SERVICE_NOT_FOUND" description:"失败的错误码"`
+}
+
+// ServiceQuery 服务搜索条件，空值表示不限
+type ServiceQuery struct {
+	Namespace string
+	Selector  string // 标签选择器，如 team=payments,env
+	Image     string // 镜像名称，可带标签，如 nginx 或 nginx:alpine
+	Status    ServiceStatus
+	Port      int // 公共端口或内部端口
+}
+
 // ServiceInstanceInfo 服务实例详细信息
 type ServiceInstanceInfo struct {
 	ID            string            `json:"id" example:"inst_1234567890" description:"实例唯一标识"`
@@ -80,6 +189,7 @@ type ServiceInstanceInfo struct {
 	CPUUsage      float64           `json:"cpu_usage" example:"0.5" description:"CPU使用率"`
 	MemoryUsage   float64           `json:"memory_usage" example:"64.5" description:"内存使用(MB)"`
 	MemoryLimit   float64           `json:"memory_limit" example:"128.0" description:"内存限制(MB)"`
+	Candidate     bool              `json:"candidate,omitempty" example:"false" description:"是否为发布中的新版本副本（金丝雀或蓝绿）"`
 }
 
 // ServiceStatusResponse 服务状态响应
@@ -93,6 +203,223 @@ type ServiceStatusResponse struct {
 	Instances       []ServiceInstanceInfo `json:"instances" description:"实例详细信息列表"`
 	LoadBalancer    string                `json:"load_balancer" example:"round_robin" description:"负载均衡策略"`
 	AccessURL       string                `json:"access_url" example:"http://localhost:30000" description:"访问地址"`
+	Release         *ReleaseStatus        `json:"release,omitempty" description:"进行中的金丝雀或蓝绿发布"`
+	Lock            *ServiceLock          `json:"lock,omitempty" description:"服务锁定信息，未锁定时为空"`
+	Operations      *OperationQueue       `json:"operations,omitempty" description:"正在执行和排队等待的修改操作，空闲时为空"`
 	CreatedAt       time.Time             `json:"created_at" example:"2023-01-01T00:00:00Z" description:"创建时间"`
 	UpdatedAt       time.Time             `json:"updated_at" example:"2023-01-01T00:00:00Z" description:"更新时间"`
 }
+
+// 部署历史操作类型
+const (
+	RevisionActionDeploy   = "deploy"   // 首次部署
+	RevisionActionUpdate   = "update"   // 滚动更新
+	RevisionActionRollback = "rollback" // 回滚
+	RevisionActionRename   = "rename"   // 重命名
+)
+
+// 上下文中保存请求方信息的键
+const (
+	ContextKeyActor     = "onedock-actor"      // 操作者标识
+	ContextKeyTokenID   = "onedock-token-id"   // 令牌指纹，未启用权限验证时为空
+	ContextKeyAdmin     = "onedock-admin"      // 是否为管理员令牌（bool）
+	ContextKeyScope     = "onedock-scope"      // 令牌可操作的服务范围（*TokenScope），为空表示不限制
+	ContextKeyRequestID = "onedock-request-id" // 请求 ID，用于在日志中关联同一请求
+)
+
+// DeploymentRevision 部署历史记录
+// @Description 每次部署或更新成功后记录一条，包含完整的服务配置快照
+type DeploymentRevision struct {
+	Revision  int            `json:"revision" example:"3" description:"版本号，从 1 开始递增"`
+	Action    string         `json:"action" example:"update" description:"操作类型：deploy / update / rollback / rename"`
+	Actor     string         `json:"actor" example:"token:abcd****" description:"操作者"`
+	Spec      ServiceRequest `json:"spec" description:"服务配置快照"`
+	Image     string         `json:"image" example:"nginx:alpine" description:"镜像"`
+	CreatedAt time.Time      `json:"created_at" example:"2024-01-15T10:30:00Z" description:"记录时间"`
+	Note      string         `json:"note,omitempty" example:"rollback to revision 2" description:"备注"`
+}
+
+// ReconcileResult 单个服务的一次调和结果
+// @Description 调和循环比较期望状态与实际容器，补齐缺失副本、移除多余副本、启动意外停止的副本并恢复端口代理
+type ReconcileResult struct {
+	Service string   `json:"service" example:"nginx-web" description:"服务名称"`
+	Desired int      `json:"desired" example:"3" description:"期望副本数"`
+	Actual  int      `json:"actual" example:"2" description:"调和前的容器数"`
+	Actions []string `json:"actions,omitempty" example:"created 1 replicas" description:"执行的操作"`
+	Skipped string   `json:"skipped,omitempty" example:"rollout in progress" description:"跳过原因"`
+	Error   string   `json:"error,omitempty" description:"调和失败原因"`
+}
+
+// ReplicaDrift 单个副本与期望状态的差异
+type ReplicaDrift struct {
+	Container   string   `json:"container" example:"onedock-nginx-web-p9203-c30001-0" description:"容器名称"`
+	State       string   `json:"state" example:"running" description:"容器运行状态"`
+	Image       string   `json:"image" example:"nginx:1.25" description:"容器实际镜像"`
+	ConfigHash  string   `json:"config_hash,omitempty" example:"3f2a9c1d0b7e4a55" description:"容器配置哈希"`
+	Drifted     bool     `json:"drifted" example:"true" description:"是否偏离期望状态"`
+	Differences []string `json:"differences,omitempty" example:"image nginx:1.25, expected nginx:alpine" description:"差异说明"`
+}
+
+// DriftReport 服务漂移报告
+// @Description 比较期望状态与实际运行的容器（镜像、配置哈希、副本数），只报告差异，不做任何修改
+type DriftReport struct {
+	Service         string         `json:"service" example:"nginx-web" description:"服务名称"`
+	Drifted         bool           `json:"drifted" example:"true" description:"是否存在漂移"`
+	DesiredImage    string         `json:"desired_image" example:"nginx:alpine" description:"期望镜像"`
+	DesiredHash     string         `json:"desired_config_hash" example:"3f2a9c1d0b7e4a55" description:"期望配置哈希"`
+	DesiredReplicas int            `json:"desired_replicas" example:"3" description:"期望副本数"`
+	ActualReplicas  int            `json:"actual_replicas" example:"2" description:"实际容器数"`
+	Stopped         bool           `json:"stopped" example:"false" description:"服务是否已手动停止"`
+	Differences     []string       `json:"differences,omitempty" example:"replicas 2, expected 3" description:"服务级差异"`
+	Replicas        []ReplicaDrift `json:"replicas" description:"各副本差异"`
+	CheckedAt       time.Time      `json:"checked_at" example:"2024-01-15T10:30:00Z" description:"检查时间"`
+}
+
+// DesiredState 服务期望状态
+// @Description 持久化保存的服务完整配置，用于扩容、回滚及重启后恢复环境变量、卷挂载等无法从容器标签获取的配置
+type DesiredState struct {
+	Name       string         `json:"name" example:"nginx-web" description:"服务名称"`
+	Replicas   int            `json:"replicas" example:"3" description:"期望副本数"`
+	Stopped    bool           `json:"stopped" example:"false" description:"是否已手动停止"`
+	Spec       ServiceRequest `json:"spec" description:"服务完整配置"`
+	Persistent bool           `json:"persistent" example:"true" description:"期望状态是否已持久化到磁盘"`
+	CreatedAt  time.Time      `json:"created_at" example:"2024-01-15T10:30:00Z" description:"首次部署时间"`
+	UpdatedAt  time.Time      `json:"updated_at" example:"2024-01-15T10:30:00Z" description:"最近修改时间"`
+}
+
+// BatchDeployResult 批量部署中单个服务的结果
+type BatchDeployResult struct {
+	Name    string       `json:"name" example:"nginx-web" description:"服务名称"`
+	Success bool         `json:"success" example:"true" description:"是否部署成功"`
+	Service *Service     `json:"service,omitempty" description:"部署后的服务信息"`
+	Error   string       `json:"error,omitempty" example:"public port cannot be empty" description:"失败原因"`
+	Errors  []FieldError `json:"errors,omitempty" description:"请求参数校验失败时的字段错误"`
+}
+
+// ScaleScheduleRequest 添加定时扩缩容规则的请求
+// @Description cron 表达式为 5 段（分 时 日 月 周），按服务器本地时区执行，如 "0 9 * * 1-5" 表示工作日 09:00
+type ScaleScheduleRequest struct {
+	Cron     string `json:"cron" binding:"required" example:"0 9 * * 1-5" description:"cron 表达式"`
+	Replicas int    `json:"replicas" binding:"required,min=1" example:"5" description:"到点后调整到的副本数"`
+	Note     string `json:"note,omitempty" example:"工作日早高峰" description:"备注"`
+}
+
+// ScaleSchedule 定时扩缩容规则
+// @Description 到达 cron 表达式指定的时间时将服务调整到指定副本数
+type ScaleSchedule struct {
+	ID        int64      `json:"id" example:"1" description:"规则 ID"`
+	Service   string     `json:"service" example:"nginx-web" description:"服务名称"`
+	Cron      string     `json:"cron" example:"0 9 * * 1-5" description:"cron 表达式"`
+	Replicas  int        `json:"replicas" example:"5" description:"目标副本数"`
+	Note      string     `json:"note,omitempty" example:"工作日早高峰" description:"备注"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty" example:"2024-01-15T09:00:00+08:00" description:"下次执行时间"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty" example:"2024-01-12T09:00:00+08:00" description:"上次执行时间"`
+	LastError string     `json:"last_error,omitempty" example:"" description:"上次执行失败的原因"`
+	CreatedAt time.Time  `json:"created_at" example:"2024-01-10T10:30:00Z" description:"创建时间"`
+}
+
+// AutoscalePolicy 自动扩缩容策略
+// @Description 定期采集副本的资源使用率和端口代理的请求速率，按 ceil(当前副本数 × 当前值 / 目标值) 在最小、最大副本数之间调整，至少设置一个目标
+type AutoscalePolicy struct {
+	MinReplicas       int `json:"min_replicas" binding:"required,min=1" example:"2" description:"最小副本数"`
+	MaxReplicas       int `json:"max_replicas" binding:"required,min=1" example:"10" description:"最大副本数"`
+	TargetCPU         int `json:"target_cpu,omitempty" example:"70" description:"目标平均 CPU 使用率（%，100 表示占满一个核），0 表示不按 CPU 扩缩容"`
+	TargetMemory      int `json:"target_memory,omitempty" example:"80" description:"目标平均内存使用率（%，相对 memory_limit），0 表示不按内存扩缩容"`
+	TargetRPS         int `json:"target_rps,omitempty" example:"100" description:"目标每副本每秒请求数（来自端口代理统计），0 表示不按请求速率扩缩容"`
+	TargetConnections int `json:"target_connections,omitempty" example:"20" description:"目标每副本并发请求数（来自端口代理统计），0 表示不按并发扩缩容"`
+	ScaleUpCooldown   int `json:"scale_up_cooldown,omitempty" example:"60" description:"扩容冷却时间（秒），默认 60"`
+	ScaleDownCooldown int `json:"scale_down_cooldown,omitempty" example:"300" description:"缩容冷却时间（秒），默认 300"`
+}
+
+// AutoscaleStatus 自动扩缩容状态
+// @Description 策略及最近一次评估的指标和结论
+type AutoscaleStatus struct {
+	Service         string          `json:"service" example:"nginx-web" description:"服务名称"`
+	Policy          AutoscalePolicy `json:"policy" description:"自动扩缩容策略"`
+	CurrentReplicas int             `json:"current_replicas" example:"3" description:"评估时的副本数"`
+	DesiredReplicas int             `json:"desired_replicas" example:"4" description:"按指标计算的期望副本数"`
+	CPUPercent      float64         `json:"cpu_percent" example:"85.2" description:"平均 CPU 使用率（%）"`
+	MemoryPercent   float64         `json:"memory_percent" example:"40.5" description:"平均内存使用率（%）"`
+	RequestsPerSec  float64         `json:"requests_per_sec" example:"320.5" description:"服务每秒请求数（全部副本）"`
+	ActiveRequests  int64           `json:"active_requests" example:"12" description:"服务当前并发请求数（全部副本）"`
+	Message         string          `json:"message,omitempty" example:"scaled from 3 to 4 replicas" description:"评估结论"`
+	LastCheckAt     *time.Time      `json:"last_check_at,omitempty" example:"2024-01-15T10:30:00Z" description:"最近评估时间"`
+	LastScaleAt     *time.Time      `json:"last_scale_at,omitempty" example:"2024-01-15T10:25:00Z" description:"最近自动扩缩容时间"`
+}
+
+// 孤立容器的原因
+const (
+	OrphanReasonUnparsable   = "unparsable_name"   // 带管理前缀但名称格式无法解析
+	OrphanReasonNoService    = "service_not_found" // 服务没有期望状态（已删除）
+	OrphanReasonPortMismatch = "port_mismatch"     // 公共端口与服务的期望状态不一致
+	OrphanReasonDuplicate    = "duplicate_replica" // 与其他容器副本编号重复
+	OrphanReasonJobLeftover  = "job_leftover"      // 已结束但未删除的任务容器（进程中断时遗留）
+	OrphanReasonSidecar      = "sidecar_orphan"    // 主容器已不存在的边车容器
+)
+
+// OrphanContainer 孤立容器
+type OrphanContainer struct {
+	ID      string `json:"id" example:"a1b2c3d4e5f6" description:"容器 ID（短）"`
+	Name    string `json:"name" example:"onedock-nginx-web-p9203-c30001-0" description:"容器名称"`
+	State   string `json:"state" example:"exited" description:"容器状态"`
+	Service string `json:"service,omitempty" example:"nginx-web" description:"容器名称中的服务名"`
+	Reason  string `json:"reason" example:"duplicate_replica" description:"原因: unparsable_name / service_not_found / port_mismatch / duplicate_replica"`
+	Detail  string `json:"detail,omitempty" example:"replica 0 also used by onedock-nginx-web-p9203-c30002-0" description:"说明"`
+	Removed bool   `json:"removed" example:"true" description:"是否已删除"`
+	Error   string `json:"error,omitempty" example:"" description:"删除失败的原因"`
+}
+
+// CleanupResult 孤立容器清理结果
+// @Description dry_run 时只列出孤立容器，不做删除
+type CleanupResult struct {
+	DryRun  bool              `json:"dry_run" example:"false" description:"是否为预演"`
+	Orphans []OrphanContainer `json:"orphans" description:"孤立容器"`
+	Removed int               `json:"removed" example:"2" description:"已删除的容器数"`
+	Notes   []string          `json:"notes,omitempty" description:"未执行的检查及原因"`
+}
+
+// 部署预演的操作类型
+const (
+	PlanActionCreate = "create"
+	PlanActionUpdate = "update"
+	PlanActionNone   = "none"
+)
+
+// DeployPlan 部署预演结果
+// @Description dry_run 部署时返回，说明请求是否有效以及实际部署时将执行的操作
+type DeployPlan struct {
+	Service         string         `json:"service" example:"nginx-web" description:"服务名称（含命名空间）"`
+	Action          string         `json:"action" example:"update" description:"将执行的操作: create / update / none"`
+	Valid           bool           `json:"valid" example:"true" description:"请求是否可以部署"`
+	Errors          []string       `json:"errors,omitempty" description:"导致部署失败的问题"`
+	Warnings        []string       `json:"warnings,omitempty" description:"不影响部署但需要注意的问题"`
+	Image           string         `json:"image" example:"nginx:alpine" description:"镜像"`
+	ImageSource     string         `json:"image_source,omitempty" example:"registry" description:"镜像来源: local（本地已有）/ registry（需要拉取）"`
+	PublicPort      int            `json:"public_port" example:"9203" description:"公共端口"`
+	CurrentReplicas int            `json:"current_replicas" example:"2" description:"当前副本数"`
+	Replicas        int            `json:"replicas" example:"2" description:"部署后的副本数"`
+	Changes         []ConfigChange `json:"changes,omitempty" description:"更新时将变化的配置"`
+}
+
+// 配置变化类型
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// ConfigChange 单项配置变化
+type ConfigChange struct {
+	Field string      `json:"field" example:"environment.LOG_LEVEL" description:"配置项，环境变量为 environment.<名称>，卷挂载为 volumes.<容器内路径>"`
+	Type  string      `json:"type" example:"changed" description:"变化类型: added / removed / changed"`
+	Old   interface{} `json:"old,omitempty" swaggertype:"string" example:"info" description:"当前值"`
+	New   interface{} `json:"new,omitempty" swaggertype:"string" example:"debug" description:"新值"`
+}
+
+// ConfigDiff 服务配置差异
+// @Description 请求中的配置与运行中服务的差异，changed 为 false 时更新不会重建容器
+type ConfigDiff struct {
+	Service string         `json:"service" example:"nginx-web" description:"服务名称"`
+	Changed bool           `json:"changed" example:"true" description:"更新是否会触发滚动更新"`
+	Changes []ConfigChange `json:"changes" description:"配置变化"`
+}
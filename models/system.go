@@ -0,0 +1,147 @@
+package models
+
+import "time"
+
+// VersionInfo OneDock 版本与构建信息
+// @Description 版本号、提交和构建时间在构建时通过 ldflags 注入，Docker API 版本为与守护进程协商后使用的版本
+type VersionInfo struct {
+	Version          string `json:"version" example:"v1.2.0" description:"OneDock 版本，未注入时为 dev"`
+	GitCommit        string `json:"git_commit,omitempty" example:"9fceb02d0ae598e95dc970b74767f19372d61af8" description:"构建时的 git 提交"`
+	BuildDate        string `json:"build_date,omitempty" example:"2024-01-15T10:30:00Z" description:"构建时间"`
+	GoVersion        string `json:"go_version" example:"go1.24.4" description:"编译使用的 Go 版本"`
+	Platform         string `json:"platform" example:"linux/amd64" description:"运行平台"`
+	DockerAPIVersion string `json:"docker_api_version,omitempty" example:"1.51" description:"与守护进程协商后使用的 Docker API 版本"`
+	DockerVersion    string `json:"docker_version,omitempty" example:"28.3.3" description:"Docker 守护进程版本"`
+	DockerError      string `json:"docker_error,omitempty" description:"Docker 守护进程不可达的原因"`
+}
+
+// 就绪检查项
+const (
+	ReadinessDocker  = "docker"  // Docker 守护进程可达
+	ReadinessStore   = "store"   // 状态存储可读写
+	ReadinessCache   = "cache"   // 端口映射缓存可用
+	ReadinessProxies = "proxies" // 启动时的端口代理恢复已完成
+)
+
+// ReadinessCheck 单项就绪检查结果
+type ReadinessCheck struct {
+	Name  string `json:"name" example:"docker" description:"检查项：docker / store / cache / proxies"`
+	Ready bool   `json:"ready" example:"true" description:"是否通过"`
+	Error string `json:"error,omitempty" description:"未通过的原因"`
+}
+
+// Readiness 就绪检查结果
+// @Description 全部检查项通过时就绪，供负载均衡和进程管理器判断是否可以转发管理请求
+type Readiness struct {
+	Ready  bool             `json:"ready" example:"true" description:"是否就绪"`
+	Checks []ReadinessCheck `json:"checks" description:"各项检查结果"`
+}
+
+// DockerStatus Docker 守护进程状态
+type DockerStatus struct {
+	Reachable     bool   `json:"reachable" example:"true" description:"守护进程是否可达"`
+	Error         string `json:"error,omitempty" description:"不可达的原因"`
+	Version       string `json:"version,omitempty" example:"28.3.3" description:"Docker 版本"`
+	APIVersion    string `json:"api_version,omitempty" example:"1.51" description:"守护进程支持的 API 版本"`
+	ClientVersion string `json:"client_version,omitempty" example:"1.51" description:"协商后使用的 API 版本"`
+	OS            string `json:"os,omitempty" example:"linux" description:"操作系统"`
+	Arch          string `json:"arch,omitempty" example:"amd64" description:"CPU 架构"`
+	KernelVersion string `json:"kernel_version,omitempty" example:"6.8.0-45-generic" description:"内核版本"`
+	RootDir       string `json:"root_dir,omitempty" example:"/var/lib/docker" description:"Docker 数据目录"`
+}
+
+// DiskStatus 磁盘占用
+type DiskStatus struct {
+	Images          int    `json:"images" example:"24" description:"镜像数量"`
+	ImagesBytes     int64  `json:"images_bytes" example:"4831838208" description:"镜像占用（字节）"`
+	Containers      int    `json:"containers" example:"30" description:"容器数量"`
+	ContainersBytes int64  `json:"containers_bytes" example:"10485760" description:"容器可写层占用（字节）"`
+	Volumes         int    `json:"volumes" example:"6" description:"卷数量"`
+	VolumesBytes    int64  `json:"volumes_bytes" example:"2147483648" description:"卷占用（字节）"`
+	BuildCacheBytes int64  `json:"build_cache_bytes" example:"0" description:"构建缓存占用（字节）"`
+	FilesystemTotal uint64 `json:"filesystem_total,omitempty" example:"107374182400" description:"Docker 数据目录所在文件系统的总容量（字节），无法访问该目录时为空"`
+	FilesystemFree  uint64 `json:"filesystem_free,omitempty" example:"53687091200" description:"Docker 数据目录所在文件系统的可用空间（字节）"`
+	Error           string `json:"error,omitempty" description:"统计失败的原因"`
+}
+
+// PortUsage 受管公共端口使用情况
+type PortUsage struct {
+	Allocated      int    `json:"allocated" example:"12" description:"已分配给服务的公共端口数"`
+	Reserved       int    `json:"reserved" example:"2" description:"预留的公共端口数"`
+	ProxiesRunning int    `json:"proxies_running" example:"12" description:"运行中的端口代理数"`
+	AutoRange      string `json:"auto_range,omitempty" example:"9300-9399" description:"自动分配端口的范围"`
+	AutoRangeSize  int    `json:"auto_range_size,omitempty" example:"100" description:"自动分配范围内的端口数"`
+	AutoRangeUsed  int    `json:"auto_range_used,omitempty" example:"10" description:"自动分配范围内已分配或预留的端口数"`
+}
+
+// SystemStatus OneDock 所在主机的系统状态
+// @Description Docker 守护进程、磁盘占用、受管端口和 OneDock 进程运行时长
+type SystemStatus struct {
+	Docker        DockerStatus `json:"docker" description:"Docker 守护进程状态"`
+	Disk          DiskStatus   `json:"disk" description:"镜像、容器、卷的磁盘占用"`
+	Ports         PortUsage    `json:"ports" description:"受管公共端口使用情况"`
+	StartedAt     time.Time    `json:"started_at" example:"2024-01-15T08:00:00Z" description:"OneDock 启动时间"`
+	UptimeSeconds int64        `json:"uptime_seconds" example:"9000" description:"OneDock 运行时长（秒）"`
+	Uptime        string       `json:"uptime" example:"2h30m0s" description:"OneDock 运行时长"`
+	Persistent    bool         `json:"persistent" example:"true" description:"状态存储是否持久化到磁盘"`
+}
+
+// 磁盘占用的资源类型
+const (
+	DiskUsageImages     = "images"      // 镜像
+	DiskUsageContainers = "containers"  // 容器可写层
+	DiskUsageVolumes    = "volumes"     // 命名卷
+	DiskUsageBuildCache = "build_cache" // 构建缓存
+)
+
+// DiskUsageSummary 一类资源的磁盘占用，对应 docker system df 的一行
+type DiskUsageSummary struct {
+	Type        string `json:"type" example:"images" description:"资源类型：images / containers / volumes / build_cache"`
+	Total       int    `json:"total" example:"24" description:"数量"`
+	Active      int    `json:"active" example:"12" description:"使用中的数量：有容器使用的镜像、运行中的容器、有容器挂载的卷、使用中的构建缓存"`
+	Size        int64  `json:"size" example:"4831838208" description:"占用（字节）"`
+	Reclaimable int64  `json:"reclaimable" example:"1073741824" description:"可回收的占用（字节）：未使用的镜像、已停止容器的可写层、未挂载的卷、未使用的构建缓存"`
+}
+
+// ImageDiskUsage 单个镜像的磁盘占用
+type ImageDiskUsage struct {
+	ID         string    `json:"id" example:"a1b2c3d4e5f6" description:"镜像ID（短格式）"`
+	Tags       []string  `json:"tags" example:"nginx:alpine" description:"镜像标签，悬空镜像为空"`
+	Size       int64     `json:"size" example:"52428800" description:"镜像总大小（字节）"`
+	SharedSize int64     `json:"shared_size" example:"8388608" description:"与其他镜像共享的层大小（字节），无法统计时为 -1"`
+	UniqueSize int64     `json:"unique_size" example:"44040192" description:"只属于该镜像的大小（字节），删除镜像可释放的空间，无法统计时为 -1"`
+	Containers int64     `json:"containers" example:"2" description:"使用该镜像的容器数（包括已停止的容器），无法统计时为 -1"`
+	Services   []string  `json:"services" example:"nginx-web" description:"使用该镜像的受管服务"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-15T10:30:00Z" description:"镜像创建时间"`
+}
+
+// ContainerDiskUsage 单个容器的磁盘占用
+type ContainerDiskUsage struct {
+	ID         string `json:"id" example:"a1b2c3d4e5f6" description:"容器ID（短格式）"`
+	Name       string `json:"name" example:"onedock-nginx-web-9303-30000-0" description:"容器名称"`
+	Service    string `json:"service,omitempty" example:"nginx-web" description:"所属服务，不是受管副本时为空"`
+	Replica    int    `json:"replica" example:"0" description:"副本编号（仅受管副本）"`
+	Image      string `json:"image" example:"nginx:alpine" description:"镜像"`
+	State      string `json:"state" example:"running" description:"容器状态"`
+	SizeRw     int64  `json:"size_rw" example:"1048576" description:"可写层大小（字节）"`
+	SizeRootFs int64  `json:"size_root_fs" example:"53477376" description:"包括镜像在内的总大小（字节）"`
+}
+
+// VolumeDiskUsage 单个命名卷的磁盘占用
+type VolumeDiskUsage struct {
+	Name     string   `json:"name" example:"nginx-data" description:"卷名称"`
+	Size     int64    `json:"size" example:"52428800" description:"占用（字节），无法统计时为 -1"`
+	RefCount int64    `json:"ref_count" example:"1" description:"挂载该卷的容器数，无法统计时为 -1"`
+	Services []string `json:"services" example:"nginx-web" description:"挂载该卷的受管服务"`
+}
+
+// SystemDiskUsage Docker 磁盘占用详情
+// @Description 与 docker system df -v 相当：按资源类型汇总占用和可回收空间，并列出每个镜像、容器和卷的占用（按占用从大到小排序），标出所属的受管服务
+type SystemDiskUsage struct {
+	Summary         []DiskUsageSummary   `json:"summary" description:"按资源类型汇总"`
+	Images          []ImageDiskUsage     `json:"images" description:"每个镜像的占用"`
+	Containers      []ContainerDiskUsage `json:"containers" description:"每个容器的占用"`
+	Volumes         []VolumeDiskUsage    `json:"volumes" description:"每个命名卷的占用"`
+	FilesystemTotal uint64               `json:"filesystem_total,omitempty" example:"107374182400" description:"Docker 数据目录所在文件系统的总容量（字节），无法访问该目录时为空"`
+	FilesystemFree  uint64               `json:"filesystem_free,omitempty" example:"53687091200" description:"Docker 数据目录所在文件系统的可用空间（字节）"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ServiceTemplate 服务模板
+// @Description 带 ${VAR} 占位符的服务配置，部署时传入变量值生成具体服务；${VAR:-默认值} 表示可选变量
+type ServiceTemplate struct {
+	Name        string                 `json:"name" binding:"required" example:"web-app" description:"模板名称"`
+	Description string                 `json:"description,omitempty" example:"标准 Web 服务" description:"模板说明"`
+	Spec        map[string]interface{} `json:"spec" binding:"required" swaggertype:"object" description:"服务配置（与部署接口的请求体相同），字符串值中可使用 ${VAR} 占位符，端口、副本数等数字字段也可写成 \"${PORT}\""`
+	Variables   []string               `json:"variables,omitempty" example:"NAME,PORT" description:"模板中使用的变量（只读）"`
+	CreatedAt   time.Time              `json:"created_at,omitempty" example:"2024-01-15T10:30:00Z" description:"创建时间"`
+	UpdatedAt   time.Time              `json:"updated_at,omitempty" example:"2024-01-15T10:30:00Z" description:"更新时间"`
+}
+
+// TemplateDeployRequest 从模板部署服务的请求
+// @Description 按模板生成服务配置并部署，服务已存在时执行滚动更新
+type TemplateDeployRequest struct {
+	Variables map[string]string `json:"variables" description:"变量值"`
+}
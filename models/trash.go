@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// TrashedService 回收站中的服务
+// @Description 放入回收站的服务保存了删除时的完整配置，公共端口为它保留，保留期内可以恢复
+type TrashedService struct {
+	Name           string    `json:"name" example:"nginx-web" description:"服务名称"`
+	Image          string    `json:"image" example:"nginx:1.27-alpine" description:"镜像"`
+	PublicPort     int       `json:"public_port" example:"9203" description:"公共端口，保留期内为该服务预留"`
+	Replicas       int       `json:"replicas" example:"3" description:"删除时的副本数"`
+	KeptContainers int       `json:"kept_containers" example:"3" description:"保留的已停止容器数，为 0 时恢复会按配置重新部署"`
+	DeletedBy      string    `json:"deleted_by" example:"token:abcd****" description:"删除服务的操作者"`
+	DeletedAt      time.Time `json:"deleted_at" example:"2024-01-15T10:30:00Z" description:"删除时间"`
+	ExpiresAt      time.Time `json:"expires_at" example:"2024-01-22T10:30:00Z" description:"保留截止时间，之后自动清除"`
+}
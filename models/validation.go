@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aichy126/onedock/utils"
+)
+
+// serviceNamePattern 服务短名称格式：字母、数字、下划线和连字符，以字母或数字开头，用作容器名称的一部分
+var serviceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,62}$`)
+
+// ValidServiceName 服务名称是否合法，可写成 命名空间.名称
+func ValidServiceName(name string) bool {
+	if strings.Contains(name, NamespaceSeparator) {
+		namespace, short := SplitServiceName(name)
+		return namespacePattern.MatchString(namespace) && serviceNamePattern.MatchString(short)
+	}
+	return serviceNamePattern.MatchString(name)
+}
+
+// ValidNamespace 命名空间名称是否合法
+func ValidNamespace(namespace string) bool {
+	return namespacePattern.MatchString(namespace)
+}
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field      string      `json:"field" example:"internal_port" description:"字段路径（JSON 名称），嵌套字段如 init_containers[0].image"`
+	Constraint string      `json:"constraint" example:"port" description:"未通过的约束：required / port / min / max / oneof / servicename / namespace 等"`
+	Param      string      `json:"param,omitempty" example:"1" description:"约束参数，如 min=1 中的 1"`
+	Value      interface{} `json:"value,omitempty" swaggertype:"string" example:"70000" description:"提交的值"`
+	Message    string      `json:"message" example:"internal_port must be a port between 1 and 65535" description:"错误说明"`
+}
+
+// ValidationError 请求参数校验失败，列出每个不合法的字段
+// @Description 校验失败时作为响应的 data 返回，msg 为全部字段错误的汇总
+type ValidationError struct {
+	Fields []FieldError `json:"errors" description:"不合法的字段"`
+}
+
+// Add 添加一个字段错误
+func (e *ValidationError) Add(field, constraint string, value interface{}, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Constraint: constraint, Value: value, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err 没有字段错误时返回 nil
+func (e *ValidationError) Err() error {
+	if e == nil || len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ErrorCode 错误码
+func (e *ValidationError) ErrorCode() string { return utils.CodeValidationFailed }
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+// ValidationReport 服务配置校验结果
+// @Description 只做静态检查（字段格式、端口、镜像引用、卷路径、环境变量文件），不访问镜像仓库，不创建或修改任何容器
+type ValidationReport struct {
+	Service  string       `json:"service" example:"nginx-web" description:"规范化后的服务全名"`
+	Valid    bool         `json:"valid" example:"true" description:"没有错误时为 true，有警告仍可部署"`
+	Errors   []FieldError `json:"errors" description:"部署时会被拒绝的问题"`
+	Warnings []FieldError `json:"warnings" description:"不影响部署但可能不符合预期的问题，如卷的主机路径不存在、使用 latest 标签"`
+}
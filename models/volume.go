@@ -0,0 +1,39 @@
+package models
+
+// 卷类型
+const (
+	VolumeTypeVolume = "volume" // Docker 命名卷
+	VolumeTypeBind   = "bind"   // 挂载到容器的主机目录
+)
+
+// VolumeUser 挂载卷的副本
+type VolumeUser struct {
+	Service     string `json:"service" example:"nginx-web" description:"服务名称"`
+	Replica     int    `json:"replica" example:"0" description:"副本编号"`
+	ContainerID string `json:"container_id" example:"a1b2c3d4e5f6" description:"容器ID（短格式）"`
+	State       string `json:"state" example:"running" description:"容器状态"`
+	Destination string `json:"destination" example:"/usr/share/nginx/html" description:"容器内路径"`
+	ReadWrite   bool   `json:"read_write" example:"true" description:"是否可写"`
+}
+
+// Volume 服务使用的卷或主机目录
+// @Description 命名卷按卷名称、主机目录按路径汇总挂载它的副本；size 为 -1 表示无法统计占用
+type Volume struct {
+	Type      string       `json:"type" example:"volume" description:"类型：volume（命名卷）/ bind（主机目录）"`
+	Name      string       `json:"name,omitempty" example:"nginx-data" description:"卷名称，主机目录为空"`
+	Source    string       `json:"source" example:"/var/lib/docker/volumes/nginx-data/_data" description:"数据在主机上的路径：卷的挂载点或主机目录"`
+	Driver    string       `json:"driver,omitempty" example:"local" description:"卷驱动（仅命名卷）"`
+	CreatedAt string       `json:"created_at,omitempty" example:"2024-01-15T10:30:00Z" description:"创建时间（仅命名卷）"`
+	Size      int64        `json:"size" example:"52428800" description:"磁盘占用（字节），无法统计时为 -1"`
+	Services  []string     `json:"services" example:"nginx-web" description:"使用该卷的服务，为空表示没有受管服务使用"`
+	Users     []VolumeUser `json:"users" description:"挂载该卷的副本"`
+	Error     string       `json:"error,omitempty" description:"统计占用失败的原因"`
+}
+
+// VolumeQuery 卷列表的过滤条件
+type VolumeQuery struct {
+	Type    string // volume / bind，为空时两类都列出
+	Service string // 只列出该服务使用的卷
+	Path    string // 只列出该主机路径
+	All     bool   // 同时列出没有受管服务使用的命名卷
+}
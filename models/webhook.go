@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Webhook 请求头
+const (
+	WebhookEventHeader     = "X-OneDock-Event"     // 事件类型
+	WebhookNameHeader      = "X-OneDock-Webhook"   // 订阅名称
+	WebhookSignatureHeader = "X-OneDock-Signature" // 请求体的 HMAC-SHA256 签名：sha256=<十六进制>，订阅未设置密钥时不发送
+)
+
+// WebhookRequest 创建或更新事件订阅的请求
+// @Description 服务生命周期事件发生时以 JSON POST 到 url，同名订阅已存在时覆盖配置（包括签名密钥）
+type WebhookRequest struct {
+	Name     string   `json:"name" binding:"required" example:"deploy-tracker" description:"订阅名称"`
+	URL      string   `json:"url" binding:"required" example:"https://ci.example.com/hooks/onedock" description:"接收事件的地址，http 或 https"`
+	Secret   string   `json:"secret,omitempty" example:"whsec_3f2a9c1b" description:"签名密钥，设置后请求带 X-OneDock-Signature 签名头；加密保存，任何接口都不会返回"`
+	Events   []string `json:"events,omitempty" example:"deployed,update_failed" description:"订阅的事件类型，为空表示全部"`
+	Services []string `json:"services,omitempty" example:"nginx-web" description:"订阅的服务，为空表示全部"`
+	Disabled bool     `json:"disabled,omitempty" example:"false" description:"暂停投递"`
+}
+
+// Webhook 事件订阅（不含签名密钥）
+// @Description 订阅配置及最近一次投递结果
+type Webhook struct {
+	Name           string     `json:"name" example:"deploy-tracker" description:"订阅名称"`
+	URL            string     `json:"url" example:"https://ci.example.com/hooks/onedock" description:"接收事件的地址"`
+	HasSecret      bool       `json:"has_secret" example:"true" description:"是否设置了签名密钥"`
+	Events         []string   `json:"events" example:"deployed,update_failed" description:"订阅的事件类型，为空表示全部"`
+	Services       []string   `json:"services" example:"nginx-web" description:"订阅的服务，为空表示全部"`
+	Disabled       bool       `json:"disabled" example:"false" description:"是否暂停投递"`
+	LastStatus     int        `json:"last_status,omitempty" example:"200" description:"最近一次投递的 HTTP 状态码"`
+	LastError      string     `json:"last_error,omitempty" example:"https://ci.example.com/hooks/onedock responded with status 502" description:"最近一次投递的错误"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty" example:"2024-01-15T10:30:00Z" description:"最近一次投递时间"`
+	CreatedAt      time.Time  `json:"created_at" example:"2024-01-15T10:30:00Z" description:"创建时间"`
+	UpdatedAt      time.Time  `json:"updated_at" example:"2024-01-15T10:30:00Z" description:"更新时间"`
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+const (
+	apiTokenPrefix      = "odk_"      // 接口创建的令牌前缀，其他令牌不查询存储
+	apiTokenPrefixLen   = 8           // 列表中展示的明文长度
+	apiTokenUsageWindow = time.Minute // 最近使用时间的记录粒度，避免每个请求都写存储
+)
+
+// hashAPIToken 令牌摘要，令牌为 32 字节随机数，SHA-256 足以防止从摘要还原
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newAPIToken 生成随机令牌
+func newAPIToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiTokenPrefix + hex.EncodeToString(buf), nil
+}
+
+// validRole 是否为有效的令牌角色
+func validRole(role string) bool {
+	return role == models.RoleReadOnly || role == models.RoleDeployer || role == models.RoleAdmin
+}
+
+// apiTokenStatus 令牌在 now 时的状态
+func apiTokenStatus(token *store.APIToken, now time.Time) string {
+	switch {
+	case token.RevokedAt != nil:
+		return models.APITokenRevoked
+	case token.ExpiresAt != nil && !now.Before(*token.ExpiresAt):
+		return models.APITokenExpired
+	}
+	return models.APITokenActive
+}
+
+// toAPIToken 将存储中的令牌转换为 API 模型（不含明文）
+func toAPIToken(token *store.APIToken, now time.Time) *models.APIToken {
+	return &models.APIToken{
+		ID:         token.ID,
+		Name:       token.Name,
+		Role:       token.Role,
+		Prefix:     token.Prefix,
+		Status:     apiTokenStatus(token, now),
+		Scope:      apiTokenScope(token),
+		Actor:      token.Actor,
+		ExpiresAt:  token.ExpiresAt,
+		RevokedAt:  token.RevokedAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+// apiTokenScope 令牌的服务范围，未限制时返回 nil
+func apiTokenScope(token *store.APIToken) *models.TokenScope {
+	scope := &models.TokenScope{Services: token.Services, Namespaces: token.Namespaces}
+	if !scope.Restricted() {
+		return nil
+	}
+	return scope
+}
+
+// CreateAPIToken 创建访问令牌，明文只在返回值中出现一次
+func (s *Service) CreateAPIToken(ctx context.IContext, req *models.APITokenRequest) (*models.APIToken, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("token name is required")
+	}
+	role := req.Role
+	if role == "" {
+		role = models.RoleDeployer
+	}
+	if !validRole(role) {
+		return nil, fmt.Errorf("invalid role %q: must be %s, %s or %s", role, models.RoleReadOnly, models.RoleDeployer, models.RoleAdmin)
+	}
+	if req.ExpiresIn < 0 {
+		return nil, fmt.Errorf("expires_in must not be negative")
+	}
+	for _, namespace := range req.Namespaces {
+		if err := models.ValidateNamespace(namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	plaintext, err := newAPIToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	record := &store.APIToken{
+		Name:       req.Name,
+		Role:       role,
+		Hash:       hashAPIToken(plaintext),
+		Prefix:     plaintext[:apiTokenPrefixLen],
+		Services:   req.Services,
+		Namespaces: req.Namespaces,
+		Actor:      actorFromContext(ctx),
+	}
+	if req.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresIn) * time.Second)
+		record.ExpiresAt = &expiresAt
+	}
+	if err := s.store.AddAPIToken(record); err != nil {
+		return nil, err
+	}
+
+	log.Info("APIToken", log.Any("ID", record.ID), log.Any("Name", record.Name), log.Any("Role", role), log.Any("Actor", record.Actor),
+		log.Any("Message", "创建访问令牌"))
+	token := toAPIToken(record, time.Now())
+	token.Token = plaintext
+	return token, nil
+}
+
+// ListAPITokens 列出通过接口创建的令牌（不含明文）
+func (s *Service) ListAPITokens(ctx context.IContext) ([]*models.APIToken, error) {
+	records, err := s.store.ListAPITokens()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tokens := make([]*models.APIToken, 0, len(records))
+	for _, record := range records {
+		tokens = append(tokens, toAPIToken(record, now))
+	}
+	return tokens, nil
+}
+
+// getAPIToken 获取令牌，不存在时返回错误
+func (s *Service) getAPIToken(id int64) (*store.APIToken, error) {
+	record, err := s.store.GetAPIToken(id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, utils.NewError(utils.CodeNotFound, "token %d not found", id)
+	}
+	return record, nil
+}
+
+// RevokeAPIToken 吊销令牌，立即失效且不可恢复
+func (s *Service) RevokeAPIToken(ctx context.IContext, id int64) (*models.APIToken, error) {
+	record, err := s.getAPIToken(id)
+	if err != nil {
+		return nil, err
+	}
+	if record.RevokedAt == nil {
+		now := time.Now()
+		record.RevokedAt = &now
+		if err := s.store.UpdateAPIToken(record); err != nil {
+			return nil, err
+		}
+		log.Info("APIToken", log.Any("ID", id), log.Any("Name", record.Name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "吊销访问令牌"))
+	}
+	return toAPIToken(record, time.Now()), nil
+}
+
+// ExpireAPIToken 设置令牌的过期时间，expiresAt 为空表示立即过期
+// 可用于轮换：创建新令牌后给旧令牌留出切换时间
+func (s *Service) ExpireAPIToken(ctx context.IContext, id int64, expiresAt *time.Time) (*models.APIToken, error) {
+	record, err := s.getAPIToken(id)
+	if err != nil {
+		return nil, err
+	}
+	if record.RevokedAt != nil {
+		return nil, fmt.Errorf("token %d is already revoked", id)
+	}
+	at := time.Now()
+	if expiresAt != nil {
+		at = *expiresAt
+	}
+	record.ExpiresAt = &at
+	if err := s.store.UpdateAPIToken(record); err != nil {
+		return nil, err
+	}
+	log.Info("APIToken", log.Any("ID", id), log.Any("Name", record.Name), log.Any("ExpiresAt", at), log.Any("Actor", actorFromContext(ctx)),
+		log.Any("Message", "设置访问令牌过期时间"))
+	return toAPIToken(record, time.Now()), nil
+}
+
+// LookupToken 查找通过接口创建的令牌，返回其角色和服务范围；不存在、已过期或已吊销时角色为空字符串
+// 供权限验证中间件使用
+func (s *Service) LookupToken(token string) (string, *models.TokenScope) {
+	if !strings.HasPrefix(token, apiTokenPrefix) || s.store == nil {
+		return "", nil
+	}
+	record, err := s.store.GetAPITokenByHash(hashAPIToken(token))
+	if err != nil {
+		log.Error("APIToken", log.Any("Error", err), log.Any("Message", "查询访问令牌失败"))
+		return "", nil
+	}
+	now := time.Now()
+	if record == nil || apiTokenStatus(record, now) != models.APITokenActive {
+		return "", nil
+	}
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) >= apiTokenUsageWindow {
+		record.LastUsedAt = &now
+		if err := s.store.UpdateAPIToken(record); err != nil {
+			log.Warn("APIToken", log.Any("Error", err), log.Any("ID", record.ID), log.Any("Message", "记录令牌使用时间失败"))
+		}
+	}
+	return record.Role, apiTokenScope(record)
+}
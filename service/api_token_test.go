@@ -0,0 +1,43 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+)
+
+// TestAPITokenStatus 测试令牌的生成与状态判断
+func TestAPITokenStatus(t *testing.T) {
+	a, err := newAPIToken()
+	if err != nil {
+		t.Fatalf("newAPIToken() = %v", err)
+	}
+	b, _ := newAPIToken()
+	if !strings.HasPrefix(a, apiTokenPrefix) || a == b {
+		t.Errorf("newAPIToken() = %s, %s, 期望带 %s 前缀且不重复", a, b, apiTokenPrefix)
+	}
+	if hashAPIToken(a) == hashAPIToken(b) || len(hashAPIToken(a)) != 64 {
+		t.Errorf("hashAPIToken(%s) = %s", a, hashAPIToken(a))
+	}
+
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+	tests := []struct {
+		token *store.APIToken
+		want  string
+	}{
+		{&store.APIToken{}, models.APITokenActive},
+		{&store.APIToken{ExpiresAt: &future}, models.APITokenActive},
+		{&store.APIToken{ExpiresAt: &past}, models.APITokenExpired},
+		{&store.APIToken{ExpiresAt: &now}, models.APITokenExpired},
+		{&store.APIToken{ExpiresAt: &future, RevokedAt: &past}, models.APITokenRevoked},
+	}
+	for i, tt := range tests {
+		if got := apiTokenStatus(tt.token, now); got != tt.want {
+			t.Errorf("用例 %d: apiTokenStatus() = %s, 期望 %s", i, got, tt.want)
+		}
+	}
+}
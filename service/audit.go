@@ -0,0 +1,87 @@
+package service
+
+import (
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+const (
+	defaultAuditRetentionDays = 90  // 审计日志默认保留天数
+	defaultAuditLimit         = 50  // 默认返回条数
+	maxAuditLimit             = 500 // 单次查询最多返回条数
+)
+
+// RecordAudit 保存一条审计记录，并清理超过 audit.retention_days 的记录；写入失败只记录日志，不影响请求
+func (s *Service) RecordAudit(record *models.AuditRecord) {
+	retention := time.Duration(utils.ConfGetIntDefault("audit.retention_days", defaultAuditRetentionDays)) * 24 * time.Hour
+	err := s.store.AddAuditLog(&store.AuditLog{
+		Actor:      record.Actor,
+		TokenID:    record.TokenID,
+		RequestID:  record.RequestID,
+		ClientIP:   record.ClientIP,
+		Method:     record.Method,
+		Path:       record.Path,
+		Service:    record.Service,
+		Body:       record.Body,
+		Status:     record.Status,
+		Code:       record.Code,
+		Error:      record.Error,
+		DurationMs: record.DurationMs,
+		CreatedAt:  record.CreatedAt,
+	}, retention)
+	if err != nil {
+		log.Error("Audit", log.Any("Error", err), log.Any("Method", record.Method), log.Any("Path", record.Path), log.Any("Message", "保存审计日志失败"))
+	}
+}
+
+// ListAuditLogs 按条件查询审计日志
+func (s *Service) ListAuditLogs(ctx context.IContext, query *models.AuditQuery) (*models.AuditList, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+
+	logs, total, err := s.store.ListAuditLogs(store.AuditQuery{
+		Service:   query.Service,
+		Actor:     query.Actor,
+		Method:    query.Method,
+		RequestID: query.RequestID,
+		Failed:    query.Failed,
+		Since:     query.Since,
+		Until:     query.Until,
+		Limit:     limit,
+		Offset:    query.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.AuditList{Total: total, Records: make([]*models.AuditRecord, 0, len(logs))}
+	for _, entry := range logs {
+		result.Records = append(result.Records, &models.AuditRecord{
+			ID:         entry.ID,
+			Actor:      entry.Actor,
+			TokenID:    entry.TokenID,
+			RequestID:  entry.RequestID,
+			ClientIP:   entry.ClientIP,
+			Method:     entry.Method,
+			Path:       entry.Path,
+			Service:    entry.Service,
+			Body:       entry.Body,
+			Status:     entry.Status,
+			Code:       entry.Code,
+			Error:      entry.Error,
+			DurationMs: entry.DurationMs,
+			CreatedAt:  entry.CreatedAt,
+		})
+	}
+	return result, nil
+}
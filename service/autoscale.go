@@ -0,0 +1,371 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+const (
+	defaultAutoscaleInterval = 30  // 默认评估间隔（秒）
+	defaultScaleUpCooldown   = 60  // 默认扩容冷却时间（秒）
+	defaultScaleDownCooldown = 300 // 默认缩容冷却时间（秒）
+	autoscaleTolerance       = 0.1 // 使用率与目标偏差在 10% 以内时不调整，避免抖动
+	autoscaleStatsTimeout    = 10 * time.Second
+)
+
+// autoscaler 自动扩缩容，保存每个服务最近一次的评估结果
+type autoscaler struct {
+	mutex   sync.Mutex
+	status  map[string]*models.AutoscaleStatus
+	samples map[string]trafficSample // 上一次评估时的请求计数，用于计算请求速率
+}
+
+// trafficSample 请求计数采样
+type trafficSample struct {
+	requests int64
+	at       time.Time
+}
+
+// startAutoscaler 启动自动扩缩容循环，container.autoscale_interval 为 0 时禁用
+func (s *Service) startAutoscaler() {
+	s.autoscaler.status = make(map[string]*models.AutoscaleStatus)
+	s.autoscaler.samples = make(map[string]trafficSample)
+
+	interval := utils.ConfGetIntDefault("container.autoscale_interval", defaultAutoscaleInterval)
+	if interval <= 0 {
+		log.Info("Autoscale", log.Any("Message", "自动扩缩容已禁用"))
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(interval) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopping:
+				return
+			case <-ticker.C:
+				s.runAutoscale(context.Background())
+			}
+		}
+	}()
+	log.Info("Autoscale", log.Any("Interval", interval), log.Any("Message", "自动扩缩容已启动"))
+}
+
+// runAutoscale 对所有配置了策略的服务执行一轮评估
+func (s *Service) runAutoscale(ctx context.IContext) {
+	policies, err := s.store.ListAutoscalePolicies()
+	if err != nil {
+		log.Error("Autoscale", log.Any("Error", err), log.Any("Message", "读取自动扩缩容策略失败"))
+		return
+	}
+	for _, policy := range policies {
+		status := s.evaluateAutoscale(ctx, policy)
+		s.autoscaler.mutex.Lock()
+		s.autoscaler.status[policy.Service] = status
+		s.autoscaler.mutex.Unlock()
+	}
+}
+
+// autoscaleReplicas 按 ceil(当前副本数 × 当前使用率 / 目标使用率) 计算期望副本数，偏差在容忍度内时保持不变
+func autoscaleReplicas(current int, usage, target float64) int {
+	if current <= 0 || target <= 0 {
+		return current
+	}
+	ratio := usage / target
+	if math.Abs(ratio-1) <= autoscaleTolerance {
+		return current
+	}
+	return int(math.Ceil(float64(current) * ratio))
+}
+
+// clampReplicas 将副本数限制在策略的最小、最大副本数之间
+func clampReplicas(replicas, min, max int) int {
+	if replicas < min {
+		return min
+	}
+	if replicas > max {
+		return max
+	}
+	return replicas
+}
+
+// collectStats 并发采集运行中副本的资源使用情况，返回平均 CPU、内存使用率和成功采集的副本数
+func (s *Service) collectStats(ctx context.IContext, containers []dockerclient.ContainerInfo) (cpu, memory float64, sampled int) {
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+	)
+	statsCtx, cancel := ctx.WithTimeout(autoscaleStatsTimeout)
+	defer cancel()
+
+	for _, container := range containers {
+		if container.State != "running" {
+			continue
+		}
+		wg.Add(1)
+		go func(id, name string) {
+			defer wg.Done()
+			stats, err := s.dockerClient.ContainerStats(statsCtx, id)
+			if err != nil {
+				log.Error("Autoscale", log.Any("Error", err), log.Any("ContainerName", name), log.Any("Message", "采集容器资源使用情况失败"))
+				return
+			}
+			mutex.Lock()
+			cpu += stats.CPUPercent
+			memory += stats.MemoryPercent
+			sampled++
+			mutex.Unlock()
+		}(container.ID, container.Name)
+	}
+	wg.Wait()
+
+	if sampled > 0 {
+		cpu /= float64(sampled)
+		memory /= float64(sampled)
+	}
+	return cpu, memory, sampled
+}
+
+// trafficRate 根据端口代理的请求计数计算服务的每秒请求数，首次采样或计数重置时 ok 为 false
+func (s *Service) trafficRate(name string, publicPort int, now time.Time) (rps float64, active int64, ok bool) {
+	requests, active, exists := s.PortManager.PortTraffic(publicPort)
+	if !exists {
+		return 0, 0, false
+	}
+
+	s.autoscaler.mutex.Lock()
+	last, hasLast := s.autoscaler.samples[name]
+	s.autoscaler.samples[name] = trafficSample{requests: requests, at: now}
+	s.autoscaler.mutex.Unlock()
+
+	elapsed := now.Sub(last.at).Seconds()
+	if !hasLast || elapsed <= 0 || requests < last.requests {
+		return 0, active, false
+	}
+	return float64(requests-last.requests) / elapsed, active, true
+}
+
+// evaluateAutoscale 评估单个服务，需要时调用 ScaleService 调整副本数
+// 已停止的服务和正在滚动更新的服务不处理
+func (s *Service) evaluateAutoscale(ctx context.IContext, policy *store.AutoscalePolicy) *models.AutoscaleStatus {
+	now := time.Now()
+	status := toAutoscaleStatus(policy)
+	status.LastCheckAt = &now
+
+	if spec, err := s.store.GetServiceSpec(policy.Service); err == nil && spec != nil && spec.Stopped {
+		status.Message = "service stopped"
+		return status
+	}
+	if r := s.rollouts.get(policy.Service); r != nil && r.active() {
+		status.Message = "rollout in progress"
+		return status
+	}
+	if release := s.pendingRelease(policy.Service); release != nil {
+		status.Message = fmt.Sprintf("%s release in progress", release.Mode)
+		return status
+	}
+	if s.serviceLock(policy.Service) != nil {
+		status.Message = "service locked"
+		return status
+	}
+	if s.operations.busy(policy.Service) {
+		status.Message = "operation in progress"
+		return status
+	}
+
+	containers, err := s.serviceContainers(ctx, policy.Service)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+	if len(containers) == 0 {
+		status.Message = "service has no replicas"
+		return status
+	}
+	current := len(containers)
+	status.CurrentReplicas = current
+
+	// 多个指标取最大的期望副本数，-1 表示没有可用指标
+	desired := -1
+	if policy.TargetCPU > 0 || policy.TargetMemory > 0 {
+		if cpu, memory, sampled := s.collectStats(ctx, containers); sampled > 0 {
+			status.CPUPercent = math.Round(cpu*10) / 10
+			status.MemoryPercent = math.Round(memory*10) / 10
+			if policy.TargetCPU > 0 {
+				desired = max(desired, autoscaleReplicas(current, cpu, float64(policy.TargetCPU)))
+			}
+			if policy.TargetMemory > 0 {
+				desired = max(desired, autoscaleReplicas(current, memory, float64(policy.TargetMemory)))
+			}
+		}
+	}
+	// 请求速率、并发数按每副本平均值与目标比较
+	if policy.TargetRPS > 0 || policy.TargetConnections > 0 {
+		if nameInfo, err := s.dockerClient.ParseContainerName(containers[0].Name); err == nil {
+			rps, active, ok := s.trafficRate(policy.Service, nameInfo.PublicPort, now)
+			status.RequestsPerSec = math.Round(rps*10) / 10
+			status.ActiveRequests = active
+			if ok && policy.TargetRPS > 0 {
+				desired = max(desired, autoscaleReplicas(current, rps/float64(current), float64(policy.TargetRPS)))
+			}
+			if policy.TargetConnections > 0 {
+				desired = max(desired, autoscaleReplicas(current, float64(active)/float64(current), float64(policy.TargetConnections)))
+			}
+		}
+	}
+	if desired < 0 {
+		status.DesiredReplicas = current
+		status.Message = "no metrics available"
+		return status
+	}
+	desired = clampReplicas(desired, policy.MinReplicas, policy.MaxReplicas)
+	status.DesiredReplicas = desired
+
+	if desired == current {
+		status.Message = "within target"
+		return status
+	}
+
+	cooldown := policy.ScaleDownCooldown
+	if desired > current {
+		cooldown = policy.ScaleUpCooldown
+	}
+	if !policy.LastScaleAt.IsZero() && now.Sub(policy.LastScaleAt) < time.Duration(cooldown)*time.Second {
+		status.Message = fmt.Sprintf("cooling down, want %d replicas", desired)
+		return status
+	}
+
+	if err := s.ScaleService(ctx, policy.Service, desired); err != nil {
+		status.Message = fmt.Sprintf("failed to scale to %d replicas: %v", desired, err)
+		log.Error("Autoscale", log.Any("Error", err), log.Any("ServiceName", policy.Service), log.Any("Replicas", desired), log.Any("Message", "自动扩缩容失败"))
+		return status
+	}
+	if err := s.store.UpdateAutoscaleLastScale(policy.Service, now); err != nil {
+		log.Error("Autoscale", log.Any("Error", err), log.Any("ServiceName", policy.Service), log.Any("Message", "记录自动扩缩容时间失败"))
+	}
+	status.LastScaleAt = &now
+	status.Message = fmt.Sprintf("scaled from %d to %d replicas", current, desired)
+	s.recordEvent(ctx, policy.Service, models.EventAutoscaled, fmt.Sprintf("%s (cpu %.1f%%, memory %.1f%%, %.1f req/s, %d active requests)",
+		status.Message, status.CPUPercent, status.MemoryPercent, status.RequestsPerSec, status.ActiveRequests))
+	log.Info("Autoscale", log.Any("ServiceName", policy.Service), log.Any("From", current), log.Any("To", desired),
+		log.Any("CPU", status.CPUPercent), log.Any("Memory", status.MemoryPercent), log.Any("RPS", status.RequestsPerSec),
+		log.Any("ActiveRequests", status.ActiveRequests), log.Any("Message", "自动扩缩容"))
+	return status
+}
+
+// toAutoscaleStatus 将存储中的策略转换为 API 模型
+func toAutoscaleStatus(policy *store.AutoscalePolicy) *models.AutoscaleStatus {
+	status := &models.AutoscaleStatus{
+		Service: policy.Service,
+		Policy: models.AutoscalePolicy{
+			MinReplicas:       policy.MinReplicas,
+			MaxReplicas:       policy.MaxReplicas,
+			TargetCPU:         policy.TargetCPU,
+			TargetMemory:      policy.TargetMemory,
+			TargetRPS:         policy.TargetRPS,
+			TargetConnections: policy.TargetConnections,
+			ScaleUpCooldown:   policy.ScaleUpCooldown,
+			ScaleDownCooldown: policy.ScaleDownCooldown,
+		},
+	}
+	if !policy.LastScaleAt.IsZero() {
+		lastScaleAt := policy.LastScaleAt
+		status.LastScaleAt = &lastScaleAt
+	}
+	return status
+}
+
+// SetAutoscalePolicy 设置服务的自动扩缩容策略
+func (s *Service) SetAutoscalePolicy(ctx context.IContext, name string, req *models.AutoscalePolicy) (*models.AutoscaleStatus, error) {
+	if req.MinReplicas < 1 || req.MaxReplicas < req.MinReplicas {
+		return nil, fmt.Errorf("min_replicas must be at least 1 and not greater than max_replicas")
+	}
+	if req.TargetCPU < 0 || req.TargetMemory < 0 || req.TargetMemory > 100 || req.TargetRPS < 0 || req.TargetConnections < 0 {
+		return nil, fmt.Errorf("targets must be positive and target_memory must be between 1 and 100")
+	}
+	if req.TargetCPU == 0 && req.TargetMemory == 0 && req.TargetRPS == 0 && req.TargetConnections == 0 {
+		return nil, fmt.Errorf("at least one of target_cpu, target_memory, target_rps and target_connections is required")
+	}
+	if req.ScaleUpCooldown <= 0 {
+		req.ScaleUpCooldown = defaultScaleUpCooldown
+	}
+	if req.ScaleDownCooldown <= 0 {
+		req.ScaleDownCooldown = defaultScaleDownCooldown
+	}
+	if s.GetService(ctx, name) == nil {
+		return nil, errServiceNotFound(name)
+	}
+
+	policy := &store.AutoscalePolicy{
+		Service:           name,
+		MinReplicas:       req.MinReplicas,
+		MaxReplicas:       req.MaxReplicas,
+		TargetCPU:         req.TargetCPU,
+		TargetMemory:      req.TargetMemory,
+		TargetRPS:         req.TargetRPS,
+		TargetConnections: req.TargetConnections,
+		ScaleUpCooldown:   req.ScaleUpCooldown,
+		ScaleDownCooldown: req.ScaleDownCooldown,
+	}
+	if err := s.store.SaveAutoscalePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	log.Info("Autoscale", log.Any("ServiceName", name), log.Any("Policy", req), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "设置自动扩缩容策略"))
+	return toAutoscaleStatus(policy), nil
+}
+
+// GetAutoscaleStatus 获取服务的自动扩缩容策略及最近一次评估结果
+func (s *Service) GetAutoscaleStatus(ctx context.IContext, name string) (*models.AutoscaleStatus, error) {
+	policy, err := s.store.GetAutoscalePolicy(name)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("service %s has no autoscale policy", name)
+	}
+
+	status := toAutoscaleStatus(policy)
+	s.autoscaler.mutex.Lock()
+	last := s.autoscaler.status[name]
+	s.autoscaler.mutex.Unlock()
+	if last != nil {
+		status.CurrentReplicas = last.CurrentReplicas
+		status.DesiredReplicas = last.DesiredReplicas
+		status.CPUPercent = last.CPUPercent
+		status.MemoryPercent = last.MemoryPercent
+		status.RequestsPerSec = last.RequestsPerSec
+		status.ActiveRequests = last.ActiveRequests
+		status.Message = last.Message
+		status.LastCheckAt = last.LastCheckAt
+	}
+	return status, nil
+}
+
+// DeleteAutoscalePolicy 删除服务的自动扩缩容策略，不影响当前副本数
+func (s *Service) DeleteAutoscalePolicy(ctx context.IContext, name string) error {
+	exists, err := s.store.DeleteAutoscalePolicy(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("service %s has no autoscale policy", name)
+	}
+
+	s.autoscaler.mutex.Lock()
+	delete(s.autoscaler.status, name)
+	delete(s.autoscaler.samples, name)
+	s.autoscaler.mutex.Unlock()
+	log.Info("Autoscale", log.Any("ServiceName", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "删除自动扩缩容策略"))
+	return nil
+}
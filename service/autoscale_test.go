@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAutoscaleReplicas 测试期望副本数计算
+func TestAutoscaleReplicas(t *testing.T) {
+	cases := []struct {
+		current       int
+		usage, target float64
+		want          int
+	}{
+		{2, 140, 70, 4},
+		{3, 75, 70, 3}, // 偏差在容忍度内
+		{4, 20, 70, 2},
+		{4, 0, 70, 0},
+		{3, 100, 0, 3},
+	}
+	for _, tc := range cases {
+		if got := autoscaleReplicas(tc.current, tc.usage, tc.target); got != tc.want {
+			t.Errorf("autoscaleReplicas(%d, %v, %v) = %d, want %d", tc.current, tc.usage, tc.target, got, tc.want)
+		}
+	}
+
+	if got := clampReplicas(0, 2, 10); got != 2 {
+		t.Errorf("clampReplicas(0, 2, 10) = %d, want 2", got)
+	}
+	if got := clampReplicas(12, 2, 10); got != 10 {
+		t.Errorf("clampReplicas(12, 2, 10) = %d, want 10", got)
+	}
+}
+
+// TestTrafficRate 测试请求速率计算
+func TestTrafficRate(t *testing.T) {
+	s := &Service{PortManager: &PortProxyManager{proxies: map[int]*PortProxy{}, traffic: map[int]*portTraffic{}}}
+	s.autoscaler.samples = make(map[string]trafficSample)
+
+	if _, _, ok := s.trafficRate("web", 9200, time.Now()); ok {
+		t.Error("端口没有请求计数时不应返回速率")
+	}
+
+	traffic := s.PortManager.trafficFor(9200)
+	start := time.Now()
+	traffic.requests = 1000
+	if _, _, ok := s.trafficRate("web", 9200, start); ok {
+		t.Error("首次采样不应返回速率")
+	}
+
+	traffic.requests = 4000
+	traffic.active = 7
+	rps, active, ok := s.trafficRate("web", 9200, start.Add(30*time.Second))
+	if !ok || rps != 100 || active != 7 {
+		t.Errorf("trafficRate = %v, %d, %v, want 100, 7, true", rps, active, ok)
+	}
+}
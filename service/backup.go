@@ -0,0 +1,271 @@
+package service
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+const (
+	backupFormat       = 1
+	backupManifestFile = "manifest.json"
+	backupStateFile    = "state.json"
+	backupProxyFile    = "proxy.json"
+	maxBackupFileSize  = 256 << 20 // 归档中单个文件的上限
+)
+
+// secretKeyFingerprint secret.key 的指纹，用于确认恢复主机能解密备份中的密钥；未配置时返回空字符串
+func secretKeyFingerprint() string {
+	key, err := secretKey()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// encryptedValues 快照中以 secret.key 加密的值的个数：密钥和 webhook 签名密钥
+func encryptedValues(snapshot *store.Snapshot) int {
+	count := len(snapshot.Secrets)
+	for _, webhook := range snapshot.Webhooks {
+		if webhook.Secret != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// proxyConfig 当前生效的全局代理配置（[proxy] 及负载均衡策略），随备份保存，恢复时与本机配置比较
+func proxyConfig() map[string]string {
+	config := make(map[string]string)
+	for key, value := range igo.App.Conf.GetStringMap("proxy") {
+		config["proxy."+key] = fmt.Sprint(value)
+	}
+	config["container.load_balance_strategy"] = utils.ConfGetString("container.load_balance_strategy")
+	return config
+}
+
+// proxyConfigWarnings 列出备份中与本机不同的代理配置，配置文件需要人工同步
+func proxyConfigWarnings(backup, current map[string]string) []string {
+	keys := make([]string, 0, len(backup))
+	for key := range backup {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	warnings := make([]string, 0)
+	for _, key := range keys {
+		if backup[key] != current[key] {
+			warnings = append(warnings, fmt.Sprintf("%s is %q in the backup but %q on this host, update config.toml if needed", key, backup[key], current[key]))
+		}
+	}
+	return warnings
+}
+
+// writeBackup 将文件按顺序写入 tar.gz 归档
+func writeBackup(w io.Writer, names []string, files map[string][]byte) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		data := files[name]
+		header := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readBackup 读取 tar.gz 归档中的全部文件
+func readBackup(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > maxBackupFileSize {
+			return nil, fmt.Errorf("file %s in backup archive is too large", header.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+		files[header.Name] = data
+	}
+	return files, nil
+}
+
+// backupContents 归档中解析出的内容
+type backupContents struct {
+	manifest models.BackupManifest
+	state    store.Snapshot
+	proxy    map[string]string
+}
+
+// parseBackup 解析并校验归档
+func parseBackup(r io.Reader) (*backupContents, error) {
+	files, err := readBackup(r)
+	if err != nil {
+		return nil, err
+	}
+	contents := &backupContents{}
+	for _, file := range []struct {
+		name string
+		dest interface{}
+	}{
+		{backupManifestFile, &contents.manifest},
+		{backupStateFile, &contents.state},
+		{backupProxyFile, &contents.proxy},
+	} {
+		data, ok := files[file.name]
+		if !ok {
+			return nil, fmt.Errorf("backup archive is missing %s", file.name)
+		}
+		if err := json.Unmarshal(data, file.dest); err != nil {
+			return nil, fmt.Errorf("invalid %s in backup archive: %w", file.name, err)
+		}
+	}
+	if contents.manifest.Format != backupFormat {
+		return nil, fmt.Errorf("unsupported backup format %d, expected %d", contents.manifest.Format, backupFormat)
+	}
+	return contents, nil
+}
+
+// CreateBackup 将期望状态、部署历史、端口登记与预留、密钥（密文）、模板、定时与自动扩缩容、定时任务、锁、镜像监视和代理配置打包为 tar.gz 归档
+func (s *Service) CreateBackup(ctx context.IContext, w io.Writer) (*models.BackupManifest, error) {
+	snapshot, err := s.store.ExportSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	manifest := &models.BackupManifest{
+		Format:    backupFormat,
+		CreatedAt: time.Now(),
+		Hostname:  hostname,
+		Services:  make([]string, 0, len(snapshot.ServiceSpecs)),
+	}
+	for _, spec := range snapshot.ServiceSpecs {
+		manifest.Services = append(manifest.Services, spec.Name)
+	}
+	if encryptedValues(snapshot) > 0 {
+		manifest.SecretKeyFingerprint = secretKeyFingerprint()
+	}
+
+	files := make(map[string][]byte)
+	for name, value := range map[string]interface{}{
+		backupManifestFile: manifest,
+		backupStateFile:    snapshot,
+		backupProxyFile:    proxyConfig(),
+	} {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		files[name] = data
+	}
+	if err := writeBackup(w, []string{backupManifestFile, backupStateFile, backupProxyFile}, files); err != nil {
+		return nil, fmt.Errorf("failed to write backup archive: %w", err)
+	}
+
+	log.Info("Backup", log.Any("Services", len(manifest.Services)), log.Any("Secrets", len(snapshot.Secrets)),
+		log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "创建备份"))
+	return manifest, nil
+}
+
+// RestoreBackup 在没有受管服务的主机上恢复备份：写入全部状态后执行一轮调和，按期望状态创建副本并启动端口代理
+// dryRun 为 true 时只校验归档并返回将恢复的内容和警告
+func (s *Service) RestoreBackup(ctx context.IContext, r io.Reader, dryRun bool) (*models.RestoreResult, error) {
+	contents, err := parseBackup(r)
+	if err != nil {
+		return nil, err
+	}
+	state := &contents.state
+
+	if encrypted := encryptedValues(state); encrypted > 0 {
+		fingerprint := secretKeyFingerprint()
+		if fingerprint == "" {
+			return nil, fmt.Errorf("backup contains %d secrets but secret.key is not configured", encrypted)
+		}
+		if fingerprint != contents.manifest.SecretKeyFingerprint {
+			return nil, fmt.Errorf("secret.key does not match the key used by the backup, secrets could not be decrypted")
+		}
+	}
+
+	specs, err := s.store.ListServiceSpecs()
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) > 0 {
+		return nil, fmt.Errorf("restore requires a host without managed services, found %d services with desired state", len(specs))
+	}
+	if services := s.ListServices(ctx); len(services) > 0 {
+		return nil, fmt.Errorf("restore requires a host without managed services, found %d running services", len(services))
+	}
+
+	result := &models.RestoreResult{
+		Manifest:  contents.manifest,
+		DryRun:    dryRun,
+		Services:  make([]string, 0, len(state.ServiceSpecs)),
+		Ports:     len(state.Ports),
+		Secrets:   len(state.Secrets),
+		Templates: len(state.Templates),
+		CronJobs:  len(state.CronJobs),
+		Warnings:  proxyConfigWarnings(contents.proxy, proxyConfig()),
+	}
+	for _, spec := range state.ServiceSpecs {
+		result.Services = append(result.Services, spec.Name)
+		if owner := s.portOwner(ctx, spec.PublicPort); owner != "" && !spec.Stopped {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("public port %d of service %s is in use by %s", spec.PublicPort, spec.Name, owner))
+		}
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	if err := s.store.RestoreSnapshot(state); err != nil {
+		return nil, err
+	}
+	log.Info("Backup", log.Any("Hostname", contents.manifest.Hostname), log.Any("CreatedAt", contents.manifest.CreatedAt),
+		log.Any("Services", len(result.Services)), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "已恢复备份"))
+
+	result.Reconcile, err = s.Reconcile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("state restored but reconcile failed: %w", err)
+	}
+	return result, nil
+}
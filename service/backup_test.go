@@ -0,0 +1,58 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestBackupArchive 测试备份归档的写入与解析
+func TestBackupArchive(t *testing.T) {
+	files := map[string][]byte{
+		backupManifestFile: []byte(`{"format":1,"hostname":"docker-01","services":["web"]}`),
+		backupStateFile:    []byte(`{"service_specs":[{"Name":"web","PublicPort":9203,"Replicas":2}]}`),
+		backupProxyFile:    []byte(`{"proxy.read_timeout":"30"}`),
+	}
+	var buf bytes.Buffer
+	if err := writeBackup(&buf, []string{backupManifestFile, backupStateFile, backupProxyFile}, files); err != nil {
+		t.Fatalf("writeBackup = %v", err)
+	}
+	archive := buf.Bytes()
+
+	contents, err := parseBackup(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("parseBackup = %v", err)
+	}
+	if contents.manifest.Hostname != "docker-01" || len(contents.state.ServiceSpecs) != 1 || contents.state.ServiceSpecs[0].Replicas != 2 {
+		t.Errorf("parseBackup = %+v", contents)
+	}
+	if contents.proxy["proxy.read_timeout"] != "30" {
+		t.Errorf("代理配置 = %v", contents.proxy)
+	}
+
+	buf.Reset()
+	files[backupManifestFile] = []byte(`{"format":99}`)
+	writeBackup(&buf, []string{backupManifestFile, backupStateFile, backupProxyFile}, files)
+	if _, err := parseBackup(&buf); err == nil || !strings.Contains(err.Error(), "format") {
+		t.Errorf("不支持的格式版本 = %v, 期望返回错误", err)
+	}
+
+	buf.Reset()
+	writeBackup(&buf, []string{backupManifestFile}, files)
+	if _, err := parseBackup(&buf); err == nil {
+		t.Error("缺少 state.json 时应返回错误")
+	}
+	if _, err := parseBackup(strings.NewReader("not an archive")); err == nil {
+		t.Error("非 gzip 数据应返回错误")
+	}
+}
+
+// TestProxyConfigWarnings 测试代理配置差异提示
+func TestProxyConfigWarnings(t *testing.T) {
+	backup := map[string]string{"proxy.read_timeout": "60", "proxy.workers": "4", "container.load_balance_strategy": "round_robin"}
+	current := map[string]string{"proxy.read_timeout": "30", "proxy.workers": "4", "container.load_balance_strategy": "round_robin"}
+	warnings := proxyConfigWarnings(backup, current)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "proxy.read_timeout") {
+		t.Errorf("proxyConfigWarnings = %v, 期望只提示 proxy.read_timeout", warnings)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/validate"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// validateBatch 校验批量部署请求：字段约束、命名空间，以及批内服务名称、公共端口不能重复
+func validateBatch(reqs []models.ServiceRequest) map[int]error {
+	errs := make(map[int]error)
+	names := make(map[string]int)
+	ports := make(map[int]int)
+	for i := range reqs {
+		req := &reqs[i]
+		if err := validate.Struct(req); err != nil {
+			errs[i] = err
+			continue
+		}
+		if err := normalizeNamespace(req); err != nil {
+			errs[i] = err
+			continue
+		}
+		if first, ok := names[req.Name]; ok {
+			errs[i] = fmt.Errorf("duplicate service name %s (also at index %d)", req.Name, first)
+			continue
+		}
+		names[req.Name] = i
+		if req.PublicPort > 0 {
+			if first, ok := ports[req.PublicPort]; ok {
+				errs[i] = utils.NewError(utils.CodePortInUse, "duplicate public port %d (also used by %s)", req.PublicPort, reqs[first].Name)
+				continue
+			}
+			ports[req.PublicPort] = i
+		}
+	}
+	return errs
+}
+
+// DeployBatch 按顺序部署或更新多个服务，逐个返回结果，单个服务失败不影响其他服务
+func (s *Service) DeployBatch(ctx context.IContext, reqs []models.ServiceRequest) []*models.BatchDeployResult {
+	errs := validateBatch(reqs)
+	results := make([]*models.BatchDeployResult, 0, len(reqs))
+	succeeded := 0
+
+	for i := range reqs {
+		req := &reqs[i]
+		result := &models.BatchDeployResult{Name: req.Name}
+		results = append(results, result)
+
+		if err := errs[i]; err != nil {
+			result.Error = err.Error()
+			if validation, ok := err.(*models.ValidationError); ok {
+				result.Errors = validation.Fields
+			}
+			continue
+		}
+
+		service, err := s.DeployOrUpdateService(ctx, req)
+		if err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ServiceName", req.Name), log.Any("Message", "批量部署中服务部署失败"))
+			result.Error = err.Error()
+			continue
+		}
+		result.Success = true
+		result.Service = service
+		succeeded++
+	}
+
+	log.Info("Docker", log.Any("Total", len(reqs)), log.Any("Success", succeeded), log.Any("Message", "批量部署完成"))
+	return results
+}
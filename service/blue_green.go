@@ -0,0 +1,177 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// startBlueGreen 蓝绿更新：按新配置创建与现有副本数相同的新版本副本并验证
+// 确认前端口代理只转发到旧版本副本，由 PromoteBlueGreen 切换流量
+func (s *Service) startBlueGreen(ctx context.IContext, req *models.ServiceRequest, existing *models.Service, config *dockerclient.Service, replicas int) (*models.Service, error) {
+	if r := s.rollouts.get(req.Name); r != nil && r.active() {
+		return nil, fmt.Errorf("service %s has a rollout in progress", req.Name)
+	}
+
+	// 切换前新旧两组副本同时存在
+	err := s.checkQuota(ctx, quotaRequest{
+		Name:       req.Name,
+		PublicPort: existing.PublicPort,
+		Replicas:   replicas * 2,
+		Memory:     req.MemoryLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := specSnapshot(req)
+	if err != nil {
+		return nil, err
+	}
+	release := &store.Release{
+		Service:  req.Name,
+		Mode:     models.DeploymentModeBlueGreen,
+		Image:    req.Image,
+		Tag:      req.Tag,
+		Replicas: replicas,
+		Spec:     spec,
+		Actor:    actorFromContext(ctx),
+	}
+	// 先保存发布记录，端口代理据此排除随后创建的新版本副本
+	if err := s.store.AddRelease(release); err != nil {
+		return nil, err
+	}
+
+	log.Info("Release", log.Any("ServiceName", req.Name), log.Any("Image", release.Image+":"+release.Tag),
+		log.Any("Replicas", replicas), log.Any("Actor", release.Actor), log.Any("Message", "开始蓝绿更新，创建新版本副本"))
+
+	if err := s.createBlueGreenSet(ctx, req.Name, config, replicas); err != nil {
+		if discardErr := s.discardRelease(ctx, req.Name, config); discardErr != nil {
+			log.Error("Release", log.Any("Error", discardErr), log.Any("ServiceName", req.Name), log.Any("Message", "清理新版本副本失败"))
+		}
+		return nil, err
+	}
+
+	log.Info("Release", log.Any("ServiceName", req.Name), log.Any("Image", release.Image+":"+release.Tag),
+		log.Any("Message", "新版本副本已就绪，等待确认切换"))
+	return existing, nil
+}
+
+// createBlueGreenSet 创建新版本副本并并行验证，全部稳定运行（配置了 HEALTHCHECK 时为 healthy）才算成功
+func (s *Service) createBlueGreenSet(ctx context.IContext, name string, config *dockerclient.Service, replicas int) error {
+	if err := s.dockerClient.CreateReplicas(ctx, config, replicas); err != nil {
+		return fmt.Errorf("failed to create new version replicas: %w", err)
+	}
+	_, candidate, err := s.splitReleaseContainers(ctx, name, config)
+	if err != nil {
+		return err
+	}
+	if len(candidate) < replicas {
+		return fmt.Errorf("only %d of %d new version replicas were created", len(candidate), replicas)
+	}
+
+	window := time.Duration(utils.ConfGetInt("container.update_verify_timeout")) * time.Second
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var failed []string
+	for _, container := range candidate {
+		wg.Add(1)
+		go func(container dockerclient.ContainerInfo) {
+			defer wg.Done()
+			if err := s.dockerClient.WaitContainerReady(ctx, container.ID, window); err != nil {
+				log.Error("Release", log.Any("Error", err), log.Any("ContainerName", container.Name), log.Any("Message", "新版本副本验证失败"))
+				mutex.Lock()
+				failed = append(failed, fmt.Sprintf("%s: %v", container.Name, err))
+				mutex.Unlock()
+			}
+		}(container)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("new version replicas failed verification: %v", failed)
+	}
+	return nil
+}
+
+// GetBlueGreen 获取服务进行中的蓝绿更新
+func (s *Service) GetBlueGreen(ctx context.IContext, name string) (*models.ReleaseStatus, error) {
+	return s.getRelease(ctx, name, models.DeploymentModeBlueGreen)
+}
+
+// PromoteBlueGreen 确认蓝绿更新：端口代理切换到新版本副本，然后删除旧版本副本
+// 旧版本副本删除失败时保留发布记录，可再次调用以完成切换
+func (s *Service) PromoteBlueGreen(ctx context.IContext, name string) (*models.Service, error) {
+	leave, err := s.serialize(ctx, name, models.OperationBlueGreen)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return nil, err
+	}
+	release, req, err := s.loadRelease(name, models.DeploymentModeBlueGreen)
+	if err != nil {
+		return nil, err
+	}
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	config, err := releaseConfig(req, service.PublicPort)
+	if err != nil {
+		return nil, err
+	}
+
+	stable, candidate, err := s.splitReleaseContainers(ctx, name, config)
+	if err != nil {
+		return nil, err
+	}
+	running := 0
+	for _, container := range candidate {
+		if container.State == "running" {
+			running++
+		}
+	}
+	if running == 0 {
+		return nil, fmt.Errorf("no new version replica of service %s is running, abort the blue-green update instead", name)
+	}
+
+	// 切换流量：发布标记为已确认后，端口代理只转发到新版本副本
+	if err := s.store.MarkReleasePromoted(name); err != nil {
+		return nil, err
+	}
+	s.refreshPortProxy(ctx, service.PublicPort)
+	log.Info("Release", log.Any("ServiceName", name), log.Any("Image", release.Image+":"+release.Tag),
+		log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "蓝绿更新已切换流量，开始删除旧版本副本"))
+
+	if failed := s.removeReplicas(ctx, stable); len(failed) > 0 {
+		return nil, fmt.Errorf("traffic switched but failed to remove old replicas %v, retry promote to finish", failed)
+	}
+	if _, err := s.store.DeleteRelease(name); err != nil {
+		return nil, err
+	}
+	s.refreshPortProxy(ctx, service.PublicPort)
+
+	s.saveDesiredState(ctx, req, service.PublicPort, len(candidate))
+	s.recordRevision(ctx, models.RevisionActionUpdate, req, fmt.Sprintf("promoted blue-green %s:%s", release.Image, release.Tag))
+
+	log.Info("Release", log.Any("ServiceName", name), log.Any("Replicas", len(candidate)), log.Any("Message", "蓝绿更新完成"))
+	if updated := s.GetService(ctx, name); updated != nil {
+		return updated, nil
+	}
+	return service, nil
+}
+
+// AbortBlueGreen 放弃蓝绿更新：删除新版本副本，旧版本副本继续提供服务
+func (s *Service) AbortBlueGreen(ctx context.IContext, name string) error {
+	return s.abortRelease(ctx, name, models.DeploymentModeBlueGreen)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// SelectServices 按命名空间和标签选择器选择服务，返回按名称排序的服务名称
+// 令牌限定了服务范围时只选择范围内的服务
+func (s *Service) SelectServices(ctx context.IContext, namespace, selector string) ([]string, error) {
+	services, err := s.SearchServices(ctx, &models.ServiceQuery{Namespace: namespace, Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(services))
+	for _, service := range services {
+		if checkServiceScope(ctx, service.Name) == nil {
+			names = append(names, service.Name)
+		}
+	}
+	return names, nil
+}
+
+// DeleteServices 批量删除服务：按服务列表，或按命名空间和标签选择器选择
+// confirm 为 false 时只返回选中的服务，不删除；逐个删除，单个服务失败不影响其他服务
+// trash 未指定时取配置 trash.default，放入回收站时不保留容器
+func (s *Service) DeleteServices(ctx context.IContext, req *models.BulkDeleteRequest) ([]string, []*models.BulkDeleteResult, error) {
+	bySelector := req.Namespace != "" || req.Selector != ""
+	if len(req.Services) > 0 && bySelector {
+		return nil, nil, utils.NewError(utils.CodeInvalidRequest, "services cannot be combined with namespace or selector")
+	}
+	if len(req.Services) == 0 && !bySelector {
+		return nil, nil, utils.NewError(utils.CodeInvalidRequest, "services, namespace or selector is required")
+	}
+
+	var names []string
+	if bySelector {
+		selected, err := s.SelectServices(ctx, req.Namespace, req.Selector)
+		if err != nil {
+			return nil, nil, err
+		}
+		names = selected
+	} else {
+		seen := make(map[string]bool, len(req.Services))
+		for _, name := range req.Services {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	if !req.Confirm {
+		return names, nil, nil
+	}
+
+	trash := TrashByDefault()
+	if req.Trash != nil {
+		trash = *req.Trash
+	}
+	results := make([]*models.BulkDeleteResult, 0, len(names))
+	succeeded := 0
+	for _, name := range names {
+		result := &models.BulkDeleteResult{Name: name}
+		results = append(results, result)
+		err := checkServiceScope(ctx, name)
+		if err == nil && trash {
+			_, err = s.TrashService(ctx, name, false)
+		} else if err == nil {
+			err = s.DeleteService(ctx, name)
+		}
+		if err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "批量删除中服务删除失败"))
+			result.Error = err.Error()
+			result.ErrorCode = utils.ErrorCode(err)
+			continue
+		}
+		result.Success = true
+		result.Trashed = trash
+		succeeded++
+	}
+	log.Info("Docker", log.Any("Total", len(names)), log.Any("Success", succeeded), log.Any("Trash", trash), log.Any("Message", "批量删除完成"))
+	return names, results, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// bulkScaleOrder 批量扩缩容的执行顺序：先缩容后扩容，先释放资源和配额；同类按名称排序
+func bulkScaleOrder(current, target map[string]int) []string {
+	names := make([]string, 0, len(target))
+	for name := range target {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		downI := target[names[i]] < current[names[i]]
+		downJ := target[names[j]] < current[names[j]]
+		if downI != downJ {
+			return downI
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// checkBulkScale 扩缩容前检查单个服务：副本数、令牌范围、服务是否存在、锁定和进行中的发布
+func (s *Service) checkBulkScale(ctx context.IContext, name string, replicas int) (*models.Service, error) {
+	if replicas < 0 {
+		return nil, utils.NewError(utils.CodeInvalidRequest, "replicas of service %s must be greater than or equal to 0", name)
+	}
+	if err := checkServiceScope(ctx, name); err != nil {
+		return nil, err
+	}
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return nil, err
+	}
+	if release := s.pendingRelease(name); replicas > 0 && release != nil {
+		return nil, utils.NewError(utils.CodeConflict, "service %s has a %s release in progress, promote or abort it first", name, release.Mode)
+	}
+	return service, nil
+}
+
+// ScaleServices 批量扩缩容，返回每个服务的结果和是否已执行
+// 先检查全部服务，任一服务不满足条件时整批都不执行；检查通过后先缩容后扩容逐个执行，单个服务失败不影响其他服务
+func (s *Service) ScaleServices(ctx context.IContext, replicas map[string]int) ([]*models.BulkScaleResult, bool) {
+	current := make(map[string]int, len(replicas))
+	results := make(map[string]*models.BulkScaleResult, len(replicas))
+	valid := true
+	for name, target := range replicas {
+		result := &models.BulkScaleResult{Name: name, Replicas: target}
+		results[name] = result
+		service, err := s.checkBulkScale(ctx, name, target)
+		if err != nil {
+			result.Error = err.Error()
+			result.ErrorCode = utils.ErrorCode(err)
+			valid = false
+			continue
+		}
+		result.Previous = service.Replicas
+		current[name] = service.Replicas
+	}
+
+	ordered := make([]*models.BulkScaleResult, 0, len(replicas))
+	for _, name := range bulkScaleOrder(current, replicas) {
+		ordered = append(ordered, results[name])
+	}
+	if !valid {
+		for _, result := range ordered {
+			if result.Error == "" {
+				result.Error = "not applied: other services in the request failed the check"
+				result.ErrorCode = utils.CodeConflict
+			}
+		}
+		log.Warn("Docker", log.Any("Total", len(replicas)), log.Any("Message", "批量扩缩容检查未通过，未执行任何操作"))
+		return ordered, false
+	}
+
+	succeeded := 0
+	for _, result := range ordered {
+		if err := s.ScaleService(ctx, result.Name, result.Replicas); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ServiceName", result.Name), log.Any("Replicas", result.Replicas), log.Any("Message", "批量扩缩容中服务扩缩容失败"))
+			result.Error = err.Error()
+			result.ErrorCode = utils.ErrorCode(err)
+			continue
+		}
+		result.Success = true
+		succeeded++
+	}
+	log.Info("Docker", log.Any("Total", len(replicas)), log.Any("Success", succeeded), log.Any("Message", "批量扩缩容完成"))
+	return ordered, true
+}
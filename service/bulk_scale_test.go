@@ -0,0 +1,16 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestBulkScaleOrder 测试批量扩缩容先缩容后扩容，同类按名称排序
+func TestBulkScaleOrder(t *testing.T) {
+	current := map[string]int{"web": 2, "api": 3, "worker": 1, "cache": 1}
+	target := map[string]int{"web": 4, "api": 0, "worker": 1, "cache": 0, "db": 1}
+	want := []string{"api", "cache", "db", "web", "worker"}
+	if got := bulkScaleOrder(current, target); !reflect.DeepEqual(got, want) {
+		t.Errorf("bulkScaleOrder() = %v, 期望 %v", got, want)
+	}
+}
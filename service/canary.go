@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+)
+
+// StartCanary 发起金丝雀发布：按新配置额外创建金丝雀副本，与现有副本一起由端口代理负载均衡
+// 期望状态保持不变，调和、自动扩缩容和定时扩缩容在金丝雀发布结束前跳过该服务
+func (s *Service) StartCanary(ctx context.IContext, name string, req *models.CanaryRequest) (*models.ReleaseStatus, error) {
+	if req.Name == "" {
+		req.Name = name
+	}
+	if err := normalizeNamespace(&req.ServiceRequest); err != nil {
+		return nil, err
+	}
+	if req.Name != name {
+		return nil, fmt.Errorf("service name %s in request does not match %s", req.Name, name)
+	}
+	if req.CanaryReplicas < 0 {
+		return nil, fmt.Errorf("canary_replicas cannot be negative")
+	}
+	if req.CanaryReplicas == 0 {
+		req.CanaryReplicas = 1
+	}
+
+	leave, err := s.serialize(ctx, name, models.OperationCanary)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	existing := s.GetService(ctx, name)
+	if existing == nil {
+		return nil, errServiceNotFound(name)
+	}
+	if r := s.rollouts.get(name); r != nil && r.active() {
+		return nil, fmt.Errorf("service %s has a rollout in progress", name)
+	}
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return nil, err
+	}
+
+	if _, err := compileRequestRules(req.RequestRules); err != nil {
+		return nil, err
+	}
+	if err := validateMirrorConfig(req.Name, req.Mirror); err != nil {
+		return nil, err
+	}
+	if err := validateInitContainers(req.InitContainers); err != nil {
+		return nil, err
+	}
+	if err := validateSidecars(req.Sidecars); err != nil {
+		return nil, err
+	}
+	if err := s.checkSecretRefs(req.Environment); err != nil {
+		return nil, err
+	}
+	if err := s.checkEnvPlaceholders(ctx, req.Environment); err != nil {
+		return nil, err
+	}
+
+	old, err := s.currentServiceConfig(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+	config, err := releaseConfig(&req.ServiceRequest, existing.PublicPort)
+	if err != nil {
+		return nil, err
+	}
+	if !s.dockerClient.CompareServiceConfig(old, config) {
+		return nil, fmt.Errorf("canary config is identical to the running service")
+	}
+
+	err = s.checkQuota(ctx, quotaRequest{
+		Name:       name,
+		PublicPort: existing.PublicPort,
+		Replicas:   existing.Replicas + req.CanaryReplicas,
+		Memory:     req.MemoryLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := specSnapshot(&req.ServiceRequest)
+	if err != nil {
+		return nil, err
+	}
+	release := &store.Release{
+		Service:  name,
+		Mode:     models.DeploymentModeCanary,
+		Image:    req.Image,
+		Tag:      req.Tag,
+		Replicas: req.CanaryReplicas,
+		Spec:     spec,
+		Actor:    actorFromContext(ctx),
+	}
+	if err := s.store.AddRelease(release); err != nil {
+		return nil, err
+	}
+
+	log.Info("Release", log.Any("ServiceName", name), log.Any("Image", release.Image+":"+release.Tag),
+		log.Any("Replicas", req.CanaryReplicas), log.Any("Actor", release.Actor), log.Any("Message", "开始金丝雀发布"))
+
+	if err := s.dockerClient.CreateReplicas(ctx, config, req.CanaryReplicas); err != nil {
+		// 创建失败时清理已创建的金丝雀副本，服务保持原状
+		if discardErr := s.discardRelease(ctx, name, config); discardErr != nil {
+			log.Error("Release", log.Any("Error", discardErr), log.Any("ServiceName", name), log.Any("Message", "清理金丝雀副本失败"))
+		}
+		return nil, fmt.Errorf("failed to create canary replicas: %w", err)
+	}
+
+	s.refreshPortProxy(ctx, existing.PublicPort)
+	return s.releaseStatus(ctx, release, &req.ServiceRequest, existing.PublicPort)
+}
+
+// GetCanary 获取服务进行中的金丝雀发布
+func (s *Service) GetCanary(ctx context.IContext, name string) (*models.ReleaseStatus, error) {
+	return s.getRelease(ctx, name, models.DeploymentModeCanary)
+}
+
+// PromoteCanary 确认金丝雀版本：删除金丝雀副本后，将现有副本滚动更新为金丝雀配置
+func (s *Service) PromoteCanary(ctx context.IContext, name string) (*models.Service, error) {
+	leave, err := s.serialize(ctx, name, models.OperationCanary)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return nil, err
+	}
+	release, req, err := s.loadRelease(name, models.DeploymentModeCanary)
+	if err != nil {
+		return nil, err
+	}
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	config, err := releaseConfig(req, service.PublicPort)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Release", log.Any("ServiceName", name), log.Any("Image", release.Image+":"+release.Tag),
+		log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "确认金丝雀发布，开始滚动更新"))
+
+	if err := s.discardRelease(ctx, name, config); err != nil {
+		return nil, err
+	}
+	return s.updateService(ctx, req, models.RevisionActionUpdate, fmt.Sprintf("promoted canary %s:%s", release.Image, release.Tag))
+}
+
+// AbortCanary 放弃金丝雀版本：删除金丝雀副本，服务保持原配置
+func (s *Service) AbortCanary(ctx context.IContext, name string) error {
+	return s.abortRelease(ctx, name, models.DeploymentModeCanary)
+}
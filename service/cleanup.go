@@ -0,0 +1,212 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+)
+
+// replicaKey 服务副本编号
+type replicaKey struct {
+	service string
+	index   int
+}
+
+// pickReplica 从副本编号相同的容器中选出保留的一个：优先运行中，其次符合期望配置，最后选最新创建的
+func pickReplica(containers []dockerclient.ContainerInfo, matchesDesired func(dockerclient.ContainerInfo) bool) int {
+	score := func(c dockerclient.ContainerInfo) int {
+		s := 0
+		if c.State == "running" {
+			s += 2
+		}
+		if matchesDesired != nil && matchesDesired(c) {
+			s++
+		}
+		return s
+	}
+	created := func(c dockerclient.ContainerInfo) int64 {
+		n, _ := strconv.ParseInt(c.CreatedAt, 10, 64)
+		return n
+	}
+
+	best := 0
+	for i := 1; i < len(containers); i++ {
+		si, sb := score(containers[i]), score(containers[best])
+		if si > sb || si == sb && created(containers[i]) > created(containers[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// findOrphans 找出孤立容器，返回孤立容器及需要人工确认的说明
+// specs 为 nil 表示期望状态不可信（内存存储），此时不检查服务是否存在和端口是否一致
+// 没有期望状态但仍在运行的容器可能属于状态存储启用前部署的服务，只提示不删除
+func (s *Service) findOrphans(containers []dockerclient.ContainerInfo, specs map[string]*store.ServiceSpec) ([]models.OrphanContainer, []string) {
+	orphans := make([]models.OrphanContainer, 0)
+	var notes []string
+	orphan := func(c dockerclient.ContainerInfo, service, reason, detail string) {
+		orphans = append(orphans, models.OrphanContainer{
+			ID:      c.ID[:12],
+			Name:    c.Name,
+			State:   c.State,
+			Service: service,
+			Reason:  reason,
+			Detail:  detail,
+		})
+	}
+
+	ids := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		ids[c.ID] = true
+	}
+
+	replicas := make(map[replicaKey][]dockerclient.ContainerInfo)
+	for _, c := range containers {
+		// 边车容器随主容器删除，只处理主容器已不存在的
+		if mainID := c.Labels[s.dockerClient.LabelKey("sidecar_of")]; mainID != "" {
+			if !ids[mainID] {
+				orphan(c, c.Labels[s.dockerClient.LabelKey("service")], models.OrphanReasonSidecar, fmt.Sprintf("main container %.12s no longer exists", mainID))
+			}
+			continue
+		}
+		// 任务容器运行结束后会自动删除，运行中的不处理
+		if jobID := c.Labels[s.dockerClient.LabelKey("job")]; jobID != "" {
+			if c.State != "running" && !s.jobs.active(jobID) {
+				orphan(c, "", models.OrphanReasonJobLeftover, fmt.Sprintf("container of job %s", jobID))
+			}
+			continue
+		}
+		nameInfo, err := s.dockerClient.ParseContainerName(c.Name)
+		if err != nil {
+			orphan(c, "", models.OrphanReasonUnparsable, err.Error())
+			continue
+		}
+		// 正在滚动更新、金丝雀或蓝绿发布的服务会临时多出副本，不处理
+		if r := s.rollouts.get(nameInfo.ServiceName); r != nil && r.active() {
+			continue
+		}
+		if s.pendingRelease(nameInfo.ServiceName) != nil {
+			continue
+		}
+		if specs != nil {
+			spec, exists := specs[nameInfo.ServiceName]
+			if !exists && c.State == "running" {
+				notes = append(notes, fmt.Sprintf("%s is running without desired state, kept (redeploy or delete service %s to manage it)", c.Name, nameInfo.ServiceName))
+			} else if !exists {
+				orphan(c, nameInfo.ServiceName, models.OrphanReasonNoService, "no desired state recorded for this service")
+				continue
+			}
+			if exists && spec.PublicPort != nameInfo.PublicPort {
+				orphan(c, nameInfo.ServiceName, models.OrphanReasonPortMismatch,
+					fmt.Sprintf("public port %d, expected %d", nameInfo.PublicPort, spec.PublicPort))
+				continue
+			}
+		}
+		key := replicaKey{service: nameInfo.ServiceName, index: nameInfo.ReplicaIndex}
+		replicas[key] = append(replicas[key], c)
+	}
+
+	for key, group := range replicas {
+		if len(group) < 2 {
+			continue
+		}
+		desired := s.desiredDockerService(key.service)
+		keep := pickReplica(group, func(c dockerclient.ContainerInfo) bool {
+			return desired != nil && s.dockerClient.MatchesConfig(c, desired)
+		})
+		for i, c := range group {
+			if i != keep {
+				orphan(c, key.service, models.OrphanReasonDuplicate,
+					fmt.Sprintf("replica %d also used by %s", key.index, group[keep].Name))
+			}
+		}
+	}
+
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].Name < orphans[j].Name })
+	sort.Strings(notes)
+	return orphans, notes
+}
+
+// CleanupOrphans 清理孤立容器：名称无法解析、服务已删除（已停止的容器）、端口与期望状态不一致、副本编号重复的容器
+// 失败的部署、更新可能遗留这类容器；dryRun 为 true 时只列出不删除
+func (s *Service) CleanupOrphans(ctx context.IContext, dryRun bool) (*models.CleanupResult, error) {
+	// 与调和循环互斥，避免删除调和过程中刚创建的容器
+	s.reconciler.mutex.Lock()
+	defer s.reconciler.mutex.Unlock()
+
+	containers, err := s.dockerClient.ListPrefixedContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.CleanupResult{DryRun: dryRun}
+	var specs map[string]*store.ServiceSpec
+	if s.store.Persistent() {
+		list, err := s.store.ListServiceSpecs()
+		if err != nil {
+			return nil, err
+		}
+		specs = make(map[string]*store.ServiceSpec, len(list))
+		for _, spec := range list {
+			specs[spec.Name] = spec
+		}
+	} else {
+		result.Notes = append(result.Notes, "state store is not persistent, service existence and port checks skipped")
+	}
+
+	orphans, notes := s.findOrphans(containers, specs)
+	result.Orphans = orphans
+	result.Notes = append(result.Notes, notes...)
+	if dryRun {
+		return result, nil
+	}
+
+	byName := make(map[string]dockerclient.ContainerInfo, len(containers))
+	for _, c := range containers {
+		byName[c.Name] = c
+	}
+	affectedPorts := make(map[int]bool)
+	for i := range result.Orphans {
+		orphan := &result.Orphans[i]
+		if err := s.dockerClient.RemoveReplica(ctx, byName[orphan.Name]); err != nil {
+			orphan.Error = err.Error()
+			log.Error("Cleanup", log.Any("Error", err), log.Any("ContainerName", orphan.Name), log.Any("Message", "删除孤立容器失败"))
+			continue
+		}
+		orphan.Removed = true
+		result.Removed++
+		delete(byName, orphan.Name)
+		if nameInfo, err := s.dockerClient.ParseContainerName(orphan.Name); err == nil {
+			affectedPorts[nameInfo.PublicPort] = true
+		}
+		log.Info("Cleanup", log.Any("ContainerName", orphan.Name), log.Any("Reason", orphan.Reason),
+			log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "已删除孤立容器"))
+	}
+
+	// 端口仍有容器时刷新代理，否则停止代理
+	for port := range affectedPorts {
+		remaining := false
+		for _, c := range byName {
+			if nameInfo, err := s.dockerClient.ParseContainerName(c.Name); err == nil && nameInfo.PublicPort == port {
+				remaining = true
+				break
+			}
+		}
+		if remaining {
+			s.refreshPortProxy(ctx, port)
+			continue
+		}
+		if err := s.PortManager.StopPortProxy(port); err != nil {
+			log.Error("Cleanup", log.Any("Error", err), log.Any("PublicPort", port), log.Any("Message", "停止端口代理失败"))
+		}
+		s.DelContainerMapping(ctx, port)
+	}
+	return result, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/library/dockerclient"
+)
+
+// TestPickReplica 测试副本编号重复时保留哪个容器
+func TestPickReplica(t *testing.T) {
+	containers := []dockerclient.ContainerInfo{
+		{Name: "old-running", State: "running", CreatedAt: "100"},
+		{Name: "new-exited", State: "exited", CreatedAt: "300"},
+		{Name: "new-running", State: "running", CreatedAt: "200"},
+	}
+	if got := containers[pickReplica(containers, nil)].Name; got != "new-running" {
+		t.Errorf("pickReplica = %s, want new-running", got)
+	}
+
+	matches := func(c dockerclient.ContainerInfo) bool { return c.Name == "old-running" }
+	if got := containers[pickReplica(containers, matches)].Name; got != "old-running" {
+		t.Errorf("pickReplica with desired config = %s, want old-running", got)
+	}
+}
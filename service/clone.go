@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// cloneSpec 按克隆请求生成新服务的配置：名称、公共端口总是使用请求中的值，其余字段只在请求中填写时覆盖
+func cloneSpec(source *models.ServiceRequest, req *models.CloneRequest) *models.ServiceRequest {
+	spec := *source
+	spec.Name = req.Name
+	spec.Namespace = req.Namespace
+	spec.PublicPort = req.PublicPort // 不能与原服务共用公共端口，为 0 时自动分配
+	spec.UpdateStrategy = nil
+	spec.DeploymentMode = ""
+	spec.DryRun = false
+	if req.Tag != "" {
+		spec.Tag = req.Tag
+	}
+	if req.Replicas > 0 {
+		spec.Replicas = req.Replicas
+	}
+
+	environment := make(map[string]string, len(source.Environment)+len(req.Environment))
+	for key, value := range source.Environment {
+		environment[key] = value
+	}
+	for key, value := range req.Environment {
+		if value == "" {
+			delete(environment, key)
+			continue
+		}
+		environment[key] = value
+	}
+	spec.Environment = environment
+	return &spec
+}
+
+// CloneService 复制服务配置部署为新服务，用于快速创建评审、预发副本
+func (s *Service) CloneService(ctx context.IContext, name string, req *models.CloneRequest) (*models.Service, error) {
+	source, err := s.ExportService(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := cloneSpec(source, req)
+	if err := normalizeNamespace(spec); err != nil {
+		return nil, err
+	}
+	if s.GetService(ctx, spec.Name) != nil {
+		return nil, utils.NewError(utils.CodeServiceExists, "service %s already exists", spec.Name)
+	}
+
+	log.Info("Docker", log.Any("ServiceName", spec.Name), log.Any("Source", name), log.Any("Image", fmt.Sprintf("%s:%s", spec.Image, spec.Tag)), log.Any("Message", "克隆服务"))
+	return s.DeployOrUpdateService(ctx, spec)
+}
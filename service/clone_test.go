@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestCloneSpec 测试克隆时的字段覆盖与环境变量合并
+func TestCloneSpec(t *testing.T) {
+	source := &models.ServiceRequest{
+		Name:        "staging.web",
+		Namespace:   "staging",
+		Image:       "nginx",
+		Tag:         "1.25",
+		PublicPort:  9203,
+		Replicas:    3,
+		Environment: map[string]string{"MODE": "staging", "DEBUG": "1"},
+	}
+	spec := cloneSpec(source, &models.CloneRequest{
+		Name:        "web-review",
+		Tag:         "1.27",
+		Environment: map[string]string{"MODE": "review", "DEBUG": ""},
+	})
+
+	if spec.Name != "web-review" || spec.Namespace != "" || spec.Tag != "1.27" || spec.Image != "nginx" {
+		t.Errorf("cloneSpec = %+v, 期望使用新名称和标签并沿用镜像", spec)
+	}
+	if spec.PublicPort != 0 || spec.Replicas != 3 {
+		t.Errorf("cloneSpec 公共端口 = %d、副本数 = %d, 期望 0 和 3", spec.PublicPort, spec.Replicas)
+	}
+	if len(spec.Environment) != 1 || spec.Environment["MODE"] != "review" {
+		t.Errorf("cloneSpec 环境变量 = %v, 期望只有 MODE=review", spec.Environment)
+	}
+	if source.Environment["MODE"] != "staging" || len(source.Environment) != 2 {
+		t.Errorf("不应修改原服务的环境变量: %v", source.Environment)
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/cron"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// defaultCronJobHistoryLimit 定时任务默认保留的运行记录数
+const defaultCronJobHistoryLimit = 10
+
+// startCronJobScheduler 启动定时任务调度，每分钟整点检查一次
+func (s *Service) startCronJobScheduler() {
+	go func() {
+		for {
+			next := time.Now().Truncate(time.Minute).Add(time.Minute)
+			select {
+			case <-s.stopping:
+				return
+			case <-time.After(time.Until(next)):
+			}
+			s.runCronJobs(context.Background(), next)
+		}
+	}()
+	log.Info("CronJob", log.Any("Message", "定时任务调度已启动"))
+}
+
+// runCronJobs 触发到点的定时任务，已暂停的不处理
+func (s *Service) runCronJobs(ctx context.IContext, now time.Time) {
+	jobs, err := s.store.ListCronJobs()
+	if err != nil {
+		log.Error("CronJob", log.Any("Error", err), log.Any("Message", "读取定时任务失败"))
+		return
+	}
+
+	for _, job := range jobs {
+		if job.Suspended {
+			continue
+		}
+		schedule, err := cron.Parse(job.Cron)
+		if err != nil {
+			log.Error("CronJob", log.Any("Error", err), log.Any("CronJob", job.Name), log.Any("Message", "cron 表达式无效"))
+			continue
+		}
+		if !schedule.Match(now) {
+			continue
+		}
+		if _, err := s.triggerCronJob(ctx, job, now); err != nil {
+			log.Error("CronJob", log.Any("Error", err), log.Any("CronJob", job.Name), log.Any("Message", "触发定时任务失败"))
+		}
+	}
+}
+
+// activeCronJobRuns 定时任务运行中的任务ID
+func (s *Service) activeCronJobRuns(name string) ([]string, error) {
+	records, err := s.store.ListJobs(name, 0)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]string, 0)
+	for _, record := range records {
+		if s.jobs.active(record.JobID) {
+			active = append(active, record.JobID)
+		}
+	}
+	return active, nil
+}
+
+// triggerCronJob 按并发策略运行一次定时任务，并记录触发结果
+func (s *Service) triggerCronJob(ctx context.IContext, cronJob *store.CronJob, now time.Time) (*models.Job, error) {
+	job, err := s.startCronJobRun(ctx, cronJob)
+	jobID, lastError := "", ""
+	if job != nil {
+		jobID = job.ID
+	}
+	if err != nil {
+		lastError = err.Error()
+	}
+	if err := s.store.UpdateCronJobRun(cronJob.Name, now, jobID, lastError); err != nil {
+		log.Error("CronJob", log.Any("Error", err), log.Any("CronJob", cronJob.Name), log.Any("Message", "记录定时任务触发结果失败"))
+	}
+	return job, err
+}
+
+// startCronJobRun 处理并发策略后启动任务
+func (s *Service) startCronJobRun(ctx context.IContext, cronJob *store.CronJob) (*models.Job, error) {
+	active, err := s.activeCronJobRuns(cronJob.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) > 0 {
+		switch cronJob.ConcurrencyPolicy {
+		case models.ConcurrencyAllow:
+		case models.ConcurrencyReplace:
+			for _, id := range active {
+				s.jobs.cancel(id)
+			}
+			log.Info("CronJob", log.Any("CronJob", cronJob.Name), log.Any("Cancelled", active), log.Any("Message", "取消上一次运行"))
+		default:
+			return nil, fmt.Errorf("skipped: previous run %s is still running", strings.Join(active, ", "))
+		}
+	}
+
+	var req models.JobRequest
+	if err := utils.DeJson(cronJob.Spec, &req); err != nil {
+		return nil, fmt.Errorf("failed to decode job spec of cron job %s: %w", cronJob.Name, err)
+	}
+	if req.Name == "" {
+		req.Name = cronJob.Name
+	}
+	return s.startJob(ctx, &req, cronJob.Name, cronJob.HistoryLimit)
+}
+
+// toCronJob 将存储中的定时任务转换为 API 模型
+func (s *Service) toCronJob(cronJob *store.CronJob) (*models.CronJob, error) {
+	result := &models.CronJob{
+		Name:              cronJob.Name,
+		Cron:              cronJob.Cron,
+		ConcurrencyPolicy: cronJob.ConcurrencyPolicy,
+		HistoryLimit:      cronJob.HistoryLimit,
+		Suspended:         cronJob.Suspended,
+		LastJobID:         cronJob.LastJobID,
+		LastError:         cronJob.LastError,
+		CreatedAt:         cronJob.CreatedAt,
+		UpdatedAt:         cronJob.UpdatedAt,
+	}
+	if err := utils.DeJson(cronJob.Spec, &result.Job); err != nil {
+		return nil, fmt.Errorf("failed to decode job spec of cron job %s: %w", cronJob.Name, err)
+	}
+	if schedule, err := cron.Parse(cronJob.Cron); err == nil && !cronJob.Suspended {
+		if next := schedule.Next(time.Now()); !next.IsZero() {
+			result.NextRunAt = &next
+		}
+	}
+	if !cronJob.LastRunAt.IsZero() {
+		lastRunAt := cronJob.LastRunAt
+		result.LastRunAt = &lastRunAt
+	}
+	active, err := s.activeCronJobRuns(cronJob.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) > 0 {
+		result.Active = active
+	}
+	return result, nil
+}
+
+// SaveCronJob 创建或更新定时任务
+func (s *Service) SaveCronJob(ctx context.IContext, req *models.CronJobRequest) (*models.CronJob, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("cron job name is required")
+	}
+	if _, err := cron.Parse(req.Cron); err != nil {
+		return nil, err
+	}
+	switch req.ConcurrencyPolicy {
+	case "":
+		req.ConcurrencyPolicy = models.ConcurrencyForbid
+	case models.ConcurrencyAllow, models.ConcurrencyForbid, models.ConcurrencyReplace:
+	default:
+		return nil, fmt.Errorf("invalid concurrency_policy %q, must be allow, forbid or replace", req.ConcurrencyPolicy)
+	}
+	if req.HistoryLimit < 0 {
+		return nil, fmt.Errorf("history_limit cannot be negative")
+	}
+	if req.HistoryLimit == 0 {
+		req.HistoryLimit = defaultCronJobHistoryLimit
+	}
+	if err := s.validateJobRequest(ctx, &req.Job); err != nil {
+		return nil, err
+	}
+
+	spec, err := utils.EnJson(req.Job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job spec: %w", err)
+	}
+	record := &store.CronJob{
+		Name:              req.Name,
+		Cron:              req.Cron,
+		ConcurrencyPolicy: req.ConcurrencyPolicy,
+		HistoryLimit:      req.HistoryLimit,
+		Suspended:         req.Suspended,
+		Spec:              spec,
+	}
+	if err := s.store.SaveCronJob(record); err != nil {
+		return nil, err
+	}
+
+	log.Info("CronJob", log.Any("CronJob", req.Name), log.Any("Cron", req.Cron), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "保存定时任务"))
+	return s.toCronJob(record)
+}
+
+// ListCronJobs 列出全部定时任务
+func (s *Service) ListCronJobs(ctx context.IContext) ([]*models.CronJob, error) {
+	records, err := s.store.ListCronJobs()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.CronJob, 0, len(records))
+	for _, record := range records {
+		item, err := s.toCronJob(record)
+		if err != nil {
+			log.Error("CronJob", log.Any("Error", err), log.Any("CronJob", record.Name), log.Any("Message", "解析定时任务失败"))
+			continue
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// getCronJob 读取定时任务，不存在时返回错误
+func (s *Service) getCronJob(name string) (*store.CronJob, error) {
+	record, err := s.store.GetCronJob(name)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, utils.NewError(utils.CodeNotFound, "cron job %s not found", name)
+	}
+	return record, nil
+}
+
+// GetCronJob 获取定时任务及最近的运行记录（含退出码和日志）
+func (s *Service) GetCronJob(ctx context.IContext, name string) (*models.CronJob, error) {
+	record, err := s.getCronJob(name)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.toCronJob(record)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := s.store.ListJobs(name, 0)
+	if err != nil {
+		return nil, err
+	}
+	result.Runs = make([]*models.Job, 0, len(runs))
+	for _, run := range runs {
+		result.Runs = append(result.Runs, toJob(run, true))
+	}
+	return result, nil
+}
+
+// RunCronJob 立即触发一次定时任务，同样遵循并发策略
+func (s *Service) RunCronJob(ctx context.IContext, name string) (*models.Job, error) {
+	record, err := s.getCronJob(name)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("CronJob", log.Any("CronJob", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "手动触发定时任务"))
+	return s.triggerCronJob(ctx, record, time.Now())
+}
+
+// DeleteCronJob 删除定时任务及其运行记录，运行中的任务会被取消
+func (s *Service) DeleteCronJob(ctx context.IContext, name string) error {
+	exists, err := s.store.DeleteCronJob(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return utils.NewError(utils.CodeNotFound, "cron job %s not found", name)
+	}
+
+	if active, err := s.activeCronJobRuns(name); err == nil {
+		for _, id := range active {
+			s.jobs.cancel(id)
+		}
+	}
+	if err := s.store.DeleteJobs(name); err != nil {
+		log.Error("CronJob", log.Any("Error", err), log.Any("CronJob", name), log.Any("Message", "删除定时任务运行记录失败"))
+	}
+	log.Info("CronJob", log.Any("CronJob", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "删除定时任务"))
+	return nil
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestSaveCronJobValidation 测试定时任务参数校验
+func TestSaveCronJobValidation(t *testing.T) {
+	s := &Service{}
+	job := models.JobRequest{Image: "busybox", Tag: "latest"}
+	cases := []struct {
+		name string
+		req  models.CronJobRequest
+	}{
+		{"缺少名称", models.CronJobRequest{Cron: "0 2 * * *", Job: job}},
+		{"无效的 cron", models.CronJobRequest{Name: "nightly", Cron: "0 25 * * *", Job: job}},
+		{"无效的并发策略", models.CronJobRequest{Name: "nightly", Cron: "0 2 * * *", ConcurrencyPolicy: "queue", Job: job}},
+		{"负数的记录数", models.CronJobRequest{Name: "nightly", Cron: "0 2 * * *", HistoryLimit: -1, Job: job}},
+		{"缺少镜像", models.CronJobRequest{Name: "nightly", Cron: "0 2 * * *"}},
+	}
+	for _, tc := range cases {
+		if _, err := s.SaveCronJob(nil, &tc.req); err == nil {
+			t.Errorf("%s: 应返回错误", tc.name)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/google/uuid"
+)
+
+// defaultDeploymentHistoryLimit 默认保留的异步部署记录数
+const defaultDeploymentHistoryLimit = 100
+
+// deploymentProgress 将部署进度写入记录，回调可能被同一批次中并行更新的副本同时调用
+type deploymentProgress struct {
+	mutex  sync.Mutex
+	store  *store.Store
+	record *store.Deployment
+}
+
+// start 标记部署开始执行
+func (p *deploymentProgress) start() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.record.Status = models.DeploymentRunning
+	p.record.Message = ""
+	p.record.StartedAt = time.Now()
+	if err := p.store.UpdateDeployment(p.record); err != nil {
+		log.Error("Deployment", log.Any("Error", err), log.Any("DeploymentID", p.record.DeploymentID), log.Any("Message", "更新部署状态失败"))
+	}
+}
+
+// update 记录当前阶段；不涉及副本数的进度（如拉取镜像）保留已有的副本进度
+func (p *deploymentProgress) update(progress dockerclient.Progress) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.record.Step = progress.Step
+	p.record.Message = progress.Message
+	if progress.Total > 0 {
+		p.record.Current = progress.Current
+		p.record.Total = progress.Total
+	}
+	if err := p.store.UpdateDeployment(p.record); err != nil {
+		log.Error("Deployment", log.Any("Error", err), log.Any("DeploymentID", p.record.DeploymentID), log.Any("Message", "更新部署进度失败"))
+	}
+}
+
+// finish 保存部署结果
+func (p *deploymentProgress) finish(message string, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.record.Step = models.DeployStepDone
+	p.record.FinishedAt = time.Now()
+	if err != nil {
+		p.record.Status = models.DeploymentFailed
+		p.record.Error = err.Error()
+		p.record.Message = ""
+	} else {
+		p.record.Status = models.DeploymentSucceeded
+		p.record.Message = message
+		p.record.Current = p.record.Total
+	}
+	if err := p.store.UpdateDeployment(p.record); err != nil {
+		log.Error("Deployment", log.Any("Error", err), log.Any("DeploymentID", p.record.DeploymentID), log.Any("Message", "保存部署结果失败"))
+	}
+}
+
+// recoverDeployments 进程重启后，上次未结束的部署已无法跟踪，标记为失败
+func (s *Service) recoverDeployments() {
+	affected, err := s.store.FailUnfinishedDeployments([]string{models.DeploymentPending, models.DeploymentRunning},
+		models.DeploymentFailed, "interrupted by onedock restart")
+	if err != nil {
+		log.Error("Deployment", log.Any("Error", err), log.Any("Message", "清理中断的部署失败"))
+		return
+	}
+	if affected > 0 {
+		log.Warn("Deployment", log.Any("Count", affected), log.Any("Message", "上次运行中断的部署已标记为失败，调和循环会按期望状态补齐副本"))
+	}
+}
+
+// toDeployment 将存储中的部署记录转换为 API 模型
+func toDeployment(record *store.Deployment) *models.Deployment {
+	deployment := &models.Deployment{
+		ID:        record.DeploymentID,
+		Service:   record.Service,
+		Action:    record.Action,
+		Image:     record.Image,
+		Status:    record.Status,
+		Step:      record.Step,
+		Message:   record.Message,
+		Current:   record.Current,
+		Total:     record.Total,
+		Error:     record.Error,
+		Actor:     record.Actor,
+		CreatedAt: record.CreatedAt,
+	}
+	if !record.StartedAt.IsZero() {
+		deployment.StartedAt = &record.StartedAt
+	}
+	if !record.FinishedAt.IsZero() {
+		deployment.FinishedAt = &record.FinishedAt
+	}
+	if err := utils.DeJson(record.Spec, &deployment.Request); err != nil {
+		log.Error("Deployment", log.Any("Error", err), log.Any("DeploymentID", record.DeploymentID), log.Any("Message", "解析部署请求失败"))
+	}
+	return deployment
+}
+
+// StartDeployment 提交异步部署，立即返回部署ID，部署或更新在后台执行，进度通过 GetDeployment 查询
+func (s *Service) StartDeployment(ctx context.IContext, req *models.ServiceRequest) (*models.Deployment, error) {
+	if req.DryRun {
+		return nil, fmt.Errorf("dry_run cannot be combined with async")
+	}
+	if err := normalizeNamespace(req); err != nil {
+		return nil, err
+	}
+	if err := checkServiceScope(ctx, req.Name); err != nil {
+		return nil, err
+	}
+
+	action := models.DeploymentActionDeploy
+	if s.GetService(ctx, req.Name) != nil {
+		action = models.DeploymentActionUpdate
+	}
+	spec, err := specSnapshot(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode deployment request: %w", err)
+	}
+	record := &store.Deployment{
+		DeploymentID: strings.ReplaceAll(uuid.NewString(), "-", "")[:12],
+		Service:      req.Name,
+		Action:       action,
+		Image:        fmt.Sprintf("%s:%s", req.Image, req.Tag),
+		Spec:         spec,
+		Status:       models.DeploymentPending,
+		Step:         models.DeployStepQueued,
+		Actor:        actorFromContext(ctx),
+	}
+	limit := utils.ConfGetIntDefault("container.deployment_history_limit", defaultDeploymentHistoryLimit)
+	if err := s.store.AddDeployment(record, limit); err != nil {
+		return nil, err
+	}
+
+	log.Info("Deployment", log.Any("DeploymentID", record.DeploymentID), log.Any("RequestID", ctx.GetString(models.ContextKeyRequestID)),
+		log.Any("ServiceName", req.Name), log.Any("Action", action), log.Any("Image", record.Image), log.Any("Actor", record.Actor), log.Any("Message", "提交异步部署"))
+	// 请求返回后上下文即被取消，后台部署使用不随请求取消的上下文
+	go s.runDeployment(detachContext(ctx), record, req)
+	return toDeployment(record), nil
+}
+
+// runDeployment 在后台执行部署或更新，并记录进度与结果
+// 服务正在执行其他操作时，部署保持排队状态，轮到后才开始
+func (s *Service) runDeployment(ctx context.IContext, record *store.Deployment, req *models.ServiceRequest) {
+	progress := &deploymentProgress{store: s.store, record: record}
+	if running := s.operations.status(record.Service).Running; running != nil {
+		progress.update(dockerclient.Progress{Step: models.DeployStepQueued, Message: fmt.Sprintf("waiting for %s to finish", running.Operation)})
+	}
+	leave, err := s.serialize(ctx, record.Service, record.Action)
+	if err != nil {
+		progress.finish("", err)
+		log.Warn("Deployment", log.Any("DeploymentID", record.DeploymentID), log.Any("RequestID", ctx.GetString(models.ContextKeyRequestID)),
+			log.Any("ServiceName", record.Service), log.Any("Error", err), log.Any("Message", "异步部署排队超时"))
+		return
+	}
+	defer leave()
+
+	progress.start()
+	dockerclient.WithProgress(ctx, progress.update)
+
+	service, err := s.DeployOrUpdateService(ctx, req)
+	message := ""
+	if service != nil {
+		message = fmt.Sprintf("%s:%s running with %d replicas on port %d", service.Image, service.Tag, service.Replicas, service.PublicPort)
+	}
+	progress.finish(message, err)
+
+	log.Info("Deployment", log.Any("DeploymentID", record.DeploymentID), log.Any("RequestID", ctx.GetString(models.ContextKeyRequestID)),
+		log.Any("ServiceName", record.Service), log.Any("Status", record.Status), log.Any("Error", record.Error), log.Any("Message", "异步部署结束"))
+}
+
+// GetDeployment 获取异步部署的进度与结果
+func (s *Service) GetDeployment(ctx context.IContext, id string) (*models.Deployment, error) {
+	record, err := s.store.GetDeployment(id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, utils.NewError(utils.CodeNotFound, "deployment %s not found", id)
+	}
+	return toDeployment(record), nil
+}
+
+// ListDeployments 按提交时间倒序列出异步部署，service 不为空时只列出该服务的部署
+func (s *Service) ListDeployments(ctx context.IContext, service string, limit int) ([]*models.Deployment, error) {
+	records, err := s.store.ListDeployments(service, limit)
+	if err != nil {
+		return nil, err
+	}
+	deployments := make([]*models.Deployment, 0, len(records))
+	for _, record := range records {
+		deployments = append(deployments, toDeployment(record))
+	}
+	return deployments, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/jinzhu/copier"
+)
+
+// saveDesiredState 保存服务期望状态，部署和更新成功后调用
+// 首次部署时记录发起请求的令牌为服务所有者
+func (s *Service) saveDesiredState(ctx context.IContext, req *models.ServiceRequest, publicPort, replicas int) {
+	spec, err := specSnapshot(req)
+	if err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", req.Name), log.Any("Message", "序列化服务配置失败"))
+		return
+	}
+
+	err = s.store.SaveServiceSpec(&store.ServiceSpec{
+		Name:       req.Name,
+		Image:      req.Image,
+		Tag:        req.Tag,
+		PublicPort: publicPort,
+		Replicas:   replicas,
+		Memory:     req.MemoryLimit,
+		Owner:      ctx.GetString(models.ContextKeyTokenID),
+		Spec:       spec,
+	})
+	if err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", req.Name), log.Any("Message", "保存服务期望状态失败"))
+	}
+}
+
+// loadDesiredState 读取服务期望状态，不存在时返回 nil
+func (s *Service) loadDesiredState(name string) (*models.DesiredState, error) {
+	spec, err := s.store.GetServiceSpec(name)
+	if err != nil || spec == nil {
+		return nil, err
+	}
+
+	state := &models.DesiredState{
+		Name:       spec.Name,
+		Replicas:   spec.Replicas,
+		Stopped:    spec.Stopped,
+		Persistent: s.store.Persistent(),
+		CreatedAt:  spec.CreatedAt,
+		UpdatedAt:  spec.UpdatedAt,
+	}
+	if err := utils.DeJson(spec.Spec, &state.Spec); err != nil {
+		return nil, fmt.Errorf("failed to decode spec of service %s: %w", name, err)
+	}
+	state.Spec.PublicPort = spec.PublicPort
+	state.Spec.Replicas = spec.Replicas
+	return state, nil
+}
+
+// desiredDockerService 按期望状态构建完整的容器配置，没有记录时返回 nil
+func (s *Service) desiredDockerService(name string) *dockerclient.Service {
+	state, err := s.loadDesiredState(name)
+	if err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "读取服务期望状态失败"))
+		return nil
+	}
+	if state == nil {
+		return nil
+	}
+
+	config := &dockerclient.Service{}
+	if err := copier.Copy(config, &state.Spec); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "转换服务期望状态失败"))
+		return nil
+	}
+	return config
+}
+
+// GetDesiredState 获取服务期望状态，与导出接口相同，名称像敏感信息的明文环境变量脱敏
+func (s *Service) GetDesiredState(ctx context.IContext, name string) (*models.DesiredState, error) {
+	state, err := s.loadDesiredState(name)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no desired state recorded for service %s", name)
+	}
+	masked := *state
+	masked.Spec = *maskExportSpec(&state.Spec)
+	return &masked, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+	"github.com/jinzhu/copier"
+)
+
+// diffServiceConfig 比较两份服务配置，返回逐项的变化
+// 环境变量按名称、卷挂载按容器内路径逐项比较，其余字段整体比较
+func diffServiceConfig(old, new *dockerclient.Service) []models.ConfigChange {
+	changes := make([]models.ConfigChange, 0)
+	add := func(field string, oldValue, newValue interface{}, oldEmpty, newEmpty bool) {
+		switch {
+		case oldEmpty && newEmpty:
+		case oldEmpty:
+			changes = append(changes, models.ConfigChange{Field: field, Type: models.ChangeAdded, New: newValue})
+		case newEmpty:
+			changes = append(changes, models.ConfigChange{Field: field, Type: models.ChangeRemoved, Old: oldValue})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, models.ConfigChange{Field: field, Type: models.ChangeChanged, Old: oldValue, New: newValue})
+		}
+	}
+
+	add("image", old.Image+":"+old.Tag, new.Image+":"+new.Tag, false, false)
+	add("internal_port", old.InternalPort, new.InternalPort, old.InternalPort == 0, new.InternalPort == 0)
+	if new.PublicPort != 0 {
+		add("public_port", old.PublicPort, new.PublicPort, old.PublicPort == 0, false)
+	}
+
+	// 环境变量
+	keys := make(map[string]bool)
+	for k := range old.Environment {
+		keys[k] = true
+	}
+	for k := range new.Environment {
+		keys[k] = true
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		oldValue, inOld := old.Environment[k]
+		newValue, inNew := new.Environment[k]
+		add("environment."+k, oldValue, newValue, !inOld, !inNew)
+	}
+	add("env_file", old.EnvFile, new.EnvFile, old.EnvFile == "", new.EnvFile == "")
+
+	// 卷挂载
+	volumes := func(list []dockerclient.VolumeMount) map[string]string {
+		result := make(map[string]string, len(list))
+		for _, v := range list {
+			bind := v.Source
+			if v.ReadOnly {
+				bind += ":ro"
+			}
+			result[v.Destination] = bind
+		}
+		return result
+	}
+	oldVolumes, newVolumes := volumes(old.Volumes), volumes(new.Volumes)
+	destinations := make([]string, 0, len(oldVolumes)+len(newVolumes))
+	for d := range oldVolumes {
+		destinations = append(destinations, d)
+	}
+	for d := range newVolumes {
+		if _, ok := oldVolumes[d]; !ok {
+			destinations = append(destinations, d)
+		}
+	}
+	sort.Strings(destinations)
+	for _, d := range destinations {
+		oldValue, inOld := oldVolumes[d]
+		newValue, inNew := newVolumes[d]
+		add("volumes."+d, oldValue, newValue, !inOld, !inNew)
+	}
+
+	add("entrypoint", strings.Join(old.Entrypoint, " "), strings.Join(new.Entrypoint, " "), len(old.Entrypoint) == 0, len(new.Entrypoint) == 0)
+	add("command", strings.Join(old.Command, " "), strings.Join(new.Command, " "), len(old.Command) == 0, len(new.Command) == 0)
+	add("working_dir", old.WorkingDir, new.WorkingDir, old.WorkingDir == "", new.WorkingDir == "")
+	add("memory_limit", old.MemoryLimit, new.MemoryLimit, old.MemoryLimit == 0, new.MemoryLimit == 0)
+	add("bind_address", old.BindAddress, new.BindAddress, old.BindAddress == "", new.BindAddress == "")
+	add("proxy_workers", old.ProxyWorkers, new.ProxyWorkers, old.ProxyWorkers == 0, new.ProxyWorkers == 0)
+	add("request_rules", old.RequestRules, new.RequestRules, len(old.RequestRules) == 0, len(new.RequestRules) == 0)
+	add("mirror", old.Mirror, new.Mirror, old.Mirror == nil, new.Mirror == nil)
+	add("init_containers", old.InitContainers, new.InitContainers, len(old.InitContainers) == 0, len(new.InitContainers) == 0)
+	add("sidecars", old.Sidecars, new.Sidecars, len(old.Sidecars) == 0, len(new.Sidecars) == 0)
+	return changes
+}
+
+// DiffService 比较请求中的配置与运行中的服务，返回更新将带来的变化
+func (s *Service) DiffService(ctx context.IContext, name string, req *models.ServiceRequest) (*models.ConfigDiff, error) {
+	if req.Name == "" {
+		req.Name = name
+	}
+	if err := normalizeNamespace(req); err != nil {
+		return nil, err
+	}
+	if req.Name != name {
+		return nil, fmt.Errorf("service name %s in request does not match %s", req.Name, name)
+	}
+
+	existing := s.GetService(ctx, name)
+	if existing == nil {
+		return nil, errServiceNotFound(name)
+	}
+	old, err := s.currentServiceConfig(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	newConfig := &dockerclient.Service{}
+	if err := copier.Copy(newConfig, req); err != nil {
+		return nil, fmt.Errorf("failed to copy service request: %w", err)
+	}
+	return &models.ConfigDiff{
+		Service: name,
+		Changed: s.dockerClient.CompareServiceConfig(old, newConfig),
+		Changes: diffServiceConfig(old, newConfig),
+	}, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+)
+
+// TestDiffServiceConfig 测试配置差异的逐项比较
+func TestDiffServiceConfig(t *testing.T) {
+	old := &dockerclient.Service{
+		Image:        "nginx",
+		Tag:          "1.25",
+		InternalPort: 80,
+		PublicPort:   9203,
+		Environment:  map[string]string{"ENV": "prod", "LOG_LEVEL": "info"},
+		Volumes:      []dockerclient.VolumeMount{{Source: "/data", Destination: "/usr/share/nginx/html"}},
+	}
+
+	same := *old
+	same.PublicPort = 0
+	same.Environment = map[string]string{"LOG_LEVEL": "info", "ENV": "prod"}
+	if changes := diffServiceConfig(old, &same); len(changes) != 0 {
+		t.Errorf("相同配置不应有变化: %v", changes)
+	}
+
+	updated := *old
+	updated.Tag = "1.27"
+	updated.Environment = map[string]string{"ENV": "prod", "LOG_LEVEL": "debug", "DEBUG": "1"}
+	updated.Volumes = []dockerclient.VolumeMount{{Source: "/data", Destination: "/usr/share/nginx/html", ReadOnly: true}, {Source: "/logs", Destination: "/var/log/nginx"}}
+	updated.Command = []string{"nginx", "-g", "daemon off;"}
+	changes := diffServiceConfig(old, &updated)
+
+	want := []models.ConfigChange{
+		{Field: "image", Type: models.ChangeChanged, Old: "nginx:1.25", New: "nginx:1.27"},
+		{Field: "environment.DEBUG", Type: models.ChangeAdded, New: "1"},
+		{Field: "environment.LOG_LEVEL", Type: models.ChangeChanged, Old: "info", New: "debug"},
+		{Field: "volumes./usr/share/nginx/html", Type: models.ChangeChanged, Old: "/data", New: "/data:ro"},
+		{Field: "volumes./var/log/nginx", Type: models.ChangeAdded, New: "/logs"},
+		{Field: "command", Type: models.ChangeAdded, New: "nginx -g daemon off;"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("diffServiceConfig = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("diffServiceConfig[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+
+	removed := *old
+	removed.Environment = nil
+	changes = diffServiceConfig(old, &removed)
+	if len(changes) != 2 || changes[0].Type != models.ChangeRemoved || changes[0].Field != "environment.ENV" {
+		t.Errorf("删除环境变量应逐项报告: %+v", changes)
+	}
+}
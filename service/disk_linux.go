@@ -0,0 +1,14 @@
+//go:build linux
+
+package service
+
+import "syscall"
+
+// filesystemUsage 路径所在文件系统的总容量与可用空间（字节）
+func filesystemUsage(path string) (total, free uint64, ok bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, false
+	}
+	return stat.Blocks * uint64(stat.Bsize), stat.Bavail * uint64(stat.Bsize), true
+}
@@ -0,0 +1,16 @@
+//go:build linux
+
+package service
+
+import "testing"
+
+// TestFilesystemUsage 测试文件系统容量统计
+func TestFilesystemUsage(t *testing.T) {
+	total, free, ok := filesystemUsage(t.TempDir())
+	if !ok || total == 0 || free > total {
+		t.Errorf("filesystemUsage(临时目录) = %d, %d, %v, 期望总容量大于 0 且不小于可用空间", total, free, ok)
+	}
+	if _, _, ok := filesystemUsage("/nonexistent/onedock"); ok {
+		t.Error("不存在的目录应返回 ok = false")
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package service
+
+// filesystemUsage 当前平台不支持统计文件系统容量
+func filesystemUsage(path string) (total, free uint64, ok bool) {
+	return 0, 0, false
+}
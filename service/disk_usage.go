@@ -0,0 +1,172 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+)
+
+// shortImageID 去掉摘要算法前缀的短格式镜像ID
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
+
+// imagesReclaimable 镜像可回收的占用：镜像层总占用减去有容器使用的镜像独占的部分，与 docker system df 的算法一致
+func imagesReclaimable(layersSize int64, images []dockerclient.ImageUsage) int64 {
+	var used int64
+	for _, image := range images {
+		if image.Containers > 0 && image.SharedSize >= 0 {
+			used += image.Size - image.SharedSize
+		}
+	}
+	if reclaimable := layersSize - used; reclaimable > 0 {
+		return reclaimable
+	}
+	return 0
+}
+
+// appendService 向已排序的服务列表中加入服务，已存在时不重复加入
+func appendService(services []string, name string) []string {
+	i := sort.SearchStrings(services, name)
+	if i < len(services) && services[i] == name {
+		return services
+	}
+	services = append(services, "")
+	copy(services[i+1:], services[i:])
+	services[i] = name
+	return services
+}
+
+// buildDiskUsage 汇总 Docker 磁盘占用，按受管副本标出镜像、容器和卷所属的服务
+func buildDiskUsage(usage *dockerclient.DiskUsage, parse func(string) (*dockerclient.ContainerNameInfo, error)) *models.SystemDiskUsage {
+	result := &models.SystemDiskUsage{
+		Images:     make([]models.ImageDiskUsage, 0, len(usage.ImageItems)),
+		Containers: make([]models.ContainerDiskUsage, 0, len(usage.ContainerItems)),
+		Volumes:    make([]models.VolumeDiskUsage, 0, len(usage.VolumeItems)),
+	}
+
+	imageServices := make(map[string][]string)
+	volumeServices := make(map[string][]string)
+	containers := models.DiskUsageSummary{Type: models.DiskUsageContainers, Total: len(usage.ContainerItems)}
+	for _, c := range usage.ContainerItems {
+		item := models.ContainerDiskUsage{
+			ID:         c.ID,
+			Name:       c.Name,
+			Image:      c.Image,
+			State:      c.State,
+			SizeRw:     c.SizeRw,
+			SizeRootFs: c.SizeRootFs,
+		}
+		if len(item.ID) > 12 {
+			item.ID = item.ID[:12]
+		}
+		if nameInfo, err := parse(c.Name); err == nil {
+			item.Service = nameInfo.ServiceName
+			item.Replica = nameInfo.ReplicaIndex
+			imageServices[c.ImageID] = appendService(imageServices[c.ImageID], item.Service)
+			for _, volume := range c.Volumes {
+				volumeServices[volume] = appendService(volumeServices[volume], item.Service)
+			}
+		}
+		containers.Size += c.SizeRw
+		if c.State == "running" {
+			containers.Active++
+		} else {
+			containers.Reclaimable += c.SizeRw
+		}
+		result.Containers = append(result.Containers, item)
+	}
+
+	images := models.DiskUsageSummary{
+		Type:        models.DiskUsageImages,
+		Total:       len(usage.ImageItems),
+		Size:        usage.LayersSize,
+		Reclaimable: imagesReclaimable(usage.LayersSize, usage.ImageItems),
+	}
+	for _, image := range usage.ImageItems {
+		if image.Containers > 0 {
+			images.Active++
+		}
+		item := models.ImageDiskUsage{
+			ID:         shortImageID(image.ID),
+			Tags:       image.Tags,
+			Size:       image.Size,
+			SharedSize: image.SharedSize,
+			UniqueSize: -1,
+			Containers: image.Containers,
+			Services:   imageServices[image.ID],
+			CreatedAt:  time.Unix(image.Created, 0).UTC(),
+		}
+		if item.Tags == nil {
+			item.Tags = []string{}
+		}
+		if item.Services == nil {
+			item.Services = []string{}
+		}
+		if image.SharedSize >= 0 {
+			item.UniqueSize = image.Size - image.SharedSize
+		}
+		result.Images = append(result.Images, item)
+	}
+
+	volumes := models.DiskUsageSummary{Type: models.DiskUsageVolumes, Total: len(usage.VolumeItems)}
+	for _, volume := range usage.VolumeItems {
+		if volume.RefCount > 0 {
+			volumes.Active++
+		}
+		if volume.Size > 0 {
+			volumes.Size += volume.Size
+			if volume.RefCount == 0 {
+				volumes.Reclaimable += volume.Size
+			}
+		}
+		item := models.VolumeDiskUsage{
+			Name:     volume.Name,
+			Size:     volume.Size,
+			RefCount: volume.RefCount,
+			Services: volumeServices[volume.Name],
+		}
+		if item.Services == nil {
+			item.Services = []string{}
+		}
+		result.Volumes = append(result.Volumes, item)
+	}
+
+	buildCache := models.DiskUsageSummary{
+		Type:        models.DiskUsageBuildCache,
+		Total:       usage.BuildCacheCount,
+		Active:      usage.BuildCacheActive,
+		Size:        usage.BuildCacheSize,
+		Reclaimable: usage.BuildCacheReclaimableSize,
+	}
+	result.Summary = []models.DiskUsageSummary{images, containers, volumes, buildCache}
+
+	sort.SliceStable(result.Images, func(i, j int) bool { return result.Images[i].Size > result.Images[j].Size })
+	sort.SliceStable(result.Containers, func(i, j int) bool { return result.Containers[i].SizeRw > result.Containers[j].SizeRw })
+	sort.SliceStable(result.Volumes, func(i, j int) bool { return result.Volumes[i].Size > result.Volumes[j].Size })
+	return result
+}
+
+// GetDiskUsage 获取 Docker 磁盘占用详情，以及 Docker 数据目录所在文件系统的容量（OneDock 能访问该目录时）
+func (s *Service) GetDiskUsage(ctx context.IContext) (*models.SystemDiskUsage, error) {
+	usage, err := s.dockerClient.DiskUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := buildDiskUsage(usage, s.dockerClient.ParseContainerName)
+	if daemon, err := s.dockerClient.DaemonInfo(ctx); err == nil && daemon.RootDir != "" {
+		if total, free, ok := filesystemUsage(daemon.RootDir); ok {
+			result.FilesystemTotal = total
+			result.FilesystemFree = free
+		}
+	}
+	return result, nil
+}
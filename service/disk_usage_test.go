@@ -0,0 +1,81 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+)
+
+// TestImagesReclaimable 测试镜像可回收占用的计算
+func TestImagesReclaimable(t *testing.T) {
+	images := []dockerclient.ImageUsage{
+		{Size: 100, SharedSize: 20, Containers: 1},
+		{Size: 50, SharedSize: 20, Containers: 0},
+		{Size: 30, SharedSize: -1, Containers: 2},
+	}
+	if got := imagesReclaimable(150, images); got != 70 {
+		t.Errorf("imagesReclaimable() = %d, 期望 70", got)
+	}
+	if got := imagesReclaimable(10, images); got != 0 {
+		t.Errorf("使用中的占用超过总占用时 imagesReclaimable() = %d, 期望 0", got)
+	}
+}
+
+// TestBuildDiskUsage 测试磁盘占用汇总和所属服务
+func TestBuildDiskUsage(t *testing.T) {
+	usage := &dockerclient.DiskUsage{
+		LayersSize: 300,
+		ImageItems: []dockerclient.ImageUsage{
+			{ID: "sha256:1111111111111111", Tags: []string{"nginx:alpine"}, Size: 100, SharedSize: 0, Containers: 2},
+			{ID: "sha256:2222222222222222", Size: 200, SharedSize: -1, Containers: 0},
+		},
+		ContainerItems: []dockerclient.ContainerUsage{
+			{ID: "aaaaaaaaaaaa0001", Name: "web-0", ImageID: "sha256:1111111111111111", State: "running", SizeRw: 10, Volumes: []string{"web-data"}},
+			{ID: "bbbbbbbbbbbb0001", Name: "web-1", ImageID: "sha256:1111111111111111", State: "exited", SizeRw: 30, Volumes: []string{"web-data"}},
+			{ID: "cccccccccccc0001", Name: "redis", ImageID: "sha256:3333333333333333", State: "exited", SizeRw: 5},
+		},
+		VolumeItems: []dockerclient.VolumeInfo{
+			{Name: "web-data", Size: 40, RefCount: 2},
+			{Name: "old-data", Size: 60, RefCount: 0},
+			{Name: "remote", Size: -1, RefCount: -1},
+		},
+		BuildCacheCount:           3,
+		BuildCacheActive:          1,
+		BuildCacheSize:            90,
+		BuildCacheReclaimableSize: 60,
+	}
+
+	result := buildDiskUsage(usage, parseTestContainerName)
+	want := []models.DiskUsageSummary{
+		{Type: models.DiskUsageImages, Total: 2, Active: 1, Size: 300, Reclaimable: 200},
+		{Type: models.DiskUsageContainers, Total: 3, Active: 1, Size: 45, Reclaimable: 35},
+		{Type: models.DiskUsageVolumes, Total: 3, Active: 1, Size: 100, Reclaimable: 60},
+		{Type: models.DiskUsageBuildCache, Total: 3, Active: 1, Size: 90, Reclaimable: 60},
+	}
+	if !reflect.DeepEqual(result.Summary, want) {
+		t.Errorf("Summary = %+v, 期望 %+v", result.Summary, want)
+	}
+
+	if result.Images[0].ID != "222222222222" || result.Images[0].UniqueSize != -1 || len(result.Images[0].Services) != 0 {
+		t.Errorf("Images[0] = %+v, 期望按大小排序、未知共享大小的镜像独占大小为 -1", result.Images[0])
+	}
+	if image := result.Images[1]; image.UniqueSize != 100 || !reflect.DeepEqual(image.Services, []string{"web"}) {
+		t.Errorf("Images[1] = %+v, 期望所属服务 [web]", image)
+	}
+
+	if c := result.Containers[0]; c.Name != "web-1" || c.Service != "web" || c.Replica != 1 || c.ID != "bbbbbbbbbbbb" {
+		t.Errorf("Containers[0] = %+v, 期望按可写层大小排序并标出服务和副本", c)
+	}
+	if c := result.Containers[2]; c.Service != "" {
+		t.Errorf("不是受管副本的容器 Service = %q, 期望为空", c.Service)
+	}
+
+	if v := result.Volumes[0]; v.Name != "old-data" || len(v.Services) != 0 {
+		t.Errorf("Volumes[0] = %+v, 期望未使用的 old-data", v)
+	}
+	if v := result.Volumes[1]; v.Name != "web-data" || !reflect.DeepEqual(v.Services, []string{"web"}) {
+		t.Errorf("Volumes[1] = %+v, 期望所属服务 [web]", v)
+	}
+}
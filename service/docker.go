@@ -8,12 +8,41 @@ import (
 	"github.com/aichy126/igo/context"
 	"github.com/aichy126/igo/log"
 	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/validate"
 	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
 	"github.com/jinzhu/copier"
 )
 
 // DeployOrUpdateService 部署或更新服务
 func (s *Service) DeployOrUpdateService(ctx context.IContext, req *models.ServiceRequest) (*models.Service, error) {
+	// 预演请求由 PlanDeploy 处理，防止经批量部署、模板部署等入口误执行
+	if req.DryRun {
+		return nil, fmt.Errorf("dry_run is only supported by the deploy endpoint")
+	}
+	if err := validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if err := normalizeNamespace(req); err != nil {
+		return nil, err
+	}
+	if err := checkServiceScope(ctx, req.Name); err != nil {
+		return nil, err
+	}
+	// 同一服务的修改操作逐个执行，是部署还是更新也在排到后再判断
+	leave, err := s.serialize(ctx, req.Name, models.OperationDeploy)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	if err := s.checkSecretRefs(req.Environment); err != nil {
+		return nil, err
+	}
+	if err := s.checkEnvPlaceholders(ctx, req.Environment); err != nil {
+		return nil, err
+	}
+
 	// 检查服务是否存在
 	existingService := s.GetService(ctx, req.Name)
 	if existingService != nil {
@@ -22,26 +51,73 @@ func (s *Service) DeployOrUpdateService(ctx context.IContext, req *models.Servic
 		return s.UpdateService(ctx, req)
 	}
 
-	// 设置默认值
+	// 校验请求过滤规则
+	if _, err := compileRequestRules(req.RequestRules); err != nil {
+		return nil, err
+	}
+	if err := validateMirrorConfig(req.Name, req.Mirror); err != nil {
+		return nil, err
+	}
+	if err := validateInitContainers(req.InitContainers); err != nil {
+		return nil, err
+	}
+	if err := validateSidecars(req.Sidecars); err != nil {
+		return nil, err
+	}
+
+	// 未指定公共端口时从 ports.auto_range 中自动分配
 	if req.PublicPort == 0 {
-		return nil, fmt.Errorf("public port cannot be empty")
+		port, err := s.pickPublicPort(ctx, req.Name, ctx.GetString(models.ContextKeyTokenID))
+		if err != nil {
+			return nil, err
+		}
+		req.PublicPort = port
+		log.Info("Docker", log.Any("ServiceName", req.Name), log.Any("PublicPort", port), log.Any("Message", "自动分配公共端口"))
+	}
+	if err := s.checkPortRegistry(req.Name, req.PublicPort); err != nil {
+		return nil, err
+	}
+	// 端口已被其他服务、容器或主机进程占用时直接失败，避免代理启动失败后服务无法访问
+	if err := s.checkPortConflict(ctx, req.Name, req.PublicPort); err != nil {
+		return nil, err
 	}
 
 	if req.Replicas == 0 {
 		req.Replicas = 1
 	}
 
+	// 校验命名空间与令牌配额
+	err = s.checkQuota(ctx, quotaRequest{
+		Name:       req.Name,
+		Owner:      ctx.GetString(models.ContextKeyTokenID),
+		PublicPort: req.PublicPort,
+		Replicas:   req.Replicas,
+		Memory:     req.MemoryLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 登记公共端口，部署失败时释放
+	if err := s.store.AllocatePort(req.PublicPort, req.Name, actorFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
 	// 构建dockerclient.Service（端口由dockerclient内部分配）
 	dockerService := &dockerclient.Service{}
-	err := copier.Copy(dockerService, req)
+	err = copier.Copy(dockerService, req)
 	if err != nil {
+		s.releasePorts(req.Name)
 		return nil, fmt.Errorf("failed to copy service request: %w", err)
 	}
 
 	// 创建容器（镜像拉取在 CreateContainer 中统一处理）
+	dockerclient.ReportProgress(ctx, dockerclient.Progress{Step: models.DeployStepCreating, Total: dockerService.Replicas,
+		Message: fmt.Sprintf("creating replica 1/%d", dockerService.Replicas)})
 	containerID, err := s.dockerClient.CreateContainer(ctx, dockerService, 0)
 	if err != nil {
 		log.Error("Docker", log.Any("Error", err), log.Any("Message", "创建容器失败"))
+		s.releasePorts(req.Name)
 		return nil, fmt.Errorf("failed to create container: %w", err)
 	}
 
@@ -51,12 +127,13 @@ func (s *Service) DeployOrUpdateService(ctx context.IContext, req *models.Servic
 		log.Error("Docker", log.Any("Error", err), log.Any("ContainerID", containerID[:12]), log.Any("Message", "启动容器失败"))
 		// 清理失败的容器
 		s.dockerClient.RemoveContainer(ctx, containerID)
+		s.releasePorts(req.Name)
 		return nil, fmt.Errorf("failed to start container: %w", err)
 	}
 
 	// 如果需要多个副本，使用dockerclient的扩缩容功能
 	if dockerService.Replicas > 1 {
-		err = s.dockerClient.ScaleService(ctx, dockerService.Name, dockerService.Replicas)
+		err = s.dockerClient.ScaleServiceWithConfig(ctx, dockerService.Name, dockerService.Replicas, dockerService)
 		if err != nil {
 			log.Error("Docker", log.Any("Error", err), log.Any("TargetReplicas", dockerService.Replicas), log.Any("Message", "扩展副本失败"))
 			// 如果扩容失败，保持单个容器运行
@@ -67,12 +144,18 @@ func (s *Service) DeployOrUpdateService(ctx context.IContext, req *models.Servic
 	service := &models.Service{
 		ID:           fmt.Sprintf("svc_%d", time.Now().Unix()),
 		Name:         dockerService.Name,
+		Namespace:    req.Namespace,
 		Image:        dockerService.Image,
 		Tag:          dockerService.Tag,
 		Status:       models.StatusRunning,
 		PublicPort:   dockerService.PublicPort,
 		InternalPort: dockerService.InternalPort,
 		Replicas:     dockerService.Replicas,
+		MemoryLimit:  dockerService.MemoryLimit,
+		BindAddress:  dockerService.BindAddress,
+		ProxyWorkers: dockerService.ProxyWorkers,
+		RequestRules: dockerService.RequestRules,
+		Mirror:       dockerService.Mirror,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
@@ -85,6 +168,11 @@ func (s *Service) DeployOrUpdateService(ctx context.IContext, req *models.Servic
 		log.Info("Docker", log.Any("PublicPort", dockerService.PublicPort), log.Any("ServiceName", dockerService.Name), log.Any("Message", "端口代理启动成功"))
 	}
 
+	s.saveDesiredState(ctx, req, dockerService.PublicPort, dockerService.Replicas)
+	s.recordRevision(ctx, models.RevisionActionDeploy, req, "")
+	s.recordEvent(ctx, req.Name, models.EventDeployed, fmt.Sprintf("deployed %s:%s with %d replicas on port %d",
+		req.Image, req.Tag, dockerService.Replicas, dockerService.PublicPort))
+
 	return service, nil
 }
 
@@ -125,8 +213,8 @@ func (s *Service) GetService(ctx context.IContext, name string) *models.Service
 // DeleteService 删除服务
 func (s *Service) DeleteService(ctx context.IContext, name string) error {
 	// 直接调用扩缩容功能，设置为0副本即删除所有容器
-	// 删除代理的逻辑统一在 ScaleService 中处理
-	return s.ScaleService(ctx, name, 0)
+	// 删除代理的逻辑统一在 scaleService 中处理
+	return s.scaleService(ctx, name, 0)
 }
 
 // GetServiceStatus 获取服务状态
@@ -143,7 +231,17 @@ func (s *Service) GetServiceStatus(ctx context.IContext, name string) (*models.S
 	// 获取指定的服务
 	service, exists := serviceMap[name]
 	if !exists {
-		return nil, fmt.Errorf("service %s not found", name)
+		return nil, errServiceNotFound(name)
+	}
+
+	// 金丝雀或蓝绿发布期间标记新版本副本
+	var candidate *dockerclient.Service
+	release := s.pendingRelease(name)
+	var releaseReq *models.ServiceRequest
+	if release != nil {
+		if _, releaseReq, err = s.loadRelease(name, release.Mode); err == nil {
+			candidate, _ = releaseConfig(releaseReq, service.PublicPort)
+		}
 	}
 
 	var instances []models.ServiceInstanceInfo
@@ -187,6 +285,7 @@ func (s *Service) GetServiceStatus(ctx context.IContext, name string) (*models.S
 				CPUUsage:      0.0,
 				MemoryUsage:   0.0,
 				MemoryLimit:   0.0,
+				Candidate:     candidate != nil && s.dockerClient.MatchesConfig(container, candidate),
 			}
 
 			if container.CreatedAt != "" {
@@ -223,33 +322,91 @@ func (s *Service) GetServiceStatus(ctx context.IContext, name string) (*models.S
 		CreatedAt:       service.CreatedAt,
 		UpdatedAt:       service.UpdatedAt,
 	}
+	if candidate != nil {
+		status.Release, _ = s.releaseStatus(ctx, release, releaseReq, service.PublicPort)
+	}
+	if lock := s.serviceLock(name); lock != nil {
+		status.Lock = toServiceLock(lock)
+	}
+	if s.operations.busy(name) {
+		status.Operations = s.operations.status(name)
+	}
 
 	return status, nil
 }
 
-// ScaleService 服务扩缩容 - 直接调用dockerclient
+// ScaleService 服务扩缩容，副本数为 0 即删除服务，配置了 trash.default 时放入回收站
 func (s *Service) ScaleService(ctx context.IContext, name string, replicas int) error {
+	if replicas == 0 && TrashByDefault() {
+		_, err := s.TrashService(ctx, name, false)
+		return err
+	}
+	return s.scaleService(ctx, name, replicas)
+}
+
+// scaleService 服务扩缩容 - 直接调用dockerclient，副本数为 0 时彻底删除服务
+func (s *Service) scaleService(ctx context.IContext, name string, replicas int) error {
+	leave, err := s.serialize(ctx, name, models.OperationScale)
+	if err != nil {
+		return err
+	}
+	defer leave()
+
 	// 获取服务信息以确定公共端口
 	service := s.GetService(ctx, name)
 	if service == nil {
-		return fmt.Errorf("service %s not found", name)
+		return errServiceNotFound(name)
+	}
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return err
+	}
+
+	// 金丝雀或蓝绿发布期间副本数由发布流程管理，只允许删除服务
+	if release := s.pendingRelease(name); replicas > 0 && release != nil {
+		return utils.NewError(utils.CodeConflict, "service %s has a %s release in progress, promote or abort it first", name, release.Mode)
+	}
+
+	// 执行扩缩容操作，扩容时按期望状态中的完整配置创建副本
+	config := s.desiredDockerService(name)
+	if config != nil {
+		config.PublicPort = service.PublicPort
 	}
 
-	// 执行扩缩容操作
-	err := s.dockerClient.ScaleService(ctx, name, replicas)
+	// 扩容时校验配额，缩容不受限制
+	if replicas > service.Replicas {
+		memory := service.MemoryLimit
+		if config != nil {
+			memory = config.MemoryLimit
+		}
+		err := s.checkQuota(ctx, quotaRequest{Name: name, PublicPort: service.PublicPort, Replicas: replicas, Memory: memory})
+		if err != nil {
+			return err
+		}
+	}
+	err = s.dockerClient.ScaleServiceWithConfig(ctx, name, replicas, config)
 	if err != nil {
 		return err
 	}
+
+	// 同步期望状态，副本数为 0 即删除服务
+	if replicas == 0 {
+		err = s.deleteServiceState(name)
+	} else {
+		err = s.store.UpdateServiceReplicas(name, replicas)
+	}
+	if err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "更新服务期望状态失败"))
+	}
 	s.DelContainerMapping(ctx, service.PublicPort)
+	if replicas == 0 {
+		s.recordEvent(ctx, name, models.EventDeleted, fmt.Sprintf("deleted %d replicas", service.Replicas))
+	} else if replicas != service.Replicas {
+		s.recordEvent(ctx, name, models.EventScaled, fmt.Sprintf("scaled from %d to %d replicas", service.Replicas, replicas))
+	}
 
 	if replicas == 0 {
 		// 副本数为 0，删除服务，停止端口代理
-		if err := s.PortManager.StopPortProxy(service.PublicPort); err != nil {
-			log.Error("Docker", log.Any("Error", err), log.Any("PublicPort", service.PublicPort), log.Any("ServiceName", name), log.Any("Message", "停止端口代理失败"))
-			// 端口代理停止失败不影响服务删除，记录日志即可
-		} else {
-			log.Info("Docker", log.Any("PublicPort", service.PublicPort), log.Any("ServiceName", name), log.Any("Message", "端口代理停止成功"))
-		}
+		s.removePortProxy(name, service.PublicPort)
 
 		// 清理端口映射缓存
 		if err := s.DelContainerMapping(ctx, service.PublicPort); err != nil {
@@ -271,6 +428,41 @@ func (s *Service) ScaleService(ctx context.IContext, name string, replicas int)
 	return nil
 }
 
+// deleteServiceState 删除服务时清理状态存储：期望状态、定时与自动扩缩容、发布、锁、公共端口和镜像监视
+func (s *Service) deleteServiceState(name string) error {
+	err := s.store.DeleteServiceSpec(name)
+	if err == nil {
+		err = s.store.DeleteScaleSchedules(name)
+	}
+	if err == nil {
+		_, err = s.store.DeleteAutoscalePolicy(name)
+	}
+	if err == nil {
+		_, err = s.store.DeleteRelease(name)
+	}
+	if err == nil {
+		_, err = s.store.DeleteServiceLock(name)
+	}
+	if err == nil {
+		err = s.store.ReleasePorts(name)
+	}
+	if err == nil {
+		_, err = s.store.DeleteImageWatch(name)
+	}
+	return err
+}
+
+// removePortProxy 删除服务时停止端口代理并清除运行时切换的代理设置
+func (s *Service) removePortProxy(name string, publicPort int) {
+	if err := s.PortManager.StopPortProxy(publicPort); err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("PublicPort", publicPort), log.Any("ServiceName", name), log.Any("Message", "停止端口代理失败"))
+		// 端口代理停止失败不影响服务删除，记录日志即可
+	} else {
+		log.Info("Docker", log.Any("PublicPort", publicPort), log.Any("ServiceName", name), log.Any("Message", "端口代理停止成功"))
+	}
+	s.PortManager.ClearPortOverrides(publicPort)
+}
+
 // 辅助方法
 
 // processContainersToServices 处理容器列表，按服务分组并返回服务映射
@@ -313,15 +505,22 @@ func (s *Service) createServiceFromContainer(container dockerclient.ContainerInf
 	}
 
 	// 转换为 models.Service
+	namespace, _ := models.SplitServiceName(dockerService.Name)
 	service := &models.Service{
 		ID:           container.ID[:12],
 		Name:         dockerService.Name,
+		Namespace:    namespace,
 		Image:        dockerService.Image,
 		Tag:          dockerService.Tag,
 		Status:       models.ServiceStatus(container.State),
 		PublicPort:   dockerService.PublicPort,
 		InternalPort: dockerService.InternalPort,
 		Replicas:     1, // 初始设为1，后续会更新
+		MemoryLimit:  dockerService.MemoryLimit,
+		BindAddress:  dockerService.BindAddress,
+		ProxyWorkers: dockerService.ProxyWorkers,
+		RequestRules: dockerService.RequestRules,
+		Mirror:       dockerService.Mirror,
 	}
 
 	if container.CreatedAt != "" {
@@ -375,9 +574,11 @@ func (s *Service) createServiceFromContainerFallback(container dockerclient.Cont
 		serviceName = nameInfo.ServiceName
 	}
 
+	namespace, _ := models.SplitServiceName(serviceName)
 	service := &models.Service{
 		ID:           container.ID[:12],
 		Name:         serviceName,
+		Namespace:    namespace,
 		Image:        image,
 		Tag:          tag,
 		Status:       models.ServiceStatus(container.State),
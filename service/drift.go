@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+)
+
+// GetServiceDrift 比较期望状态与实际运行的容器，只报告差异，不做任何修改
+func (s *Service) GetServiceDrift(ctx context.IContext, name string) (*models.DriftReport, error) {
+	state, err := s.loadDesiredState(name)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no desired state recorded for service %s", name)
+	}
+
+	config := s.desiredDockerService(name)
+	if config == nil {
+		return nil, fmt.Errorf("failed to build container config from desired state")
+	}
+
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	desiredImage := fmt.Sprintf("%s:%s", config.Image, config.Tag)
+	report := &models.DriftReport{
+		Service:         name,
+		DesiredImage:    desiredImage,
+		DesiredHash:     s.dockerClient.ConfigHash(config),
+		DesiredReplicas: state.Replicas,
+		ActualReplicas:  len(containers),
+		Stopped:         state.Stopped,
+		Replicas:        make([]models.ReplicaDrift, 0, len(containers)),
+		CheckedAt:       time.Now(),
+	}
+
+	if len(containers) != state.Replicas {
+		report.Differences = append(report.Differences, fmt.Sprintf("replicas %d, expected %d", len(containers), state.Replicas))
+	}
+
+	for _, container := range containers {
+		replica := models.ReplicaDrift{
+			Container:  container.Name,
+			State:      container.State,
+			ConfigHash: container.Labels[s.dockerClient.LabelKey("config_hash")],
+		}
+
+		if current, err := s.dockerClient.ExtractServiceFromContainer(container); err == nil {
+			replica.Image = fmt.Sprintf("%s:%s", current.Image, current.Tag)
+		} else {
+			replica.Image = container.Image
+		}
+		if replica.Image != desiredImage {
+			replica.Differences = append(replica.Differences, fmt.Sprintf("image %s, expected %s", replica.Image, desiredImage))
+		}
+
+		switch {
+		case replica.ConfigHash == "":
+			replica.Differences = append(replica.Differences, "no config_hash label, config cannot be verified")
+		case replica.ConfigHash != report.DesiredHash:
+			replica.Differences = append(replica.Differences, "config (environment, volumes, command, etc.) differs from desired state")
+		}
+
+		running := container.State == "running"
+		if state.Stopped && running {
+			replica.Differences = append(replica.Differences, "running while service is stopped")
+		} else if !state.Stopped && !running {
+			replica.Differences = append(replica.Differences, fmt.Sprintf("state %s, expected running", container.State))
+		}
+
+		replica.Drifted = len(replica.Differences) > 0
+		if replica.Drifted {
+			report.Drifted = true
+		}
+		report.Replicas = append(report.Replicas, replica)
+	}
+
+	if len(report.Differences) > 0 {
+		report.Drifted = true
+	}
+	return report, nil
+}
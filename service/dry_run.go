@@ -0,0 +1,191 @@
+package service
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/validate"
+	"github.com/aichy126/onedock/models"
+	"github.com/jinzhu/copier"
+)
+
+// currentServiceConfig 获取服务当前的完整配置：优先使用期望状态，没有时从容器标签还原
+func (s *Service) currentServiceConfig(ctx context.IContext, existing *models.Service) (*dockerclient.Service, error) {
+	if desired := s.desiredDockerService(existing.Name); desired != nil {
+		desired.PublicPort = existing.PublicPort
+		return desired, nil
+	}
+
+	containers, err := s.serviceContainers(ctx, existing.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, container := range containers {
+		if config, err := s.dockerClient.ExtractServiceFromContainer(container); err == nil {
+			return config, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to extract configuration of service %s", existing.Name)
+}
+
+// PlanDeploy 部署预演：校验请求、检查镜像是否可拉取、公共端口是否可用，返回实际部署时将执行的操作
+// 只读取 Docker 和状态存储，不创建或修改任何容器
+func (s *Service) PlanDeploy(ctx context.IContext, req *models.ServiceRequest) (*models.DeployPlan, error) {
+	plan := &models.DeployPlan{Replicas: req.Replicas}
+	fail := func(format string, args ...interface{}) {
+		plan.Errors = append(plan.Errors, fmt.Sprintf(format, args...))
+	}
+
+	if err := normalizeNamespace(req); err != nil {
+		fail("%v", err)
+	}
+	if err := checkServiceScope(ctx, req.Name); err != nil {
+		fail("%v", err)
+	}
+	plan.Service = req.Name
+	plan.Image = fmt.Sprintf("%s:%s", req.Image, req.Tag)
+
+	if err := validate.Struct(req); err != nil {
+		if validation, ok := err.(*models.ValidationError); ok {
+			for _, field := range validation.Fields {
+				fail("%s", field.Message)
+			}
+		} else {
+			fail("%v", err)
+		}
+	}
+	if _, err := compileRequestRules(req.RequestRules); err != nil {
+		fail("%v", err)
+	}
+	if err := validateMirrorConfig(req.Name, req.Mirror); err != nil {
+		fail("%v", err)
+	}
+	if err := validateInitContainers(req.InitContainers); err != nil {
+		fail("%v", err)
+	}
+	if err := validateSidecars(req.Sidecars); err != nil {
+		fail("%v", err)
+	}
+	if err := s.checkSecretRefs(req.Environment); err != nil {
+		fail("%v", err)
+	}
+	if err := s.checkEnvPlaceholders(ctx, req.Environment); err != nil {
+		fail("%v", err)
+	}
+	if req.EnvFile != "" {
+		if _, err := os.Stat(req.EnvFile); err != nil {
+			fail("env_file %s is not readable: %v", req.EnvFile, err)
+		}
+	}
+	for _, volume := range req.Volumes {
+		if _, err := os.Stat(volume.Source); err != nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("volume source %s does not exist, docker will create an empty directory", volume.Source))
+		}
+	}
+
+	if req.Image != "" && req.Tag != "" {
+		source, err := s.dockerClient.CheckImage(ctx, req.Image, req.Tag)
+		if err != nil {
+			fail("%v", err)
+		}
+		plan.ImageSource = source
+	}
+
+	existing := s.GetService(ctx, req.Name)
+	if existing != nil {
+		s.planUpdate(ctx, req, existing, plan)
+	} else {
+		s.planCreate(ctx, req, plan)
+	}
+
+	plan.Valid = len(plan.Errors) == 0
+	return plan, nil
+}
+
+// planCreate 预演新服务部署：检查公共端口与配额
+func (s *Service) planCreate(ctx context.IContext, req *models.ServiceRequest, plan *models.DeployPlan) {
+	plan.Action = models.PlanActionCreate
+	plan.PublicPort = req.PublicPort
+	if plan.Replicas == 0 {
+		plan.Replicas = 1
+	}
+
+	if req.PublicPort == 0 {
+		port, err := s.pickPublicPort(ctx, req.Name, ctx.GetString(models.ContextKeyTokenID))
+		if err != nil {
+			plan.Errors = append(plan.Errors, err.Error())
+			return
+		}
+		req.PublicPort = port
+		plan.PublicPort = port
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("public port %d will be allocated automatically and may differ at deploy time", port))
+	}
+	if err := s.checkPortRegistry(req.Name, req.PublicPort); err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+		return
+	}
+	if err := s.checkPortConflict(ctx, req.Name, req.PublicPort); err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+		return
+	}
+
+	err := s.checkQuota(ctx, quotaRequest{
+		Name:       req.Name,
+		Owner:      ctx.GetString(models.ContextKeyTokenID),
+		PublicPort: req.PublicPort,
+		Replicas:   plan.Replicas,
+		Memory:     req.MemoryLimit,
+	})
+	if err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+	}
+}
+
+// planUpdate 预演已有服务的更新：比较配置差异，公共端口与副本数保持不变
+func (s *Service) planUpdate(ctx context.IContext, req *models.ServiceRequest, existing *models.Service, plan *models.DeployPlan) {
+	plan.Action = models.PlanActionUpdate
+	plan.PublicPort = existing.PublicPort
+	plan.CurrentReplicas = existing.Replicas
+	plan.Replicas = existing.Replicas
+
+	if req.PublicPort != 0 && req.PublicPort != existing.PublicPort {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("public port cannot be changed by update, keeping %d", existing.PublicPort))
+	}
+	if _, err := resolveUpdateStrategy(req.UpdateStrategy); err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+	}
+	if r := s.rollouts.get(req.Name); r != nil && r.active() {
+		plan.Errors = append(plan.Errors, fmt.Sprintf("a rollout of service %s is already in progress", req.Name))
+	}
+	if err := validateDeploymentMode(req.DeploymentMode); err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+	}
+	if release := s.pendingRelease(req.Name); release != nil {
+		plan.Errors = append(plan.Errors, fmt.Sprintf("service %s has a %s release in progress, promote or abort it first", req.Name, release.Mode))
+	}
+
+	old, err := s.currentServiceConfig(ctx, existing)
+	if err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+		return
+	}
+	newConfig := &dockerclient.Service{}
+	if err := copier.Copy(newConfig, req); err != nil {
+		plan.Errors = append(plan.Errors, fmt.Sprintf("failed to copy service request: %v", err))
+		return
+	}
+
+	plan.Changes = diffServiceConfig(old, newConfig)
+	if !s.dockerClient.CompareServiceConfig(old, newConfig) {
+		plan.Action = models.PlanActionNone
+		return
+	}
+	if req.MemoryLimit != old.MemoryLimit && (req.MemoryLimit > old.MemoryLimit || req.MemoryLimit <= 0) {
+		err := s.checkQuota(ctx, quotaRequest{Name: req.Name, PublicPort: existing.PublicPort, Replicas: existing.Replicas, Memory: req.MemoryLimit})
+		if err != nil {
+			plan.Errors = append(plan.Errors, err.Error())
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestDeployRejectsDryRun 测试预演请求不会被实际部署
+func TestDeployRejectsDryRun(t *testing.T) {
+	s := &Service{}
+	if _, err := s.DeployOrUpdateService(nil, &models.ServiceRequest{Name: "web", DryRun: true}); err == nil {
+		t.Error("dry_run 请求应被拒绝")
+	}
+}
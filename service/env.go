@@ -0,0 +1,196 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+)
+
+// maskedEnvValue 敏感变量脱敏后显示的值
+const maskedEnvValue = "******"
+
+// sensitiveEnvNames 名称包含这些词（不区分大小写）的变量视为敏感，不是密钥引用时也不返回明文
+var sensitiveEnvNames = []string{"password", "passwd", "secret", "token", "credential", "api_key", "apikey", "private_key", "access_key"}
+
+// isSensitiveEnvName 变量名是否像密码、令牌等敏感信息
+func isSensitiveEnvName(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveEnvNames {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretEnvRefs 副本中来自密钥的变量（变量名 → secret://<名称>）：以创建容器时记录在标签中的为准，
+// 再补充当前配置中引用密钥的变量（兼容没有该标签的旧容器）
+func secretEnvRefs(recorded map[string]string, current map[string]string) map[string]string {
+	refs := make(map[string]string, len(recorded))
+	for key, value := range current {
+		if strings.HasPrefix(value, models.SecretRefPrefix) {
+			refs[key] = value
+		}
+	}
+	for key, ref := range recorded {
+		refs[key] = ref
+	}
+	return refs
+}
+
+// displayEnvValue 变量的显示值：来自密钥的显示为引用，名称像敏感信息的脱敏，其余原样返回
+func displayEnvValue(key, value string, secrets map[string]string) string {
+	if ref, ok := secrets[key]; ok {
+		return ref
+	}
+	if value != "" && isSensitiveEnvName(key) {
+		return maskedEnvValue
+	}
+	return value
+}
+
+// configuredEnv 配置中的一个环境变量及其来源
+type configuredEnv struct {
+	value  string
+	source string
+}
+
+// GetServiceEnvironment 获取服务实际生效的环境变量：读取副本容器中的环境变量，按当前配置（环境变量文件与 environment 合并）标注来源，
+// 引用密钥的变量显示为引用，与当前配置不同的变量标出将使用的值。index 小于 0 时使用编号最小的副本
+func (s *Service) GetServiceEnvironment(ctx context.IContext, name string, index int) (*models.ServiceEnvironment, error) {
+	state, err := s.loadDesiredState(name)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.ServiceEnvironment{Service: name}
+	var environment map[string]string
+	if state != nil {
+		result.EnvFile, environment = state.Spec.EnvFile, state.Spec.Environment
+	} else if s.GetService(ctx, name) != nil {
+		// 没有期望状态的服务无法得知配置，全部变量按镜像变量返回
+		result.Warnings = append(result.Warnings, "service has no desired state, variables cannot be attributed to its configuration")
+	} else {
+		return nil, errServiceNotFound(name)
+	}
+
+	configured := make(map[string]configuredEnv)
+	if result.EnvFile != "" {
+		vars, err := s.dockerClient.ReadEnvFile(result.EnvFile)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("env_file is not readable, its variables are not labelled: %v", err))
+		}
+		for key, value := range vars {
+			configured[key] = configuredEnv{value: value, source: models.EnvSourceEnvFile}
+		}
+	}
+	for key, value := range environment {
+		configured[key] = configuredEnv{value: value, source: models.EnvSourceEnvironment}
+	}
+
+	// 按创建容器时的规则替换占位符，得到当前配置下的值；密钥不解析
+	expected := make(map[string]string, len(configured))
+	for key, env := range configured {
+		if strings.HasPrefix(env.value, models.SecretRefPrefix) {
+			continue
+		}
+		values, err := expandEnvPlaceholders(map[string]string{key: env.value}, func(service, field string) (string, error) {
+			return s.serviceEnvField(ctx, service, field)
+		}, advertisedHostIP)
+		if err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+			continue
+		}
+		expected[key] = values[key]
+	}
+
+	container, err := s.environmentReplica(ctx, name, index)
+	if err != nil {
+		return nil, err
+	}
+	var replicaEnv []string
+	var recorded map[string]string
+	if container != nil {
+		detail, err := s.dockerClient.InspectContainerDetail(ctx, container.ID)
+		if err != nil {
+			return nil, err
+		}
+		result.Replica = detail.Name
+		replicaEnv = detail.Env
+		recorded = s.dockerClient.ParseSecretEnv(detail.Labels)
+	} else {
+		result.Warnings = append(result.Warnings, "service has no replicas, showing the configured environment")
+	}
+	current := make(map[string]string, len(configured))
+	for key, env := range configured {
+		current[key] = env.value
+	}
+	result.Variables = effectiveEnvironment(replicaEnv, container != nil, secretEnvRefs(recorded, current), configured, expected)
+	sort.Strings(result.Warnings)
+	return result, nil
+}
+
+// environmentReplica 读取环境变量的副本：index 小于 0 时为编号最小的副本，服务没有副本时返回 nil
+func (s *Service) environmentReplica(ctx context.IContext, name string, index int) (*dockerclient.ContainerInfo, error) {
+	if index >= 0 {
+		return s.replicaContainer(ctx, name, index)
+	}
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var first *dockerclient.ContainerInfo
+	lowest := -1
+	for i := range containers {
+		nameInfo, err := s.dockerClient.ParseContainerName(containers[i].Name)
+		if err == nil && (lowest < 0 || nameInfo.ReplicaIndex < lowest) {
+			first, lowest = &containers[i], nameInfo.ReplicaIndex
+		}
+	}
+	return first, nil
+}
+
+// effectiveEnvironment 合并副本中的环境变量与当前配置：配置中的变量标注来源，其余为镜像变量；
+// secrets 中来自密钥的变量显示为引用，名称像敏感信息的变量脱敏；expected 为按当前配置解析出的值，与副本不同或副本中缺少时标出
+// hasReplica 为 false 时只返回配置中的变量
+func effectiveEnvironment(replicaEnv []string, hasReplica bool, secrets map[string]string, configured map[string]configuredEnv, expected map[string]string) []models.EnvVariable {
+	actual := make(map[string]string, len(replicaEnv))
+	for _, item := range replicaEnv {
+		key, value, _ := strings.Cut(item, "=")
+		actual[key] = value
+	}
+
+	variables := make([]models.EnvVariable, 0, len(actual)+len(configured))
+	for key, value := range actual {
+		variable := models.EnvVariable{Name: key, Value: displayEnvValue(key, value, secrets), Source: models.EnvSourceImage}
+		if env, ok := configured[key]; ok {
+			variable.Source = env.source
+			if strings.HasPrefix(env.value, models.SecretRefPrefix) {
+				if variable.Value != env.value {
+					variable.Differs, variable.Expected = true, env.value
+				}
+			} else if want, ok := expected[key]; ok && want != value {
+				variable.Differs, variable.Expected = true, displayEnvValue(key, want, nil)
+			}
+		}
+		variables = append(variables, variable)
+	}
+	for key, env := range configured {
+		if _, ok := actual[key]; ok {
+			continue
+		}
+		variable := models.EnvVariable{Name: key, Value: env.value, Source: env.source}
+		if want, ok := expected[key]; ok {
+			variable.Value = displayEnvValue(key, want, nil)
+		}
+		if hasReplica {
+			variable.Value, variable.Differs, variable.Expected = "", true, variable.Value
+		}
+		variables = append(variables, variable)
+	}
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Name < variables[j].Name })
+	return variables
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/utils"
+)
+
+// envPlaceholderPattern 环境变量占位符：${host_ip} 或 ${service:<服务名>.<字段>}
+// 服务名可能带命名空间（staging.redis），字段取最后一个点之后的部分
+var envPlaceholderPattern = regexp.MustCompile(`\$\{(host_ip|service:([^}]+)\.([a-z_]+))\}`)
+
+// envServiceFields 跨服务引用支持的字段
+var envServiceFields = map[string]bool{
+	"public_port":   true,
+	"internal_port": true,
+	"replicas":      true,
+}
+
+// envServiceRefs 收集环境变量中引用的服务名称（去重、排序），字段不受支持时返回错误
+func envServiceRefs(env map[string]string) ([]string, error) {
+	names := make([]string, 0)
+	for key, value := range env {
+		for _, match := range envPlaceholderPattern.FindAllStringSubmatch(value, -1) {
+			if match[1] == "host_ip" {
+				continue
+			}
+			if !envServiceFields[match[3]] {
+				return nil, fmt.Errorf("environment %s: unsupported field %q in %s, must be public_port, internal_port or replicas", key, match[3], match[0])
+			}
+			names = append(names, match[2])
+		}
+	}
+	sort.Strings(names)
+	return uniqueStrings(names), nil
+}
+
+// expandEnvPlaceholders 替换环境变量中的占位符，不修改传入的映射
+// lookup 返回被引用服务的字段值，hostIP 仅在用到 ${host_ip} 时调用
+func expandEnvPlaceholders(env map[string]string, lookup func(service, field string) (string, error), hostIP func() (string, error)) (map[string]string, error) {
+	result := make(map[string]string, len(env))
+	for key, value := range env {
+		var expandErr error
+		result[key] = envPlaceholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+			if expandErr != nil {
+				return placeholder
+			}
+			match := envPlaceholderPattern.FindStringSubmatch(placeholder)
+			var resolved string
+			if match[1] == "host_ip" {
+				resolved, expandErr = hostIP()
+			} else {
+				resolved, expandErr = lookup(match[2], match[3])
+			}
+			if expandErr != nil {
+				expandErr = fmt.Errorf("environment %s: failed to resolve %s: %w", key, placeholder, expandErr)
+				return placeholder
+			}
+			return resolved
+		})
+		if expandErr != nil {
+			return nil, expandErr
+		}
+	}
+	return result, nil
+}
+
+// advertisedHostIP 容器访问宿主机使用的地址：优先 container.advertise_ip，否则取第一个非回环 IPv4 地址
+func advertisedHostIP() (string, error) {
+	if ip := utils.ConfGetString("container.advertise_ip"); ip != "" {
+		return ip, nil
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no non-loopback IPv4 address found, set container.advertise_ip")
+}
+
+// serviceEnvField 读取被引用服务的字段值，优先使用期望状态，没有期望状态时使用运行中的服务
+func (s *Service) serviceEnvField(ctx context.IContext, name, field string) (string, error) {
+	var publicPort, internalPort, replicas int
+	state, err := s.loadDesiredState(name)
+	if err != nil {
+		return "", err
+	}
+	if state != nil {
+		publicPort, internalPort, replicas = state.Spec.PublicPort, state.Spec.InternalPort, state.Replicas
+	} else if service := s.GetService(ctx, name); service != nil {
+		publicPort, internalPort, replicas = service.PublicPort, service.InternalPort, service.Replicas
+	} else {
+		return "", errServiceNotFound(name)
+	}
+
+	switch field {
+	case "public_port":
+		if publicPort == 0 {
+			return "", fmt.Errorf("service %s has no public port", name)
+		}
+		return strconv.Itoa(publicPort), nil
+	case "internal_port":
+		return strconv.Itoa(internalPort), nil
+	case "replicas":
+		return strconv.Itoa(replicas), nil
+	}
+	return "", fmt.Errorf("unsupported field %q", field)
+}
+
+// checkEnvPlaceholders 检查环境变量引用的服务是否都存在，部署前调用以便尽早失败
+func (s *Service) checkEnvPlaceholders(ctx context.IContext, env map[string]string) error {
+	names, err := envServiceRefs(env)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, name := range names {
+		if _, err := s.serviceEnvField(ctx, name, "internal_port"); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("services referenced by environment not found: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// resolveEnv 创建容器时由 dockerclient 调用：先替换占位符，再将 secret:// 引用替换为密钥值
+// 占位符先于密钥解析，密钥明文中的 ${...} 不会被当作占位符
+func (s *Service) resolveEnv(ctx context.IContext, env map[string]string) (map[string]string, error) {
+	expanded, err := expandEnvPlaceholders(env, func(service, field string) (string, error) {
+		return s.serviceEnvField(ctx, service, field)
+	}, advertisedHostIP)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolveSecrets(ctx, expanded)
+}
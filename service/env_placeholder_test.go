@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestEnvServiceRefs 测试收集环境变量中的跨服务引用
+func TestEnvServiceRefs(t *testing.T) {
+	refs, err := envServiceRefs(map[string]string{
+		"REDIS_ADDR":  "${host_ip}:${service:redis.public_port}",
+		"REDIS_PORT":  "${service:redis.internal_port}",
+		"CACHE_ADDR":  "${host_ip}:${service:staging.cache.public_port}",
+		"LOG_LEVEL":   "info",
+		"SHELL_STYLE": "${HOME}",
+	})
+	if err != nil {
+		t.Fatalf("envServiceRefs 返回错误: %v", err)
+	}
+	want := []string{"redis", "staging.cache"}
+	if len(refs) != len(want) || refs[0] != want[0] || refs[1] != want[1] {
+		t.Errorf("envServiceRefs = %v, want %v", refs, want)
+	}
+
+	if _, err := envServiceRefs(map[string]string{"ADDR": "${service:redis.image}"}); err == nil {
+		t.Error("不支持的字段应返回错误")
+	}
+}
+
+// TestExpandEnvPlaceholders 测试替换环境变量占位符
+func TestExpandEnvPlaceholders(t *testing.T) {
+	ports := map[string]string{"redis": "30001", "staging.cache": "30002"}
+	lookup := func(service, field string) (string, error) {
+		if port, ok := ports[service]; ok && field == "public_port" {
+			return port, nil
+		}
+		return "", fmt.Errorf("service %s not found", service)
+	}
+	hostIP := func() (string, error) { return "10.0.0.5", nil }
+
+	env := map[string]string{
+		"REDIS_ADDR": "${host_ip}:${service:redis.public_port}",
+		"CACHE_ADDR": "${service:staging.cache.public_port}",
+		"HOME_DIR":   "${HOME}",
+	}
+	result, err := expandEnvPlaceholders(env, lookup, hostIP)
+	if err != nil {
+		t.Fatalf("expandEnvPlaceholders 返回错误: %v", err)
+	}
+	if result["REDIS_ADDR"] != "10.0.0.5:30001" || result["CACHE_ADDR"] != "30002" || result["HOME_DIR"] != "${HOME}" {
+		t.Errorf("expandEnvPlaceholders = %v", result)
+	}
+	if env["REDIS_ADDR"] != "${host_ip}:${service:redis.public_port}" {
+		t.Error("不应修改传入的环境变量")
+	}
+
+	if _, err := expandEnvPlaceholders(map[string]string{"DB": "${service:postgres.public_port}"}, lookup, hostIP); err == nil {
+		t.Error("引用不存在的服务应返回错误")
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestEffectiveEnvironment 测试副本环境变量的来源标注、密钥引用和与当前配置的差异
+func TestEffectiveEnvironment(t *testing.T) {
+	configured := map[string]configuredEnv{
+		"LOG_LEVEL":   {"debug", models.EnvSourceEnvFile},
+		"DB_HOST":     {"${host_ip}", models.EnvSourceEnvironment},
+		"DB_PASSWORD": {"secret://db_password", models.EnvSourceEnvironment},
+		"FEATURE_X":   {"on", models.EnvSourceEnvironment},
+	}
+	expected := map[string]string{"LOG_LEVEL": "info", "DB_HOST": "10.0.0.5", "FEATURE_X": "on"}
+	replicaEnv := []string{"PATH=/usr/bin", "LOG_LEVEL=debug", "DB_HOST=10.0.0.5", "DB_PASSWORD=hunter2"}
+
+	secrets := secretEnvRefs(nil, map[string]string{"DB_PASSWORD": "secret://db_password", "FEATURE_X": "on"})
+	got := effectiveEnvironment(replicaEnv, true, secrets, configured, expected)
+	want := []models.EnvVariable{
+		{Name: "DB_HOST", Value: "10.0.0.5", Source: models.EnvSourceEnvironment},
+		{Name: "DB_PASSWORD", Value: "secret://db_password", Source: models.EnvSourceEnvironment},
+		{Name: "FEATURE_X", Source: models.EnvSourceEnvironment, Differs: true, Expected: "on"},
+		{Name: "LOG_LEVEL", Value: "debug", Source: models.EnvSourceEnvFile, Differs: true, Expected: "info"},
+		{Name: "PATH", Value: "/usr/bin", Source: models.EnvSourceImage},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("effectiveEnvironment() = %+v, 期望 %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("第 %d 个变量 = %+v, 期望 %+v", i, got[i], want[i])
+		}
+	}
+
+	// 没有副本时只返回配置中的变量，密钥仍为引用
+	got = effectiveEnvironment(nil, false, secrets, configured, expected)
+	if len(got) != 4 || got[0].Value != "10.0.0.5" || got[1].Value != "secret://db_password" || got[2].Differs {
+		t.Errorf("没有副本时 = %+v", got)
+	}
+}
+
+// TestEffectiveEnvironmentMasking 测试按创建容器时记录的密钥来源隐藏明文，以及敏感名称变量的脱敏
+func TestEffectiveEnvironmentMasking(t *testing.T) {
+	// 创建副本时 API_KEY、DSN、DB_PASSWORD 来自密钥，之后配置改为明文或引用其他密钥
+	recorded := map[string]string{"API_KEY": "secret://api_key", "DSN": "secret://dsn", "DB_PASSWORD": "secret://db_old"}
+	configured := map[string]configuredEnv{
+		"API_KEY":       {"plain-key", models.EnvSourceEnvironment},
+		"DB_PASSWORD":   {"secret://db_password", models.EnvSourceEnvironment},
+		"SERVICE_TOKEN": {"t0ken", models.EnvSourceEnvFile},
+	}
+	current := map[string]string{"API_KEY": "plain-key", "DB_PASSWORD": "secret://db_password", "SERVICE_TOKEN": "t0ken"}
+	expected := map[string]string{"API_KEY": "plain-key", "SERVICE_TOKEN": "t0ken"}
+	replicaEnv := []string{"API_KEY=k-123", "DSN=postgres://app:pw@db/app", "DB_PASSWORD=hunter2", "MYSQL_ROOT_PASSWORD=root", "LANG=C"}
+
+	got := effectiveEnvironment(replicaEnv, true, secretEnvRefs(recorded, current), configured, expected)
+	want := []models.EnvVariable{
+		{Name: "API_KEY", Value: "secret://api_key", Source: models.EnvSourceEnvironment, Differs: true, Expected: maskedEnvValue},
+		{Name: "DB_PASSWORD", Value: "secret://db_old", Source: models.EnvSourceEnvironment, Differs: true, Expected: "secret://db_password"},
+		{Name: "DSN", Value: "secret://dsn", Source: models.EnvSourceImage},
+		{Name: "LANG", Value: "C", Source: models.EnvSourceImage},
+		{Name: "MYSQL_ROOT_PASSWORD", Value: maskedEnvValue, Source: models.EnvSourceImage},
+		{Name: "SERVICE_TOKEN", Source: models.EnvSourceEnvFile, Differs: true, Expected: maskedEnvValue},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("effectiveEnvironment() = %+v, 期望 %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("第 %d 个变量 = %+v, 期望 %+v", i, got[i], want[i])
+		}
+	}
+}
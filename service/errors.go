@@ -0,0 +1,8 @@
+package service
+
+import "github.com/aichy126/onedock/utils"
+
+// errServiceNotFound 服务不存在
+func errServiceNotFound(name string) error {
+	return utils.NewError(utils.CodeServiceNotFound, "service %s not found", name)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+const (
+	defaultEventRetentionDays = 7   // 事件默认保留天数
+	defaultCrashLoopThreshold = 3   // 时间窗口内副本崩溃达到该次数视为崩溃循环
+	defaultCrashLoopWindow    = 600 // 崩溃循环检测窗口（秒）
+	defaultEventLimit         = 50  // 默认返回条数
+	defaultServiceEventLimit  = 20  // 单个服务的最近事件默认返回条数
+	maxEventLimit             = 500 // 单次查询最多返回条数
+)
+
+// recordEvent 记录一条服务生命周期事件，清理超过 events.retention_days 的事件，并推送到实时事件流和订阅的通知方式；写入失败只记录日志
+func (s *Service) recordEvent(ctx context.IContext, name, eventType, message string) {
+	event := &store.Event{
+		Service: name,
+		Type:    eventType,
+		Message: message,
+		Actor:   actorFromContext(ctx),
+	}
+	retention := time.Duration(utils.ConfGetIntDefault("events.retention_days", defaultEventRetentionDays)) * 24 * time.Hour
+	if err := s.store.AddEvent(event, retention); err != nil {
+		log.Error("Event", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Type", eventType), log.Any("Message", "记录事件失败"))
+	}
+
+	result := toEvent(event)
+	s.events.publish(result)
+	s.notifyEvent(result)
+}
+
+// toEvent 将存储中的事件转换为 API 模型
+func toEvent(event *store.Event) *models.Event {
+	return &models.Event{
+		ID:        event.ID,
+		Service:   event.Service,
+		Type:      event.Type,
+		Message:   event.Message,
+		Actor:     event.Actor,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// recordReplicaCrash 记录副本崩溃，时间窗口内崩溃次数达到 events.crash_loop_threshold 时再记录一条崩溃循环事件
+func (s *Service) recordReplicaCrash(ctx context.IContext, name, message string) {
+	s.recordEvent(ctx, name, models.EventReplicaCrashed, message)
+
+	threshold := utils.ConfGetIntDefault("events.crash_loop_threshold", defaultCrashLoopThreshold)
+	if threshold <= 0 {
+		return
+	}
+	window := time.Duration(utils.ConfGetIntDefault("events.crash_loop_window", defaultCrashLoopWindow)) * time.Second
+	_, crashes, err := s.store.ListEvents(store.EventQuery{
+		Service: name,
+		Type:    models.EventReplicaCrashed,
+		Since:   time.Now().Add(-window),
+		Limit:   1,
+	})
+	if err != nil {
+		log.Error("Event", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "统计副本崩溃次数失败"))
+		return
+	}
+	// 只在达到阈值时记录一次，窗口内后续的崩溃不重复记录
+	if crashes == int64(threshold) {
+		s.recordEvent(ctx, name, models.EventCrashLoop, fmt.Sprintf("replicas crashed %d times in the last %s", crashes, window))
+	}
+}
+
+// ListEvents 按条件查询服务生命周期事件
+func (s *Service) ListEvents(ctx context.IContext, query *models.EventQuery) (*models.EventList, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultEventLimit
+	}
+	if limit > maxEventLimit {
+		limit = maxEventLimit
+	}
+
+	events, total, err := s.store.ListEvents(store.EventQuery{
+		Service: query.Service,
+		Type:    query.Type,
+		Types:   query.Types,
+		Since:   query.Since,
+		Until:   query.Until,
+		Limit:   limit,
+		Offset:  query.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.EventList{Total: total, Events: make([]*models.Event, 0, len(events))}
+	for _, event := range events {
+		result.Events = append(result.Events, toEvent(event))
+	}
+	return result, nil
+}
+
+// ListServiceEvents 获取单个服务最近的事件，按时间倒序，types 为空时返回全部类型
+// 服务已删除时仍返回保留期内的事件
+func (s *Service) ListServiceEvents(ctx context.IContext, name string, types []string, limit int) (*models.EventList, error) {
+	if limit <= 0 {
+		limit = defaultServiceEventLimit
+	}
+	return s.ListEvents(ctx, &models.EventQuery{Service: name, Types: types, Limit: limit})
+}
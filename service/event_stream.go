@@ -0,0 +1,94 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+)
+
+// eventStreamBuffer 每个订阅者的事件缓冲，客户端读取过慢时丢弃新事件，不阻塞事件的产生方
+const eventStreamBuffer = 64
+
+// eventSubscriber 实时事件的订阅者
+type eventSubscriber struct {
+	service string   // 为空表示全部服务
+	types   []string // 为空表示全部事件类型
+	events  chan *models.Event
+}
+
+// matches 订阅者是否关注该事件
+func (sub *eventSubscriber) matches(event *models.Event) bool {
+	return (sub.service == "" || sub.service == event.Service) && matchAny(sub.types, event.Type)
+}
+
+// eventHub 将事件推送给实时事件流的订阅者
+type eventHub struct {
+	mutex       sync.Mutex
+	subscribers map[*eventSubscriber]struct{}
+}
+
+// subscribe 添加订阅者
+func (h *eventHub) subscribe(service string, types []string) *eventSubscriber {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.subscribers == nil {
+		h.subscribers = make(map[*eventSubscriber]struct{})
+	}
+	sub := &eventSubscriber{service: service, types: types, events: make(chan *models.Event, eventStreamBuffer)}
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// unsubscribe 移除订阅者并关闭其通道
+func (h *eventHub) unsubscribe(sub *eventSubscriber) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// publish 推送事件给关注它的订阅者，缓冲已满的订阅者丢弃该事件
+func (h *eventHub) publish(event *models.Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for sub := range h.subscribers {
+		if !sub.matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			log.Warn("Event", log.Any("ServiceName", event.Service), log.Any("Type", event.Type), log.Any("Message", "事件流客户端读取过慢，丢弃事件"))
+		}
+	}
+}
+
+// publishProxyEvent 推送端口代理事件，这类事件只实时推送，不保存也不发送通知
+func (s *Service) publishProxyEvent(name, eventType, message string) {
+	s.events.publish(&models.Event{
+		Service:   name,
+		Type:      eventType,
+		Message:   message,
+		Actor:     "system",
+		CreatedAt: time.Now(),
+	})
+}
+
+// StreamEvents 订阅实时事件，service 和 types 为空时不过滤
+// 通道在 ctx 取消或 CloseStreams 后关闭
+func (s *Service) StreamEvents(ctx context.IContext, service string, types []string) <-chan *models.Event {
+	sub := s.events.subscribe(service, types)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.streams:
+		}
+		s.events.unsubscribe(sub)
+	}()
+	return sub.events
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestEventHub 测试按服务和事件类型推送给订阅者
+func TestEventHub(t *testing.T) {
+	var hub eventHub
+	all := hub.subscribe("", nil)
+	web := hub.subscribe("nginx-web", []string{models.EventDeployed, models.EventProxyStarted})
+
+	hub.publish(&models.Event{Service: "nginx-web", Type: models.EventDeployed})
+	hub.publish(&models.Event{Service: "nginx-web", Type: models.EventScaled})
+	hub.publish(&models.Event{Service: "api", Type: models.EventProxyStarted})
+
+	if got := len(all.events); got != 3 {
+		t.Errorf("未过滤的订阅者收到 %d 个事件, 期望 3", got)
+	}
+	if got := len(web.events); got != 1 {
+		t.Fatalf("过滤的订阅者收到 %d 个事件, 期望 1", got)
+	}
+	if event := <-web.events; event.Type != models.EventDeployed {
+		t.Errorf("收到 %s, 期望 %s", event.Type, models.EventDeployed)
+	}
+
+	hub.unsubscribe(web)
+	hub.unsubscribe(web) // 重复取消订阅不应 panic
+	if _, ok := <-web.events; ok {
+		t.Error("取消订阅后通道应关闭")
+	}
+	hub.publish(&models.Event{Service: "nginx-web", Type: models.EventDeployed})
+	if got := len(all.events); got != 4 {
+		t.Errorf("其他订阅者收到 %d 个事件, 期望 4", got)
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/jinzhu/copier"
+	"gopkg.in/yaml.v2"
+)
+
+// composeFile docker-compose 文件
+type composeFile struct {
+	Services map[string]*composeService `yaml:"services"`
+}
+
+// composeService docker-compose 服务定义，OneDock 特有的代理配置放在 x-onedock 扩展字段中
+type composeService struct {
+	Image       string            `yaml:"image"`
+	Entrypoint  []string          `yaml:"entrypoint,omitempty"`
+	Command     []string          `yaml:"command,omitempty"`
+	WorkingDir  string            `yaml:"working_dir,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	EnvFile     []string          `yaml:"env_file,omitempty"`
+	Volumes     []string          `yaml:"volumes,omitempty"`
+	Ports       []string          `yaml:"ports,omitempty"`
+	Restart     string            `yaml:"restart,omitempty"`
+	Deploy      *composeDeploy    `yaml:"deploy,omitempty"`
+	OneDock     *composeOneDock   `yaml:"x-onedock,omitempty"`
+}
+
+type composeDeploy struct {
+	Replicas  int               `yaml:"replicas"`
+	Resources *composeResources `yaml:"resources,omitempty"`
+}
+
+type composeResources struct {
+	Limits map[string]string `yaml:"limits"`
+}
+
+// composeOneDock OneDock 扩展配置，docker compose 会忽略 x- 开头的字段
+type composeOneDock struct {
+	PublicPort   int                  `yaml:"public_port"`
+	BindAddress  string               `yaml:"bind_address,omitempty"`
+	ProxyWorkers int                  `yaml:"proxy_workers,omitempty"`
+	RequestRules []models.RequestRule `yaml:"request_rules,omitempty"`
+	Mirror       *models.MirrorConfig `yaml:"mirror,omitempty"`
+
+	InitContainers []models.InitContainer `yaml:"init_containers,omitempty"`
+	Sidecars       []models.Sidecar       `yaml:"sidecars,omitempty"`
+}
+
+// ExportService 导出服务的生效配置（OneDock 服务配置格式，可直接用于部署接口）
+// 优先使用期望状态；没有期望状态时从容器标签还原，此时环境变量、卷挂载等配置无法导出
+func (s *Service) ExportService(ctx context.IContext, name string) (*models.ServiceRequest, error) {
+	state, err := s.loadDesiredState(name)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil {
+		spec := state.Spec
+		return &spec, nil
+	}
+
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, errServiceNotFound(name)
+	}
+	config, err := s.dockerClient.ExtractServiceFromContainer(containers[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract service config: %w", err)
+	}
+
+	spec := &models.ServiceRequest{}
+	if err := copier.Copy(spec, config); err != nil {
+		return nil, fmt.Errorf("failed to copy service config: %w", err)
+	}
+	spec.Replicas = service.Replicas
+	log.Warn("Docker", log.Any("ServiceName", name), log.Any("Message", "服务没有期望状态，导出配置不包含环境变量、卷挂载等信息"))
+	return spec, nil
+}
+
+// ExportSpec 导出服务配置供接口返回，名称像敏感信息且不是密钥引用的环境变量脱敏
+func (s *Service) ExportSpec(ctx context.IContext, name string) (*models.ServiceRequest, error) {
+	spec, err := s.ExportService(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return maskExportSpec(spec), nil
+}
+
+// ExportCompose 将服务的生效配置渲染为 docker-compose YAML，敏感环境变量脱敏
+func (s *Service) ExportCompose(ctx context.IContext, name string) ([]byte, error) {
+	spec, err := s.ExportService(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return renderCompose(maskExportSpec(spec))
+}
+
+// maskExportSpec 复制服务配置，主容器、初始化容器和边车中名称像敏感信息的明文环境变量替换为 ******；
+// 密钥引用（secret://）本身不含明文，原样保留。只读令牌也能导出配置，不能借此读到密码
+func maskExportSpec(spec *models.ServiceRequest) *models.ServiceRequest {
+	masked := *spec
+	masked.Environment = maskEnvironment(spec.Environment)
+	if spec.InitContainers != nil {
+		masked.InitContainers = make([]models.InitContainer, len(spec.InitContainers))
+		for i, init := range spec.InitContainers {
+			init.Environment = maskEnvironment(init.Environment)
+			masked.InitContainers[i] = init
+		}
+	}
+	if spec.Sidecars != nil {
+		masked.Sidecars = make([]models.Sidecar, len(spec.Sidecars))
+		for i, sidecar := range spec.Sidecars {
+			sidecar.Environment = maskEnvironment(sidecar.Environment)
+			masked.Sidecars[i] = sidecar
+		}
+	}
+	return &masked
+}
+
+// maskEnvironment 复制环境变量，名称像敏感信息的明文值脱敏
+func maskEnvironment(env map[string]string) map[string]string {
+	if env == nil {
+		return nil
+	}
+	masked := make(map[string]string, len(env))
+	for key, value := range env {
+		if !strings.HasPrefix(value, models.SecretRefPrefix) {
+			value = displayEnvValue(key, value, nil)
+		}
+		masked[key] = value
+	}
+	return masked
+}
+
+// renderCompose 将服务配置渲染为 docker-compose YAML
+func renderCompose(spec *models.ServiceRequest) ([]byte, error) {
+	service := &composeService{
+		Image:       fmt.Sprintf("%s:%s", spec.Image, spec.Tag),
+		Entrypoint:  spec.Entrypoint,
+		Command:     spec.Command,
+		WorkingDir:  spec.WorkingDir,
+		Environment: spec.Environment,
+		Restart:     "always",
+		Deploy:      &composeDeploy{Replicas: spec.Replicas},
+		OneDock: &composeOneDock{
+			PublicPort:   spec.PublicPort,
+			BindAddress:  spec.BindAddress,
+			ProxyWorkers: spec.ProxyWorkers,
+			RequestRules: spec.RequestRules,
+			Mirror:       spec.Mirror,
+
+			InitContainers: spec.InitContainers,
+			Sidecars:       spec.Sidecars,
+		},
+	}
+	if spec.MemoryLimit > 0 {
+		service.Deploy.Resources = &composeResources{Limits: map[string]string{"memory": fmt.Sprintf("%dM", spec.MemoryLimit)}}
+	}
+	if spec.EnvFile != "" {
+		service.EnvFile = []string{spec.EnvFile}
+	}
+	for _, volume := range spec.Volumes {
+		bind := fmt.Sprintf("%s:%s", volume.Source, volume.Destination)
+		if volume.ReadOnly {
+			bind += ":ro"
+		}
+		service.Volumes = append(service.Volumes, bind)
+	}
+	// 多副本时由 OneDock 代理负载均衡，compose 中只声明容器端口
+	if spec.InternalPort > 0 {
+		if spec.Replicas <= 1 && spec.PublicPort > 0 {
+			service.Ports = []string{fmt.Sprintf("%d:%d", spec.PublicPort, spec.InternalPort)}
+		} else {
+			service.Ports = []string{fmt.Sprintf("%d", spec.InternalPort)}
+		}
+	}
+
+	out, err := yaml.Marshal(&composeFile{Services: map[string]*composeService{spec.Name: service}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render compose file: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestRenderCompose 测试服务配置到 docker-compose YAML 的转换：按副本数决定端口映射、只读卷、内存上限
+func TestRenderCompose(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *models.ServiceRequest
+		want string
+	}{
+		{
+			name: "单副本映射公共端口",
+			spec: &models.ServiceRequest{Name: "nginx-web", Image: "nginx", Tag: "1.25", InternalPort: 80, PublicPort: 9203, Replicas: 1,
+				MemoryLimit: 512, EnvFile: "/etc/onedock/web.env",
+				Volumes: []models.VolumeMount{{Source: "/data/html", Destination: "/usr/share/nginx/html", ReadOnly: true}, {Source: "/data/logs", Destination: "/var/log/nginx"}}},
+			want: `services:
+  nginx-web:
+    image: nginx:1.25
+    env_file:
+    - /etc/onedock/web.env
+    volumes:
+    - /data/html:/usr/share/nginx/html:ro
+    - /data/logs:/var/log/nginx
+    ports:
+    - 9203:80
+    restart: always
+    deploy:
+      replicas: 1
+      resources:
+        limits:
+          memory: 512M
+    x-onedock:
+      public_port: 9203
+`,
+		},
+		{
+			name: "多副本只声明容器端口",
+			spec: &models.ServiceRequest{Name: "api", Image: "myapp", Tag: "2.0", InternalPort: 8080, PublicPort: 9210, Replicas: 3,
+				Command: []string{"./server", "--port", "8080"}, Environment: map[string]string{"LOG_LEVEL": "info"}},
+			want: `services:
+  api:
+    image: myapp:2.0
+    command:
+    - ./server
+    - --port
+    - "8080"
+    environment:
+      LOG_LEVEL: info
+    ports:
+    - "8080"
+    restart: always
+    deploy:
+      replicas: 3
+    x-onedock:
+      public_port: 9210
+`,
+		},
+		{
+			name: "没有内部端口时不声明端口",
+			spec: &models.ServiceRequest{Name: "worker", Image: "myapp", Tag: "2.0", Replicas: 0},
+			want: `services:
+  worker:
+    image: myapp:2.0
+    restart: always
+    deploy:
+      replicas: 0
+    x-onedock:
+      public_port: 0
+`,
+		},
+	}
+	for _, tt := range tests {
+		out, err := renderCompose(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: renderCompose() error = %v", tt.name, err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("%s:\n得到\n%s\n期望\n%s", tt.name, out, tt.want)
+		}
+	}
+}
+
+// TestMaskExportSpec 测试导出配置中敏感明文环境变量脱敏，密钥引用和普通变量原样保留，且不修改原配置
+func TestMaskExportSpec(t *testing.T) {
+	spec := &models.ServiceRequest{
+		Name: "api",
+		Environment: map[string]string{
+			"DB_PASSWORD": "hunter2",
+			"API_TOKEN":   "secret://api_token",
+			"LOG_LEVEL":   "info",
+			"AUTH_SECRET": "",
+		},
+		Sidecars:       []models.Sidecar{{Name: "agent", Environment: map[string]string{"AGENT_API_KEY": "k-123", "AGENT_PORT": "9000"}}},
+		InitContainers: []models.InitContainer{{Name: "migrate", Environment: map[string]string{"DB_PASSWORD": "secret://db_password"}}},
+	}
+
+	masked := maskExportSpec(spec)
+	want := map[string]string{"DB_PASSWORD": maskedEnvValue, "API_TOKEN": "secret://api_token", "LOG_LEVEL": "info", "AUTH_SECRET": ""}
+	for key, value := range want {
+		if masked.Environment[key] != value {
+			t.Errorf("%s = %q, 期望 %q", key, masked.Environment[key], value)
+		}
+	}
+	if env := masked.Sidecars[0].Environment; env["AGENT_API_KEY"] != maskedEnvValue || env["AGENT_PORT"] != "9000" {
+		t.Errorf("边车环境变量 = %v, 期望 AGENT_API_KEY 脱敏", env)
+	}
+	if env := masked.InitContainers[0].Environment; env["DB_PASSWORD"] != "secret://db_password" {
+		t.Errorf("初始化容器环境变量 = %v, 期望保留密钥引用", env)
+	}
+	if spec.Environment["DB_PASSWORD"] != "hunter2" || spec.Sidecars[0].Environment["AGENT_API_KEY"] != "k-123" {
+		t.Error("脱敏不应修改原配置")
+	}
+
+	out, err := renderCompose(masked)
+	if err != nil {
+		t.Fatalf("renderCompose() error = %v", err)
+	}
+	for _, secret := range []string{"hunter2", "k-123"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("compose 输出包含明文 %q:\n%s", secret, out)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultGitOpsInterval = 60 // 默认同步间隔（秒）
+	defaultGitOpsBranch   = "main"
+	defaultGitOpsWorkdir  = "./gitops"
+	gitCommandTimeout     = 2 * time.Minute
+	gitOpsActor           = "gitops"
+)
+
+// gitopsConfig gitops 配置
+type gitopsConfig struct {
+	Repo    string // 服务配置仓库地址，为空表示未启用
+	Branch  string // 同步的分支
+	Path    string // 服务配置所在目录（相对仓库根目录）
+	Workdir string // 本地克隆目录
+	Prune   bool   // 是否删除仓库中已移除的服务
+}
+
+// gitopsSyncer GitOps 同步状态
+type gitopsSyncer struct {
+	mutex       sync.Mutex // 同一时间只运行一次同步（定时与手动触发互斥）
+	statusMutex sync.RWMutex
+	status      *models.GitOpsStatus // 最近一次同步（不含预演）的结果
+}
+
+// gitopsSpec 仓库中的一份服务配置
+type gitopsSpec struct {
+	File string
+	Spec models.ServiceRequest
+}
+
+// loadGitOpsConfig 读取 gitops 配置
+func loadGitOpsConfig() gitopsConfig {
+	cfg := gitopsConfig{
+		Repo:    utils.ConfGetString("gitops.repo"),
+		Branch:  utils.ConfGetString("gitops.branch"),
+		Path:    utils.ConfGetString("gitops.path"),
+		Workdir: utils.ConfGetString("gitops.workdir"),
+		Prune:   utils.ConfGetbool("gitops.prune"),
+	}
+	if cfg.Branch == "" {
+		cfg.Branch = defaultGitOpsBranch
+	}
+	if cfg.Workdir == "" {
+		cfg.Workdir = defaultGitOpsWorkdir
+	}
+	return cfg
+}
+
+// startGitOps 启动 GitOps 同步循环，未配置 gitops.repo 或 gitops.interval 为 0 时不自动同步
+func (s *Service) startGitOps() {
+	cfg := loadGitOpsConfig()
+	if cfg.Repo == "" {
+		return
+	}
+	interval := utils.ConfGetIntDefault("gitops.interval", defaultGitOpsInterval)
+	if interval <= 0 {
+		log.Info("GitOps", log.Any("Repo", cfg.Repo), log.Any("Message", "GitOps 自动同步已禁用，可通过接口手动同步"))
+		return
+	}
+
+	go func() {
+		s.runGitOpsSync()
+		ticker := time.NewTicker(time.Duration(interval) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopping:
+				return
+			case <-ticker.C:
+				s.runGitOpsSync()
+			}
+		}
+	}()
+	log.Info("GitOps", log.Any("Repo", cfg.Repo), log.Any("Branch", cfg.Branch), log.Any("Interval", interval), log.Any("Message", "GitOps 同步已启动"))
+}
+
+// runGitOpsSync 执行一次定时同步
+func (s *Service) runGitOpsSync() {
+	ctx := context.Background()
+	ctx.Set(models.ContextKeyActor, gitOpsActor)
+	if _, err := s.SyncGitOps(ctx, false); err != nil {
+		log.Error("GitOps", log.Any("Error", err), log.Any("Message", "GitOps 同步失败"))
+	}
+}
+
+// gitCommand 在 dir 中执行 git 命令，返回去掉首尾空白的输出
+func gitCommand(ctx context.IContext, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// fetchGitOpsRepo 克隆或更新本地仓库到分支最新提交，返回提交哈希
+func fetchGitOpsRepo(ctx context.IContext, cfg gitopsConfig) (string, error) {
+	gitCtx, cancel := ctx.WithTimeout(gitCommandTimeout)
+	defer cancel()
+
+	if _, err := os.Stat(filepath.Join(cfg.Workdir, ".git")); err != nil {
+		if _, err := gitCommand(gitCtx, "", "clone", "--depth", "1", "--branch", cfg.Branch, cfg.Repo, cfg.Workdir); err != nil {
+			return "", err
+		}
+	} else {
+		if _, err := gitCommand(gitCtx, cfg.Workdir, "remote", "set-url", "origin", cfg.Repo); err != nil {
+			return "", err
+		}
+		if _, err := gitCommand(gitCtx, cfg.Workdir, "fetch", "--depth", "1", "origin", cfg.Branch); err != nil {
+			return "", err
+		}
+		if _, err := gitCommand(gitCtx, cfg.Workdir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return "", err
+		}
+	}
+	return gitCommand(gitCtx, cfg.Workdir, "rev-parse", "HEAD")
+}
+
+// jsonCompatible 将 YAML 解析出的 map[interface{}]interface{} 转换为可 JSON 序列化的 map[string]interface{}
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	}
+	return value
+}
+
+// parseGitOpsSpec 解析一份 JSON 或 YAML 服务配置，字段与部署接口相同，未知字段视为错误
+func parseGitOpsSpec(file string, data []byte) (*models.ServiceRequest, error) {
+	if ext := filepath.Ext(file); ext == ".yaml" || ext == ".yml" {
+		var value interface{}
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		converted, err := json.Marshal(jsonCompatible(value))
+		if err != nil {
+			return nil, err
+		}
+		data = converted
+	}
+
+	spec := &models.ServiceRequest{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(spec); err != nil {
+		return nil, err
+	}
+	if spec.DryRun {
+		return nil, fmt.Errorf("dry_run is not supported in gitops specs")
+	}
+	return spec, nil
+}
+
+// loadGitOpsSpecs 读取目录下全部 .json、.yaml、.yml 服务配置（含子目录，跳过 . 开头的目录），每个文件一个服务
+// 任一文件无效时返回错误，避免按不完整的配置删除服务
+func loadGitOpsSpecs(root string) ([]gitopsSpec, error) {
+	var specs []gitopsSpec
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch filepath.Ext(path) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+
+		file, _ := filepath.Rel(root, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		spec, err := parseGitOpsSpec(file, data)
+		if err != nil {
+			return fmt.Errorf("invalid service spec %s: %w", file, err)
+		}
+		specs = append(specs, gitopsSpec{File: filepath.ToSlash(file), Spec: *spec})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no service specs found in %s", root)
+	}
+
+	reqs := make([]models.ServiceRequest, len(specs))
+	for i := range specs {
+		reqs[i] = specs[i].Spec
+	}
+	errs := validateBatch(reqs)
+	for i := range specs {
+		if err := errs[i]; err != nil {
+			return nil, fmt.Errorf("invalid service spec %s: %w", specs[i].File, err)
+		}
+		specs[i].Spec = reqs[i]
+	}
+	return specs, nil
+}
+
+// SyncGitOps 拉取仓库并让运行中的服务与其中的配置一致：新增的服务部署，配置变化的滚动更新，副本数变化的扩缩容
+// 仓库中已删除的服务在开启 gitops.prune 时删除；dryRun 为 true 时只返回将要执行的操作
+func (s *Service) SyncGitOps(ctx context.IContext, dryRun bool) (*models.GitOpsStatus, error) {
+	cfg := loadGitOpsConfig()
+	if cfg.Repo == "" {
+		return nil, fmt.Errorf("gitops is not configured: set gitops.repo")
+	}
+
+	s.gitops.mutex.Lock()
+	defer s.gitops.mutex.Unlock()
+
+	now := time.Now()
+	status := &models.GitOpsStatus{
+		Enabled:    true,
+		Repo:       cfg.Repo,
+		Branch:     cfg.Branch,
+		Path:       cfg.Path,
+		Prune:      cfg.Prune,
+		DryRun:     dryRun,
+		LastSyncAt: &now,
+		Services:   make([]*models.GitOpsServiceResult, 0),
+	}
+	err := s.syncGitOps(ctx, cfg, status)
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	if !dryRun {
+		s.gitops.statusMutex.Lock()
+		s.gitops.status = status
+		s.gitops.statusMutex.Unlock()
+	}
+	return status, err
+}
+
+// syncGitOps 执行同步，结果写入 status
+func (s *Service) syncGitOps(ctx context.IContext, cfg gitopsConfig, status *models.GitOpsStatus) error {
+	commit, err := fetchGitOpsRepo(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	status.Commit = commit
+
+	specs, err := loadGitOpsSpecs(filepath.Join(cfg.Workdir, cfg.Path))
+	if err != nil {
+		return err
+	}
+	managed, err := s.store.ListGitOpsServices()
+	if err != nil {
+		return err
+	}
+
+	inRepo := make(map[string]bool, len(specs))
+	for i := range specs {
+		spec := &specs[i]
+		inRepo[spec.Spec.Name] = true
+		result := s.syncGitOpsService(ctx, spec, status.DryRun)
+		status.Services = append(status.Services, result)
+		if result.Error == "" && !status.DryRun {
+			if err := s.store.SaveGitOpsService(spec.Spec.Name, spec.File, commit); err != nil {
+				log.Error("GitOps", log.Any("Error", err), log.Any("ServiceName", spec.Spec.Name), log.Any("Message", "保存 GitOps 管理记录失败"))
+			}
+		}
+	}
+
+	for _, record := range managed {
+		if inRepo[record.Service] {
+			continue
+		}
+		if result := s.pruneGitOpsService(ctx, record.Service, record.File, cfg.Prune, status.DryRun); result != nil {
+			status.Services = append(status.Services, result)
+		}
+	}
+
+	for _, result := range status.Services {
+		if result.Action != models.GitOpsUnchanged || result.Error != "" {
+			log.Info("GitOps", log.Any("ServiceName", result.Service), log.Any("File", result.File), log.Any("Action", result.Action),
+				log.Any("Commit", commit), log.Any("DryRun", status.DryRun), log.Any("Error", result.Error), log.Any("Message", "GitOps 同步服务"))
+		}
+	}
+	return nil
+}
+
+// syncGitOpsService 让单个服务与仓库中的配置一致
+// 配置中不填 replicas 时不调整副本数，便于与自动扩缩容、定时扩缩容配合
+func (s *Service) syncGitOpsService(ctx context.IContext, spec *gitopsSpec, dryRun bool) *models.GitOpsServiceResult {
+	req := spec.Spec
+	result := &models.GitOpsServiceResult{Service: req.Name, File: spec.File}
+
+	existing := s.GetService(ctx, req.Name)
+	if existing == nil {
+		result.Action = models.GitOpsCreate
+		if !dryRun {
+			if _, err := s.DeployOrUpdateService(ctx, &req); err != nil {
+				result.Error = err.Error()
+			}
+		}
+		return result
+	}
+
+	if req.PublicPort > 0 && req.PublicPort != existing.PublicPort {
+		result.Action = models.GitOpsUpdate
+		result.Error = fmt.Sprintf("public port %d differs from the running port %d, delete the service to move it", req.PublicPort, existing.PublicPort)
+		return result
+	}
+	diff, err := s.DiffService(ctx, req.Name, &req)
+	if err != nil {
+		result.Action = models.GitOpsUpdate
+		result.Error = err.Error()
+		return result
+	}
+	result.Changes = diff.Changes
+
+	scale := req.Replicas > 0 && req.Replicas != existing.Replicas
+	if scale {
+		result.Changes = append(result.Changes, models.ConfigChange{Field: "replicas", Type: "changed", Old: existing.Replicas, New: req.Replicas})
+	}
+	switch {
+	case diff.Changed:
+		result.Action = models.GitOpsUpdate
+	case scale:
+		result.Action = models.GitOpsScale
+	default:
+		result.Action = models.GitOpsUnchanged
+	}
+	if dryRun {
+		return result
+	}
+
+	if diff.Changed {
+		if _, err := s.UpdateService(ctx, &req); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+	if scale {
+		if err := s.ScaleService(ctx, req.Name, req.Replicas); err != nil {
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
+
+// pruneGitOpsService 处理仓库中已删除的服务：开启 prune 时删除，否则标记为 orphaned 保留运行
+// 服务已被手动删除时只清除管理记录，返回 nil
+func (s *Service) pruneGitOpsService(ctx context.IContext, name, file string, prune, dryRun bool) *models.GitOpsServiceResult {
+	if s.GetService(ctx, name) == nil {
+		if !dryRun {
+			if err := s.store.DeleteGitOpsService(name); err != nil {
+				log.Error("GitOps", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "删除 GitOps 管理记录失败"))
+			}
+		}
+		return nil
+	}
+
+	result := &models.GitOpsServiceResult{Service: name, File: file, Action: models.GitOpsOrphaned}
+	if !prune {
+		return result
+	}
+	result.Action = models.GitOpsDelete
+	if dryRun {
+		return result
+	}
+	if err := s.DeleteService(ctx, name); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if err := s.store.DeleteGitOpsService(name); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// GetGitOpsStatus 获取 GitOps 配置及最近一次同步结果
+func (s *Service) GetGitOpsStatus(ctx context.IContext) *models.GitOpsStatus {
+	s.gitops.statusMutex.RLock()
+	defer s.gitops.statusMutex.RUnlock()
+	if s.gitops.status != nil {
+		return s.gitops.status
+	}
+
+	cfg := loadGitOpsConfig()
+	status := &models.GitOpsStatus{Enabled: cfg.Repo != "", Services: make([]*models.GitOpsServiceResult, 0)}
+	if status.Enabled {
+		status.Repo, status.Branch, status.Path, status.Prune = cfg.Repo, cfg.Branch, cfg.Path, cfg.Prune
+	}
+	return status
+}
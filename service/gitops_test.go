@@ -0,0 +1,81 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseGitOpsSpec 测试解析 YAML、JSON 服务配置
+func TestParseGitOpsSpec(t *testing.T) {
+	yamlSpec := `
+name: web
+namespace: staging
+image: nginx
+tag: "1.25"
+internal_port: 80
+environment:
+  LOG_LEVEL: info
+volumes:
+  - source: /data
+    destination: /usr/share/nginx/html
+`
+	spec, err := parseGitOpsSpec("web.yaml", []byte(yamlSpec))
+	if err != nil {
+		t.Fatalf("parseGitOpsSpec(yaml) = %v", err)
+	}
+	if spec.Name != "web" || spec.Tag != "1.25" || spec.Environment["LOG_LEVEL"] != "info" || len(spec.Volumes) != 1 {
+		t.Errorf("parseGitOpsSpec(yaml) = %+v", spec)
+	}
+
+	spec, err = parseGitOpsSpec("api.json", []byte(`{"name":"api","image":"app","tag":"1.0","internal_port":8080,"replicas":2}`))
+	if err != nil || spec.Replicas != 2 {
+		t.Errorf("parseGitOpsSpec(json) = %+v, %v", spec, err)
+	}
+
+	if _, err := parseGitOpsSpec("web.yaml", []byte("name: web\nimage: nginx\nreplica: 3\n")); err == nil {
+		t.Error("未知字段应返回错误")
+	}
+	if _, err := parseGitOpsSpec("web.json", []byte(`{"name":"web","dry_run":true}`)); err == nil {
+		t.Error("dry_run 应返回错误")
+	}
+}
+
+// TestLoadGitOpsSpecs 测试读取目录中的服务配置
+func TestLoadGitOpsSpecs(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("apps/web.yaml", "name: web\nnamespace: staging\nimage: nginx\ntag: \"1.25\"\ninternal_port: 80\n")
+	write("api.json", `{"name":"api","image":"app","tag":"1.0","internal_port":8080}`)
+	write("README.md", "# services")
+	write(".git/config.json", "not a spec")
+
+	specs, err := loadGitOpsSpecs(root)
+	if err != nil {
+		t.Fatalf("loadGitOpsSpecs = %v", err)
+	}
+	if len(specs) != 2 || specs[0].File != "api.json" || specs[1].File != "apps/web.yaml" {
+		t.Fatalf("loadGitOpsSpecs = %+v, 期望 api.json、apps/web.yaml", specs)
+	}
+	if specs[1].Spec.Name != "staging.web" {
+		t.Errorf("服务名称 = %q, 期望按命名空间补全为 staging.web", specs[1].Spec.Name)
+	}
+
+	write("web-copy.json", `{"name":"staging.web","image":"nginx","tag":"1.25","internal_port":80}`)
+	if _, err := loadGitOpsSpecs(root); err == nil || !strings.Contains(err.Error(), "web-copy.json") {
+		t.Errorf("服务名称重复时 = %v, 期望指出 web-copy.json", err)
+	}
+
+	if _, err := loadGitOpsSpecs(t.TempDir()); err == nil {
+		t.Error("目录中没有服务配置时应返回错误")
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/library/graphql"
+	"github.com/aichy126/onedock/models"
+)
+
+// topologyPort 副本的端口及其在端口代理中的后端统计
+type topologyPort struct {
+	PublicPort    int                       `json:"public_port"`
+	ContainerPort int                       `json:"container_port"`
+	InternalPort  int                       `json:"internal_port"`
+	Proxy         *models.ProxyBackendStats `json:"proxy"`
+}
+
+// QueryGraphQL 执行只读 GraphQL 查询，一次获取服务 → 副本 → 端口 → 代理统计
+// 限定服务范围的令牌只能查询范围内的服务
+func (s *Service) QueryGraphQL(ctx context.IContext, req *graphql.Request) *graphql.Response {
+	s.graphqlOnce.Do(func() {
+		s.graphql = s.topologySchema()
+	})
+	return s.graphql.Execute(ctx, req)
+}
+
+// topologySchema 服务拓扑的 GraphQL 模式，对象字段与 REST 接口返回的 JSON 字段同名
+//
+//	Query    { services(namespace, label, image, status, port): [Service], service(name): Service }
+//	Service  { ...models.Service, instances: [Instance], proxies: [ProxyStats] }
+//	Instance { ...models.ServiceInstanceInfo, ports: [Port] }
+//	Port     { public_port, container_port, internal_port, proxy: ProxyBackendStats }
+func (s *Service) topologySchema() *graphql.Schema {
+	proxyStats := graphql.ObjectOf(models.ProxyStats{})
+	port := graphql.ObjectOf(topologyPort{})
+	port.Name = "Port"
+	port.Fields["proxy"].Type = proxyStats.Fields["backends"].Type
+
+	instance := graphql.ObjectOf(models.ServiceInstanceInfo{})
+	instance.Name = "Instance"
+	instance.AddField("ports", &graphql.Field{Type: port, Resolve: func(ctx context.IContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+		return s.instancePorts(source.(models.ServiceInstanceInfo)), nil
+	}})
+
+	service := graphql.ObjectOf(models.Service{})
+	service.AddField("instances", &graphql.Field{Type: instance, Resolve: func(ctx context.IContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+		status, err := s.GetServiceStatus(ctx, source.(*models.Service).Name)
+		if err != nil {
+			return nil, err
+		}
+		return status.Instances, nil
+	}})
+	service.AddField("proxies", &graphql.Field{Type: proxyStats, Resolve: func(ctx context.IContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+		return s.PortManager.serviceProxies(source.(*models.Service).Name), nil
+	}})
+
+	query := graphql.NewObject("Query")
+	query.AddField("services", &graphql.Field{
+		Type:    service,
+		Args:    []string{"namespace", "label", "image", "status", "port"},
+		Resolve: s.resolveServices,
+	})
+	query.AddField("service", &graphql.Field{
+		Type: service,
+		Args: []string{"name"},
+		Resolve: func(ctx context.IContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+			name, err := graphql.StringArg(args, "name")
+			if err != nil {
+				return nil, err
+			}
+			if err := checkServiceScope(ctx, name); err != nil {
+				return nil, err
+			}
+			service := s.GetService(ctx, name)
+			if service == nil {
+				return nil, errServiceNotFound(name)
+			}
+			return s.AttachLabels([]*models.Service{service})[0], nil
+		},
+	})
+	return &graphql.Schema{Query: query}
+}
+
+// resolveServices 按与搜索接口相同的条件列出服务，跳过令牌范围外的服务
+func (s *Service) resolveServices(ctx context.IContext, source interface{}, args map[string]interface{}) (interface{}, error) {
+	query := &models.ServiceQuery{}
+	var status string
+	var err error
+	for name, value := range map[string]*string{"namespace": &query.Namespace, "label": &query.Selector, "image": &query.Image, "status": &status} {
+		if *value, err = graphql.StringArg(args, name); err != nil {
+			return nil, err
+		}
+	}
+	query.Status = models.ServiceStatus(status)
+	if query.Port, err = graphql.IntArg(args, "port"); err != nil {
+		return nil, err
+	}
+
+	services, err := s.SearchServices(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return ScopedServices(ctx, services), nil
+}
+
+// instancePorts 副本在服务各个端口代理中的端口和后端统计；服务没有端口代理时只返回副本本身的端口
+func (s *Service) instancePorts(instance models.ServiceInstanceInfo) []topologyPort {
+	proxies := s.PortManager.serviceProxies(instance.ServiceName)
+	if len(proxies) == 0 {
+		return []topologyPort{{
+			PublicPort:    instance.PublicPort,
+			ContainerPort: instance.ContainerPort,
+			InternalPort:  instance.InternalPort,
+		}}
+	}
+	ports := make([]topologyPort, 0, len(proxies))
+	for _, proxy := range proxies {
+		port := topologyPort{
+			PublicPort:    proxy.PublicPort,
+			ContainerPort: instance.ContainerPort,
+			InternalPort:  instance.InternalPort,
+		}
+		for i := range proxy.Backends {
+			if sameContainer(proxy.Backends[i].ContainerID, instance.ContainerID) {
+				port.Proxy = &proxy.Backends[i]
+				break
+			}
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// sameContainer 两个容器 ID 是否指向同一容器，兼容完整 ID 和短 ID
+func sameContainer(a, b string) bool {
+	return a != "" && b != "" && (strings.HasPrefix(a, b) || strings.HasPrefix(b, a))
+}
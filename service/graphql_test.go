@@ -0,0 +1,58 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/library/graphql"
+)
+
+// TestTopologySchema 测试服务拓扑模式的字段，@skip 跳过的字段只校验不执行
+func TestTopologySchema(t *testing.T) {
+	schema := (&Service{}).topologySchema()
+
+	resp := schema.Execute(context.Background(), &graphql.Request{Query: `{
+		services(namespace: "default", label: "team=payments", status: "running", port: 9303) @skip(if: true) {
+			name public_port labels created_at
+			request_rules { name path methods }
+			instances { container_name status container_port ports { public_port container_port proxy { container_id active requests errors } } }
+			proxies { public_port strategy requests_total backends { container_id connections } mirror { mirrored } }
+		}
+		service(name: "nginx-web") @skip(if: true) { __typename name }
+	}`})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Execute() errors = %v", resp.Errors[0].Message)
+	}
+
+	for query, want := range map[string]string{
+		`{ services { replicas { name } } }`:         "is a scalar",
+		`{ services { instances { ports } } }`:       "must have a selection of subfields",
+		`{ service(name: "a") { instances { x } } }`: `cannot query field "x" on type Instance`,
+		`{ services(name: "a") { name } }`:           `unknown argument "name"`,
+	} {
+		resp := schema.Execute(context.Background(), &graphql.Request{Query: query})
+		if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, want) {
+			t.Errorf("%s: errors = %v, 期望包含 %q", query, resp.Errors, want)
+		}
+	}
+}
+
+// TestSameContainer 测试完整 ID 与短 ID 的匹配
+func TestSameContainer(t *testing.T) {
+	full := "3f2a1b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f70"
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{full, full[:12], true},
+		{full[:12], full, true},
+		{full, "4f2a1b4c5d6e", false},
+		{"", full, false},
+	}
+	for _, tt := range tests {
+		if got := sameContainer(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameContainer(%q, %q) = %v, 期望 %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+)
+
+// readinessTimeout 就绪检查的总超时，避免 Docker 或 Redis 无响应时探针挂起
+const readinessTimeout = 3 * time.Second
+
+// Readiness 检查 Docker 守护进程、状态存储和缓存是否可用，以及启动时的端口代理恢复是否完成
+func (s *Service) Readiness(ctx context.IContext) *models.Readiness {
+	ctx, cancel := ctx.WithTimeout(readinessTimeout)
+	defer cancel()
+
+	checks := []struct {
+		name  string
+		check func() error
+	}{
+		{models.ReadinessDocker, func() error { return s.dockerClient.Ping(ctx) }},
+		{models.ReadinessStore, s.store.Ping},
+		{models.ReadinessCache, func() error { return s.Cache.Ping(ctx) }},
+		{models.ReadinessProxies, func() error {
+			if !s.recovered.Load() {
+				return fmt.Errorf("port proxies are still being recovered")
+			}
+			return nil
+		}},
+	}
+
+	readiness := &models.Readiness{Ready: true, Checks: make([]models.ReadinessCheck, 0, len(checks))}
+	for _, item := range checks {
+		result := models.ReadinessCheck{Name: item.name, Ready: true}
+		if err := item.check(); err != nil {
+			result.Ready = false
+			result.Error = err.Error()
+			readiness.Ready = false
+		}
+		readiness.Checks = append(readiness.Checks, result)
+	}
+	return readiness
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// defaultHistoryLimit 每个服务默认保留的历史版本数
+const defaultHistoryLimit = 20
+
+// historyLimit 每个服务保留的历史版本数，可通过 container.history_limit 配置
+func historyLimit() int {
+	if limit := utils.ConfGetInt("container.history_limit"); limit > 0 {
+		return limit
+	}
+	return defaultHistoryLimit
+}
+
+// actorFromContext 从上下文获取操作者，未设置时视为系统操作
+func actorFromContext(ctx context.IContext) string {
+	if actor := ctx.GetString(models.ContextKeyActor); actor != "" {
+		return actor
+	}
+	return "system"
+}
+
+// detachContext 复制上下文中的操作者、令牌范围等值到新的上下文，新上下文不随请求取消，用于请求返回后仍在后台执行的操作
+func detachContext(ctx context.IContext) context.IContext {
+	detached := context.Background()
+	for key, value := range ctx.GetAllKey() {
+		detached.Set(key, value)
+	}
+	return detached
+}
+
+// specSnapshot 序列化服务配置快照，更新策略和更新方式只对当次更新生效，不属于服务配置
+func specSnapshot(req *models.ServiceRequest) (string, error) {
+	spec := *req
+	spec.UpdateStrategy = nil
+	spec.DeploymentMode = ""
+	return utils.EnJson(&spec)
+}
+
+// toDeploymentRevision 将存储中的历史记录转换为 API 模型
+func toDeploymentRevision(rev *store.Revision) *models.DeploymentRevision {
+	result := &models.DeploymentRevision{
+		Revision:  rev.Revision,
+		Action:    rev.Action,
+		Actor:     rev.Actor,
+		Image:     rev.Image,
+		CreatedAt: rev.CreatedAt,
+		Note:      rev.Note,
+	}
+	if err := utils.DeJson(rev.Spec, &result.Spec); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", rev.Service), log.Any("Revision", rev.Revision), log.Any("Message", "解析历史配置失败"))
+	}
+	return result
+}
+
+// recordRevision 记录一次成功的部署或更新
+func (s *Service) recordRevision(ctx context.IContext, action string, req *models.ServiceRequest, note string) {
+	spec, err := specSnapshot(req)
+	if err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", req.Name), log.Any("Message", "序列化服务配置失败"))
+		return
+	}
+
+	rev := &store.Revision{
+		Service: req.Name,
+		Action:  action,
+		Actor:   actorFromContext(ctx),
+		Image:   fmt.Sprintf("%s:%s", req.Image, req.Tag),
+		Note:    note,
+		Spec:    spec,
+	}
+	if err := s.store.AddRevision(rev, historyLimit()); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", req.Name), log.Any("Message", "记录部署历史失败"))
+		return
+	}
+
+	log.Info("Docker", log.Any("ServiceName", req.Name), log.Any("Revision", rev.Revision), log.Any("Action", action),
+		log.Any("Actor", rev.Actor), log.Any("Message", "记录部署历史"))
+}
+
+// GetServiceHistory 获取服务的部署历史，按版本号倒序
+// 与导出接口相同，各版本配置中名称像敏感信息的明文环境变量脱敏；回滚通过 getRevision 读取未脱敏的配置
+func (s *Service) GetServiceHistory(ctx context.IContext, name string) ([]*models.DeploymentRevision, error) {
+	revisions, err := s.store.ListRevisions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.DeploymentRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		revision := toDeploymentRevision(rev)
+		revision.Spec = *maskExportSpec(&revision.Spec)
+		result = append(result, revision)
+	}
+	return result, nil
+}
+
+// getRevision 查找指定版本，revision 为 0 时返回当前版本的上一个版本
+func (s *Service) getRevision(name string, revision int) (*models.DeploymentRevision, error) {
+	if revision == 0 {
+		revisions, err := s.store.ListRevisions(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(revisions) == 0 {
+			return nil, fmt.Errorf("no deployment history for service %s", name)
+		}
+		if len(revisions) < 2 {
+			return nil, fmt.Errorf("service %s has no previous revision", name)
+		}
+		return toDeploymentRevision(revisions[1]), nil
+	}
+
+	rev, err := s.store.GetRevision(name, revision)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, utils.NewError(utils.CodeNotFound, "revision %d not found for service %s", revision, name)
+	}
+	return toDeploymentRevision(rev), nil
+}
+
+// RollbackService 将服务回滚到指定历史版本的配置，revision 为 0 时回滚到上一个版本
+func (s *Service) RollbackService(ctx context.IContext, name string, revision int) (*models.Service, error) {
+	if s.GetService(ctx, name) == nil {
+		return nil, errServiceNotFound(name)
+	}
+
+	target, err := s.getRevision(name, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := target.Spec
+	// 服务改名前的历史版本记录的是旧名称
+	spec.Name = name
+	spec.Namespace, _ = models.SplitServiceName(name)
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("Revision", target.Revision),
+		log.Any("Image", target.Image), log.Any("Message", "开始回滚服务"))
+
+	return s.updateService(ctx, &spec, models.RevisionActionRollback, fmt.Sprintf("rollback to revision %d", target.Revision))
+}
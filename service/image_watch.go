@@ -0,0 +1,295 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/registry"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+const (
+	defaultImageWatchInterval = 300 // 默认检查间隔（秒）
+	imageWatchTimeout         = 30 * time.Second
+	imageWatchActor           = "image-watcher"
+)
+
+// registryConfig image_watch.registries 中单个镜像仓库的配置
+type registryConfig struct {
+	Host     string `mapstructure:"host"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Insecure bool   `mapstructure:"insecure"`
+}
+
+// newRegistryClient 按 image_watch.registries 配置创建镜像仓库客户端，未配置的仓库匿名访问
+func newRegistryClient() *registry.Client {
+	var configs []registryConfig
+	if err := igo.App.Conf.UnmarshalKey("image_watch.registries", &configs); err != nil {
+		log.Error("ImageWatch", log.Any("Error", err), log.Any("Message", "解析镜像仓库配置失败"))
+	}
+
+	credentials := make(map[string]registry.Credential)
+	var insecure []string
+	for _, config := range configs {
+		host := registry.NormalizeHost(config.Host)
+		if config.Username != "" {
+			credentials[host] = registry.Credential{Username: config.Username, Password: config.Password}
+		}
+		if config.Insecure {
+			insecure = append(insecure, host)
+		}
+	}
+	return registry.NewClient(imageWatchTimeout, credentials, insecure)
+}
+
+// validateImageWatch 校验监视配置并补全默认值
+func validateImageWatch(req *models.ImageWatch) error {
+	if req.Mode != models.ImageWatchDigest && req.Mode != models.ImageWatchSemver {
+		return fmt.Errorf("invalid mode %q: must be %s or %s", req.Mode, models.ImageWatchDigest, models.ImageWatchSemver)
+	}
+	if req.Action == "" {
+		req.Action = models.ImageWatchNotify
+	}
+	if req.Action != models.ImageWatchNotify && req.Action != models.ImageWatchRedeploy {
+		return fmt.Errorf("invalid action %q: must be %s or %s", req.Action, models.ImageWatchNotify, models.ImageWatchRedeploy)
+	}
+	if req.Mode == models.ImageWatchDigest && req.Pattern != "" {
+		return fmt.Errorf("pattern is only supported by the %s mode", models.ImageWatchSemver)
+	}
+	if _, err := regexp.Compile(req.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	return nil
+}
+
+// startImageWatcher 启动镜像更新检查循环，image_watch.interval 为 0 时禁用
+func (s *Service) startImageWatcher() {
+	interval := utils.ConfGetIntDefault("image_watch.interval", defaultImageWatchInterval)
+	if interval <= 0 {
+		log.Info("ImageWatch", log.Any("Message", "镜像更新检查已禁用"))
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(interval) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopping:
+				return
+			case <-ticker.C:
+				s.runImageWatches()
+			}
+		}
+	}()
+	log.Info("ImageWatch", log.Any("Interval", interval), log.Any("Message", "镜像更新检查已启动"))
+}
+
+// runImageWatches 检查所有配置了监视的服务
+func (s *Service) runImageWatches() {
+	watches, err := s.store.ListImageWatches()
+	if err != nil {
+		log.Error("ImageWatch", log.Any("Error", err), log.Any("Message", "读取镜像更新监视失败"))
+		return
+	}
+	client := newRegistryClient()
+	for _, watch := range watches {
+		ctx := context.Background()
+		ctx.Set(models.ContextKeyActor, imageWatchActor)
+		s.checkImageWatch(ctx, client, watch)
+	}
+}
+
+// checkImageWatch 检查服务镜像是否有更新，按配置记录或自动滚动更新，并保存检查结果
+func (s *Service) checkImageWatch(ctx context.IContext, client *registry.Client, watch *store.ImageWatch) {
+	watch.LastCheckAt = time.Now()
+	watch.LastError = ""
+	defer func() {
+		if err := s.store.UpdateImageWatchResult(watch); err != nil {
+			log.Error("ImageWatch", log.Any("Error", err), log.Any("ServiceName", watch.Service), log.Any("Message", "保存镜像检查结果失败"))
+		}
+	}()
+
+	service := s.GetService(ctx, watch.Service)
+	if service == nil {
+		watch.LastError = fmt.Sprintf("service %s not found", watch.Service)
+		return
+	}
+
+	available, err := s.findImageUpdate(ctx, client, watch, service)
+	if err != nil {
+		watch.LastError = err.Error()
+		log.Warn("ImageWatch", log.Any("Error", err), log.Any("ServiceName", watch.Service), log.Any("Message", "检查镜像更新失败"))
+		return
+	}
+	watch.UpdateAvailable = available
+	if !available {
+		return
+	}
+
+	log.Warn("ImageWatch", log.Any("ServiceName", watch.Service), log.Any("Image", fmt.Sprintf("%s:%s", service.Image, service.Tag)),
+		log.Any("LatestTag", watch.LatestTag), log.Any("LatestDigest", watch.LatestDigest), log.Any("Action", watch.Action), log.Any("Message", "发现镜像更新"))
+	if watch.Action != models.ImageWatchRedeploy {
+		return
+	}
+
+	if err := s.applyImageUpdate(ctx, watch); err != nil {
+		watch.LastError = err.Error()
+		log.Error("ImageWatch", log.Any("Error", err), log.Any("ServiceName", watch.Service), log.Any("Message", "自动更新镜像失败"))
+		return
+	}
+	watch.UpdateAvailable = false
+	watch.LastUpdateAt = time.Now()
+}
+
+// findImageUpdate 查询镜像仓库，返回是否有尚未部署的更新
+// digest 方式比较仓库中当前标签的摘要与本地镜像拉取时记录的摘要；semver 方式查找比当前标签更新的版本标签
+func (s *Service) findImageUpdate(ctx context.IContext, client *registry.Client, watch *store.ImageWatch, service *models.Service) (bool, error) {
+	checkCtx, cancel := ctx.WithTimeout(imageWatchTimeout)
+	defer cancel()
+
+	if watch.Mode == models.ImageWatchSemver {
+		pattern, err := regexp.Compile(watch.Pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern: %w", err)
+		}
+		tags, err := client.Tags(checkCtx, service.Image)
+		if err != nil {
+			return false, err
+		}
+		watch.LatestTag = registry.LatestTag(service.Tag, tags, pattern)
+		return watch.LatestTag != "", nil
+	}
+
+	digest, err := client.Digest(checkCtx, service.Image, service.Tag)
+	if err != nil {
+		return false, err
+	}
+	watch.LatestDigest = digest
+	local, err := s.dockerClient.ImageDigests(checkCtx, service.Image, service.Tag)
+	if err != nil {
+		return false, err
+	}
+	for _, localDigest := range local {
+		if localDigest == digest {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// applyImageUpdate 按检查结果滚动更新服务：semver 方式更新到新标签，digest 方式按原标签重建副本以拉取新镜像
+func (s *Service) applyImageUpdate(ctx context.IContext, watch *store.ImageWatch) error {
+	if err := s.checkServiceLock(ctx, watch.Service); err != nil {
+		return err
+	}
+	spec, err := s.ExportService(ctx, watch.Service)
+	if err != nil {
+		return err
+	}
+
+	if watch.Mode == models.ImageWatchSemver {
+		spec.Tag = watch.LatestTag
+		_, err = s.updateService(ctx, spec, models.RevisionActionUpdate, fmt.Sprintf("image watcher: new tag %s", watch.LatestTag))
+		return err
+	}
+	_, err = s.redeployService(ctx, spec, fmt.Sprintf("image watcher: new digest %s", watch.LatestDigest))
+	return err
+}
+
+// toImageWatchStatus 将存储中的监视记录转换为 API 模型
+func toImageWatchStatus(watch *store.ImageWatch, service *models.Service) *models.ImageWatchStatus {
+	status := &models.ImageWatchStatus{
+		Service: watch.Service,
+		Watch: models.ImageWatch{
+			Mode:    watch.Mode,
+			Pattern: watch.Pattern,
+			Action:  watch.Action,
+		},
+		LatestTag:       watch.LatestTag,
+		LatestDigest:    watch.LatestDigest,
+		UpdateAvailable: watch.UpdateAvailable,
+		LastError:       watch.LastError,
+	}
+	if service != nil {
+		status.Image = fmt.Sprintf("%s:%s", service.Image, service.Tag)
+	}
+	if !watch.LastCheckAt.IsZero() {
+		lastCheckAt := watch.LastCheckAt
+		status.LastCheckAt = &lastCheckAt
+	}
+	if !watch.LastUpdateAt.IsZero() {
+		lastUpdateAt := watch.LastUpdateAt
+		status.LastUpdateAt = &lastUpdateAt
+	}
+	return status
+}
+
+// SetImageWatch 设置服务的镜像更新监视
+func (s *Service) SetImageWatch(ctx context.IContext, name string, req *models.ImageWatch) (*models.ImageWatchStatus, error) {
+	if err := validateImageWatch(req); err != nil {
+		return nil, err
+	}
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+
+	watch := &store.ImageWatch{
+		Service: name,
+		Mode:    req.Mode,
+		Pattern: req.Pattern,
+		Action:  req.Action,
+	}
+	if err := s.store.SaveImageWatch(watch); err != nil {
+		return nil, err
+	}
+
+	log.Info("ImageWatch", log.Any("ServiceName", name), log.Any("Watch", req), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "设置镜像更新监视"))
+	return toImageWatchStatus(watch, service), nil
+}
+
+// GetImageWatch 获取服务的镜像更新监视及最近一次检查结果
+func (s *Service) GetImageWatch(ctx context.IContext, name string) (*models.ImageWatchStatus, error) {
+	watch, err := s.store.GetImageWatch(name)
+	if err != nil {
+		return nil, err
+	}
+	if watch == nil {
+		return nil, fmt.Errorf("service %s has no image watch", name)
+	}
+	return toImageWatchStatus(watch, s.GetService(ctx, name)), nil
+}
+
+// CheckImageWatch 立即检查服务的镜像更新，配置为 redeploy 时发现更新会直接滚动更新
+func (s *Service) CheckImageWatch(ctx context.IContext, name string) (*models.ImageWatchStatus, error) {
+	watch, err := s.store.GetImageWatch(name)
+	if err != nil {
+		return nil, err
+	}
+	if watch == nil {
+		return nil, fmt.Errorf("service %s has no image watch", name)
+	}
+	s.checkImageWatch(ctx, newRegistryClient(), watch)
+	return toImageWatchStatus(watch, s.GetService(ctx, name)), nil
+}
+
+// DeleteImageWatch 删除服务的镜像更新监视
+func (s *Service) DeleteImageWatch(ctx context.IContext, name string) error {
+	exists, err := s.store.DeleteImageWatch(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("service %s has no image watch", name)
+	}
+	log.Info("ImageWatch", log.Any("ServiceName", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "删除镜像更新监视"))
+	return nil
+}
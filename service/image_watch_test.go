@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestValidateImageWatch 测试镜像更新监视配置校验
+func TestValidateImageWatch(t *testing.T) {
+	req := &models.ImageWatch{Mode: models.ImageWatchDigest}
+	if err := validateImageWatch(req); err != nil {
+		t.Fatalf("validateImageWatch(digest) = %v", err)
+	}
+	if req.Action != models.ImageWatchNotify {
+		t.Errorf("未指定 action 时 = %q, 期望 %s", req.Action, models.ImageWatchNotify)
+	}
+
+	valid := &models.ImageWatch{Mode: models.ImageWatchSemver, Pattern: `^1\.25\.`, Action: models.ImageWatchRedeploy}
+	if err := validateImageWatch(valid); err != nil {
+		t.Errorf("validateImageWatch(semver) = %v", err)
+	}
+
+	invalid := []*models.ImageWatch{
+		{Mode: "poll"},
+		{Mode: models.ImageWatchSemver, Action: "restart"},
+		{Mode: models.ImageWatchSemver, Pattern: "(1.25"},
+		{Mode: models.ImageWatchDigest, Pattern: `^1\.`},
+	}
+	for _, req := range invalid {
+		if err := validateImageWatch(req); err == nil {
+			t.Errorf("validateImageWatch(%+v) 应返回错误", req)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// initNamePattern 初始化容器、边车容器名称，会作为容器名称的一部分
+var initNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// validateInitContainers 校验初始化容器配置
+func validateInitContainers(inits []models.InitContainer) error {
+	seen := make(map[string]bool, len(inits))
+	for i, init := range inits {
+		if !initNamePattern.MatchString(init.Name) {
+			return fmt.Errorf("init_containers[%d]: invalid name %q", i, init.Name)
+		}
+		if seen[init.Name] {
+			return fmt.Errorf("init_containers[%d]: duplicate name %s", i, init.Name)
+		}
+		seen[init.Name] = true
+		if init.Image == "" || init.Tag == "" {
+			return fmt.Errorf("init container %s: image and tag are required", init.Name)
+		}
+		if init.Timeout < 0 {
+			return fmt.Errorf("init container %s: timeout cannot be negative", init.Name)
+		}
+	}
+	return nil
+}
+
+// validateSidecars 校验边车容器配置
+func validateSidecars(sidecars []models.Sidecar) error {
+	seen := make(map[string]bool, len(sidecars))
+	for i, sidecar := range sidecars {
+		if !initNamePattern.MatchString(sidecar.Name) {
+			return fmt.Errorf("sidecars[%d]: invalid name %q", i, sidecar.Name)
+		}
+		if seen[sidecar.Name] {
+			return fmt.Errorf("sidecars[%d]: duplicate name %s", i, sidecar.Name)
+		}
+		seen[sidecar.Name] = true
+		if sidecar.Image == "" || sidecar.Tag == "" {
+			return fmt.Errorf("sidecar %s: image and tag are required", sidecar.Name)
+		}
+		if sidecar.MemoryLimit < 0 {
+			return fmt.Errorf("sidecar %s: memory_limit cannot be negative", sidecar.Name)
+		}
+	}
+	return nil
+}
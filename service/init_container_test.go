@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestValidateInitContainers 测试初始化容器配置校验
+func TestValidateInitContainers(t *testing.T) {
+	valid := []models.InitContainer{
+		{Name: "migrate", Image: "myapp", Tag: "1.0"},
+		{Name: "assets", Image: "curlimages/curl", Tag: "latest", Timeout: 60},
+	}
+	if err := validateInitContainers(valid); err != nil {
+		t.Errorf("合法配置不应报错: %v", err)
+	}
+
+	invalid := map[string][]models.InitContainer{
+		"缺少名称":  {{Image: "myapp", Tag: "1.0"}},
+		"名称非法":  {{Name: "bad name", Image: "myapp", Tag: "1.0"}},
+		"名称重复":  {{Name: "migrate", Image: "myapp", Tag: "1.0"}, {Name: "migrate", Image: "myapp", Tag: "1.0"}},
+		"缺少镜像":  {{Name: "migrate", Tag: "1.0"}},
+		"负数的超时": {{Name: "migrate", Image: "myapp", Tag: "1.0", Timeout: -1}},
+	}
+	for name, inits := range invalid {
+		if err := validateInitContainers(inits); err == nil {
+			t.Errorf("%s: 应返回错误", name)
+		}
+	}
+}
+
+// TestValidateSidecars 测试边车容器配置校验
+func TestValidateSidecars(t *testing.T) {
+	valid := []models.Sidecar{{Name: "log-shipper", Image: "fluent/fluent-bit", Tag: "3.0", MemoryLimit: 64}}
+	if err := validateSidecars(valid); err != nil {
+		t.Errorf("合法配置不应报错: %v", err)
+	}
+
+	invalid := map[string][]models.Sidecar{
+		"缺少名称":    {{Image: "fluent/fluent-bit", Tag: "3.0"}},
+		"名称重复":    {{Name: "exporter", Image: "prom/exporter", Tag: "1.0"}, {Name: "exporter", Image: "prom/exporter", Tag: "1.0"}},
+		"缺少标签":    {{Name: "exporter", Image: "prom/exporter"}},
+		"负数的内存上限": {{Name: "exporter", Image: "prom/exporter", Tag: "1.0", MemoryLimit: -1}},
+	}
+	for name, sidecars := range invalid {
+		if err := validateSidecars(sidecars); err == nil {
+			t.Errorf("%s: 应返回错误", name)
+		}
+	}
+}
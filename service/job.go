@@ -0,0 +1,291 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+)
+
+const (
+	defaultJobTimeout      = 3600 // 默认任务超时（秒）
+	defaultJobHistoryLimit = 100  // 默认保留的任务记录数
+	jobLogTail             = 1000 // 保存的日志末尾行数
+)
+
+// jobRunner 记录运行中的任务，用于取消
+type jobRunner struct {
+	mutex     sync.Mutex
+	cancels   map[string]context.CancelFunc
+	cancelled map[string]bool
+}
+
+// track 登记运行中的任务
+func (r *jobRunner) track(id string, cancel context.CancelFunc) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.cancels == nil {
+		r.cancels = make(map[string]context.CancelFunc)
+		r.cancelled = make(map[string]bool)
+	}
+	r.cancels[id] = cancel
+}
+
+// untrack 任务结束后注销，返回是否被手动取消
+func (r *jobRunner) untrack(id string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	cancelled := r.cancelled[id]
+	delete(r.cancels, id)
+	delete(r.cancelled, id)
+	return cancelled
+}
+
+// cancel 取消运行中的任务，任务不在运行时返回 false
+func (r *jobRunner) cancel(id string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	cancel, ok := r.cancels[id]
+	if !ok {
+		return false
+	}
+	r.cancelled[id] = true
+	cancel()
+	return true
+}
+
+// active 任务是否正在运行
+func (r *jobRunner) active(id string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, ok := r.cancels[id]
+	return ok
+}
+
+// recoverJobs 进程重启后，上次未结束的任务已无法跟踪，标记为失败
+func (s *Service) recoverJobs() {
+	affected, err := s.store.FailUnfinishedJobs([]string{models.JobStatusPending, models.JobStatusRunning},
+		models.JobStatusFailed, "interrupted by onedock restart")
+	if err != nil {
+		log.Error("Job", log.Any("Error", err), log.Any("Message", "清理中断的任务失败"))
+		return
+	}
+	if affected > 0 {
+		log.Warn("Job", log.Any("Count", affected), log.Any("Message", "上次运行中断的任务已标记为失败，遗留容器可通过 /onedock/system/cleanup 清理"))
+	}
+}
+
+// toJob 将存储中的任务记录转换为 API 模型
+func toJob(record *store.Job, withLogs bool) *models.Job {
+	job := &models.Job{
+		ID:          record.JobID,
+		Name:        record.Name,
+		CronJob:     record.CronJob,
+		Image:       record.Image,
+		Status:      record.Status,
+		ExitCode:    record.ExitCode,
+		Error:       record.Error,
+		ContainerID: record.ContainerID,
+		CreatedAt:   record.CreatedAt,
+	}
+	if withLogs {
+		job.Logs = record.Logs
+	}
+	if len(job.ContainerID) > 12 {
+		job.ContainerID = job.ContainerID[:12]
+	}
+	if !record.StartedAt.IsZero() {
+		job.StartedAt = &record.StartedAt
+	}
+	if !record.FinishedAt.IsZero() {
+		job.FinishedAt = &record.FinishedAt
+	}
+	if err := utils.DeJson(record.Spec, &job.Request); err != nil {
+		log.Error("Job", log.Any("Error", err), log.Any("JobID", record.JobID), log.Any("Message", "解析任务请求失败"))
+	}
+	return job
+}
+
+// RunJob 提交一次性任务，立即返回，容器在后台运行直到结束
+func (s *Service) RunJob(ctx context.IContext, req *models.JobRequest) (*models.Job, error) {
+	return s.startJob(ctx, req, "", utils.ConfGetIntDefault("container.job_history_limit", defaultJobHistoryLimit))
+}
+
+// validateJobRequest 校验任务请求
+func (s *Service) validateJobRequest(ctx context.IContext, req *models.JobRequest) error {
+	if req.Image == "" || req.Tag == "" {
+		return fmt.Errorf("image and tag are required")
+	}
+	if req.Timeout < 0 || req.MemoryLimit < 0 {
+		return fmt.Errorf("timeout and memory_limit cannot be negative")
+	}
+	if err := s.checkSecretRefs(req.Environment); err != nil {
+		return err
+	}
+	return s.checkEnvPlaceholders(ctx, req.Environment)
+}
+
+// startJob 保存任务记录并在后台运行，cronJob 为触发该任务的定时任务，historyLimit 为保留的记录数
+func (s *Service) startJob(ctx context.IContext, req *models.JobRequest, cronJob string, historyLimit int) (*models.Job, error) {
+	if err := s.validateJobRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	spec, err := utils.EnJson(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job request: %w", err)
+	}
+	record := &store.Job{
+		JobID:   strings.ReplaceAll(uuid.NewString(), "-", "")[:12],
+		Name:    req.Name,
+		CronJob: cronJob,
+		Image:   fmt.Sprintf("%s:%s", req.Image, req.Tag),
+		Spec:    spec,
+		Status:  models.JobStatusPending,
+	}
+	if err := s.store.AddJob(record, historyLimit); err != nil {
+		return nil, err
+	}
+
+	timeout := req.Timeout
+	if timeout == 0 {
+		timeout = utils.ConfGetIntDefault("container.job_timeout", defaultJobTimeout)
+	}
+	runCtx, cancel := context.Background().WithTimeout(time.Duration(timeout) * time.Second)
+	s.jobs.track(record.JobID, cancel)
+
+	log.Info("Job", log.Any("JobID", record.JobID), log.Any("Name", req.Name), log.Any("CronJob", cronJob), log.Any("Image", record.Image),
+		log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "提交任务"))
+	go s.runJob(runCtx, cancel, record, req, timeout)
+	return toJob(record, false), nil
+}
+
+// runJob 创建并运行任务容器，等待结束后保存退出码与日志并删除容器
+func (s *Service) runJob(ctx context.IContext, cancel context.CancelFunc, record *store.Job, req *models.JobRequest, timeout int) {
+	defer cancel()
+
+	finish := func(status, reason string) {
+		cancelled := s.jobs.untrack(record.JobID)
+		if cancelled && status == models.JobStatusFailed {
+			status, reason = models.JobStatusCancelled, "cancelled"
+		}
+		record.Status = status
+		record.Error = reason
+		record.FinishedAt = time.Now()
+		if err := s.store.UpdateJob(record); err != nil {
+			log.Error("Job", log.Any("Error", err), log.Any("JobID", record.JobID), log.Any("Message", "保存任务结果失败"))
+		}
+		log.Info("Job", log.Any("JobID", record.JobID), log.Any("Status", status), log.Any("ExitCode", record.ExitCode),
+			log.Any("Error", reason), log.Any("Message", "任务结束"))
+	}
+
+	job := &dockerclient.Job{}
+	if err := copier.Copy(job, req); err != nil {
+		finish(models.JobStatusFailed, fmt.Sprintf("failed to copy job request: %v", err))
+		return
+	}
+	job.ID = record.JobID
+
+	containerID, err := s.dockerClient.CreateJobContainer(ctx, job)
+	if err != nil {
+		finish(models.JobStatusFailed, err.Error())
+		return
+	}
+	record.ContainerID = containerID
+
+	// 日志读取与容器删除不受任务超时影响
+	cleanup := context.Background()
+	defer func() {
+		if err := s.dockerClient.RemoveContainer(cleanup, containerID); err != nil {
+			log.Error("Job", log.Any("Error", err), log.Any("JobID", record.JobID), log.Any("Message", "删除任务容器失败"))
+		}
+	}()
+
+	if err := s.dockerClient.StartContainer(ctx, containerID); err != nil {
+		record.Logs, _ = s.dockerClient.ContainerLogs(cleanup, containerID, jobLogTail)
+		finish(models.JobStatusFailed, err.Error())
+		return
+	}
+	record.Status = models.JobStatusRunning
+	record.StartedAt = time.Now()
+	if err := s.store.UpdateJob(record); err != nil {
+		log.Error("Job", log.Any("Error", err), log.Any("JobID", record.JobID), log.Any("Message", "更新任务状态失败"))
+	}
+
+	exitCode, waitErr := s.dockerClient.WaitContainer(ctx, containerID)
+	if logs, err := s.dockerClient.ContainerLogs(cleanup, containerID, jobLogTail); err == nil {
+		record.Logs = logs
+	}
+
+	switch {
+	case waitErr != nil && ctx.Err() != nil:
+		// 超时或取消：容器仍在运行，由 defer 中的强制删除结束
+		finish(models.JobStatusFailed, fmt.Sprintf("timed out after %ds", timeout))
+	case waitErr != nil:
+		finish(models.JobStatusFailed, waitErr.Error())
+	case exitCode == 0:
+		record.ExitCode = &exitCode
+		finish(models.JobStatusSucceeded, "")
+	default:
+		record.ExitCode = &exitCode
+		finish(models.JobStatusFailed, fmt.Sprintf("exited with code %d", exitCode))
+	}
+}
+
+// ListJobs 列出最近的任务（不含日志）
+func (s *Service) ListJobs(ctx context.IContext, limit int) ([]*models.Job, error) {
+	records, err := s.store.ListJobs("", limit)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*models.Job, 0, len(records))
+	for _, record := range records {
+		jobs = append(jobs, toJob(record, false))
+	}
+	return jobs, nil
+}
+
+// GetJob 获取任务状态、退出码和日志
+func (s *Service) GetJob(ctx context.IContext, id string) (*models.Job, error) {
+	record, err := s.store.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, utils.NewError(utils.CodeNotFound, "job %s not found", id)
+	}
+	return toJob(record, true), nil
+}
+
+// CancelJob 取消运行中的任务，容器会被强制删除
+func (s *Service) CancelJob(ctx context.IContext, id string) error {
+	if !s.jobs.cancel(id) {
+		return fmt.Errorf("job %s is not running", id)
+	}
+	log.Info("Job", log.Any("JobID", id), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "取消任务"))
+	return nil
+}
+
+// DeleteJob 删除已结束的任务记录
+func (s *Service) DeleteJob(ctx context.IContext, id string) error {
+	if s.jobs.active(id) {
+		return fmt.Errorf("job %s is still running, cancel it first", id)
+	}
+	exists, err := s.store.DeleteJob(id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return utils.NewError(utils.CodeNotFound, "job %s not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+)
+
+// TestJobRunner 测试运行中任务的登记与取消
+func TestJobRunner(t *testing.T) {
+	var r jobRunner
+	if r.cancel("missing") {
+		t.Error("未登记的任务不能取消")
+	}
+
+	called := false
+	r.track("job-a", func() { called = true })
+	if !r.active("job-a") {
+		t.Fatal("登记后任务应处于运行中")
+	}
+	if !r.cancel("job-a") || !called {
+		t.Error("取消运行中的任务应调用 cancel")
+	}
+	if !r.untrack("job-a") {
+		t.Error("注销时应报告任务已被取消")
+	}
+	if r.active("job-a") {
+		t.Error("注销后任务不应处于运行中")
+	}
+}
+
+// TestToJob 测试任务记录转换
+func TestToJob(t *testing.T) {
+	record := &store.Job{
+		JobID:       "3f2a9c1b7d4e",
+		Image:       "busybox:latest",
+		Spec:        `{"image":"busybox","tag":"latest","command":["echo","hi"]}`,
+		Status:      models.JobStatusSucceeded,
+		Logs:        "hi\n",
+		ContainerID: "a1b2c3d4e5f6a7b8c9d0",
+	}
+
+	job := toJob(record, false)
+	if job.Logs != "" || job.StartedAt != nil || job.ContainerID != "a1b2c3d4e5f6" {
+		t.Errorf("列表中的任务不应包含日志和未设置的时间: %+v", job)
+	}
+	if len(job.Request.Command) != 2 || job.Request.Command[1] != "hi" {
+		t.Errorf("任务请求解析错误: %+v", job.Request)
+	}
+	if job := toJob(record, true); job.Logs != "hi\n" {
+		t.Errorf("任务详情应包含日志: %q", job.Logs)
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+)
+
+// serviceContainers 获取服务的全部容器（包括已停止的）
+func (s *Service) serviceContainers(ctx context.IContext, name string) ([]dockerclient.ContainerInfo, error) {
+	containers, err := s.dockerClient.ListContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	result := make([]dockerclient.ContainerInfo, 0)
+	for _, container := range containers {
+		nameInfo, err := s.dockerClient.ParseContainerName(container.Name)
+		if err != nil {
+			continue
+		}
+		if nameInfo.ServiceName == name {
+			result = append(result, container)
+		}
+	}
+	return result, nil
+}
+
+// StopService 停止服务：停止全部副本和端口代理，但保留容器及其配置、端口，可通过 StartService 立即恢复
+func (s *Service) StopService(ctx context.IContext, name string) (*models.Service, error) {
+	leave, err := s.serialize(ctx, name, models.OperationStop)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return nil, err
+	}
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, errServiceNotFound(name)
+	}
+
+	// 先停止端口代理，不再接收新请求
+	publicPort := 0
+	if config, err := s.dockerClient.ExtractServiceFromContainer(containers[0]); err == nil {
+		publicPort = config.PublicPort
+	}
+	if publicPort > 0 {
+		if err := s.PortManager.StopPortProxy(publicPort); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("PublicPort", publicPort), log.Any("ServiceName", name), log.Any("Message", "停止端口代理失败"))
+		}
+		s.DelContainerMapping(ctx, publicPort)
+	}
+
+	stopped := 0
+	for _, container := range containers {
+		if container.State != "running" && container.State != "restarting" {
+			continue
+		}
+		if err := s.dockerClient.StopReplica(ctx, container.ID); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", container.Name), log.Any("Message", "停止副本失败"))
+			continue
+		}
+		stopped++
+	}
+
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("Stopped", stopped), log.Any("Total", len(containers)), log.Any("Message", "服务已停止"))
+	if err := s.store.UpdateServiceStopped(name, true); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "更新服务期望状态失败"))
+	}
+
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	return service, nil
+}
+
+// StartService 启动已停止的服务：启动全部已停止的副本并恢复端口代理
+func (s *Service) StartService(ctx context.IContext, name string) (*models.Service, error) {
+	leave, err := s.serialize(ctx, name, models.OperationStart)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return nil, err
+	}
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, errServiceNotFound(name)
+	}
+
+	started := 0
+	running := 0
+	for _, container := range containers {
+		if container.State == "running" {
+			running++
+			continue
+		}
+		if err := s.dockerClient.StartReplica(ctx, container.ID); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", container.Name), log.Any("Message", "启动副本失败"))
+			continue
+		}
+		started++
+	}
+
+	if started+running == 0 {
+		return nil, fmt.Errorf("failed to start any replica of service %s", name)
+	}
+
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("Started", started), log.Any("Total", len(containers)), log.Any("Message", "服务已启动"))
+	if err := s.store.UpdateServiceStopped(name, false); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "更新服务期望状态失败"))
+	}
+
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	s.refreshPortProxy(ctx, service.PublicPort)
+	return service, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// isAdmin 当前请求是否使用管理员令牌
+func isAdmin(ctx context.IContext) bool {
+	if ctx == nil {
+		return false
+	}
+	value, _ := ctx.Get(models.ContextKeyAdmin)
+	admin, _ := value.(bool)
+	return admin
+}
+
+// serviceLock 服务的锁定记录，未锁定时返回 nil
+func (s *Service) serviceLock(name string) *store.ServiceLock {
+	lock, err := s.store.GetServiceLock(name)
+	if err != nil {
+		log.Error("Lock", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "读取服务锁定记录失败"))
+		return nil
+	}
+	return lock
+}
+
+// checkServiceLock 服务已锁定且请求方不是管理员时返回错误，部署、更新、扩缩容、启停和删除前调用
+func (s *Service) checkServiceLock(ctx context.IContext, name string) error {
+	lock := s.serviceLock(name)
+	if lock == nil {
+		return nil
+	}
+	if isAdmin(ctx) {
+		log.Warn("Lock", log.Any("ServiceName", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "管理员操作已锁定的服务"))
+		return nil
+	}
+	if lock.Reason != "" {
+		return utils.NewError(utils.CodeServiceLocked, "service %s is locked by %s (%s), unlock it first", name, lock.Actor, lock.Reason)
+	}
+	return utils.NewError(utils.CodeServiceLocked, "service %s is locked by %s, unlock it first", name, lock.Actor)
+}
+
+func toServiceLock(lock *store.ServiceLock) *models.ServiceLock {
+	return &models.ServiceLock{
+		Service:   lock.Service,
+		Reason:    lock.Reason,
+		Actor:     lock.Actor,
+		CreatedAt: lock.CreatedAt,
+	}
+}
+
+// LockService 锁定服务，锁定后只有管理员令牌可以部署、更新、扩缩容、启停或删除该服务
+func (s *Service) LockService(ctx context.IContext, name string, req *models.LockRequest) (*models.ServiceLock, error) {
+	if s.GetService(ctx, name) == nil {
+		return nil, errServiceNotFound(name)
+	}
+	lock := &store.ServiceLock{
+		Service: name,
+		Reason:  req.Reason,
+		Actor:   actorFromContext(ctx),
+	}
+	if err := s.store.AddServiceLock(lock); err != nil {
+		return nil, err
+	}
+	log.Info("Lock", log.Any("ServiceName", name), log.Any("Reason", lock.Reason), log.Any("Actor", lock.Actor), log.Any("Message", "服务已锁定"))
+	return toServiceLock(lock), nil
+}
+
+// UnlockService 解除服务锁定
+func (s *Service) UnlockService(ctx context.IContext, name string) error {
+	exists, err := s.store.DeleteServiceLock(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("service %s is not locked", name)
+	}
+	log.Info("Lock", log.Any("ServiceName", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "服务已解除锁定"))
+	return nil
+}
+
+// GetServiceLock 获取服务锁定信息，未锁定时返回 nil
+func (s *Service) GetServiceLock(ctx context.IContext, name string) (*models.ServiceLock, error) {
+	lock, err := s.store.GetServiceLock(name)
+	if err != nil || lock == nil {
+		return nil, err
+	}
+	return toServiceLock(lock), nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+)
+
+// TestIsAdmin 测试从请求上下文识别管理员令牌
+func TestIsAdmin(t *testing.T) {
+	if isAdmin(nil) {
+		t.Error("空上下文不应视为管理员")
+	}
+	c := context.NewContext()
+	if isAdmin(c) {
+		t.Error("未设置管理员标记时不应视为管理员")
+	}
+	c.Set(models.ContextKeyAdmin, false)
+	if isAdmin(c) {
+		t.Error("普通令牌不应视为管理员")
+	}
+	c.Set(models.ContextKeyAdmin, true)
+	if !isAdmin(c) {
+		t.Error("管理员令牌应视为管理员")
+	}
+}
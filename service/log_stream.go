@@ -0,0 +1,168 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+)
+
+const (
+	logStreamRescan  = 5 * time.Second // 检查新副本（扩容、滚动更新、副本重启）的间隔
+	logStreamBuffer  = 256             // 日志行缓冲，客户端读取过慢时阻塞副本日志读取
+	maxLogLineLength = 64 << 10        // 单行上限，超出时截断为多行
+)
+
+// StreamServiceLogs 实时跟踪服务全部副本的日志，合并到一个通道中
+// 通道在 ctx 取消或 CloseStreams 后关闭；期间新创建或重新启动的副本会自动加入
+func (s *Service) StreamServiceLogs(ctx context.IContext, name string, options *models.LogStreamOptions) (<-chan *models.LogLine, error) {
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, errServiceNotFound(name)
+	}
+
+	ctx, cancel := ctx.WithCancel()
+	lines := make(chan *models.LogLine, logStreamBuffer)
+	go func() {
+		defer close(lines)
+		defer cancel()
+
+		var (
+			wg      sync.WaitGroup
+			mutex   sync.Mutex
+			running = make(map[string]bool)      // 正在跟踪的容器
+			endedAt = make(map[string]time.Time) // 日志流结束的时间，副本重启后从该时间继续
+		)
+		follow := func(container dockerclient.ContainerInfo, tail string, since time.Time) {
+			running[container.ID] = true
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.followReplicaLogs(ctx, container.Name, container.ID, tail, since, lines)
+				mutex.Lock()
+				delete(running, container.ID)
+				endedAt[container.ID] = time.Now()
+				mutex.Unlock()
+			}()
+		}
+
+		mutex.Lock()
+		for _, container := range containers {
+			follow(container, options.Tail, options.Since)
+		}
+		mutex.Unlock()
+
+		ticker := time.NewTicker(logStreamRescan)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			case <-s.streams:
+				cancel()
+			case <-ticker.C:
+				current, err := s.serviceContainers(ctx, name)
+				if err != nil {
+					continue
+				}
+				mutex.Lock()
+				for _, container := range current {
+					if running[container.ID] || container.State != "running" {
+						continue
+					}
+					// 新副本输出全部日志，重启的副本从上次结束处继续
+					if ended, ok := endedAt[container.ID]; ok {
+						follow(container, "all", ended)
+					} else {
+						follow(container, "all", time.Time{})
+					}
+				}
+				mutex.Unlock()
+			}
+		}
+	}()
+	return lines, nil
+}
+
+// followReplicaLogs 跟踪单个副本的日志直到副本停止或 ctx 取消
+func (s *Service) followReplicaLogs(ctx context.IContext, replica, containerID, tail string, since time.Time, lines chan<- *models.LogLine) {
+	stdout := &logLineWriter{ctx: ctx, replica: replica, stream: "stdout", lines: lines}
+	stderr := &logLineWriter{ctx: ctx, replica: replica, stream: "stderr", lines: lines}
+	if err := s.dockerClient.FollowLogs(ctx, containerID, tail, since, stdout, stderr); err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", replica), log.Any("Message", "跟踪副本日志失败"))
+	}
+	stdout.flush()
+	stderr.flush()
+}
+
+// logLineWriter 将日志流按行拆分后发送到通道，每行以 Docker 的时间戳开头
+type logLineWriter struct {
+	ctx     context.IContext
+	replica string
+	stream  string
+	lines   chan<- *models.LogLine
+	buf     bytes.Buffer
+}
+
+// Write 实现 io.Writer，ctx 取消后返回错误以结束日志读取
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		data := w.buf.Bytes()
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			if len(data) < maxLogLineLength {
+				return len(p), nil
+			}
+			i = maxLogLineLength
+		}
+		line := string(data[:i])
+		w.buf.Next(min(i+1, len(data)))
+		if !w.send(line) {
+			return 0, w.ctx.Err()
+		}
+	}
+}
+
+// flush 发送缓冲中最后不完整的一行
+func (w *logLineWriter) flush() {
+	if w.buf.Len() > 0 {
+		w.send(w.buf.String())
+		w.buf.Reset()
+	}
+}
+
+// send 解析时间戳并发送一行，ctx 已取消时返回 false
+func (w *logLineWriter) send(raw string) bool {
+	line := parseLogLine(raw)
+	line.Replica = w.replica
+	line.Stream = w.stream
+	select {
+	case w.lines <- line:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+// parseLogLine 拆分 Docker 日志行的时间戳前缀，去掉 TTY 输出的回车符
+func parseLogLine(raw string) *models.LogLine {
+	raw = strings.TrimRight(raw, "\r")
+	line := &models.LogLine{Line: raw}
+	if prefix, rest, found := strings.Cut(raw, " "); found {
+		if t, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+			line.Time = t
+			line.Line = rest
+		}
+	}
+	return line
+}
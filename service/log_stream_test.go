@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+)
+
+// TestLogLineWriter 测试日志流按行拆分、解析时间戳并处理 TTY 回车符
+func TestLogLineWriter(t *testing.T) {
+	lines := make(chan *models.LogLine, 10)
+	w := &logLineWriter{ctx: context.Background(), replica: "nginx-web-9300-1", stream: "stdout", lines: lines}
+
+	w.Write([]byte("2024-01-15T10:30:00.123456789Z GET /health 200\r\n2024-01-15T10:30:01Z partial"))
+	w.Write([]byte(" line\nno timestamp"))
+	w.flush()
+	close(lines)
+
+	var got []*models.LogLine
+	for line := range lines {
+		got = append(got, line)
+	}
+	if len(got) != 3 {
+		t.Fatalf("拆分出 %d 行, 期望 3 行", len(got))
+	}
+	want := time.Date(2024, 1, 15, 10, 30, 0, 123456789, time.UTC)
+	if got[0].Line != "GET /health 200" || !got[0].Time.Equal(want) || got[0].Replica != "nginx-web-9300-1" || got[0].Stream != "stdout" {
+		t.Errorf("第 1 行 = %+v", got[0])
+	}
+	if got[1].Line != "partial line" {
+		t.Errorf("跨两次写入的行 = %q, 期望 %q", got[1].Line, "partial line")
+	}
+	if got[2].Line != "no timestamp" || !got[2].Time.IsZero() {
+		t.Errorf("没有时间戳的行 = %+v", got[2])
+	}
+}
+
+// TestLogLineWriterCanceled 测试 ctx 取消后写入返回错误以结束日志读取
+func TestLogLineWriterCanceled(t *testing.T) {
+	ctx, cancel := context.Background().WithCancel()
+	cancel()
+	w := &logLineWriter{ctx: ctx, replica: "nginx-web-9300-1", stream: "stderr", lines: make(chan *models.LogLine)}
+	if _, err := w.Write([]byte("line\n")); err == nil {
+		t.Error("ctx 取消后 Write 应返回错误")
+	}
+}
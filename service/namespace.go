@@ -0,0 +1,64 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+)
+
+// normalizeNamespace 规范化请求中的命名空间与服务名称
+// 服务名称可以直接写成 命名空间.名称，也可以通过 namespace 字段指定；默认命名空间下服务名称不带前缀
+func normalizeNamespace(req *models.ServiceRequest) error {
+	namespace, short := models.SplitServiceName(req.Name)
+	if req.Namespace == "" {
+		req.Namespace = namespace
+	} else if strings.Contains(req.Name, models.NamespaceSeparator) && namespace != req.Namespace {
+		return fmt.Errorf("service name %s does not belong to namespace %s", req.Name, req.Namespace)
+	}
+
+	if err := models.ValidateNamespace(req.Namespace); err != nil {
+		return err
+	}
+	if short == "" || strings.Contains(short, models.NamespaceSeparator) {
+		return fmt.Errorf("invalid service name %q: name cannot be empty or contain %q", short, models.NamespaceSeparator)
+	}
+
+	req.Name = models.QualifiedName(req.Namespace, short)
+	return nil
+}
+
+// ListServicesInNamespace 列出指定命名空间下的服务
+func (s *Service) ListServicesInNamespace(ctx context.IContext, namespace string) []*models.Service {
+	services := s.ListServices(ctx)
+	result := make([]*models.Service, 0, len(services))
+	for _, service := range services {
+		if service.Namespace == namespace {
+			result = append(result, service)
+		}
+	}
+	return result
+}
+
+// ListNamespaces 列出所有命名空间及其服务数、副本数
+func (s *Service) ListNamespaces(ctx context.IContext) []*models.NamespaceInfo {
+	namespaces := make(map[string]*models.NamespaceInfo)
+	for _, service := range s.ListServices(ctx) {
+		info, ok := namespaces[service.Namespace]
+		if !ok {
+			info = &models.NamespaceInfo{Name: service.Namespace}
+			namespaces[service.Namespace] = info
+		}
+		info.Services++
+		info.Replicas += service.Replicas
+	}
+
+	result := make([]*models.NamespaceInfo, 0, len(namespaces))
+	for _, info := range namespaces {
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestNormalizeNamespace 测试命名空间与服务名称的规范化
+func TestNormalizeNamespace(t *testing.T) {
+	cases := []struct {
+		name, namespace string
+		wantName        string
+		wantNamespace   string
+		wantErr         bool
+	}{
+		{"web", "", "web", "default", false},
+		{"web", "default", "web", "default", false},
+		{"web", "staging", "staging.web", "staging", false},
+		{"staging.web", "", "staging.web", "staging", false},
+		{"staging.web", "staging", "staging.web", "staging", false},
+		{"staging.web", "prod", "", "", true},
+		{"web", "Bad_NS", "", "", true},
+		{"a.b.c", "", "", "", true},
+	}
+
+	for _, tc := range cases {
+		req := &models.ServiceRequest{Name: tc.name, Namespace: tc.namespace}
+		err := normalizeNamespace(req)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s/%s: 期望错误=%v，实际 %v", tc.namespace, tc.name, tc.wantErr, err)
+			continue
+		}
+		if err == nil && (req.Name != tc.wantName || req.Namespace != tc.wantNamespace) {
+			t.Errorf("%s/%s: 期望 %s/%s，实际 %s/%s", tc.namespace, tc.name, tc.wantNamespace, tc.wantName, req.Namespace, req.Name)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// networkNamePattern Docker 网络名称格式
+var networkNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+// predefinedNetworks Docker 预定义的网络，不能创建同名网络
+var predefinedNetworks = map[string]bool{"bridge": true, "host": true, "none": true}
+
+// networkAttachments 按网络名称汇总受管副本，副本按服务和副本编号排序
+func networkAttachments(containers []dockerclient.ContainerInfo, parse func(string) (*dockerclient.ContainerNameInfo, error)) map[string][]models.NetworkAttachment {
+	result := make(map[string][]models.NetworkAttachment)
+	for _, container := range containers {
+		nameInfo, err := parse(container.Name)
+		if err != nil {
+			continue
+		}
+		for _, endpoint := range container.Networks {
+			result[endpoint.Network] = append(result[endpoint.Network], models.NetworkAttachment{
+				Service:     nameInfo.ServiceName,
+				Replica:     nameInfo.ReplicaIndex,
+				ContainerID: container.ID[:12],
+				State:       container.State,
+				IPAddress:   endpoint.IPAddress,
+			})
+		}
+	}
+	for _, replicas := range result {
+		sort.Slice(replicas, func(i, j int) bool {
+			if replicas[i].Service != replicas[j].Service {
+				return replicas[i].Service < replicas[j].Service
+			}
+			return replicas[i].Replica < replicas[j].Replica
+		})
+	}
+	return result
+}
+
+// networkModel 转换 Docker 网络，replicas 为已排序的加入该网络的副本
+func networkModel(info *dockerclient.NetworkInfo, replicas []models.NetworkAttachment) *models.Network {
+	network := &models.Network{
+		ID:        info.ID,
+		Name:      info.Name,
+		Driver:    info.Driver,
+		Scope:     info.Scope,
+		Internal:  info.Internal,
+		Subnets:   info.Subnets,
+		Gateways:  info.Gateways,
+		Labels:    info.Labels,
+		Managed:   info.Managed,
+		CreatedAt: info.CreatedAt,
+		Services:  []string{},
+		Replicas:  replicas,
+	}
+	if len(network.ID) > 12 {
+		network.ID = network.ID[:12]
+	}
+	if network.Replicas == nil {
+		network.Replicas = []models.NetworkAttachment{}
+	}
+	for i, replica := range network.Replicas {
+		if i == 0 || replica.Service != network.Replicas[i-1].Service {
+			network.Services = append(network.Services, replica.Service)
+		}
+	}
+	return network
+}
+
+// ListNetworks 列出 OneDock 创建的网络和受管服务加入的网络，以及加入每个网络的服务和副本
+// all 为 true 时列出全部 Docker 网络
+func (s *Service) ListNetworks(ctx context.IContext, all bool) ([]*models.Network, error) {
+	containers, err := s.dockerClient.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	networks, err := s.dockerClient.ListNetworks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attachments := networkAttachments(containers, s.dockerClient.ParseContainerName)
+
+	result := make([]*models.Network, 0, len(networks))
+	for i := range networks {
+		info := &networks[i]
+		replicas := attachments[info.Name]
+		if all || info.Managed || len(replicas) > 0 {
+			result = append(result, networkModel(info, replicas))
+		}
+	}
+	return result, nil
+}
+
+// CreateNetwork 创建由 OneDock 管理的网络
+func (s *Service) CreateNetwork(ctx context.IContext, req *models.NetworkCreateRequest) (*models.Network, error) {
+	if !networkNamePattern.MatchString(req.Name) {
+		return nil, utils.NewError(utils.CodeInvalidRequest, "invalid network name %q: must be letters, digits, '_', '.' or '-', starting with a letter or digit", req.Name)
+	}
+	if predefinedNetworks[req.Name] {
+		return nil, utils.NewError(utils.CodeConflict, "network %s is predefined by docker", req.Name)
+	}
+
+	info, err := s.dockerClient.CreateNetwork(ctx, &dockerclient.NetworkConfig{
+		Name:     req.Name,
+		Driver:   req.Driver,
+		Internal: req.Internal,
+		Subnet:   req.Subnet,
+		Gateway:  req.Gateway,
+		Labels:   req.Labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Docker", log.Any("Network", info.Name), log.Any("Driver", info.Driver), log.Any("Subnets", info.Subnets), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "网络已创建"))
+	return networkModel(info, nil), nil
+}
+
+// DeleteNetwork 删除由 OneDock 创建的网络
+// 不是 OneDock 创建的网络不能删除；仍有受管副本（包括已停止的副本）加入时拒绝删除
+func (s *Service) DeleteNetwork(ctx context.IContext, name string) error {
+	networks, err := s.ListNetworks(ctx, true)
+	if err != nil {
+		return err
+	}
+	var network *models.Network
+	for _, n := range networks {
+		if n.Name == name {
+			network = n
+			break
+		}
+	}
+	if network == nil {
+		return utils.NewError(utils.CodeNotFound, "network %s not found", name)
+	}
+	if !network.Managed {
+		return utils.NewError(utils.CodeInvalidRequest, "network %s was not created by onedock", name)
+	}
+	if len(network.Services) > 0 {
+		return utils.NewError(utils.CodeConflict, "network %s is used by services %v, delete or update them first", name, network.Services)
+	}
+	if err := s.dockerClient.RemoveNetwork(ctx, name); err != nil {
+		return err
+	}
+	log.Info("Docker", log.Any("Network", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "网络已删除"))
+	return nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aichy126/onedock/library/dockerclient"
+)
+
+// TestNetworkAttachments 测试按网络汇总受管副本
+func TestNetworkAttachments(t *testing.T) {
+	containers := []dockerclient.ContainerInfo{
+		{ID: "bbbbbbbbbbbb0001", Name: "web-1", State: "running", Networks: []dockerclient.NetworkEndpoint{
+			{Network: "backend", IPAddress: "172.30.0.3"},
+			{Network: "bridge", IPAddress: "172.17.0.3"},
+		}},
+		{ID: "aaaaaaaaaaaa0001", Name: "web-0", State: "exited", Networks: []dockerclient.NetworkEndpoint{
+			{Network: "backend"},
+		}},
+		{ID: "cccccccccccc0001", Name: "api-0", State: "running", Networks: []dockerclient.NetworkEndpoint{
+			{Network: "backend", IPAddress: "172.30.0.2"},
+		}},
+		{ID: "dddddddddddd0001", Name: "orphan", Networks: []dockerclient.NetworkEndpoint{
+			{Network: "backend"},
+		}},
+	}
+
+	attachments := networkAttachments(containers, parseTestContainerName)
+	if len(attachments) != 2 {
+		t.Fatalf("networkAttachments() 返回 %d 个网络, 期望 2 个", len(attachments))
+	}
+	backend := attachments["backend"]
+	if len(backend) != 3 {
+		t.Fatalf("backend 的副本 = %+v, 期望 3 个（无法解析的容器不计入）", backend)
+	}
+	if backend[0].Service != "api" || backend[1].Replica != 0 || backend[1].ContainerID != "aaaaaaaaaaaa" || backend[2].IPAddress != "172.30.0.3" {
+		t.Errorf("backend 的副本 = %+v, 期望按服务和副本编号排序", backend)
+	}
+
+	network := networkModel(&dockerclient.NetworkInfo{ID: "0123456789abcdef", Name: "backend", Managed: true}, backend)
+	if network.ID != "0123456789ab" {
+		t.Errorf("network.ID = %s, 期望短格式", network.ID)
+	}
+	if !reflect.DeepEqual(network.Services, []string{"api", "web"}) {
+		t.Errorf("network.Services = %v, 期望 [api web]", network.Services)
+	}
+
+	empty := networkModel(&dockerclient.NetworkInfo{ID: "abc", Name: "unused"}, nil)
+	if empty.ID != "abc" || empty.Services == nil || empty.Replicas == nil || len(empty.Services) != 0 {
+		t.Errorf("没有副本的网络 = %+v, 期望空列表", empty)
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+const defaultNotifyTimeout = 10 // 默认单次通知超时（秒）
+
+// 通知方式
+const (
+	notifierWebhook = "webhook"
+	notifierSlack   = "slack"
+	notifierEmail   = "email"
+)
+
+// notifierConfig notifications.notifiers 中单个通知方式的配置
+type notifierConfig struct {
+	Name     string            `mapstructure:"name"`
+	Type     string            `mapstructure:"type"`
+	Events   []string          `mapstructure:"events"`   // 为空表示全部事件
+	Services []string          `mapstructure:"services"` // 为空表示全部服务
+	URL      string            `mapstructure:"url"`      // webhook / slack
+	Headers  map[string]string `mapstructure:"headers"`  // webhook 附加请求头
+	SMTPHost string            `mapstructure:"smtp_host"`
+	SMTPPort int               `mapstructure:"smtp_port"`
+	Username string            `mapstructure:"username"`
+	Password string            `mapstructure:"password"`
+	From     string            `mapstructure:"from"`
+	To       []string          `mapstructure:"to"`
+}
+
+// eventNotification webhook 通知的请求体
+type eventNotification struct {
+	*models.Event
+	Host string `json:"host"`
+}
+
+// notifierConfigs 读取通知配置（[[notifications.notifiers]]），每次读取以便配置热加载
+func notifierConfigs() []notifierConfig {
+	var configs []notifierConfig
+	if err := igo.App.Conf.UnmarshalKey("notifications.notifiers", &configs); err != nil {
+		log.Error("Notify", log.Any("Error", err), log.Any("Message", "解析通知配置失败"))
+	}
+	return configs
+}
+
+// matches 通知方式是否订阅了该事件
+func (config *notifierConfig) matches(event *models.Event) bool {
+	return matchAny(config.Events, event.Type) && matchAny(config.Services, event.Service)
+}
+
+// matchAny 列表为空或包含 value
+func matchAny(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyEvent 将事件异步发送到所有订阅的通知方式和通过接口创建的 webhook，发送失败只记录日志
+func (s *Service) notifyEvent(event *models.Event) {
+	timeout := time.Duration(utils.ConfGetIntDefault("notifications.timeout", defaultNotifyTimeout)) * time.Second
+	host, _ := os.Hostname()
+	s.deliverWebhooks(event, host, timeout)
+
+	for _, config := range notifierConfigs() {
+		if !config.matches(event) {
+			continue
+		}
+		go func(config notifierConfig) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			if err := sendNotification(ctx, &config, host, event); err != nil {
+				log.Error("Notify", log.Any("Error", err), log.Any("Notifier", config.Name), log.Any("Type", config.Type),
+					log.Any("ServiceName", event.Service), log.Any("Event", event.Type), log.Any("Message", "发送事件通知失败"))
+			}
+		}(config)
+	}
+}
+
+// sendNotification 按通知方式发送一条事件
+func sendNotification(ctx context.Context, config *notifierConfig, host string, event *models.Event) error {
+	switch config.Type {
+	case notifierWebhook:
+		return postJSON(ctx, config.URL, config.Headers, &eventNotification{Event: event, Host: host})
+	case notifierSlack:
+		return postJSON(ctx, config.URL, nil, map[string]string{"text": notificationText(host, event)})
+	case notifierEmail:
+		return sendEmail(ctx, config, host, event)
+	default:
+		return fmt.Errorf("unknown notifier type %q: must be %s, %s or %s", config.Type, notifierWebhook, notifierSlack, notifierEmail)
+	}
+}
+
+// notificationText 单行事件描述，用于 Slack 消息和邮件标题
+func notificationText(host string, event *models.Event) string {
+	return fmt.Sprintf("[onedock@%s] %s %s: %s", host, event.Service, event.Type, event.Message)
+}
+
+// postJSON 以 JSON 发送 POST 请求，非 2xx 响应视为失败
+func postJSON(ctx context.Context, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = postBody(ctx, url, headers, body)
+	return err
+}
+
+// postBody 发送 JSON 请求体，返回 HTTP 状态码（连接失败时为 0），非 2xx 响应视为失败
+func postBody(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	if url == "" {
+		return 0, fmt.Errorf("url is required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("%s responded with status %d", url, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// emailMessage 构造纯文本邮件
+func emailMessage(config *notifierConfig, host string, event *models.Event) []byte {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", notificationText(host, event))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&msg, "Host: %s\r\nService: %s\r\nEvent: %s\r\nTime: %s\r\nActor: %s\r\n\r\n%s\r\n",
+		host, event.Service, event.Type, event.CreatedAt.Format(time.RFC3339), event.Actor, event.Message)
+	return []byte(msg.String())
+}
+
+// sendEmail 通过 SMTP 发送邮件，服务器支持时自动启用 STARTTLS
+func sendEmail(ctx context.Context, config *notifierConfig, host string, event *models.Event) error {
+	if config.SMTPHost == "" || config.From == "" || len(config.To) == 0 {
+		return fmt.Errorf("smtp_host, from and to are required")
+	}
+	port := config.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.SMTPHost)
+	}
+
+	// smtp.SendMail 不支持超时，在独立协程中发送并等待 ctx
+	done := make(chan error, 1)
+	go func() {
+		addr := net.JoinHostPort(config.SMTPHost, strconv.Itoa(port))
+		done <- smtp.SendMail(addr, auth, config.From, config.To, emailMessage(config, host, event))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("smtp %s: %w", config.SMTPHost, ctx.Err())
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestNotifierMatches 测试通知方式按事件类型和服务过滤
+func TestNotifierMatches(t *testing.T) {
+	event := &models.Event{Service: "nginx-web", Type: models.EventUpdateFailed}
+	cases := []struct {
+		config notifierConfig
+		want   bool
+	}{
+		{notifierConfig{}, true},
+		{notifierConfig{Events: []string{models.EventUpdateFailed, models.EventCrashLoop}}, true},
+		{notifierConfig{Events: []string{models.EventAutoscaled}}, false},
+		{notifierConfig{Services: []string{"nginx-web"}}, true},
+		{notifierConfig{Events: []string{models.EventUpdateFailed}, Services: []string{"billing"}}, false},
+	}
+	for _, c := range cases {
+		if got := c.config.matches(event); got != c.want {
+			t.Errorf("matches(%+v) = %v, 期望 %v", c.config, got, c.want)
+		}
+	}
+}
+
+// TestSendNotification 测试 webhook 和 Slack 通知的请求内容
+func TestSendNotification(t *testing.T) {
+	var received map[string]interface{}
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	event := &models.Event{ID: 7, Service: "nginx-web", Type: models.EventCrashLoop, Message: "replicas crashed 3 times", CreatedAt: time.Now()}
+	ctx := context.Background()
+
+	webhook := &notifierConfig{Type: notifierWebhook, URL: server.URL, Headers: map[string]string{"Authorization": "Bearer abc"}}
+	if err := sendNotification(ctx, webhook, "host-1", event); err != nil {
+		t.Fatalf("发送 webhook 通知失败: %v", err)
+	}
+	if received["type"] != models.EventCrashLoop || received["host"] != "host-1" || received["id"] != float64(7) || header != "Bearer abc" {
+		t.Errorf("webhook 请求 = %v（Authorization %q）, 期望包含事件、主机名和自定义请求头", received, header)
+	}
+
+	slack := &notifierConfig{Type: notifierSlack, URL: server.URL}
+	if err := sendNotification(ctx, slack, "host-1", event); err != nil {
+		t.Fatalf("发送 Slack 通知失败: %v", err)
+	}
+	if text, _ := received["text"].(string); !strings.Contains(text, "nginx-web crash_loop") {
+		t.Errorf("Slack 消息 = %q, 期望包含服务名和事件类型", text)
+	}
+
+	if err := sendNotification(ctx, &notifierConfig{Type: "pager"}, "host-1", event); err == nil {
+		t.Error("未知的通知方式应返回错误")
+	}
+	if err := sendNotification(ctx, &notifierConfig{Type: notifierEmail}, "host-1", event); err == nil {
+		t.Error("邮件通知缺少 smtp_host 时应返回错误")
+	}
+}
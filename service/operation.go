@@ -0,0 +1,205 @@
+package service
+
+import (
+	stdcontext "context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// defaultOperationQueueTimeout 默认的排队等待上限（秒）
+const defaultOperationQueueTimeout = 300
+
+// operationContextKey 上下文中标记已持有服务操作队列的键前缀
+const operationContextKey = "onedock-operation:"
+
+// queuedOperation 队列中的一项操作，轮到执行时关闭 ready
+type queuedOperation struct {
+	operation models.Operation
+	ready     chan struct{}
+}
+
+// serviceOperations 单个服务的操作队列
+type serviceOperations struct {
+	running *queuedOperation
+	waiting []*queuedOperation
+}
+
+// operationQueue 按服务串行执行修改操作，避免并发部署、扩缩容、更新争用端口和副本序号
+type operationQueue struct {
+	mutex    sync.Mutex
+	services map[string]*serviceOperations
+}
+
+// enter 进入服务的操作队列，返回时已轮到该操作，调用返回的函数退出队列
+// 排队超过 timeout 或 ctx 被取消（请求超时、客户端断开）时放弃并返回错误
+func (q *operationQueue) enter(ctx stdcontext.Context, name, operation, actor string, timeout time.Duration) (func(), error) {
+	item := &queuedOperation{
+		operation: models.Operation{Operation: operation, Actor: actor, QueuedAt: time.Now()},
+		ready:     make(chan struct{}),
+	}
+
+	q.mutex.Lock()
+	if q.services == nil {
+		q.services = make(map[string]*serviceOperations)
+	}
+	ops, ok := q.services[name]
+	if !ok {
+		ops = &serviceOperations{}
+		q.services[name] = ops
+	}
+	if ops.running == nil {
+		item.start()
+		ops.running = item
+		q.mutex.Unlock()
+		return q.leaveFunc(name, item), nil
+	}
+	ops.waiting = append(ops.waiting, item)
+	running := ops.running.operation.Operation
+	q.mutex.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var giveUp error
+	select {
+	case <-item.ready:
+		return q.leaveFunc(name, item), nil
+	case <-timer.C:
+		giveUp = utils.NewError(utils.CodeServiceBusy, "service %s is busy with %s, gave up after waiting %s in the operation queue", name, running, timeout)
+	case <-ctx.Done():
+		giveUp = fmt.Errorf("request ended while service %s is busy with %s: %w", name, running, ctx.Err())
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	// 放弃的同时可能刚好轮到该操作
+	select {
+	case <-item.ready:
+		return q.leaveFunc(name, item), nil
+	default:
+	}
+	for i, waiting := range ops.waiting {
+		if waiting == item {
+			ops.waiting = append(ops.waiting[:i], ops.waiting[i+1:]...)
+			break
+		}
+	}
+	return nil, giveUp
+}
+
+// tryEnter 服务空闲时进入队列，否则立即返回 false，用于后台循环跳过正在操作的服务
+func (q *operationQueue) tryEnter(name, operation, actor string) (func(), bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if ops, ok := q.services[name]; ok && ops.running != nil {
+		return nil, false
+	}
+	if q.services == nil {
+		q.services = make(map[string]*serviceOperations)
+	}
+	item := &queuedOperation{
+		operation: models.Operation{Operation: operation, Actor: actor, QueuedAt: time.Now()},
+		ready:     make(chan struct{}),
+	}
+	item.start()
+	q.services[name] = &serviceOperations{running: item}
+	return q.leaveFunc(name, item), true
+}
+
+// start 标记操作开始执行，调用方需持有队列的锁
+func (item *queuedOperation) start() {
+	now := time.Now()
+	item.operation.StartedAt = &now
+	close(item.ready)
+}
+
+// leaveFunc 返回只生效一次的退出函数
+func (q *operationQueue) leaveFunc(name string, item *queuedOperation) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { q.leave(name, item) })
+	}
+}
+
+// leave 结束当前操作，并让队首的操作开始执行
+func (q *operationQueue) leave(name string, item *queuedOperation) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	ops, ok := q.services[name]
+	if !ok || ops.running != item {
+		return
+	}
+	if len(ops.waiting) == 0 {
+		delete(q.services, name)
+		return
+	}
+	next := ops.waiting[0]
+	ops.waiting = ops.waiting[1:]
+	next.start()
+	ops.running = next
+}
+
+// busy 服务是否有正在执行的操作
+func (q *operationQueue) busy(name string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	ops, ok := q.services[name]
+	return ok && ops.running != nil
+}
+
+// status 返回服务操作队列的快照
+func (q *operationQueue) status(name string) *models.OperationQueue {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	status := &models.OperationQueue{Service: name, Queued: make([]*models.Operation, 0)}
+	ops, ok := q.services[name]
+	if !ok {
+		return status
+	}
+	if ops.running != nil {
+		running := ops.running.operation
+		status.Running = &running
+	}
+	for _, item := range ops.waiting {
+		waiting := item.operation
+		status.Queued = append(status.Queued, &waiting)
+	}
+	return status
+}
+
+// serialize 在服务的操作队列中执行修改操作，返回的函数在操作结束时调用
+// 同一上下文中的嵌套调用（如部署转为更新、删除转为缩容）已持有队列，直接执行
+func (s *Service) serialize(ctx context.IContext, name, operation string) (func(), error) {
+	key := operationContextKey + name
+	if held, _ := ctx.Get(key); held == true {
+		return func() {}, nil
+	}
+
+	if s.operations.busy(name) {
+		log.Info("Operation", log.Any("ServiceName", name), log.Any("Operation", operation), log.Any("Message", "服务正在执行其他操作，排队等待"))
+	}
+	timeout := time.Duration(utils.ConfGetIntDefault("container.operation_queue_timeout", defaultOperationQueueTimeout)) * time.Second
+	leave, err := s.operations.enter(ctx, name, operation, actorFromContext(ctx), timeout)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Set(key, true)
+	return func() {
+		ctx.Set(key, false)
+		leave()
+	}, nil
+}
+
+// GetOperationQueue 获取服务正在执行和排队等待的修改操作
+func (s *Service) GetOperationQueue(ctx context.IContext, name string) (*models.OperationQueue, error) {
+	status := s.operations.status(name)
+	if status.Running == nil && s.GetService(ctx, name) == nil {
+		return nil, errServiceNotFound(name)
+	}
+	return status, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	stdcontext "context"
+	"testing"
+	"time"
+
+	"github.com/aichy126/onedock/utils"
+)
+
+// TestOperationQueue 测试同一服务的操作按进入顺序逐个执行
+func TestOperationQueue(t *testing.T) {
+	var queue operationQueue
+	leave, err := queue.enter(stdcontext.Background(), "nginx-web", "deploy", "token:a", time.Second)
+	if err != nil {
+		t.Fatalf("enter() = %v", err)
+	}
+	if _, ok := queue.tryEnter("nginx-web", "reconcile", "system"); ok {
+		t.Error("服务正在执行操作时 tryEnter() 应返回 false")
+	}
+	otherLeave, ok := queue.tryEnter("api", "reconcile", "system")
+	if !ok {
+		t.Fatal("其他服务空闲时 tryEnter() 应返回 true")
+	}
+	otherLeave()
+
+	order := make(chan string, 2)
+	for i, operation := range []string{"scale", "stop"} {
+		operation := operation
+		go func() {
+			next, err := queue.enter(stdcontext.Background(), "nginx-web", operation, "token:b", time.Second)
+			if err != nil {
+				order <- err.Error()
+				return
+			}
+			order <- operation
+			next()
+		}()
+		// 等待操作进入队列，保证排队顺序
+		for len(queue.status("nginx-web").Queued) <= i {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	status := queue.status("nginx-web")
+	if status.Running == nil || status.Running.Operation != "deploy" || status.Running.StartedAt == nil {
+		t.Errorf("Running = %+v, 期望 deploy", status.Running)
+	}
+	if len(status.Queued) != 2 || status.Queued[0].Operation != "scale" || status.Queued[1].Operation != "stop" {
+		t.Fatalf("Queued = %+v, 期望 scale、stop", status.Queued)
+	}
+
+	leave()
+	leave() // 重复调用不影响后续操作
+	for _, want := range []string{"scale", "stop"} {
+		if got := <-order; got != want {
+			t.Errorf("执行顺序 = %s, 期望 %s", got, want)
+		}
+	}
+	// 最后一个操作退出后队列清空
+	for queue.busy("nginx-web") {
+		time.Sleep(time.Millisecond)
+	}
+	if status := queue.status("nginx-web"); status.Running != nil || len(status.Queued) != 0 {
+		t.Errorf("队列应为空, 实际 %+v", status)
+	}
+}
+
+// TestOperationQueueTimeout 测试排队超时的操作退出队列
+func TestOperationQueueTimeout(t *testing.T) {
+	var queue operationQueue
+	leave, err := queue.enter(stdcontext.Background(), "nginx-web", "update", "token:a", time.Second)
+	if err != nil {
+		t.Fatalf("enter() = %v", err)
+	}
+	if _, err := queue.enter(stdcontext.Background(), "nginx-web", "scale", "token:b", 10*time.Millisecond); err == nil {
+		t.Error("排队超时时 enter() 应返回错误")
+	}
+	if queued := queue.status("nginx-web").Queued; len(queued) != 0 {
+		t.Errorf("超时的操作应退出队列, 实际 %+v", queued)
+	}
+
+	// 请求超时时不再等待队列超时
+	ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := queue.enter(ctx, "nginx-web", "scale", "token:b", time.Minute); utils.ErrorCode(err) != utils.CodeTimeout {
+		t.Errorf("请求超时时 enter() 错误码 = %s, 期望 %s", utils.ErrorCode(err), utils.CodeTimeout)
+	}
+	if queued := queue.status("nginx-web").Queued; len(queued) != 0 {
+		t.Errorf("请求超时的操作应退出队列, 实际 %+v", queued)
+	}
+
+	leave()
+	if queue.busy("nginx-web") {
+		t.Error("操作结束后服务应为空闲")
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+)
+
+const (
+	overviewFailureWindow = 24 * time.Hour // 最近故障的统计窗口
+	overviewFailureLimit  = 20             // 最近故障最多返回条数
+)
+
+// overviewFailureEvents 计入最近故障的事件类型
+var overviewFailureEvents = []string{models.EventUpdateFailed, models.EventReplicaCrashed, models.EventCrashLoop}
+
+// GetOverview 汇总服务、副本、端口、代理和最近故障
+func (s *Service) GetOverview(ctx context.IContext) (*models.Overview, error) {
+	containers, err := s.dockerClient.ListContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	specs, err := s.store.ListServiceSpecs()
+	if err != nil {
+		return nil, err
+	}
+	ports, err := s.store.ListPorts(false)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &models.Overview{
+		ServicesByStatus:  make(map[string]int),
+		DegradedServices:  make([]*models.DegradedService, 0),
+		ActiveRollouts:    make([]string, 0),
+		UnhealthyReplicas: make([]*models.UnhealthyReplica, 0),
+		MissingProxies:    make([]int, 0),
+		GeneratedAt:       time.Now(),
+	}
+
+	services := s.processContainersToServices(containers)
+	overview.Services = len(services)
+	for _, service := range services {
+		overview.ServicesByStatus[string(service.Status)]++
+	}
+
+	// 副本状态
+	running := make(map[string]int)
+	for _, container := range containers {
+		nameInfo, err := s.dockerClient.ParseContainerName(container.Name)
+		if err != nil {
+			continue
+		}
+		overview.Replicas++
+		if container.State == "running" {
+			overview.RunningReplicas++
+			running[nameInfo.ServiceName]++
+			continue
+		}
+		overview.UnhealthyReplicas = append(overview.UnhealthyReplicas, &models.UnhealthyReplica{
+			Service:   nameInfo.ServiceName,
+			Container: container.Name,
+			State:     container.State,
+			Status:    container.Status,
+		})
+	}
+
+	// 期望状态与滚动更新
+	for _, spec := range specs {
+		if r := s.rollouts.get(spec.Name); r != nil && r.active() {
+			overview.ActiveRollouts = append(overview.ActiveRollouts, spec.Name)
+		}
+		if !spec.Stopped && running[spec.Name] < spec.Replicas {
+			overview.DegradedServices = append(overview.DegradedServices, &models.DegradedService{
+				Service: spec.Name,
+				Desired: spec.Replicas,
+				Running: running[spec.Name],
+			})
+		}
+	}
+
+	// 端口与代理
+	for _, record := range ports {
+		if record.Reserved {
+			overview.PortsReserved++
+		} else {
+			overview.PortsInUse++
+		}
+	}
+	overview.ProxiesRunning = len(s.PortManager.ProxyPorts())
+	for name, service := range services {
+		if service.PublicPort > 0 && running[name] > 0 && !s.PortManager.HasPortProxy(service.PublicPort) {
+			overview.MissingProxies = append(overview.MissingProxies, service.PublicPort)
+		}
+	}
+	sort.Ints(overview.MissingProxies)
+
+	events, _, err := s.store.ListEvents(store.EventQuery{
+		Types: overviewFailureEvents,
+		Since: time.Now().Add(-overviewFailureWindow),
+		Limit: overviewFailureLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	overview.RecentFailures = make([]*models.Event, 0, len(events))
+	for _, event := range events {
+		overview.RecentFailures = append(overview.RecentFailures, toEvent(event))
+	}
+	return overview, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// defaultAutoPortRange 自动分配公共端口的默认范围，与容器内部端口（internal_port_start 起）错开
+const defaultAutoPortRange = "20000-29999"
+
+// nextFreePort 返回 [low, high] 中第一个未被占用且可用的端口，没有时返回 0
+func nextFreePort(low, high int, used map[int]bool, usable func(port int) bool) int {
+	for port := low; port <= high; port++ {
+		if used[port] || !usable(port) {
+			continue
+		}
+		return port
+	}
+	return 0
+}
+
+// autoPortRange 读取自动分配公共端口的范围 ports.auto_range
+func autoPortRange() (string, int, int, error) {
+	autoRange := utils.ConfGetString("ports.auto_range")
+	if autoRange == "" {
+		autoRange = defaultAutoPortRange
+	}
+	low, high, err := parsePortRange(autoRange)
+	if err != nil {
+		return autoRange, 0, 0, fmt.Errorf("ports.auto_range: %w", err)
+	}
+	return autoRange, low, high, nil
+}
+
+// pickPublicPort 从 ports.auto_range 中为新服务选择公共端口
+// 跳过已分配或预留的端口、系统端口、配额不允许的端口以及主机上已被占用的端口；选中的端口在部署时才登记
+func (s *Service) pickPublicPort(ctx context.IContext, name, owner string) (int, error) {
+	autoRange, low, high, err := autoPortRange()
+	if err != nil {
+		return 0, err
+	}
+
+	used := make(map[int]bool)
+	records, err := s.store.ListPorts(false)
+	if err != nil {
+		return 0, err
+	}
+	for _, record := range records {
+		used[record.Port] = true
+	}
+	for _, service := range s.ListServices(ctx) {
+		used[service.PublicPort] = true
+	}
+
+	// 配额限定了端口范围时只在允许的范围内选择
+	var quotaRanges [][]string
+	namespace, _ := models.SplitServiceName(name)
+	if limit, ok := namespaceQuotas()[namespace]; ok {
+		quotaRanges = append(quotaRanges, limit.PortRanges)
+	}
+	if limit, ok := tokenQuotas()[owner]; ok && owner != "" {
+		quotaRanges = append(quotaRanges, limit.PortRanges)
+	}
+
+	minPort := utils.ConfGetIntDefault("ports.min_public_port", defaultMinPublicPort)
+	systemPorts := igo.App.Conf.GetIntSlice("ports.system_ports")
+	apiPort := apiListenPort()
+	port := nextFreePort(low, high, used, func(port int) bool {
+		if systemPortReason(port, minPort, systemPorts, apiPort) != "" {
+			return false
+		}
+		for _, ranges := range quotaRanges {
+			if !portAllowed(port, ranges) {
+				return false
+			}
+		}
+		return !s.PortManager.HasPortProxy(port) && !s.dockerClient.IsPortOccupied(port)
+	})
+	if port == 0 {
+		return 0, utils.NewError(utils.CodePortInUse, "no free public port in ports.auto_range %s", autoRange)
+	}
+	return port, nil
+}
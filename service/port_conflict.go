@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/utils"
+)
+
+// portOwner 描述占用主机端口的对象：OneDock 端口代理、Docker 容器或主机进程，未被占用时返回空字符串
+func (s *Service) portOwner(ctx context.IContext, port int) string {
+	if s.PortManager.HasPortProxy(port) {
+		return "a OneDock port proxy"
+	}
+	if !s.dockerClient.IsPortOccupied(port) {
+		return ""
+	}
+	return s.hostPortOwner(ctx, port)
+}
+
+// hostPortOwner 查找已被占用的主机端口属于哪个容器或进程，查不到时返回 "another process"
+func (s *Service) hostPortOwner(ctx context.IContext, port int) string {
+	name, err := s.dockerClient.HostPortContainer(ctx, port)
+	if err != nil {
+		log.Warn("PortConflict", log.Any("Error", err), log.Any("PublicPort", port), log.Any("Message", "查询发布端口的容器失败"))
+	}
+	if name != "" {
+		return fmt.Sprintf("docker container %s", name)
+	}
+	if process := portOwnerProcess(port); process != "" {
+		return fmt.Sprintf("process %s", process)
+	}
+	return "another process"
+}
+
+// checkPortConflict 部署前检查公共端口在主机范围内是否冲突：已被其他受管服务使用，或被容器、主机进程占用
+func (s *Service) checkPortConflict(ctx context.IContext, name string, port int) error {
+	for _, service := range s.ListServices(ctx) {
+		if service.PublicPort == port && service.Name != name {
+			return utils.NewError(utils.CodePortInUse, "public port %d is already used by service %s", port, service.Name)
+		}
+	}
+	if owner := s.portOwner(ctx, port); owner != "" {
+		return utils.NewError(utils.CodePortInUse, "public port %d is already in use on the host by %s", port, owner)
+	}
+	return nil
+}
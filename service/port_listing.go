@@ -0,0 +1,113 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// freePortRanges 返回 [low, high] 中连续的空闲端口范围
+func freePortRanges(low, high int, free func(port int) bool) []models.PortRange {
+	ranges := make([]models.PortRange, 0)
+	start := 0
+	for port := low; port <= high+1; port++ {
+		if port <= high && free(port) {
+			if start == 0 {
+				start = port
+			}
+			continue
+		}
+		if start != 0 {
+			ranges = append(ranges, models.PortRange{Start: start, End: port - 1})
+			start = 0
+		}
+	}
+	return ranges
+}
+
+// ListHostPorts 列出使用中的主机端口：服务的公共端口、副本映射端口和预留端口，以及自动分配范围内的空闲范围
+func (s *Service) ListHostPorts(ctx context.IContext) (*models.PortListing, error) {
+	containers, err := s.dockerClient.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.store.ListPorts(false)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := make([]models.HostPort, 0)
+	public := make(map[int]bool)
+	for _, service := range s.ListServices(ctx) {
+		if service.PublicPort <= 0 {
+			continue
+		}
+		public[service.PublicPort] = true
+		ports = append(ports, models.HostPort{
+			Port:    service.PublicPort,
+			Type:    models.PortTypePublic,
+			Service: service.Name,
+			Proxy:   s.PortManager.HasPortProxy(service.PublicPort),
+		})
+	}
+	for _, record := range records {
+		switch {
+		case record.Reserved:
+			ports = append(ports, models.HostPort{Port: record.Port, Type: models.PortTypeReserved, Service: record.Service, Note: record.Note})
+		case !public[record.Port]:
+			// 已登记但当前没有副本的服务（如部署中）
+			ports = append(ports, models.HostPort{Port: record.Port, Type: models.PortTypePublic, Service: record.Service})
+		}
+	}
+	for _, container := range containers {
+		nameInfo, err := s.dockerClient.ParseContainerName(container.Name)
+		if err != nil {
+			continue
+		}
+		replica := nameInfo.ReplicaIndex
+		for _, mapping := range container.Ports {
+			port, err := strconv.Atoi(mapping.HostPort)
+			if err != nil || port <= 0 {
+				continue
+			}
+			containerPort := mapping.ContainerPort
+			if mapping.Protocol != "" {
+				containerPort += "/" + mapping.Protocol
+			}
+			ports = append(ports, models.HostPort{
+				Port:          port,
+				Type:          models.PortTypeContainer,
+				Service:       nameInfo.ServiceName,
+				Replica:       &replica,
+				ContainerID:   container.ID[:12],
+				ContainerPort: containerPort,
+			})
+		}
+	}
+	sort.SliceStable(ports, func(i, j int) bool { return ports[i].Port < ports[j].Port })
+
+	listing := &models.PortListing{Ports: ports, Total: len(ports), FreeRanges: make([]models.PortRange, 0)}
+	autoRange, low, high, err := autoPortRange()
+	listing.AutoRange = autoRange
+	if err != nil {
+		return listing, nil
+	}
+	used := make(map[int]bool, len(ports))
+	for _, port := range ports {
+		used[port.Port] = true
+	}
+	minPort := utils.ConfGetIntDefault("ports.min_public_port", defaultMinPublicPort)
+	systemPorts := igo.App.Conf.GetIntSlice("ports.system_ports")
+	apiPort := apiListenPort()
+	listing.FreeRanges = freePortRanges(low, high, func(port int) bool {
+		return !used[port] && !s.PortManager.HasPortProxy(port) && systemPortReason(port, minPort, systemPorts, apiPort) == ""
+	})
+	for _, r := range listing.FreeRanges {
+		listing.FreeCount += r.End - r.Start + 1
+	}
+	return listing, nil
+}
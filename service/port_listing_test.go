@@ -0,0 +1,25 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestFreePortRanges 测试空闲端口范围的合并
+func TestFreePortRanges(t *testing.T) {
+	used := map[int]bool{20000: true, 20003: true, 20004: true, 20009: true}
+	got := freePortRanges(20000, 20009, func(port int) bool { return !used[port] })
+	want := []models.PortRange{{Start: 20001, End: 20002}, {Start: 20005, End: 20008}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("freePortRanges() = %v, 期望 %v", got, want)
+	}
+
+	if got := freePortRanges(20000, 20002, func(int) bool { return true }); !reflect.DeepEqual(got, []models.PortRange{{Start: 20000, End: 20002}}) {
+		t.Errorf("全部空闲时 freePortRanges() = %v", got)
+	}
+	if got := freePortRanges(20000, 20002, func(int) bool { return false }); len(got) != 0 {
+		t.Errorf("全部占用时 freePortRanges() = %v, 期望为空", got)
+	}
+}
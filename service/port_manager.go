@@ -2,18 +2,25 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	igoContext "github.com/aichy126/igo/context"
 	"github.com/aichy126/igo/log"
 	"github.com/aichy126/igo/util"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -31,7 +38,9 @@ type Backend struct {
 	ContainerMapping *ContainerMapping
 	Proxy            *httputil.ReverseProxy
 	Active           bool
-	Connections      int64
+	Connections      int64 // 正在处理的请求数
+	Requests         int64 // 转发的请求数
+	Errors           int64 // 后端不可达或返回 5xx 的请求数
 	Weight           int
 	LastUsed         time.Time
 }
@@ -46,30 +55,47 @@ type LoadBalancer struct {
 
 // PortProxy 单个端口的代理实例
 type PortProxy struct {
-	publicPort int
-	server     *http.Server
-	proxyType  string // "single" 或 "load_balancer"
-	cancel     context.CancelFunc
-	ctx        context.Context
-	
+	publicPort  int
+	bindAddress string // 监听地址，为空表示监听所有网卡
+	workers     int    // 监听器数量，大于 1 时使用 SO_REUSEPORT
+	server      *http.Server
+	proxyType   string // "single" 或 "load_balancer"
+	service     string // 所属服务
+	cancel      context.CancelFunc
+	ctx         context.Context
+	settings    atomic.Pointer[proxySettings] // 可热加载的配置
+	filter      *requestFilter                // 请求过滤规则，为 nil 表示不过滤
+	mirror      *trafficMirror                // 流量镜像，为 nil 表示不镜像
+	traffic     *portTraffic                  // 请求计数
+
 	// 具体代理实现（二选一）
-	singleProxy *httputil.ReverseProxy
-	balancer    *LoadBalancer
+	single   *Backend
+	balancer *LoadBalancer
 }
 
 // PortProxyManager 端口代理管理器（轻量化）
 type PortProxyManager struct {
 	service *Service
-	proxies map[int]*PortProxy // publicPort -> 独立的端口代理
-	mutex   sync.RWMutex
+	proxies map[int]*PortProxy   // publicPort -> 独立的端口代理
+	traffic map[int]*portTraffic // publicPort -> 请求计数，代理重建后保留
+	// publicPort -> 通过接口设置的负载均衡策略，代理重建和热加载配置后保留
+	strategies map[int]LoadBalanceStrategy
+	// publicPort -> 通过接口停用的后端容器ID，代理重建后保留
+	disabled map[int]map[string]bool
+	mutex    sync.RWMutex
 }
 
 // NewPortManager 创建端口代理管理器
 func NewPortManager(service *Service) *PortProxyManager {
-	return &PortProxyManager{
-		service: service,
-		proxies: make(map[int]*PortProxy),
-	}
+	ppm := &PortProxyManager{
+		service:    service,
+		proxies:    make(map[int]*PortProxy),
+		traffic:    make(map[int]*portTraffic),
+		strategies: make(map[int]LoadBalanceStrategy),
+		disabled:   make(map[int]map[string]bool),
+	}
+	ppm.watchReloadSignal()
+	return ppm
 }
 
 // StartPortProxy 启动端口代理
@@ -88,9 +114,15 @@ func (ppm *PortProxyManager) StartPortProxy(ctx igoContext.IContext, publicPort
 	if err != nil {
 		return fmt.Errorf("failed to create port proxy: %w", err)
 	}
+	proxy.traffic = ppm.trafficFor(publicPort)
 
 	// 启动代理
 	if err := proxy.start(); err != nil {
+		proxy.cancel()
+		// 端口被占用时指明占用者，而不是只返回 bind 错误
+		if errors.Is(err, syscall.EADDRINUSE) {
+			return utils.NewError(utils.CodePortInUse, "failed to start port proxy: public port %d is in use by %s: %w", publicPort, ppm.service.hostPortOwner(ctx, publicPort), err)
+		}
 		return fmt.Errorf("failed to start port proxy: %w", err)
 	}
 
@@ -98,6 +130,7 @@ func (ppm *PortProxyManager) StartPortProxy(ctx igoContext.IContext, publicPort
 	ppm.proxies[publicPort] = proxy
 
 	log.Info("PortProxyManager", log.Any("Message", fmt.Sprintf("Port proxy started for port %d", publicPort)))
+	ppm.service.publishProxyEvent(proxy.service, models.EventProxyStarted, fmt.Sprintf("port proxy on %d started with %d backends", publicPort, proxy.backendCount()))
 	return nil
 }
 
@@ -119,9 +152,28 @@ func (ppm *PortProxyManager) createPortProxy(ctx igoContext.IContext, publicPort
 	proxyCtx, cancel := context.WithCancel(context.Background())
 
 	proxy := &PortProxy{
-		publicPort: publicPort,
-		cancel:     cancel,
-		ctx:        proxyCtx,
+		publicPort:  publicPort,
+		service:     mappings[0].ServiceName,
+		bindAddress: resolveBindAddress(mappings[0]),
+		workers:     resolveProxyWorkers(mappings[0]),
+		cancel:      cancel,
+		ctx:         proxyCtx,
+	}
+	proxy.settings.Store(loadProxySettings())
+
+	// 编译请求过滤规则
+	if rules := mappings[0].RequestRules; len(rules) > 0 {
+		filter, err := compileRequestRules(rules)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to compile request rules: %w", err)
+		}
+		proxy.filter = filter
+	}
+
+	// 流量镜像
+	if mirror := mappings[0].Mirror; mirror != nil && mirror.Service != "" && mirror.Percent > 0 {
+		proxy.mirror = ppm.newTrafficMirror(mirror)
 	}
 
 	// 根据容器数量决定代理类型
@@ -133,7 +185,13 @@ func (ppm *PortProxyManager) createPortProxy(ctx igoContext.IContext, publicPort
 			cancel()
 			return nil, fmt.Errorf("failed to create single proxy: %w", err)
 		}
-		proxy.singleProxy = singleProxy
+		proxy.single = &Backend{
+			ContainerMapping: mappings[0],
+			Proxy:            singleProxy,
+			Active:           true,
+			Weight:           100,
+			LastUsed:         time.Now(),
+		}
 	} else {
 		// 多副本：创建负载均衡器
 		proxy.proxyType = "load_balancer"
@@ -144,13 +202,23 @@ func (ppm *PortProxyManager) createPortProxy(ctx igoContext.IContext, publicPort
 		}
 		proxy.balancer = balancer
 	}
+	ppm.applyDisabledBackends(proxy)
 
 	return proxy, nil
 }
 
+// resolveBindAddress 确定代理监听地址
+// 优先使用服务级配置（容器标签），其次使用全局配置 proxy.bind_address
+func resolveBindAddress(mapping *ContainerMapping) string {
+	if mapping != nil && mapping.BindAddress != "" {
+		return mapping.BindAddress
+	}
+	return util.ConfGetString("proxy.bind_address")
+}
+
 // createSingleProxy 创建单副本代理
 func (ppm *PortProxyManager) createSingleProxy(mapping *ContainerMapping) (*httputil.ReverseProxy, error) {
-	targetURL := fmt.Sprintf("http://localhost:%d", mapping.ContainerPort)
+	targetURL := backendURL(mapping.ContainerPort)
 	target, err := url.Parse(targetURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse target URL: %w", err)
@@ -170,12 +238,8 @@ func (ppm *PortProxyManager) createSingleProxy(mapping *ContainerMapping) (*http
 
 // createLoadBalancer 创建负载均衡器
 func (ppm *PortProxyManager) createLoadBalancer(mappings []*ContainerMapping) (*LoadBalancer, error) {
-	// 获取负载均衡策略
-	strategyConfig := util.ConfGetString("container.load_balance_strategy")
-	strategy := LoadBalanceStrategy(strategyConfig)
-	if strategy == "" {
-		strategy = RoundRobin // 默认策略
-	}
+	// 获取负载均衡策略，优先使用通过接口为端口设置的策略
+	strategy := ppm.strategyFor(mappings[0].PublicPort, loadProxySettings())
 
 	// 创建负载均衡器
 	balancer := &LoadBalancer{
@@ -202,7 +266,7 @@ func (ppm *PortProxyManager) createLoadBalancer(mappings []*ContainerMapping) (*
 
 // createBackend 创建后端服务器
 func (ppm *PortProxyManager) createBackend(mapping *ContainerMapping) (*Backend, error) {
-	targetURL := fmt.Sprintf("http://localhost:%d", mapping.ContainerPort)
+	targetURL := backendURL(mapping.ContainerPort)
 	target, err := url.Parse(targetURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse target URL: %w", err)
@@ -230,10 +294,25 @@ func (ppm *PortProxyManager) createBackend(mapping *ContainerMapping) (*Backend,
 func (pp *PortProxy) start() error {
 	router := gin.New()
 	router.Use(gin.Recovery())
+	if pp.filter != nil {
+		router.Use(pp.filter.middleware(pp.publicPort))
+	}
+	if pp.traffic != nil {
+		router.Use(pp.traffic.middleware())
+	}
+	if pp.mirror != nil {
+		router.Use(pp.mirror.middleware(pp.publicPort))
+	}
 
 	// 根据代理类型设置路由
 	if pp.proxyType == "single" {
-		router.NoRoute(gin.WrapH(pp.singleProxy))
+		router.NoRoute(func(c *gin.Context) {
+			if !pp.single.Active {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "No available backends"})
+				return
+			}
+			pp.single.serve(c)
+		})
 		log.Info("PortProxy", log.Any("Message", fmt.Sprintf("Starting single proxy server for port %d", pp.publicPort)))
 	} else {
 		router.NoRoute(func(c *gin.Context) {
@@ -244,45 +323,76 @@ func (pp *PortProxy) start() error {
 				return
 			}
 
-			// 增加连接计数
-			atomic.AddInt64(&backend.Connections, 1)
-			defer atomic.AddInt64(&backend.Connections, -1)
-
-			backend.LastUsed = time.Now()
 			log.Debug("PortProxy", log.Any("Message", fmt.Sprintf("Load balancing request: %s %s -> container %d", c.Request.Method, c.Request.URL.Path, backend.ContainerMapping.ContainerPort)))
-
-			// 代理请求
-			backend.Proxy.ServeHTTP(c.Writer, c.Request)
+			backend.serve(c)
 		})
 		log.Info("PortProxy", log.Any("Message", fmt.Sprintf("Starting load balancer server for port %d with %d backends", pp.publicPort, len(pp.balancer.backends))))
 	}
 
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", pp.publicPort),
-		Handler:      router,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
+	// 读写超时在 withDeadlines 中按请求设置，以支持热加载
+	server := pp.newServer(router)
+
+	// 同步创建监听器，确保端口绑定失败能返回给调用方
+	listeners, err := pp.listen()
+	if err != nil {
+		return err
 	}
 
 	pp.server = server
 
-	// 启动服务器
-	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Error("PortProxy", log.Any("Error", fmt.Sprintf("Server error for port %d: %v", pp.publicPort, err)))
-		}
-	}()
+	// 启动服务器，每个监听器一个 accept 循环
+	for _, listener := range listeners {
+		go func(l net.Listener) {
+			if err := server.Serve(l); err != nil && err != http.ErrServerClosed {
+				log.Error("PortProxy", log.Any("Error", fmt.Sprintf("Server error for port %d: %v", pp.publicPort, err)))
+			}
+		}(listener)
+	}
 
 	return nil
 }
 
+// serve 将请求转发给后端，并记录连接数、请求数和失败数
+func (b *Backend) serve(c *gin.Context) {
+	atomic.AddInt64(&b.Connections, 1)
+	defer atomic.AddInt64(&b.Connections, -1)
+	atomic.AddInt64(&b.Requests, 1)
+	b.LastUsed = time.Now()
+
+	b.Proxy.ServeHTTP(c.Writer, c.Request)
+	if c.Writer.Status() >= http.StatusInternalServerError {
+		atomic.AddInt64(&b.Errors, 1)
+	}
+}
+
+// listenAddr 返回代理监听地址，格式为 host:port
+func (pp *PortProxy) listenAddr() string {
+	return net.JoinHostPort(pp.bindAddress, strconv.Itoa(pp.publicPort))
+}
+
+// backendCount 代理转发的后端数量
+func (pp *PortProxy) backendCount() int {
+	if pp.balancer != nil {
+		pp.balancer.mutex.RLock()
+		defer pp.balancer.mutex.RUnlock()
+		return len(pp.balancer.backends)
+	}
+	return 1
+}
+
 // stop 停止端口代理
 func (pp *PortProxy) stop() error {
-	if pp.server != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pp.drain(ctx)
+	return nil
+}
 
-		if err := pp.server.Shutdown(ctx); err != nil {
+// drain 停止接收新连接，等待进行中的请求完成或 ctx 超时
+func (pp *PortProxy) drain(ctx context.Context) error {
+	var err error
+	if pp.server != nil {
+		if err = pp.server.Shutdown(ctx); err != nil {
 			log.Error("PortProxy", log.Any("Error", fmt.Sprintf("Failed to shutdown server for port %d: %v", pp.publicPort, err)))
 		}
 	}
@@ -293,7 +403,27 @@ func (pp *PortProxy) stop() error {
 	}
 
 	log.Info("PortProxy", log.Any("Message", fmt.Sprintf("Port proxy stopped for port %d", pp.publicPort)))
-	return nil
+	return err
+}
+
+// HasPortProxy 端口代理是否在运行
+func (ppm *PortProxyManager) HasPortProxy(publicPort int) bool {
+	ppm.mutex.RLock()
+	defer ppm.mutex.RUnlock()
+	_, exists := ppm.proxies[publicPort]
+	return exists
+}
+
+// ProxyPorts 运行中的端口代理监听的公共端口
+func (ppm *PortProxyManager) ProxyPorts() []int {
+	ppm.mutex.RLock()
+	defer ppm.mutex.RUnlock()
+	ports := make([]int, 0, len(ppm.proxies))
+	for port := range ppm.proxies {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports
 }
 
 // StopPortProxy 停止端口代理
@@ -316,6 +446,7 @@ func (ppm *PortProxyManager) StopPortProxy(publicPort int) error {
 	delete(ppm.proxies, publicPort)
 
 	log.Info("PortProxyManager", log.Any("Message", fmt.Sprintf("Port proxy stopped for port %d", publicPort)))
+	ppm.service.publishProxyEvent(proxy.service, models.EventProxyStopped, fmt.Sprintf("port proxy on %d stopped", publicPort))
 	return nil
 }
 
@@ -333,70 +464,26 @@ func (ppm *PortProxyManager) UpdatePortProxy(ctx igoContext.IContext, publicPort
 	return ppm.StartPortProxy(ctx, publicPort)
 }
 
-// GetProxyStats 获取代理统计信息
-func (ppm *PortProxyManager) GetProxyStats(ctx igoContext.IContext) map[string]interface{} {
-	ppm.mutex.RLock()
-	defer ppm.mutex.RUnlock()
-
-	singleCount := 0
-	balancerCount := 0
-
-	proxyDetails := make([]map[string]interface{}, 0)
-
-	for port, proxy := range ppm.proxies {
-		detail := map[string]interface{}{
-			"public_port": port,
-			"server_addr": fmt.Sprintf(":%d", port),
-			"type":        proxy.proxyType,
-		}
-
-		if proxy.proxyType == "single" {
-			singleCount++
-		} else {
-			balancerCount++
-			if proxy.balancer != nil {
-				detail["strategy"] = proxy.balancer.strategy
-				detail["backend_count"] = len(proxy.balancer.backends)
-
-				backends := make([]map[string]interface{}, 0)
-				for _, backend := range proxy.balancer.backends {
-					backends = append(backends, map[string]interface{}{
-						"container_id":   backend.ContainerMapping.ContainerID,
-						"container_port": backend.ContainerMapping.ContainerPort,
-						"active":         backend.Active,
-						"connections":    atomic.LoadInt64(&backend.Connections),
-						"weight":         backend.Weight,
-						"last_used":      backend.LastUsed,
-					})
-				}
-				detail["backends"] = backends
-			}
-		}
-
-		proxyDetails = append(proxyDetails, detail)
-	}
-
-	stats := map[string]interface{}{
-		"total_proxies":  len(ppm.proxies),
-		"single_proxies": singleCount,
-		"load_balancers": balancerCount,
-		"proxy_details":  proxyDetails,
-	}
-
-	return stats
-}
-
-// Shutdown 关闭所有代理
-func (ppm *PortProxyManager) Shutdown() error {
+// Shutdown 并行排空所有代理，等待进行中的请求完成或 ctx 超时
+func (ppm *PortProxyManager) Shutdown(ctx context.Context) error {
 	ppm.mutex.Lock()
 	defer ppm.mutex.Unlock()
 
 	var errors []error
+	var errMutex sync.Mutex
+	var wg sync.WaitGroup
 	for port, proxy := range ppm.proxies {
-		if err := proxy.stop(); err != nil {
-			errors = append(errors, fmt.Errorf("failed to stop proxy for port %d: %w", port, err))
-		}
+		wg.Add(1)
+		go func(port int, proxy *PortProxy) {
+			defer wg.Done()
+			if err := proxy.drain(ctx); err != nil {
+				errMutex.Lock()
+				errors = append(errors, fmt.Errorf("failed to drain proxy for port %d: %w", port, err))
+				errMutex.Unlock()
+			}
+		}(port, proxy)
 	}
+	wg.Wait()
 
 	// 清理所有代理
 	ppm.proxies = make(map[int]*PortProxy)
@@ -496,4 +583,4 @@ func (lb *LoadBalancer) selectWeighted(backends []*Backend) *Backend {
 	}
 
 	return backends[0]
-}
\ No newline at end of file
+}
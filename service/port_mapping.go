@@ -1,11 +1,12 @@
 package service
 
 import (
-	"strconv"
+	"fmt"
 
 	"github.com/aichy126/igo/context"
 	"github.com/aichy126/igo/util"
 	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
 )
 
 // ContainerMapping 容器映射信息
@@ -14,14 +15,24 @@ type ContainerMapping struct {
 	ContainerPort int    `json:"container_port"` // 容器映射端口
 	ContainerID   string `json:"container_id"`   // 容器ID
 	ServiceName   string `json:"service_name"`   // 服务名称
+	BindAddress   string `json:"bind_address"`   // 代理监听地址（来自容器标签）
+	ProxyWorkers  int    `json:"proxy_workers"`  // 代理监听器数量（来自容器标签）
+
+	RequestRules []models.RequestRule `json:"request_rules,omitempty"` // 请求过滤规则（来自容器标签）
+	Mirror       *models.MirrorConfig `json:"mirror,omitempty"`        // 流量镜像配置（来自容器标签）
 }
 
 //PortMapping
 
+// mappingCacheKey 端口映射的缓存键，带容器名称前缀，避免共用 Redis 的不同 OneDock 部署互相覆盖
+func mappingCacheKey(publicPort int) string {
+	return fmt.Sprintf("%s:%s:%d", utils.ConfGetString("container.prefix"), models.ContainerMappingKey, publicPort)
+}
+
 // GetContainerMapping 获取端口的所有容器映射
 // 缓存未命中时会从 Docker 实时查询并重新构建缓存
 func (s *Service) GetContainerMapping(ctx context.IContext, publicPort int) ([]*ContainerMapping, error) {
-	cacheKey := models.ContainerMappingKey + ":" + strconv.Itoa(publicPort)
+	cacheKey := mappingCacheKey(publicPort)
 
 	// 尝试从缓存获取
 	var cachedList []*ContainerMapping
@@ -48,8 +59,8 @@ func (s *Service) GetContainerMapping(ctx context.IContext, publicPort int) ([]*
 // DelContainerMapping 删除端口映射缓存
 // 当容器被删除或服务停止时调用此方法清理缓存
 func (s *Service) DelContainerMapping(ctx context.IContext, publicPort int) error {
-	cacheKey := models.ContainerMappingKey + ":" + strconv.Itoa(publicPort)
-	return s.Cache.Del(ctx, cacheKey)
+	cacheKey := mappingCacheKey(publicPort)
+	return s.Cache.Delete(ctx, cacheKey)
 }
 
 // rebuildContainerMappingFromDocker 从 Docker 实时查询重建端口映射
@@ -63,6 +74,7 @@ func (s *Service) rebuildContainerMappingFromDocker(ctx context.IContext, public
 	}
 
 	mappings := make([]*ContainerMapping, 0)
+	serving := s.servingFilter()
 
 	// 遍历容器，查找匹配指定公共端口的容器
 	for _, container := range allContainers {
@@ -77,12 +89,20 @@ func (s *Service) rebuildContainerMappingFromDocker(ctx context.IContext, public
 		if containerNameInfo.PublicPort != publicPort {
 			continue
 		}
+		// 蓝绿发布切换前后只转发到其中一组副本
+		if !serving(containerNameInfo.ServiceName, container) {
+			continue
+		}
 
 		mapping := &ContainerMapping{
 			PublicPort:    publicPort,
 			ContainerPort: containerNameInfo.ContainerPort,
 			ContainerID:   container.ID,
 			ServiceName:   containerNameInfo.ServiceName,
+			BindAddress:   container.Labels[s.dockerClient.LabelKey("bind_address")],
+			ProxyWorkers:  utils.StringToInt(container.Labels[s.dockerClient.LabelKey("proxy_workers")]),
+			RequestRules:  s.dockerClient.ParseRequestRules(container.Labels),
+			Mirror:        s.dockerClient.ParseMirrorConfig(container.Labels),
 		}
 
 		mappings = append(mappings, mapping)
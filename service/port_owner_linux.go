@@ -0,0 +1,71 @@
+//go:build linux
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tcpStateListen /proc/net/tcp 中 LISTEN 状态的编码
+const tcpStateListen = "0A"
+
+// listeningInodes 从 /proc/net/tcp(6) 的内容中找出监听指定端口的 socket inode
+func listeningInodes(content string, port int) []string {
+	var inodes []string
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		if len(fields) < 10 || fields[3] != tcpStateListen {
+			continue
+		}
+		colon := strings.LastIndex(fields[1], ":")
+		if colon < 0 {
+			continue
+		}
+		localPort, err := strconv.ParseInt(fields[1][colon+1:], 16, 32)
+		if err != nil || int(localPort) != port {
+			continue
+		}
+		if fields[9] != "0" {
+			inodes = append(inodes, fields[9])
+		}
+	}
+	return inodes
+}
+
+// portOwnerProcess 查找监听指定端口的主机进程，返回 "进程名 (pid N)"，无法确定时返回空字符串
+// 读取其他用户进程的 fd 需要相应权限，权限不足时只能返回空字符串
+func portOwnerProcess(port int) string {
+	sockets := make(map[string]bool)
+	for _, file := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, inode := range listeningInodes(string(content), port) {
+			sockets[fmt.Sprintf("socket:[%s]", inode)] = true
+		}
+	}
+	if len(sockets) == 0 {
+		return ""
+	}
+
+	fds, _ := filepath.Glob("/proc/[0-9]*/fd/*")
+	for _, fd := range fds {
+		link, err := os.Readlink(fd)
+		if err != nil || !sockets[link] {
+			continue
+		}
+		pid := strings.Split(fd, "/")[2]
+		comm, err := os.ReadFile(filepath.Join("/proc", pid, "comm"))
+		if err != nil {
+			return fmt.Sprintf("pid %s", pid)
+		}
+		return fmt.Sprintf("%s (pid %s)", strings.TrimSpace(string(comm)), pid)
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+//go:build linux
+
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestListeningInodes 测试从 /proc/net/tcp 中找出监听端口的 socket
+func TestListeningInodes(t *testing.T) {
+	content := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:2453 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 31337 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:2453 0100007F:C350 01 00000000:00000000 00:00000000 00000000     0        0 40001 1 0000000000000000 20 4 30 10 -1
+   2: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 12345 1 0000000000000000 100 0 0 10 0
+`
+	if inodes := listeningInodes(content, 9299); !reflect.DeepEqual(inodes, []string{"31337"}) {
+		t.Errorf("listeningInodes(9299) = %v, 期望 [31337]", inodes)
+	}
+	if inodes := listeningInodes(content, 8080); !reflect.DeepEqual(inodes, []string{"12345"}) {
+		t.Errorf("listeningInodes(8080) = %v, 期望 [12345]", inodes)
+	}
+	if inodes := listeningInodes(content, 9300); len(inodes) != 0 {
+		t.Errorf("listeningInodes(9300) = %v, 期望为空", inodes)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package service
+
+// portOwnerProcess 当前平台不支持查找占用端口的进程，始终返回空字符串
+func portOwnerProcess(port int) string {
+	return ""
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// defaultMinPublicPort 默认允许的最小公共端口，低于该值的为系统端口
+const defaultMinPublicPort = 1024
+
+// systemPortReason 端口属于系统端口时返回原因，否则返回空字符串
+// 系统端口包括低于 minPort 的端口、systemPorts 中列出的端口以及 OneDock API 自身的端口
+func systemPortReason(port, minPort int, systemPorts []int, apiPort int) string {
+	if port < 1 || port > 65535 {
+		return fmt.Sprintf("public port %d is out of range", port)
+	}
+	if port < minPort {
+		return fmt.Sprintf("public port %d is below ports.min_public_port %d", port, minPort)
+	}
+	if port == apiPort {
+		return fmt.Sprintf("public port %d is used by the OneDock API", port)
+	}
+	for _, system := range systemPorts {
+		if port == system {
+			return fmt.Sprintf("public port %d is listed in ports.system_ports", port)
+		}
+	}
+	return ""
+}
+
+// apiListenPort OneDock API 监听的端口，无法解析时返回 0
+func apiListenPort() int {
+	_, value, err := net.SplitHostPort(utils.ConfGetString("local.address"))
+	if err != nil {
+		return 0
+	}
+	port, _ := strconv.Atoi(value)
+	return port
+}
+
+// checkSystemPort 端口为系统端口时返回错误
+func checkSystemPort(port int) error {
+	reason := systemPortReason(port,
+		utils.ConfGetIntDefault("ports.min_public_port", defaultMinPublicPort),
+		igo.App.Conf.GetIntSlice("ports.system_ports"),
+		apiListenPort())
+	if reason != "" {
+		return fmt.Errorf("%s", reason)
+	}
+	return nil
+}
+
+// checkPortRegistry 检查端口能否分配给服务：不能是系统端口，不能已分配给其他服务或为其他服务预留
+func (s *Service) checkPortRegistry(name string, port int) error {
+	if err := checkSystemPort(port); err != nil {
+		return err
+	}
+	record, err := s.store.GetPort(port)
+	if err != nil || record == nil {
+		return err
+	}
+	if !record.Reserved && record.Service != name {
+		return utils.NewError(utils.CodePortInUse, "public port %d is allocated to service %s", port, record.Service)
+	}
+	if record.Reserved && record.Service != "" && record.Service != name {
+		return utils.NewError(utils.CodePortInUse, "public port %d is reserved for service %s", port, record.Service)
+	}
+	return nil
+}
+
+// syncPortRegistry 启动时登记已存在服务的公共端口，兼容引入端口登记前部署的服务
+func (s *Service) syncPortRegistry() {
+	for _, service := range s.ListServices(context.Background()) {
+		if service.PublicPort <= 0 {
+			continue
+		}
+		if err := s.store.AllocatePort(service.PublicPort, service.Name, "system"); err != nil {
+			log.Warn("PortRegistry", log.Any("Error", err), log.Any("ServiceName", service.Name), log.Any("PublicPort", service.PublicPort), log.Any("Message", "登记服务公共端口失败"))
+		}
+	}
+}
+
+// releasePorts 释放服务的公共端口，部署失败时调用
+func (s *Service) releasePorts(name string) {
+	if err := s.store.ReleasePorts(name); err != nil {
+		log.Error("PortRegistry", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "释放公共端口失败"))
+	}
+}
+
+func toPortReservation(record *store.PortRecord) *models.PortReservation {
+	return &models.PortReservation{
+		Port:      record.Port,
+		Service:   record.Service,
+		Note:      record.Note,
+		Actor:     record.Actor,
+		CreatedAt: record.CreatedAt,
+	}
+}
+
+// ReservePort 预留公共端口，预留后其他服务不能部署到该端口
+func (s *Service) ReservePort(ctx context.IContext, req *models.PortReservationRequest) (*models.PortReservation, error) {
+	if err := checkSystemPort(req.Port); err != nil {
+		return nil, err
+	}
+	for _, service := range s.ListServices(ctx) {
+		if service.PublicPort == req.Port {
+			return nil, utils.NewError(utils.CodePortInUse, "public port %d is already used by service %s", req.Port, service.Name)
+		}
+	}
+
+	record := &store.PortRecord{
+		Port:    req.Port,
+		Service: req.Service,
+		Note:    req.Note,
+		Actor:   actorFromContext(ctx),
+	}
+	if err := s.store.ReservePort(record); err != nil {
+		return nil, err
+	}
+	log.Info("PortRegistry", log.Any("PublicPort", req.Port), log.Any("ServiceName", req.Service), log.Any("Actor", record.Actor), log.Any("Message", "公共端口已预留"))
+	return toPortReservation(record), nil
+}
+
+// ListPortReservations 列出全部端口预留
+func (s *Service) ListPortReservations(ctx context.IContext) ([]*models.PortReservation, error) {
+	records, err := s.store.ListPorts(true)
+	if err != nil {
+		return nil, err
+	}
+	reservations := make([]*models.PortReservation, 0, len(records))
+	for _, record := range records {
+		reservations = append(reservations, toPortReservation(record))
+	}
+	return reservations, nil
+}
+
+// DeletePortReservation 取消端口预留
+func (s *Service) DeletePortReservation(ctx context.IContext, port int) error {
+	exists, err := s.store.DeletePortReservation(port)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("public port %d is not reserved", port)
+	}
+	log.Info("PortRegistry", log.Any("PublicPort", port), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "端口预留已取消"))
+	return nil
+}
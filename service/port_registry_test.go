@@ -0,0 +1,34 @@
+package service
+
+import "testing"
+
+// TestSystemPortReason 测试系统端口判断
+func TestSystemPortReason(t *testing.T) {
+	cases := map[int]bool{
+		0:     true,
+		80:    true,
+		1024:  false,
+		8801:  true,
+		9203:  false,
+		22222: true,
+		70000: true,
+	}
+	for port, system := range cases {
+		reason := systemPortReason(port, 1024, []int{22222}, 8801)
+		if (reason != "") != system {
+			t.Errorf("systemPortReason(%d) = %q, 期望系统端口: %v", port, reason, system)
+		}
+	}
+}
+
+// TestNextFreePort 测试自动分配时跳过已占用和不可用的端口
+func TestNextFreePort(t *testing.T) {
+	used := map[int]bool{20000: true, 20002: true}
+	usable := func(port int) bool { return port != 20001 }
+	if port := nextFreePort(20000, 20005, used, usable); port != 20003 {
+		t.Errorf("nextFreePort = %d, 期望 20003", port)
+	}
+	if port := nextFreePort(20000, 20002, used, usable); port != 0 {
+		t.Errorf("nextFreePort = %d, 范围内没有可用端口时期望 0", port)
+	}
+}
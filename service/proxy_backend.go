@@ -0,0 +1,92 @@
+package service
+
+import (
+	igoContext "github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// backends 代理的全部后端
+func (pp *PortProxy) backends() []*Backend {
+	if pp.single != nil {
+		return []*Backend{pp.single}
+	}
+	if pp.balancer != nil {
+		return pp.balancer.backends
+	}
+	return nil
+}
+
+// setBackendActive 设置后端是否参与负载均衡，找不到容器对应的后端时返回 false
+func (pp *PortProxy) setBackendActive(containerID string, active bool) bool {
+	if pp.balancer != nil {
+		pp.balancer.mutex.Lock()
+		defer pp.balancer.mutex.Unlock()
+	}
+	for _, backend := range pp.backends() {
+		if backend.ContainerMapping.ContainerID == containerID {
+			backend.Active = active
+			return true
+		}
+	}
+	return false
+}
+
+// applyDisabledBackends 代理重建后恢复停用的后端，并清除已不存在的容器；调用方需持有 ppm.mutex
+func (ppm *PortProxyManager) applyDisabledBackends(proxy *PortProxy) {
+	disabled := ppm.disabled[proxy.publicPort]
+	if len(disabled) == 0 {
+		return
+	}
+	current := make(map[string]bool, len(disabled))
+	for containerID := range disabled {
+		if proxy.setBackendActive(containerID, false) {
+			current[containerID] = true
+		}
+	}
+	if len(current) == 0 {
+		delete(ppm.disabled, proxy.publicPort)
+		return
+	}
+	ppm.disabled[proxy.publicPort] = current
+}
+
+// SetReplicaActive 停用或启用副本在端口代理中的后端，不影响容器本身
+// 停用的副本不再接收新请求，正在处理的请求不受影响；副本重建后恢复为启用
+func (ppm *PortProxyManager) SetReplicaActive(ctx igoContext.IContext, name string, index int, active bool) (*models.ServiceProxyStats, error) {
+	service := ppm.service.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	if service.PublicPort == 0 {
+		return nil, utils.NewError(utils.CodeConflict, "service %s has no public port", name)
+	}
+	container, err := ppm.service.replicaContainer(ctx, name, index)
+	if err != nil {
+		return nil, err
+	}
+
+	ppm.mutex.Lock()
+	disabled := ppm.disabled[service.PublicPort]
+	if !active {
+		if disabled == nil {
+			disabled = make(map[string]bool)
+			ppm.disabled[service.PublicPort] = disabled
+		}
+		disabled[container.ID] = true
+	} else if disabled != nil {
+		delete(disabled, container.ID)
+		if len(disabled) == 0 {
+			delete(ppm.disabled, service.PublicPort)
+		}
+	}
+	if proxy, exists := ppm.proxies[service.PublicPort]; exists {
+		proxy.setBackendActive(container.ID, active)
+	}
+	ppm.mutex.Unlock()
+
+	log.Info("PortProxyManager", log.Any("ServiceName", name), log.Any("Replica", index), log.Any("ContainerID", container.ID[:12]),
+		log.Any("Active", active), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "副本后端状态已切换"))
+	return ppm.ServiceProxyStats(ctx, name)
+}
@@ -0,0 +1,32 @@
+package service
+
+import "testing"
+
+// TestApplyDisabledBackends 测试代理重建后恢复停用的后端，并清除已不存在的容器
+func TestApplyDisabledBackends(t *testing.T) {
+	newBackend := func(id string) *Backend {
+		return &Backend{ContainerMapping: &ContainerMapping{ContainerID: id}, Active: true, Weight: 100}
+	}
+	proxy := &PortProxy{
+		publicPort: 9303,
+		balancer:   &LoadBalancer{strategy: RoundRobin, backends: []*Backend{newBackend("a"), newBackend("b")}},
+	}
+	ppm := &PortProxyManager{disabled: map[int]map[string]bool{9303: {"a": true, "gone": true}}}
+
+	ppm.applyDisabledBackends(proxy)
+	if proxy.balancer.backends[0].Active || !proxy.balancer.backends[1].Active {
+		t.Errorf("后端 a 应停用、b 应启用")
+	}
+	if disabled := ppm.disabled[9303]; len(disabled) != 1 || !disabled["a"] {
+		t.Errorf("disabled = %v, 期望只保留 a", disabled)
+	}
+	for i := 0; i < 4; i++ {
+		if backend := proxy.balancer.SelectBackend(nil); backend == nil || backend.ContainerMapping.ContainerID != "b" {
+			t.Fatalf("SelectBackend() 应只选择启用的后端 b")
+		}
+	}
+
+	if !proxy.setBackendActive("a", true) || proxy.setBackendActive("gone", true) {
+		t.Errorf("setBackendActive() 返回值不符")
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/igo/util"
+)
+
+// IP 模式
+const (
+	IPModeDual = "dual" // 双栈（默认）
+	IPModeIPv4 = "ipv4" // 仅 IPv4
+	IPModeIPv6 = "ipv6" // 仅 IPv6
+)
+
+// listenNetwork 根据 proxy.ip_mode 返回监听使用的网络类型
+func listenNetwork() string {
+	switch strings.ToLower(util.ConfGetString("proxy.ip_mode")) {
+	case IPModeIPv4:
+		return "tcp4"
+	case IPModeIPv6:
+		return "tcp6"
+	default:
+		return "tcp"
+	}
+}
+
+// backendHost 返回访问容器映射端口使用的主机地址
+// 与 Docker 端口绑定的 container.host_ip 保持一致，IPv6 地址如 ::1 也可直接使用
+func backendHost() string {
+	host := util.ConfGetString("container.host_ip")
+	if host == "" {
+		return "127.0.0.1"
+	}
+	return strings.Trim(host, "[]")
+}
+
+// backendURL 返回容器映射端口的代理目标地址
+func backendURL(containerPort int) string {
+	return fmt.Sprintf("http://%s", net.JoinHostPort(backendHost(), strconv.Itoa(containerPort)))
+}
+
+// resolveProxyWorkers 确定代理监听 worker 数量
+// 优先使用服务级配置（容器标签），其次使用全局配置 proxy.workers，最少为 1
+func resolveProxyWorkers(mapping *ContainerMapping) int {
+	workers := 0
+	if mapping != nil {
+		workers = mapping.ProxyWorkers
+	}
+	if workers <= 0 {
+		workers = util.ConfGetInt("proxy.workers")
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	return workers
+}
+
+// listen 按配置的网络类型创建监听器
+// workers 大于 1 时通过 SO_REUSEPORT 创建多个共享端口的监听器，由内核分发新连接，降低单个 accept 循环的竞争
+func (pp *PortProxy) listen() ([]net.Listener, error) {
+	network := listenNetwork()
+
+	if pp.workers <= 1 {
+		listener, err := net.Listen(network, pp.listenAddr())
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s (%s): %w", pp.listenAddr(), network, err)
+		}
+		return []net.Listener{listener}, nil
+	}
+
+	if !reusePortSupported {
+		log.Warn("PortProxy", log.Any("PublicPort", pp.publicPort), log.Any("Workers", pp.workers),
+			log.Any("Message", "当前平台不支持 SO_REUSEPORT，回退为单个监听器"))
+		pp.workers = 1
+		return pp.listen()
+	}
+
+	lc := net.ListenConfig{Control: setReusePort}
+	listeners := make([]net.Listener, 0, pp.workers)
+	for i := 0; i < pp.workers; i++ {
+		listener, err := lc.Listen(context.Background(), network, pp.listenAddr())
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("failed to listen on %s (%s) with SO_REUSEPORT: %w", pp.listenAddr(), network, err)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	igoContext "github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/igo/util"
+	"github.com/aichy126/onedock/utils"
+)
+
+// proxySettings 可热加载的代理配置
+// 监听地址、IP 模式、worker 数量等监听器级别的配置需要重建代理才能生效，不在此列
+type proxySettings struct {
+	Strategy     LoadBalanceStrategy `json:"strategy"`      // 默认负载均衡策略
+	ReadTimeout  time.Duration       `json:"read_timeout"`  // 读取请求超时
+	WriteTimeout time.Duration       `json:"write_timeout"` // 写入响应超时
+	IdleTimeout  time.Duration       `json:"idle_timeout"`  // keep-alive 空闲连接超时，创建代理时应用
+}
+
+// defaultProxyIdleTimeout 默认的 keep-alive 空闲连接超时
+const defaultProxyIdleTimeout = 120 * time.Second
+
+// loadProxySettings 从当前配置读取代理设置
+func loadProxySettings() *proxySettings {
+	settings := &proxySettings{
+		Strategy:     LoadBalanceStrategy(util.ConfGetString("container.load_balance_strategy")),
+		ReadTimeout:  time.Duration(util.ConfGetInt("proxy.read_timeout")) * time.Second,
+		WriteTimeout: time.Duration(util.ConfGetInt("proxy.write_timeout")) * time.Second,
+		IdleTimeout:  time.Duration(util.ConfGetInt("proxy.idle_timeout")) * time.Second,
+	}
+	if settings.Strategy == "" {
+		settings.Strategy = RoundRobin // 默认策略
+	}
+	if settings.ReadTimeout <= 0 {
+		settings.ReadTimeout = 30 * time.Second
+	}
+	if settings.WriteTimeout <= 0 {
+		settings.WriteTimeout = 30 * time.Second
+	}
+	if settings.IdleTimeout <= 0 {
+		settings.IdleTimeout = defaultProxyIdleTimeout
+	}
+	return settings
+}
+
+// applySettings 将新配置应用到运行中的代理，strategy 为端口实际使用的负载均衡策略
+// 配置通过原子指针替换，正在处理的请求不受影响，新请求使用新配置
+func (pp *PortProxy) applySettings(settings *proxySettings, strategy LoadBalanceStrategy) {
+	pp.settings.Store(settings)
+	if pp.balancer != nil {
+		pp.balancer.setStrategy(strategy)
+	}
+}
+
+// newServer 创建代理的 http.Server
+// 读写超时由 withDeadlines 按请求设置；ReadTimeout 为 0，必须设置 IdleTimeout，否则空闲的 keep-alive 连接永不超时
+func (pp *PortProxy) newServer(handler http.Handler) *http.Server {
+	idleTimeout := defaultProxyIdleTimeout
+	if settings := pp.settings.Load(); settings != nil && settings.IdleTimeout > 0 {
+		idleTimeout = settings.IdleTimeout
+	}
+	return &http.Server{
+		Addr:              pp.listenAddr(),
+		Handler:           pp.withDeadlines(handler),
+		ReadHeaderTimeout: 30 * time.Second,
+		IdleTimeout:       idleTimeout,
+	}
+}
+
+// withDeadlines 按当前配置为每个请求设置读写超时
+// 超时在处理请求时读取，因此热加载后无需重启 http.Server
+func (pp *PortProxy) withDeadlines(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		settings := pp.settings.Load()
+		if settings != nil {
+			rc := http.NewResponseController(w)
+			now := time.Now()
+			rc.SetReadDeadline(now.Add(settings.ReadTimeout))
+			rc.SetWriteDeadline(now.Add(settings.WriteTimeout))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setStrategy 切换负载均衡策略
+func (lb *LoadBalancer) setStrategy(strategy LoadBalanceStrategy) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	lb.strategy = strategy
+}
+
+// ReloadConfig 重新读取配置文件并应用到所有运行中的端口代理，不中断现有连接
+func (ppm *PortProxyManager) ReloadConfig(ctx igoContext.IContext) (map[string]interface{}, error) {
+	if err := utils.ReloadConfig(); err != nil {
+		log.Error("PortProxyManager", log.Any("Error", err), log.Any("Message", "重新加载配置失败"))
+		return nil, fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	settings := loadProxySettings()
+
+	ppm.mutex.RLock()
+	defer ppm.mutex.RUnlock()
+
+	for port, proxy := range ppm.proxies {
+		proxy.applySettings(settings, ppm.strategyFor(port, settings))
+	}
+
+	log.Info("PortProxyManager", log.Any("Proxies", len(ppm.proxies)), log.Any("Strategy", settings.Strategy),
+		log.Any("ReadTimeout", settings.ReadTimeout.String()), log.Any("WriteTimeout", settings.WriteTimeout.String()),
+		log.Any("Message", "代理配置已重新加载"))
+
+	return map[string]interface{}{
+		"reloaded_proxies": len(ppm.proxies),
+		"strategy":         settings.Strategy,
+		"read_timeout":     settings.ReadTimeout.String(),
+		"write_timeout":    settings.WriteTimeout.String(),
+	}, nil
+}
+
+// watchReloadSignal 监听 SIGHUP 信号，收到后重新加载代理配置
+func (ppm *PortProxyManager) watchReloadSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	go func() {
+		for range ch {
+			log.Info("PortProxyManager", log.Any("Message", "收到 SIGHUP，重新加载代理配置"))
+			ppm.ReloadConfig(igoContext.Background())
+		}
+	}()
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestProxyServerIdleTimeout 测试代理的 http.Server 总是设置空闲连接超时
+func TestProxyServerIdleTimeout(t *testing.T) {
+	pp := &PortProxy{publicPort: 9300}
+	if server := pp.newServer(http.NotFoundHandler()); server.IdleTimeout != defaultProxyIdleTimeout {
+		t.Errorf("未加载配置时 IdleTimeout = %s, 期望 %s", server.IdleTimeout, defaultProxyIdleTimeout)
+	}
+
+	pp.settings.Store(&proxySettings{ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second, IdleTimeout: time.Minute})
+	server := pp.newServer(http.NotFoundHandler())
+	if server.IdleTimeout != time.Minute || server.ReadHeaderTimeout <= 0 {
+		t.Errorf("IdleTimeout = %s, ReadHeaderTimeout = %s, 期望 1m0s 且设置了请求头超时", server.IdleTimeout, server.ReadHeaderTimeout)
+	}
+}
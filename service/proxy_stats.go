@@ -0,0 +1,110 @@
+package service
+
+import (
+	"sort"
+	"sync/atomic"
+
+	igoContext "github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+)
+
+// stats 端口代理的统计信息
+func (pp *PortProxy) stats() models.ProxyStats {
+	stats := models.ProxyStats{
+		Service:     pp.service,
+		PublicPort:  pp.publicPort,
+		ListenAddr:  pp.listenAddr(),
+		BindAddress: pp.bindAddress,
+		Workers:     pp.workers,
+		Type:        pp.proxyType,
+		Backends:    make([]models.ProxyBackendStats, 0),
+	}
+	if pp.traffic != nil {
+		stats.RequestsTotal = atomic.LoadInt64(&pp.traffic.requests)
+		stats.ActiveRequests = atomic.LoadInt64(&pp.traffic.active)
+		stats.ErrorsTotal = atomic.LoadInt64(&pp.traffic.errors)
+	}
+	if pp.filter != nil {
+		stats.RequestRules = pp.filter.stats()
+	}
+	if pp.mirror != nil {
+		stats.Mirror = pp.mirror.stats()
+	}
+
+	if pp.single != nil {
+		stats.Backends = append(stats.Backends, pp.single.stats())
+	}
+	if pp.balancer != nil {
+		pp.balancer.mutex.RLock()
+		stats.Strategy = string(pp.balancer.strategy)
+		for _, backend := range pp.balancer.backends {
+			stats.Backends = append(stats.Backends, backend.stats())
+		}
+		pp.balancer.mutex.RUnlock()
+	}
+	return stats
+}
+
+// stats 后端的统计信息
+func (b *Backend) stats() models.ProxyBackendStats {
+	return models.ProxyBackendStats{
+		ContainerID:   b.ContainerMapping.ContainerID,
+		ContainerPort: b.ContainerMapping.ContainerPort,
+		Active:        b.Active,
+		Connections:   atomic.LoadInt64(&b.Connections),
+		Requests:      atomic.LoadInt64(&b.Requests),
+		Errors:        atomic.LoadInt64(&b.Errors),
+		Weight:        b.Weight,
+		LastUsed:      b.LastUsed,
+	}
+}
+
+// ServiceProxyStats 获取单个服务的端口代理统计信息，按公共端口排序
+func (ppm *PortProxyManager) ServiceProxyStats(ctx igoContext.IContext, name string) (*models.ServiceProxyStats, error) {
+	if ppm.service.GetService(ctx, name) == nil {
+		return nil, errServiceNotFound(name)
+	}
+
+	return &models.ServiceProxyStats{Service: name, Proxies: ppm.serviceProxies(name)}, nil
+}
+
+// GetProxyStats 获取全部端口代理的统计信息，按公共端口排序
+func (ppm *PortProxyManager) GetProxyStats(ctx igoContext.IContext) *models.ProxyOverview {
+	ppm.mutex.RLock()
+	defer ppm.mutex.RUnlock()
+
+	overview := &models.ProxyOverview{TotalProxies: len(ppm.proxies), ProxyDetails: make([]models.ProxyStats, 0, len(ppm.proxies))}
+	for _, proxy := range ppm.proxies {
+		stats := proxy.stats()
+		_, stats.StrategyOverridden = ppm.strategies[proxy.publicPort]
+		if stats.Type == "single" {
+			overview.SingleProxies++
+		} else {
+			overview.LoadBalancers++
+		}
+		overview.ProxyDetails = append(overview.ProxyDetails, stats)
+	}
+	sort.Slice(overview.ProxyDetails, func(i, j int) bool {
+		return overview.ProxyDetails[i].PublicPort < overview.ProxyDetails[j].PublicPort
+	})
+	return overview
+}
+
+// serviceProxies 服务的端口代理统计信息，按公共端口排序，不检查服务是否存在
+func (ppm *PortProxyManager) serviceProxies(name string) []models.ProxyStats {
+	ppm.mutex.RLock()
+	defer ppm.mutex.RUnlock()
+
+	proxies := make([]models.ProxyStats, 0)
+	for _, proxy := range ppm.proxies {
+		if proxy.service == name {
+			stats := proxy.stats()
+			_, stats.StrategyOverridden = ppm.strategies[proxy.publicPort]
+			proxies = append(proxies, stats)
+		}
+	}
+	sort.Slice(proxies, func(i, j int) bool {
+		return proxies[i].PublicPort < proxies[j].PublicPort
+	})
+	return proxies
+}
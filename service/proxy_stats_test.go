@@ -0,0 +1,82 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestBackendServeCounts 测试后端转发时的请求数和失败数统计
+func TestBackendServeCounts(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	target, _ := url.Parse(upstream.URL)
+	backend := &Backend{
+		ContainerMapping: &ContainerMapping{ContainerID: "abc", ContainerPort: 32768},
+		Proxy:            httputil.NewSingleHostReverseProxy(target),
+		Active:           true,
+		Weight:           100,
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.NoRoute(backend.serve)
+	proxy := httptest.NewServer(router)
+	defer proxy.Close()
+	for _, path := range []string{"/", "/fail", "/ok"} {
+		resp, err := http.Get(proxy.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	stats := backend.stats()
+	if stats.Requests != 3 || stats.Errors != 1 || stats.Connections != 0 {
+		t.Errorf("stats() = 请求 %d 失败 %d 连接 %d, 期望 3 1 0", stats.Requests, stats.Errors, stats.Connections)
+	}
+	if stats.ContainerID != "abc" || stats.ContainerPort != 32768 || !stats.Active {
+		t.Errorf("stats() = %+v, 后端信息不符", stats)
+	}
+}
+
+// TestGetProxyStats 测试全部端口代理的统计按公共端口排序，并区分单副本代理和负载均衡器
+func TestGetProxyStats(t *testing.T) {
+	ppm := &PortProxyManager{
+		proxies: map[int]*PortProxy{
+			9304: {publicPort: 9304, service: "api", proxyType: "load_balancer", balancer: &LoadBalancer{
+				strategy: LeastConnections,
+				backends: []*Backend{
+					{ContainerMapping: &ContainerMapping{ContainerID: "b1", ContainerPort: 32769}, Active: true, Weight: 100},
+					{ContainerMapping: &ContainerMapping{ContainerID: "b2", ContainerPort: 32770}, Active: true, Weight: 100},
+				},
+			}},
+			9303: {publicPort: 9303, service: "web", proxyType: "single",
+				single: &Backend{ContainerMapping: &ContainerMapping{ContainerID: "a1", ContainerPort: 32768}, Active: true, Weight: 100}},
+		},
+		strategies: map[int]LoadBalanceStrategy{9304: LeastConnections},
+	}
+
+	overview := ppm.GetProxyStats(nil)
+	if overview.TotalProxies != 2 || overview.SingleProxies != 1 || overview.LoadBalancers != 1 || len(overview.ProxyDetails) != 2 {
+		t.Fatalf("GetProxyStats() = %+v", overview)
+	}
+	web, api := overview.ProxyDetails[0], overview.ProxyDetails[1]
+	if web.PublicPort != 9303 || web.Service != "web" || len(web.Backends) != 1 || web.Backends[0].ContainerID != "a1" {
+		t.Errorf("单副本代理 = %+v", web)
+	}
+	if api.PublicPort != 9304 || api.Strategy != string(LeastConnections) || !api.StrategyOverridden || len(api.Backends) != 2 {
+		t.Errorf("负载均衡器 = %+v", api)
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	igoContext "github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// loadBalanceStrategies 支持的负载均衡策略
+var loadBalanceStrategies = map[LoadBalanceStrategy]bool{
+	RoundRobin:       true,
+	LeastConnections: true,
+	Weighted:         true,
+}
+
+// strategyFor 端口使用的负载均衡策略：通过接口设置的策略优先，否则使用配置文件中的默认策略；调用方需持有 ppm.mutex
+func (ppm *PortProxyManager) strategyFor(publicPort int, settings *proxySettings) LoadBalanceStrategy {
+	if strategy, exists := ppm.strategies[publicPort]; exists {
+		return strategy
+	}
+	return settings.Strategy
+}
+
+// SetServiceStrategy 设置服务端口的负载均衡策略，立即应用到运行中的代理，无需重建代理
+// strategy 为空表示恢复使用配置文件中的默认策略
+func (ppm *PortProxyManager) SetServiceStrategy(ctx igoContext.IContext, name, value string) (*models.ServiceProxyStats, error) {
+	strategy := LoadBalanceStrategy(value)
+	if strategy != "" && !loadBalanceStrategies[strategy] {
+		return nil, utils.NewError(utils.CodeInvalidRequest, "invalid load balance strategy %q", strategy)
+	}
+	service := ppm.service.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	if service.PublicPort == 0 {
+		return nil, utils.NewError(utils.CodeConflict, "service %s has no public port", name)
+	}
+
+	ppm.mutex.Lock()
+	if strategy == "" {
+		delete(ppm.strategies, service.PublicPort)
+	} else {
+		ppm.strategies[service.PublicPort] = strategy
+	}
+	effective := ppm.strategyFor(service.PublicPort, loadProxySettings())
+	if proxy, exists := ppm.proxies[service.PublicPort]; exists && proxy.balancer != nil {
+		proxy.balancer.setStrategy(effective)
+	}
+	ppm.mutex.Unlock()
+
+	log.Info("PortProxyManager", log.Any("ServiceName", name), log.Any("PublicPort", service.PublicPort), log.Any("Strategy", effective),
+		log.Any("Message", "负载均衡策略已切换"))
+	return ppm.ServiceProxyStats(ctx, name)
+}
+
+// ClearPortOverrides 清除端口通过接口设置的负载均衡策略和停用的后端，服务删除后调用，避免影响之后使用该端口的服务
+func (ppm *PortProxyManager) ClearPortOverrides(publicPort int) {
+	ppm.mutex.Lock()
+	defer ppm.mutex.Unlock()
+	delete(ppm.strategies, publicPort)
+	delete(ppm.disabled, publicPort)
+}
@@ -0,0 +1,16 @@
+package service
+
+import "testing"
+
+// TestStrategyFor 测试端口负载均衡策略的选择：接口设置的策略优先于配置文件
+func TestStrategyFor(t *testing.T) {
+	ppm := &PortProxyManager{strategies: map[int]LoadBalanceStrategy{9303: LeastConnections}}
+	settings := &proxySettings{Strategy: RoundRobin}
+
+	if got := ppm.strategyFor(9303, settings); got != LeastConnections {
+		t.Errorf("strategyFor(9303) = %s, 期望 %s", got, LeastConnections)
+	}
+	if got := ppm.strategyFor(9304, settings); got != RoundRobin {
+		t.Errorf("strategyFor(9304) = %s, 期望 %s", got, RoundRobin)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// portTraffic 端口的请求计数，由代理管理器按端口保存，代理重建（扩缩容、更新）后继续累计
+type portTraffic struct {
+	requests int64 // 累计转发的请求数
+	active   int64 // 正在处理的请求数
+	errors   int64 // 返回 5xx 的请求数
+}
+
+// middleware 统计经过过滤规则后转发给容器的请求
+func (t *portTraffic) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		atomic.AddInt64(&t.requests, 1)
+		atomic.AddInt64(&t.active, 1)
+		defer atomic.AddInt64(&t.active, -1)
+		c.Next()
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			atomic.AddInt64(&t.errors, 1)
+		}
+	}
+}
+
+// trafficFor 获取端口的请求计数，不存在时创建；调用方需持有 ppm.mutex
+func (ppm *PortProxyManager) trafficFor(publicPort int) *portTraffic {
+	traffic, exists := ppm.traffic[publicPort]
+	if !exists {
+		traffic = &portTraffic{}
+		ppm.traffic[publicPort] = traffic
+	}
+	return traffic
+}
+
+// PortTraffic 返回端口累计转发的请求数和正在处理的请求数，端口没有代理过请求时 ok 为 false
+func (ppm *PortProxyManager) PortTraffic(publicPort int) (requests, active int64, ok bool) {
+	ppm.mutex.RLock()
+	defer ppm.mutex.RUnlock()
+
+	traffic, exists := ppm.traffic[publicPort]
+	if !exists {
+		return 0, 0, false
+	}
+	return atomic.LoadInt64(&traffic.requests), atomic.LoadInt64(&traffic.active), true
+}
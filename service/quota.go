@@ -0,0 +1,214 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// tokenQuota 令牌配额配置
+type tokenQuota struct {
+	Token             string `mapstructure:"token"`
+	models.QuotaLimit `mapstructure:",squash"`
+}
+
+// quotaRequest 待校验的服务资源占用
+type quotaRequest struct {
+	Name       string
+	Owner      string
+	PublicPort int
+	Replicas   int
+	Memory     int
+}
+
+// namespaceQuotas 读取命名空间配额（quota.namespaces.<命名空间>），每次读取以便配置热加载
+func namespaceQuotas() map[string]models.QuotaLimit {
+	quotas := make(map[string]models.QuotaLimit)
+	if err := igo.App.Conf.UnmarshalKey("quota.namespaces", &quotas); err != nil {
+		log.Error("Quota", log.Any("Error", err), log.Any("Message", "解析命名空间配额失败"))
+	}
+	return quotas
+}
+
+// tokenQuotas 读取令牌配额（[[quota.tokens]]），按令牌指纹索引
+func tokenQuotas() map[string]models.QuotaLimit {
+	var items []tokenQuota
+	if err := igo.App.Conf.UnmarshalKey("quota.tokens", &items); err != nil {
+		log.Error("Quota", log.Any("Error", err), log.Any("Message", "解析令牌配额失败"))
+	}
+	quotas := make(map[string]models.QuotaLimit, len(items))
+	for _, item := range items {
+		if item.Token != "" {
+			quotas[utils.TokenID(item.Token)] = item.QuotaLimit
+		}
+	}
+	return quotas
+}
+
+// parsePortRange 解析端口范围，支持 "9300-9399" 和单个端口 "9300"
+func parsePortRange(value string) (int, int, error) {
+	start, end, found := strings.Cut(strings.TrimSpace(value), "-")
+	low, err := strconv.Atoi(strings.TrimSpace(start))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %q", value)
+	}
+	high := low
+	if found {
+		if high, err = strconv.Atoi(strings.TrimSpace(end)); err != nil {
+			return 0, 0, fmt.Errorf("invalid port range %q", value)
+		}
+	}
+	if low <= 0 || high > 65535 || low > high {
+		return 0, 0, fmt.Errorf("invalid port range %q", value)
+	}
+	return low, high, nil
+}
+
+// portAllowed 检查端口是否在允许范围内，未配置范围时不限制
+func portAllowed(port int, ranges []string) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	for _, r := range ranges {
+		low, high, err := parsePortRange(r)
+		if err != nil {
+			log.Error("Quota", log.Any("Error", err), log.Any("Message", "端口范围配置无效"))
+			continue
+		}
+		if port >= low && port <= high {
+			return true
+		}
+	}
+	return false
+}
+
+// quotaUsage 统计满足条件的服务的资源占用，exclude 为正在变更的服务
+func quotaUsage(specs []*store.ServiceSpec, match func(*store.ServiceSpec) bool, exclude string) models.QuotaUsage {
+	var usage models.QuotaUsage
+	for _, spec := range specs {
+		if spec.Name == exclude || !match(spec) {
+			continue
+		}
+		usage.Services++
+		usage.Replicas += spec.Replicas
+		usage.Memory += spec.Replicas * spec.Memory
+	}
+	return usage
+}
+
+// checkQuotaLimit 检查加入本服务后是否超出配额
+func checkQuotaLimit(scope, name string, limit models.QuotaLimit, usage models.QuotaUsage, req quotaRequest) error {
+	if limit.MaxServices > 0 && usage.Services+1 > limit.MaxServices {
+		return utils.NewError(utils.CodeQuotaExceeded, "quota exceeded for %s %s: max_services %d, already used %d", scope, name, limit.MaxServices, usage.Services)
+	}
+	if limit.MaxReplicas > 0 && usage.Replicas+req.Replicas > limit.MaxReplicas {
+		return utils.NewError(utils.CodeQuotaExceeded, "quota exceeded for %s %s: max_replicas %d, used %d, requested %d", scope, name, limit.MaxReplicas, usage.Replicas, req.Replicas)
+	}
+	if limit.MaxMemory > 0 {
+		if req.Memory <= 0 {
+			return fmt.Errorf("memory_limit is required by the max_memory quota of %s %s", scope, name)
+		}
+		if requested := req.Replicas * req.Memory; usage.Memory+requested > limit.MaxMemory {
+			return utils.NewError(utils.CodeQuotaExceeded, "quota exceeded for %s %s: max_memory %dMB, used %dMB, requested %dMB", scope, name, limit.MaxMemory, usage.Memory, requested)
+		}
+	}
+	if !portAllowed(req.PublicPort, limit.PortRanges) {
+		return utils.NewError(utils.CodeQuotaExceeded, "public port %d is not allowed for %s %s, allowed ranges: %s", req.PublicPort, scope, name, strings.Join(limit.PortRanges, ", "))
+	}
+	return nil
+}
+
+// checkQuota 校验服务部署、更新、扩容后的资源占用是否超出命名空间和令牌配额
+// 令牌配额按服务所有者（首次部署的令牌）计算
+func (s *Service) checkQuota(ctx context.IContext, req quotaRequest) error {
+	if req.Memory < 0 {
+		return fmt.Errorf("memory_limit cannot be negative")
+	}
+
+	namespaces := namespaceQuotas()
+	tokens := tokenQuotas()
+	if len(namespaces) == 0 && len(tokens) == 0 {
+		return nil
+	}
+
+	specs, err := s.store.ListServiceSpecs()
+	if err != nil {
+		return fmt.Errorf("failed to load services for quota check: %w", err)
+	}
+	owner := req.Owner
+	for _, spec := range specs {
+		if spec.Name == req.Name && spec.Owner != "" {
+			owner = spec.Owner
+		}
+	}
+
+	namespace, _ := models.SplitServiceName(req.Name)
+	if limit, ok := namespaces[namespace]; ok {
+		usage := quotaUsage(specs, func(spec *store.ServiceSpec) bool {
+			ns, _ := models.SplitServiceName(spec.Name)
+			return ns == namespace
+		}, req.Name)
+		if err := checkQuotaLimit(models.QuotaScopeNamespace, namespace, limit, usage, req); err != nil {
+			return err
+		}
+	}
+
+	if limit, ok := tokens[owner]; ok && owner != "" {
+		usage := quotaUsage(specs, func(spec *store.ServiceSpec) bool {
+			return spec.Owner == owner
+		}, req.Name)
+		if err := checkQuotaLimit(models.QuotaScopeToken, owner, limit, usage, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListQuotas 列出已配置的配额及当前使用量
+func (s *Service) ListQuotas(ctx context.IContext) ([]*models.QuotaStatus, error) {
+	specs, err := s.store.ListServiceSpecs()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.QuotaStatus, 0)
+	for namespace, limit := range namespaceQuotas() {
+		namespace := namespace
+		result = append(result, &models.QuotaStatus{
+			Scope: models.QuotaScopeNamespace,
+			Name:  namespace,
+			Limit: limit,
+			Usage: quotaUsage(specs, func(spec *store.ServiceSpec) bool {
+				ns, _ := models.SplitServiceName(spec.Name)
+				return ns == namespace
+			}, ""),
+		})
+	}
+	for owner, limit := range tokenQuotas() {
+		owner := owner
+		result = append(result, &models.QuotaStatus{
+			Scope: models.QuotaScopeToken,
+			Name:  owner,
+			Limit: limit,
+			Usage: quotaUsage(specs, func(spec *store.ServiceSpec) bool {
+				return spec.Owner == owner
+			}, ""),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Scope != result[j].Scope {
+			return result[i].Scope < result[j].Scope
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+)
+
+// TestParsePortRange 测试端口范围解析
+func TestParsePortRange(t *testing.T) {
+	cases := []struct {
+		value     string
+		low, high int
+		wantErr   bool
+	}{
+		{"9300-9399", 9300, 9399, false},
+		{" 9300 - 9399 ", 9300, 9399, false},
+		{"9300", 9300, 9300, false},
+		{"9399-9300", 0, 0, true},
+		{"0-100", 0, 0, true},
+		{"9000-70000", 0, 0, true},
+		{"abc", 0, 0, true},
+	}
+
+	for _, tc := range cases {
+		low, high, err := parsePortRange(tc.value)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parsePortRange(%q) error = %v, wantErr %v", tc.value, err, tc.wantErr)
+			continue
+		}
+		if low != tc.low || high != tc.high {
+			t.Errorf("parsePortRange(%q) = %d-%d, want %d-%d", tc.value, low, high, tc.low, tc.high)
+		}
+	}
+}
+
+// TestCheckQuotaLimit 测试配额校验
+func TestCheckQuotaLimit(t *testing.T) {
+	specs := []*store.ServiceSpec{
+		{Name: "staging.web", Replicas: 2, Memory: 512},
+		{Name: "staging.api", Replicas: 3, Memory: 1024},
+		{Name: "prod.web", Replicas: 5, Memory: 2048},
+	}
+	inStaging := func(spec *store.ServiceSpec) bool {
+		ns, _ := models.SplitServiceName(spec.Name)
+		return ns == "staging"
+	}
+
+	usage := quotaUsage(specs, inStaging, "")
+	if usage.Services != 2 || usage.Replicas != 5 || usage.Memory != 4096 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+	// 变更已有服务时不计入其当前占用
+	usage = quotaUsage(specs, inStaging, "staging.api")
+	if usage.Services != 1 || usage.Replicas != 2 || usage.Memory != 1024 {
+		t.Fatalf("unexpected usage excluding staging.api: %+v", usage)
+	}
+
+	limit := models.QuotaLimit{MaxServices: 2, MaxReplicas: 6, MaxMemory: 4096, PortRanges: []string{"9300-9399"}}
+	cases := []struct {
+		name    string
+		req     quotaRequest
+		wantErr bool
+	}{
+		{"scale existing within limits", quotaRequest{Name: "staging.api", PublicPort: 9301, Replicas: 4, Memory: 512}, false},
+		{"too many services", quotaRequest{Name: "staging.new", PublicPort: 9302, Replicas: 1, Memory: 128}, true},
+		{"too many replicas", quotaRequest{Name: "staging.api", PublicPort: 9301, Replicas: 5, Memory: 128}, true},
+		{"too much memory", quotaRequest{Name: "staging.api", PublicPort: 9301, Replicas: 3, Memory: 2048}, true},
+		{"memory limit required", quotaRequest{Name: "staging.api", PublicPort: 9301, Replicas: 1}, true},
+		{"port out of range", quotaRequest{Name: "staging.api", PublicPort: 9203, Replicas: 1, Memory: 128}, true},
+	}
+
+	for _, tc := range cases {
+		usage := quotaUsage(specs, inStaging, tc.req.Name)
+		err := checkQuotaLimit(models.QuotaScopeNamespace, "staging", limit, usage, tc.req)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// defaultReconcileInterval 默认调和间隔（秒）
+const defaultReconcileInterval = 30
+
+// reconciler 调和循环，周期性地让实际容器向期望状态收敛
+type reconciler struct {
+	mutex sync.Mutex // 同一时间只运行一轮调和（定时与手动触发互斥）
+}
+
+// startReconciler 启动调和循环，container.reconcile_interval 为 0 时禁用
+func (s *Service) startReconciler() {
+	interval := utils.ConfGetIntDefault("container.reconcile_interval", defaultReconcileInterval)
+	if interval <= 0 {
+		log.Info("Reconcile", log.Any("Message", "调和循环已禁用"))
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(interval) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopping:
+				return
+			case <-ticker.C:
+				s.Reconcile(context.Background())
+			}
+		}
+	}()
+	log.Info("Reconcile", log.Any("Interval", interval), log.Any("Message", "调和循环已启动"))
+}
+
+// Reconcile 对所有有期望状态的服务执行一轮调和
+func (s *Service) Reconcile(ctx context.IContext) ([]*models.ReconcileResult, error) {
+	s.reconciler.mutex.Lock()
+	defer s.reconciler.mutex.Unlock()
+
+	specs, err := s.store.ListServiceSpecs()
+	if err != nil {
+		log.Error("Reconcile", log.Any("Error", err), log.Any("Message", "读取期望状态失败"))
+		return nil, err
+	}
+
+	results := make([]*models.ReconcileResult, 0, len(specs))
+	for _, spec := range specs {
+		result := s.reconcileService(ctx, spec.Name)
+		if len(result.Actions) > 0 || result.Error != "" {
+			log.Info("Reconcile", log.Any("ServiceName", result.Service), log.Any("Desired", result.Desired),
+				log.Any("Actual", result.Actual), log.Any("Actions", result.Actions), log.Any("Error", result.Error),
+				log.Any("Message", "服务已调和"))
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// reconcileService 调和单个服务
+// 已停止的服务、正在滚动更新或有其他操作进行中的服务不处理，避免与人工操作冲突
+func (s *Service) reconcileService(ctx context.IContext, name string) *models.ReconcileResult {
+	result := &models.ReconcileResult{Service: name}
+
+	state, err := s.loadDesiredState(name)
+	if err != nil || state == nil {
+		result.Error = fmt.Sprintf("failed to load desired state: %v", err)
+		return result
+	}
+	result.Desired = state.Replicas
+
+	if state.Stopped {
+		result.Skipped = "service stopped"
+		return result
+	}
+	if r := s.rollouts.get(name); r != nil && r.active() {
+		result.Skipped = "rollout in progress"
+		return result
+	}
+	if release := s.pendingRelease(name); release != nil {
+		result.Skipped = fmt.Sprintf("%s release in progress", release.Mode)
+		return result
+	}
+	leave, ok := s.operations.tryEnter(name, models.OperationReconcile, actorFromContext(ctx))
+	if !ok {
+		result.Skipped = "operation in progress"
+		return result
+	}
+	defer leave()
+
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Actual = len(containers)
+
+	config := s.desiredDockerService(name)
+	if config == nil {
+		result.Error = "failed to build container config from desired state"
+		return result
+	}
+	config.Replicas = state.Replicas
+	changed := false
+
+	// 启动意外停止的副本（手动 docker stop、主机重启后未自动拉起等）
+	for _, container := range containers {
+		if container.State == "running" || container.State == "restarting" {
+			continue
+		}
+		if err := s.dockerClient.StartReplica(ctx, container.ID); err != nil {
+			log.Error("Reconcile", log.Any("Error", err), log.Any("ContainerName", container.Name), log.Any("Message", "启动副本失败"))
+			s.recordReplicaCrash(ctx, name, fmt.Sprintf("replica %s is %s, restart failed: %v", container.Name, container.Status, err))
+			continue
+		}
+		s.recordReplicaCrash(ctx, name, fmt.Sprintf("replica %s was %s, restarted", container.Name, container.Status))
+		result.Actions = append(result.Actions, fmt.Sprintf("started %s", container.Name))
+		changed = true
+	}
+
+	// 补齐缺失副本或移除多余副本
+	switch {
+	case len(containers) == 0:
+		if err := s.dockerClient.CreateReplicas(ctx, config, state.Replicas); err != nil {
+			result.Error = fmt.Sprintf("failed to create replicas: %v", err)
+			return result
+		}
+		result.Actions = append(result.Actions, fmt.Sprintf("created %d replicas", state.Replicas))
+		changed = true
+	case len(containers) != state.Replicas:
+		if err := s.dockerClient.ScaleServiceWithConfig(ctx, name, state.Replicas, config); err != nil {
+			result.Error = fmt.Sprintf("failed to scale service: %v", err)
+			return result
+		}
+		if len(containers) < state.Replicas {
+			result.Actions = append(result.Actions, fmt.Sprintf("created %d replicas", state.Replicas-len(containers)))
+		} else {
+			result.Actions = append(result.Actions, fmt.Sprintf("removed %d replicas", len(containers)-state.Replicas))
+		}
+		changed = true
+	}
+
+	// 恢复端口代理
+	if changed {
+		s.refreshPortProxy(ctx, config.PublicPort)
+		result.Actions = append(result.Actions, "refreshed port proxy")
+	} else if !s.PortManager.HasPortProxy(config.PublicPort) {
+		if err := s.PortManager.StartPortProxy(ctx, config.PublicPort); err != nil {
+			result.Error = fmt.Sprintf("failed to start port proxy: %v", err)
+			return result
+		}
+		result.Actions = append(result.Actions, "started port proxy")
+		s.recordEvent(ctx, name, models.EventProxyRestarted, fmt.Sprintf("port proxy on %d was not running, restarted", config.PublicPort))
+	}
+
+	return result
+}
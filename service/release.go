@@ -0,0 +1,198 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/jinzhu/copier"
+)
+
+// pendingRelease 服务进行中的金丝雀或蓝绿发布，没有时返回 nil
+func (s *Service) pendingRelease(name string) *store.Release {
+	release, err := s.store.GetRelease(name)
+	if err != nil {
+		log.Error("Release", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "读取发布记录失败"))
+		return nil
+	}
+	return release
+}
+
+// loadRelease 读取指定方式的进行中发布及新版本服务配置，不存在时返回错误
+func (s *Service) loadRelease(name, mode string) (*store.Release, *models.ServiceRequest, error) {
+	release, err := s.store.GetRelease(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if release == nil || release.Mode != mode {
+		return nil, nil, fmt.Errorf("service %s has no %s release in progress", name, mode)
+	}
+	req := &models.ServiceRequest{}
+	if err := utils.DeJson(release.Spec, req); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode release spec of service %s: %w", name, err)
+	}
+	return release, req, nil
+}
+
+// releaseConfig 将新版本服务配置转换为容器配置，公共端口沿用现有服务
+func releaseConfig(req *models.ServiceRequest, publicPort int) (*dockerclient.Service, error) {
+	config := &dockerclient.Service{}
+	if err := copier.Copy(config, req); err != nil {
+		return nil, fmt.Errorf("failed to copy service request: %w", err)
+	}
+	config.PublicPort = publicPort
+	return config, nil
+}
+
+// releaseServing 新版本副本是否接收流量：金丝雀始终接收，蓝绿发布确认后才接收
+func releaseServing(release *store.Release) bool {
+	return release.Mode != models.DeploymentModeBlueGreen || release.Promoted
+}
+
+// splitReleaseContainers 按配置哈希将服务容器分为当前版本与新版本
+func (s *Service) splitReleaseContainers(ctx context.IContext, name string, config *dockerclient.Service) (stable, candidate []dockerclient.ContainerInfo, err error) {
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, container := range containers {
+		if s.dockerClient.MatchesConfig(container, config) {
+			candidate = append(candidate, container)
+		} else {
+			stable = append(stable, container)
+		}
+	}
+	return stable, candidate, nil
+}
+
+// releaseStatus 统计发布中新旧版本的副本
+func (s *Service) releaseStatus(ctx context.IContext, release *store.Release, req *models.ServiceRequest, publicPort int) (*models.ReleaseStatus, error) {
+	config, err := releaseConfig(req, publicPort)
+	if err != nil {
+		return nil, err
+	}
+	stable, candidate, err := s.splitReleaseContainers(ctx, release.Service, config)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.ReleaseStatus{
+		Service:           release.Service,
+		Mode:              release.Mode,
+		StableReplicas:    len(stable),
+		CandidateImage:    fmt.Sprintf("%s:%s", release.Image, release.Tag),
+		CandidateReplicas: len(candidate),
+		Serving:           releaseServing(release),
+		Actor:             release.Actor,
+		CreatedAt:         release.CreatedAt,
+	}
+	if len(stable) > 0 {
+		if current, err := s.dockerClient.ExtractServiceFromContainer(stable[0]); err == nil {
+			status.StableImage = fmt.Sprintf("%s:%s", current.Image, current.Tag)
+		} else {
+			status.StableImage = stable[0].Image
+		}
+	}
+	return status, nil
+}
+
+// removeReplicas 删除一组副本，返回删除失败的容器
+func (s *Service) removeReplicas(ctx context.IContext, containers []dockerclient.ContainerInfo) []string {
+	var failed []string
+	for _, container := range containers {
+		if err := s.dockerClient.RemoveReplica(ctx, container); err != nil {
+			log.Error("Release", log.Any("Error", err), log.Any("ContainerName", container.Name), log.Any("Message", "删除副本失败"))
+			failed = append(failed, container.Name)
+		}
+	}
+	return failed
+}
+
+// discardRelease 删除新版本副本和发布记录，服务保持原配置
+func (s *Service) discardRelease(ctx context.IContext, name string, config *dockerclient.Service) error {
+	_, candidate, err := s.splitReleaseContainers(ctx, name, config)
+	if err != nil {
+		return err
+	}
+	failed := s.removeReplicas(ctx, candidate)
+	s.refreshPortProxy(ctx, config.PublicPort)
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to remove new version replicas: %v", failed)
+	}
+	_, err = s.store.DeleteRelease(name)
+	return err
+}
+
+// servingFilter 返回判断副本是否接收流量的函数，用于构建端口代理的后端列表
+// 蓝绿发布确认前新版本副本不接收流量，确认后旧版本副本不再接收流量
+func (s *Service) servingFilter() func(serviceName string, container dockerclient.ContainerInfo) bool {
+	releases := make(map[string]*store.Release)
+	configs := make(map[string]*dockerclient.Service)
+	return func(serviceName string, container dockerclient.ContainerInfo) bool {
+		release, loaded := releases[serviceName]
+		if !loaded {
+			release = s.pendingRelease(serviceName)
+			releases[serviceName] = release
+			if release != nil && release.Mode == models.DeploymentModeBlueGreen {
+				req := &models.ServiceRequest{}
+				if err := utils.DeJson(release.Spec, req); err == nil {
+					configs[serviceName], _ = releaseConfig(req, 0)
+				}
+			}
+		}
+		config := configs[serviceName]
+		if config == nil {
+			return true
+		}
+		return s.dockerClient.MatchesConfig(container, config) == release.Promoted
+	}
+}
+
+// getRelease 获取服务进行中的指定方式的发布
+func (s *Service) getRelease(ctx context.IContext, name, mode string) (*models.ReleaseStatus, error) {
+	release, req, err := s.loadRelease(name, mode)
+	if err != nil {
+		return nil, err
+	}
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	return s.releaseStatus(ctx, release, req, service.PublicPort)
+}
+
+// abortRelease 中止指定方式的发布：删除新版本副本，服务保持原配置
+func (s *Service) abortRelease(ctx context.IContext, name, mode string) error {
+	leave, err := s.serialize(ctx, name, mode)
+	if err != nil {
+		return err
+	}
+	defer leave()
+
+	release, req, err := s.loadRelease(name, mode)
+	if err != nil {
+		return err
+	}
+	if release.Promoted {
+		return utils.NewError(utils.CodeConflict, "release of service %s is already promoted, retry promote to finish it", name)
+	}
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return errServiceNotFound(name)
+	}
+	config, err := releaseConfig(req, service.PublicPort)
+	if err != nil {
+		return err
+	}
+	if err := s.discardRelease(ctx, name, config); err != nil {
+		return err
+	}
+
+	log.Info("Release", log.Any("ServiceName", name), log.Any("Mode", mode), log.Any("Image", release.Image+":"+release.Tag),
+		log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "发布已中止，新版本副本已删除"))
+	return nil
+}
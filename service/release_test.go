@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestStartCanaryValidation 测试金丝雀发布请求校验
+func TestStartCanaryValidation(t *testing.T) {
+	s := &Service{}
+	cases := map[string]*models.CanaryRequest{
+		"服务名称不一致": {ServiceRequest: models.ServiceRequest{Name: "api", Image: "nginx", Tag: "1.27", InternalPort: 80}},
+		"副本数为负数":  {ServiceRequest: models.ServiceRequest{Image: "nginx", Tag: "1.27", InternalPort: 80}, CanaryReplicas: -1},
+	}
+	for name, req := range cases {
+		if _, err := s.StartCanary(nil, "web", req); err == nil {
+			t.Errorf("%s: 应返回错误", name)
+		}
+	}
+}
+
+// TestReleaseConfig 测试新版本配置沿用现有服务的公共端口
+func TestReleaseConfig(t *testing.T) {
+	req := &models.ServiceRequest{
+		Name:         "web",
+		Image:        "nginx",
+		Tag:          "1.27",
+		InternalPort: 80,
+		PublicPort:   0,
+		Environment:  map[string]string{"ENV": "canary"},
+	}
+	config, err := releaseConfig(req, 9203)
+	if err != nil {
+		t.Fatalf("releaseConfig 返回错误: %v", err)
+	}
+	if config.PublicPort != 9203 || config.Tag != "1.27" || config.Environment["ENV"] != "canary" {
+		t.Errorf("releaseConfig = %+v", config)
+	}
+}
+
+// TestValidateDeploymentMode 测试更新方式校验
+func TestValidateDeploymentMode(t *testing.T) {
+	for _, mode := range []string{"", models.DeploymentModeRolling, models.DeploymentModeBlueGreen} {
+		if err := validateDeploymentMode(mode); err != nil {
+			t.Errorf("validateDeploymentMode(%q) 返回错误: %v", mode, err)
+		}
+	}
+	for _, mode := range []string{models.DeploymentModeCanary, "recreate"} {
+		if err := validateDeploymentMode(mode); err == nil {
+			t.Errorf("validateDeploymentMode(%q) 应返回错误", mode)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/jinzhu/copier"
+)
+
+// RenameService 重命名服务
+// 容器名称和标签中都包含服务名称且无法原地修改，因此按新名称逐个替换副本（先建后删），公共端口和端口代理保持不变；
+// 期望状态、部署历史、扩缩容规则、锁和端口登记迁移到新名称
+func (s *Service) RenameService(ctx context.IContext, name string, req *models.RenameRequest) (*models.Service, error) {
+	leave, err := s.serialize(ctx, name, models.OperationRename)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	existing := s.GetService(ctx, name)
+	if existing == nil {
+		return nil, errServiceNotFound(name)
+	}
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return nil, err
+	}
+
+	target := &models.ServiceRequest{Name: req.Name, Namespace: req.Namespace}
+	if err := normalizeNamespace(target); err != nil {
+		return nil, err
+	}
+	if err := checkServiceScope(ctx, target.Name); err != nil {
+		return nil, err
+	}
+	if target.Name == name {
+		return nil, fmt.Errorf("service %s already has this name", name)
+	}
+	// 同时占用新名称的操作队列，防止改名期间以新名称部署
+	leaveTarget, err := s.serialize(ctx, target.Name, models.OperationRename)
+	if err != nil {
+		return nil, err
+	}
+	defer leaveTarget()
+
+	if s.GetService(ctx, target.Name) != nil {
+		return nil, utils.NewError(utils.CodeServiceExists, "service %s already exists", target.Name)
+	}
+	if release := s.pendingRelease(name); release != nil {
+		return nil, utils.NewError(utils.CodeConflict, "service %s has a %s release in progress, promote or abort it first", name, release.Mode)
+	}
+	if r := s.rollouts.get(name); r != nil && r.active() {
+		return nil, fmt.Errorf("service %s has a rollout in progress", name)
+	}
+
+	// 改名期间暂停调和，避免按旧名称补齐副本
+	s.reconciler.mutex.Lock()
+	defer s.reconciler.mutex.Unlock()
+
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no containers found for service %s", name)
+	}
+	for _, container := range containers {
+		if container.State != "running" {
+			return nil, fmt.Errorf("service %s has stopped replicas, start it before renaming", name)
+		}
+	}
+
+	// 容器标签中没有环境变量、卷挂载等配置，有期望状态时以其为准
+	oldConfig := s.desiredDockerService(name)
+	if oldConfig == nil {
+		oldConfig, err = s.dockerClient.ExtractServiceFromContainer(containers[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract service configuration: %w", err)
+		}
+		log.Warn("Docker", log.Any("ServiceName", name), log.Any("Message", "没有期望状态，按容器标签重建配置，环境变量和卷挂载等配置会丢失"))
+	}
+	oldConfig.PublicPort = existing.PublicPort
+
+	spec := &models.ServiceRequest{}
+	if err := copier.Copy(spec, oldConfig); err != nil {
+		return nil, fmt.Errorf("failed to copy service configuration: %w", err)
+	}
+	spec.Name = target.Name
+	spec.Namespace = target.Namespace
+	spec.Replicas = existing.Replicas
+	newConfig := &dockerclient.Service{}
+	if err := copier.Copy(newConfig, spec); err != nil {
+		return nil, fmt.Errorf("failed to copy service configuration: %w", err)
+	}
+
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("NewName", target.Name), log.Any("Message", "开始重命名服务"))
+
+	// 逐个替换副本，新旧名称的容器公共端口相同，替换期间端口代理同时转发到两者
+	renamed := make([]int, 0, len(containers))
+	for _, container := range containers {
+		nameInfo, err := s.dockerClient.ParseContainerName(container.Name)
+		if err != nil {
+			continue
+		}
+		if _, _, err := s.dockerClient.UpdateContainer(ctx, name, newConfig, nameInfo.ReplicaIndex); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ServiceName", name), log.Any("ReplicaIndex", nameInfo.ReplicaIndex), log.Any("Message", "重命名副本失败，恢复旧名称"))
+			for _, replicaIndex := range renamed {
+				if _, _, err := s.dockerClient.UpdateContainer(ctx, target.Name, oldConfig, replicaIndex); err != nil {
+					log.Error("Docker", log.Any("Error", err), log.Any("ServiceName", target.Name), log.Any("ReplicaIndex", replicaIndex), log.Any("Message", "恢复副本名称失败"))
+				}
+			}
+			s.refreshPortProxy(ctx, existing.PublicPort)
+			return nil, fmt.Errorf("failed to rename replica %d of service %s: %w", nameInfo.ReplicaIndex, name, err)
+		}
+		renamed = append(renamed, nameInfo.ReplicaIndex)
+	}
+	s.refreshPortProxy(ctx, existing.PublicPort)
+
+	snapshot, err := specSnapshot(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.RenameService(name, target.Name, snapshot); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("NewName", target.Name), log.Any("Message", "迁移服务记录失败"))
+		return nil, fmt.Errorf("containers of service %s were renamed to %s but its records could not be migrated: %w", name, target.Name, err)
+	}
+	s.recordRevision(ctx, models.RevisionActionRename, spec, fmt.Sprintf("renamed from %s", name))
+
+	log.Info("Docker", log.Any("ServiceName", target.Name), log.Any("OldName", name), log.Any("Replicas", len(renamed)), log.Any("Message", "服务重命名完成"))
+
+	service := s.GetService(ctx, target.Name)
+	if service == nil {
+		return nil, fmt.Errorf("service %s not found after rename", target.Name)
+	}
+	return service, nil
+}
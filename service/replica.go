@@ -0,0 +1,226 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// replicaContainer 按副本编号查找服务的容器
+func (s *Service) replicaContainer(ctx context.IContext, name string, index int) (*dockerclient.ContainerInfo, error) {
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, errServiceNotFound(name)
+	}
+	for i := range containers {
+		nameInfo, err := s.dockerClient.ParseContainerName(containers[i].Name)
+		if err == nil && nameInfo.ReplicaIndex == index {
+			return &containers[i], nil
+		}
+	}
+	return nil, utils.NewError(utils.CodeNotFound, "replica %d of service %s not found", index, name)
+}
+
+// checkReplicaOperation 单副本操作前的检查：服务已锁定、已停止、正在滚动更新或金丝雀/蓝绿发布期间拒绝操作
+func (s *Service) checkReplicaOperation(ctx context.IContext, name string) error {
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return err
+	}
+	if spec, err := s.store.GetServiceSpec(name); err == nil && spec != nil && spec.Stopped {
+		return fmt.Errorf("service %s is stopped, start it first", name)
+	}
+	if r := s.rollouts.get(name); r != nil && r.active() {
+		return fmt.Errorf("service %s has a rollout in progress", name)
+	}
+	if release := s.pendingRelease(name); release != nil {
+		return utils.NewError(utils.CodeConflict, "service %s has a %s release in progress, promote or abort it first", name, release.Mode)
+	}
+	return nil
+}
+
+// RestartReplica 重启服务的单个副本（连同边车容器），其余副本不受影响
+func (s *Service) RestartReplica(ctx context.IContext, name string, index int) (*models.Service, error) {
+	leave, err := s.serialize(ctx, name, models.OperationRestartReplica)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	if err := s.checkReplicaOperation(ctx, name); err != nil {
+		return nil, err
+	}
+	container, err := s.replicaContainer(ctx, name, index)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.dockerClient.StopContainer(ctx, container.ID); err != nil {
+		return nil, err
+	}
+	if err := s.dockerClient.StartContainer(ctx, container.ID); err != nil {
+		return nil, err
+	}
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("ContainerName", container.Name),
+		log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "副本已重启"))
+	s.recordEvent(ctx, name, models.EventReplicaRestarted, fmt.Sprintf("replica %s restarted", container.Name))
+
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	s.refreshPortProxy(ctx, service.PublicPort)
+	return service, nil
+}
+
+// RecreateReplica 删除并按期望状态重建服务的单个副本，副本编号不变
+// surge 为 true 时先创建并验证新副本再删除旧副本，否则先删除旧副本（用于释放卡住的容器）
+func (s *Service) RecreateReplica(ctx context.IContext, name string, index int, surge bool) (*models.Service, error) {
+	leave, err := s.serialize(ctx, name, models.OperationRecreateReplica)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	if err := s.checkReplicaOperation(ctx, name); err != nil {
+		return nil, err
+	}
+	container, err := s.replicaContainer(ctx, name, index)
+	if err != nil {
+		return nil, err
+	}
+	service := s.GetService(ctx, name)
+	if service == nil {
+		return nil, errServiceNotFound(name)
+	}
+
+	config := s.desiredDockerService(name)
+	if config == nil {
+		config, err = s.dockerClient.ExtractServiceFromContainer(*container)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract service configuration: %w", err)
+		}
+		log.Warn("Docker", log.Any("ServiceName", name), log.Any("Message", "没有期望状态，按容器标签重建配置，环境变量和卷挂载等配置会丢失"))
+	}
+	config.PublicPort = service.PublicPort
+
+	replace := s.dockerClient.ReplaceContainer
+	if surge {
+		replace = s.dockerClient.UpdateContainer
+	}
+	newContainerID, _, err := replace(ctx, name, config, index)
+	s.refreshPortProxy(ctx, service.PublicPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recreate replica %d of service %s: %w", index, name, err)
+	}
+
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("ReplicaIndex", index), log.Any("OldContainer", container.ID[:12]),
+		log.Any("NewContainer", newContainerID[:12]), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "副本已重建"))
+	s.recordEvent(ctx, name, models.EventReplicaRecreated, fmt.Sprintf("replica %s recreated as container %s", container.Name, newContainerID[:12]))
+
+	if service = s.GetService(ctx, name); service == nil {
+		return nil, errServiceNotFound(name)
+	}
+	return service, nil
+}
+
+// replicaEnvironment 将容器的 KEY=VALUE 环境变量转换为映射，secrets 中来自密钥的变量显示为引用，名称像敏感信息的变量脱敏
+func replicaEnvironment(env []string, secrets map[string]string) map[string]string {
+	environment := make(map[string]string, len(env))
+	for _, item := range env {
+		key, value, _ := strings.Cut(item, "=")
+		environment[key] = displayEnvValue(key, value, secrets)
+	}
+	return environment
+}
+
+// GetReplica 获取单个副本的检查详情
+func (s *Service) GetReplica(ctx context.IContext, name string, index int) (*models.ReplicaDetail, error) {
+	container, err := s.replicaContainer(ctx, name, index)
+	if err != nil {
+		return nil, err
+	}
+	detail, err := s.dockerClient.InspectContainerDetail(ctx, container.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var specEnv map[string]string
+	if state, err := s.loadDesiredState(name); err == nil && state != nil {
+		specEnv = state.Spec.Environment
+	}
+
+	replica := &models.ReplicaDetail{
+		Service:       name,
+		Index:         index,
+		ContainerID:   detail.ID,
+		ContainerName: detail.Name,
+		Image:         detail.Image,
+		ImageID:       detail.ImageID,
+		Entrypoint:    detail.Entrypoint,
+		Command:       detail.Command,
+		WorkingDir:    detail.WorkingDir,
+		Environment:   replicaEnvironment(detail.Env, secretEnvRefs(s.dockerClient.ParseSecretEnv(detail.Labels), specEnv)),
+		Labels:        detail.Labels,
+		Mounts:        make([]models.ReplicaMount, 0, len(detail.Mounts)),
+		NetworkMode:   detail.NetworkMode,
+		Networks:      make([]models.ReplicaNetwork, 0, len(detail.Networks)),
+		RestartPolicy: detail.RestartPolicy,
+		MemoryLimit:   detail.MemoryLimit,
+		State: models.ReplicaState{
+			Status:        detail.State,
+			Running:       detail.Running,
+			OOMKilled:     detail.OOMKilled,
+			Pid:           detail.Pid,
+			ExitCode:      detail.ExitCode,
+			Error:         detail.Error,
+			RestartCount:  detail.RestartCount,
+			Health:        detail.Health,
+			FailingStreak: detail.FailingStreak,
+			HealthLog:     detail.HealthLog,
+		},
+	}
+	if created, err := time.Parse(time.RFC3339Nano, detail.CreatedAt); err == nil {
+		replica.CreatedAt = created
+	}
+	if !detail.StartedAt.IsZero() {
+		startedAt := detail.StartedAt
+		replica.State.StartedAt = &startedAt
+	}
+	if !detail.FinishedAt.IsZero() {
+		finishedAt := detail.FinishedAt
+		replica.State.FinishedAt = &finishedAt
+	}
+	for _, m := range detail.Mounts {
+		replica.Mounts = append(replica.Mounts, models.ReplicaMount{
+			Type:        m.Type,
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+			Mode:        m.Mode,
+			ReadWrite:   m.ReadWrite,
+		})
+	}
+	for _, n := range detail.Networks {
+		replica.Networks = append(replica.Networks, models.ReplicaNetwork{
+			Network:    n.Network,
+			IPAddress:  n.IPAddress,
+			Gateway:    n.Gateway,
+			MacAddress: n.MacAddress,
+		})
+	}
+	if len(detail.Ports) > 0 {
+		replica.HostPort, _ = strconv.Atoi(detail.Ports[0].HostPort)
+		replica.InternalPort, _ = strconv.Atoi(detail.Ports[0].ContainerPort)
+	}
+	return replica, nil
+}
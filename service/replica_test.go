@@ -0,0 +1,31 @@
+package service
+
+import "testing"
+
+// TestReplicaEnvironment 测试副本环境变量转换及密钥引用的隐藏
+func TestReplicaEnvironment(t *testing.T) {
+	env := []string{"PATH=/usr/bin", "DB_PASSWORD=hunter2", "DSN=user=app host=db", "EMPTY="}
+	spec := map[string]string{"DB_PASSWORD": "secret://db_password", "DSN": "user=app host=db"}
+
+	got := replicaEnvironment(env, secretEnvRefs(nil, spec))
+	want := map[string]string{
+		"PATH":        "/usr/bin",
+		"DB_PASSWORD": "secret://db_password",
+		"DSN":         "user=app host=db",
+		"EMPTY":       "",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("replicaEnvironment() = %v, 期望 %v", got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %q, 期望 %q", key, got[key], value)
+		}
+	}
+
+	// 没有期望状态时按创建容器时记录的来源隐藏，名称像敏感信息的变量脱敏
+	if got := replicaEnvironment(env, secretEnvRefs(map[string]string{"DSN": "secret://dsn"}, nil)); got["DSN"] != "secret://dsn" ||
+		got["DB_PASSWORD"] != maskedEnvValue || got["PATH"] != "/usr/bin" || got["EMPTY"] != "" {
+		t.Errorf("没有期望状态时 = %v, 期望 DSN 为引用、DB_PASSWORD 脱敏", got)
+	}
+}
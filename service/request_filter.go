@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/gin-gonic/gin"
+)
+
+// compiledRule 预编译的请求过滤规则
+type compiledRule struct {
+	name      string
+	path      *regexp.Regexp
+	methods   map[string]bool
+	userAgent *regexp.Regexp
+	headers   map[string]*regexp.Regexp
+	blocked   int64 // 命中次数
+}
+
+// requestFilter 端口代理的请求过滤器（轻量 WAF）
+type requestFilter struct {
+	rules []*compiledRule
+}
+
+// compileRequestRules 编译请求过滤规则，正则非法时返回错误
+func compileRequestRules(rules []models.RequestRule) (*requestFilter, error) {
+	filter := &requestFilter{rules: make([]*compiledRule, 0, len(rules))}
+
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule-%d", i)
+		}
+		compiled := &compiledRule{
+			name:    name,
+			methods: make(map[string]bool),
+			headers: make(map[string]*regexp.Regexp),
+		}
+
+		if rule.Path == "" && len(rule.Methods) == 0 && rule.UserAgent == "" && len(rule.Headers) == 0 {
+			return nil, fmt.Errorf("request rule %s has no conditions", name)
+		}
+
+		var err error
+		if rule.Path != "" {
+			if compiled.path, err = regexp.Compile(rule.Path); err != nil {
+				return nil, fmt.Errorf("request rule %s: invalid path regex: %w", name, err)
+			}
+		}
+		if rule.UserAgent != "" {
+			if compiled.userAgent, err = regexp.Compile(rule.UserAgent); err != nil {
+				return nil, fmt.Errorf("request rule %s: invalid user_agent regex: %w", name, err)
+			}
+		}
+		for header, pattern := range rule.Headers {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("request rule %s: invalid regex for header %s: %w", name, header, err)
+			}
+			compiled.headers[http.CanonicalHeaderKey(header)] = re
+		}
+		for _, method := range rule.Methods {
+			compiled.methods[strings.ToUpper(method)] = true
+		}
+
+		filter.rules = append(filter.rules, compiled)
+	}
+
+	return filter, nil
+}
+
+// match 返回第一个命中的规则，未命中返回 nil
+func (f *requestFilter) match(r *http.Request) *compiledRule {
+	for _, rule := range f.rules {
+		if rule.matches(r) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// matches 判断请求是否命中规则（所有已设置的条件都需满足）
+func (rule *compiledRule) matches(r *http.Request) bool {
+	if rule.path != nil && !rule.path.MatchString(r.URL.Path) {
+		return false
+	}
+	if len(rule.methods) > 0 && !rule.methods[r.Method] {
+		return false
+	}
+	if rule.userAgent != nil && !rule.userAgent.MatchString(r.UserAgent()) {
+		return false
+	}
+	for header, re := range rule.headers {
+		if !re.MatchString(r.Header.Get(header)) {
+			return false
+		}
+	}
+	return true
+}
+
+// middleware 返回执行过滤规则的 gin 中间件，命中规则的请求直接返回 403
+func (f *requestFilter) middleware(publicPort int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rule := f.match(c.Request); rule != nil {
+			atomic.AddInt64(&rule.blocked, 1)
+			log.Warn("PortProxy", log.Any("PublicPort", publicPort), log.Any("Rule", rule.name),
+				log.Any("Method", c.Request.Method), log.Any("Path", c.Request.URL.Path),
+				log.Any("RemoteAddr", c.Request.RemoteAddr), log.Any("Message", "请求被过滤规则拦截"))
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Request blocked"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// stats 返回各规则的命中次数
+func (f *requestFilter) stats() []models.RequestRuleStats {
+	result := make([]models.RequestRuleStats, 0, len(f.rules))
+	for _, rule := range f.rules {
+		result = append(result, models.RequestRuleStats{
+			Name:    rule.name,
+			Blocked: atomic.LoadInt64(&rule.blocked),
+		})
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestRequestFilterMatch 测试请求过滤规则匹配
+func TestRequestFilterMatch(t *testing.T) {
+	filter, err := compileRequestRules([]models.RequestRule{
+		{Name: "block-wp", Path: `^/wp-(admin|login)`},
+		{Name: "block-delete-api", Path: `^/api/`, Methods: []string{"delete"}},
+		{Name: "block-scanner", UserAgent: `(?i)sqlmap|nikto`},
+		{Name: "block-debug-header", Headers: map[string]string{"x-debug": "^1$"}},
+	})
+	if err != nil {
+		t.Fatalf("编译规则失败: %v", err)
+	}
+
+	cases := []struct {
+		method string
+		path   string
+		ua     string
+		header string
+		want   string
+	}{
+		{"GET", "/wp-login.php", "", "", "block-wp"},
+		{"DELETE", "/api/users/1", "", "", "block-delete-api"},
+		{"GET", "/api/users/1", "", "", ""},
+		{"GET", "/", "sqlmap/1.7", "", "block-scanner"},
+		{"GET", "/", "Mozilla/5.0", "1", "block-debug-header"},
+		{"GET", "/", "Mozilla/5.0", "0", ""},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("User-Agent", tc.ua)
+		if tc.header != "" {
+			req.Header.Set("X-Debug", tc.header)
+		}
+		got := ""
+		if rule := filter.match(req); rule != nil {
+			got = rule.name
+		}
+		if got != tc.want {
+			t.Errorf("%s %s ua=%q: 期望命中 %q，实际 %q", tc.method, tc.path, tc.ua, tc.want, got)
+		}
+	}
+}
+
+// TestCompileRequestRulesInvalid 测试非法规则
+func TestCompileRequestRulesInvalid(t *testing.T) {
+	if _, err := compileRequestRules([]models.RequestRule{{Name: "bad", Path: "("}}); err == nil {
+		t.Error("非法正则应返回错误")
+	}
+	if _, err := compileRequestRules([]models.RequestRule{{Name: "empty"}}); err == nil {
+		t.Error("无条件规则应返回错误")
+	}
+}
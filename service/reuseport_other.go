@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package service
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// reusePortSupported 当前平台是否支持 SO_REUSEPORT
+const reusePortSupported = false
+
+// setReusePort 当前平台不支持 SO_REUSEPORT
+func setReusePort(network, address string, c syscall.RawConn) error {
+	return fmt.Errorf("SO_REUSEPORT is not supported on this platform")
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// rollout 单个服务的滚动更新进度与暂停控制
+type rollout struct {
+	mutex   sync.Mutex
+	wake    chan struct{} // 恢复或中止时关闭并替换，唤醒暂停中的 checkpoint
+	status  models.RolloutStatus
+	aborted bool
+}
+
+// rolloutTracker 记录每个服务最近一次滚动更新
+type rolloutTracker struct {
+	mutex    sync.Mutex
+	rollouts map[string]*rollout
+}
+
+func newRolloutTracker() *rolloutTracker {
+	return &rolloutTracker{
+		rollouts: make(map[string]*rollout),
+	}
+}
+
+// start 开始一次滚动更新，同一服务已有进行中的更新时返回错误
+func (t *rolloutTracker) start(ctx context.IContext, name, image string, total int, strategy *models.UpdateStrategy) (*rollout, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if current, ok := t.rollouts[name]; ok && current.active() {
+		return nil, utils.NewError(utils.CodeConflict, "service %s already has a rollout in progress", name)
+	}
+
+	now := time.Now()
+	r := &rollout{
+		wake: make(chan struct{}),
+		status: models.RolloutStatus{
+			Service:        name,
+			State:          models.RolloutRunning,
+			Image:          image,
+			Total:          total,
+			MaxSurge:       strategy.MaxSurge,
+			MaxUnavailable: strategy.MaxUnavailable,
+			PauseAfter:     strategy.PauseAfter,
+			Actor:          actorFromContext(ctx),
+			StartedAt:      now,
+			UpdatedAt:      now,
+		},
+	}
+	t.rollouts[name] = r
+	return r, nil
+}
+
+// get 获取服务最近一次滚动更新
+func (t *rolloutTracker) get(name string) *rollout {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.rollouts[name]
+}
+
+// active 是否仍在进行中（更新中或已暂停）
+func (r *rollout) active() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.status.State == models.RolloutRunning || r.status.State == models.RolloutPaused
+}
+
+// snapshot 返回当前状态的副本
+func (r *rollout) snapshot() models.RolloutStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.status
+}
+
+// progress 记录单个副本的更新结果
+func (r *rollout) progress(success bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if success {
+		r.status.Updated++
+	} else {
+		r.status.Failed++
+	}
+	r.status.UpdatedAt = time.Now()
+}
+
+// pause 暂停更新，当前批次完成后生效
+func (r *rollout) pause(message string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.status.State != models.RolloutRunning {
+		return fmt.Errorf("rollout is %s, cannot pause", r.status.State)
+	}
+	r.status.State = models.RolloutPaused
+	r.status.Message = message
+	r.status.UpdatedAt = time.Now()
+	return nil
+}
+
+// resume 恢复已暂停的更新
+func (r *rollout) resume() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.status.State != models.RolloutPaused {
+		return fmt.Errorf("rollout is %s, cannot resume", r.status.State)
+	}
+	r.status.State = models.RolloutRunning
+	r.status.Message = ""
+	r.status.UpdatedAt = time.Now()
+	r.broadcast()
+	return nil
+}
+
+// abort 中止更新，已更新的副本会回滚到旧配置
+func (r *rollout) abort() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.status.State != models.RolloutRunning && r.status.State != models.RolloutPaused {
+		return fmt.Errorf("rollout is %s, cannot abort", r.status.State)
+	}
+	r.aborted = true
+	r.status.Message = "aborting"
+	r.status.UpdatedAt = time.Now()
+	r.broadcast()
+	return nil
+}
+
+// broadcast 唤醒所有等待中的 checkpoint，调用方持有锁
+func (r *rollout) broadcast() {
+	close(r.wake)
+	r.wake = make(chan struct{})
+}
+
+// checkpoint 在批次之间调用：已暂停时阻塞直到恢复、中止或 ctx 结束（请求超时、客户端断开），返回 false 表示不再继续
+func (r *rollout) checkpoint(ctx context.IContext) bool {
+	for {
+		r.mutex.Lock()
+		if r.aborted {
+			r.mutex.Unlock()
+			return false
+		}
+		if r.status.State != models.RolloutPaused {
+			r.mutex.Unlock()
+			return true
+		}
+		wake := r.wake
+		r.mutex.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// paused 当前是否处于暂停状态
+func (r *rollout) paused() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.status.State == models.RolloutPaused
+}
+
+// finish 结束更新并记录最终状态
+func (r *rollout) finish(state models.RolloutState, message string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	r.status.State = state
+	r.status.Message = message
+	r.status.UpdatedAt = now
+	r.status.FinishedAt = &now
+}
+
+// GetRollout 获取服务最近一次滚动更新的状态
+func (s *Service) GetRollout(ctx context.IContext, name string) (*models.RolloutStatus, error) {
+	r := s.rollouts.get(name)
+	if r == nil {
+		return nil, fmt.Errorf("no rollout found for service %s", name)
+	}
+	status := r.snapshot()
+	return &status, nil
+}
+
+// PauseRollout 暂停服务进行中的滚动更新，当前批次完成后生效
+func (s *Service) PauseRollout(ctx context.IContext, name string) (*models.RolloutStatus, error) {
+	r := s.rollouts.get(name)
+	if r == nil {
+		return nil, fmt.Errorf("no rollout found for service %s", name)
+	}
+	if err := r.pause(fmt.Sprintf("paused by %s", actorFromContext(ctx))); err != nil {
+		return nil, err
+	}
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "滚动更新已暂停"))
+	status := r.snapshot()
+	return &status, nil
+}
+
+// ResumeRollout 恢复已暂停的滚动更新
+func (s *Service) ResumeRollout(ctx context.IContext, name string) (*models.RolloutStatus, error) {
+	r := s.rollouts.get(name)
+	if r == nil {
+		return nil, fmt.Errorf("no rollout found for service %s", name)
+	}
+	if err := r.resume(); err != nil {
+		return nil, err
+	}
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "滚动更新已恢复"))
+	status := r.snapshot()
+	return &status, nil
+}
+
+// AbortRollout 中止进行中的滚动更新，已更新的副本回滚到旧配置
+func (s *Service) AbortRollout(ctx context.IContext, name string) (*models.RolloutStatus, error) {
+	r := s.rollouts.get(name)
+	if r == nil {
+		return nil, fmt.Errorf("no rollout found for service %s", name)
+	}
+	if err := r.abort(); err != nil {
+		return nil, err
+	}
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "滚动更新中止中"))
+	status := r.snapshot()
+	return &status, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+)
+
+var testLoggerOnce sync.Once
+
+// initTestLogger 初始化只记录错误级别的日志，runBatches 等函数会写日志
+func initTestLogger(t *testing.T) {
+	testLoggerOnce.Do(func() {
+		if err := log.InitLogger(filepath.Join(os.TempDir(), "onedock-test.log"), "error", 1, 1, 1, false); err != nil {
+			t.Fatalf("初始化日志失败: %v", err)
+		}
+	})
+}
+
+// batchRecorder 记录 runBatches 对每个副本的更新调用
+type batchRecorder struct {
+	mutex  sync.Mutex
+	calls  []string
+	failed map[int]bool
+}
+
+func (b *batchRecorder) update(replicaIndex int, removeFirst bool) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.calls = append(b.calls, fmt.Sprintf("%d:%v", replicaIndex, removeFirst))
+	return !b.failed[replicaIndex]
+}
+
+func (b *batchRecorder) count() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.calls)
+}
+
+func startRollout(t *testing.T, total int, strategy *models.UpdateStrategy) *rollout {
+	initTestLogger(t)
+	r, err := newRolloutTracker().start(context.Background(), "nginx-web", "nginx:1.26", total, strategy)
+	if err != nil {
+		t.Fatalf("start() 失败: %v", err)
+	}
+	return r
+}
+
+// TestRunBatches 测试按 max_surge、max_unavailable 分批更新，以及失败副本的计数
+func TestRunBatches(t *testing.T) {
+	strategy := &models.UpdateStrategy{MaxSurge: 1, MaxUnavailable: 1}
+	r := startRollout(t, 5, strategy)
+	recorder := &batchRecorder{failed: map[int]bool{4: true}}
+
+	success, aborted := runBatches(context.Background(), "nginx-web", r, []int{1, 2, 3, 4, 5}, strategy, func() {}, recorder.update)
+	if success != 4 || aborted {
+		t.Errorf("runBatches() = %d, %v, 期望 4 个成功且未中止", success, aborted)
+	}
+	// 每批 2 个副本：第 1 个先建后删，第 2 个先删后建；批内并行，只比较集合
+	want := map[string]bool{"1:false": true, "2:true": true, "3:false": true, "4:true": true, "5:false": true}
+	if len(recorder.calls) != len(want) {
+		t.Fatalf("更新调用 = %v", recorder.calls)
+	}
+	for _, call := range recorder.calls {
+		if !want[call] {
+			t.Errorf("更新调用 %s 不符合预期，全部调用 %v", call, recorder.calls)
+		}
+	}
+	if status := r.snapshot(); status.Updated != 4 || status.Failed != 1 {
+		t.Errorf("进度 = %d 成功 %d 失败, 期望 4 和 1", status.Updated, status.Failed)
+	}
+}
+
+// runPaused 以 pause_after=2 运行 4 个副本的更新，返回等待暂停完成的通道和结果通道
+func runPaused(t *testing.T, ctx context.IContext) (*rollout, *batchRecorder, chan [2]interface{}) {
+	strategy := &models.UpdateStrategy{MaxSurge: 1, PauseAfter: 2}
+	r := startRollout(t, 4, strategy)
+	recorder := &batchRecorder{}
+	paused := make(chan struct{}, 1)
+	result := make(chan [2]interface{}, 1)
+	go func() {
+		success, aborted := runBatches(ctx, "nginx-web", r, []int{1, 2, 3, 4}, strategy, func() { paused <- struct{}{} }, recorder.update)
+		result <- [2]interface{}{success, aborted}
+	}()
+
+	select {
+	case <-paused:
+	case <-time.After(time.Second):
+		t.Fatal("达到 pause_after 后期望暂停")
+	}
+	if r.snapshot().State != models.RolloutPaused || recorder.count() != 2 {
+		t.Fatalf("暂停时状态 = %s, 已更新 %d 个副本, 期望 paused 和 2", r.snapshot().State, recorder.count())
+	}
+	return r, recorder, result
+}
+
+// waitResult 等待 runBatches 返回
+func waitResult(t *testing.T, result chan [2]interface{}) (int, bool) {
+	select {
+	case res := <-result:
+		return res[0].(int), res[1].(bool)
+	case <-time.After(time.Second):
+		t.Fatal("runBatches 没有返回")
+		return 0, false
+	}
+}
+
+// TestRunBatchesPause 测试暂停后恢复、中止，以及暂停期间请求结束
+func TestRunBatchesPause(t *testing.T) {
+	t.Run("恢复", func(t *testing.T) {
+		r, recorder, result := runPaused(t, context.Background())
+		if err := r.resume(); err != nil {
+			t.Fatalf("resume() 失败: %v", err)
+		}
+		if success, aborted := waitResult(t, result); success != 4 || aborted || recorder.count() != 4 {
+			t.Errorf("恢复后 = %d, %v, 期望 4 个成功且未中止", success, aborted)
+		}
+	})
+
+	t.Run("中止", func(t *testing.T) {
+		r, recorder, result := runPaused(t, context.Background())
+		if err := r.abort(); err != nil {
+			t.Fatalf("abort() 失败: %v", err)
+		}
+		if success, aborted := waitResult(t, result); success != 2 || !aborted || recorder.count() != 2 {
+			t.Errorf("中止后 = %d, %v, 期望停在 2 个副本且已中止", success, aborted)
+		}
+	})
+
+	t.Run("请求结束", func(t *testing.T) {
+		ctx, cancel := context.Background().WithCancel()
+		_, recorder, result := runPaused(t, ctx)
+		cancel()
+		if success, aborted := waitResult(t, result); success != 2 || !aborted || recorder.count() != 2 {
+			t.Errorf("请求结束后 = %d, %v, 期望停在 2 个副本且视为中止", success, aborted)
+		}
+	})
+}
+
+// TestRolloutTransitions 测试滚动更新状态的合法转换以及同一服务不能同时进行两次更新
+func TestRolloutTransitions(t *testing.T) {
+	tracker := newRolloutTracker()
+	strategy := &models.UpdateStrategy{MaxSurge: 1}
+	r, err := tracker.start(context.Background(), "nginx-web", "nginx:1.26", 2, strategy)
+	if err != nil {
+		t.Fatalf("start() 失败: %v", err)
+	}
+	if _, err := tracker.start(context.Background(), "nginx-web", "nginx:1.27", 2, strategy); err == nil {
+		t.Error("已有进行中的更新时期望返回错误")
+	}
+
+	if !r.checkpoint(context.Background()) {
+		t.Error("更新中 checkpoint 期望继续")
+	}
+	if err := r.resume(); err == nil {
+		t.Error("未暂停时 resume 期望返回错误")
+	}
+	if err := r.pause("manual"); err != nil {
+		t.Fatalf("pause() 失败: %v", err)
+	}
+	if err := r.pause("again"); err == nil {
+		t.Error("已暂停时 pause 期望返回错误")
+	}
+
+	r.finish(models.RolloutCompleted, "")
+	if err := r.abort(); err == nil {
+		t.Error("已结束的更新 abort 期望返回错误")
+	}
+	if _, err := tracker.start(context.Background(), "nginx-web", "nginx:1.27", 2, strategy); err != nil {
+		t.Errorf("上一次更新结束后期望可以开始新的更新: %v", err)
+	}
+}
+
+// TestResolveUpdateStrategy 测试更新策略的默认值和校验
+func TestResolveUpdateStrategy(t *testing.T) {
+	autoRollback := false
+	resolved, err := resolveUpdateStrategy(&models.UpdateStrategy{MaxUnavailable: 2, AutoRollback: &autoRollback})
+	if err != nil || resolved.MaxSurge != 0 || resolved.MaxUnavailable != 2 || *resolved.AutoRollback {
+		t.Errorf("resolveUpdateStrategy() = %+v, %v", resolved, err)
+	}
+
+	for _, invalid := range []*models.UpdateStrategy{
+		{MaxSurge: -1, MaxUnavailable: 1},
+		{MaxSurge: 0, MaxUnavailable: 0},
+		{MaxSurge: 1, PauseAfter: -1},
+	} {
+		if _, err := resolveUpdateStrategy(invalid); err == nil {
+			t.Errorf("resolveUpdateStrategy(%+v) 期望返回错误", invalid)
+		}
+	}
+}
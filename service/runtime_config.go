@@ -0,0 +1,120 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// runtimeConfigMutex 串行执行运行时配置的修改，避免并发写配置文件
+var runtimeConfigMutex sync.Mutex
+
+// GetRuntimeConfig 读取可在运行时修改的配置的当前值
+func (s *Service) GetRuntimeConfig() *models.RuntimeConfig {
+	settings := loadProxySettings()
+	autoRange, _, _, _ := autoPortRange()
+	level := strings.ToUpper(utils.ConfGetString("local.logger.level"))
+	if level == "" {
+		level = "INFO"
+	}
+	return &models.RuntimeConfig{
+		CacheTTL:            utils.ConfGetInt("container.cache_ttl"),
+		LoadBalanceStrategy: string(settings.Strategy),
+		AutoRange:           autoRange,
+		MinPublicPort:       utils.ConfGetIntDefault("ports.min_public_port", defaultMinPublicPort),
+		ProxyReadTimeout:    int(settings.ReadTimeout.Seconds()),
+		ProxyWriteTimeout:   int(settings.WriteTimeout.Seconds()),
+		LogLevel:            level,
+		ConfigFile:          utils.ConfigFile(),
+	}
+}
+
+// runtimeConfigValues 校验修改请求，转换为配置路径和 TOML 字面量
+func runtimeConfigValues(req *models.RuntimeConfigUpdate) (map[string]string, error) {
+	values := make(map[string]string)
+	if req.CacheTTL != nil {
+		if *req.CacheTTL <= 0 {
+			return nil, utils.NewError(utils.CodeInvalidRequest, "cache_ttl must be greater than 0")
+		}
+		values["container.cache_ttl"] = strconv.Itoa(*req.CacheTTL)
+	}
+	if req.LoadBalanceStrategy != nil {
+		if !loadBalanceStrategies[LoadBalanceStrategy(*req.LoadBalanceStrategy)] {
+			return nil, utils.NewError(utils.CodeInvalidRequest, "invalid load balance strategy %q", *req.LoadBalanceStrategy)
+		}
+		values["container.load_balance_strategy"] = strconv.Quote(*req.LoadBalanceStrategy)
+	}
+	if req.AutoRange != nil {
+		low, high, err := parsePortRange(*req.AutoRange)
+		if err != nil {
+			return nil, utils.WithCode(utils.CodeInvalidRequest, err)
+		}
+		values["ports.auto_range"] = strconv.Quote(strconv.Itoa(low) + "-" + strconv.Itoa(high))
+	}
+	if req.MinPublicPort != nil {
+		if *req.MinPublicPort < 1 || *req.MinPublicPort > 65535 {
+			return nil, utils.NewError(utils.CodeInvalidRequest, "min_public_port must be a port between 1 and 65535")
+		}
+		values["ports.min_public_port"] = strconv.Itoa(*req.MinPublicPort)
+	}
+	if req.ProxyReadTimeout != nil {
+		if *req.ProxyReadTimeout <= 0 {
+			return nil, utils.NewError(utils.CodeInvalidRequest, "proxy_read_timeout must be greater than 0")
+		}
+		values["proxy.read_timeout"] = strconv.Itoa(*req.ProxyReadTimeout)
+	}
+	if req.ProxyWriteTimeout != nil {
+		if *req.ProxyWriteTimeout <= 0 {
+			return nil, utils.NewError(utils.CodeInvalidRequest, "proxy_write_timeout must be greater than 0")
+		}
+		values["proxy.write_timeout"] = strconv.Itoa(*req.ProxyWriteTimeout)
+	}
+	if req.LogLevel != nil {
+		level := strings.ToUpper(*req.LogLevel)
+		switch level {
+		case "DEBUG", "INFO", "WARN", "ERROR":
+		default:
+			return nil, utils.NewError(utils.CodeInvalidRequest, "invalid log level %q", *req.LogLevel)
+		}
+		values["local.logger.level"] = strconv.Quote(level)
+	}
+	if len(values) == 0 {
+		return nil, utils.NewError(utils.CodeInvalidRequest, "no config values to update")
+	}
+	return values, nil
+}
+
+// UpdateRuntimeConfig 修改运行时配置：写回配置文件、重新读取并立即生效
+// 负载均衡策略和代理超时应用到运行中的端口代理，日志级别重新初始化日志；缓存时间、端口范围在下次使用时读取
+func (s *Service) UpdateRuntimeConfig(ctx context.IContext, req *models.RuntimeConfigUpdate) (*models.RuntimeConfig, error) {
+	values, err := runtimeConfigValues(req)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeConfigMutex.Lock()
+	defer runtimeConfigMutex.Unlock()
+
+	if err := utils.WriteConfigValues(values); err != nil {
+		log.Error("Config", log.Any("Error", err), log.Any("File", utils.ConfigFile()), log.Any("Message", "写回配置文件失败"))
+		return nil, utils.NewError(utils.CodeOperationFailed, "failed to write config file: %w", err)
+	}
+	if req.LoadBalanceStrategy != nil || req.ProxyReadTimeout != nil || req.ProxyWriteTimeout != nil {
+		if _, err := s.PortManager.ReloadConfig(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if req.LogLevel != nil {
+		if err := utils.ReloadLogger(); err != nil {
+			return nil, utils.NewError(utils.CodeOperationFailed, "failed to apply log level: %w", err)
+		}
+	}
+
+	log.Info("Config", log.Any("Values", values), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "运行时配置已更新"))
+	return s.GetRuntimeConfig(), nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// TestRuntimeConfigValues 测试运行时配置修改请求的校验和转换
+func TestRuntimeConfigValues(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	strPtr := func(v string) *string { return &v }
+
+	values, err := runtimeConfigValues(&models.RuntimeConfigUpdate{
+		CacheTTL:            intPtr(60),
+		LoadBalanceStrategy: strPtr("least_connections"),
+		AutoRange:           strPtr(" 21000 - 21999 "),
+		LogLevel:            strPtr("debug"),
+	})
+	if err != nil {
+		t.Fatalf("runtimeConfigValues() error = %v", err)
+	}
+	want := map[string]string{
+		"container.cache_ttl":             "60",
+		"container.load_balance_strategy": `"least_connections"`,
+		"ports.auto_range":                `"21000-21999"`,
+		"local.logger.level":              `"DEBUG"`,
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("runtimeConfigValues() = %v, 期望 %v", values, want)
+	}
+
+	invalid := []*models.RuntimeConfigUpdate{
+		{},
+		{CacheTTL: intPtr(0)},
+		{LoadBalanceStrategy: strPtr("random")},
+		{AutoRange: strPtr("30000-20000")},
+		{MinPublicPort: intPtr(70000)},
+		{ProxyWriteTimeout: intPtr(-1)},
+		{LogLevel: strPtr("verbose")},
+	}
+	for _, req := range invalid {
+		if _, err := runtimeConfigValues(req); utils.ErrorCode(err) != utils.CodeInvalidRequest {
+			t.Errorf("runtimeConfigValues(%+v) 错误码 = %s, 期望 %s", req, utils.ErrorCode(err), utils.CodeInvalidRequest)
+		}
+	}
+}
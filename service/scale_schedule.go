@@ -0,0 +1,165 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/cron"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// startScaleScheduler 启动定时扩缩容，每分钟整点检查一次规则
+func (s *Service) startScaleScheduler() {
+	go func() {
+		for {
+			next := time.Now().Truncate(time.Minute).Add(time.Minute)
+			select {
+			case <-s.stopping:
+				return
+			case <-time.After(time.Until(next)):
+			}
+			s.runScaleSchedules(context.Background(), next)
+		}
+	}()
+	log.Info("Schedule", log.Any("Message", "定时扩缩容已启动"))
+}
+
+// runScaleSchedules 执行到点的规则，同一服务同一分钟有多条规则时按 ID 顺序执行，以最后一条为准
+func (s *Service) runScaleSchedules(ctx context.IContext, now time.Time) {
+	schedules, err := s.store.ListScaleSchedules("")
+	if err != nil {
+		log.Error("Schedule", log.Any("Error", err), log.Any("Message", "读取定时扩缩容规则失败"))
+		return
+	}
+
+	for _, rule := range schedules {
+		schedule, err := cron.Parse(rule.Cron)
+		if err != nil {
+			log.Error("Schedule", log.Any("Error", err), log.Any("ServiceName", rule.Service), log.Any("ScheduleID", rule.ID), log.Any("Message", "cron 表达式无效"))
+			continue
+		}
+		if !schedule.Match(now) {
+			continue
+		}
+
+		lastError := ""
+		if err := s.runScaleSchedule(ctx, rule); err != nil {
+			lastError = err.Error()
+			log.Error("Schedule", log.Any("Error", err), log.Any("ServiceName", rule.Service), log.Any("ScheduleID", rule.ID), log.Any("Message", "定时扩缩容失败"))
+		}
+		if err := s.store.UpdateScaleScheduleRun(rule.ID, now, lastError); err != nil {
+			log.Error("Schedule", log.Any("Error", err), log.Any("ScheduleID", rule.ID), log.Any("Message", "记录定时扩缩容结果失败"))
+		}
+	}
+}
+
+// runScaleSchedule 执行单条规则；已停止的服务不处理，副本数一致时跳过
+func (s *Service) runScaleSchedule(ctx context.IContext, rule *store.ScaleSchedule) error {
+	service := s.GetService(ctx, rule.Service)
+	if service == nil {
+		return errServiceNotFound(rule.Service)
+	}
+	if spec, err := s.store.GetServiceSpec(rule.Service); err == nil && spec != nil && spec.Stopped {
+		log.Info("Schedule", log.Any("ServiceName", rule.Service), log.Any("ScheduleID", rule.ID), log.Any("Message", "服务已停止，跳过定时扩缩容"))
+		return nil
+	}
+	if r := s.rollouts.get(rule.Service); r != nil && r.active() {
+		return fmt.Errorf("rollout in progress")
+	}
+	if release := s.pendingRelease(rule.Service); release != nil {
+		return fmt.Errorf("%s release in progress", release.Mode)
+	}
+	if s.serviceLock(rule.Service) != nil {
+		return fmt.Errorf("service locked")
+	}
+	if s.operations.busy(rule.Service) {
+		return fmt.Errorf("operation in progress")
+	}
+	if service.Replicas == rule.Replicas {
+		return nil
+	}
+
+	log.Info("Schedule", log.Any("ServiceName", rule.Service), log.Any("ScheduleID", rule.ID), log.Any("From", service.Replicas),
+		log.Any("To", rule.Replicas), log.Any("Message", "执行定时扩缩容"))
+	return s.ScaleService(ctx, rule.Service, rule.Replicas)
+}
+
+// toScaleSchedule 将存储中的规则转换为 API 模型
+func toScaleSchedule(rule *store.ScaleSchedule) *models.ScaleSchedule {
+	result := &models.ScaleSchedule{
+		ID:        rule.ID,
+		Service:   rule.Service,
+		Cron:      rule.Cron,
+		Replicas:  rule.Replicas,
+		Note:      rule.Note,
+		LastError: rule.LastError,
+		CreatedAt: rule.CreatedAt,
+	}
+	if schedule, err := cron.Parse(rule.Cron); err == nil {
+		if next := schedule.Next(time.Now()); !next.IsZero() {
+			result.NextRunAt = &next
+		}
+	}
+	if !rule.LastRunAt.IsZero() {
+		lastRunAt := rule.LastRunAt
+		result.LastRunAt = &lastRunAt
+	}
+	return result
+}
+
+// AddScaleSchedule 为服务添加定时扩缩容规则
+func (s *Service) AddScaleSchedule(ctx context.IContext, name string, req *models.ScaleScheduleRequest) (*models.ScaleSchedule, error) {
+	if _, err := cron.Parse(req.Cron); err != nil {
+		return nil, err
+	}
+	if req.Replicas < 1 {
+		return nil, fmt.Errorf("replicas must be at least 1, use DELETE to remove a service")
+	}
+	if s.GetService(ctx, name) == nil {
+		return nil, errServiceNotFound(name)
+	}
+
+	rule := &store.ScaleSchedule{
+		Service:  name,
+		Cron:     req.Cron,
+		Replicas: req.Replicas,
+		Note:     req.Note,
+	}
+	if err := s.store.AddScaleSchedule(rule); err != nil {
+		return nil, err
+	}
+
+	log.Info("Schedule", log.Any("ServiceName", name), log.Any("Cron", req.Cron), log.Any("Replicas", req.Replicas),
+		log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "添加定时扩缩容规则"))
+	return toScaleSchedule(rule), nil
+}
+
+// ListScaleSchedules 列出服务的定时扩缩容规则
+func (s *Service) ListScaleSchedules(ctx context.IContext, name string) ([]*models.ScaleSchedule, error) {
+	rules, err := s.store.ListScaleSchedules(name)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.ScaleSchedule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, toScaleSchedule(rule))
+	}
+	return result, nil
+}
+
+// DeleteScaleSchedule 删除服务的定时扩缩容规则
+func (s *Service) DeleteScaleSchedule(ctx context.IContext, name string, id int64) error {
+	exists, err := s.store.DeleteScaleSchedule(name, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return utils.NewError(utils.CodeNotFound, "schedule %d of service %s not found", id, name)
+	}
+	log.Info("Schedule", log.Any("ServiceName", name), log.Any("ScheduleID", id), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "删除定时扩缩容规则"))
+	return nil
+}
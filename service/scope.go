@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// checkServiceScope 请求令牌限定了服务范围且服务不在范围内时返回错误
+// 带服务名称的接口由权限验证中间件校验，这里校验部署、批量部署、克隆、重命名等请求体中的服务名称
+func checkServiceScope(ctx context.IContext, name string) error {
+	if ctx == nil {
+		return nil
+	}
+	value, _ := ctx.Get(models.ContextKeyScope)
+	scope, _ := value.(*models.TokenScope)
+	if !scope.Allows(name) {
+		return utils.NewError(utils.CodeForbidden, "token is not allowed to operate service %s", name)
+	}
+	return nil
+}
+
+// ScopedServices 只保留令牌服务范围内的服务，用于服务列表、搜索等返回多个服务的查询
+func ScopedServices(ctx context.IContext, services []*models.Service) []*models.Service {
+	result := make([]*models.Service, 0, len(services))
+	for _, service := range services {
+		if checkServiceScope(ctx, service.Name) == nil {
+			result = append(result, service)
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// serviceStatuses 可用于搜索的服务状态
+var serviceStatuses = map[models.ServiceStatus]bool{
+	models.StatusStopped:  true,
+	models.StatusStarting: true,
+	models.StatusRunning:  true,
+	models.StatusStopping: true,
+	models.StatusFailed:   true,
+	models.StatusUpdating: true,
+}
+
+// matchImage 服务镜像是否符合搜索条件：镜像名称相同，或仓库路径的最后一段相同（nginx 匹配 library/nginx）；
+// 条件带标签时还要求标签相同
+func matchImage(image, tag, query string) bool {
+	name, wantTag := query, ""
+	if i := strings.LastIndex(query, ":"); i > strings.LastIndex(query, "/") {
+		name, wantTag = query[:i], query[i+1:]
+	}
+	if wantTag != "" && tag != wantTag {
+		return false
+	}
+	return image == name || strings.HasSuffix(image, "/"+name)
+}
+
+// matchService 服务是否符合搜索条件
+func matchService(service *models.Service, query *models.ServiceQuery, selector labelSelector) bool {
+	if query.Namespace != "" && service.Namespace != query.Namespace {
+		return false
+	}
+	if query.Image != "" && !matchImage(service.Image, service.Tag, query.Image) {
+		return false
+	}
+	if query.Status != "" && service.Status != query.Status {
+		return false
+	}
+	if query.Port != 0 && service.PublicPort != query.Port && service.InternalPort != query.Port {
+		return false
+	}
+	return selector.matches(service.Labels)
+}
+
+// SearchServices 按命名空间、标签、镜像、状态和端口搜索服务，按名称排序
+func (s *Service) SearchServices(ctx context.IContext, query *models.ServiceQuery) ([]*models.Service, error) {
+	selector, err := parseLabelSelector(query.Selector)
+	if err != nil {
+		return nil, err
+	}
+	if query.Status != "" && !serviceStatuses[query.Status] {
+		return nil, utils.NewError(utils.CodeInvalidRequest, "invalid status %q", query.Status)
+	}
+	if query.Port < 0 || query.Port > 65535 {
+		return nil, utils.NewError(utils.CodeInvalidRequest, "port must be between 1 and 65535")
+	}
+
+	services := s.AttachLabels(s.ListServices(ctx))
+	result := make([]*models.Service, 0, len(services))
+	for _, service := range services {
+		if matchService(service, query, selector) {
+			result = append(result, service)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// AttachLabels 为服务填充期望状态中的用户标签
+func (s *Service) AttachLabels(services []*models.Service) []*models.Service {
+	labels := s.serviceLabels()
+	for _, service := range services {
+		service.Labels = labels[service.Name]
+	}
+	return services
+}
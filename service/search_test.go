@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+)
+
+// TestMatchImage 测试镜像搜索条件的匹配
+func TestMatchImage(t *testing.T) {
+	tests := []struct {
+		image, tag, query string
+		want              bool
+	}{
+		{"nginx", "alpine", "nginx", true},
+		{"library/nginx", "alpine", "nginx", true},
+		{"registry.example.com:5000/team/nginx", "1.25", "nginx", true},
+		{"registry.example.com:5000/team/nginx", "1.25", "registry.example.com:5000/team/nginx", true},
+		{"nginx", "alpine", "nginx:alpine", true},
+		{"nginx", "alpine", "nginx:latest", false},
+		{"my-nginx", "alpine", "nginx", false},
+		{"redis", "7", "nginx", false},
+	}
+	for _, tt := range tests {
+		if got := matchImage(tt.image, tt.tag, tt.query); got != tt.want {
+			t.Errorf("matchImage(%q, %q, %q) = %v, 期望 %v", tt.image, tt.tag, tt.query, got, tt.want)
+		}
+	}
+}
+
+// TestMatchService 测试服务搜索条件的组合匹配
+func TestMatchService(t *testing.T) {
+	service := &models.Service{
+		Name:         "staging.web",
+		Namespace:    "staging",
+		Image:        "nginx",
+		Tag:          "alpine",
+		Status:       models.StatusRunning,
+		PublicPort:   9303,
+		InternalPort: 80,
+		Labels:       map[string]string{"team": "payments"},
+	}
+	tests := []struct {
+		name  string
+		query models.ServiceQuery
+		want  bool
+	}{
+		{"无条件", models.ServiceQuery{}, true},
+		{"标签和镜像", models.ServiceQuery{Selector: "team=payments", Image: "nginx"}, true},
+		{"标签不符", models.ServiceQuery{Selector: "team=search"}, false},
+		{"公共端口", models.ServiceQuery{Port: 9303}, true},
+		{"内部端口", models.ServiceQuery{Port: 80}, true},
+		{"端口不符", models.ServiceQuery{Port: 9304}, false},
+		{"状态不符", models.ServiceQuery{Status: models.StatusStopped}, false},
+		{"命名空间不符", models.ServiceQuery{Namespace: "default", Image: "nginx"}, false},
+	}
+	for _, tt := range tests {
+		selector, err := parseLabelSelector(tt.query.Selector)
+		if err != nil {
+			t.Fatalf("%s: parseLabelSelector() = %v", tt.name, err)
+		}
+		if got := matchService(service, &tt.query, selector); got != tt.want {
+			t.Errorf("%s: matchService() = %v, 期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestScopedServices 测试服务列表和搜索结果按令牌服务范围过滤
+func TestScopedServices(t *testing.T) {
+	services := []*models.Service{{Name: "app-a"}, {Name: "staging.app-b"}, {Name: "prod.app-c"}}
+
+	if got := ScopedServices(context.Background(), services); len(got) != 3 {
+		t.Errorf("未限制范围时返回 %d 个服务, 期望全部 3 个", len(got))
+	}
+
+	ctx := context.Background()
+	ctx.Set(models.ContextKeyScope, &models.TokenScope{Services: []string{"app-a"}, Namespaces: []string{"staging"}})
+	got := ScopedServices(ctx, services)
+	if len(got) != 2 || got[0].Name != "app-a" || got[1].Name != "staging.app-b" {
+		names := make([]string, 0, len(got))
+		for _, service := range got {
+			names = append(names, service.Name)
+		}
+		t.Errorf("ScopedServices() = %v, 期望 [app-a staging.app-b]", names)
+	}
+}
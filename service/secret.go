@@ -0,0 +1,230 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// secretNamePattern 密钥名称：字母、数字、下划线、点和连字符
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// secretKey 读取 secret.key 并派生 AES-256 密钥
+func secretKey() ([]byte, error) {
+	key := utils.ConfGetString("secret.key")
+	if key == "" {
+		return nil, fmt.Errorf("secret.key is not configured")
+	}
+	sum := sha256.Sum256([]byte(key))
+	return sum[:], nil
+}
+
+// encryptSecret AES-GCM 加密，随机 nonce 放在密文前，结果为 base64
+func encryptSecret(key []byte, plaintext string) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), nil)), nil
+}
+
+// decryptSecret 解密 encryptSecret 的结果
+func decryptSecret(key []byte, ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt, secret.key may have changed: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// secretRefs 收集环境变量中引用的密钥名称（去重、排序）
+func secretRefs(env map[string]string) []string {
+	names := make([]string, 0)
+	for _, value := range env {
+		if strings.HasPrefix(value, models.SecretRefPrefix) {
+			names = append(names, strings.TrimPrefix(value, models.SecretRefPrefix))
+		}
+	}
+	sort.Strings(names)
+	return uniqueStrings(names)
+}
+
+// checkSecretRefs 检查环境变量引用的密钥是否都存在，部署前调用以便尽早失败
+func (s *Service) checkSecretRefs(env map[string]string) error {
+	var missing []string
+	for _, name := range secretRefs(env) {
+		secret, err := s.store.GetSecret(name)
+		if err != nil {
+			return err
+		}
+		if secret == nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return utils.NewError(utils.CodeNotFound, "secrets not found: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// resolveSecrets 将 secret://<名称> 替换为密钥明文，创建容器时由 dockerclient 调用
+func (s *Service) resolveSecrets(ctx context.IContext, env map[string]string) (map[string]string, error) {
+	if len(secretRefs(env)) == 0 {
+		return env, nil
+	}
+	key, err := secretKey()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]string, len(env))
+	for k, v := range env {
+		if !strings.HasPrefix(v, models.SecretRefPrefix) {
+			resolved[k] = v
+			continue
+		}
+		name := strings.TrimPrefix(v, models.SecretRefPrefix)
+		secret, err := s.store.GetSecret(name)
+		if err != nil {
+			return nil, err
+		}
+		if secret == nil {
+			return nil, utils.NewError(utils.CodeNotFound, "secret %s referenced by %s not found", name, k)
+		}
+		if resolved[k], err = decryptSecret(key, secret.Value); err != nil {
+			return nil, fmt.Errorf("secret %s: %w", name, err)
+		}
+	}
+	return resolved, nil
+}
+
+// secretUsers 按期望状态统计每个密钥被哪些服务引用
+func (s *Service) secretUsers() (map[string][]string, error) {
+	specs, err := s.store.ListServiceSpecs()
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[string][]string)
+	for _, spec := range specs {
+		var req models.ServiceRequest
+		if err := utils.DeJson(spec.Spec, &req); err != nil {
+			continue
+		}
+		for _, name := range secretRefs(req.Environment) {
+			users[name] = append(users[name], spec.Name)
+		}
+	}
+	return users, nil
+}
+
+// toSecret 将存储中的密钥转换为 API 模型（不含密钥值）
+func toSecret(secret *store.Secret, users map[string][]string) *models.Secret {
+	return &models.Secret{
+		Name:      secret.Name,
+		UsedBy:    users[secret.Name],
+		CreatedAt: secret.CreatedAt,
+		UpdatedAt: secret.UpdatedAt,
+	}
+}
+
+// SaveSecret 创建或更新密钥，已运行的副本在重新创建后才会使用新值
+func (s *Service) SaveSecret(ctx context.IContext, name, value string) (*models.Secret, error) {
+	if !secretNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid secret name %q: only letters, digits, '_', '.' and '-' are allowed", name)
+	}
+	key, err := secretKey()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := encryptSecret(key, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	record := &store.Secret{Name: name, Value: ciphertext}
+	if err := s.store.SaveSecret(record); err != nil {
+		return nil, err
+	}
+	users, err := s.secretUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Secret", log.Any("Secret", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "保存密钥"))
+	return toSecret(record, users), nil
+}
+
+// ListSecrets 列出全部密钥（不含密钥值）
+func (s *Service) ListSecrets(ctx context.IContext) ([]*models.Secret, error) {
+	secrets, err := s.store.ListSecrets()
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.secretUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.Secret, 0, len(secrets))
+	for _, secret := range secrets {
+		result = append(result, toSecret(secret, users))
+	}
+	return result, nil
+}
+
+// DeleteSecret 删除密钥，仍被服务引用时拒绝删除
+func (s *Service) DeleteSecret(ctx context.IContext, name string) error {
+	users, err := s.secretUsers()
+	if err != nil {
+		return err
+	}
+	if len(users[name]) > 0 {
+		return fmt.Errorf("secret %s is used by services: %s", name, strings.Join(users[name], ", "))
+	}
+
+	exists, err := s.store.DeleteSecret(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return utils.NewError(utils.CodeNotFound, "secret %s not found", name)
+	}
+	log.Info("Secret", log.Any("Secret", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "删除密钥"))
+	return nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+// TestSecretCipher 测试密钥加解密
+func TestSecretCipher(t *testing.T) {
+	sum := sha256.Sum256([]byte("test-key"))
+	key := sum[:]
+
+	ciphertext, err := encryptSecret(key, "s3cr3t")
+	if err != nil {
+		t.Fatalf("加密失败: %v", err)
+	}
+	if ciphertext == "s3cr3t" {
+		t.Fatal("密文不应等于明文")
+	}
+	if again, _ := encryptSecret(key, "s3cr3t"); again == ciphertext {
+		t.Error("相同明文每次加密的密文应不同")
+	}
+
+	plaintext, err := decryptSecret(key, ciphertext)
+	if err != nil || plaintext != "s3cr3t" {
+		t.Errorf("decryptSecret = %q, %v, want s3cr3t", plaintext, err)
+	}
+
+	other := sha256.Sum256([]byte("other-key"))
+	if _, err := decryptSecret(other[:], ciphertext); err == nil {
+		t.Error("使用错误的密钥解密应失败")
+	}
+}
+
+// TestSecretRefs 测试收集环境变量中的密钥引用
+func TestSecretRefs(t *testing.T) {
+	refs := secretRefs(map[string]string{
+		"DB_PASSWORD": "secret://db_password",
+		"DB_REPLICA":  "secret://db_password",
+		"API_KEY":     "secret://api_key",
+		"LOG_LEVEL":   "info",
+	})
+	want := []string{"api_key", "db_password"}
+	if len(refs) != len(want) || refs[0] != want[0] || refs[1] != want[1] {
+		t.Errorf("secretRefs = %v, want %v", refs, want)
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// labelRequirement 标签选择器中的一项条件，value 为空表示只要求存在该标签
+type labelRequirement struct {
+	key   string
+	value string
+	equal bool
+}
+
+// labelSelector 标签选择器，全部条件满足才选中
+type labelSelector []labelRequirement
+
+// parseLabelSelector 解析标签选择器：逗号分隔的 key=value 或 key
+func parseLabelSelector(selector string) (labelSelector, error) {
+	var result labelSelector
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, equal := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, utils.NewError(utils.CodeInvalidRequest, "invalid label selector %q: empty key", part)
+		}
+		result = append(result, labelRequirement{key: key, value: strings.TrimSpace(value), equal: equal})
+	}
+	return result, nil
+}
+
+// matches 标签是否满足全部条件
+func (selector labelSelector) matches(labels map[string]string) bool {
+	for _, req := range selector {
+		value, ok := labels[req.key]
+		if !ok || (req.equal && value != req.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// serviceLabels 读取全部服务期望状态中的用户标签
+func (s *Service) serviceLabels() map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	specs, err := s.store.ListServiceSpecs()
+	if err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("Message", "读取服务期望状态失败"))
+		return result
+	}
+	for _, spec := range specs {
+		var req models.ServiceRequest
+		if err := utils.DeJson(spec.Spec, &req); err != nil {
+			log.Error("Store", log.Any("Error", err), log.Any("ServiceName", spec.Name), log.Any("Message", "解析服务期望状态失败"))
+			continue
+		}
+		if len(req.Labels) > 0 {
+			result[spec.Name] = req.Labels
+		}
+	}
+	return result
+}
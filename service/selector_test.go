@@ -0,0 +1,33 @@
+package service
+
+import "testing"
+
+// TestLabelSelector 测试标签选择器的解析与匹配
+func TestLabelSelector(t *testing.T) {
+	selector, err := parseLabelSelector(" env=preview , team ")
+	if err != nil {
+		t.Fatalf("parseLabelSelector() = %v", err)
+	}
+	tests := []struct {
+		labels map[string]string
+		want   bool
+	}{
+		{map[string]string{"env": "preview", "team": "payments"}, true},
+		{map[string]string{"env": "preview", "team": ""}, true},
+		{map[string]string{"env": "production", "team": "payments"}, false},
+		{map[string]string{"env": "preview"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := selector.matches(tt.labels); got != tt.want {
+			t.Errorf("matches(%v) = %v, 期望 %v", tt.labels, got, tt.want)
+		}
+	}
+
+	if empty, err := parseLabelSelector(""); err != nil || len(empty) != 0 || !empty.matches(nil) {
+		t.Errorf("空选择器应匹配全部服务, 实际 %v, %v", empty, err)
+	}
+	if _, err := parseLabelSelector("=preview"); err == nil {
+		t.Error("缺少标签名时应返回错误")
+	}
+}
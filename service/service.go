@@ -2,18 +2,38 @@ package service
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/aichy126/igo/context"
 	"github.com/aichy126/igo/log"
 	"github.com/aichy126/onedock/library/cache"
 	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/library/graphql"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
 )
 
 // Service
 type Service struct {
-	Cache        *cache.MemCache
+	Cache        cache.Cache // 端口映射缓存，使用 Redis 时多个实例共享
 	dockerClient *dockerclient.DockerClient
 	PortManager  *PortProxyManager
+	store        *store.Store
+	rollouts     *rolloutTracker
+	reconciler   reconciler
+	autoscaler   autoscaler
+	jobs         jobRunner
+	operations   operationQueue // 按服务串行执行修改操作
+	events       eventHub       // 实时事件流的订阅者
+	gitops       gitopsSyncer
+	stopping     chan struct{} // 关闭后后台循环退出
+	stopOnce     sync.Once
+	streams      chan struct{} // 关闭后实时日志等长连接结束
+	streamsOnce  sync.Once
+	recovered    atomic.Bool // 启动时的端口代理恢复已完成
+	graphqlOnce  sync.Once
+	graphql      *graphql.Schema // 只读 GraphQL 查询的模式，首次查询时创建
 }
 
 // NewService
@@ -24,22 +44,59 @@ func NewService() *Service {
 		return nil
 	}
 
+	stateStore, err := store.NewStore()
+	if err != nil {
+		log.Error("Store", log.Any("Error", fmt.Sprintf("failed to open state store: %v", err)))
+		return nil
+	}
+
+	mappingCache, err := cache.NewCache()
+	if err != nil {
+		log.Error("Cache", log.Any("Error", err), log.Any("Message", "缓存后端不可用，使用内存缓存"))
+		mappingCache = cache.NewMemCache()
+	}
+
 	service := &Service{
-		Cache:        cache.NewMemCache(),
+		Cache:        mappingCache,
 		dockerClient: docekrClient,
+		store:        stateStore,
+		rollouts:     newRolloutTracker(),
+		stopping:     make(chan struct{}),
+		streams:      make(chan struct{}),
 	}
 
+	// 创建容器时替换环境变量占位符，并将 secret:// 引用替换为密钥值
+	docekrClient.SetEnvResolver(service.resolveEnv)
+
 	// 初始化端口管理器
 	service.PortManager = NewPortManager(service)
 
 	// 恢复已存在的代理服务
 	service.recoverPortProxies()
+	service.syncPortRegistry()
+	service.recoverJobs()
+	service.recoverDeployments()
+
+	// 启动调和循环，持续让实际容器向期望状态收敛
+	service.startReconciler()
+	// 启动定时扩缩容与自动扩缩容
+	service.startScaleScheduler()
+	service.startAutoscaler()
+	// 启动镜像更新检查
+	service.startImageWatcher()
+	// 启动 GitOps 同步
+	service.startGitOps()
+	// 启动定时任务调度
+	service.startCronJobScheduler()
+	// 启动回收站清理
+	service.startTrashPurger()
 
 	return service
 }
 
 // recoverPortProxies 恢复所有已存在的端口代理服务
 func (s *Service) recoverPortProxies() {
+	defer s.recovered.Store(true)
 	ctx := context.Background()
 
 	log.Info("PortProxy", log.Any("Message", "开始恢复端口代理服务..."))
@@ -60,8 +117,8 @@ func (s *Service) recoverPortProxies() {
 			continue
 		}
 
-		// 检查服务是否有运行的副本
-		if service.Replicas <= 0 {
+		// 检查服务是否有运行的副本（已停止的服务不恢复代理）
+		if service.Replicas <= 0 || service.Status != models.StatusRunning {
 			continue
 		}
 
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aichy126/igo/log"
+)
+
+// CloseStreams 结束实时日志等长连接，API 服务开始优雅退出时调用，避免等待这些请求直到超时
+func (s *Service) CloseStreams() {
+	s.streamsOnce.Do(func() { close(s.streams) })
+}
+
+// Shutdown 优雅退出：停止后台循环，等待进行中的调和结束，排空全部端口代理，关闭缓存和状态存储
+// 容器保持运行，重启后由 recoverPortProxies 恢复端口代理
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stopping) })
+
+	// 等待进行中的一轮调和结束，避免副本创建到一半时关闭存储
+	done := make(chan struct{})
+	go func() {
+		s.reconciler.mutex.Lock()
+		s.reconciler.mutex.Unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warn("Shutdown", log.Any("Message", "等待调和结束超时"))
+	}
+
+	var errs []error
+	if err := s.PortManager.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.Cache.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close cache: %w", err))
+	}
+	if err := s.store.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close state store: %w", err))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("shutdown errors: %v", errs)
+	}
+	log.Info("Shutdown", log.Any("Message", "端口代理已排空，状态存储已关闭"))
+	return nil
+}
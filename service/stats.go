@@ -0,0 +1,85 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/models"
+)
+
+// serviceStatsTimeout 采样服务资源使用情况的超时时间
+const serviceStatsTimeout = 10 * time.Second
+
+// sumReplicaStats 汇总成功采样的副本
+func sumReplicaStats(stats *models.ServiceStats) {
+	for _, replica := range stats.Replicas {
+		if replica.State != "running" || replica.Error != "" {
+			continue
+		}
+		stats.CPUPercent += replica.CPUPercent
+		stats.MemoryUsage += replica.MemoryUsage
+		stats.NetworkRx += replica.NetworkRx
+		stats.NetworkTx += replica.NetworkTx
+		stats.BlockRead += replica.BlockRead
+		stats.BlockWrite += replica.BlockWrite
+	}
+}
+
+// GetServiceStats 并发采样服务每个运行中副本的 CPU、内存、网络和块设备 I/O
+func (s *Service) GetServiceStats(ctx context.IContext, name string) (*models.ServiceStats, error) {
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, errServiceNotFound(name)
+	}
+
+	result := &models.ServiceStats{
+		Service:   name,
+		SampledAt: time.Now(),
+		Replicas:  make([]models.ReplicaStats, len(containers)),
+	}
+	statsCtx, cancel := ctx.WithTimeout(serviceStatsTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i, container := range containers {
+		replica := &result.Replicas[i]
+		replica.ContainerID = container.ID[:12]
+		replica.State = container.State
+		if nameInfo, err := s.dockerClient.ParseContainerName(container.Name); err == nil {
+			replica.Index = nameInfo.ReplicaIndex
+		}
+		if container.State != "running" {
+			continue
+		}
+		wg.Add(1)
+		go func(id, containerName string) {
+			defer wg.Done()
+			stats, err := s.dockerClient.ContainerStats(statsCtx, id)
+			if err != nil {
+				log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", containerName), log.Any("Message", "采集容器资源使用情况失败"))
+				replica.Error = err.Error()
+				return
+			}
+			replica.CPUPercent = stats.CPUPercent
+			replica.MemoryUsage = stats.MemoryUsage
+			replica.MemoryLimit = stats.MemoryLimit
+			replica.MemoryPercent = stats.MemoryPercent
+			replica.NetworkRx = stats.NetworkRx
+			replica.NetworkTx = stats.NetworkTx
+			replica.BlockRead = stats.BlockRead
+			replica.BlockWrite = stats.BlockWrite
+			replica.PIDs = stats.PIDs
+		}(container.ID, container.Name)
+	}
+	wg.Wait()
+
+	sort.Slice(result.Replicas, func(i, j int) bool { return result.Replicas[i].Index < result.Replicas[j].Index })
+	sumReplicaStats(result)
+	return result, nil
+}
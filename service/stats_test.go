@@ -0,0 +1,24 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestSumReplicaStats 测试只汇总成功采样的副本
+func TestSumReplicaStats(t *testing.T) {
+	stats := &models.ServiceStats{Replicas: []models.ReplicaStats{
+		{Index: 0, State: "running", CPUPercent: 10, MemoryUsage: 100, NetworkRx: 1, NetworkTx: 2, BlockRead: 3, BlockWrite: 4},
+		{Index: 1, State: "running", CPUPercent: 5, MemoryUsage: 50, NetworkRx: 1, NetworkTx: 2, BlockRead: 3, BlockWrite: 4},
+		{Index: 2, State: "running", CPUPercent: 99, MemoryUsage: 999, Error: "timeout"},
+		{Index: 3, State: "exited", CPUPercent: 99, MemoryUsage: 999},
+	}}
+	sumReplicaStats(stats)
+	if stats.CPUPercent != 15 || stats.MemoryUsage != 150 {
+		t.Errorf("CPUPercent = %v, MemoryUsage = %d, 期望 15, 150", stats.CPUPercent, stats.MemoryUsage)
+	}
+	if stats.NetworkRx != 2 || stats.NetworkTx != 4 || stats.BlockRead != 6 || stats.BlockWrite != 8 {
+		t.Errorf("网络 %d/%d, 块设备 %d/%d, 期望 2/4, 6/8", stats.NetworkRx, stats.NetworkTx, stats.BlockRead, stats.BlockWrite)
+	}
+}
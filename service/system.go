@@ -0,0 +1,120 @@
+package service
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// processStartedAt OneDock 进程启动时间
+var processStartedAt = time.Now()
+
+// GetSystemStatus 汇总 Docker 守护进程、磁盘占用、受管端口和进程运行时长
+// Docker 不可达时仍返回其余信息，不可达的原因记录在对应字段中
+func (s *Service) GetSystemStatus(ctx context.IContext) (*models.SystemStatus, error) {
+	uptime := time.Since(processStartedAt).Truncate(time.Second)
+	status := &models.SystemStatus{
+		StartedAt:     processStartedAt,
+		UptimeSeconds: int64(uptime.Seconds()),
+		Uptime:        uptime.String(),
+		Persistent:    s.store.Persistent(),
+	}
+
+	daemon, err := s.dockerClient.DaemonInfo(ctx)
+	if err != nil {
+		status.Docker.Error = err.Error()
+		status.Disk.Error = "docker daemon is unreachable"
+	} else {
+		status.Docker = models.DockerStatus{
+			Reachable:     true,
+			Version:       daemon.Version,
+			APIVersion:    daemon.APIVersion,
+			ClientVersion: daemon.ClientVersion,
+			OS:            daemon.OS,
+			Arch:          daemon.Arch,
+			KernelVersion: daemon.KernelVersion,
+			RootDir:       daemon.RootDir,
+		}
+		status.Disk = s.diskStatus(ctx, daemon.RootDir)
+	}
+
+	ports, err := s.portUsage()
+	if err != nil {
+		return nil, err
+	}
+	status.Ports = *ports
+	return status, nil
+}
+
+// diskStatus 统计 Docker 磁盘占用，以及 Docker 数据目录所在文件系统的容量（OneDock 能访问该目录时）
+func (s *Service) diskStatus(ctx context.IContext, rootDir string) models.DiskStatus {
+	var disk models.DiskStatus
+	usage, err := s.dockerClient.DiskUsage(ctx)
+	if err != nil {
+		disk.Error = err.Error()
+	} else {
+		disk.Images = usage.Images
+		disk.ImagesBytes = usage.ImagesSize
+		disk.Containers = usage.Containers
+		disk.ContainersBytes = usage.ContainersSize
+		disk.Volumes = usage.Volumes
+		disk.VolumesBytes = usage.VolumesSize
+		disk.BuildCacheBytes = usage.BuildCacheSize
+	}
+	if rootDir != "" {
+		if total, free, ok := filesystemUsage(rootDir); ok {
+			disk.FilesystemTotal = total
+			disk.FilesystemFree = free
+		}
+	}
+	return disk
+}
+
+// portUsage 统计端口登记、运行中的端口代理以及自动分配范围的占用
+func (s *Service) portUsage() (*models.PortUsage, error) {
+	records, err := s.store.ListPorts(false)
+	if err != nil {
+		return nil, err
+	}
+	usage := &models.PortUsage{ProxiesRunning: len(s.PortManager.ProxyPorts())}
+
+	autoRange, low, high, rangeErr := autoPortRange()
+	if rangeErr == nil {
+		usage.AutoRange = autoRange
+		usage.AutoRangeSize = high - low + 1
+	}
+
+	for _, record := range records {
+		if record.Reserved {
+			usage.Reserved++
+		} else {
+			usage.Allocated++
+		}
+		if rangeErr == nil && record.Port >= low && record.Port <= high {
+			usage.AutoRangeUsed++
+		}
+	}
+	return usage, nil
+}
+
+// GetVersion 返回 OneDock 版本与构建信息，以及协商后的 Docker API 版本；Docker 不可达时仍返回构建信息
+func (s *Service) GetVersion(ctx context.IContext) *models.VersionInfo {
+	version := &models.VersionInfo{
+		Version:   utils.Version,
+		GitCommit: utils.BuildCommit(),
+		BuildDate: utils.BuildTime(),
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	daemon, err := s.dockerClient.DaemonInfo(ctx)
+	if err != nil {
+		version.DockerError = err.Error()
+		return version
+	}
+	version.DockerAPIVersion = daemon.ClientVersion
+	version.DockerVersion = daemon.Version
+	return version
+}
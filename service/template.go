@@ -0,0 +1,235 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+	"github.com/mitchellh/mapstructure"
+)
+
+// templateVarPattern 模板占位符：${VAR} 或 ${VAR:-默认值}
+var templateVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// templateVariables 收集模板中使用的变量名（去重、排序）
+func templateVariables(value interface{}) []string {
+	seen := make(map[string]bool)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case string:
+			for _, match := range templateVarPattern.FindAllStringSubmatch(v, -1) {
+				seen[match[1]] = true
+			}
+		case map[string]interface{}:
+			for key, item := range v {
+				walk(key)
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// renderTemplate 替换模板中的占位符，缺少没有默认值的变量时返回错误
+func renderTemplate(value interface{}, variables map[string]string) (interface{}, error) {
+	var missing []string
+	substitute := func(s string) string {
+		return templateVarPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+			match := templateVarPattern.FindStringSubmatch(placeholder)
+			if v, ok := variables[match[1]]; ok {
+				return v
+			}
+			if strings.Contains(placeholder, ":-") {
+				return match[2]
+			}
+			missing = append(missing, match[1])
+			return placeholder
+		})
+	}
+
+	var walk func(v interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case string:
+			return substitute(v)
+		case map[string]interface{}:
+			result := make(map[string]interface{}, len(v))
+			for key, item := range v {
+				result[substitute(key)] = walk(item)
+			}
+			return result
+		case []interface{}:
+			result := make([]interface{}, len(v))
+			for i, item := range v {
+				result[i] = walk(item)
+			}
+			return result
+		default:
+			return v
+		}
+	}
+
+	rendered := walk(value)
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing template variables: %s", strings.Join(uniqueStrings(missing), ", "))
+	}
+	return rendered, nil
+}
+
+// uniqueStrings 去除已排序切片中的重复项
+func uniqueStrings(values []string) []string {
+	result := values[:0]
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// toServiceTemplate 将存储中的模板转换为 API 模型
+func toServiceTemplate(template *store.Template) (*models.ServiceTemplate, error) {
+	result := &models.ServiceTemplate{
+		Name:        template.Name,
+		Description: template.Description,
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+	if err := utils.DeJson(template.Spec, &result.Spec); err != nil {
+		return nil, fmt.Errorf("failed to decode template %s: %w", template.Name, err)
+	}
+	result.Variables = templateVariables(result.Spec)
+	return result, nil
+}
+
+// SaveTemplate 创建或覆盖服务模板
+func (s *Service) SaveTemplate(ctx context.IContext, template *models.ServiceTemplate) (*models.ServiceTemplate, error) {
+	if template.Name == "" {
+		return nil, fmt.Errorf("template name is required")
+	}
+	if len(template.Spec) == 0 {
+		return nil, fmt.Errorf("template spec is required")
+	}
+
+	spec, err := utils.EnJson(template.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template spec: %w", err)
+	}
+
+	record := &store.Template{
+		Name:        template.Name,
+		Description: template.Description,
+		Spec:        spec,
+	}
+	if err := s.store.SaveTemplate(record); err != nil {
+		return nil, err
+	}
+
+	log.Info("Template", log.Any("Template", template.Name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "保存服务模板"))
+	return toServiceTemplate(record)
+}
+
+// ListTemplates 列出全部服务模板
+func (s *Service) ListTemplates(ctx context.IContext) ([]*models.ServiceTemplate, error) {
+	templates, err := s.store.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.ServiceTemplate, 0, len(templates))
+	for _, template := range templates {
+		item, err := toServiceTemplate(template)
+		if err != nil {
+			log.Error("Template", log.Any("Error", err), log.Any("Template", template.Name), log.Any("Message", "解析服务模板失败"))
+			continue
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// GetTemplate 获取服务模板
+func (s *Service) GetTemplate(ctx context.IContext, name string) (*models.ServiceTemplate, error) {
+	template, err := s.store.GetTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, utils.NewError(utils.CodeNotFound, "template %s not found", name)
+	}
+	return toServiceTemplate(template)
+}
+
+// DeleteTemplate 删除服务模板
+func (s *Service) DeleteTemplate(ctx context.IContext, name string) error {
+	exists, err := s.store.DeleteTemplate(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return utils.NewError(utils.CodeNotFound, "template %s not found", name)
+	}
+	log.Info("Template", log.Any("Template", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "删除服务模板"))
+	return nil
+}
+
+// RenderTemplate 用变量值渲染模板，生成服务配置
+func (s *Service) RenderTemplate(ctx context.IContext, name string, variables map[string]string) (*models.ServiceRequest, error) {
+	template, err := s.GetTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := renderTemplate(template.Spec, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	// 弱类型解码：端口、副本数等字段可以写成 "${PORT}" 这样的字符串
+	req := &models.ServiceRequest{}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:          "json",
+		WeaklyTypedInput: true,
+		Result:           req,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(rendered); err != nil {
+		return nil, fmt.Errorf("rendered template is not a valid service spec: %w", err)
+	}
+	if req.Name == "" || req.Image == "" || req.Tag == "" || req.InternalPort == 0 {
+		return nil, fmt.Errorf("rendered template must set name, image, tag and internal_port")
+	}
+	return req, nil
+}
+
+// DeployFromTemplate 按模板渲染服务配置并部署，服务已存在时执行滚动更新
+func (s *Service) DeployFromTemplate(ctx context.IContext, name string, variables map[string]string) (*models.Service, error) {
+	req, err := s.RenderTemplate(ctx, name, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Template", log.Any("Template", name), log.Any("ServiceName", req.Name), log.Any("Message", "从模板部署服务"))
+	return s.DeployOrUpdateService(ctx, req)
+}
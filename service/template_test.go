@@ -0,0 +1,38 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestRenderTemplate 测试模板变量替换
+func TestRenderTemplate(t *testing.T) {
+	spec := map[string]interface{}{
+		"name":        "${NAME}-web",
+		"public_port": "${PORT}",
+		"environment": map[string]interface{}{"ENV": "${ENV:-production}", "EMPTY": "${EMPTY:-}"},
+		"command":     []interface{}{"serve", "--port=${PORT}"},
+	}
+
+	if vars := templateVariables(spec); !reflect.DeepEqual(vars, []string{"EMPTY", "ENV", "NAME", "PORT"}) {
+		t.Errorf("变量列表错误: %v", vars)
+	}
+
+	rendered, err := renderTemplate(spec, map[string]string{"NAME": "shop", "PORT": "9200"})
+	if err != nil {
+		t.Fatalf("渲染模板失败: %v", err)
+	}
+	want := map[string]interface{}{
+		"name":        "shop-web",
+		"public_port": "9200",
+		"environment": map[string]interface{}{"ENV": "production", "EMPTY": ""},
+		"command":     []interface{}{"serve", "--port=9200"},
+	}
+	if !reflect.DeepEqual(rendered, want) {
+		t.Errorf("渲染结果错误: %v", rendered)
+	}
+
+	if _, err := renderTemplate(spec, map[string]string{"NAME": "shop"}); err == nil || err.Error() != "missing template variables: PORT" {
+		t.Errorf("缺少变量时应返回错误，实际: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/utils"
+)
+
+// defaultTerminalCommand 未指定命令时在副本中启动的 shell
+const defaultTerminalCommand = "/bin/sh"
+
+// OpenTerminal 在副本中启动交互式终端，terminal.enabled 为 false 时拒绝
+func (s *Service) OpenTerminal(ctx context.IContext, name string, index int, cmd []string, rows, cols uint) (*dockerclient.ExecSession, error) {
+	if !utils.ConfGetboolDefault("terminal.enabled", true) {
+		return nil, fmt.Errorf("web terminal is disabled, set terminal.enabled to enable it")
+	}
+	container, err := s.replicaContainer(ctx, name, index)
+	if err != nil {
+		return nil, err
+	}
+	if container.State != "running" {
+		return nil, fmt.Errorf("replica %s is %s, not running", container.Name, container.State)
+	}
+	if len(cmd) == 0 {
+		cmd = []string{utils.ConfGetString("terminal.command")}
+		if cmd[0] == "" {
+			cmd[0] = defaultTerminalCommand
+		}
+	}
+
+	session, err := s.dockerClient.StartExec(ctx, container.ID, cmd, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Terminal", log.Any("ServiceName", name), log.Any("ContainerName", container.Name), log.Any("Command", cmd),
+		log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "打开副本终端"))
+	return session, nil
+}
+
+// ResizeTerminal 调整终端大小
+func (s *Service) ResizeTerminal(ctx context.IContext, session *dockerclient.ExecSession, rows, cols uint) error {
+	return s.dockerClient.ResizeExec(ctx, session.ID, rows, cols)
+}
+
+// TerminalExitCode 终端命令结束后的退出码
+func (s *Service) TerminalExitCode(ctx context.IContext, session *dockerclient.ExecSession) (int, error) {
+	return s.dockerClient.ExecExitCode(ctx, session.ID)
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	igoContext "github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/igo/util"
+	"github.com/aichy126/onedock/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	mirrorHeader          = "X-Onedock-Mirror" // 镜像请求标记头
+	mirrorDefaultMaxBody  = 1 << 20            // 默认最大镜像请求体 1MB
+	mirrorDefaultTimeout  = 10 * time.Second   // 默认镜像请求超时
+	mirrorResolveInterval = 30 * time.Second   // 目标服务地址的重新解析间隔，目标服务端口变更或删除后最多延迟这么久生效
+	mirrorMaxInflight     = 64                 // 同时进行中的镜像请求上限
+)
+
+// trafficMirror 流量镜像，按比例将请求异步复制到另一个服务，响应直接丢弃
+type trafficMirror struct {
+	ppm        *PortProxyManager
+	service    string // 目标服务名称
+	percent    int    // 镜像比例 1-100
+	maxBody    int64  // 可镜像的最大请求体
+	client     *http.Client
+	inflight   chan struct{} // 限制并发，避免镜像拖垮代理
+	resolve    func() string // 解析目标服务代理地址
+	mutex      sync.Mutex
+	targetURL  string    // 目标服务代理地址，每隔 mirrorResolveInterval 在后台重新解析
+	resolvedAt time.Time // 上次解析完成的时间
+	resolving  bool      // 后台解析进行中
+
+	mirrored int64 // 已发送
+	failed   int64 // 发送失败
+	skipped  int64 // 因请求体过大或并发已满跳过
+}
+
+// validateMirrorConfig 校验流量镜像配置
+func validateMirrorConfig(serviceName string, mirror *models.MirrorConfig) error {
+	if mirror == nil {
+		return nil
+	}
+	if mirror.Service == "" {
+		return fmt.Errorf("mirror service is required")
+	}
+	if mirror.Service == serviceName {
+		return fmt.Errorf("mirror service cannot be the service itself")
+	}
+	if mirror.Percent < 1 || mirror.Percent > 100 {
+		return fmt.Errorf("mirror percent must be between 1 and 100")
+	}
+	return nil
+}
+
+// newTrafficMirror 创建流量镜像，目标服务的地址在首次使用时开始在后台解析
+func (ppm *PortProxyManager) newTrafficMirror(mirror *models.MirrorConfig) *trafficMirror {
+	maxBody := int64(util.ConfGetInt("proxy.mirror_max_body"))
+	if maxBody <= 0 {
+		maxBody = mirrorDefaultMaxBody
+	}
+	timeout := time.Duration(util.ConfGetInt("proxy.mirror_timeout")) * time.Second
+	if timeout <= 0 {
+		timeout = mirrorDefaultTimeout
+	}
+	m := &trafficMirror{
+		ppm:      ppm,
+		service:  mirror.Service,
+		percent:  mirror.Percent,
+		maxBody:  maxBody,
+		client:   &http.Client{Timeout: timeout},
+		inflight: make(chan struct{}, mirrorMaxInflight),
+	}
+	m.resolve = m.resolveTarget
+	return m
+}
+
+// target 返回目标服务的代理地址，目标服务不存在或首次解析尚未完成时返回空字符串，此时不镜像
+// 解析需要查询 Docker，不在请求中同步进行：地址过期时启动后台解析并立即返回上次的结果，
+// 目标服务重新部署到其他端口或被删除后最多延迟 mirrorResolveInterval 生效
+func (m *trafficMirror) target() string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.resolving && time.Since(m.resolvedAt) >= mirrorResolveInterval {
+		m.resolving = true
+		go m.refresh()
+	}
+	return m.targetURL
+}
+
+// refresh 在后台解析目标服务地址并更新缓存
+func (m *trafficMirror) refresh() {
+	target := m.resolve()
+	m.mutex.Lock()
+	m.targetURL = target
+	m.resolvedAt = time.Now()
+	m.resolving = false
+	m.mutex.Unlock()
+}
+
+// resolveTarget 查询目标服务的公共端口，目标服务不存在时返回空字符串
+func (m *trafficMirror) resolveTarget() string {
+	svc := m.ppm.service.GetService(igoContext.Background(), m.service)
+	if svc == nil || svc.PublicPort <= 0 {
+		log.Warn("PortProxy", log.Any("MirrorService", m.service), log.Any("Message", "流量镜像目标服务不存在，稍后重试"))
+		return ""
+	}
+
+	host := svc.BindAddress
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+		if listenNetwork() == "tcp6" {
+			host = "::1"
+		}
+	}
+	return fmt.Sprintf("http://%s", net.JoinHostPort(host, strconv.Itoa(svc.PublicPort)))
+}
+
+// middleware 返回流量镜像中间件，镜像请求在后台发送，不影响原请求
+func (m *trafficMirror) middleware(publicPort int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 已经是镜像请求时不再继续镜像，避免服务间循环
+		if c.Request.Header.Get(mirrorHeader) != "" || rand.Intn(100) >= m.percent {
+			c.Next()
+			return
+		}
+
+		target := m.target()
+		if target == "" {
+			c.Next()
+			return
+		}
+
+		// 读取请求体用于复制，超出上限时放弃镜像并还原请求体
+		var body []byte
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			buf, err := io.ReadAll(io.LimitReader(c.Request.Body, m.maxBody+1))
+			if err != nil || int64(len(buf)) > m.maxBody {
+				c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(buf), c.Request.Body), c.Request.Body}
+				atomic.AddInt64(&m.skipped, 1)
+				c.Next()
+				return
+			}
+			body = buf
+			c.Request.Body = readCloser{bytes.NewReader(buf), c.Request.Body}
+		}
+
+		select {
+		case m.inflight <- struct{}{}:
+		default:
+			atomic.AddInt64(&m.skipped, 1)
+			c.Next()
+			return
+		}
+
+		mirrorReq, err := http.NewRequestWithContext(context.Background(), c.Request.Method, target+c.Request.URL.RequestURI(), bytes.NewReader(body))
+		if err != nil {
+			<-m.inflight
+			atomic.AddInt64(&m.failed, 1)
+			c.Next()
+			return
+		}
+		mirrorReq.Header = c.Request.Header.Clone()
+		mirrorReq.Header.Set(mirrorHeader, strconv.Itoa(publicPort))
+		mirrorReq.Host = c.Request.Host
+
+		go m.send(mirrorReq, publicPort)
+		c.Next()
+	}
+}
+
+// send 发送镜像请求并丢弃响应
+func (m *trafficMirror) send(req *http.Request, publicPort int) {
+	defer func() { <-m.inflight }()
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		atomic.AddInt64(&m.failed, 1)
+		log.Debug("PortProxy", log.Any("PublicPort", publicPort), log.Any("MirrorService", m.service),
+			log.Any("Error", err), log.Any("Message", "镜像请求发送失败"))
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	atomic.AddInt64(&m.mirrored, 1)
+}
+
+// stats 返回流量镜像统计
+func (m *trafficMirror) stats() *models.MirrorStats {
+	m.mutex.Lock()
+	target := m.targetURL
+	m.mutex.Unlock()
+	return &models.MirrorStats{
+		Service:  m.service,
+		Percent:  m.percent,
+		Target:   target,
+		Mirrored: atomic.LoadInt64(&m.mirrored),
+		Failed:   atomic.LoadInt64(&m.failed),
+		Skipped:  atomic.LoadInt64(&m.skipped),
+	}
+}
+
+// readCloser 组合新的 Reader 与原请求体的 Closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
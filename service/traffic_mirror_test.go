@@ -0,0 +1,68 @@
+package service
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitMirrorTarget 等待后台解析完成，target() 返回期望的地址
+func waitMirrorTarget(t *testing.T, m *trafficMirror, want string) {
+	deadline := time.Now().Add(time.Second)
+	for m.target() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("target() = %q, 期望后台解析后为 %q", m.target(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// expireMirrorTarget 让缓存的地址超过解析间隔
+func expireMirrorTarget(m *trafficMirror) {
+	m.mutex.Lock()
+	m.resolvedAt = time.Now().Add(-mirrorResolveInterval)
+	m.mutex.Unlock()
+}
+
+// TestTrafficMirrorTarget 测试目标地址在后台解析：首次解析完成前不镜像，地址过期后继续使用旧地址直到重新解析完成
+func TestTrafficMirrorTarget(t *testing.T) {
+	answers := make(chan string)
+	var resolved int32
+	m := &trafficMirror{service: "nginx-api", resolve: func() string {
+		atomic.AddInt32(&resolved, 1)
+		return <-answers
+	}}
+
+	// 首次解析完成前返回空字符串，不阻塞请求，也不重复启动解析
+	if target := m.target(); target != "" {
+		t.Fatalf("首次解析完成前 target() = %q, 期望为空", target)
+	}
+	if target := m.target(); target != "" {
+		t.Fatalf("首次解析完成前 target() = %q, 期望为空", target)
+	}
+	answers <- "http://127.0.0.1:9203"
+	waitMirrorTarget(t, m, "http://127.0.0.1:9203")
+	if n := atomic.LoadInt32(&resolved); n != 1 {
+		t.Errorf("解析 %d 次, 期望解析中的请求不重复解析", n)
+	}
+
+	// 超过解析间隔后立即返回旧地址，后台解析完成后使用目标服务的新端口
+	expireMirrorTarget(m)
+	if target := m.target(); target != "http://127.0.0.1:9203" {
+		t.Errorf("重新解析期间 target() = %q, 期望继续使用旧地址", target)
+	}
+	answers <- "http://127.0.0.1:9300"
+	waitMirrorTarget(t, m, "http://127.0.0.1:9300")
+	if stats := m.stats(); stats.Target != "http://127.0.0.1:9300" {
+		t.Errorf("stats().Target = %q, 期望新的地址", stats.Target)
+	}
+
+	// 目标服务被删除后停止镜像
+	expireMirrorTarget(m)
+	m.target()
+	answers <- ""
+	waitMirrorTarget(t, m, "")
+	if n := atomic.LoadInt32(&resolved); n != 3 {
+		t.Errorf("解析 %d 次, 期望 3 次", n)
+	}
+}
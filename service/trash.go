@@ -0,0 +1,357 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+const (
+	defaultTrashRetentionDays = 7         // 回收站默认保留天数
+	trashPurgeInterval        = time.Hour // 清理过期回收站记录的间隔
+	trashPortNote             = "trash"   // 为回收站中的服务保留公共端口时的备注
+)
+
+// trashRetention 回收站保留时长
+func trashRetention() time.Duration {
+	days := utils.ConfGetIntDefault("trash.retention_days", defaultTrashRetentionDays)
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// TrashByDefault 删除服务时未指定 trash 参数是否放入回收站
+func TrashByDefault() bool {
+	return utils.ConfGetbool("trash.default")
+}
+
+// TrashService 删除服务并放入回收站：保存删除时的期望状态，并为服务保留公共端口，保留期内可以恢复
+// keepContainers 为 true 时停止副本并改名保留容器，恢复时直接启动；否则删除容器，恢复时按保存的配置重新部署
+func (s *Service) TrashService(ctx context.IContext, name string, keepContainers bool) (*models.TrashedService, error) {
+	spec, err := s.store.GetServiceSpec(name)
+	if err != nil {
+		return nil, err
+	}
+	if spec == nil {
+		if s.GetService(ctx, name) == nil {
+			return nil, errServiceNotFound(name)
+		}
+		return nil, utils.NewError(utils.CodeConflict, "service %s has no desired state and cannot be moved to trash, delete it with trash=false", name)
+	}
+
+	// 同名服务已在回收站中时先清除旧记录
+	if previous, err := s.store.GetTrashedService(name); err != nil {
+		return nil, err
+	} else if previous != nil {
+		if err := s.purgeTrashed(ctx, previous); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	trashed := &store.TrashedService{
+		Name:       name,
+		Image:      spec.Image,
+		Tag:        spec.Tag,
+		PublicPort: spec.PublicPort,
+		Replicas:   spec.Replicas,
+		Memory:     spec.Memory,
+		Owner:      spec.Owner,
+		Spec:       spec.Spec,
+		DeletedBy:  actorFromContext(ctx),
+		DeletedAt:  now,
+		ExpiresAt:  now.Add(trashRetention()),
+	}
+	if keepContainers {
+		trashed.Containers, err = s.trashContainers(ctx, name, spec.PublicPort)
+	} else {
+		err = s.DeleteService(ctx, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.SaveTrashedService(trashed); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "保存回收站记录失败"))
+		return nil, err
+	}
+	// 为服务保留公共端口，恢复前不会分配给其他服务；端口已被预留时保持原样
+	err = s.store.ReservePort(&store.PortRecord{Port: spec.PublicPort, Service: name, Note: trashPortNote, Actor: trashed.DeletedBy})
+	if err != nil {
+		log.Warn("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("PublicPort", spec.PublicPort), log.Any("Message", "为回收站中的服务保留端口失败"))
+	}
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("KeptContainers", len(trashed.Containers)), log.Any("ExpiresAt", trashed.ExpiresAt), log.Any("Message", "服务已放入回收站"))
+	return toTrashedService(trashed), nil
+}
+
+// trashContainers 停止服务的全部副本并改名保留，然后像删除服务一样清理端口代理和状态，返回保留的容器ID
+func (s *Service) trashContainers(ctx context.IContext, name string, publicPort int) ([]string, error) {
+	leave, err := s.serialize(ctx, name, models.OperationScale)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	if err := s.checkServiceLock(ctx, name); err != nil {
+		return nil, err
+	}
+	containers, err := s.serviceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, errServiceNotFound(name)
+	}
+
+	// 先停止端口代理，不再接收新请求
+	s.removePortProxy(name, publicPort)
+	ids := make([]string, 0, len(containers))
+	for _, container := range containers {
+		if err := s.dockerClient.TrashReplica(ctx, container.ID); err != nil {
+			// 无法保留的容器直接删除，避免留下不受管理的容器
+			log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", container.Name), log.Any("Message", "保留副本失败，删除容器"))
+			if err := s.dockerClient.RemoveReplica(ctx, container); err != nil {
+				log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", container.Name), log.Any("Message", "删除副本失败"))
+			}
+			continue
+		}
+		ids = append(ids, container.ID)
+	}
+
+	if err := s.deleteServiceState(name); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "更新服务期望状态失败"))
+	}
+	s.DelContainerMapping(ctx, publicPort)
+	s.recordEvent(ctx, name, models.EventDeleted, fmt.Sprintf("moved to trash, kept %d stopped replicas", len(ids)))
+	return ids, nil
+}
+
+// ListTrash 列出回收站中令牌可以访问的服务
+func (s *Service) ListTrash(ctx context.IContext) ([]*models.TrashedService, error) {
+	records, err := s.store.ListTrashedServices()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.TrashedService, 0, len(records))
+	for _, record := range records {
+		if checkServiceScope(ctx, record.Name) == nil {
+			result = append(result, toTrashedService(record))
+		}
+	}
+	return result, nil
+}
+
+// RestoreService 从回收站恢复服务，使用删除时的配置、副本数和公共端口
+// 保留了容器时改回原名称并启动，否则按保存的配置重新部署
+func (s *Service) RestoreService(ctx context.IContext, name string) (*models.Service, error) {
+	trashed, err := s.store.GetTrashedService(name)
+	if err != nil {
+		return nil, err
+	}
+	if trashed == nil {
+		return nil, utils.NewError(utils.CodeNotFound, "service %s is not in trash", name)
+	}
+	if s.GetService(ctx, name) != nil {
+		return nil, utils.NewError(utils.CodeConflict, "service %s already exists, delete it before restoring", name)
+	}
+
+	var req models.ServiceRequest
+	if err := utils.DeJson(trashed.Spec, &req); err != nil {
+		return nil, fmt.Errorf("failed to decode spec of trashed service %s: %w", name, err)
+	}
+	req.PublicPort = trashed.PublicPort
+	req.Replicas = trashed.Replicas
+
+	var service *models.Service
+	if len(trashed.Containers) > 0 {
+		service, err = s.restoreContainers(ctx, trashed, &req)
+	} else {
+		service, err = s.DeployOrUpdateService(ctx, &req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.store.DeleteTrashedService(name); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", name), log.Any("Message", "删除回收站记录失败"))
+	}
+	s.recordEvent(ctx, name, models.EventRestored, fmt.Sprintf("restored from trash with %d replicas", trashed.Replicas))
+	log.Info("Docker", log.Any("ServiceName", name), log.Any("PublicPort", trashed.PublicPort), log.Any("Message", "服务已从回收站恢复"))
+	return service, nil
+}
+
+// restoreContainers 将保留的容器改回原名称并启动，缺少的副本由调和循环补齐
+// 保留的容器都已不存在时按保存的配置重新部署；恢复前检查保留的副本，名称或主机端口已被占用时不做任何修改
+// 改名或启动失败时撤销已做的修改，回收站记录保持不变，可以重试
+func (s *Service) restoreContainers(ctx context.IContext, trashed *store.TrashedService, req *models.ServiceRequest) (*models.Service, error) {
+	leave, err := s.serialize(ctx, trashed.Name, models.OperationStart)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	err = s.checkQuota(ctx, quotaRequest{Name: trashed.Name, Owner: trashed.Owner, PublicPort: trashed.PublicPort, Replicas: trashed.Replicas, Memory: trashed.Memory})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPortConflict(ctx, trashed.Name, trashed.PublicPort); err != nil {
+		return nil, err
+	}
+
+	kept := make([]string, 0, len(trashed.Containers))
+	for _, id := range trashed.Containers {
+		err := s.dockerClient.CheckTrashedReplica(ctx, id)
+		if utils.ErrorCode(err) == utils.CodeNotFound {
+			log.Warn("Docker", log.Any("Error", err), log.Any("ID", id), log.Any("ServiceName", trashed.Name), log.Any("Message", "保留的副本已不存在"))
+			continue
+		}
+		if err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ID", id), log.Any("ServiceName", trashed.Name), log.Any("Message", "保留的副本无法恢复"))
+			return nil, err
+		}
+		kept = append(kept, id)
+	}
+	if err := s.store.AllocatePort(trashed.PublicPort, trashed.Name, actorFromContext(ctx)); err != nil {
+		return nil, err
+	}
+	if len(kept) == 0 {
+		log.Info("Docker", log.Any("ServiceName", trashed.Name), log.Any("Message", "保留的副本均已不存在，重新部署服务"))
+		return s.DeployOrUpdateService(ctx, req)
+	}
+
+	restored := make([]string, 0, len(kept))
+	for _, id := range kept {
+		if err := s.dockerClient.RestoreReplica(ctx, id); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ID", id), log.Any("ServiceName", trashed.Name), log.Any("Message", "恢复保留的副本失败"))
+			s.undoRestore(ctx, trashed, restored)
+			return nil, err
+		}
+		restored = append(restored, id)
+	}
+
+	err = s.store.SaveServiceSpec(&store.ServiceSpec{
+		Name:       trashed.Name,
+		Image:      trashed.Image,
+		Tag:        trashed.Tag,
+		PublicPort: trashed.PublicPort,
+		Replicas:   trashed.Replicas,
+		Memory:     trashed.Memory,
+		Owner:      trashed.Owner,
+		Spec:       trashed.Spec,
+	})
+	if err != nil {
+		s.undoRestore(ctx, trashed, restored)
+		return nil, err
+	}
+	service, err := s.StartService(ctx, trashed.Name)
+	if err != nil {
+		s.undoRestore(ctx, trashed, restored)
+		return nil, err
+	}
+	return service, nil
+}
+
+// undoRestore 撤销恢复：重新停止并改名已恢复的副本，删除期望状态，重新为服务保留公共端口
+func (s *Service) undoRestore(ctx context.IContext, trashed *store.TrashedService, restored []string) {
+	for _, id := range restored {
+		if err := s.dockerClient.TrashReplica(ctx, id); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ID", id), log.Any("ServiceName", trashed.Name), log.Any("Message", "撤销恢复时保留副本失败"))
+		}
+	}
+	if err := s.store.DeleteServiceSpec(trashed.Name); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", trashed.Name), log.Any("Message", "撤销恢复时删除期望状态失败"))
+	}
+	if err := s.store.ReleasePorts(trashed.Name); err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("ServiceName", trashed.Name), log.Any("Message", "撤销恢复时释放端口失败"))
+	}
+	err := s.store.ReservePort(&store.PortRecord{Port: trashed.PublicPort, Service: trashed.Name, Note: trashPortNote, Actor: trashed.DeletedBy})
+	if err != nil {
+		log.Warn("Store", log.Any("Error", err), log.Any("ServiceName", trashed.Name), log.Any("PublicPort", trashed.PublicPort), log.Any("Message", "撤销恢复时为服务保留端口失败"))
+	}
+	s.DelContainerMapping(ctx, trashed.PublicPort)
+	log.Warn("Docker", log.Any("ServiceName", trashed.Name), log.Any("Containers", len(restored)), log.Any("Message", "恢复失败，已撤销，服务仍在回收站中"))
+}
+
+// PurgeTrashedService 从回收站中彻底删除服务：删除保留的容器，释放为它保留的公共端口
+func (s *Service) PurgeTrashedService(ctx context.IContext, name string) error {
+	trashed, err := s.store.GetTrashedService(name)
+	if err != nil {
+		return err
+	}
+	if trashed == nil {
+		return utils.NewError(utils.CodeNotFound, "service %s is not in trash", name)
+	}
+	return s.purgeTrashed(ctx, trashed)
+}
+
+// purgeTrashed 删除回收站记录及其保留的容器和端口
+func (s *Service) purgeTrashed(ctx context.IContext, trashed *store.TrashedService) error {
+	for _, id := range trashed.Containers {
+		if err := s.dockerClient.RemoveTrashedReplica(ctx, id); err != nil {
+			return err
+		}
+	}
+	// 只释放放入回收站时创建的预留，用户手动预留的端口保持不变
+	record, err := s.store.GetPort(trashed.PublicPort)
+	if err == nil && record != nil && record.Reserved && record.Service == trashed.Name && record.Note == trashPortNote {
+		_, err = s.store.DeletePortReservation(trashed.PublicPort)
+	}
+	if err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("PublicPort", trashed.PublicPort), log.Any("Message", "释放回收站保留的端口失败"))
+	}
+	if _, err := s.store.DeleteTrashedService(trashed.Name); err != nil {
+		return err
+	}
+	log.Info("Docker", log.Any("ServiceName", trashed.Name), log.Any("Containers", len(trashed.Containers)), log.Any("Message", "已从回收站清除服务"))
+	return nil
+}
+
+// startTrashPurger 启动回收站清理循环，定期清除保留期已过的服务
+func (s *Service) startTrashPurger() {
+	go func() {
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopping:
+				return
+			case <-ticker.C:
+				s.purgeExpiredTrash(context.Background())
+			}
+		}
+	}()
+}
+
+// purgeExpiredTrash 清除保留期已过的服务
+func (s *Service) purgeExpiredTrash(ctx context.IContext) {
+	expired, err := s.store.ListExpiredTrashedServices(time.Now())
+	if err != nil {
+		log.Error("Store", log.Any("Error", err), log.Any("Message", "读取过期的回收站记录失败"))
+		return
+	}
+	for _, trashed := range expired {
+		if err := s.purgeTrashed(ctx, trashed); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ServiceName", trashed.Name), log.Any("Message", "清除过期的回收站记录失败"))
+		}
+	}
+}
+
+// toTrashedService 转换为接口返回的回收站记录
+func toTrashedService(record *store.TrashedService) *models.TrashedService {
+	return &models.TrashedService{
+		Name:           record.Name,
+		Image:          fmt.Sprintf("%s:%s", record.Image, record.Tag),
+		PublicPort:     record.PublicPort,
+		Replicas:       record.Replicas,
+		KeptContainers: len(record.Containers),
+		DeletedBy:      record.DeletedBy,
+		DeletedAt:      record.DeletedAt,
+		ExpiresAt:      record.ExpiresAt,
+	}
+}
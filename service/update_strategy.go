@@ -0,0 +1,185 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// resolveUpdateStrategy 校验并补全滚动更新策略，未指定时逐个先建后删（max_surge=1, max_unavailable=0）
+func resolveUpdateStrategy(strategy *models.UpdateStrategy) (*models.UpdateStrategy, error) {
+	resolved := &models.UpdateStrategy{MaxSurge: 1}
+	if strategy != nil {
+		if strategy.MaxSurge < 0 || strategy.MaxUnavailable < 0 {
+			return nil, fmt.Errorf("max_surge and max_unavailable must be greater than or equal to 0")
+		}
+		if strategy.MaxSurge == 0 && strategy.MaxUnavailable == 0 {
+			return nil, fmt.Errorf("max_surge and max_unavailable cannot both be 0")
+		}
+		if strategy.PauseAfter < 0 {
+			return nil, fmt.Errorf("pause_after must be greater than or equal to 0")
+		}
+		*resolved = *strategy
+	}
+	if resolved.AutoRollback == nil {
+		autoRollback := utils.ConfGetboolDefault("container.auto_rollback", true)
+		resolved.AutoRollback = &autoRollback
+	}
+	return resolved, nil
+}
+
+// validateDeploymentMode 校验更新方式，金丝雀发布需通过金丝雀接口发起
+func validateDeploymentMode(mode string) error {
+	switch mode {
+	case "", models.DeploymentModeRolling, models.DeploymentModeBlueGreen:
+		return nil
+	}
+	return fmt.Errorf("invalid deployment_mode %q, must be rolling or blue_green", mode)
+}
+
+// rollingUpdate 按策略分批更新容器，返回成功更新的副本数以及是否被中止
+// 暂停期间刷新端口代理，使流量到达新旧混合的副本以便观察
+func (s *Service) rollingUpdate(ctx context.IContext, serviceName string, newService *dockerclient.Service,
+	containers []dockerclient.ContainerInfo, strategy *models.UpdateStrategy, r *rollout) (int, bool) {
+	dockerclient.ReportProgress(ctx, dockerclient.Progress{Step: models.DeployStepUpdating, Total: len(containers),
+		Message: fmt.Sprintf("updating %d replicas", len(containers))})
+
+	replicas := make([]int, 0, len(containers))
+	for _, container := range containers {
+		nameInfo, err := s.dockerClient.ParseContainerName(container.Name)
+		if err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", container.Name), log.Any("Message", "解析容器名称失败"))
+			continue
+		}
+		replicas = append(replicas, nameInfo.ReplicaIndex)
+	}
+
+	onPause := func() {
+		s.refreshPortProxy(ctx, newService.PublicPort)
+	}
+	update := func(replicaIndex int, removeFirst bool) bool {
+		update := s.dockerClient.UpdateContainer
+		if removeFirst {
+			update = s.dockerClient.ReplaceContainer
+		}
+		newContainerID, newPort, err := update(ctx, serviceName, newService, replicaIndex)
+		if err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ReplicaIndex", replicaIndex), log.Any("Message", "容器更新失败"))
+			return false
+		}
+		log.Info("Docker", log.Any("ServiceName", serviceName), log.Any("ReplicaIndex", replicaIndex),
+			log.Any("NewContainer", newContainerID[:12]), log.Any("NewPort", newPort), log.Any("Message", "容器更新成功"))
+		return true
+	}
+	return runBatches(ctx, serviceName, r, replicas, strategy, onPause, update)
+}
+
+// runBatches 按策略分批调用 update 更新副本，返回成功的副本数以及是否被中止（手动中止，或暂停期间请求超时、客户端断开）
+// 每批最多 max_surge 个副本先建后删、max_unavailable 个副本先删后建，批内并行，批间串行
+// 批次之间检查暂停/中止，暂停时先调用 onPause
+func runBatches(ctx context.IContext, serviceName string, r *rollout, replicas []int, strategy *models.UpdateStrategy,
+	onPause func(), update func(replicaIndex int, removeFirst bool) bool) (int, bool) {
+	batchSize := strategy.MaxSurge + strategy.MaxUnavailable
+	successCount := 0
+	autoPaused := false
+
+	for start := 0; start < len(replicas); start += batchSize {
+		if r.paused() {
+			onPause()
+			log.Info("Docker", log.Any("ServiceName", serviceName), log.Any("Updated", successCount), log.Any("Message", "滚动更新已暂停，等待恢复或中止"))
+		}
+		if !r.checkpoint(ctx) {
+			log.Warn("Docker", log.Any("ServiceName", serviceName), log.Any("Updated", successCount), log.Any("Message", "滚动更新已中止"))
+			return successCount, true
+		}
+
+		end := start + batchSize
+		if end > len(replicas) {
+			end = len(replicas)
+		}
+		batch := replicas[start:end]
+
+		log.Info("Docker", log.Any("ServiceName", serviceName), log.Any("Batch", start/batchSize+1),
+			log.Any("Size", len(batch)), log.Any("Message", "开始更新批次"))
+
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		for i, replicaIndex := range batch {
+			// 批内前 max_surge 个副本先建后删，其余先删后建
+			wg.Add(1)
+			go func(replicaIndex int, removeFirst bool) {
+				defer wg.Done()
+
+				success := update(replicaIndex, removeFirst)
+				r.progress(success)
+				status := r.snapshot()
+				dockerclient.ReportProgress(ctx, dockerclient.Progress{Step: models.DeployStepUpdating, Current: status.Updated + status.Failed, Total: status.Total,
+					Message: fmt.Sprintf("updated %d/%d replicas", status.Updated, status.Total)})
+				if success {
+					mutex.Lock()
+					successCount++
+					mutex.Unlock()
+				}
+			}(replicaIndex, i >= strategy.MaxSurge)
+		}
+		wg.Wait()
+
+		// 达到 pause_after 后自动暂停一次（仍有剩余批次时）
+		if strategy.PauseAfter > 0 && !autoPaused && successCount >= strategy.PauseAfter && end < len(replicas) {
+			autoPaused = true
+			r.pause(fmt.Sprintf("paused after %d replicas", successCount))
+		}
+	}
+
+	return successCount, false
+}
+
+// rollbackUpdate 更新失败后回滚到旧配置
+// 已更新为新配置的副本（按 config_hash 标签识别）重新用旧配置替换，更新过程中丢失的副本按旧配置补齐到原副本数
+func (s *Service) rollbackUpdate(ctx context.IContext, serviceName string, oldService, newService *dockerclient.Service, replicas int) error {
+	log.Warn("Docker", log.Any("ServiceName", serviceName), log.Any("Image", fmt.Sprintf("%s:%s", oldService.Image, oldService.Tag)),
+		log.Any("Message", "滚动更新失败，开始自动回滚"))
+
+	containers, err := s.dockerClient.ListContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	current := 0
+	var failed []string
+	for _, container := range containers {
+		nameInfo, err := s.dockerClient.ParseContainerName(container.Name)
+		if err != nil || nameInfo.ServiceName != serviceName {
+			continue
+		}
+		current++
+
+		if !s.dockerClient.MatchesConfig(container, newService) {
+			continue // 仍是旧配置，无需回滚
+		}
+
+		if _, _, err := s.dockerClient.UpdateContainer(ctx, serviceName, oldService, nameInfo.ReplicaIndex); err != nil {
+			log.Error("Docker", log.Any("Error", err), log.Any("ReplicaIndex", nameInfo.ReplicaIndex), log.Any("Message", "回滚副本失败"))
+			failed = append(failed, container.Name)
+		}
+	}
+
+	if current < replicas {
+		log.Info("Docker", log.Any("ServiceName", serviceName), log.Any("Missing", replicas-current), log.Any("Message", "按旧配置补齐副本"))
+		if err := s.dockerClient.CreateReplicas(ctx, oldService, replicas-current); err != nil {
+			return fmt.Errorf("failed to recreate replicas: %w", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to roll back containers: %v", failed)
+	}
+
+	log.Info("Docker", log.Any("ServiceName", serviceName), log.Any("Message", "自动回滚完成"))
+	return nil
+}
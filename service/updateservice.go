@@ -8,25 +8,78 @@ import (
 	"github.com/aichy126/igo/log"
 	"github.com/aichy126/onedock/library/dockerclient"
 	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
 	"github.com/jinzhu/copier"
 )
 
 // UpdateService 更新服务 - 实现滚动更新逻辑
 func (s *Service) UpdateService(ctx context.IContext, req *models.ServiceRequest) (*models.Service, error) {
+	if err := normalizeNamespace(req); err != nil {
+		return nil, err
+	}
+	return s.updateService(ctx, req, models.RevisionActionUpdate, "")
+}
+
+// updateService 执行滚动更新，成功后按 action 记录部署历史
+func (s *Service) updateService(ctx context.IContext, req *models.ServiceRequest, action, note string) (*models.Service, error) {
+	return s.applyUpdate(ctx, req, action, note, false)
+}
+
+// redeployService 配置不变时也逐个重建副本，用于同一标签的镜像在仓库中有了新版本
+func (s *Service) redeployService(ctx context.IContext, req *models.ServiceRequest, note string) (*models.Service, error) {
+	return s.applyUpdate(ctx, req, models.RevisionActionUpdate, note, true)
+}
+
+// applyUpdate 滚动更新的实现，force 为 true 时跳过配置比较
+func (s *Service) applyUpdate(ctx context.IContext, req *models.ServiceRequest, action, note string, force bool) (*models.Service, error) {
+	leave, err := s.serialize(ctx, req.Name, action)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
 	//获取现有服务
 	existingService := s.GetService(ctx, req.Name)
 	if existingService == nil {
-		return nil, fmt.Errorf("service %s not found", req.Name)
+		return nil, errServiceNotFound(req.Name)
+	}
+	if err := s.checkServiceLock(ctx, req.Name); err != nil {
+		return nil, err
+	}
+
+	// 校验请求过滤规则
+	if _, err := compileRequestRules(req.RequestRules); err != nil {
+		return nil, err
+	}
+	if err := validateMirrorConfig(req.Name, req.Mirror); err != nil {
+		return nil, err
+	}
+	if err := validateInitContainers(req.InitContainers); err != nil {
+		return nil, err
+	}
+	if err := validateSidecars(req.Sidecars); err != nil {
+		return nil, err
+	}
+	strategy, err := resolveUpdateStrategy(req.UpdateStrategy)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDeploymentMode(req.DeploymentMode); err != nil {
+		return nil, err
+	}
+	if release := s.pendingRelease(req.Name); release != nil {
+		return nil, utils.NewError(utils.CodeConflict, "service %s has a %s release in progress, promote or abort it first", req.Name, release.Mode)
 	}
 
 	log.Info("Docker", log.Any("ServiceName", req.Name), log.Any("Message", "开始滚动更新服务"))
 
 	//构建新的服务配置
 	newDockerService := &dockerclient.Service{}
-	err := copier.Copy(newDockerService, req)
+	err = copier.Copy(newDockerService, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy service request: %w", err)
 	}
+	newDockerService.PublicPort = existingService.PublicPort // 保持公共端口不变
 
 	//获取现有容器列表
 	containers, err := s.dockerClient.ListContainers(ctx)
@@ -66,39 +119,81 @@ func (s *Service) UpdateService(ctx context.IContext, req *models.ServiceRequest
 		return nil, fmt.Errorf("failed to extract old service configuration")
 	}
 
+	// 容器标签中没有环境变量、卷挂载等配置，有期望状态时以其作为旧配置
+	if desired := s.desiredDockerService(req.Name); desired != nil {
+		desired.PublicPort = existingService.PublicPort
+		oldDockerService = desired
+	}
+
 	//比较配置，检查是否需要更新
 	hasChanges := s.dockerClient.CompareServiceConfig(oldDockerService, newDockerService)
-	if !hasChanges {
+	if !hasChanges && !force {
 		log.Info("Docker", log.Any("ServiceName", req.Name), log.Any("Message", "服务配置无变化，返回现有服务"))
 		return existingService, nil
 	}
 
 	log.Info("Docker", log.Any("ServiceName", req.Name), log.Any("Message", "检测到配置变化，开始滚动更新"))
 
-	//逐个更新容器
-	successCount := 0
-
-	for _, container := range serviceContainers {
-		nameInfo, err := s.dockerClient.ParseContainerName(container.Name)
+	// 内存限制增大或取消时校验配额
+	if req.MemoryLimit != oldDockerService.MemoryLimit && (req.MemoryLimit > oldDockerService.MemoryLimit || req.MemoryLimit <= 0) {
+		err := s.checkQuota(ctx, quotaRequest{
+			Name:       req.Name,
+			PublicPort: existingService.PublicPort,
+			Replicas:   existingService.Replicas,
+			Memory:     req.MemoryLimit,
+		})
 		if err != nil {
-			log.Error("Docker", log.Any("Error", err), log.Any("ContainerName", container.Name), log.Any("Message", "解析容器名称失败"))
-			continue
+			return nil, err
 		}
+	}
 
-		// 使用UpdateContainer方法更新单个容器
-		newContainerID, newPort, err := s.dockerClient.UpdateContainer(ctx, req.Name, newDockerService, nameInfo.ReplicaIndex)
-		if err != nil {
-			log.Error("Docker", log.Any("Error", err), log.Any("ReplicaIndex", nameInfo.ReplicaIndex), log.Any("Message", "容器更新失败"))
-			continue
+	// 蓝绿更新：创建完整的新版本副本，确认前不接收流量
+	if req.DeploymentMode == models.DeploymentModeBlueGreen {
+		return s.startBlueGreen(ctx, req, existingService, newDockerService, len(serviceContainers))
+	}
+
+	// 登记滚动更新，同一服务同时只允许一个更新
+	r, err := s.rollouts.start(ctx, req.Name, fmt.Sprintf("%s:%s", req.Image, req.Tag), len(serviceContainers), strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	//按更新策略分批更新容器
+	log.Info("Docker", log.Any("ServiceName", req.Name), log.Any("MaxSurge", strategy.MaxSurge),
+		log.Any("MaxUnavailable", strategy.MaxUnavailable), log.Any("PauseAfter", strategy.PauseAfter), log.Any("Message", "滚动更新策略"))
+	successCount, aborted := s.rollingUpdate(ctx, req.Name, newDockerService, serviceContainers, strategy, r)
+
+	// 手动中止，或有副本更新失败且开启自动回滚时，回滚到旧配置，避免服务停留在新旧混合的状态
+	if aborted || (successCount < len(serviceContainers) && *strategy.AutoRollback) {
+		state := models.RolloutFailed
+		reason := fmt.Sprintf("rolling update failed (%d/%d replicas updated)", successCount, len(serviceContainers))
+		if aborted {
+			state = models.RolloutAborted
+			reason = fmt.Sprintf("rolling update aborted (%d/%d replicas updated)", successCount, len(serviceContainers))
+		}
+		// 请求超时或客户端断开（如暂停期间）时同样回滚，回滚不随请求取消
+		if err := ctx.Err(); err != nil {
+			reason = fmt.Sprintf("rolling update canceled: %v (%d/%d replicas updated)", err, successCount, len(serviceContainers))
+			ctx = detachContext(ctx)
 		}
 
-		successCount++
+		rollbackErr := s.rollbackUpdate(ctx, req.Name, oldDockerService, newDockerService, len(serviceContainers))
+		s.refreshPortProxy(ctx, existingService.PublicPort)
 
-		log.Info("Docker", log.Any("ServiceName", req.Name), log.Any("ReplicaIndex", nameInfo.ReplicaIndex),
-			log.Any("NewContainer", newContainerID[:12]), log.Any("NewPort", newPort), log.Any("Message", "容器更新成功"))
+		if rollbackErr != nil {
+			s.recordEvent(ctx, req.Name, models.EventUpdateFailed, fmt.Sprintf("%s to %s:%s, rollback failed: %v", reason, req.Image, req.Tag, rollbackErr))
+			r.finish(state, fmt.Sprintf("%s, rollback failed: %v", reason, rollbackErr))
+			return nil, fmt.Errorf("%s and rollback failed: %w", reason, rollbackErr)
+		}
+		s.recordEvent(ctx, req.Name, models.EventUpdateFailed, fmt.Sprintf("%s to %s:%s, rolled back to %s:%s",
+			reason, req.Image, req.Tag, oldDockerService.Image, oldDockerService.Tag))
+		r.finish(state, fmt.Sprintf("%s, rolled back to %s:%s", reason, oldDockerService.Image, oldDockerService.Tag))
+		return nil, fmt.Errorf("%s, rolled back to %s:%s", reason, oldDockerService.Image, oldDockerService.Tag)
 	}
 
 	if successCount == 0 {
+		s.recordEvent(ctx, req.Name, models.EventUpdateFailed, fmt.Sprintf("all %d replicas failed to update to %s:%s", len(serviceContainers), req.Image, req.Tag))
+		r.finish(models.RolloutFailed, "all container updates failed")
 		return nil, fmt.Errorf("all container updates failed for service %s", req.Name)
 	}
 
@@ -108,26 +203,29 @@ func (s *Service) UpdateService(ctx context.IContext, req *models.ServiceRequest
 	}
 
 	//更新端口代理
-
-	//删除缓存
-	s.DelContainerMapping(ctx, existingService.PublicPort)
-
-	log.Info("Docker", log.Any("ServiceName", req.Name), log.Any("PublicPort", existingService.PublicPort), log.Any("Message", "更新端口代理"))
-	if err := s.PortManager.UpdatePortProxy(ctx, existingService.PublicPort); err != nil {
-		log.Error("Docker", log.Any("Error", err), log.Any("PublicPort", existingService.PublicPort), log.Any("Message", "更新端口代理失败"))
-		// 端口代理更新失败不影响服务更新结果，记录日志即可
+	s.refreshPortProxy(ctx, existingService.PublicPort)
+	if successCount < len(serviceContainers) {
+		r.finish(models.RolloutCompleted, fmt.Sprintf("%d replicas failed to update", len(serviceContainers)-successCount))
+	} else {
+		r.finish(models.RolloutCompleted, "")
 	}
 
 	//返回更新后的服务信息
 	updatedService := &models.Service{
 		ID:           existingService.ID,
 		Name:         req.Name,
+		Namespace:    existingService.Namespace,
 		Image:        req.Image,
 		Tag:          req.Tag,
 		Status:       models.StatusRunning,
 		PublicPort:   existingService.PublicPort, // 保持公共端口不变
 		InternalPort: req.InternalPort,
 		Replicas:     existingService.Replicas, // 副本数保持不变
+		MemoryLimit:  req.MemoryLimit,
+		BindAddress:  req.BindAddress,
+		ProxyWorkers: req.ProxyWorkers,
+		RequestRules: req.RequestRules,
+		Mirror:       req.Mirror,
 		CreatedAt:    existingService.CreatedAt,
 		UpdatedAt:    time.Now(),
 	}
@@ -135,5 +233,22 @@ func (s *Service) UpdateService(ctx context.IContext, req *models.ServiceRequest
 	log.Info("Docker", log.Any("ServiceName", req.Name), log.Any("UpdatedContainers", successCount),
 		log.Any("Message", "滚动更新完成"))
 
+	s.saveDesiredState(ctx, req, existingService.PublicPort, existingService.Replicas)
+	s.recordRevision(ctx, action, req, note)
+	s.recordEvent(ctx, req.Name, models.EventUpdated, fmt.Sprintf("updated %d/%d replicas to %s:%s",
+		successCount, len(serviceContainers), req.Image, req.Tag))
+
 	return updatedService, nil
 }
+
+// refreshPortProxy 清除端口映射缓存并按最新容器重建端口代理
+func (s *Service) refreshPortProxy(ctx context.IContext, publicPort int) {
+	//删除缓存
+	s.DelContainerMapping(ctx, publicPort)
+
+	log.Info("Docker", log.Any("PublicPort", publicPort), log.Any("Message", "更新端口代理"))
+	if err := s.PortManager.UpdatePortProxy(ctx, publicPort); err != nil {
+		log.Error("Docker", log.Any("Error", err), log.Any("PublicPort", publicPort), log.Any("Message", "更新端口代理失败"))
+		// 端口代理更新失败不影响服务更新结果，记录日志即可
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/onedock/library/validate"
+	"github.com/aichy126/onedock/models"
+	"github.com/distribution/reference"
+)
+
+// ValidateServiceRequest 静态校验服务配置，返回错误和警告，不访问镜像仓库，不创建或修改任何容器
+func (s *Service) ValidateServiceRequest(ctx context.IContext, req *models.ServiceRequest) *models.ValidationReport {
+	report := &models.ValidationReport{Errors: make([]models.FieldError, 0), Warnings: make([]models.FieldError, 0)}
+	fail := func(field, constraint string, value interface{}, err error) {
+		report.Errors = append(report.Errors, models.FieldError{Field: field, Constraint: constraint, Value: value, Message: err.Error()})
+	}
+
+	if err := normalizeNamespace(req); err != nil {
+		fail("name", "servicename", req.Name, err)
+	} else if err := checkServiceScope(ctx, req.Name); err != nil {
+		fail("name", "scope", req.Name, err)
+	}
+	report.Service = req.Name
+
+	if err := validate.Struct(req); err != nil {
+		if validation, ok := err.(*models.ValidationError); ok {
+			report.Errors = append(report.Errors, validation.Fields...)
+		} else {
+			fail("", "", nil, err)
+		}
+	}
+	imageErrors, imageWarnings := lintImageReference(req.Image, req.Tag)
+	report.Errors = append(report.Errors, imageErrors...)
+	report.Warnings = append(report.Warnings, imageWarnings...)
+
+	if req.PublicPort > 0 && models.ValidServiceName(req.Name) {
+		if err := s.checkPortRegistry(req.Name, req.PublicPort); err != nil {
+			fail("public_port", "port", req.PublicPort, err)
+		}
+	}
+	if req.EnvFile != "" {
+		if _, err := s.dockerClient.ReadEnvFile(req.EnvFile); err != nil {
+			fail("env_file", "envfile", req.EnvFile, fmt.Errorf("env_file %s is not readable: %v", req.EnvFile, err))
+		}
+	}
+	if err := s.checkSecretRefs(req.Environment); err != nil {
+		fail("environment", "secret", nil, err)
+	}
+	if err := s.checkEnvPlaceholders(ctx, req.Environment); err != nil {
+		fail("environment", "placeholder", nil, err)
+	}
+	volumeErrors, volumeWarnings := lintVolumes(req.Volumes)
+	report.Errors = append(report.Errors, volumeErrors...)
+	report.Warnings = append(report.Warnings, volumeWarnings...)
+
+	if _, err := compileRequestRules(req.RequestRules); err != nil {
+		fail("request_rules", "rule", nil, err)
+	}
+	if err := validateMirrorConfig(req.Name, req.Mirror); err != nil {
+		fail("mirror", "mirror", nil, err)
+	}
+	if err := validateInitContainers(req.InitContainers); err != nil {
+		fail("init_containers", "container", nil, err)
+	}
+	if err := validateSidecars(req.Sidecars); err != nil {
+		fail("sidecars", "container", nil, err)
+	}
+
+	report.Valid = len(report.Errors) == 0
+	return report
+}
+
+// lintImageReference 检查镜像名称和标签是否符合 Docker 镜像引用格式，使用 latest 标签时给出警告
+func lintImageReference(image, tag string) (errs, warnings []models.FieldError) {
+	if image == "" || tag == "" {
+		return nil, nil // 必填项缺失已由字段校验报告
+	}
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		errs = append(errs, models.FieldError{Field: "image", Constraint: "image", Value: image,
+			Message: fmt.Sprintf("image %q is not a valid image reference: %v", image, err)})
+		return errs, nil
+	}
+	if !reference.IsNameOnly(named) {
+		errs = append(errs, models.FieldError{Field: "image", Constraint: "image", Value: image,
+			Message: fmt.Sprintf("image %q must not contain a tag or digest, use the tag field", image)})
+		return errs, nil
+	}
+	if _, err := reference.WithTag(named, tag); err != nil {
+		errs = append(errs, models.FieldError{Field: "tag", Constraint: "tag", Value: tag,
+			Message: fmt.Sprintf("tag %q is not a valid image tag", tag)})
+		return errs, nil
+	}
+	if tag == "latest" {
+		warnings = append(warnings, models.FieldError{Field: "tag", Constraint: "tag", Value: tag,
+			Message: "tag latest is mutable, replicas recreated later may run a different image"})
+	}
+	return errs, warnings
+}
+
+// volumeNamePattern Docker 命名卷名称格式
+var volumeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// lintVolumes 检查卷挂载路径：来源为主机绝对路径或命名卷名称，容器内路径必须是绝对路径且不能重复
+// 主机路径不存在时给出警告（Docker 会创建空目录）
+func lintVolumes(volumes []models.VolumeMount) (errs, warnings []models.FieldError) {
+	seen := make(map[string]bool, len(volumes))
+	for i, volume := range volumes {
+		prefix := fmt.Sprintf("volumes[%d]", i)
+		if !filepath.IsAbs(volume.Source) {
+			if !volumeNamePattern.MatchString(volume.Source) {
+				errs = append(errs, models.FieldError{Field: prefix + ".Source", Constraint: "path", Value: volume.Source,
+					Message: fmt.Sprintf("volume source %q must be an absolute host path or a volume name", volume.Source)})
+			}
+		} else if _, err := os.Stat(volume.Source); err != nil {
+			warnings = append(warnings, models.FieldError{Field: prefix + ".Source", Constraint: "exists", Value: volume.Source,
+				Message: fmt.Sprintf("volume source %s does not exist, docker will create an empty directory", volume.Source)})
+		}
+		if !filepath.IsAbs(volume.Destination) {
+			errs = append(errs, models.FieldError{Field: prefix + ".Destination", Constraint: "path", Value: volume.Destination,
+				Message: fmt.Sprintf("volume destination %q must be an absolute path", volume.Destination)})
+		} else if seen[volume.Destination] {
+			errs = append(errs, models.FieldError{Field: prefix + ".Destination", Constraint: "unique", Value: volume.Destination,
+				Message: fmt.Sprintf("volume destination %s is mounted more than once", volume.Destination)})
+		}
+		seen[volume.Destination] = true
+	}
+	return errs, warnings
+}
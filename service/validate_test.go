@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestLintImageReference 测试镜像引用格式检查
+func TestLintImageReference(t *testing.T) {
+	tests := []struct {
+		image, tag string
+		errField   string
+		warning    bool
+	}{
+		{image: "nginx", tag: "1.27-alpine"},
+		{image: "registry.example.com:5000/team/app", tag: "v1.2.3"},
+		{image: "nginx", tag: "latest", warning: true},
+		{image: "Nginx", tag: "alpine", errField: "image"},
+		{image: "nginx:alpine", tag: "alpine", errField: "image"},
+		{image: "nginx", tag: "-bad", errField: "tag"},
+		{image: "nginx", tag: "a/b", errField: "tag"},
+		{image: "", tag: "alpine"},
+	}
+	for _, tt := range tests {
+		errs, warnings := lintImageReference(tt.image, tt.tag)
+		if tt.errField == "" && len(errs) != 0 {
+			t.Errorf("lintImageReference(%q, %q) 返回错误 %+v", tt.image, tt.tag, errs)
+		}
+		if tt.errField != "" && (len(errs) != 1 || errs[0].Field != tt.errField) {
+			t.Errorf("lintImageReference(%q, %q) = %+v, 期望 %s 字段错误", tt.image, tt.tag, errs, tt.errField)
+		}
+		if (len(warnings) != 0) != tt.warning {
+			t.Errorf("lintImageReference(%q, %q) 警告 = %+v", tt.image, tt.tag, warnings)
+		}
+	}
+}
+
+// TestLintVolumes 测试卷挂载路径检查
+func TestLintVolumes(t *testing.T) {
+	dir := t.TempDir()
+	errs, warnings := lintVolumes([]models.VolumeMount{
+		{Source: dir, Destination: "/data"},
+		{Source: "app-data", Destination: "/var/lib/app"},
+		{Source: dir + "/missing", Destination: "/cache"},
+		{Source: "./relative", Destination: "/relative"},
+		{Source: dir, Destination: "data"},
+		{Source: dir, Destination: "/data"},
+	})
+
+	fields := make([]string, 0, len(errs))
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	want := []string{"volumes[3].Source", "volumes[4].Destination", "volumes[5].Destination"}
+	if len(fields) != len(want) {
+		t.Fatalf("错误字段 = %v, 期望 %v", fields, want)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("错误字段 = %v, 期望 %v", fields, want)
+		}
+	}
+	if len(warnings) != 1 || warnings[0].Field != "volumes[2].Source" {
+		t.Errorf("警告 = %+v, 期望 volumes[2].Source 不存在", warnings)
+	}
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	stdcontext "context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// volumeSizeTimeout 统计主机目录占用的超时时间，超时未统计完的目录占用记为 -1
+const volumeSizeTimeout = 10 * time.Second
+
+// volumeUsage 按命名卷名称和主机目录路径汇总副本的挂载，tmpfs 等其他挂载不计入
+// 结果按类型、名称和路径排序，副本按服务、副本编号和容器内路径排序
+func volumeUsage(containers []dockerclient.ContainerInfo, parse func(string) (*dockerclient.ContainerNameInfo, error)) []*models.Volume {
+	volumes := make(map[string]*models.Volume)
+	for _, container := range containers {
+		nameInfo, err := parse(container.Name)
+		if err != nil {
+			continue
+		}
+		for _, mount := range container.Mounts {
+			key := mount.Source
+			switch mount.Type {
+			case models.VolumeTypeVolume:
+				key = mount.Name
+			case models.VolumeTypeBind:
+			default:
+				continue
+			}
+			key = mount.Type + ":" + key
+			volume := volumes[key]
+			if volume == nil {
+				volume = &models.Volume{Type: mount.Type, Source: mount.Source, Size: -1, Services: []string{}}
+				if mount.Type == models.VolumeTypeVolume {
+					volume.Name = mount.Name
+				}
+				volumes[key] = volume
+			}
+			volume.Users = append(volume.Users, models.VolumeUser{
+				Service:     nameInfo.ServiceName,
+				Replica:     nameInfo.ReplicaIndex,
+				ContainerID: container.ID[:12],
+				State:       container.State,
+				Destination: mount.Destination,
+				ReadWrite:   mount.ReadWrite,
+			})
+		}
+	}
+
+	result := make([]*models.Volume, 0, len(volumes))
+	for _, volume := range volumes {
+		sort.Slice(volume.Users, func(i, j int) bool {
+			a, b := volume.Users[i], volume.Users[j]
+			if a.Service != b.Service {
+				return a.Service < b.Service
+			}
+			if a.Replica != b.Replica {
+				return a.Replica < b.Replica
+			}
+			return a.Destination < b.Destination
+		})
+		for i, user := range volume.Users {
+			if i == 0 || user.Service != volume.Users[i-1].Service {
+				volume.Services = append(volume.Services, user.Service)
+			}
+		}
+		result = append(result, volume)
+	}
+	sortVolumes(result)
+	return result
+}
+
+// sortVolumes 命名卷在前，同类按名称和路径排序
+func sortVolumes(volumes []*models.Volume) {
+	sort.Slice(volumes, func(i, j int) bool {
+		a, b := volumes[i], volumes[j]
+		if a.Type != b.Type {
+			return a.Type == models.VolumeTypeVolume
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Source < b.Source
+	})
+}
+
+// matchVolume 卷是否满足过滤条件
+func matchVolume(volume *models.Volume, query *models.VolumeQuery) bool {
+	if query.Type != "" && volume.Type != query.Type {
+		return false
+	}
+	if query.Path != "" && filepath.Clean(volume.Source) != filepath.Clean(query.Path) {
+		return false
+	}
+	if query.Service != "" {
+		for _, service := range volume.Services {
+			if service == query.Service {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// dirSize 统计目录下普通文件的总大小，路径是文件时返回文件大小；ctx 结束时停止统计
+func dirSize(ctx stdcontext.Context, path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// fillBindSizes 并发统计主机目录的占用，OneDock 无法访问该目录或超时时占用为 -1 并记录原因
+func fillBindSizes(ctx context.IContext, volumes []*models.Volume) {
+	sizeCtx, cancel := ctx.WithTimeout(volumeSizeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, volume := range volumes {
+		if volume.Type != models.VolumeTypeBind {
+			continue
+		}
+		wg.Add(1)
+		go func(volume *models.Volume) {
+			defer wg.Done()
+			size, err := dirSize(sizeCtx, volume.Source)
+			if err != nil {
+				volume.Error = err.Error()
+				return
+			}
+			volume.Size = size
+		}(volume)
+	}
+	wg.Wait()
+}
+
+// managedVolumes 汇总受管副本使用的命名卷和主机目录
+func (s *Service) managedVolumes(ctx context.IContext) ([]*models.Volume, error) {
+	containers, err := s.dockerClient.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return volumeUsage(containers, s.dockerClient.ParseContainerName), nil
+}
+
+// ListVolumes 列出服务使用的命名卷和主机目录，包括使用它的副本和磁盘占用
+// query.All 为 true 时同时列出没有受管服务使用的命名卷
+func (s *Service) ListVolumes(ctx context.IContext, query *models.VolumeQuery) ([]*models.Volume, error) {
+	volumes, err := s.managedVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Type != models.VolumeTypeBind {
+		dockerVolumes, err := s.dockerClient.ListVolumes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		used := make(map[string]*models.Volume)
+		for _, volume := range volumes {
+			if volume.Type == models.VolumeTypeVolume {
+				used[volume.Name] = volume
+			}
+		}
+		for _, info := range dockerVolumes {
+			volume := used[info.Name]
+			if volume == nil {
+				if !query.All {
+					continue
+				}
+				volume = &models.Volume{Type: models.VolumeTypeVolume, Name: info.Name, Services: []string{}, Users: []models.VolumeUser{}}
+				volumes = append(volumes, volume)
+			}
+			fillVolumeInfo(volume, &info)
+		}
+	}
+
+	result := make([]*models.Volume, 0, len(volumes))
+	for _, volume := range volumes {
+		if matchVolume(volume, query) {
+			result = append(result, volume)
+		}
+	}
+	fillBindSizes(ctx, result)
+	sortVolumes(result)
+	return result, nil
+}
+
+// fillVolumeInfo 用 Docker 卷信息补充驱动、挂载点、创建时间和占用
+func fillVolumeInfo(volume *models.Volume, info *dockerclient.VolumeInfo) {
+	volume.Driver = info.Driver
+	volume.Source = info.Mountpoint
+	volume.CreatedAt = info.CreatedAt
+	volume.Size = info.Size
+}
+
+// GetVolume 获取命名卷及使用它的副本
+func (s *Service) GetVolume(ctx context.IContext, name string) (*models.Volume, error) {
+	info, err := s.dockerClient.InspectVolume(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := s.managedVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.Volume{Type: models.VolumeTypeVolume, Name: name, Services: []string{}, Users: []models.VolumeUser{}}
+	for _, volume := range volumes {
+		if volume.Type == models.VolumeTypeVolume && volume.Name == name {
+			result = volume
+			break
+		}
+	}
+	fillVolumeInfo(result, info)
+	return result, nil
+}
+
+// DeleteVolume 删除命名卷，卷中的数据随之删除
+// 仍有受管副本（包括已停止的副本）挂载该卷时拒绝删除；主机目录不通过 OneDock 删除
+func (s *Service) DeleteVolume(ctx context.IContext, name string) error {
+	volume, err := s.GetVolume(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(volume.Services) > 0 {
+		return utils.NewError(utils.CodeConflict, "volume %s is used by services %v, delete or update them first", name, volume.Services)
+	}
+	if err := s.dockerClient.RemoveVolume(ctx, name); err != nil {
+		return err
+	}
+	log.Info("Docker", log.Any("Volume", name), log.Any("Size", volume.Size), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "卷已删除"))
+	return nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	stdcontext "context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aichy126/onedock/library/dockerclient"
+	"github.com/aichy126/onedock/models"
+)
+
+// parseTestContainerName 解析测试用的容器名称 <服务>-<副本编号>
+func parseTestContainerName(name string) (*dockerclient.ContainerNameInfo, error) {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid container name %s", name)
+	}
+	var index int
+	if _, err := fmt.Sscanf(name[i+1:], "%d", &index); err != nil {
+		return nil, err
+	}
+	return &dockerclient.ContainerNameInfo{ServiceName: name[:i], ReplicaIndex: index}, nil
+}
+
+// TestVolumeUsage 测试按卷名称和主机路径汇总副本挂载
+func TestVolumeUsage(t *testing.T) {
+	containers := []dockerclient.ContainerInfo{
+		{ID: "bbbbbbbbbbbb0001", Name: "web-1", State: "running", Mounts: []dockerclient.MountInfo{
+			{Type: "volume", Name: "web-data", Source: "/var/lib/docker/volumes/web-data/_data", Destination: "/data", ReadWrite: true},
+			{Type: "bind", Source: "/srv/shared", Destination: "/shared"},
+		}},
+		{ID: "aaaaaaaaaaaa0001", Name: "web-0", State: "exited", Mounts: []dockerclient.MountInfo{
+			{Type: "volume", Name: "web-data", Source: "/var/lib/docker/volumes/web-data/_data", Destination: "/data", ReadWrite: true},
+			{Type: "tmpfs", Destination: "/tmp"},
+		}},
+		{ID: "cccccccccccc0001", Name: "api-0", State: "running", Mounts: []dockerclient.MountInfo{
+			{Type: "bind", Source: "/srv/shared", Destination: "/mnt/shared", ReadWrite: true},
+		}},
+		{ID: "dddddddddddd0001", Name: "orphan", Mounts: []dockerclient.MountInfo{
+			{Type: "volume", Name: "orphan-data", Destination: "/data"},
+		}},
+	}
+
+	volumes := volumeUsage(containers, parseTestContainerName)
+	if len(volumes) != 2 {
+		t.Fatalf("volumeUsage() 返回 %d 个卷, 期望 2 个（tmpfs 和无法解析的容器不计入）", len(volumes))
+	}
+
+	named := volumes[0]
+	if named.Type != models.VolumeTypeVolume || named.Name != "web-data" || named.Size != -1 {
+		t.Errorf("第一个卷 = %+v, 期望命名卷 web-data 且占用未知", named)
+	}
+	if !reflect.DeepEqual(named.Services, []string{"web"}) {
+		t.Errorf("web-data 的服务 = %v, 期望 [web]", named.Services)
+	}
+	if len(named.Users) != 2 || named.Users[0].Replica != 0 || named.Users[0].ContainerID != "aaaaaaaaaaaa" || named.Users[0].State != "exited" {
+		t.Errorf("web-data 的副本 = %+v, 期望按副本编号排序", named.Users)
+	}
+
+	bind := volumes[1]
+	if bind.Type != models.VolumeTypeBind || bind.Name != "" || bind.Source != "/srv/shared" {
+		t.Errorf("第二个卷 = %+v, 期望主机目录 /srv/shared", bind)
+	}
+	if !reflect.DeepEqual(bind.Services, []string{"api", "web"}) {
+		t.Errorf("/srv/shared 的服务 = %v, 期望 [api web]", bind.Services)
+	}
+	if bind.Users[0].Destination != "/mnt/shared" || !bind.Users[0].ReadWrite || bind.Users[1].ReadWrite {
+		t.Errorf("/srv/shared 的副本 = %+v", bind.Users)
+	}
+}
+
+// TestMatchVolume 测试卷列表的过滤条件
+func TestMatchVolume(t *testing.T) {
+	volume := &models.Volume{Type: models.VolumeTypeBind, Source: "/srv/shared", Services: []string{"api", "web"}}
+	tests := []struct {
+		query models.VolumeQuery
+		want  bool
+	}{
+		{models.VolumeQuery{}, true},
+		{models.VolumeQuery{Type: models.VolumeTypeBind}, true},
+		{models.VolumeQuery{Type: models.VolumeTypeVolume}, false},
+		{models.VolumeQuery{Service: "web"}, true},
+		{models.VolumeQuery{Service: "db"}, false},
+		{models.VolumeQuery{Path: "/srv/shared/"}, true},
+		{models.VolumeQuery{Path: "/srv"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchVolume(volume, &tt.query); got != tt.want {
+			t.Errorf("matchVolume(%+v) = %v, 期望 %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+// TestDirSize 测试目录占用统计
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 50), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if size, err := dirSize(stdcontext.Background(), dir); err != nil || size != 150 {
+		t.Errorf("dirSize(目录) = %d, %v, 期望 150", size, err)
+	}
+	if size, err := dirSize(stdcontext.Background(), filepath.Join(dir, "a")); err != nil || size != 100 {
+		t.Errorf("dirSize(文件) = %d, %v, 期望 100", size, err)
+	}
+	if _, err := dirSize(stdcontext.Background(), filepath.Join(dir, "missing")); err == nil {
+		t.Error("dirSize(不存在的路径) 期望返回错误")
+	}
+
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	cancel()
+	if _, err := dirSize(ctx, dir); err == nil {
+		t.Error("ctx 已取消时 dirSize 期望返回错误")
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	stdcontext "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/aichy126/igo/context"
+	"github.com/aichy126/igo/log"
+	"github.com/aichy126/onedock/library/store"
+	"github.com/aichy126/onedock/models"
+	"github.com/aichy126/onedock/utils"
+)
+
+// webhookNamePattern 订阅名称：字母、数字、下划线、点和连字符
+var webhookNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// webhookEventTypes 可以订阅的事件类型，代理启动和停止只在实时事件流中推送，不投递
+var webhookEventTypes = []string{
+	models.EventDeployed, models.EventUpdated, models.EventUpdateFailed, models.EventScaled, models.EventDeleted, models.EventRestored,
+	models.EventReplicaCrashed, models.EventReplicaRestarted, models.EventReplicaRecreated, models.EventCrashLoop,
+	models.EventAutoscaled, models.EventProxyRestarted,
+}
+
+// validateWebhookRequest 校验订阅名称、地址和事件类型
+func validateWebhookRequest(req *models.WebhookRequest) error {
+	if !webhookNamePattern.MatchString(req.Name) {
+		return utils.NewError(utils.CodeInvalidRequest, "invalid webhook name %q: only letters, digits, '_', '.' and '-' are allowed", req.Name)
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return utils.NewError(utils.CodeInvalidRequest, "invalid webhook url %q: must be an absolute http or https url", req.URL)
+	}
+	for _, eventType := range req.Events {
+		if !matchAny(webhookEventTypes, eventType) {
+			return utils.NewError(utils.CodeInvalidRequest, "unknown event type %q", eventType)
+		}
+	}
+	return nil
+}
+
+// toWebhook 将存储中的订阅转换为 API 模型（不含签名密钥）
+func toWebhook(webhook *store.Webhook) *models.Webhook {
+	result := &models.Webhook{
+		Name:       webhook.Name,
+		URL:        webhook.URL,
+		HasSecret:  webhook.Secret != "",
+		Events:     webhook.Events,
+		Services:   webhook.Services,
+		Disabled:   webhook.Disabled,
+		LastStatus: webhook.LastStatus,
+		LastError:  webhook.LastError,
+		CreatedAt:  webhook.CreatedAt,
+		UpdatedAt:  webhook.UpdatedAt,
+	}
+	if result.Events == nil {
+		result.Events = []string{}
+	}
+	if result.Services == nil {
+		result.Services = []string{}
+	}
+	if !webhook.LastDeliveryAt.IsZero() {
+		lastDeliveryAt := webhook.LastDeliveryAt
+		result.LastDeliveryAt = &lastDeliveryAt
+	}
+	return result
+}
+
+// SaveWebhook 创建或更新事件订阅，签名密钥以 secret.key 加密保存
+func (s *Service) SaveWebhook(ctx context.IContext, req *models.WebhookRequest) (*models.Webhook, error) {
+	if err := validateWebhookRequest(req); err != nil {
+		return nil, err
+	}
+	sort.Strings(req.Events)
+	sort.Strings(req.Services)
+	record := &store.Webhook{
+		Name:     req.Name,
+		URL:      req.URL,
+		Events:   uniqueStrings(req.Events),
+		Services: uniqueStrings(req.Services),
+		Disabled: req.Disabled,
+	}
+	if req.Secret != "" {
+		key, err := secretKey()
+		if err != nil {
+			return nil, fmt.Errorf("webhook secret requires secret.key: %w", err)
+		}
+		if record.Secret, err = encryptSecret(key, req.Secret); err != nil {
+			return nil, fmt.Errorf("failed to encrypt webhook secret: %w", err)
+		}
+	}
+	if err := s.store.SaveWebhook(record); err != nil {
+		return nil, err
+	}
+
+	log.Info("Webhook", log.Any("Webhook", req.Name), log.Any("URL", req.URL), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "保存事件订阅"))
+	return toWebhook(record), nil
+}
+
+// ListWebhooks 列出全部事件订阅
+func (s *Service) ListWebhooks(ctx context.IContext) ([]*models.Webhook, error) {
+	records, err := s.store.ListWebhooks()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Webhook, 0, len(records))
+	for _, record := range records {
+		result = append(result, toWebhook(record))
+	}
+	return result, nil
+}
+
+// GetWebhook 获取事件订阅及最近一次投递结果
+func (s *Service) GetWebhook(ctx context.IContext, name string) (*models.Webhook, error) {
+	record, err := s.store.GetWebhook(name)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, utils.NewError(utils.CodeNotFound, "webhook %s not found", name)
+	}
+	return toWebhook(record), nil
+}
+
+// DeleteWebhook 删除事件订阅
+func (s *Service) DeleteWebhook(ctx context.IContext, name string) error {
+	exists, err := s.store.DeleteWebhook(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return utils.NewError(utils.CodeNotFound, "webhook %s not found", name)
+	}
+	log.Info("Webhook", log.Any("Webhook", name), log.Any("Actor", actorFromContext(ctx)), log.Any("Message", "删除事件订阅"))
+	return nil
+}
+
+// deliverWebhooks 将事件异步投递到所有匹配的订阅，并记录每个订阅最近一次投递的结果
+func (s *Service) deliverWebhooks(event *models.Event, host string, timeout time.Duration) {
+	webhooks, err := s.store.ListWebhooks()
+	if err != nil {
+		log.Error("Webhook", log.Any("Error", err), log.Any("Message", "读取事件订阅失败"))
+		return
+	}
+	for _, webhook := range webhooks {
+		if webhook.Disabled || !matchAny(webhook.Events, event.Type) || !matchAny(webhook.Services, event.Service) {
+			continue
+		}
+		go func(webhook *store.Webhook) {
+			ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), timeout)
+			defer cancel()
+
+			var status int
+			secret, err := webhookSecret(webhook)
+			if err == nil {
+				status, err = sendWebhook(ctx, webhook.URL, webhook.Name, secret, host, event)
+			}
+			lastError := ""
+			if err != nil {
+				lastError = err.Error()
+				log.Error("Webhook", log.Any("Error", err), log.Any("Webhook", webhook.Name), log.Any("ServiceName", event.Service),
+					log.Any("Event", event.Type), log.Any("Message", "投递事件失败"))
+			}
+			if err := s.store.UpdateWebhookDelivery(webhook.Name, status, lastError, time.Now()); err != nil {
+				log.Error("Webhook", log.Any("Error", err), log.Any("Webhook", webhook.Name), log.Any("Message", "记录投递结果失败"))
+			}
+		}(webhook)
+	}
+}
+
+// webhookSecret 解密订阅的签名密钥，未设置时返回空字符串
+func webhookSecret(webhook *store.Webhook) (string, error) {
+	if webhook.Secret == "" {
+		return "", nil
+	}
+	key, err := secretKey()
+	if err != nil {
+		return "", err
+	}
+	return decryptSecret(key, webhook.Secret)
+}
+
+// sendWebhook 投递一条事件，请求体与 [[notifications.notifiers]] 的 webhook 通知相同，返回 HTTP 状态码
+func sendWebhook(ctx stdcontext.Context, url, name, secret, host string, event *models.Event) (int, error) {
+	body, err := json.Marshal(&eventNotification{Event: event, Host: host})
+	if err != nil {
+		return 0, err
+	}
+	headers := map[string]string{
+		models.WebhookEventHeader: event.Type,
+		models.WebhookNameHeader:  name,
+	}
+	if secret != "" {
+		headers[models.WebhookSignatureHeader] = webhookSignature(secret, body)
+	}
+	return postBody(ctx, url, headers, body)
+}
+
+// webhookSignature 请求体的 HMAC-SHA256 签名，格式为 sha256=<十六进制>
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
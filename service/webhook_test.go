@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aichy126/onedock/models"
+)
+
+// TestValidateWebhookRequest 测试订阅名称、地址和事件类型的校验
+func TestValidateWebhookRequest(t *testing.T) {
+	cases := []struct {
+		req  models.WebhookRequest
+		want string
+	}{
+		{models.WebhookRequest{Name: "tracker", URL: "https://ci.example.com/hooks", Events: []string{models.EventDeployed}}, ""},
+		{models.WebhookRequest{Name: "a b", URL: "https://ci.example.com/hooks"}, "invalid webhook name"},
+		{models.WebhookRequest{Name: "tracker", URL: "ftp://ci.example.com"}, "invalid webhook url"},
+		{models.WebhookRequest{Name: "tracker", URL: "/hooks"}, "invalid webhook url"},
+		{models.WebhookRequest{Name: "tracker", URL: "http://ci.example.com", Events: []string{models.EventProxyStarted}}, "unknown event type"},
+	}
+	for _, c := range cases {
+		err := validateWebhookRequest(&c.req)
+		if (c.want == "" && err != nil) || (c.want != "" && (err == nil || !strings.Contains(err.Error(), c.want))) {
+			t.Errorf("validateWebhookRequest(%+v) = %v, 期望 %q", c.req, err, c.want)
+		}
+	}
+}
+
+// TestSendWebhook 测试投递的请求头、请求体和签名
+func TestSendWebhook(t *testing.T) {
+	var header http.Header
+	var body []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	event := &models.Event{ID: 9, Service: "nginx-web", Type: models.EventDeployed, Message: "deployed 2 replicas", CreatedAt: time.Now()}
+	code, err := sendWebhook(context.Background(), server.URL, "tracker", "whsec", "host-1", event)
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("sendWebhook = %d, %v", code, err)
+	}
+	var received map[string]interface{}
+	json.Unmarshal(body, &received)
+	if received["type"] != models.EventDeployed || received["host"] != "host-1" {
+		t.Errorf("请求体 = %s, 期望包含事件和主机名", body)
+	}
+	if header.Get(models.WebhookEventHeader) != models.EventDeployed || header.Get(models.WebhookNameHeader) != "tracker" {
+		t.Errorf("请求头 = %v", header)
+	}
+	if got := header.Get(models.WebhookSignatureHeader); got != webhookSignature("whsec", body) || !strings.HasPrefix(got, "sha256=") {
+		t.Errorf("签名 = %q, 期望 %q", got, webhookSignature("whsec", body))
+	}
+
+	status = http.StatusBadGateway
+	if code, err := sendWebhook(context.Background(), server.URL, "tracker", "", "host-1", event); err == nil || code != http.StatusBadGateway {
+		t.Errorf("非 2xx 响应应返回错误和状态码: %d, %v", code, err)
+	}
+	if header.Get(models.WebhookSignatureHeader) != "" {
+		t.Error("未设置密钥时不应发送签名头")
+	}
+}
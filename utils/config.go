@@ -1,22 +1,23 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 
 	"github.com/aichy126/igo"
+	"github.com/aichy126/igo/log"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 // Rfail 错误返回
 func Rfail(c *gin.Context, msg string) {
-	c.JSON(http.StatusOK, gin.H{
-		"code": 1,
-		"msg":  msg,
-		"data": nil,
-	})
+	RfailCode(c, CodeInvalidRequest, msg, nil)
 }
 
 // Rsucc 成功返回
@@ -54,6 +55,14 @@ func ConfGetbool(path string) bool {
 	return igo.App.Conf.GetBool(path)
 }
 
+// ConfGetboolDefault 读取布尔配置，未配置时返回默认值
+func ConfGetboolDefault(path string, def bool) bool {
+	if !igo.App.Conf.IsSet(path) {
+		return def
+	}
+	return igo.App.Conf.GetBool(path)
+}
+
 func ConfGetString(path string) string {
 	return igo.App.Conf.GetString(path)
 }
@@ -66,6 +75,66 @@ func ConfGetInt(path string) int {
 	return igo.App.Conf.GetInt(path)
 }
 
+// ConfGetIntDefault 读取整数配置，未配置时返回默认值
+func ConfGetIntDefault(path string, def int) int {
+	if !igo.App.Conf.IsSet(path) {
+		return def
+	}
+	return igo.App.Conf.GetInt(path)
+}
+
+// ReloadConfig 重新读取配置文件
+func ReloadConfig() error {
+	return igo.App.Conf.ReadInConfig()
+}
+
+// ConfigFile 当前使用的配置文件路径
+func ConfigFile() string {
+	return igo.App.Conf.ConfigFileUsed()
+}
+
+// WriteConfigValues 将配置项写回配置文件并重新读取，values 的键为点分隔的配置路径，值为 TOML 字面量
+// 只修改对应的行，文件中的注释和其他配置保持不变
+func WriteConfigValues(values map[string]string) error {
+	path := ConfigFile()
+	if path == "" {
+		return errors.New("config is not loaded from a file")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	text := string(content)
+	for _, key := range keys {
+		text = SetTOMLValue(text, key, values[key])
+	}
+	if err := os.WriteFile(path, []byte(text), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return ReloadConfig()
+}
+
+// ReloadLogger 按当前的 local.logger 配置重新初始化日志，用于运行时调整日志级别
+func ReloadLogger() error {
+	_, err := log.NewLog(igo.App.Conf)
+	return err
+}
+
+// TokenID 令牌指纹，用于记录服务所有者、匹配令牌配额等，不暴露令牌本身
+func TokenID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "tok_" + hex.EncodeToString(sum[:6])
+}
+
 func GenerateToken() string {
 	uid, _ := uuid.NewUUID()
 	return uid.String()
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/docker/docker/client"
+	"github.com/gin-gonic/gin"
+)
+
+// 失败响应中的错误码（error_code），客户端按错误码判断失败原因，不依赖 msg 的措辞
+const (
+	CodeInvalidRequest    = "INVALID_REQUEST"    // 请求参数错误
+	CodeValidationFailed  = "VALIDATION_FAILED"  // 请求字段校验失败，data.errors 列出不合法的字段
+	CodeUnauthorized      = "UNAUTHORIZED"       // 缺少或无效的凭据
+	CodeForbidden         = "FORBIDDEN"          // 凭据有效但无权执行该操作
+	CodeRateLimited       = "RATE_LIMITED"       // 请求过于频繁
+	CodeNotFound          = "NOT_FOUND"          // 部署记录、模板、密钥、任务等资源不存在
+	CodeServiceNotFound   = "SERVICE_NOT_FOUND"  // 服务不存在
+	CodeServiceExists     = "SERVICE_EXISTS"     // 服务已存在
+	CodeServiceLocked     = "SERVICE_LOCKED"     // 服务已锁定
+	CodeServiceBusy       = "SERVICE_BUSY"       // 服务的其他操作排队超时
+	CodeConflict          = "CONFLICT"           // 与服务当前状态冲突，如发布进行中
+	CodePortInUse         = "PORT_IN_USE"        // 公共端口已被占用或预留
+	CodeQuotaExceeded     = "QUOTA_EXCEEDED"     // 超出配额
+	CodeImagePullFailed   = "IMAGE_PULL_FAILED"  // 镜像拉取失败
+	CodeDockerUnavailable = "DOCKER_UNAVAILABLE" // 无法连接 Docker 守护进程
+	CodeTimeout           = "TIMEOUT"            // 超过接口超时时间，进行中的操作已取消
+	CodeOperationFailed   = "OPERATION_FAILED"   // 其他未归类的失败
+)
+
+// CodedError 带错误码的错误
+type CodedError struct {
+	Code string
+	Err  error
+}
+
+func (e *CodedError) Error() string { return e.Err.Error() }
+
+func (e *CodedError) Unwrap() error { return e.Err }
+
+// ErrorCode 错误码
+func (e *CodedError) ErrorCode() string { return e.Code }
+
+// NewError 创建带错误码的错误，format 支持 %w
+func NewError(code, format string, args ...interface{}) error {
+	return &CodedError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// WithCode 为错误加上错误码，err 为 nil 时返回 nil
+func WithCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// ErrorCode 错误的错误码：无法连接 Docker 时为 DOCKER_UNAVAILABLE，因超时取消时为 TIMEOUT，
+// 否则取错误链上最外层的错误码（实现了 ErrorCode() string 的错误），都没有时为 OPERATION_FAILED
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	if client.IsErrConnectionFailed(err) {
+		return CodeDockerUnavailable
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CodeTimeout
+	}
+	var coded interface{ ErrorCode() string }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return CodeOperationFailed
+}
+
+// RfailErr 按错误返回失败，带上错误码；请求已超时的失败一律为 TIMEOUT（Docker 返回的错误不一定保留超时原因）
+func RfailErr(c *gin.Context, err error) {
+	code := ErrorCode(err)
+	if c.Request != nil && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		code = CodeTimeout
+	}
+	RfailCode(c, code, err.Error(), nil)
+}
+
+// RfailCode 返回指定错误码的失败
+func RfailCode(c *gin.Context, code, msg string, data interface{}) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":       1,
+		"error_code": code,
+		"msg":        msg,
+		"data":       data,
+	})
+}
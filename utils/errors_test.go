@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestErrorCode 测试按错误链取错误码
+func TestErrorCode(t *testing.T) {
+	notFound := NewError(CodeServiceNotFound, "service %s not found", "web")
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errors.New("boom"), CodeOperationFailed},
+		{notFound, CodeServiceNotFound},
+		{fmt.Errorf("scale failed: %w", notFound), CodeServiceNotFound},
+		{WithCode(CodePortInUse, notFound), CodePortInUse},
+		{NewError(CodeImagePullFailed, "failed to pull image: %w", context.DeadlineExceeded), CodeTimeout},
+	}
+	for _, tt := range tests {
+		if got := ErrorCode(tt.err); got != tt.want {
+			t.Errorf("ErrorCode(%v) = %s, 期望 %s", tt.err, got, tt.want)
+		}
+	}
+	if WithCode(CodeConflict, nil) != nil {
+		t.Error("WithCode(nil) 应返回 nil")
+	}
+	if notFound.Error() != "service web not found" {
+		t.Errorf("Error() = %q", notFound.Error())
+	}
+}
+
+// TestRfailErr 测试失败响应带上错误码
+func TestRfailErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	RfailErr(c, NewError(CodeQuotaExceeded, "quota exceeded"))
+
+	var body struct {
+		Code      int    `json:"code"`
+		ErrorCode string `json:"error_code"`
+		Msg       string `json:"msg"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if body.Code != 1 || body.ErrorCode != CodeQuotaExceeded || body.Msg != "quota exceeded" {
+		t.Errorf("响应 = %+v", body)
+	}
+}
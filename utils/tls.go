@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ServerTLSConfig 按 local.tls 配置生成 API 服务的 TLS 配置，未配置证书时返回 nil（使用 HTTP）
+func ServerTLSConfig() (*tls.Config, error) {
+	certFile := ConfGetString("local.tls.cert_file")
+	keyFile := ConfGetString("local.tls.key_file")
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	return BuildTLSConfig(certFile, keyFile, ConfGetString("local.tls.client_ca_file"),
+		ConfGetString("local.tls.client_auth"), ConfGetString("local.tls.min_version"))
+}
+
+// BuildTLSConfig 生成服务端 TLS 配置
+// clientCAFile 不为空时校验客户端证书（mTLS）：clientAuth 为 require（默认）时必须提供，为 optional 时提供了才校验
+// 证书文件更新后（如自动续期）新的连接使用新证书，无需重启
+func BuildTLSConfig(certFile, keyFile, clientCAFile, clientAuth, minVersion string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both cert_file and key_file are required for tls")
+	}
+	certs := &certReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := certs.certificate(); err != nil {
+		return nil, err
+	}
+	config := &tls.Config{GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return certs.certificate()
+	}}
+
+	switch minVersion {
+	case "", "1.2":
+		config.MinVersion = tls.VersionTLS12
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("invalid tls min_version %q: must be 1.2 or 1.3", minVersion)
+	}
+
+	if clientCAFile == "" {
+		if clientAuth != "" {
+			return nil, fmt.Errorf("client_auth requires client_ca_file")
+		}
+		return config, nil
+	}
+	pem, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client ca file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("client ca file %s contains no certificates", clientCAFile)
+	}
+	config.ClientCAs = pool
+	switch clientAuth {
+	case "", "require":
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	case "optional":
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		return nil, fmt.Errorf("invalid tls client_auth %q: must be require or optional", clientAuth)
+	}
+	return config, nil
+}
+
+// certReloader 证书文件修改后重新加载
+type certReloader struct {
+	mutex    sync.Mutex
+	certFile string
+	keyFile  string
+	cert     *tls.Certificate
+	modTime  time.Time
+}
+
+// certificate 返回当前证书，证书或私钥文件修改时间变化时重新加载，加载失败时继续使用旧证书
+func (r *certReloader) certificate() (*tls.Certificate, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+	if err != nil && r.cert == nil {
+		return nil, fmt.Errorf("failed to load tls certificate: %w", err)
+	}
+	if r.cert != nil && (err != nil || !modTime.After(r.modTime)) {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, fmt.Errorf("failed to load tls certificate: %w", err)
+	}
+	r.cert, r.modTime = &cert, modTime
+	return r.cert, nil
+}
+
+// latestModTime 多个文件中最近的修改时间
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
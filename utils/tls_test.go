@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert 生成自签名证书并写入 dir，返回证书和私钥文件路径
+func writeCert(t *testing.T, dir, name string) (string, string, *x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("生成证书失败: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile, cert, key
+}
+
+// TestBuildTLSConfig 测试 TLS 配置校验与客户端证书校验
+func TestBuildTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, serverCert, _ := writeCert(t, dir, "server")
+	caFile, caKeyFile, _, _ := writeCert(t, dir, "client")
+
+	if _, err := BuildTLSConfig(certFile, "", "", "", ""); err == nil {
+		t.Error("缺少私钥时应返回错误")
+	}
+	if _, err := BuildTLSConfig(certFile, keyFile, "", "", "1.0"); err == nil {
+		t.Error("不支持的 min_version 应返回错误")
+	}
+	if _, err := BuildTLSConfig(certFile, keyFile, "", "require", ""); err == nil {
+		t.Error("未配置 client_ca_file 时 client_auth 应返回错误")
+	}
+	if _, err := BuildTLSConfig(certFile, keyFile, caFile, "always", ""); err == nil {
+		t.Error("无效的 client_auth 应返回错误")
+	}
+
+	config, err := BuildTLSConfig(certFile, keyFile, caFile, "", "")
+	if err != nil {
+		t.Fatalf("BuildTLSConfig() = %v", err)
+	}
+	if config.ClientAuth != tls.RequireAndVerifyClientCert || config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("ClientAuth = %v, MinVersion = %x", config.ClientAuth, config.MinVersion)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = config
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCert)
+	clientCert, err := tls.LoadX509KeyPair(caFile, caKeyFile)
+	if err != nil {
+		t.Fatalf("加载客户端证书失败: %v", err)
+	}
+	get := func(certs []tls.Certificate) error {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs, ServerName: "localhost"}}}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	if err := get(nil); err == nil {
+		t.Error("未提供客户端证书时应拒绝连接")
+	}
+	if err := get([]tls.Certificate{clientCert}); err != nil {
+		t.Errorf("提供客户端证书时应连接成功: %v", err)
+	}
+}
+
+// TestCertReloader 测试证书文件更新后重新加载
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, first, _ := writeCert(t, dir, "server")
+	reloader := &certReloader{certFile: certFile, keyFile: keyFile}
+	cert, err := reloader.certificate()
+	if err != nil {
+		t.Fatalf("certificate() = %v", err)
+	}
+	if cert.Leaf != nil && !cert.Leaf.Equal(first) {
+		t.Error("应加载初始证书")
+	}
+
+	// 用新证书覆盖，并把修改时间调到之后
+	newCert, newKey, second, _ := writeCert(t, t.TempDir(), "server")
+	for src, dst := range map[string]string{newCert: certFile, newKey: keyFile} {
+		data, _ := os.ReadFile(src)
+		os.WriteFile(dst, data, 0o600)
+		later := time.Now().Add(time.Minute)
+		os.Chtimes(dst, later, later)
+	}
+	cert, err = reloader.certificate()
+	if err != nil {
+		t.Fatalf("certificate() = %v", err)
+	}
+	leaf, _ := x509.ParseCertificate(cert.Certificate[0])
+	if !leaf.Equal(second) {
+		t.Error("证书文件更新后应加载新证书")
+	}
+
+	// 文件损坏时继续使用已加载的证书
+	os.WriteFile(certFile, []byte("broken"), 0o600)
+	evenLater := time.Now().Add(2 * time.Minute)
+	os.Chtimes(certFile, evenLater, evenLater)
+	if cert, err := reloader.certificate(); err != nil || cert == nil {
+		t.Errorf("加载失败时应返回旧证书, 实际 %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"strings"
+)
+
+// SetTOMLValue 修改 TOML 文本中一个配置项的值，保留注释、缩进和其他内容
+// key 为点分隔的配置路径（如 container.cache_ttl），最后一段为键名、前面为表名；literal 为 TOML 字面量（字符串需带引号）
+// 配置项不存在时添加到对应表的表头之后，表不存在时在文件末尾添加
+func SetTOMLValue(content, key, literal string) string {
+	section, name := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		section, name = key[:i], key[i+1:]
+	}
+
+	lines := strings.Split(content, "\n")
+	current := ""
+	header := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			current = tomlTableName(trimmed)
+			if current == section {
+				header = i
+			}
+			continue
+		}
+		if current != section {
+			continue
+		}
+		eq := strings.Index(line, "=")
+		if eq < 0 || strings.HasPrefix(trimmed, "#") || strings.TrimSpace(line[:eq]) != name {
+			continue
+		}
+		value := line[eq+1:]
+		newLine := line[:eq+1] + " " + literal
+		if comment := tomlCommentStart(value); comment >= 0 {
+			// 保留值与注释之间原有的空白
+			spacing := value[:comment]
+			spacing = spacing[len(strings.TrimRight(spacing, " \t")):]
+			if spacing == "" {
+				spacing = " "
+			}
+			newLine += spacing + value[comment:]
+		}
+		lines[i] = newLine
+		return strings.Join(lines, "\n")
+	}
+
+	entry := name + " = " + literal
+	if header >= 0 {
+		lines = append(lines[:header+1], append([]string{entry}, lines[header+1:]...)...)
+		return strings.Join(lines, "\n")
+	}
+	if section == "" {
+		return entry + "\n" + content
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "\n[" + section + "]\n" + entry + "\n"
+}
+
+// tomlTableName 表头行的表名，数组表（[[name]]）返回带括号的名称，不与普通表混淆
+func tomlTableName(line string) string {
+	if strings.HasPrefix(line, "[[") {
+		if end := strings.Index(line, "]]"); end >= 0 {
+			return line[:end+2]
+		}
+		return line
+	}
+	if end := strings.Index(line, "]"); end >= 0 {
+		return strings.TrimSpace(line[1:end])
+	}
+	return line
+}
+
+// tomlCommentStart 值之后注释的起始位置，引号内的 # 不算注释；没有注释时返回 -1
+func tomlCommentStart(value string) int {
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,118 @@
+package utils
+
+import "testing"
+
+// TestSetTOMLValue 测试修改 TOML 配置项时保留注释和其他内容
+func TestSetTOMLValue(t *testing.T) {
+	content := `[local]
+address = ":8801" # host and port
+
+[local.logger]
+level = "INFO"
+
+[container]
+prefix = "onedock"  # 容器名称前缀
+cache_ttl = 300 # 单位秒
+# load_balance_strategy = "weighted"
+load_balance_strategy = "round_robin"
+
+[[image_watch.registries]]
+host = "registry.example.com"
+`
+	tests := []struct {
+		name    string
+		key     string
+		literal string
+		want    string
+	}{
+		{
+			name: "保留行尾注释", key: "container.cache_ttl", literal: "60",
+			want: `[local]
+address = ":8801" # host and port
+
+[local.logger]
+level = "INFO"
+
+[container]
+prefix = "onedock"  # 容器名称前缀
+cache_ttl = 60 # 单位秒
+# load_balance_strategy = "weighted"
+load_balance_strategy = "round_robin"
+
+[[image_watch.registries]]
+host = "registry.example.com"
+`,
+		},
+		{
+			name: "跳过注释掉的配置项", key: "container.load_balance_strategy", literal: `"weighted"`,
+			want: `[local]
+address = ":8801" # host and port
+
+[local.logger]
+level = "INFO"
+
+[container]
+prefix = "onedock"  # 容器名称前缀
+cache_ttl = 300 # 单位秒
+# load_balance_strategy = "weighted"
+load_balance_strategy = "weighted"
+
+[[image_watch.registries]]
+host = "registry.example.com"
+`,
+		},
+		{
+			name: "嵌套表", key: "local.logger.level", literal: `"DEBUG"`,
+			want: `[local]
+address = ":8801" # host and port
+
+[local.logger]
+level = "DEBUG"
+
+[container]
+prefix = "onedock"  # 容器名称前缀
+cache_ttl = 300 # 单位秒
+# load_balance_strategy = "weighted"
+load_balance_strategy = "round_robin"
+
+[[image_watch.registries]]
+host = "registry.example.com"
+`,
+		},
+		{
+			name: "表中没有该配置项", key: "local.shutdown_timeout", literal: "10",
+			want: `[local]
+shutdown_timeout = 10
+address = ":8801" # host and port
+
+[local.logger]
+level = "INFO"
+
+[container]
+prefix = "onedock"  # 容器名称前缀
+cache_ttl = 300 # 单位秒
+# load_balance_strategy = "weighted"
+load_balance_strategy = "round_robin"
+
+[[image_watch.registries]]
+host = "registry.example.com"
+`,
+		},
+		{
+			name: "不修改数组表中的同名键", key: "ports.auto_range", literal: `"20000-20999"`,
+			want: content + `
+[ports]
+auto_range = "20000-20999"
+`,
+		},
+	}
+	for _, tt := range tests {
+		if got := SetTOMLValue(content, tt.key, tt.literal); got != tt.want {
+			t.Errorf("%s: SetTOMLValue(%s) =\n%s\n期望\n%s", tt.name, tt.key, got, tt.want)
+		}
+	}
+
+	if got := SetTOMLValue(`name = "a # b" # 注释`, "name", `"c"`); got != `name = "c" # 注释` {
+		t.Errorf("引号内的 # 被当作注释: %s", got)
+	}
+}
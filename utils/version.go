@@ -0,0 +1,48 @@
+package utils
+
+import "runtime/debug"
+
+// 构建信息，发布时通过 ldflags 注入，例如：
+//
+//	go build -ldflags "-X github.com/aichy126/onedock/utils.Version=v1.2.0 \
+//	  -X github.com/aichy126/onedock/utils.GitCommit=$(git rev-parse HEAD) \
+//	  -X github.com/aichy126/onedock/utils.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev" // 版本号
+	GitCommit = ""    // 构建时的 git 提交
+	BuildDate = ""    // 构建时间（RFC3339）
+)
+
+// BuildCommit 返回构建时的 git 提交，未通过 ldflags 注入时使用 go build 记录的 VCS 信息
+func BuildCommit() string {
+	if GitCommit != "" {
+		return GitCommit
+	}
+	commit := buildSetting("vcs.revision")
+	if commit != "" && buildSetting("vcs.modified") == "true" {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+// BuildTime 返回构建时间，未通过 ldflags 注入时使用 go build 记录的提交时间
+func BuildTime() string {
+	if BuildDate != "" {
+		return BuildDate
+	}
+	return buildSetting("vcs.time")
+}
+
+// buildSetting 读取 go build 记录的构建参数
+func buildSetting(key string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == key {
+			return setting.Value
+		}
+	}
+	return ""
+}